@@ -0,0 +1,172 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/fatih/color"
+	"os"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/service"
+	"time"
+)
+
+// newAccountService wires account lifecycle management for a dashboard
+func newAccountService(client *sql.DB) *service.AccountService {
+	return service.NewAccountService(
+		repository.NewUserRepository(client),
+		repository.NewAccountStatusRepository(client),
+		repository.NewServiceProviderRepository(client),
+		repository.NewServiceRequestRepository(client),
+		repository.NewNotificationRepository(client),
+		newServiceRepository(client),
+		repository.NewAuditRepository(client),
+		repository.NewTransactionManager(client),
+	)
+}
+
+// ensureSession re-checks the account before every dashboard action so that a suspension takes effect
+// straight away. It reports false when the user has to be signed out.
+func ensureSession(ctx context.Context, accountService *service.AccountService, userID string) bool {
+	err := accountService.EnsureActive(ctx, userID)
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, model.ErrAccountInactive) {
+		color.Red("You have been signed out, %v", err)
+	} else {
+		color.Red("Could not check your account: %v", err)
+	}
+	return false
+}
+
+// manageAccounts lets the admin suspend, ban, delete and reactivate accounts of any role, and export or
+// erase their personal data
+func manageAccounts(ctx context.Context, admin *model.Admin, accountService *service.AccountService, privacyService *service.PrivacyService) {
+	for {
+		color.Blue("Manage Accounts")
+		color.Blue("1. View Account Status and History")
+		color.Blue("2. Suspend Account")
+		color.Blue("3. Ban Account")
+		color.Blue("4. Delete Account")
+		color.Blue("5. Reactivate Account")
+		color.Blue("6. Export User Data")
+		color.Blue("7. Erase User Data")
+		color.Blue("8. Find Accounts by Contact")
+		color.Blue("9. Back to Dashboard")
+
+		var choice int
+		fmt.Scanln(&choice)
+
+		switch choice {
+		case 1:
+			viewAccountStatus(ctx, accountService)
+		case 2:
+			changeAccountStatus(ctx, "suspend", true, func(userID, reason string, until *time.Time) error {
+				return accountService.SuspendAccount(ctx, admin.User.ID, userID, reason, until)
+			})
+		case 3:
+			changeAccountStatus(ctx, "ban", true, func(userID, reason string, until *time.Time) error {
+				return accountService.BanAccount(ctx, admin.User.ID, userID, reason, until)
+			})
+		case 4:
+			changeAccountStatus(ctx, "delete", false, func(userID, reason string, _ *time.Time) error {
+				return accountService.DeleteAccount(ctx, admin.User.ID, userID, reason)
+			})
+		case 5:
+			changeAccountStatus(ctx, "reactivate", false, func(userID, reason string, _ *time.Time) error {
+				return accountService.ReactivateAccount(ctx, admin.User.ID, userID, reason)
+			})
+		case 6:
+			exportUserData(ctx, privacyService, admin.User.ID, promptOption("Enter User ID to export: "))
+		case 7:
+			eraseUserData(ctx, privacyService, admin.User.ID)
+		case 8:
+			findAccountsByContact(ctx, accountService)
+		case 9:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
+func findAccountsByContact(ctx context.Context, accountService *service.AccountService) {
+	users, err := accountService.FindAccountsByContact(ctx, promptOption("Enter Contact: "))
+	if err != nil {
+		color.Red("Error searching accounts: %v", err)
+		return
+	}
+	if len(users) == 0 {
+		color.Yellow("No account uses this contact number.")
+		return
+	}
+	for _, user := range users {
+		color.Cyan("ID: %s, Name: %s, Email: %s, Role: %s, Status: %s", user.ID, user.Name, user.Email, user.Role, user.Status)
+	}
+}
+
+func viewAccountStatus(ctx context.Context, accountService *service.AccountService) {
+	var userID string
+	fmt.Print("Enter User ID: ")
+	fmt.Scanln(&userID)
+
+	user, err := accountService.GetAccount(ctx, userID)
+	if err != nil {
+		color.Red("Error loading account: %v", err)
+		return
+	}
+	color.Cyan("User: %s (%s), Role: %s, Status: %s", user.Name, user.Email, user.Role, user.Status)
+	if user.StatusReason != "" {
+		color.Cyan("Reason: %s", user.StatusReason)
+	}
+	if user.StatusExpiresAt != nil {
+		color.Cyan("Until: %s", user.StatusExpiresAt.Format("2006-01-02 15:04"))
+	}
+
+	history, err := accountService.GetStatusHistory(ctx, userID)
+	if err != nil {
+		color.Red("Error loading status history: %v", err)
+		return
+	}
+	if len(history) == 0 {
+		color.Cyan("The status of this account has never been changed.")
+		return
+	}
+	color.Blue("Status history:")
+	for _, change := range history {
+		line := fmt.Sprintf("%s  %s -> %s by %s: %s", change.ChangedAt.Format("2006-01-02 15:04"), change.FromStatus, change.ToStatus, change.ChangedBy, change.Reason)
+		if change.ExpiresAt != nil {
+			line += " (until " + change.ExpiresAt.Format("2006-01-02 15:04") + ")"
+		}
+		color.Cyan("%s", line)
+	}
+}
+
+// changeAccountStatus prompts for the account, a reason and, when withExpiry is set, an optional expiry
+func changeAccountStatus(ctx context.Context, action string, withExpiry bool, apply func(userID, reason string, until *time.Time) error) {
+	var userID string
+	fmt.Printf("Enter User ID to %s: ", action)
+	fmt.Scanln(&userID)
+	reader := bufio.NewReader(os.Stdin)
+	reason := promptLine(reader, "Enter the reason: ")
+
+	var until *time.Time
+	if withExpiry {
+		if date := promptDate("Enter the date it ends (YYYY-MM-DD, leave empty for no end date): "); !date.IsZero() {
+			until = &date
+		}
+	}
+
+	if err := apply(userID, reason, until); err != nil {
+		color.Red("Could not %s account: %v", action, err)
+		return
+	}
+	color.Green("Account %s updated", userID)
+}
@@ -4,6 +4,7 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/fatih/color"
@@ -13,35 +14,71 @@ import (
 )
 
 // AdminDashboard is the main dashboard for admin actions
-func adminDashboard(admin *model.Admin, client *sql.DB) {
-	serviceRepo := repository.NewServiceRepository(client)
+func adminDashboard(ctx context.Context, admin *model.Admin, client *sql.DB) {
+	serviceRepo := newServiceRepository(client)
 	userRepo := repository.NewUserRepository(client)
 	serviceRequestRepo := repository.NewServiceRequestRepository(client)
 	providerRepo := repository.NewServiceProviderRepository(client)
 
-	adminService := service.NewAdminService(serviceRepo, serviceRequestRepo, userRepo, providerRepo)
+	adminService := service.NewAdminService(serviceRepo, serviceRequestRepo, userRepo, providerRepo, repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+	categoryService := newCategoryService(client)
+	customRequestService := service.NewCustomRequestService(repository.NewCustomRequestRepository(client), repository.NewNotificationRepository(client), categoryService, newAccountService(client), repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+	onboardingService := newOnboardingService(client)
+	accountService := newAccountService(client)
+	authService := newAuthService(client)
+	twoFactorService := newTwoFactorService(client)
+	roleService := newRoleService(client)
+	auditService := service.NewAuditService(repository.NewAuditRepository(client))
+	reviewService := newReviewService(client)
 
 	for {
 		color.Blue("Admin Dashboard")
 		color.Blue("1. Manage Services")
 		color.Blue("2. View Reports")
-		color.Blue("3. Deactivate User Account")
+		color.Blue("3. Manage Accounts")
 		//color.Blue("4. Add Service Area")
 		//color.Blue("5. Remove Service Area")
 		//color.Blue("6. Update Service Area")
-		color.Blue("4. Exit")
+		color.Blue("4. Manage Categories")
+		color.Blue("5. Custom Request Queue")
+		color.Blue("6. Provider Verification")
+		color.Blue("7. Login Security")
+		color.Blue("8. Two-Factor Authentication")
+		color.Blue("9. Manage Roles")
+		color.Blue("10. Audit Log")
+		color.Blue("11. Review Moderation")
+		color.Blue("12. Exit")
 
 		var choice int
 		fmt.Scanln(&choice)
+		if !ensureSession(ctx, accountService, admin.User.ID) {
+			return
+		}
 
 		switch choice {
 		case 1:
-			manageServices(adminService)
+			manageServices(ctx, adminService)
 		case 2:
-			viewReports(adminService)
+			viewReports(ctx, adminService)
 		case 3:
-			deactivateUserAccount(adminService)
+			manageAccounts(ctx, admin, accountService, newPrivacyService(client))
 		case 4:
+			manageCategories(ctx, categoryService)
+		case 5:
+			triageCustomRequests(ctx, admin, customRequestService)
+		case 6:
+			manageProviderVerification(ctx, admin, onboardingService)
+		case 7:
+			manageLoginSecurity(ctx, admin, authService)
+		case 8:
+			manageTwoFactor(ctx, admin.User, twoFactorService)
+		case 9:
+			manageRoles(ctx, admin, roleService)
+		case 10:
+			manageAuditLog(ctx, auditService)
+		case 11:
+			moderateReviews(ctx, admin, reviewService)
+		case 12:
 			return
 
 		default:
@@ -50,8 +87,46 @@ func adminDashboard(admin *model.Admin, client *sql.DB) {
 	}
 }
 
+// supportDashboard is the dashboard of support staff, who can look into reports and help users locked out
+// of their accounts but cannot change the catalogue, accounts or roles
+func supportDashboard(ctx context.Context, support *model.Admin, client *sql.DB) {
+	adminService := service.NewAdminService(newServiceRepository(client), repository.NewServiceRequestRepository(client),
+		repository.NewUserRepository(client), repository.NewServiceProviderRepository(client), repository.NewAuditRepository(client),
+		repository.NewTransactionManager(client))
+	accountService := newAccountService(client)
+	authService := newAuthService(client)
+	twoFactorService := newTwoFactorService(client)
+
+	for {
+		color.Blue("Support Dashboard")
+		color.Blue("1. View Reports")
+		color.Blue("2. Login Security")
+		color.Blue("3. Two-Factor Authentication")
+		color.Blue("4. Exit")
+
+		var choice int
+		fmt.Scanln(&choice)
+		if !ensureSession(ctx, accountService, support.User.ID) {
+			return
+		}
+
+		switch choice {
+		case 1:
+			viewReports(ctx, adminService)
+		case 2:
+			manageLoginSecurity(ctx, support, authService)
+		case 3:
+			manageTwoFactor(ctx, support.User, twoFactorService)
+		case 4:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
 // ManageServices handles the services management functionality
-func manageServices(adminService *service.AdminService) {
+func manageServices(ctx context.Context, adminService *service.AdminService) {
 	for {
 		color.Blue("Manage Services")
 		color.Blue("1. View All Services")
@@ -64,9 +139,9 @@ func manageServices(adminService *service.AdminService) {
 
 		switch choice {
 		case 1:
-			viewAllServices(adminService)
+			viewAllServices(ctx, adminService)
 		case 2:
-			deleteService(adminService)
+			deleteService(ctx, adminService)
 		case 4:
 			return
 		default:
@@ -76,27 +151,38 @@ func manageServices(adminService *service.AdminService) {
 
 }
 
-// ViewAllServices displays all the services available
-func viewAllServices(adminService *service.AdminService) {
-	services, err := adminService.GetAllService()
-	if err != nil {
-		color.Red("Error retrieving services: %v", err)
-		return
-	}
+// ViewAllServices displays the services available, one page at a time
+func viewAllServices(ctx context.Context, adminService *service.AdminService) {
+	opts := promptQueryOptions([]string{"name", "price", "category"}, false)
+	for {
+		services, err := adminService.GetAllService(ctx, opts)
+		if err != nil {
+			color.Red("Error retrieving services: %v", err)
+			return
+		}
+		if len(services) == 0 && opts.Offset == 0 {
+			color.Yellow("No services found.")
+			return
+		}
 
-	for _, svc := range services {
-		color.Cyan("Service ID: %s, Name: %s, Description: %s, Price: %.2f", svc.ID, svc.Name, svc.Description, svc.Price)
-		fmt.Println()
+		for _, svc := range services {
+			color.Cyan("Service ID: %s, Name: %s, Description: %s, Price: %.2f", svc.ID, svc.Name, svc.Description, svc.Price)
+			fmt.Println()
+		}
+		if !wantNextPage(len(services), opts) {
+			return
+		}
+		opts = opts.NextPage()
 	}
 }
 
 // DeleteService allows the admin to delete a service_test
-func deleteService(adminService *service.AdminService) {
+func deleteService(ctx context.Context, adminService *service.AdminService) {
 	var serviceID string
 	fmt.Print("Enter Service ID to delete: ")
 	fmt.Scanln(&serviceID)
 
-	err := adminService.DeleteService(serviceID)
+	err := adminService.DeleteService(ctx, serviceID)
 	if err != nil {
 		color.Red("Error deleting service_test: %v", err)
 	} else {
@@ -105,36 +191,33 @@ func deleteService(adminService *service.AdminService) {
 }
 
 // ViewReports allows the admin to view various reports
-func viewReports(adminService *service.AdminService) {
+func viewReports(ctx context.Context, adminService *service.AdminService) {
 	color.Blue("View Reports")
-	reports, err := adminService.ViewReports()
-	if err != nil {
-		color.Red("Error generating reports: %v", err)
-		return
-	}
-
-	for _, report := range reports {
-		color.Cyan("Report ID: %v, Details: Service ID- %v ", report.ID, report.ServiceID)
-		color.Cyan("Service Providers :")
-		for _, provider := range report.ProviderDetails {
-			color.Cyan("ProviderID: %v", provider.ServiceProviderID)
-			color.Cyan("Name: %v Contact: %v Address: %v Rating: %v ", provider.Name, provider.Contact, provider.Address, provider.Rating)
-			color.Cyan("-----------------")
+	opts := promptQueryOptions([]string{"requested_time", "scheduled_time", "status"}, true)
+	for {
+		reports, err := adminService.ViewReports(ctx, opts)
+		if err != nil {
+			color.Red("Error generating reports: %v", err)
+			return
+		}
+		if len(reports) == 0 && opts.Offset == 0 {
+			color.Yellow("No service requests match the filters.")
+			return
 		}
-		fmt.Println()
-	}
-}
-
-// DeactivateUserAccount allows the admin to deactivate a user account
-func deactivateUserAccount(adminService *service.AdminService) {
-	var userID string
-	fmt.Print("Enter ServiceProvider ID to deactivate: ")
-	fmt.Scanln(&userID)
 
-	err := adminService.DeactivateAccount(userID)
-	if err != nil {
-		color.Red("Error deactivating account: %v", err)
-	} else {
-		color.Green("Account deactivated successfully")
+		for _, report := range reports {
+			color.Cyan("Report ID: %v, Details: Service ID- %v Status- %v", report.ID, report.ServiceID, report.Status)
+			color.Cyan("Service Providers :")
+			for _, provider := range report.ProviderDetails {
+				color.Cyan("ProviderID: %v", provider.ServiceProviderID)
+				color.Cyan("Name: %v Contact: %v Address: %v Rating: %v ", provider.Name, provider.Contact, provider.Address, provider.Rating)
+				color.Cyan("-----------------")
+			}
+			fmt.Println()
+		}
+		if !wantNextPage(len(reports), opts) {
+			return
+		}
+		opts = opts.NextPage()
 	}
 }
@@ -0,0 +1,101 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/fatih/color"
+	"serviceNest/model"
+	"serviceNest/service"
+	"strconv"
+	"strings"
+)
+
+// auditPageSize is how many audit entries are shown at a time
+const auditPageSize = 20
+
+// manageAuditLog lets an admin search the audit log and check its hash chain
+func manageAuditLog(ctx context.Context, auditService *service.AuditService) {
+	for {
+		color.Blue("Audit Log")
+		color.Blue("1. Search Audit Log")
+		color.Blue("2. Verify Integrity")
+		color.Blue("3. Back to Dashboard")
+
+		var choice int
+		fmt.Scanln(&choice)
+
+		switch choice {
+		case 1:
+			searchAuditLog(ctx, auditService)
+		case 2:
+			result, err := auditService.VerifyChain(ctx)
+			if err != nil {
+				color.Red("Error verifying the audit log: %v", err)
+				continue
+			}
+			if !result.Intact() {
+				color.Red("The audit log has been tampered with at entry %d: %s (%d entries checked)", result.BrokenAt, result.Problem, result.Checked)
+				continue
+			}
+			color.Green("All %d entries are intact", result.Checked)
+			color.Cyan("Newest entry %d has hash %s, note it down to detect a rewrite of the whole log later", result.HeadSeq, result.HeadHash)
+		case 3:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
+// searchAuditLog shows the entries matching the admin's filters, one page at a time
+func searchAuditLog(ctx context.Context, auditService *service.AuditService) {
+	filter := auditFilterFromPrompts()
+	for {
+		entries, err := auditService.GetEntries(ctx, filter)
+		if err != nil {
+			color.Red("Error loading the audit log: %v", err)
+			return
+		}
+		if len(entries) == 0 && filter.Offset == 0 {
+			color.Yellow("No audit entries found.")
+			return
+		}
+		for _, entry := range entries {
+			color.Cyan("#%d %s  %s %s %s %s", entry.Seq, entry.CreatedAt.Format("2006-01-02 15:04:05"), entry.ActorID, entry.Action,
+				entry.EntityType, entry.EntityID)
+			if entry.Before != "" {
+				fmt.Println("    before:", entry.Before)
+			}
+			if entry.After != "" {
+				fmt.Println("    after: ", entry.After)
+			}
+		}
+		if len(entries) < filter.Limit || !strings.EqualFold(promptOption("Show next page? (yes/no): "), "yes") {
+			return
+		}
+		filter = filter.NextPage()
+	}
+}
+
+// auditFilterFromPrompts asks for the filters of an audit log search, empty answers match everything
+func auditFilterFromPrompts() model.AuditFilter {
+	filter := model.AuditFilter{
+		ActorID:    promptOption("Filter by actor ID (or system): "),
+		EntityType: promptOption("Filter by entity type (User/Service/ServiceRequest/CustomRequest/Category/ServiceProvider/Review): "),
+		EntityID:   promptOption("Filter by entity ID: "),
+		From:       promptDate("On or after (YYYY-MM-DD): "),
+		To:         promptDate("Before (YYYY-MM-DD): "),
+		Limit:      auditPageSize,
+	}
+	if size := promptOption("Page size (empty for " + strconv.Itoa(auditPageSize) + "): "); size != "" {
+		if limit, err := strconv.Atoi(size); err == nil && limit > 0 {
+			filter.Limit = limit
+		} else {
+			color.Yellow("Invalid page size, showing %d entries per page", auditPageSize)
+		}
+	}
+	return filter
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/fatih/color"
 	"golang.org/x/crypto/bcrypt"
@@ -10,6 +12,7 @@ import (
 	"os"
 	"serviceNest/interfaces"
 	"serviceNest/model"
+	"serviceNest/service"
 	"serviceNest/util"
 	"strings"
 )
@@ -21,7 +24,7 @@ var inputReader *bufio.Reader = bufio.NewReader(os.Stdin)
 func SetInputReader(r io.Reader) {
 	inputReader = bufio.NewReader(r)
 }
-func SignUp(userRepo interfaces.UserRepository) (*model.User, error) {
+func SignUp(ctx context.Context, userRepo interfaces.UserRepository) (*model.User, error) {
 	_ = bufio.NewReader(os.Stdin)
 
 	name, err := getInput("Enter Name: ")
@@ -29,7 +32,7 @@ func SignUp(userRepo interfaces.UserRepository) (*model.User, error) {
 		return nil, err
 	}
 
-	email, err := getValidEmail(userRepo)
+	email, err := getValidEmail(ctx, userRepo)
 	if err != nil {
 		return nil, err
 	}
@@ -48,9 +51,9 @@ Press 2-ServiceProvider
 		fmt.Scanln(&choice)
 		switch choice {
 		case "1":
-			role = "Householder"
+			role = model.RoleHouseholder
 		case "2":
-			role = "ServiceProvider"
+			role = model.RoleServiceProvider
 		default:
 			color.Red("Invalid choice")
 			continue
@@ -84,7 +87,7 @@ Press 2-ServiceProvider
 		Contact:  contact,
 	}
 
-	if err := userRepo.SaveUser(&user); err != nil {
+	if err := userRepo.SaveUser(ctx, &user); err != nil {
 		return nil, err
 	}
 
@@ -101,7 +104,7 @@ func getInput(prompt string) (string, error) {
 	return strings.TrimSpace(input), nil
 }
 
-func getValidEmail(userRepo interfaces.UserRepository) (string, error) {
+func getValidEmail(ctx context.Context, userRepo interfaces.UserRepository) (string, error) {
 	for {
 		email, err := getInput("Enter Email: ")
 		if err != nil {
@@ -113,7 +116,7 @@ func getValidEmail(userRepo interfaces.UserRepository) (string, error) {
 			continue
 		}
 
-		existingUser, _ := userRepo.GetUserByEmail(email)
+		existingUser, _ := userRepo.GetUserByEmail(ctx, email)
 		if existingUser != nil {
 			return "", fmt.Errorf("email already registered. Please use a different email address.")
 		}
@@ -150,9 +153,9 @@ Press 2-ServiceProvider
 		}
 		switch choice {
 		case 1:
-			return "Householder", nil
+			return model.RoleHouseholder, nil
 		case 2:
-			return "ServiceProvider", nil
+			return model.RoleServiceProvider, nil
 		default:
 			color.Red("Invalid choice")
 			continue
@@ -175,7 +178,21 @@ func getValidContact() (string, error) {
 		return contact, nil
 	}
 }
-func Login(userRepo interfaces.UserRepository) (*model.User, error) {
+
+// loginSource identifies where a login attempt comes from: the client address of an SSH session, or
+// this machine for a local terminal, which is audited but not throttled
+func loginSource() string {
+	if client := strings.Fields(os.Getenv("SSH_CLIENT")); len(client) > 0 {
+		return client[0]
+	}
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	return model.LocalSource(host)
+}
+
+func Login(ctx context.Context, authService *service.AuthService, twoFactorService *service.TwoFactorService) (*model.User, error) {
 	email, err := getInput("Enter Email: ")
 	if err != nil {
 		return nil, err
@@ -186,16 +203,80 @@ func Login(userRepo interfaces.UserRepository) (*model.User, error) {
 		return nil, err
 	}
 
-	user, err := userRepo.GetUserByEmail(email)
+	source := loginSource()
+	user, err := authService.Login(ctx, email, password, source)
+	var secondFactor *model.SecondFactorRequiredError
+	if errors.As(err, &secondFactor) {
+		if secondFactor.EnrolmentRequired {
+			user, err = enrolDuringLogin(ctx, authService, twoFactorService, secondFactor, source)
+		} else {
+			user, err = promptSecondFactor(ctx, authService, secondFactor.Challenge, source)
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	fmt.Println("Login successful!")
+	return user, nil
+}
+
+// secondFactorAttempts is how often the code can be re-entered before the login starts over
+const secondFactorAttempts = 3
+
+// promptSecondFactor asks for the authenticator or recovery code that completes a login
+func promptSecondFactor(ctx context.Context, authService *service.AuthService, challenge, source string) (*model.User, error) {
+	for attempt := 1; ; attempt++ {
+		code, err := getInput("Enter the code from your authenticator app (or a recovery code): ")
+		if err != nil {
+			return nil, err
+		}
+		user, err := authService.CompleteLogin(ctx, challenge, code, source)
+		if errors.Is(err, model.ErrInvalidSecondFactor) && attempt < secondFactorAttempts {
+			color.Red("%s", err)
+			continue
+		}
+		return user, err
+	}
+}
+
+// enrolDuringLogin walks an admin without two-factor authentication through setting it up
+func enrolDuringLogin(ctx context.Context, authService *service.AuthService, twoFactorService *service.TwoFactorService, secondFactor *model.SecondFactorRequiredError, source string) (*model.User, error) {
+	color.Yellow("Admins have to use two-factor authentication. Set it up now to continue.")
+	enrolment, err := twoFactorService.StartEnrolment(ctx, secondFactor.UserID)
 	if err != nil {
-		return nil, fmt.Errorf("invalid credentials")
+		return nil, err
 	}
+	showEnrolment(enrolment)
 
-	fmt.Println("Login successful!")
-	return user, nil
+	for attempt := 1; ; attempt++ {
+		code, err := getInput("Enter the code shown by your authenticator app: ")
+		if err != nil {
+			return nil, err
+		}
+		user, recoveryCodes, err := authService.CompleteEnrolment(ctx, secondFactor.Challenge, code, source)
+		if errors.Is(err, model.ErrInvalidSecondFactor) && attempt < secondFactorAttempts {
+			color.Red("%s", err)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		showRecoveryCodes(recoveryCodes)
+		return user, nil
+	}
+}
+
+func showEnrolment(enrolment *model.TOTPEnrolment) {
+	fmt.Println("Add this account to your authenticator app with the link below, or type in the secret key.")
+	color.Cyan("Link:   %s", enrolment.URI)
+	color.Cyan("Secret: %s", enrolment.Secret)
+}
+
+func showRecoveryCodes(codes []string) {
+	color.Green("Two-factor authentication is on. Keep these recovery codes somewhere safe, each works once")
+	color.Green("when you cannot use your authenticator app. They will not be shown again.")
+	for _, code := range codes {
+		color.Cyan("  %s", code)
+	}
 }
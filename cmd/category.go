@@ -0,0 +1,158 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/fatih/color"
+	"os"
+	"serviceNest/config"
+	"serviceNest/repository"
+	"serviceNest/service"
+	"serviceNest/util"
+)
+
+const categoriesUsage = "usage: serviceNest categories import [file]"
+
+// newCategoryService wires the category catalogue for a dashboard
+func newCategoryService(client *sql.DB) *service.CategoryService {
+	return service.NewCategoryService(repository.NewCategoryRepository(client), newServiceRepository(client), repository.NewRatingRepository(client),
+		repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+}
+
+// runCategories handles the `categories import [file]` subcommand, a one-time import of the legacy
+// category file into the database. The file defaults to the configured category_file.
+func runCategories(ctx context.Context, client *sql.DB, args []string) error {
+	if len(args) < 1 || len(args) > 2 || args[0] != "import" {
+		return errors.New(categoriesUsage)
+	}
+	path := config.Current().CategoryFile
+	if len(args) == 2 {
+		path = args[1]
+	}
+
+	data, err := util.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	imported, err := newCategoryService(client).ImportCategories(ctx, data)
+	if err != nil {
+		return err
+	}
+	color.Green("Imported %d categories from %s", imported, path)
+	return nil
+}
+
+// displayCategories prints the category catalogue
+func displayCategories(ctx context.Context, categoryService *service.CategoryService) {
+	categories, err := categoryService.GetAllCategories(ctx)
+	if err != nil {
+		color.Red("Error loading categories: %v", err)
+		return
+	}
+	if len(categories) == 0 {
+		color.Yellow("No categories have been set up yet.")
+		return
+	}
+	util.DisplayCategory(categories)
+}
+
+// manageCategories lets the admin maintain the category catalogue
+func manageCategories(ctx context.Context, categoryService *service.CategoryService) {
+	for {
+		color.Blue("Manage Categories")
+		color.Blue("1. View Categories")
+		color.Blue("2. Add Category")
+		color.Blue("3. Update Category")
+		color.Blue("4. Delete Category")
+		color.Blue("5. Back to Dashboard")
+
+		var choice int
+		fmt.Scanln(&choice)
+
+		switch choice {
+		case 1:
+			viewCategories(ctx, categoryService)
+		case 2:
+			addCategory(ctx, categoryService)
+		case 3:
+			updateCategory(ctx, categoryService)
+		case 4:
+			deleteCategory(ctx, categoryService)
+		case 5:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
+// viewCategories lists every category with its ID so that it can be edited
+func viewCategories(ctx context.Context, categoryService *service.CategoryService) {
+	categories, err := categoryService.GetAllCategories(ctx)
+	if err != nil {
+		color.Red("Error loading categories: %v", err)
+		return
+	}
+	if len(categories) == 0 {
+		color.Yellow("No categories found.")
+		return
+	}
+
+	names := make(map[string]string, len(categories))
+	for _, category := range categories {
+		names[category.ID] = category.Name
+	}
+	for _, category := range categories {
+		if category.ParentID == "" {
+			color.Cyan("Category ID: %s, Name: %s, Description: %s", category.ID, category.Name, category.Description)
+		} else {
+			color.Cyan("Category ID: %s, Name: %s, Parent: %s, Description: %s", category.ID, category.Name, names[category.ParentID], category.Description)
+		}
+	}
+}
+
+func addCategory(ctx context.Context, categoryService *service.CategoryService) {
+	reader := bufio.NewReader(os.Stdin)
+	name := promptLine(reader, "Enter category name: ")
+	description := promptLine(reader, "Enter description: ")
+	parent := promptLine(reader, "Enter parent category (leave empty for a top-level category): ")
+
+	category, err := categoryService.AddCategory(ctx, name, description, parent)
+	if err != nil {
+		color.Red("Error adding category: %v", err)
+		return
+	}
+	color.Green("Category %s added with ID %s", category.Name, category.ID)
+}
+
+func updateCategory(ctx context.Context, categoryService *service.CategoryService) {
+	reader := bufio.NewReader(os.Stdin)
+	categoryID := promptLine(reader, "Enter Category ID to update: ")
+	name := promptLine(reader, "Enter new name (leave empty to keep): ")
+	description := promptLine(reader, "Enter new description (leave empty to keep): ")
+	parent := promptLine(reader, "Enter new parent category (leave empty to keep, - for top-level): ")
+
+	if err := categoryService.UpdateCategory(ctx, categoryID, name, description, parent); err != nil {
+		color.Red("Error updating category: %v", err)
+		return
+	}
+	color.Green("Category updated successfully")
+}
+
+func deleteCategory(ctx context.Context, categoryService *service.CategoryService) {
+	var categoryID string
+	fmt.Print("Enter Category ID to delete: ")
+	fmt.Scanln(&categoryID)
+
+	if err := categoryService.RemoveCategory(ctx, categoryID); err != nil {
+		color.Red("Error deleting category: %v", err)
+		return
+	}
+	color.Green("Category deleted successfully")
+}
@@ -0,0 +1,101 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/fatih/color"
+	"os"
+	"serviceNest/model"
+	"serviceNest/service"
+)
+
+// triageCustomRequests lets the admin work through requests for services that are not offered yet
+func triageCustomRequests(ctx context.Context, admin *model.Admin, customRequestService *service.CustomRequestService) {
+	for {
+		color.Blue("Custom Request Queue")
+		color.Blue("1. View Open Requests")
+		color.Blue("2. Map Request to Existing Category")
+		color.Blue("3. Map Request to New Category")
+		color.Blue("4. Reject Request")
+		color.Blue("5. Back to Dashboard")
+
+		var choice int
+		fmt.Scanln(&choice)
+
+		switch choice {
+		case 1:
+			viewOpenCustomRequests(ctx, customRequestService)
+		case 2:
+			mapCustomRequest(ctx, admin, customRequestService)
+		case 3:
+			mapCustomRequestToNewCategory(ctx, admin, customRequestService)
+		case 4:
+			rejectCustomRequest(ctx, admin, customRequestService)
+		case 5:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
+func viewOpenCustomRequests(ctx context.Context, customRequestService *service.CustomRequestService) {
+	requests, err := customRequestService.GetOpenCustomRequests(ctx)
+	if err != nil {
+		color.Red("Error loading custom requests: %v", err)
+		return
+	}
+	if len(requests) == 0 {
+		color.Yellow("The queue is empty.")
+		return
+	}
+
+	for _, request := range requests {
+		color.Cyan("Request ID: %s, Service: %q, Householder: %s (%s)", request.ID, request.ServiceName, request.HouseholderName, request.HouseholderID)
+		color.Cyan("Requested: %s, Scheduled: %s", request.RequestedTime.Format("2006-01-02 15:04"), request.ScheduledTime.Format("2006-01-02 15:04"))
+		fmt.Println()
+	}
+}
+
+func mapCustomRequest(ctx context.Context, admin *model.Admin, customRequestService *service.CustomRequestService) {
+	reader := bufio.NewReader(os.Stdin)
+	requestID := promptLine(reader, "Enter Request ID: ")
+	category := promptLine(reader, "Enter category: ")
+
+	if err := customRequestService.MapToCategory(ctx, admin.User.ID, requestID, category); err != nil {
+		color.Red("Error mapping request: %v", err)
+		return
+	}
+	color.Green("Request mapped, the householder has been notified")
+}
+
+func mapCustomRequestToNewCategory(ctx context.Context, admin *model.Admin, customRequestService *service.CustomRequestService) {
+	reader := bufio.NewReader(os.Stdin)
+	requestID := promptLine(reader, "Enter Request ID: ")
+	name := promptLine(reader, "Enter new category name: ")
+	description := promptLine(reader, "Enter description: ")
+	parent := promptLine(reader, "Enter parent category (leave empty for a top-level category): ")
+
+	category, err := customRequestService.MapToNewCategory(ctx, admin.User.ID, requestID, name, description, parent)
+	if err != nil {
+		color.Red("Error mapping request: %v", err)
+		return
+	}
+	color.Green("Category %s created and request mapped, the householder has been notified", category.Name)
+}
+
+func rejectCustomRequest(ctx context.Context, admin *model.Admin, customRequestService *service.CustomRequestService) {
+	reader := bufio.NewReader(os.Stdin)
+	requestID := promptLine(reader, "Enter Request ID: ")
+	reason := promptLine(reader, "Enter the reason: ")
+
+	if err := customRequestService.RejectCustomRequest(ctx, admin.User.ID, requestID, reason); err != nil {
+		color.Red("Error rejecting request: %v", err)
+		return
+	}
+	color.Green("Request rejected, the householder has been notified")
+}
@@ -0,0 +1,105 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"github.com/fatih/color"
+	"os"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/service"
+	"time"
+)
+
+// newFavouriteService wires favourite providers and direct re-booking
+func newFavouriteService(client *sql.DB) *service.FavouriteService {
+	return service.NewFavouriteService(repository.NewFavouriteRepository(client), repository.NewServiceProviderRepository(client),
+		newServiceRepository(client), repository.NewServiceRequestRepository(client), repository.NewNotificationRepository(client),
+		newAccountService(client), repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+}
+
+// manageFavourites lists the householder's favourite providers and lets them book one again directly
+func manageFavourites(ctx context.Context, favouriteService *service.FavouriteService, profileService *service.ProviderProfileService, reviewService *service.ReviewService, householder *model.Householder) {
+	for {
+		favourites, err := favouriteService.GetFavourites(ctx, householder.User.ID)
+		if err != nil {
+			color.Red("Error loading your favourites: %v", err)
+			return
+		}
+		if len(favourites) == 0 {
+			color.Cyan("You have no favourite providers yet.")
+		}
+		for _, favourite := range favourites {
+			availability := "taking new jobs"
+			if !favourite.Available {
+				availability = "not taking new jobs"
+			}
+			color.Cyan("%s (ID %s), %s", favourite.ProviderName, favourite.ProviderID, availability)
+		}
+
+		color.Blue("1. Add a Favourite")
+		color.Blue("2. Remove a Favourite")
+		color.Blue("3. Book Again")
+		color.Blue("4. View a Provider's Profile")
+		color.Blue("5. Back")
+		reader := bufio.NewReader(os.Stdin)
+		switch promptOption("") {
+		case "1":
+			if err := favouriteService.AddFavourite(ctx, householder.User.ID, promptLine(reader, "Enter Service Provider ID: ")); err != nil {
+				color.Red("Error adding favourite: %v", err)
+			} else {
+				color.Green("Provider added to your favourites")
+			}
+		case "2":
+			if err := favouriteService.RemoveFavourite(ctx, householder.User.ID, promptLine(reader, "Enter Service Provider ID: ")); err != nil {
+				color.Red("Error removing favourite: %v", err)
+			} else {
+				color.Green("Provider removed from your favourites")
+			}
+		case "3":
+			bookAgain(ctx, favouriteService, profileService, householder)
+		case "4":
+			viewProviderProfile(ctx, profileService, reviewService, promptLine(reader, "Enter Service Provider ID: "))
+		case "5":
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
+// bookAgain sends a request straight to one provider. If they decline, it is opened to every provider.
+func bookAgain(ctx context.Context, favouriteService *service.FavouriteService, profileService *service.ProviderProfileService, householder *model.Householder) {
+	reader := bufio.NewReader(os.Stdin)
+	providerID := promptLine(reader, "Enter Service Provider ID: ")
+	profile, err := profileService.GetProviderProfile(ctx, providerID)
+	if err != nil {
+		color.Red("Error loading the provider: %v", err)
+		return
+	}
+	if len(profile.Services) == 0 {
+		color.Yellow("%s does not offer any services at the moment.", profile.Name)
+		return
+	}
+	for _, offered := range profile.Services {
+		color.Cyan("Service: %s (%s), Price: %.2f, ID %s", offered.Name, offered.Category, offered.Price, offered.ID)
+	}
+
+	serviceID := promptLine(reader, "Enter Service ID: ")
+	scheduledTime, err := time.Parse("2006-01-02 15:04", promptLine(reader, "Enter the scheduled time (YYYY-MM-DD HH:MM): "))
+	if err != nil {
+		color.Red("Error parsing time: %v", err)
+		return
+	}
+
+	requestID, err := favouriteService.BookAgain(ctx, householder, providerID, serviceID, scheduledTime)
+	if err != nil {
+		color.Red("Error booking %s: %v", profile.Name, err)
+		return
+	}
+	color.Green("Request %s sent to %s. If they decline, it is opened to all providers.", requestID, profile.Name)
+}
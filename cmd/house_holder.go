@@ -5,22 +5,23 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/fatih/color"
 	"os"
 	"serviceNest/model"
 	"serviceNest/repository"
 	"serviceNest/service"
-	"serviceNest/util"
+	"strconv"
 	"strings"
 	"time"
 )
 
 // ViewProfile allows the user to view their profile details
-func updateProfile(user *model.User) {
-	userRepo := repository.NewUserRepository(nil)
-	userService := service.NewUserService(userRepo)
+func updateProfile(ctx context.Context, user *model.User, client *sql.DB) {
+	userService := newUserService(client)
 
 	userID := user.ID
 	var choice int
@@ -38,7 +39,7 @@ func updateProfile(user *model.User) {
 			fmt.Print("Enter Email: ")
 			fmt.Scanln(&newEmail)
 			newEmail = strings.TrimSpace(newEmail)
-			err := userService.UpdateUser(userID, &newEmail, &user.Password, &user.Address, &user.Contact)
+			err := userService.UpdateUser(ctx, userID, &newEmail, &user.Password, &user.Address, &user.Contact)
 			if err != nil {
 				color.Red("%v", err)
 			} else {
@@ -52,7 +53,7 @@ func updateProfile(user *model.User) {
 			fmt.Print("Enter Password: ")
 			fmt.Scanln(&newPassword)
 			newPassword = strings.TrimSpace(newPassword)
-			err := userService.UpdateUser(userID, &user.Email, &newPassword, &user.Address, &user.Contact)
+			err := userService.UpdateUser(ctx, userID, &user.Email, &newPassword, &user.Address, &user.Contact)
 			if err != nil {
 				color.Red("%v", err)
 			} else {
@@ -67,7 +68,7 @@ func updateProfile(user *model.User) {
 			fmt.Print("Enter Contact : ")
 			fmt.Scanln(&newPhone)
 			newPhone = strings.TrimSpace(newPhone)
-			err := userService.UpdateUser(userID, &user.Email, &user.Password, &user.Address, &newPhone)
+			err := userService.UpdateUser(ctx, userID, &user.Email, &user.Password, &user.Address, &newPhone)
 			if err != nil {
 				color.Red("%v", err)
 			} else {
@@ -80,7 +81,7 @@ func updateProfile(user *model.User) {
 		fmt.Print("Enter Address: ")
 		fmt.Scanln(&newAddress)
 		newAddress = strings.TrimSpace(newAddress)
-		err := userService.UpdateUser(userID, &user.Email, &user.Password, &newAddress, &user.Contact)
+		err := userService.UpdateUser(ctx, userID, &user.Email, &user.Password, &newAddress, &user.Contact)
 		if err != nil {
 			color.Red("%v", err)
 		} else {
@@ -95,10 +96,9 @@ func updateProfile(user *model.User) {
 	}
 
 }
-func viewProfile(user *model.User) {
-	userRepo := repository.NewUserRepository(nil)
-	userService := service.NewUserService(userRepo)
-	currUser, err := userService.ViewProfileByID(user.ID)
+func viewProfile(ctx context.Context, user *model.User, client *sql.DB) {
+	userService := newUserService(client)
+	currUser, err := userService.ViewProfileByID(ctx, user.ID)
 	if err != nil {
 		color.Red("%v", err)
 	}
@@ -107,22 +107,33 @@ func viewProfile(user *model.User) {
 	color.Cyan("User Address: %s\n", currUser.Address)
 	color.Cyan("User Contact: %s\n", currUser.Contact)
 	color.Cyan("User Role: %s\n", currUser.Role)
+	privacyService := newPrivacyService(client)
 	for {
 		color.Blue("For updateProfile press 1")
-		color.Blue("For previous menu press 2")
+		color.Blue("For exporting your data press 2")
+		color.Blue("For closing your account and erasing your data press 3")
+		color.Blue("For previous menu press 4")
 		var choice int
 		fmt.Scanln(&choice)
-		if choice == 1 {
-			updateProfile(currUser)
-		} else {
-			break
+		switch choice {
+		case 1:
+			updateProfile(ctx, currUser, client)
+		case 2:
+			exportUserData(ctx, privacyService, user.ID, user.ID)
+		case 3:
+			if eraseOwnData(ctx, privacyService, user.ID) {
+				// The dashboard signs the user out on its next check of the account
+				return
+			}
+		default:
+			return
 		}
 	}
 
 }
 
 // view services provide by provider
-func viewServices(householderService *service.HouseholderService) {
+func viewServices(ctx context.Context, householderService *service.HouseholderService, categoryService *service.CategoryService) {
 	//color.Blue("Available Service are: ")
 	//services, err := householderService.GetAvailableServices()
 	//if err != nil {
@@ -138,13 +149,11 @@ func viewServices(householderService *service.HouseholderService) {
 	//	//color.Green("%v", service_test.Price)
 	//
 	//}
-	util.DisplayCategory()
+	displayCategories(ctx, categoryService)
 	color.Blue("View Services by Category:")
-	var category string
-	fmt.Print("Enter the category: ")
-	fmt.Scanln(&category)
+	category := promptLine(bufio.NewReader(os.Stdin), "Enter the category: ")
 
-	services, err := householderService.GetServicesByCategory(category)
+	services, err := householderService.GetServicesByCategory(ctx, category)
 	if err != nil {
 		color.Red("Error fetching services: %v", err)
 		return
@@ -159,55 +168,44 @@ func viewServices(householderService *service.HouseholderService) {
 	for _, service := range services {
 		color.Cyan("Service ID: %s, Name: %s, Description: %s, Price: %.2f, Provider: %s",
 			service.ID, service.Name, service.Description, service.Price, service.ProviderName)
-		color.Cyan("Provider Contact: %s, Provider Address: %s, Provider Rating: %.2f",
-			service.ProviderContact, service.ProviderAddress, service.ProviderRating)
+		color.Cyan("Provider Rating: %.2f, Provider Contact: %s", service.ProviderRating, service.ProviderContact)
 	}
 }
 
-// SearchService allows the householder to search for available service_test providers
-func searchService(householderService *service.HouseholderService, householder *model.Householder) {
-	util.DisplayCategory()
-	//var serviceType string
-	//fmt.Print("Enter the type of service_test you're looking for: ")
-	//fmt.Scanln(&serviceType)
-	//
-	//providers, err := householderService.SearchService(householder, serviceType)
-	//if err != nil {
-	//	color.Red("Error searching for service_test: %v", err)
-	//	return
-	//}
-	//
-	//color.Cyan("Found %d providers for %s:", len(providers), serviceType)
-	//for _, provider := range providers {
-	//	color.Cyan("- %s (%s)", provider.Name, provider.Contact)
-	//}
-	var category string
-	fmt.Print("Enter the category of services you're looking for: ")
-	fmt.Scanln(&category)
+// SearchService allows the householder to search services by name, description, category or provider
+func searchService(ctx context.Context, searchSvc *service.SearchService, householder *model.Householder) {
+	fmt.Print("What are you looking for? ")
+	reader := bufio.NewReader(os.Stdin)
+	text, _ := reader.ReadString('\n')
+	text = strings.TrimSpace(text)
 
-	services, err := householderService.GetServicesByCategory(category)
+	results, err := searchSvc.SearchServices(ctx, householder, text, 0)
 	if err != nil {
-		color.Red("Error fetching services: %v", err)
+		color.Red("Error searching services: %v", err)
 		return
 	}
 
-	if len(services) == 0 {
-		color.Cyan("No services found in this category.")
+	if len(results) == 0 {
+		color.Cyan("No services match %q.", text)
 		return
 	}
 
-	color.Cyan("Available Services:")
-	for _, service := range services {
-		color.Cyan("Service ID: %s, Name: %s, Description: %s, Price: %.2f, Provider: %s",
-			service.ID, service.Name, service.Description, service.Price, service.ProviderName)
-		color.Cyan("Provider Contact: %s, Provider Address: %s, Provider Rating: %.2f",
-			service.ProviderContact, service.ProviderAddress, service.ProviderRating)
+	color.Cyan("Best matches for %q:", text)
+	for _, result := range results {
+		service := result.Service
+		color.Cyan("Service ID: %s, Name: %s, Category: %s, Price: %.2f, Provider: %s, Rating: %.1f",
+			service.ID, service.Name, service.Category, service.Price, service.ProviderName, service.ProviderRating)
+		if result.DistanceKm >= 0 {
+			color.Cyan("Description: %s, Distance: %.1f km", service.Description, result.DistanceKm)
+		} else {
+			color.Cyan("Description: %s", service.Description)
+		}
 	}
 }
 
 // RequestService allows the householder to request a specific service_test
-func requestService(householderService *service.HouseholderService, user *model.Householder) {
-	util.DisplayCategory()
+func requestService(ctx context.Context, householderService *service.HouseholderService, categoryService *service.CategoryService, user *model.Householder) {
+	displayCategories(ctx, categoryService)
 	var serviceType string
 	fmt.Print("Enter the type of service you want to request: ")
 	fmt.Scanln(&serviceType)
@@ -221,7 +219,11 @@ func requestService(householderService *service.HouseholderService, user *model.
 		color.Red("Error parsing new time: %v", err)
 		return
 	}
-	requestID, err := householderService.RequestService(user, serviceType, &newTime)
+	requestID, err := householderService.RequestService(ctx, user, serviceType, &newTime)
+	if errors.Is(err, model.ErrServiceNotOffered) {
+		requestCustomService(ctx, householderService, user, serviceType, &newTime)
+		return
+	}
 	if err != nil {
 		color.Red("Error requesting service: %v", err)
 		return
@@ -230,50 +232,92 @@ func requestService(householderService *service.HouseholderService, user *model.
 	color.Green("Service requested successfully! Your request ID is %s", requestID)
 }
 
-// ViewBookingHistory allows the householder to view their booking history
-func viewBookingHistory(householderService *service.HouseholderService, user *model.User) {
-	history, err := householderService.ViewBookingHistory(user.ID)
-	if err != nil {
-		color.Red("Error viewing booking history: %v", err)
+// requestCustomService offers to send a request for a service that is not offered yet to the admins
+func requestCustomService(ctx context.Context, householderService *service.HouseholderService, user *model.Householder, serviceName string, scheduledTime *time.Time) {
+	color.Yellow("No provider offers %q yet.", serviceName)
+	if !strings.EqualFold(promptOption("Send the request to our team for review? (yes/no): "), "yes") {
 		return
 	}
 
-	if len(history) == 0 {
-		color.Cyan("No booking history found.")
+	requestID, err := householderService.RequestCustomService(ctx, user, serviceName, scheduledTime)
+	if err != nil {
+		color.Red("Error sending the request: %v", err)
 		return
 	}
-
-	color.Cyan("Booking History:")
-	for _, booking := range history {
-		color.Cyan("- %s: %s (%s)", booking.ID, booking.ServiceID, booking.Status)
-	}
+	color.Green("Your request %s is being reviewed, you will find the outcome in your notifications.", requestID)
 }
 
-// LeaveReview allows the householder to leave a review for a service_test provider
-func leaveReview(householderService *service.HouseholderService, user *model.User) {
-	var serviceID, providerID string
+// ViewBookingHistory allows the householder to view their booking history
+func viewBookingHistory(ctx context.Context, householderService *service.HouseholderService, user *model.User) {
+	opts := promptQueryOptions([]string{"requested_time", "scheduled_time", "status"}, true)
+	for {
+		history, err := householderService.ViewBookingHistory(ctx, user.ID, opts)
+		if err != nil {
+			color.Red("Error viewing booking history: %v", err)
+			return
+		}
 
-	var rating float64
+		if len(history) == 0 && opts.Offset == 0 {
+			color.Cyan("No booking history found.")
+			return
+		}
 
-	fmt.Print("Enter the Service ID you want to review: ")
-	fmt.Scanln(&serviceID)
+		color.Cyan("Booking History:")
+		for _, booking := range history {
+			color.Cyan("- %s: %s (%s)", booking.ID, booking.ServiceID, booking.Status)
+		}
+		if !wantNextPage(len(history), opts) {
+			return
+		}
+		opts = opts.NextPage()
+	}
+}
 
-	fmt.Print("Enter the Provider ID you want to review: ")
-	fmt.Scanln(&providerID)
+// LeaveReview allows the householder to review the provider of a completed request
+func leaveReview(ctx context.Context, householderService *service.HouseholderService, user *model.User) {
+	completed, err := householderService.ViewBookingHistory(ctx, user.ID, model.QueryOptions{Status: model.RequestCompleted})
+	if err != nil {
+		color.Red("Error fetching completed requests: %v", err)
+		return
+	}
+	reviews, err := householderService.GetMyReviews(ctx, user.ID)
+	if err != nil {
+		color.Red("Error fetching your reviews: %v", err)
+		return
+	}
+	reviewed := make(map[string]bool, len(reviews))
+	for _, review := range reviews {
+		reviewed[review.RequestID] = true
+	}
+
+	var pending []model.ServiceRequest
+	for _, request := range completed {
+		if !reviewed[request.ID] {
+			pending = append(pending, request)
+		}
+	}
+	if len(pending) == 0 {
+		color.Cyan("You have no completed requests left to review.")
+		return
+	}
+	color.Cyan("Completed requests you can review:")
+	for _, request := range pending {
+		color.Cyan("- %s: %s (scheduled %s)", request.ID, request.ServiceName, request.ScheduledTime.Format("2006-01-02"))
+	}
 
-	fmt.Print("Enter your review: ")
 	reader := bufio.NewReader(os.Stdin)
-	reviewText, err := reader.ReadString('\n')
-	if err != nil {
-		color.Red("Error submitting review: %v", err)
+	requestID := promptLine(reader, "Enter the Request ID you want to review: ")
+	reviewText := promptLine(reader, "Enter your review: ")
+	rating, ok := promptRating(reader)
+	if !ok {
+		return
+	}
+	scores, ok := promptScores(reader)
+	if !ok {
 		return
 	}
-	fmt.Print("Enter your rating (1-5): ")
-	fmt.Scanln(&rating)
 
-	// Call the AddReview method with the providerID now included
-	err = householderService.AddReview(providerID, user.ID, serviceID, reviewText, rating)
-	if err != nil {
+	if err := householderService.AddReview(ctx, user.ID, requestID, reviewText, rating, scores); err != nil {
 		color.Red("Error submitting review: %v", err)
 		return
 	}
@@ -281,12 +325,65 @@ func leaveReview(householderService *service.HouseholderService, user *model.Use
 	color.Green("Review submitted successfully!")
 }
 
-func cancelServiceRequest(householderService *service.HouseholderService) {
+// editReview lets the householder change a review written within the edit window
+func editReview(ctx context.Context, householderService *service.HouseholderService, user *model.User) {
+	reviews, err := householderService.GetMyReviews(ctx, user.ID)
+	if err != nil {
+		color.Red("Error fetching your reviews: %v", err)
+		return
+	}
+	now := time.Now()
+	var editable []model.Review
+	for _, review := range reviews {
+		if review.Editable(now) {
+			editable = append(editable, review)
+		}
+	}
+	if len(editable) == 0 {
+		color.Cyan("You have no reviews that can still be changed.")
+		return
+	}
+	for _, review := range editable {
+		color.Cyan("- %s: %.0f stars, %q (editable until %s)", review.ID, review.Rating, strings.TrimSpace(review.Comments),
+			review.ReviewDate.Add(model.ReviewEditWindow).Format("2006-01-02"))
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	reviewID := promptLine(reader, "Enter the Review ID you want to change: ")
+	reviewText := promptLine(reader, "Enter your review: ")
+	rating, ok := promptRating(reader)
+	if !ok {
+		return
+	}
+	scores, ok := promptScores(reader)
+	if !ok {
+		return
+	}
+
+	if err := householderService.EditReview(ctx, user.ID, reviewID, reviewText, rating, scores); err != nil {
+		color.Red("Error updating review: %v", err)
+		return
+	}
+	color.Green("Review updated successfully!")
+}
+
+// promptRating reads a whole star rating between model.MinRating and model.MaxRating
+func promptRating(reader *bufio.Reader) (float64, bool) {
+	value := promptLine(reader, fmt.Sprintf("Enter your rating (%d-%d): ", model.MinRating, model.MaxRating))
+	rating, err := strconv.Atoi(value)
+	if err != nil || !model.ValidRating(float64(rating)) {
+		color.Red("Error submitting review: %v", model.ErrInvalidRating)
+		return 0, false
+	}
+	return float64(rating), true
+}
+
+func cancelServiceRequest(ctx context.Context, householderService *service.HouseholderService) {
 	var requestID string
 	fmt.Print("Enter the Service Request ID you want to cancel: ")
 	fmt.Scanln(&requestID)
 
-	err := householderService.CancelServiceRequest(requestID)
+	err := householderService.CancelServiceRequest(ctx, requestID)
 	if err != nil {
 		color.Red("Error canceling service_test request: %v", err)
 		return
@@ -295,7 +392,7 @@ func cancelServiceRequest(householderService *service.HouseholderService) {
 	color.Green("Service request %s has been successfully canceled.", requestID)
 }
 
-func rescheduleServiceRequest(householderService *service.HouseholderService) {
+func rescheduleServiceRequest(ctx context.Context, householderService *service.HouseholderService) {
 	var requestID string
 	fmt.Print("Enter the Service Request ID you want to reschedule: ")
 	fmt.Scanln(&requestID)
@@ -310,7 +407,7 @@ func rescheduleServiceRequest(householderService *service.HouseholderService) {
 		return
 	}
 
-	err = householderService.RescheduleServiceRequest(requestID, newTime)
+	err = householderService.RescheduleServiceRequest(ctx, requestID, newTime)
 	if err != nil {
 		color.Red("Error rescheduling service_test request: %v", err)
 		return
@@ -332,9 +429,9 @@ func rescheduleServiceRequest(householderService *service.HouseholderService) {
 //
 //		color.Cyan("Status of service_test request %s: %s", requestID, status)
 //	}
-func viewStatus(householderService *service.HouseholderService, householder *model.Householder) {
+func viewStatus(ctx context.Context, householderService *service.HouseholderService, householder *model.Householder) {
 	// Fetch all service requests for the householder
-	requests, err := householderService.ViewStatus(householderService, householder)
+	requests, err := householderService.ViewStatus(ctx, householderService, householder)
 	if err != nil {
 		color.Red("Error viewing status: %v", err)
 	}
@@ -345,7 +442,10 @@ func viewStatus(householderService *service.HouseholderService, householder *mod
 	}
 
 	for _, request := range requests {
-		color.Cyan("Request ID: %s, Service ID: %s, Status: %s", request.ID, request.ServiceID, request.Status)
+		color.Cyan("Request ID: %s, Service ID: %s, Status: %s, Reference: %s", request.ID, request.ServiceID, request.Status, request.ContactReference)
+		if request.TargetProviderID != nil {
+			color.Cyan("Booked directly with provider %s", *request.TargetProviderID)
+		}
 		if request.Status == "Accepted" && request.ProviderDetails != nil && !request.ApproveStatus {
 			for _, provider := range request.ProviderDetails {
 				color.Green("ServiceProvider Details:")
@@ -388,9 +488,9 @@ func viewStatus(householderService *service.HouseholderService, householder *mod
 		fmt.Scanln(&choice)
 		switch choice {
 		case "1":
-			cancelAcceptedServiceRequest(householderService, householder.ID)
+			cancelAcceptedServiceRequest(ctx, householderService, householder.ID)
 		case "2":
-			ApproveRequest(householderService)
+			ApproveRequest(ctx, householderService)
 		case "3":
 			return
 		default:
@@ -399,12 +499,12 @@ func viewStatus(householderService *service.HouseholderService, householder *mod
 	}
 
 }
-func cancelAcceptedServiceRequest(householderService *service.HouseholderService, householderID string) {
+func cancelAcceptedServiceRequest(ctx context.Context, householderService *service.HouseholderService, householderID string) {
 	var requestID string
 	fmt.Print("Enter the Service Request ID you want to cancel: ")
 	fmt.Scanln(&requestID)
 
-	err := householderService.CancelAcceptedRequest(requestID, householderID)
+	err := householderService.CancelAcceptedRequest(ctx, requestID, householderID)
 	if err != nil {
 		color.Red("Error canceling service request: %v", err)
 		return
@@ -413,7 +513,7 @@ func cancelAcceptedServiceRequest(householderService *service.HouseholderService
 	color.Green("Service request %s has been successfully canceled.", requestID)
 }
 
-func ApproveRequest(householderService *service.HouseholderService) {
+func ApproveRequest(ctx context.Context, householderService *service.HouseholderService) {
 	reader := bufio.NewReader(os.Stdin)
 
 	// Prompt householder for the service request ID
@@ -427,16 +527,16 @@ func ApproveRequest(householderService *service.HouseholderService) {
 	providerID = strings.TrimSpace(providerID)
 
 	// Call the approval function
-	if err := householderService.ApproveServiceRequest(requestID, providerID); err != nil {
+	if err := householderService.ApproveServiceRequest(ctx, requestID, providerID); err != nil {
 		color.Red("Error approving service request: %v", err)
 		return
 	}
 
 	color.Green("Service request approved successfully!")
 }
-func viewApprovedRequests(householderService *service.HouseholderService, householderID string) {
+func viewApprovedRequests(ctx context.Context, householderService *service.HouseholderService, householderID string) {
 	// Call the service method to get approved requests
-	approvedRequests, err := householderService.ViewApprovedRequests(householderID)
+	approvedRequests, err := householderService.ViewApprovedRequests(ctx, householderID)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
@@ -481,14 +581,22 @@ func viewApprovedRequests(householderService *service.HouseholderService, househ
 }
 
 // HouseholderDashboard is the main dashboard for householder actions
-func householderDashboard(user *model.User, client *sql.DB) {
+func householderDashboard(ctx context.Context, user *model.User, client *sql.DB) {
 
 	// Initialize repositories and services
 	householderRepo := repository.NewHouseholderRepository(client)
 	serviceRequestRepo := repository.NewServiceRequestRepository(client)
 	serviceProviderRepo := repository.NewServiceProviderRepository(client)
-	serviceRepo := repository.NewServiceRepository(client)
-	householderService := service.NewHouseholderService(householderRepo, serviceProviderRepo, serviceRepo, serviceRequestRepo)
+	serviceRepo := newServiceRepository(client)
+	customRequestRepo := repository.NewCustomRequestRepository(client)
+	householderService := service.NewHouseholderService(householderRepo, serviceProviderRepo, serviceRepo, serviceRequestRepo, customRequestRepo, newRatingService(client), newAccountService(client), repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+	categoryService := newCategoryService(client)
+	notificationService := service.NewNotificationService(repository.NewNotificationRepository(client))
+	accountService := newAccountService(client)
+	relayService := newMessageRelayService(client)
+	reviewService := newReviewService(client)
+	profileService := newProviderProfileService(client)
+	favouriteService := newFavouriteService(client)
 
 	// Convert the User to a Householder
 	householder := &model.Householder{
@@ -506,33 +614,58 @@ func householderDashboard(user *model.User, client *sql.DB) {
 		color.Blue("8. Reschedule Service Request")
 		color.Blue("9. View Service Request Status")
 		color.Blue("10. View Approved Request")
-		color.Blue("11.Exit")
+		color.Blue("11. View Notifications%s", unreadBadge(ctx, notificationService, user.ID))
+		color.Blue("12. Message a Provider")
+		color.Blue("13. Edit a Review")
+		color.Blue("14. Flag a Review")
+		color.Blue("15. Review Photos")
+		color.Blue("16. View a Provider's Profile")
+		color.Blue("17. Favourite Providers")
+		color.Blue("18. Exit")
 
 		var choice int
 		fmt.Scanln(&choice)
+		if !ensureSession(ctx, accountService, user.ID) {
+			return
+		}
 
 		switch choice {
 		case 1:
-			viewProfile(user)
+			viewProfile(ctx, user, client)
 		case 2:
-			viewServices(householderService)
+			viewServices(ctx, householderService, categoryService)
 		case 3:
-			searchService(householderService, householder)
+			searchService(ctx, service.NewSearchService(serviceIndex), householder)
 		case 4:
-			requestService(householderService, householder)
+			requestService(ctx, householderService, categoryService, householder)
 		case 5:
-			viewBookingHistory(householderService, user)
+			viewBookingHistory(ctx, householderService, user)
 		case 6:
-			leaveReview(householderService, user)
+			leaveReview(ctx, householderService, user)
 		case 7:
-			cancelServiceRequest(householderService)
+			cancelServiceRequest(ctx, householderService)
 		case 8:
-			rescheduleServiceRequest(householderService)
+			rescheduleServiceRequest(ctx, householderService)
 		case 9:
-			viewStatus(householderService, householder)
+			viewStatus(ctx, householderService, householder)
 		case 10:
-			viewApprovedRequests(householderService, user.ID)
+			viewApprovedRequests(ctx, householderService, user.ID)
 		case 11:
+			viewNotifications(ctx, notificationService, user.ID)
+		case 12:
+			sendRelayMessage(ctx, relayService, user.ID, "Enter Service Provider ID: ")
+		case 13:
+			editReview(ctx, householderService, user)
+		case 14:
+			flagReview(ctx, reviewService, user.ID)
+		case 15:
+			manageReviewPhotos(ctx, reviewService, householderService, user)
+		case 16:
+			reader := bufio.NewReader(os.Stdin)
+			viewProviderProfile(ctx, profileService, reviewService, promptLine(reader, "Enter Service Provider ID: "))
+		case 17:
+			manageFavourites(ctx, favouriteService, profileService, reviewService, householder)
+		case 18:
 			return
 		default:
 			color.Red("Invalid choice")
@@ -0,0 +1,122 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/fatih/color"
+	"io/fs"
+	"log/slog"
+	"serviceNest/interfaces"
+	"serviceNest/keyring"
+	"serviceNest/repository"
+	"sort"
+	"strings"
+)
+
+const keysUsage = "usage: serviceNest keys status|rotate|reencrypt|retire <key id>"
+
+// openKeyFile loads the encryption keys and hands them to the repositories. A key file is only created
+// for a database that holds no encrypted values yet; otherwise a missing file means the wrong path or a lost
+// volume, and a new key would leave the stored values unreadable. A newly created key file is reported, it
+// has to be backed up because the data cannot be read without it.
+func openKeyFile(ctx context.Context, client *sql.DB, path string) (*keyring.LocalKeyFile, error) {
+	keyFile, err := keyring.OpenLocalKeyFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		keyFile, err = createKeyFile(ctx, repository.NewFieldEncryptionRepository(client), path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	repository.SetFieldCipher(keyring.NewEnvelope(keyFile))
+	return keyFile, nil
+}
+
+func createKeyFile(ctx context.Context, fields interfaces.FieldEncryptionRepository, path string) (*keyring.LocalKeyFile, error) {
+	usage, err := fields.KeyUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var keyIDs []string
+	for id, count := range usage {
+		if id != "" && count > 0 {
+			keyIDs = append(keyIDs, id)
+		}
+	}
+	if len(keyIDs) > 0 {
+		sort.Strings(keyIDs)
+		return nil, fmt.Errorf("key file %s is missing but the database holds values encrypted with keys %s; restore the key file from a backup or fix storage.key_file",
+			path, strings.Join(keyIDs, ", "))
+	}
+	keyFile, err := keyring.CreateLocalKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+	slog.Warn("created a new encryption key file, back it up together with the database", "path", path)
+	return keyFile, nil
+}
+
+// runKeys handles the `keys` subcommands. rotate adds a new key and moves every stored value to it,
+// reencrypt resumes that after an interruption and encrypts values stored before encryption was turned on.
+func runKeys(ctx context.Context, client *sql.DB, keyFile *keyring.LocalKeyFile, args []string) error {
+	if len(args) == 0 {
+		return errors.New(keysUsage)
+	}
+	fields := repository.NewFieldEncryptionRepository(client)
+
+	switch {
+	case args[0] == "status" && len(args) == 1:
+		usage, err := fields.KeyUsage(ctx)
+		if err != nil {
+			return err
+		}
+		current, _, err := keyFile.CurrentKey(ctx)
+		if err != nil {
+			return err
+		}
+		for _, id := range keyFile.KeyIDs() {
+			marker := ""
+			if id == current {
+				marker = " (current)"
+			}
+			color.Cyan("%s%s: %d values", id, marker, usage[id])
+		}
+		if usage[""] > 0 {
+			color.Yellow("%d values are not encrypted yet, run `keys reencrypt`", usage[""])
+		}
+	case args[0] == "rotate" && len(args) == 1:
+		id, err := keyFile.Rotate()
+		if err != nil {
+			return err
+		}
+		color.Green("Key %s is now the current key", id)
+		return reencrypt(ctx, fields)
+	case args[0] == "reencrypt" && len(args) == 1:
+		return reencrypt(ctx, fields)
+	case args[0] == "retire" && len(args) == 2:
+		usage, err := fields.KeyUsage(ctx)
+		if err != nil {
+			return err
+		}
+		if usage[args[1]] > 0 {
+			return fmt.Errorf("key %s still protects %d values, run `keys reencrypt` first", args[1], usage[args[1]])
+		}
+		if err := keyFile.Retire(args[1]); err != nil {
+			return err
+		}
+		color.Green("Key %s was removed from the key file", args[1])
+	default:
+		return errors.New(keysUsage)
+	}
+	return nil
+}
+
+func reencrypt(ctx context.Context, fields interfaces.FieldEncryptionRepository) error {
+	changed, err := fields.Reencrypt(ctx)
+	color.Green("Re-encrypted %d rows", changed)
+	return err
+}
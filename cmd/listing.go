@@ -0,0 +1,79 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/fatih/color"
+	"serviceNest/model"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const listingDateLayout = "2006-01-02"
+
+// promptQueryOptions asks for the optional filters, sort key and page size of a listing.
+// Leaving an answer empty skips that option.
+func promptQueryOptions(sortKeys []string, withRequestFilters bool) model.QueryOptions {
+	var opts model.QueryOptions
+
+	if withRequestFilters {
+		opts.Status = promptOption("Filter by status (Pending/Accepted/Cancelled/...): ")
+		opts.From = promptDate("Requested on or after (YYYY-MM-DD): ")
+		opts.To = promptDate("Requested before (YYYY-MM-DD): ")
+	}
+	opts.Category = promptOption("Filter by category: ")
+	opts.ProviderID = promptOption("Filter by provider ID: ")
+
+	opts.SortBy = promptOption(fmt.Sprintf("Sort by (%s): ", strings.Join(sortKeys, "/")))
+	opts.Descending = strings.EqualFold(promptOption("Descending order? (yes/no): "), "yes")
+
+	if size := promptOption("Page size (empty for all): "); size != "" {
+		limit, err := strconv.Atoi(size)
+		if err != nil || limit <= 0 {
+			color.Yellow("Invalid page size, showing all results")
+		} else {
+			opts.Limit = limit
+		}
+	}
+	return opts
+}
+
+// wantNextPage reports whether another page may exist and the user wants to see it
+func wantNextPage(shown int, opts model.QueryOptions) bool {
+	if opts.Limit == 0 || shown < opts.Limit {
+		return false
+	}
+	return strings.EqualFold(promptOption("Show next page? (yes/no): "), "yes")
+}
+
+func promptOption(prompt string) string {
+	var value string
+	fmt.Print(prompt)
+	fmt.Scanln(&value)
+	return strings.TrimSpace(value)
+}
+
+// promptLine reads a whole line so that the answer may contain spaces
+func promptLine(reader *bufio.Reader, prompt string) string {
+	fmt.Print(prompt)
+	line, _ := reader.ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func promptDate(prompt string) time.Time {
+	for {
+		value := promptOption(prompt)
+		if value == "" {
+			return time.Time{}
+		}
+		date, err := time.Parse(listingDateLayout, value)
+		if err == nil {
+			return date
+		}
+		color.Red("Invalid date, use the format YYYY-MM-DD")
+	}
+}
@@ -0,0 +1,78 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/fatih/color"
+	"serviceNest/model"
+	"serviceNest/service"
+)
+
+// loginAuditLimit is how many entries the admin sees when looking at the login audit of an address
+const loginAuditLimit = 20
+
+// manageLoginSecurity shows locked out accounts and failed logins, and lets the admin unlock an account
+func manageLoginSecurity(ctx context.Context, admin *model.Admin, authService *service.AuthService) {
+	for {
+		color.Blue("Login Security")
+		color.Blue("1. View Locked Accounts")
+		color.Blue("2. View Failed Logins for an Email")
+		color.Blue("3. Unlock Account")
+		color.Blue("4. Back to Dashboard")
+
+		var choice int
+		fmt.Scanln(&choice)
+
+		switch choice {
+		case 1:
+			locked, err := authService.GetLockedAccounts(ctx)
+			if err != nil {
+				color.Red("Error loading locked accounts: %v", err)
+				continue
+			}
+			if len(locked) == 0 {
+				color.Cyan("No account is locked at the moment.")
+				continue
+			}
+			for _, throttle := range locked {
+				color.Cyan("%s locked until %s", throttle.Key, throttle.LockedUntil.Format("2006-01-02 15:04"))
+			}
+		case 2:
+			email, err := getInput("Enter Email: ")
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			entries, err := authService.GetLoginAudit(ctx, email, loginAuditLimit)
+			if err != nil {
+				color.Red("Error loading the login audit: %v", err)
+				continue
+			}
+			if len(entries) == 0 {
+				color.Cyan("No failed logins on record for this address.")
+				continue
+			}
+			for _, entry := range entries {
+				color.Cyan("%s  %-18s from %s", entry.CreatedAt.Format("2006-01-02 15:04"), entry.Outcome, entry.Source)
+			}
+		case 3:
+			email, err := getInput("Enter Email to unlock: ")
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			if err := authService.UnlockAccount(ctx, admin.User.ID, email); err != nil {
+				color.Red("Could not unlock account: %v", err)
+				continue
+			}
+			color.Green("%s can log in again", email)
+		case 4:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
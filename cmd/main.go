@@ -61,25 +61,89 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/fatih/color"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
+	"serviceNest/blob"
 	"serviceNest/config"
+	"serviceNest/mail"
+	"serviceNest/repository"
 	"syscall"
 )
 
 func main() {
-	if err := runApp(); err != nil {
+	cfg, args, err := config.Load(os.Args[1:], os.LookupEnv)
+	if err != nil {
 		log.Fatal(err)
 	}
+	config.SetCurrent(cfg)
+	setupLogging(cfg.LogLevel)
+	repository.SetQueryTimeout(cfg.Database.QueryTimeout.Duration)
 
+	if len(args) > 0 {
+		if err := runCommand(cfg, args); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if err := runApp(cfg); err != nil {
+		log.Fatal(err)
+	}
+
+}
+
+// runCommand runs a maintenance command such as `migrate up` in place of the interactive app
+func runCommand(cfg *config.Config, args []string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	client := config.GetMySQLDB(cfg.Database)
+	defer client.Close()
+
+	switch args[0] {
+	case "migrate":
+		return runMigrate(ctx, client, args[1:])
+	case "categories":
+		return runCategories(ctx, client, args[1:])
+	case "ratings":
+		return runRatings(ctx, client, args[1:])
+	case "keys":
+		keyFile, err := openKeyFile(ctx, client, cfg.Storage.KeyFile)
+		if err != nil {
+			return err
+		}
+		return runKeys(ctx, client, keyFile, args[1:])
+	case "bootstrap-admin":
+		if _, err := openKeyFile(ctx, client, cfg.Storage.KeyFile); err != nil {
+			return err
+		}
+		return runBootstrapAdmin(ctx, client, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q, expected migrate, categories, ratings, keys or bootstrap-admin", args[0])
+	}
 }
 
-func runApp() error {
-	// Initialize MongoDB Connection
-	client := config.GetMySQLDB()
+// setupLogging routes structured logs through a handler that honours the configured level
+func setupLogging(level string) {
+	var slogLevel slog.Level
+	if err := slogLevel.UnmarshalText([]byte(level)); err != nil {
+		slogLevel = slog.LevelInfo
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slogLevel})))
+	slog.Debug("configuration loaded", "storage", config.Current().Storage.Backend, "category_file", config.Current().CategoryFile)
+}
+
+func runApp(cfg *config.Config) error {
+	// ctx is cancelled on shutdown so that in-flight queries are abandoned
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Initialize MySQL Connection
+	client := config.GetMySQLDB(cfg.Database)
 	defer func() {
 		client.Close()
 	}()
@@ -90,12 +154,33 @@ func runApp() error {
 		log.Println("Connected to database MySql")
 	}
 
+	if err := ensureSchemaUpToDate(ctx, client); err != nil {
+		return err
+	}
+	if _, err := openKeyFile(ctx, client, cfg.Storage.KeyFile); err != nil {
+		return err
+	}
+	if _, err := newRatingService(client).RefreshScores(ctx); err != nil {
+		return fmt.Errorf("could not refresh provider ratings: %v", err)
+	}
+	if err := buildSearchIndex(ctx, client); err != nil {
+		return fmt.Errorf("could not build the search index: %v", err)
+	}
+	go refreshRatings(ctx, client, cfg.Rating.RefreshInterval.Duration)
+	store, err := blob.NewLocalStore(cfg.Storage.BlobDir)
+	if err != nil {
+		return err
+	}
+	blobStore = store
+	mailer = mail.New(cfg.Notification, os.Stdout)
+
 	// Handle interrupt signals for graceful shutdown
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 	go func() {
 		<-c
-		fmt.Println("\nDisconnecting from MySql...")
+		fmt.Println("\nCancelling in-flight work and disconnecting from MySql...")
+		cancel()
 		client.Close()
 		os.Exit(1)
 	}()
@@ -104,20 +189,30 @@ func runApp() error {
 		fmt.Println("-----------------------Welcome-----------------------")
 		color.Blue("For SignUp press 1\n")
 		color.Blue("For Login press 2\n")
-		color.Blue("For Exit press 3\n")
+		color.Blue("To Verify Email press 3\n")
+		color.Blue("If you Forgot Password press 4\n")
+		color.Blue("For Exit press 5\n")
 		var choice int
 		color.Cyan("Enter your choice: ")
 		fmt.Scanln(&choice)
 		switch choice {
 		case 1:
-			if err := SignUpUser(client); err != nil {
+			if err := SignUpUser(ctx, client); err != nil {
 				color.Red("Error during signup: %s", err)
 			}
 		case 2:
-			if err := LoginUser(client); err != nil {
+			if err := LoginUser(ctx, client); err != nil {
 				color.Red("Error during login: %s", err)
 			}
 		case 3:
+			if err := verifyEmail(ctx, client); err != nil {
+				color.Red("Error during email verification: %s", err)
+			}
+		case 4:
+			if err := forgotPassword(ctx, client); err != nil {
+				color.Red("Error during password reset: %s", err)
+			}
+		case 5:
 			return nil
 		default:
 			color.Red("Invalid choice")
@@ -0,0 +1,34 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"github.com/fatih/color"
+	"os"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/service"
+)
+
+// newMessageRelayService wires messaging between householders and providers
+func newMessageRelayService(client *sql.DB) *service.MessageRelayService {
+	return service.NewMessageRelayService(repository.NewUserRepository(client), repository.NewServiceRequestRepository(client),
+		repository.NewNotificationRepository(client), newAccountService(client), repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+}
+
+// sendRelayMessage passes a message about a request to the other party through ServiceNest
+func sendRelayMessage(ctx context.Context, relayService *service.MessageRelayService, senderID, recipientPrompt string) {
+	requestID := promptOption("Enter Service Request ID: ")
+	recipientID := promptOption(recipientPrompt)
+	text := promptLine(bufio.NewReader(os.Stdin), "Enter your message: ")
+
+	if err := relayService.SendMessage(ctx, senderID, requestID, recipientID, text); err != nil {
+		color.Red("Error sending message: %v", err)
+		return
+	}
+	color.Green("Message sent under reference %s, the reply will arrive in your notifications.", model.ContactReference(requestID))
+}
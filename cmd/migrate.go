@@ -0,0 +1,74 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/fatih/color"
+	"serviceNest/migration"
+)
+
+const migrateUsage = "usage: serviceNest migrate up|down|status"
+
+// runMigrate handles the `migrate up|down|status` subcommands
+func runMigrate(ctx context.Context, client *sql.DB, args []string) error {
+	if len(args) != 1 {
+		return errors.New(migrateUsage)
+	}
+
+	migrator, err := migration.NewMigrator(client)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			color.Green("Applied %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			color.Cyan("Schema is already up to date")
+		}
+	case "down":
+		rolledBack, err := migrator.Down(ctx)
+		if err != nil {
+			return err
+		}
+		if rolledBack == nil {
+			color.Cyan("No migrations to roll back")
+			return nil
+		}
+		color.Green("Rolled back %04d_%s", rolledBack.Version, rolledBack.Name)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		for _, status := range statuses {
+			if status.Applied {
+				color.Green("[applied %s] %04d_%s", status.AppliedAt.Format("2006-01-02 15:04:05"), status.Version, status.Name)
+			} else {
+				color.Yellow("[pending] %04d_%s", status.Version, status.Name)
+			}
+		}
+	default:
+		return errors.New(migrateUsage)
+	}
+	return nil
+}
+
+// ensureSchemaUpToDate refuses to start the application against an outdated schema
+func ensureSchemaUpToDate(ctx context.Context, client *sql.DB) error {
+	migrator, err := migration.NewMigrator(client)
+	if err != nil {
+		return err
+	}
+	return migrator.EnsureUpToDate(ctx)
+}
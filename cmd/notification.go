@@ -0,0 +1,42 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/fatih/color"
+	"serviceNest/service"
+)
+
+// unreadBadge shows the number of unread notifications next to the menu entry
+func unreadBadge(ctx context.Context, notificationService *service.NotificationService, userID string) string {
+	unread, err := notificationService.CountUnread(ctx, userID)
+	if err != nil || unread == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d new)", unread)
+}
+
+// viewNotifications prints the inbox of the user, newest first
+func viewNotifications(ctx context.Context, notificationService *service.NotificationService, userID string) {
+	notifications, err := notificationService.GetNotifications(ctx, userID)
+	if err != nil {
+		color.Red("Error loading notifications: %v", err)
+		return
+	}
+	if len(notifications) == 0 {
+		color.Cyan("You have no notifications.")
+		return
+	}
+
+	for _, notification := range notifications {
+		line := fmt.Sprintf("%s  %s", notification.CreatedAt.Format("2006-01-02 15:04"), notification.Message)
+		if notification.Read {
+			color.Cyan("%s", line)
+		} else {
+			color.Green("[new] %s", line)
+		}
+	}
+}
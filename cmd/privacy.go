@@ -0,0 +1,109 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/fatih/color"
+	"os"
+	"serviceNest/repository"
+	"serviceNest/service"
+	"strings"
+	"time"
+)
+
+// newPrivacyService wires personal data export and erasure
+func newPrivacyService(client *sql.DB) *service.PrivacyService {
+	return service.NewPrivacyService(
+		repository.NewUserRepository(client),
+		repository.NewAccountStatusRepository(client),
+		repository.NewRoleHistoryRepository(client),
+		repository.NewServiceRequestRepository(client),
+		repository.NewCustomRequestRepository(client),
+		repository.NewServiceProviderRepository(client),
+		repository.NewProviderDocumentRepository(client),
+		repository.NewReviewPhotoRepository(client),
+		repository.NewProviderProfileRepository(client),
+		repository.NewFavouriteRepository(client),
+		repository.NewNotificationRepository(client),
+		repository.NewLoginAttemptRepository(client),
+		repository.NewAuthTokenRepository(client),
+		repository.NewTwoFactorRepository(client),
+		blobStore,
+		newAccountService(client),
+		repository.NewAuditRepository(client),
+		repository.NewTransactionManager(client),
+	)
+}
+
+// exportUserData writes the data held about userID to a JSON file chosen by the user
+func exportUserData(ctx context.Context, privacyService *service.PrivacyService, requesterID, userID string) {
+	export, err := privacyService.ExportUserData(ctx, requesterID, userID)
+	if err != nil {
+		color.Red("Could not export the data: %v", err)
+		return
+	}
+
+	defaultPath := fmt.Sprintf("servicenest-export-%s-%s.json", userID, time.Now().Format("20060102"))
+	path, err := getInput(fmt.Sprintf("Save to (leave empty for %s): ", defaultPath))
+	if err != nil {
+		color.Red("%v", err)
+		return
+	}
+	if path == "" {
+		path = defaultPath
+	}
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		color.Red("Could not encode the data: %v", err)
+		return
+	}
+	// The file holds personal data, only its owner may read it
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		color.Red("Could not write %s: %v", path, err)
+		return
+	}
+	color.Green("Your data has been saved to %s", path)
+}
+
+// eraseOwnData closes the account of the user after they confirm with their password. It reports whether
+// the data was erased, in which case the user has to be signed out.
+func eraseOwnData(ctx context.Context, privacyService *service.PrivacyService, userID string) bool {
+	color.Yellow("This closes your account and erases your name, email, address and contact details for good.")
+	color.Yellow("Your past requests and ratings are kept without them. Export your data first if you want a copy.")
+	answer, err := getInput("Type ERASE to continue: ")
+	if err != nil || answer != "ERASE" {
+		color.Cyan("Nothing was erased")
+		return false
+	}
+	password, err := getPassword("Enter your password to confirm: ")
+	if err != nil {
+		color.Red("%v", err)
+		return false
+	}
+	if err := privacyService.EraseOwnData(ctx, userID, password); err != nil {
+		color.Red("Could not erase your data: %v", err)
+		return false
+	}
+	color.Green("Your account has been closed and your personal data erased. Goodbye.")
+	return true
+}
+
+// eraseUserData lets an admin erase the data of another account
+func eraseUserData(ctx context.Context, privacyService *service.PrivacyService, adminID string) {
+	userID := promptOption("Enter User ID to erase: ")
+	answer, err := getInput(fmt.Sprintf("Erasing the data of %s cannot be undone, type ERASE to continue: ", userID))
+	if err != nil || strings.TrimSpace(answer) != "ERASE" {
+		color.Cyan("Nothing was erased")
+		return
+	}
+	if err := privacyService.EraseUserData(ctx, adminID, userID); err != nil {
+		color.Red("Could not erase the data: %v", err)
+		return
+	}
+	color.Green("The account %s has been closed and its personal data erased", userID)
+}
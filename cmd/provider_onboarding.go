@@ -0,0 +1,248 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/fatih/color"
+	"io"
+	"os"
+	"path/filepath"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/service"
+)
+
+// blobStore holds uploaded files, it is opened at startup from the configured blob directory
+var blobStore interfaces.BlobStore
+
+// newOnboardingService wires provider verification for a dashboard
+func newOnboardingService(client *sql.DB) *service.ProviderOnboardingService {
+	return service.NewProviderOnboardingService(
+		repository.NewServiceProviderRepository(client),
+		repository.NewProviderDocumentRepository(client),
+		repository.NewNotificationRepository(client),
+		blobStore,
+		newAccountService(client),
+		repository.NewAuditRepository(client),
+		repository.NewTransactionManager(client),
+	)
+}
+
+// providerOnboarding is the dashboard of a provider who has not been verified yet
+func providerOnboarding(ctx context.Context, provider *model.ServiceProvider, onboardingService *service.ProviderOnboardingService, notificationService *service.NotificationService, accountService *service.AccountService) {
+	for {
+		color.Blue("Provider Verification")
+		switch provider.VerificationStatus {
+		case model.VerificationRejected:
+			color.Red("Your verification was rejected: %s", provider.VerificationNote)
+			color.Yellow("Upload new documents to be reviewed again.")
+		default:
+			color.Yellow("Your account is awaiting verification. Upload an identity document, and a licence or insurance certificate if your trade needs one.")
+		}
+		color.Blue("1. Upload Document")
+		color.Blue("2. View Submitted Documents")
+		color.Blue("3. View Notifications%s", unreadBadge(ctx, notificationService, provider.User.ID))
+		color.Blue("4. Exit")
+
+		var choice int
+		fmt.Scanln(&choice)
+		if !ensureSession(ctx, accountService, provider.User.ID) {
+			return
+		}
+
+		switch choice {
+		case 1:
+			uploadProviderDocument(ctx, provider, onboardingService)
+		case 2:
+			viewProviderDocuments(ctx, onboardingService, provider.User.ID)
+		case 3:
+			viewNotifications(ctx, notificationService, provider.User.ID)
+		case 4:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
+func uploadProviderDocument(ctx context.Context, provider *model.ServiceProvider, onboardingService *service.ProviderOnboardingService) {
+	documentType, ok := promptDocumentType()
+	if !ok {
+		return
+	}
+	reader := bufio.NewReader(os.Stdin)
+	path := promptLine(reader, "Enter the path of the file (PDF, JPEG or PNG): ")
+
+	file, err := os.Open(path)
+	if err != nil {
+		color.Red("Error opening file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	document, err := onboardingService.SubmitDocument(ctx, provider.User.ID, documentType, filepath.Base(path), file)
+	if err != nil {
+		color.Red("Error uploading document: %v", err)
+		return
+	}
+	if provider.VerificationStatus == model.VerificationRejected {
+		provider.VerificationStatus = model.VerificationPending
+	}
+	color.Green("Document %s uploaded (%d bytes), an admin will review it shortly", document.FileName, document.Size)
+}
+
+func promptDocumentType() (string, bool) {
+	color.Blue("Document type:")
+	color.Blue("1. Identity document")
+	color.Blue("2. Trade licence")
+	color.Blue("3. Insurance certificate")
+
+	var choice int
+	fmt.Scanln(&choice)
+	switch choice {
+	case 1:
+		return model.DocumentIdentity, true
+	case 2:
+		return model.DocumentLicence, true
+	case 3:
+		return model.DocumentInsurance, true
+	default:
+		color.Red("Invalid choice")
+		return "", false
+	}
+}
+
+func viewProviderDocuments(ctx context.Context, onboardingService *service.ProviderOnboardingService, providerID string) {
+	documents, err := onboardingService.GetDocuments(ctx, providerID)
+	if err != nil {
+		color.Red("Error loading documents: %v", err)
+		return
+	}
+	if len(documents) == 0 {
+		color.Yellow("No documents have been submitted.")
+		return
+	}
+	for _, document := range documents {
+		color.Cyan("Document ID: %s, Type: %s, File: %s, Size: %d bytes, Uploaded: %s", document.ID, document.Type, document.FileName,
+			document.Size, document.UploadedAt.Format("2006-01-02 15:04"))
+	}
+}
+
+// manageProviderVerification lets the admin review the documents of new providers
+func manageProviderVerification(ctx context.Context, admin *model.Admin, onboardingService *service.ProviderOnboardingService) {
+	for {
+		color.Blue("Provider Verification")
+		color.Blue("1. View Pending Providers")
+		color.Blue("2. View Provider Documents")
+		color.Blue("3. Save a Copy of a Document")
+		color.Blue("4. Approve Provider")
+		color.Blue("5. Reject Provider")
+		color.Blue("6. Back to Dashboard")
+
+		var choice int
+		fmt.Scanln(&choice)
+
+		switch choice {
+		case 1:
+			viewPendingProviders(ctx, onboardingService)
+		case 2:
+			var providerID string
+			fmt.Print("Enter Provider ID: ")
+			fmt.Scanln(&providerID)
+			viewProviderDocuments(ctx, onboardingService, providerID)
+		case 3:
+			saveDocumentCopy(ctx, onboardingService)
+		case 4:
+			approveProvider(ctx, admin, onboardingService)
+		case 5:
+			rejectProvider(ctx, admin, onboardingService)
+		case 6:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
+func viewPendingProviders(ctx context.Context, onboardingService *service.ProviderOnboardingService) {
+	providers, err := onboardingService.GetPendingProviders(ctx)
+	if err != nil {
+		color.Red("Error loading providers: %v", err)
+		return
+	}
+	if len(providers) == 0 {
+		color.Yellow("No providers are waiting for verification.")
+		return
+	}
+	for _, provider := range providers {
+		color.Cyan("Provider ID: %s, Name: %s, Email: %s, Contact: %s", provider.User.ID, provider.Name, provider.Email, provider.Contact)
+		if provider.VerificationNote != "" {
+			color.Cyan("Note: %s", provider.VerificationNote)
+		}
+	}
+}
+
+func saveDocumentCopy(ctx context.Context, onboardingService *service.ProviderOnboardingService) {
+	var documentID string
+	fmt.Print("Enter Document ID: ")
+	fmt.Scanln(&documentID)
+	reader := bufio.NewReader(os.Stdin)
+	path := promptLine(reader, "Enter the path to save the copy to: ")
+
+	document, content, err := onboardingService.OpenDocument(ctx, documentID)
+	if err != nil {
+		color.Red("Error opening document: %v", err)
+		return
+	}
+	defer content.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		color.Red("Error creating file: %v", err)
+		return
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		color.Red("Error saving document: %v", err)
+		return
+	}
+	if err := file.Close(); err != nil {
+		color.Red("Error saving document: %v", err)
+		return
+	}
+	color.Green("%s document %s saved to %s", document.Type, document.FileName, path)
+}
+
+func approveProvider(ctx context.Context, admin *model.Admin, onboardingService *service.ProviderOnboardingService) {
+	var providerID string
+	fmt.Print("Enter Provider ID to approve: ")
+	fmt.Scanln(&providerID)
+	reader := bufio.NewReader(os.Stdin)
+	note := promptLine(reader, "Enter a note (optional): ")
+
+	if err := onboardingService.ApproveProvider(ctx, admin.User.ID, providerID, note); err != nil {
+		color.Red("Error approving provider: %v", err)
+		return
+	}
+	color.Green("Provider %s has been verified", providerID)
+}
+
+func rejectProvider(ctx context.Context, admin *model.Admin, onboardingService *service.ProviderOnboardingService) {
+	var providerID string
+	fmt.Print("Enter Provider ID to reject: ")
+	fmt.Scanln(&providerID)
+	reader := bufio.NewReader(os.Stdin)
+	reason := promptLine(reader, "Enter the reason: ")
+
+	if err := onboardingService.RejectProvider(ctx, admin.User.ID, providerID, reason); err != nil {
+		color.Red("Error rejecting provider: %v", err)
+		return
+	}
+	color.Green("Provider %s has been rejected", providerID)
+}
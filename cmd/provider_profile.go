@@ -0,0 +1,210 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"github.com/fatih/color"
+	"io"
+	"os"
+	"path/filepath"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/service"
+	"strconv"
+	"strings"
+)
+
+// newProviderProfileService wires the public profiles of providers
+func newProviderProfileService(client *sql.DB) *service.ProviderProfileService {
+	return service.NewProviderProfileService(repository.NewProviderProfileRepository(client), repository.NewServiceProviderRepository(client),
+		newServiceRepository(client), repository.NewServiceRequestRepository(client), blobStore, newRatingService(client),
+		newAccountService(client), repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+}
+
+func printProviderProfile(profile *model.ProviderProfile) {
+	color.Cyan("Provider: %s (ID %s)", profile.Name, profile.ProviderID)
+	if profile.Available {
+		color.Cyan("Currently taking new jobs")
+	} else {
+		color.Cyan("Currently not taking new jobs")
+	}
+	if profile.Bio != "" {
+		color.Cyan("About: %s", profile.Bio)
+	}
+	color.Cyan("Years of experience: %d, completed jobs: %d", profile.YearsExperience, profile.CompletedJobs)
+	if len(profile.Languages) > 0 {
+		color.Cyan("Languages: %s", strings.Join(profile.Languages, ", "))
+	}
+	for _, certification := range profile.Certifications {
+		printCertification(certification)
+	}
+	if len(profile.Services) == 0 {
+		color.Cyan("No services offered yet.")
+	}
+	for _, offered := range profile.Services {
+		color.Cyan("Service: %s (%s), Price: %.2f, ID %s", offered.Name, offered.Category, offered.Price, offered.ID)
+	}
+	for _, image := range profile.Portfolio {
+		printPortfolioImage(image)
+	}
+}
+
+func printCertification(certification model.Certification) {
+	line := "Certification: " + certification.Name
+	if certification.Issuer != "" {
+		line += ", " + certification.Issuer
+	}
+	if certification.Year != 0 {
+		line += " (" + strconv.Itoa(certification.Year) + ")"
+	}
+	color.Cyan("%s, ID %s", line, certification.ID)
+}
+
+func printPortfolioImage(image model.PortfolioImage) {
+	if image.Caption != "" {
+		color.Cyan("Portfolio: %s - %s, ID %s", image.FileName, image.Caption, image.ID)
+		return
+	}
+	color.Cyan("Portfolio: %s, ID %s", image.FileName, image.ID)
+}
+
+// editPublicProfile lets a provider change what householders see on their profile
+func editPublicProfile(ctx context.Context, profileService *service.ProviderProfileService, providerID string) {
+	for {
+		details, err := profileService.GetProfileDetails(ctx, providerID)
+		if err != nil {
+			color.Red("Error loading your profile: %v", err)
+			return
+		}
+		color.Cyan("About: %s", details.Bio)
+		color.Cyan("Years of experience: %d", details.YearsExperience)
+		color.Cyan("Languages: %s", strings.Join(details.Languages, ", "))
+		for _, certification := range details.Certifications {
+			printCertification(certification)
+		}
+
+		color.Blue("1. Edit Bio, Experience and Languages")
+		color.Blue("2. Add a Certification")
+		color.Blue("3. Remove a Certification")
+		color.Blue("4. Add a Portfolio Image")
+		color.Blue("5. Remove a Portfolio Image")
+		color.Blue("6. Back")
+		switch promptOption("") {
+		case "1":
+			editProfileDetails(ctx, profileService, providerID)
+		case "2":
+			addCertification(ctx, profileService, providerID)
+		case "3":
+			reader := bufio.NewReader(os.Stdin)
+			if err := profileService.RemoveCertification(ctx, providerID, promptLine(reader, "Enter Certification ID: ")); err != nil {
+				color.Red("Error removing certification: %v", err)
+			} else {
+				color.Green("Certification removed")
+			}
+		case "4":
+			addPortfolioImage(ctx, profileService, providerID)
+		case "5":
+			reader := bufio.NewReader(os.Stdin)
+			if err := profileService.RemovePortfolioImage(ctx, providerID, promptLine(reader, "Enter Image ID: ")); err != nil {
+				color.Red("Error removing image: %v", err)
+			} else {
+				color.Green("Image removed from your portfolio")
+			}
+		case "6":
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
+func editProfileDetails(ctx context.Context, profileService *service.ProviderProfileService, providerID string) {
+	reader := bufio.NewReader(os.Stdin)
+	bio := promptLine(reader, "Enter a short bio: ")
+	years, err := strconv.Atoi(promptLine(reader, "Enter your years of experience: "))
+	if err != nil {
+		color.Red("Years of experience must be a whole number")
+		return
+	}
+	languages := promptLine(reader, "Enter the languages you speak, separated by commas: ")
+
+	if err := profileService.UpdateProfileDetails(ctx, providerID, bio, years, []string{languages}); err != nil {
+		color.Red("Error updating profile: %v", err)
+		return
+	}
+	color.Green("Profile updated")
+}
+
+func addCertification(ctx context.Context, profileService *service.ProviderProfileService, providerID string) {
+	reader := bufio.NewReader(os.Stdin)
+	name := promptLine(reader, "Enter the name of the certification: ")
+	issuer := promptLine(reader, "Enter the issuing body (optional): ")
+	var year int
+	if input := promptLine(reader, "Enter the year it was obtained (optional): "); input != "" {
+		var err error
+		if year, err = strconv.Atoi(input); err != nil {
+			color.Red("The year must be a number")
+			return
+		}
+	}
+
+	certification, err := profileService.AddCertification(ctx, providerID, name, issuer, year)
+	if err != nil {
+		color.Red("Error adding certification: %v", err)
+		return
+	}
+	color.Green("Certification added, ID %s", certification.ID)
+}
+
+func addPortfolioImage(ctx context.Context, profileService *service.ProviderProfileService, providerID string) {
+	reader := bufio.NewReader(os.Stdin)
+	path := promptLine(reader, "Enter the path of the photo (JPEG or PNG): ")
+	caption := promptLine(reader, "Enter a caption (optional): ")
+
+	file, err := os.Open(path)
+	if err != nil {
+		color.Red("Error opening file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	image, err := profileService.AddPortfolioImage(ctx, providerID, caption, filepath.Base(path), file)
+	if err != nil {
+		color.Red("Error adding image: %v", err)
+		return
+	}
+	color.Green("Image %s added to your portfolio, ID %s", image.FileName, image.ID)
+}
+
+func savePortfolioImage(ctx context.Context, profileService *service.ProviderProfileService) {
+	reader := bufio.NewReader(os.Stdin)
+	imageID := promptLine(reader, "Enter Image ID: ")
+	path := promptLine(reader, "Enter the path to save the copy to: ")
+
+	image, content, err := profileService.OpenPortfolioImage(ctx, imageID)
+	if err != nil {
+		color.Red("Error opening image: %v", err)
+		return
+	}
+	defer content.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		color.Red("Error creating file: %v", err)
+		return
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		color.Red("Error saving image: %v", err)
+		return
+	}
+	if err := file.Close(); err != nil {
+		color.Red("Error saving image: %v", err)
+		return
+	}
+	color.Green("Image %s saved to %s", image.FileName, path)
+}
@@ -0,0 +1,101 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/fatih/color"
+	"log/slog"
+	"serviceNest/config"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/service"
+	"strconv"
+	"time"
+)
+
+const ratingsUsage = "usage: serviceNest ratings rebuild"
+
+// newRatingService wires the provider rating totals with the configured prior and decay
+func newRatingService(client *sql.DB) *service.RatingService {
+	settings := config.Current().Rating
+	options := model.RatingOptions{PriorMean: settings.PriorMean, PriorWeight: settings.PriorWeight, HalfLife: settings.HalfLife.Duration}
+	return service.NewRatingService(repository.NewRatingRepository(client), repository.NewServiceProviderRepository(client),
+		newServiceRepository(client), options, repository.NewTransactionManager(client))
+}
+
+// refreshRatings keeps the stored provider scores, and the search ranking built from them, in step with
+// the decay of reviews until ctx is cancelled
+func refreshRatings(ctx context.Context, client *sql.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := newRatingService(client).RefreshScores(ctx); err != nil {
+				slog.Warn("could not refresh provider ratings", "error", err)
+			}
+		}
+	}
+}
+
+// runRatings handles the `ratings rebuild` subcommand, which recomputes every provider's rating totals
+// from their reviews. Run it after changing the rating settings.
+func runRatings(ctx context.Context, client *sql.DB, args []string) error {
+	if len(args) != 1 || args[0] != "rebuild" {
+		return errors.New(ratingsUsage)
+	}
+	rebuilt, err := newRatingService(client).Rebuild(ctx)
+	if err != nil {
+		return err
+	}
+	color.Green("Rebuilt the ratings of %d providers", rebuilt)
+	return nil
+}
+
+// printRatingBreakdown shows a provider's score overall and per criterion, across all categories and
+// for each category they were reviewed in
+func printRatingBreakdown(rating *model.ProviderRating) {
+	if rating.All.Overall.ReviewCount == 0 {
+		color.Cyan("No ratings yet.")
+		return
+	}
+	printBreakdown("All categories", rating.All)
+	for _, breakdown := range rating.Categories {
+		printBreakdown(breakdown.Category, breakdown)
+	}
+	color.Cyan("----------------------------------------")
+}
+
+func printBreakdown(title string, breakdown model.RatingBreakdown) {
+	color.Cyan("%s: %.2f (average %.2f from %d reviews)", title, breakdown.Overall.Score, breakdown.Overall.Average, breakdown.Overall.ReviewCount)
+	for _, criterion := range model.RatingCriteria {
+		if score, ok := breakdown.Criteria[criterion]; ok {
+			color.Cyan("  %s: %.2f (%d reviews)", criterion, score.Score, score.ReviewCount)
+		}
+	}
+}
+
+// promptScores asks for the optional sub-scores of a review; a blank answer skips the criterion
+func promptScores(reader *bufio.Reader) (map[string]float64, bool) {
+	scores := make(map[string]float64)
+	for _, criterion := range model.RatingCriteria {
+		value := promptLine(reader, "Rate "+criterion+" (1-5, blank to skip): ")
+		if value == "" {
+			continue
+		}
+		score, err := strconv.Atoi(value)
+		if err != nil || !model.ValidRating(float64(score)) {
+			color.Red("Error submitting review: %v", model.ErrInvalidRating)
+			return nil, false
+		}
+		scores[criterion] = float64(score)
+	}
+	return scores, true
+}
@@ -0,0 +1,306 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/fatih/color"
+	"io"
+	"os"
+	"path/filepath"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/service"
+)
+
+// newReviewService wires replies, flags and moderation of reviews
+func newReviewService(client *sql.DB) *service.ReviewService {
+	return service.NewReviewService(repository.NewServiceProviderRepository(client), repository.NewReviewFlagRepository(client),
+		repository.NewReviewPhotoRepository(client), repository.NewNotificationRepository(client), blobStore, newRatingService(client),
+		newAccountService(client), repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+}
+
+// printReview shows a review together with the provider's reply and, for hidden reviews, the moderator's reason
+func printReview(review model.Review) {
+	color.Cyan("Review ID: %s, Service ID: %s", review.ID, review.ServiceID)
+	color.Cyan("Rating: %v", review.Rating)
+	for _, criterion := range model.RatingCriteria {
+		if score, ok := review.Scores[criterion]; ok {
+			color.Cyan("  %s: %v", criterion, score)
+		}
+	}
+	color.Cyan("Comments: %v", review.Comments)
+	color.Cyan("Date: %s", review.ReviewDate.Format("2006-01-02"))
+	for _, photo := range review.Photos {
+		color.Cyan("Photo (%s): %s, ID %s", photo.Kind, photo.FileName, photo.ID)
+	}
+	if review.Reply != "" {
+		color.Cyan("Reply: %s", review.Reply)
+	}
+	if review.Hidden {
+		color.Yellow("Hidden by a moderator: %s", review.ModerationNote)
+	}
+	color.Cyan("----------------------------------------")
+}
+
+// replyToReview lets a provider answer one of the reviews they received
+func replyToReview(ctx context.Context, reviewService *service.ReviewService, providerID string) {
+	reader := bufio.NewReader(os.Stdin)
+	reviewID := promptLine(reader, "Enter Review ID: ")
+	reply := promptLine(reader, "Enter your reply: ")
+
+	if err := reviewService.ReplyToReview(ctx, providerID, reviewID, reply); err != nil {
+		color.Red("Error posting reply: %v", err)
+		return
+	}
+	color.Green("Reply posted.")
+}
+
+// flagReview reports a review to the moderators
+func flagReview(ctx context.Context, reviewService *service.ReviewService, userID string) {
+	reader := bufio.NewReader(os.Stdin)
+	reviewID := promptLine(reader, "Enter Review ID: ")
+	reason := promptLine(reader, "Why should a moderator look at this review? ")
+
+	if err := reviewService.FlagReview(ctx, userID, reviewID, reason); err != nil {
+		color.Red("Error flagging review: %v", err)
+		return
+	}
+	color.Green("Thank you, a moderator will look at the review.")
+}
+
+// moderateReviews lets the admin work through flagged reviews and hide or restore them
+func moderateReviews(ctx context.Context, admin *model.Admin, reviewService *service.ReviewService) {
+	for {
+		color.Blue("Review Moderation")
+		color.Blue("1. View Flagged Reviews")
+		color.Blue("2. View Hidden Reviews")
+		color.Blue("3. Hide Review")
+		color.Blue("4. Restore Review")
+		color.Blue("5. Back to Dashboard")
+
+		var choice int
+		fmt.Scanln(&choice)
+
+		switch choice {
+		case 1:
+			viewFlaggedReviews(ctx, reviewService)
+		case 2:
+			viewHiddenReviews(ctx, reviewService)
+		case 3:
+			moderateReview(ctx, admin, reviewService, true)
+		case 4:
+			moderateReview(ctx, admin, reviewService, false)
+		case 5:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
+func viewFlaggedReviews(ctx context.Context, reviewService *service.ReviewService) {
+	queue, err := reviewService.GetModerationQueue(ctx)
+	if err != nil {
+		color.Red("Error loading flagged reviews: %v", err)
+		return
+	}
+	if len(queue) == 0 {
+		color.Yellow("The queue is empty.")
+		return
+	}
+
+	for _, entry := range queue {
+		color.Cyan("Provider: %s, Householder: %s", entry.Review.ProviderID, entry.Review.HouseholderID)
+		for _, flag := range entry.Flags {
+			color.Yellow("Flagged by %s on %s: %s", flag.UserID, flag.CreatedAt.Format("2006-01-02 15:04"), flag.Reason)
+		}
+		printReview(entry.Review)
+	}
+}
+
+func viewHiddenReviews(ctx context.Context, reviewService *service.ReviewService) {
+	reviews, err := reviewService.GetHiddenReviews(ctx)
+	if err != nil {
+		color.Red("Error loading hidden reviews: %v", err)
+		return
+	}
+	if len(reviews) == 0 {
+		color.Yellow("No reviews are hidden.")
+		return
+	}
+	for _, review := range reviews {
+		printReview(review)
+	}
+}
+
+func moderateReview(ctx context.Context, admin *model.Admin, reviewService *service.ReviewService, hide bool) {
+	reader := bufio.NewReader(os.Stdin)
+	reviewID := promptLine(reader, "Enter Review ID: ")
+	reason := promptLine(reader, "Enter the reason: ")
+
+	moderate, done := reviewService.RestoreReview, "Review restored."
+	if hide {
+		moderate, done = reviewService.HideReview, "Review hidden, the provider's rating has been updated."
+	}
+	if err := moderate(ctx, admin.User.ID, reviewID, reason); err != nil {
+		color.Red("Error moderating review: %v", err)
+		return
+	}
+	color.Green(done)
+}
+
+// viewProviderProfile shows the public profile of a provider followed by its review section: the rating of
+// every criterion, the reviews and the photo gallery. Review photos and portfolio images can be saved.
+func viewProviderProfile(ctx context.Context, profileService *service.ProviderProfileService, reviewService *service.ReviewService, providerID string) {
+	profile, err := profileService.GetProviderProfile(ctx, providerID)
+	if err != nil {
+		color.Red("Error loading the provider's profile: %v", err)
+		return
+	}
+	printProviderProfile(profile)
+
+	summary, err := reviewService.GetProviderReviewSummary(ctx, providerID)
+	if err != nil {
+		color.Red("Error loading the provider's reviews: %v", err)
+		return
+	}
+	printProviderReviewSummary(summary)
+	if len(summary.Gallery) == 0 && len(profile.Portfolio) == 0 {
+		return
+	}
+
+	color.Blue("1. Save a Review Photo")
+	color.Blue("2. Save a Portfolio Image")
+	color.Blue("3. Back")
+	switch promptOption("") {
+	case "1":
+		savePhoto(ctx, reviewService)
+	case "2":
+		savePortfolioImage(ctx, profileService)
+	}
+}
+
+func printProviderReviewSummary(summary *model.ProviderReviewSummary) {
+	printRatingBreakdown(&summary.Rating)
+	if len(summary.Reviews) == 0 {
+		color.Cyan("No reviews yet.")
+		return
+	}
+	for _, review := range summary.Reviews {
+		printReview(review)
+	}
+	if len(summary.Gallery) > 0 {
+		color.Cyan("Gallery:")
+		for _, photo := range summary.Gallery {
+			color.Cyan("- %s (%s, %s), ID %s", photo.FileName, photo.Kind, photo.UploadedAt.Format("2006-01-02"), photo.ID)
+		}
+	}
+}
+
+// savePhoto writes a copy of a review photo to a file chosen by the user
+func savePhoto(ctx context.Context, reviewService *service.ReviewService) {
+	reader := bufio.NewReader(os.Stdin)
+	photoID := promptLine(reader, "Enter Photo ID: ")
+	path := promptLine(reader, "Enter the path to save the copy to: ")
+
+	photo, content, err := reviewService.OpenPhoto(ctx, photoID)
+	if err != nil {
+		color.Red("Error opening photo: %v", err)
+		return
+	}
+	defer content.Close()
+
+	file, err := os.Create(path)
+	if err != nil {
+		color.Red("Error creating file: %v", err)
+		return
+	}
+	if _, err := io.Copy(file, content); err != nil {
+		file.Close()
+		color.Red("Error saving photo: %v", err)
+		return
+	}
+	if err := file.Close(); err != nil {
+		color.Red("Error saving photo: %v", err)
+		return
+	}
+	color.Green("Photo %s saved to %s", photo.FileName, path)
+}
+
+// manageReviewPhotos lets the householder attach before and after photos to their reviews, or take them off
+func manageReviewPhotos(ctx context.Context, reviewService *service.ReviewService, householderService *service.HouseholderService, user *model.User) {
+	for {
+		color.Blue("Review Photos")
+		color.Blue("1. View Photos of a Review")
+		color.Blue("2. Attach a Photo")
+		color.Blue("3. Remove a Photo")
+		color.Blue("4. Back to Dashboard")
+
+		switch promptOption("") {
+		case "1":
+			viewReviewPhotos(ctx, reviewService, householderService, user)
+		case "2":
+			attachReviewPhoto(ctx, reviewService, user)
+		case "3":
+			reader := bufio.NewReader(os.Stdin)
+			photoID := promptLine(reader, "Enter Photo ID: ")
+			if err := reviewService.RemovePhoto(ctx, user.ID, photoID); err != nil {
+				color.Red("Error removing photo: %v", err)
+				continue
+			}
+			color.Green("Photo removed.")
+		case "4":
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
+
+func viewReviewPhotos(ctx context.Context, reviewService *service.ReviewService, householderService *service.HouseholderService, user *model.User) {
+	reviews, err := householderService.GetMyReviews(ctx, user.ID)
+	if err != nil {
+		color.Red("Error fetching your reviews: %v", err)
+		return
+	}
+	if len(reviews) == 0 {
+		color.Cyan("You have not written any reviews yet.")
+		return
+	}
+	for _, review := range reviews {
+		if review.Photos, err = reviewService.GetPhotos(ctx, review.ID); err != nil {
+			color.Red("Error fetching photos: %v", err)
+			return
+		}
+		printReview(review)
+	}
+}
+
+func attachReviewPhoto(ctx context.Context, reviewService *service.ReviewService, user *model.User) {
+	reader := bufio.NewReader(os.Stdin)
+	reviewID := promptLine(reader, "Enter Review ID: ")
+	kind := model.PhotoAfter
+	if promptLine(reader, "Was the photo taken before the job? (y/n): ") == "y" {
+		kind = model.PhotoBefore
+	}
+	path := promptLine(reader, "Enter the path of the photo (JPEG or PNG): ")
+
+	file, err := os.Open(path)
+	if err != nil {
+		color.Red("Error opening file: %v", err)
+		return
+	}
+	defer file.Close()
+
+	photo, err := reviewService.AttachPhoto(ctx, user.ID, reviewID, kind, filepath.Base(path), file)
+	if err != nil {
+		color.Red("Error attaching photo: %v", err)
+		return
+	}
+	color.Green("Photo %s attached to your review, ID %s", photo.FileName, photo.ID)
+}
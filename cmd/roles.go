@@ -0,0 +1,135 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/fatih/color"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/service"
+)
+
+const bootstrapAdminUsage = "usage: serviceNest bootstrap-admin"
+
+// newRoleService wires admin bootstrap and role assignment
+func newRoleService(client *sql.DB) *service.RoleService {
+	return service.NewRoleService(repository.NewUserRepository(client), repository.NewRoleHistoryRepository(client),
+		repository.NewNotificationRepository(client), newAccountService(client), repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+}
+
+// runBootstrapAdmin handles the `bootstrap-admin` subcommand, which creates the first admin of a new
+// installation. It refuses to run once an admin exists.
+func runBootstrapAdmin(ctx context.Context, client *sql.DB, args []string) error {
+	if len(args) > 0 {
+		return errors.New(bootstrapAdminUsage)
+	}
+	if err := ensureSchemaUpToDate(ctx, client); err != nil {
+		return err
+	}
+
+	name, err := getInput("Enter Name: ")
+	if err != nil {
+		return err
+	}
+	email, err := getInput("Enter Email: ")
+	if err != nil {
+		return err
+	}
+	password, err := getPassword("Enter Password: ")
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	admin, err := newRoleService(client).BootstrapAdmin(ctx, name, email, password)
+	if err != nil {
+		return err
+	}
+	color.Green("Admin %s created with ID %s. Two-factor authentication is set up at the first login.", admin.Email, admin.ID)
+	return nil
+}
+
+// manageRoles lets an admin see the staff accounts and grant or revoke the admin and support roles
+func manageRoles(ctx context.Context, admin *model.Admin, roleService *service.RoleService) {
+	for {
+		color.Blue("Manage Roles")
+		color.Blue("1. View Admins and Support Staff")
+		color.Blue("2. View Role History of an Account")
+		color.Blue("3. Grant Admin Role")
+		color.Blue("4. Grant Support Role")
+		color.Blue("5. Revoke Role")
+		color.Blue("6. Back to Dashboard")
+
+		var choice int
+		fmt.Scanln(&choice)
+
+		switch choice {
+		case 1:
+			staff, err := roleService.GetStaff(ctx)
+			if err != nil {
+				color.Red("Error loading staff accounts: %v", err)
+				continue
+			}
+			for _, user := range staff {
+				color.Cyan("%-8s %s (%s), ID: %s", user.Role, user.Name, user.Email, user.ID)
+			}
+		case 2:
+			userID, err := getInput("Enter User ID: ")
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			history, err := roleService.GetRoleHistory(ctx, userID)
+			if err != nil {
+				color.Red("Error loading role history: %v", err)
+				continue
+			}
+			if len(history) == 0 {
+				color.Cyan("The role of this account has never changed.")
+				continue
+			}
+			for _, change := range history {
+				from := change.FromRole
+				if from == "" {
+					from = "(new account)"
+				}
+				color.Cyan("%s  %s -> %s by %s", change.ChangedAt.Format("2006-01-02 15:04"), from, change.ToRole, change.ChangedBy)
+			}
+		case 3, 4:
+			role := model.RoleAdmin
+			if choice == 4 {
+				role = model.RoleSupport
+			}
+			userID, err := getInput("Enter User ID: ")
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			if err := roleService.GrantRole(ctx, admin.User.ID, userID, role); err != nil {
+				color.Red("Could not grant the role: %v", err)
+				continue
+			}
+			color.Green("The account now has the %s role", role)
+		case 5:
+			userID, err := getInput("Enter User ID: ")
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			if err := roleService.RevokeRole(ctx, admin.User.ID, userID); err != nil {
+				color.Red("Could not revoke the role: %v", err)
+				continue
+			}
+			color.Green("The account is a %s account again", model.RoleHouseholder)
+		case 6:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
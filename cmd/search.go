@@ -0,0 +1,34 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"serviceNest/repository"
+	"serviceNest/search"
+)
+
+// serviceIndex is shared by every dashboard so that service writes are searchable straight away
+var serviceIndex = search.NewIndex()
+
+// newServiceRepository returns a service repository whose writes keep serviceIndex up to date
+func newServiceRepository(client *sql.DB) *repository.IndexedServiceRepository {
+	return repository.NewIndexedServiceRepository(
+		repository.NewServiceRepository(client),
+		serviceIndex,
+		repository.NewServiceProviderRepository(client),
+		repository.NewUserRepository(client),
+	)
+}
+
+// buildSearchIndex loads the stored services into serviceIndex
+func buildSearchIndex(ctx context.Context, client *sql.DB) error {
+	if err := newServiceRepository(client).Reindex(ctx); err != nil {
+		return err
+	}
+	slog.Debug("search index built", "services", serviceIndex.Len())
+	return nil
+}
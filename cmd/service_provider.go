@@ -5,6 +5,7 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"database/sql"
 	"fmt"
 	"github.com/fatih/color"
@@ -16,12 +17,23 @@ import (
 	"time"
 )
 
-func serviceProviderDashboard(user *model.User, client *sql.DB) {
-	serviceRepo := repository.NewServiceRepository(client)
+func serviceProviderDashboard(ctx context.Context, user *model.User, client *sql.DB) {
+	serviceRepo := newServiceRepository(client)
 	requestRepo := repository.NewServiceRequestRepository(client)
 	providerRepo := repository.NewServiceProviderRepository(client)
 
-	providerService := service.NewServiceProviderService(providerRepo, requestRepo, serviceRepo)
+	categoryRepo := repository.NewCategoryRepository(client)
+
+	providerService := service.NewServiceProviderService(providerRepo, requestRepo, serviceRepo, categoryRepo, repository.NewNotificationRepository(client), newAccountService(client), repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+	categoryService := service.NewCategoryService(categoryRepo, serviceRepo, repository.NewRatingRepository(client), repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+	onboardingService := newOnboardingService(client)
+	notificationService := service.NewNotificationService(repository.NewNotificationRepository(client))
+	accountService := newAccountService(client)
+	twoFactorService := newTwoFactorService(client)
+	relayService := newMessageRelayService(client)
+	reviewService := newReviewService(client)
+	ratingService := newRatingService(client)
+	profileService := newProviderProfileService(client)
 	//provider := &model.ServiceProvider{
 	//	User:            *user,
 	//	ServicesOffered: []model.Service{},
@@ -36,30 +48,19 @@ func serviceProviderDashboard(user *model.User, client *sql.DB) {
 		return
 	}
 
-	provider, err := providerRepo.GetProviderByID(user.ID)
+	provider, err := onboardingService.GetOrCreateProvider(ctx, user)
 	if err != nil {
-
-		// If not found, create a new ServiceProvider
-		provider = &model.ServiceProvider{
-			User:            *user,
-			ServicesOffered: []model.Service{},
-			Rating:          0.0,
-			Reviews:         []*model.Review{},
-			Availability:    true,
-			IsActive:        true,
-		}
-
-		// Save the new ServiceProvider to the repository_test
-		err = providerRepo.SaveServiceProvider(*provider)
-		if err != nil {
-			color.Red("Error saving new service provider: %v", err)
-			return
-		}
+		color.Red("Error loading service provider: %v", err)
+		return
 	}
 	if !provider.IsActive {
 		color.Red("Service provider is deactivated by admin")
 		return
 	}
+	if !provider.IsVerified() {
+		providerOnboarding(ctx, provider, onboardingService, notificationService, accountService)
+		return
+	}
 	for {
 		color.Blue("1. View Profile")
 		color.Blue("2. Add Service")
@@ -71,34 +72,51 @@ func serviceProviderDashboard(user *model.User, client *sql.DB) {
 		color.Blue("8. Update Availability")
 		color.Blue("9. View Approved Services")
 		color.Blue("10. View Reviews")
-
-		color.Blue("11. Exit")
+		color.Blue("11. View Notifications%s", unreadBadge(ctx, notificationService, provider.User.ID))
+		color.Blue("12. Two-Factor Authentication")
+		color.Blue("13. Message a Householder")
+		color.Blue("14. Complete Service Request")
+		color.Blue("15. Edit Public Profile")
+		color.Blue("16. Exit")
 
 		var choice int
 		fmt.Scanln(&choice)
+		if !ensureSession(ctx, accountService, provider.User.ID) {
+			return
+		}
 
 		switch choice {
 		case 1:
-			viewProfile(user)
+			viewProfile(ctx, user, client)
 		case 2:
-			addService(providerService, provider)
+			addService(ctx, providerService, categoryService, provider)
 		case 3:
-			viewProviderServices(providerService, provider)
+			viewProviderServices(ctx, providerService, provider)
 		case 4:
-			updateService(providerService, provider)
+			updateService(ctx, providerService, provider)
 		case 5:
-			removeService(providerService, provider)
+			removeService(ctx, providerService, provider)
 		case 6:
-			viewAndAcceptServiceRequest(providerService, provider)
+			viewAndAcceptServiceRequest(ctx, providerService, provider)
 		case 7:
-			declineServiceRequest(providerService, provider)
+			declineServiceRequest(ctx, providerService, provider)
 		case 8:
-			updateAvailability(providerService, provider)
+			updateAvailability(ctx, providerService, provider)
 		case 9:
-			viewApprovedRequestsForProvider(providerService, provider.User.ID)
+			viewApprovedRequestsForProvider(ctx, providerService, provider.User.ID)
 		case 10:
-			viewReview(providerService, provider.User.ID)
+			viewReview(ctx, providerService, reviewService, ratingService, profileService, provider.User.ID)
 		case 11:
+			viewNotifications(ctx, notificationService, provider.User.ID)
+		case 12:
+			manageTwoFactor(ctx, user, twoFactorService)
+		case 13:
+			sendRelayMessage(ctx, relayService, provider.User.ID, "Enter Householder ID: ")
+		case 14:
+			completeServiceRequest(ctx, providerService, provider)
+		case 15:
+			editPublicProfile(ctx, profileService, provider.User.ID)
+		case 16:
 			return
 		default:
 			color.Red("Invalid choice")
@@ -106,9 +124,9 @@ func serviceProviderDashboard(user *model.User, client *sql.DB) {
 	}
 }
 
-func addService(providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
-	util.DisplayCategory()
-	var serviceName, category string
+func addService(ctx context.Context, providerService *service.ServiceProviderService, categoryService *service.CategoryService, provider *model.ServiceProvider) {
+	displayCategories(ctx, categoryService)
+	var serviceName string
 	var price float64
 
 	fmt.Print("Enter service name: ")
@@ -120,8 +138,7 @@ func addService(providerService *service.ServiceProviderService, provider *model
 		color.Red("Error reading desciption")
 		return
 	}
-	fmt.Print("Enter category: ")
-	fmt.Scanln(&category)
+	category := promptLine(reader, "Enter category: ")
 	fmt.Print("Enter price: ")
 	fmt.Scanln(&price)
 
@@ -138,7 +155,7 @@ func addService(providerService *service.ServiceProviderService, provider *model
 		ProviderRating:  provider.Rating,
 	}
 
-	err = providerService.AddService(provider.ID, service)
+	err = providerService.AddService(ctx, provider.ID, service)
 	if err != nil {
 		color.Red("Error adding service_test: %v", err)
 		return
@@ -147,7 +164,7 @@ func addService(providerService *service.ServiceProviderService, provider *model
 	color.Green("Service added successfully!")
 }
 
-func updateService(providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
+func updateService(ctx context.Context, providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
 	var serviceID, newName string
 	var newPrice float64
 
@@ -172,7 +189,7 @@ func updateService(providerService *service.ServiceProviderService, provider *mo
 		Price:       newPrice,
 	}
 
-	err = providerService.UpdateService(provider.ID, serviceID, updatedService)
+	err = providerService.UpdateService(ctx, provider.ID, serviceID, updatedService)
 	if err != nil {
 		color.Red("Error updating service: %v", err)
 		return
@@ -181,13 +198,13 @@ func updateService(providerService *service.ServiceProviderService, provider *mo
 	color.Green("Service updated successfully!")
 }
 
-func removeService(providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
+func removeService(ctx context.Context, providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
 	var serviceID string
 
 	fmt.Print("Enter service ID to remove: ")
 	fmt.Scanln(&serviceID)
 
-	err := providerService.RemoveService(provider.ID, serviceID)
+	err := providerService.RemoveService(ctx, provider.ID, serviceID)
 	if err != nil {
 		color.Red("Error removing service: %v", err)
 		return
@@ -196,13 +213,13 @@ func removeService(providerService *service.ServiceProviderService, provider *mo
 	color.Green("Service removed successfully!")
 }
 
-func declineServiceRequest(providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
+func declineServiceRequest(ctx context.Context, providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
 	var requestID string
 
 	fmt.Print("Enter service request ID to decline: ")
 	fmt.Scanln(&requestID)
 
-	err := providerService.DeclineServiceRequest(provider.ID, requestID)
+	err := providerService.DeclineServiceRequest(ctx, provider.ID, requestID)
 	if err != nil {
 		color.Red("Error declining service request: %v", err)
 		return
@@ -211,14 +228,29 @@ func declineServiceRequest(providerService *service.ServiceProviderService, prov
 	color.Green("Service request declined successfully!")
 }
 
-func updateAvailability(providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
+// completeServiceRequest marks a booked job as done so that the householder can review it
+func completeServiceRequest(ctx context.Context, providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
+	var requestID string
+
+	fmt.Print("Enter service request ID you have completed: ")
+	fmt.Scanln(&requestID)
+
+	if err := providerService.CompleteServiceRequest(ctx, provider.User.ID, requestID); err != nil {
+		color.Red("Error completing service request: %v", err)
+		return
+	}
+
+	color.Green("Service request marked as completed. The householder can now review it.")
+}
+
+func updateAvailability(ctx context.Context, providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
 	var available string
 
 	fmt.Print("Are you available? (yes/no): ")
 	fmt.Scanln(&available)
 
 	isAvailable := available == "yes"
-	err := providerService.UpdateAvailability(provider.ID, isAvailable)
+	err := providerService.UpdateAvailability(ctx, provider.ID, isAvailable)
 	if err != nil {
 		color.Red("Error updating availability: %v", err)
 		return
@@ -226,8 +258,8 @@ func updateAvailability(providerService *service.ServiceProviderService, provide
 
 	color.Green("Availability updated successfully!")
 }
-func viewProviderServices(serviceProviderService *service.ServiceProviderService, provider *model.ServiceProvider) {
-	services, err := serviceProviderService.ViewServices(provider.ID)
+func viewProviderServices(ctx context.Context, serviceProviderService *service.ServiceProviderService, provider *model.ServiceProvider) {
+	services, err := serviceProviderService.ViewServices(ctx, provider.ID)
 	if err != nil {
 		color.Red("Error viewing services: %v", err)
 		return
@@ -243,10 +275,10 @@ func viewProviderServices(serviceProviderService *service.ServiceProviderService
 		color.Cyan("-ID-%s %s: %s (Price: %.2f)", service.ID, service.Name, service.Description, service.Price)
 	}
 }
-func viewAndAcceptServiceRequest(providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
+func viewAndAcceptServiceRequest(ctx context.Context, providerService *service.ServiceProviderService, provider *model.ServiceProvider) {
 
-	// Fetch all service requests
-	serviceRequests, err := providerService.GetAllServiceRequests()
+	// Fetch all service requests, oldest first
+	serviceRequests, err := providerService.GetAllServiceRequests(ctx, provider.User.ID, model.QueryOptions{SortBy: "requested_time"})
 	if err != nil {
 		color.Red("Error fetching service requests: %v", err)
 		return
@@ -257,7 +289,11 @@ func viewAndAcceptServiceRequest(providerService *service.ServiceProviderService
 	for _, request := range serviceRequests {
 		if request.ApproveStatus == false && request.Status != "Cancelled" {
 			pendingRequests = append(pendingRequests, request)
-			color.Cyan("Request ID: %s, Service ID: %s", request.ID, request.ServiceID)
+			if request.TargetProviderID != nil {
+				color.Cyan("Request ID: %s, Service ID: %s (booked directly with you)", request.ID, request.ServiceID)
+			} else {
+				color.Cyan("Request ID: %s, Service ID: %s", request.ID, request.ServiceID)
+			}
 		}
 	}
 
@@ -272,7 +308,7 @@ func viewAndAcceptServiceRequest(providerService *service.ServiceProviderService
 	fmt.Scanln(&requestID)
 
 	// Fetch the service request by ID
-	serviceRequest, err := providerService.GetServiceRequestByID(requestID)
+	serviceRequest, err := providerService.GetServiceRequestByID(ctx, provider.User.ID, requestID)
 	if err != nil {
 		color.Red("Error fetching service request: %v", err)
 		return
@@ -281,7 +317,10 @@ func viewAndAcceptServiceRequest(providerService *service.ServiceProviderService
 	// Display the details of the service_test request
 	color.Cyan("Service Request Details:")
 	color.Cyan("Request ID: %s", serviceRequest.ID)
-	color.Cyan("Householder ID: %v", serviceRequest.HouseholderID)
+	if serviceRequest.HouseholderID != nil {
+		color.Cyan("Householder ID: %s", *serviceRequest.HouseholderID)
+	}
+	color.Cyan("Reference: %s", serviceRequest.ContactReference)
 	color.Cyan("Service Name: %s", serviceRequest.ServiceName)
 	color.Cyan("Service ID: %s", serviceRequest.ServiceID)
 	color.Cyan("Requested Time: %s", serviceRequest.RequestedTime.Format(time.RFC1123))
@@ -294,8 +333,12 @@ func viewAndAcceptServiceRequest(providerService *service.ServiceProviderService
 	fmt.Scanln(&accept)
 
 	if accept == "yes" {
+		var estimatedPrice string
+		color.Cyan("Enter the Price for service:")
+		fmt.Scanln(&estimatedPrice)
+
 		// Accept the service_test request
-		err = providerService.AcceptServiceRequest(provider.ID, requestID)
+		err = providerService.AcceptServiceRequest(ctx, provider.ID, requestID, estimatedPrice)
 		if err != nil {
 			color.Red("Error accepting service request: %v", err)
 			return
@@ -305,9 +348,9 @@ func viewAndAcceptServiceRequest(providerService *service.ServiceProviderService
 		color.Yellow("Service request not accepted.")
 	}
 }
-func viewApprovedRequestsForProvider(serviceProviderService *service.ServiceProviderService, providerID string) {
+func viewApprovedRequestsForProvider(ctx context.Context, serviceProviderService *service.ServiceProviderService, providerID string) {
 	// Call the service method to get approved requests
-	approvedRequests, err := serviceProviderService.ViewApprovedRequestsByHouseholder(providerID)
+	approvedRequests, err := serviceProviderService.ViewApprovedRequestsByHouseholder(ctx, providerID)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return
@@ -350,18 +393,36 @@ func viewApprovedRequestsForProvider(serviceProviderService *service.ServiceProv
 	}
 }
 
-func viewReview(serviceProviderService *service.ServiceProviderService, providerID string) {
-	reviews, err := serviceProviderService.GetReviews(providerID)
+func viewReview(ctx context.Context, serviceProviderService *service.ServiceProviderService, reviewService *service.ReviewService, ratingService *service.RatingService, profileService *service.ProviderProfileService, providerID string) {
+	if rating, err := ratingService.GetProviderRating(ctx, providerID); err != nil {
+		color.Red("Error fetching your rating: %v", err)
+	} else {
+		printRatingBreakdown(rating)
+	}
+
+	reviews, err := serviceProviderService.GetReviews(ctx, providerID)
 	if err != nil {
 		color.Red("Error fetching reviews: %v", err)
 		return
 	}
+	if len(reviews) == 0 {
+		color.Cyan("You have no reviews yet.")
+		return
+	}
 	for _, review := range reviews {
-		color.Cyan("ServiceID: %v ", review.ServiceID)
-		color.Cyan("Rating: %v ", review.Rating)
-		color.Cyan("Comments: %v", review.Comments)
-		color.Cyan("Date: %v", review.ReviewDate)
-		color.Cyan("----------------------------------------")
+		printReview(review)
+	}
 
+	color.Blue("1. Reply to a Review")
+	color.Blue("2. Flag a Review")
+	color.Blue("3. View Your Profile Page")
+	color.Blue("4. Back")
+	switch promptOption("") {
+	case "1":
+		replyToReview(ctx, reviewService, providerID)
+	case "2":
+		flagReview(ctx, reviewService, providerID)
+	case "3":
+		viewProviderProfile(ctx, profileService, reviewService, providerID)
 	}
 }
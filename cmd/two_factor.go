@@ -0,0 +1,103 @@
+//go:build !test
+// +build !test
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/fatih/color"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/service"
+)
+
+// newTwoFactorService wires TOTP enrolment and code checks
+func newTwoFactorService(client *sql.DB) *service.TwoFactorService {
+	return service.NewTwoFactorService(
+		repository.NewUserRepository(client),
+		repository.NewTwoFactorRepository(client),
+		newAccountService(client),
+		repository.NewAuditRepository(client),
+		repository.NewTransactionManager(client),
+	)
+}
+
+// manageTwoFactor lets a signed-in user turn two-factor authentication on or off and renew recovery codes
+func manageTwoFactor(ctx context.Context, user *model.User, twoFactorService *service.TwoFactorService) {
+	for {
+		enabled, err := twoFactorService.IsEnabled(ctx, user.ID)
+		if err != nil {
+			color.Red("Error loading two-factor settings: %v", err)
+			return
+		}
+		if enabled {
+			left, err := twoFactorService.RecoveryCodesLeft(ctx, user.ID)
+			if err != nil {
+				color.Red("Error loading recovery codes: %v", err)
+				return
+			}
+			color.Cyan("Two-factor authentication is on, %d recovery codes left", left)
+		} else {
+			color.Cyan("Two-factor authentication is off")
+		}
+
+		color.Blue("Two-Factor Authentication")
+		color.Blue("1. Turn On")
+		color.Blue("2. New Recovery Codes")
+		color.Blue("3. Turn Off")
+		color.Blue("4. Back to Dashboard")
+
+		var choice int
+		fmt.Scanln(&choice)
+
+		switch choice {
+		case 1:
+			enrolment, err := twoFactorService.StartEnrolment(ctx, user.ID)
+			if err != nil {
+				color.Red("Could not turn on two-factor authentication: %v", err)
+				continue
+			}
+			showEnrolment(enrolment)
+			code, err := getInput("Enter the code shown by your authenticator app: ")
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			recoveryCodes, err := twoFactorService.ConfirmEnrolment(ctx, user.ID, code)
+			if err != nil {
+				color.Red("Could not turn on two-factor authentication: %v", err)
+				continue
+			}
+			showRecoveryCodes(recoveryCodes)
+		case 2:
+			code, err := getInput("Enter a code from your authenticator app: ")
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			recoveryCodes, err := twoFactorService.RegenerateRecoveryCodes(ctx, user.ID, code)
+			if err != nil {
+				color.Red("Could not create new recovery codes: %v", err)
+				continue
+			}
+			showRecoveryCodes(recoveryCodes)
+		case 3:
+			code, err := getInput("Enter a code from your authenticator app: ")
+			if err != nil {
+				color.Red("%v", err)
+				continue
+			}
+			if err := twoFactorService.Disable(ctx, user.ID, code); err != nil {
+				color.Red("Could not turn off two-factor authentication: %v", err)
+				continue
+			}
+			color.Green("Two-factor authentication is off")
+		case 4:
+			return
+		default:
+			color.Red("Invalid choice")
+		}
+	}
+}
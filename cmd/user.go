@@ -4,45 +4,158 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
 	"github.com/fatih/color"
+	"serviceNest/config"
+	"serviceNest/interfaces"
 	"serviceNest/model"
 	"serviceNest/repository"
+	"serviceNest/service"
 )
 
-func SignUpUser(client *sql.DB) error {
+// mailer delivers verification and password reset codes, it is chosen at startup from the notification settings
+var mailer interfaces.Mailer
+
+// newAuthService wires sign-in, email verification and password reset
+func newAuthService(client *sql.DB) *service.AuthService {
+	auth := config.Current().Auth
+	return service.NewAuthService(
+		repository.NewUserRepository(client),
+		repository.NewAuthTokenRepository(client),
+		repository.NewLoginAttemptRepository(client),
+		newTwoFactorService(client),
+		mailer,
+		newAccountService(client),
+		repository.NewAuditRepository(client),
+		service.AuthOptions{
+			RequireVerifiedEmail:     auth.RequireVerifiedEmail,
+			VerificationTokenTTL:     auth.VerificationTokenTTL.Duration,
+			ResetTokenTTL:            auth.ResetTokenTTL.Duration,
+			MaxFailedLogins:          auth.MaxFailedLogins,
+			MaxFailedLoginsPerSource: auth.MaxFailedLoginsPerSource,
+			LockoutDuration:          auth.LockoutDuration.Duration,
+		},
+		repository.NewTransactionManager(client),
+	)
+}
+
+// newUserService wires profile viewing and editing
+func newUserService(client *sql.DB) *service.UserService {
+	return service.NewUserService(repository.NewUserRepository(client), newAccountService(client), repository.NewAuditRepository(client), repository.NewTransactionManager(client))
+}
+
+func SignUpUser(ctx context.Context, client *sql.DB) error {
 	userRepo := repository.NewUserRepository(client)
 
-	_, err := SignUp(userRepo)
+	user, err := SignUp(ctx, userRepo)
 	if err != nil {
 		return err
 	}
+	if err := newAuthService(client).SendEmailVerification(ctx, user.Email); err != nil {
+		color.Red("Could not send the verification email: %v", err)
+		return nil
+	}
+	color.Green("A verification code has been sent to %s, choose \"Verify Email\" to enter it", user.Email)
 	return nil
 }
 
-func LoginUser(client *sql.DB) error {
-	userRepo := repository.NewUserRepository(client)
-
-	user, err := Login(userRepo)
+func LoginUser(ctx context.Context, client *sql.DB) error {
+	user, err := Login(ctx, newAuthService(client), newTwoFactorService(client))
 	if err != nil {
 		return err
 	}
-	dashBoard(user, client)
+	dashBoard(ctx, user, client)
 	return nil
 }
 
-func dashBoard(user *model.User, client *sql.DB) {
+func dashBoard(ctx context.Context, user *model.User, client *sql.DB) {
 	color.Blue("Welcome to Service Nest")
+	// Everything done from the dashboard is recorded in the audit log under this user
+	ctx = service.WithActor(ctx, user.ID)
+
+	switch user.Role {
+	case model.RoleHouseholder:
+		householderDashboard(ctx, user, client)
+	case model.RoleServiceProvider:
+		serviceProviderDashboard(ctx, user, client)
+	case model.RoleAdmin:
+		adminDashboard(ctx, &model.Admin{User: user}, client)
+	case model.RoleSupport:
+		supportDashboard(ctx, &model.Admin{User: user}, client)
+	default:
+		color.Red("%v", model.ErrUnknownRole)
+	}
+
+}
+
+// verifyEmail redeems the code from the verification email, or sends a new one
+func verifyEmail(ctx context.Context, client *sql.DB) error {
+	authService := newAuthService(client)
 
-	if user.Role == "Householder" {
-		householderDashboard(user, client)
-	} else if user.Role == "ServiceProvider" {
-		serviceProviderDashboard(user, client)
-	} else {
-		admin := &model.Admin{
-			user,
+	color.Blue("1. Enter Verification Code")
+	color.Blue("2. Send a New Code")
+	var choice int
+	fmt.Scanln(&choice)
+	switch choice {
+	case 1:
+		code, err := getInput("Enter the code from the email: ")
+		if err != nil {
+			return err
 		}
-		adminDashboard(admin, client)
+		if err := authService.VerifyEmail(ctx, code); err != nil {
+			return err
+		}
+		color.Green("Your email address has been verified")
+	case 2:
+		email, err := getInput("Enter Email: ")
+		if err != nil {
+			return err
+		}
+		if err := authService.SendEmailVerification(ctx, email); err != nil {
+			return err
+		}
+		color.Green("If the address belongs to an unverified account, a new code has been sent to it")
+	default:
+		color.Red("Invalid choice")
 	}
+	return nil
+}
+
+// forgotPassword emails a reset code and lets the user choose a new password with it
+func forgotPassword(ctx context.Context, client *sql.DB) error {
+	authService := newAuthService(client)
 
+	color.Blue("1. Request a Reset Code")
+	color.Blue("2. Enter Reset Code")
+	var choice int
+	fmt.Scanln(&choice)
+	switch choice {
+	case 1:
+		email, err := getInput("Enter Email: ")
+		if err != nil {
+			return err
+		}
+		if err := authService.RequestPasswordReset(ctx, email); err != nil {
+			return err
+		}
+		color.Green("If an account exists for this address, a reset code has been sent to it")
+	case 2:
+		code, err := getInput("Enter the code from the email: ")
+		if err != nil {
+			return err
+		}
+		password, err := getPassword("Enter New Password: ")
+		if err != nil {
+			return err
+		}
+		if err := authService.ResetPassword(ctx, code, password); err != nil {
+			return err
+		}
+		color.Green("Your password has been changed, you can log in now")
+	default:
+		color.Red("Invalid choice")
+	}
+	return nil
 }
@@ -1,3 +1,399 @@
 package config
 
-const FILENAME = "service_category.json"
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const envPrefix = "SERVICENEST_"
+
+// Config is the typed application configuration. Values are layered in the order
+// defaults < config file < SERVICENEST_* environment variables < command-line flags.
+type Config struct {
+	Database     DatabaseConfig     `json:"database" yaml:"database"`
+	Storage      StorageConfig      `json:"storage" yaml:"storage"`
+	CategoryFile string             `json:"category_file" yaml:"category_file"`
+	Notification NotificationConfig `json:"notification" yaml:"notification"`
+	Auth         AuthConfig         `json:"auth" yaml:"auth"`
+	Rating       RatingConfig       `json:"rating" yaml:"rating"`
+	LogLevel     string             `json:"log_level" yaml:"log_level"`
+}
+
+type DatabaseConfig struct {
+	DSN             string   `json:"dsn" yaml:"dsn"`
+	MaxOpenConns    int      `json:"max_open_conns" yaml:"max_open_conns"`
+	MaxIdleConns    int      `json:"max_idle_conns" yaml:"max_idle_conns"`
+	ConnMaxLifetime Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime"`
+	QueryTimeout    Duration `json:"query_timeout" yaml:"query_timeout"` // deadline for each repository call
+}
+
+type StorageConfig struct {
+	Backend string `json:"backend" yaml:"backend"`   // only "mysql" is supported
+	BlobDir string `json:"blob_dir" yaml:"blob_dir"` // directory for uploaded files such as provider documents
+	KeyFile string `json:"key_file" yaml:"key_file"` // keys encrypting addresses and contact numbers, created when missing
+}
+
+type NotificationConfig struct {
+	Enabled  bool   `json:"enabled" yaml:"enabled"`
+	Channel  string `json:"channel" yaml:"channel"` // console or smtp
+	SMTPHost string `json:"smtp_host" yaml:"smtp_host"`
+	SMTPPort int    `json:"smtp_port" yaml:"smtp_port"`
+	From     string `json:"from" yaml:"from"`
+}
+
+type AuthConfig struct {
+	RequireVerifiedEmail bool     `json:"require_verified_email" yaml:"require_verified_email"` // block login until the email is verified
+	VerificationTokenTTL Duration `json:"verification_token_ttl" yaml:"verification_token_ttl"`
+	ResetTokenTTL        Duration `json:"reset_token_ttl" yaml:"reset_token_ttl"`
+
+	MaxFailedLogins          int      `json:"max_failed_logins" yaml:"max_failed_logins"`                       // failures before an account is locked
+	MaxFailedLoginsPerSource int      `json:"max_failed_logins_per_source" yaml:"max_failed_logins_per_source"` // failures before a source is locked
+	LockoutDuration          Duration `json:"lockout_duration" yaml:"lockout_duration"`
+}
+
+// RatingConfig tunes how reviews are combined into a provider's rating. Every provider starts from
+// PriorWeight imaginary reviews of PriorMean stars, and a review counts half as much every HalfLife.
+// The stored scores are brought up to date with the decay every RefreshInterval.
+type RatingConfig struct {
+	PriorMean       float64  `json:"prior_mean" yaml:"prior_mean"`
+	PriorWeight     float64  `json:"prior_weight" yaml:"prior_weight"`
+	HalfLife        Duration `json:"half_life" yaml:"half_life"` // 0 turns the decay off
+	RefreshInterval Duration `json:"refresh_interval" yaml:"refresh_interval"`
+}
+
+// Duration is a time.Duration that is written as "30s" or "5m" in config files.
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string like \"5m\": %v", err)
+	}
+	return d.set(value)
+}
+
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	return d.set(node.Value)
+}
+
+func (d *Duration) set(value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	d.Duration = parsed
+	return nil
+}
+
+var current = Default()
+
+// Default returns the configuration used when nothing overrides it.
+func Default() *Config {
+	return &Config{
+		Database: DatabaseConfig{
+			MaxOpenConns:    10,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: Duration{5 * time.Minute},
+			QueryTimeout:    Duration{5 * time.Second},
+		},
+		Storage:      StorageConfig{Backend: "mysql", BlobDir: "data/blobs", KeyFile: "data/keys.json"},
+		CategoryFile: "service_category.json",
+		Notification: NotificationConfig{Channel: "console", SMTPPort: 587},
+		Auth: AuthConfig{
+			VerificationTokenTTL: Duration{24 * time.Hour},
+			ResetTokenTTL:        Duration{30 * time.Minute},
+
+			MaxFailedLogins:          10,
+			MaxFailedLoginsPerSource: 50,
+			LockoutDuration:          Duration{15 * time.Minute},
+		},
+		Rating:   RatingConfig{PriorMean: 3.5, PriorWeight: 5, HalfLife: Duration{365 * 24 * time.Hour}, RefreshInterval: Duration{24 * time.Hour}},
+		LogLevel: "info",
+	}
+}
+
+// Current returns the configuration loaded at startup, or the defaults before SetCurrent is called.
+func Current() *Config {
+	return current
+}
+
+// SetCurrent makes cfg the configuration returned by Current.
+func SetCurrent(cfg *Config) {
+	current = cfg
+}
+
+// Load builds the configuration from the config file, the environment and the command-line
+// flags in args. It returns the arguments left over after flag parsing (e.g. subcommands).
+func Load(args []string, lookupEnv func(string) (string, bool)) (*Config, []string, error) {
+	cfg := Default()
+
+	flags := flag.NewFlagSet("serviceNest", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	configFile := flags.String("config", "", "path to a YAML or JSON config file")
+	dsn := flags.String("db-dsn", "", "MySQL data source name")
+	maxOpen := flags.Int("db-max-open-conns", 0, "maximum open database connections")
+	maxIdle := flags.Int("db-max-idle-conns", 0, "maximum idle database connections")
+	maxLifetime := flags.Duration("db-conn-max-lifetime", 0, "maximum lifetime of a database connection")
+	queryTimeout := flags.Duration("db-query-timeout", 0, "deadline for each database call")
+	backend := flags.String("storage-backend", "", "storage backend")
+	blobDir := flags.String("storage-blob-dir", "", "directory for uploaded files")
+	keyFile := flags.String("storage-key-file", "", "file holding the encryption keys for personal data")
+	categoryFile := flags.String("category-file", "", "path to the service category file")
+	notifyEnabled := flags.Bool("notification-enabled", false, "enable notifications")
+	notifyChannel := flags.String("notification-channel", "", "notification channel (console or smtp)")
+	requireVerified := flags.Bool("auth-require-verified-email", false, "block login until the email address is verified")
+	logLevel := flags.String("log-level", "", "log level (debug, info, warn, error)")
+	if err := flags.Parse(args); err != nil {
+		return nil, nil, fmt.Errorf("invalid command-line flags: %v", err)
+	}
+
+	path := *configFile
+	if path == "" {
+		path, _ = lookupEnv(envPrefix + "CONFIG")
+	}
+	if path != "" {
+		if err := loadFile(cfg, path); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := applyEnv(cfg, lookupEnv); err != nil {
+		return nil, nil, err
+	}
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "db-dsn":
+			cfg.Database.DSN = *dsn
+		case "db-max-open-conns":
+			cfg.Database.MaxOpenConns = *maxOpen
+		case "db-max-idle-conns":
+			cfg.Database.MaxIdleConns = *maxIdle
+		case "db-conn-max-lifetime":
+			cfg.Database.ConnMaxLifetime = Duration{*maxLifetime}
+		case "db-query-timeout":
+			cfg.Database.QueryTimeout = Duration{*queryTimeout}
+		case "storage-backend":
+			cfg.Storage.Backend = *backend
+		case "storage-blob-dir":
+			cfg.Storage.BlobDir = *blobDir
+		case "storage-key-file":
+			cfg.Storage.KeyFile = *keyFile
+		case "category-file":
+			cfg.CategoryFile = *categoryFile
+		case "notification-enabled":
+			cfg.Notification.Enabled = *notifyEnabled
+		case "notification-channel":
+			cfg.Notification.Channel = *notifyChannel
+		case "auth-require-verified-email":
+			cfg.Auth.RequireVerifiedEmail = *requireVerified
+		case "log-level":
+			cfg.LogLevel = *logLevel
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, nil, err
+	}
+	return cfg, flags.Args(), nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read config file: %v", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, cfg)
+	case ".json":
+		err = json.Unmarshal(data, cfg)
+	default:
+		return fmt.Errorf("unsupported config file type %q", filepath.Ext(path))
+	}
+	if err != nil {
+		return fmt.Errorf("could not parse config file %s: %v", path, err)
+	}
+	return nil
+}
+
+func applyEnv(cfg *Config, lookupEnv func(string) (string, bool)) error {
+	stringVars := map[string]*string{
+		"DB_DSN":                 &cfg.Database.DSN,
+		"STORAGE_BACKEND":        &cfg.Storage.Backend,
+		"STORAGE_BLOB_DIR":       &cfg.Storage.BlobDir,
+		"STORAGE_KEY_FILE":       &cfg.Storage.KeyFile,
+		"CATEGORY_FILE":          &cfg.CategoryFile,
+		"NOTIFICATION_CHANNEL":   &cfg.Notification.Channel,
+		"NOTIFICATION_SMTP_HOST": &cfg.Notification.SMTPHost,
+		"NOTIFICATION_FROM":      &cfg.Notification.From,
+		"LOG_LEVEL":              &cfg.LogLevel,
+	}
+	for name, target := range stringVars {
+		if value, ok := lookupEnv(envPrefix + name); ok {
+			*target = value
+		}
+	}
+
+	intVars := map[string]*int{
+		"DB_MAX_OPEN_CONNS":      &cfg.Database.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS":      &cfg.Database.MaxIdleConns,
+		"NOTIFICATION_SMTP_PORT": &cfg.Notification.SMTPPort,
+
+		"AUTH_MAX_FAILED_LOGINS":            &cfg.Auth.MaxFailedLogins,
+		"AUTH_MAX_FAILED_LOGINS_PER_SOURCE": &cfg.Auth.MaxFailedLoginsPerSource,
+	}
+	for name, target := range intVars {
+		if value, ok := lookupEnv(envPrefix + name); ok {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s%s must be an integer", envPrefix, name)
+			}
+			*target = parsed
+		}
+	}
+
+	if value, ok := lookupEnv(envPrefix + "DB_CONN_MAX_LIFETIME"); ok {
+		if err := cfg.Database.ConnMaxLifetime.set(value); err != nil {
+			return fmt.Errorf("%sDB_CONN_MAX_LIFETIME must be a duration: %v", envPrefix, err)
+		}
+	}
+	if value, ok := lookupEnv(envPrefix + "DB_QUERY_TIMEOUT"); ok {
+		if err := cfg.Database.QueryTimeout.set(value); err != nil {
+			return fmt.Errorf("%sDB_QUERY_TIMEOUT must be a duration: %v", envPrefix, err)
+		}
+	}
+	durationVars := map[string]*Duration{
+		"AUTH_VERIFICATION_TOKEN_TTL": &cfg.Auth.VerificationTokenTTL,
+		"AUTH_RESET_TOKEN_TTL":        &cfg.Auth.ResetTokenTTL,
+		"AUTH_LOCKOUT_DURATION":       &cfg.Auth.LockoutDuration,
+		"RATING_HALF_LIFE":            &cfg.Rating.HalfLife,
+		"RATING_REFRESH_INTERVAL":     &cfg.Rating.RefreshInterval,
+	}
+	for name, target := range durationVars {
+		if value, ok := lookupEnv(envPrefix + name); ok {
+			if err := target.set(value); err != nil {
+				return fmt.Errorf("%s%s must be a duration: %v", envPrefix, name, err)
+			}
+		}
+	}
+
+	floatVars := map[string]*float64{
+		"RATING_PRIOR_MEAN":   &cfg.Rating.PriorMean,
+		"RATING_PRIOR_WEIGHT": &cfg.Rating.PriorWeight,
+	}
+	for name, target := range floatVars {
+		if value, ok := lookupEnv(envPrefix + name); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return fmt.Errorf("%s%s must be a number", envPrefix, name)
+			}
+			*target = parsed
+		}
+	}
+
+	boolVars := map[string]*bool{
+		"NOTIFICATION_ENABLED":        &cfg.Notification.Enabled,
+		"AUTH_REQUIRE_VERIFIED_EMAIL": &cfg.Auth.RequireVerifiedEmail,
+	}
+	for name, target := range boolVars {
+		if value, ok := lookupEnv(envPrefix + name); ok {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s%s must be a boolean", envPrefix, name)
+			}
+			*target = parsed
+		}
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once so startup fails with a complete message.
+func (c *Config) Validate() error {
+	var problems []string
+	if c.Database.DSN == "" {
+		problems = append(problems, "database.dsn is required")
+	}
+	if c.Database.MaxOpenConns < 1 {
+		problems = append(problems, "database.max_open_conns must be at least 1")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		problems = append(problems, "database.max_idle_conns must be between 0 and max_open_conns")
+	}
+	if c.Database.ConnMaxLifetime.Duration < 0 {
+		problems = append(problems, "database.conn_max_lifetime must not be negative")
+	}
+	if c.Database.QueryTimeout.Duration <= 0 {
+		problems = append(problems, "database.query_timeout must be positive")
+	}
+	if c.Storage.Backend != "mysql" {
+		problems = append(problems, fmt.Sprintf("storage.backend %q is not supported", c.Storage.Backend))
+	}
+	if c.Storage.BlobDir == "" {
+		problems = append(problems, "storage.blob_dir is required")
+	}
+	if c.Storage.KeyFile == "" {
+		problems = append(problems, "storage.key_file is required")
+	}
+	if c.CategoryFile == "" {
+		problems = append(problems, "category_file is required")
+	}
+	switch c.Notification.Channel {
+	case "console":
+	case "smtp":
+		if c.Notification.Enabled && (c.Notification.SMTPHost == "" || c.Notification.From == "") {
+			problems = append(problems, "notification.smtp_host and notification.from are required for the smtp channel")
+		}
+		if c.Notification.SMTPPort < 1 || c.Notification.SMTPPort > 65535 {
+			problems = append(problems, "notification.smtp_port must be a valid port")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("notification.channel %q is not supported", c.Notification.Channel))
+	}
+	if c.Auth.VerificationTokenTTL.Duration <= 0 {
+		problems = append(problems, "auth.verification_token_ttl must be positive")
+	}
+	if c.Auth.ResetTokenTTL.Duration <= 0 {
+		problems = append(problems, "auth.reset_token_ttl must be positive")
+	}
+	if c.Auth.MaxFailedLogins < 0 || c.Auth.MaxFailedLoginsPerSource < 0 {
+		problems = append(problems, "auth.max_failed_logins and auth.max_failed_logins_per_source must not be negative")
+	}
+	if c.Auth.LockoutDuration.Duration <= 0 {
+		problems = append(problems, "auth.lockout_duration must be positive")
+	}
+	if c.Rating.PriorMean < 1 || c.Rating.PriorMean > 5 {
+		problems = append(problems, "rating.prior_mean must be between 1 and 5")
+	}
+	if c.Rating.PriorWeight < 0 {
+		problems = append(problems, "rating.prior_weight must not be negative")
+	}
+	if c.Rating.HalfLife.Duration < 0 {
+		problems = append(problems, "rating.half_life must not be negative")
+	}
+	if c.Rating.RefreshInterval.Duration <= 0 {
+		problems = append(problems, "rating.refresh_interval must be positive")
+	}
+	switch c.LogLevel {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, fmt.Sprintf("log_level %q is not supported", c.LogLevel))
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid configuration: " + strings.Join(problems, "; "))
+	}
+	return nil
+}
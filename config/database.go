@@ -13,13 +13,15 @@ var (
 )
 
 // GetMySQLDB returns a singleton instance of the database connection.
-func GetMySQLDB() *sql.DB {
+func GetMySQLDB(cfg DatabaseConfig) *sql.DB {
 	once.Do(func() {
-		dsn := "root:Asdfghjkl@0987@tcp(localhost:3306)/servicenest"
-		db, err := sql.Open("mysql", dsn)
+		db, err := sql.Open("mysql", cfg.DSN)
 		if err != nil {
 			log.Fatalf("Error opening database: %v", err)
 		}
+		db.SetMaxOpenConns(cfg.MaxOpenConns)
+		db.SetMaxIdleConns(cfg.MaxIdleConns)
+		db.SetConnMaxLifetime(cfg.ConnMaxLifetime.Duration)
 
 		// Test the connection
 		err = db.Ping()
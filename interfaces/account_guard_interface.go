@@ -0,0 +1,8 @@
+package interfaces
+
+import "context"
+
+// AccountGuard refuses to act for a user whose account is suspended, banned or deleted
+type AccountGuard interface {
+	EnsureActive(ctx context.Context, userID string) error
+}
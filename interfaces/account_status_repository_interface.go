@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type AccountStatusRepository interface {
+	SaveStatusChange(ctx context.Context, change model.AccountStatusChange) error
+	GetStatusHistory(ctx context.Context, userID string) ([]model.AccountStatusChange, error)
+}
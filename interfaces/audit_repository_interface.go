@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type AuditRepository interface {
+	AppendEntry(ctx context.Context, entry *model.AuditEntry) error
+	GetEntries(ctx context.Context, filter model.AuditFilter) ([]model.AuditEntry, error)
+	GetEntriesAfter(ctx context.Context, afterSeq int64, limit int) ([]model.AuditEntry, error)
+	GetChainHead(ctx context.Context) (int64, string, error)
+}
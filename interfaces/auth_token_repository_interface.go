@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type AuthTokenRepository interface {
+	SaveToken(ctx context.Context, token model.AuthToken) error
+	GetTokenByHash(ctx context.Context, tokenHash string) (*model.AuthToken, error)
+	MarkTokenUsed(ctx context.Context, tokenID string) error
+	InvalidateTokens(ctx context.Context, userID, purpose string) error
+	DeleteTokens(ctx context.Context, userID string) error
+}
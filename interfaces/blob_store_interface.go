@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStore keeps uploaded files outside the database. Keys are slash separated relative paths.
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader) (int64, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type CategoryRepository interface {
+	SaveCategory(ctx context.Context, category model.Category) error
+	GetCategoryByID(ctx context.Context, categoryID string) (*model.Category, error)
+	GetCategoryByName(ctx context.Context, name string) (*model.Category, error)
+	GetAllCategories(ctx context.Context) ([]model.Category, error)
+	UpdateCategory(ctx context.Context, category model.Category) error
+	RemoveCategory(ctx context.Context, categoryID string) error
+}
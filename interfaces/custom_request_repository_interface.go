@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type CustomRequestRepository interface {
+	SaveCustomRequest(ctx context.Context, request model.CustomRequest) error
+	GetCustomRequestByID(ctx context.Context, requestID string) (*model.CustomRequest, error)
+	GetCustomRequestsByStatus(ctx context.Context, status string) ([]model.CustomRequest, error)
+	ResolveCustomRequest(ctx context.Context, request model.CustomRequest) error
+	GetCustomRequestsByHouseholderID(ctx context.Context, householderID string) ([]model.CustomRequest, error)
+	AnonymiseHouseholder(ctx context.Context, householderID, name string) error
+}
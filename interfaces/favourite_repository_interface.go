@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type FavouriteRepository interface {
+	AddFavourite(ctx context.Context, favourite model.FavouriteProvider) error
+	RemoveFavourite(ctx context.Context, householderID, providerID string) error
+	GetFavourites(ctx context.Context, householderID string) ([]model.FavouriteProvider, error)
+	DeleteFavouritesByUserID(ctx context.Context, userID string) error
+}
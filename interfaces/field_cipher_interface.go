@@ -0,0 +1,16 @@
+package interfaces
+
+import "context"
+
+// FieldCipher encrypts single column values. Values that were stored before encryption was turned on are
+// returned by Decrypt as they are.
+type FieldCipher interface {
+	Encrypt(ctx context.Context, plaintext string) (string, error)
+	Decrypt(ctx context.Context, stored string) (string, error)
+	// Rewrap returns stored encrypted under the current key, and whether that changed it
+	Rewrap(ctx context.Context, stored string) (string, bool, error)
+	// KeyID returns the key a stored value was encrypted with, empty when it is not encrypted
+	KeyID(stored string) string
+	// BlindIndex returns a keyed hash of value that can be searched for without decrypting anything
+	BlindIndex(ctx context.Context, value string) (string, error)
+}
@@ -0,0 +1,10 @@
+package interfaces
+
+import "context"
+
+type FieldEncryptionRepository interface {
+	// Reencrypt moves every encrypted column to the current key and returns the number of rows changed
+	Reencrypt(ctx context.Context) (int, error)
+	// KeyUsage counts the stored values per key ID, values that are not encrypted yet are counted under ""
+	KeyUsage(ctx context.Context) (map[string]int, error)
+}
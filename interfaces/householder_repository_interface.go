@@ -1,8 +1,11 @@
 package interfaces
 
-import "serviceNest/model"
+import (
+	"context"
+	"serviceNest/model"
+)
 
 type HouseholderRepository interface {
-	SaveHouseholder(householder *model.Householder) error
-	GetHouseholderByID(id string) (*model.Householder, error)
+	SaveHouseholder(ctx context.Context, householder *model.Householder) error
+	GetHouseholderByID(ctx context.Context, id string) (*model.Householder, error)
 }
@@ -0,0 +1,12 @@
+package interfaces
+
+import "context"
+
+// KeyProvider holds the key-encryption keys that protect personal data at rest. Every key has an ID so
+// that values written under an older key can still be read after a rotation.
+type KeyProvider interface {
+	CurrentKey(ctx context.Context) (keyID string, key []byte, err error)
+	Key(ctx context.Context, keyID string) ([]byte, error)
+	// IndexKey is used for blind indexes, it does not change when the encryption keys are rotated
+	IndexKey(ctx context.Context) ([]byte, error)
+}
@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+	"time"
+)
+
+type LoginAttemptRepository interface {
+	SaveAuditEntry(ctx context.Context, entry model.LoginAuditEntry) error
+	GetAuditEntries(ctx context.Context, email string, limit int) ([]model.LoginAuditEntry, error)
+	GetThrottle(ctx context.Context, scope, key string) (*model.LoginThrottle, error)
+	IncrementFailures(ctx context.Context, scope, key string, now, windowStart time.Time) (int, error)
+	LockThrottle(ctx context.Context, scope, key string, until time.Time) error
+	ResetThrottle(ctx context.Context, scope, key string) error
+	GetLockedThrottles(ctx context.Context, scope string, now time.Time) ([]model.LoginThrottle, error)
+	AnonymiseAuditEntries(ctx context.Context, email, erasedEmail string) error
+}
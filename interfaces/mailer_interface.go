@@ -0,0 +1,8 @@
+package interfaces
+
+import "context"
+
+// Mailer delivers email to a single recipient
+type Mailer interface {
+	Send(ctx context.Context, to, subject, body string) error
+}
@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type NotificationRepository interface {
+	SaveNotification(ctx context.Context, notification model.Notification) error
+	GetNotificationsByUserID(ctx context.Context, userID string, unreadOnly bool) ([]model.Notification, error)
+	MarkNotificationsRead(ctx context.Context, userID string) error
+	DeleteNotifications(ctx context.Context, userID string) error
+}
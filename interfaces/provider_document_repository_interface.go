@@ -0,0 +1,13 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type ProviderDocumentRepository interface {
+	SaveDocument(ctx context.Context, document model.ProviderDocument) error
+	GetDocumentByID(ctx context.Context, documentID string) (*model.ProviderDocument, error)
+	GetDocumentsByProviderID(ctx context.Context, providerID string) ([]model.ProviderDocument, error)
+	DeleteDocumentsByProviderID(ctx context.Context, providerID string) error
+}
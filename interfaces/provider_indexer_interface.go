@@ -0,0 +1,8 @@
+package interfaces
+
+import "context"
+
+// ProviderIndexer refreshes the searchable services of a provider after the provider's standing changed
+type ProviderIndexer interface {
+	ReindexProvider(ctx context.Context, providerID string) error
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type ProviderProfileRepository interface {
+	GetProfileDetails(ctx context.Context, providerID string) (*model.ProviderProfileDetails, error)
+	SaveProfileDetails(ctx context.Context, details model.ProviderProfileDetails) error
+	SaveCertification(ctx context.Context, certification model.Certification) error
+	GetCertificationByID(ctx context.Context, certificationID string) (*model.Certification, error)
+	DeleteCertification(ctx context.Context, providerID, certificationID string) error
+	SavePortfolioImage(ctx context.Context, image model.PortfolioImage) error
+	GetPortfolioImageByID(ctx context.Context, imageID string) (*model.PortfolioImage, error)
+	GetPortfolioImages(ctx context.Context, providerID string) ([]model.PortfolioImage, error)
+	DeletePortfolioImage(ctx context.Context, providerID, imageID string) error
+	DeleteProfile(ctx context.Context, providerID string) error
+}
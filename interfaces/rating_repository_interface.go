@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type RatingRepository interface {
+	LockProviderRatings(ctx context.Context, providerID string) ([]model.RatingAggregate, error)
+	GetProviderRatings(ctx context.Context, providerID string) ([]model.RatingAggregate, error)
+	SaveRatingAggregates(ctx context.Context, aggregates []model.RatingAggregate) error
+	ReplaceProviderRatings(ctx context.Context, providerID string, aggregates []model.RatingAggregate) error
+	RenameCategory(ctx context.Context, oldName, newName string) error
+	SetProviderRating(ctx context.Context, providerID string, rating float64) error
+	GetRatedProviderIDs(ctx context.Context) ([]string, error)
+}
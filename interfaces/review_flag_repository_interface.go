@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type ReviewFlagRepository interface {
+	SaveFlag(ctx context.Context, flag model.ReviewFlag) error
+	GetOpenFlags(ctx context.Context) ([]model.ReviewFlag, error)
+	ResolveFlags(ctx context.Context, reviewID string) error
+}
@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type ReviewPhotoRepository interface {
+	SavePhoto(ctx context.Context, photo model.ReviewPhoto) error
+	GetPhotoByID(ctx context.Context, photoID string) (*model.ReviewPhoto, error)
+	GetPhotosByReviewID(ctx context.Context, reviewID string) ([]model.ReviewPhoto, error)
+	GetPhotosByProviderID(ctx context.Context, providerID string) ([]model.ReviewPhoto, error)
+	GetPhotosByHouseholderID(ctx context.Context, householderID string) ([]model.ReviewPhoto, error)
+	DeletePhoto(ctx context.Context, photoID string) error
+	DeletePhotosByHouseholderID(ctx context.Context, householderID string) error
+}
@@ -0,0 +1,11 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+)
+
+type RoleHistoryRepository interface {
+	SaveRoleChange(ctx context.Context, change model.RoleChange) error
+	GetRoleHistory(ctx context.Context, userID string) ([]model.RoleChange, error)
+}
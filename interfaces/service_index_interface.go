@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"serviceNest/model"
+)
+
+type ServiceIndex interface {
+	Index(doc model.SearchDocument)
+	Remove(serviceID string)
+	Search(query model.SearchQuery) []model.SearchResult
+	Len() int
+}
@@ -1,18 +1,31 @@
 package interfaces
 
-import "serviceNest/model"
+import (
+	"context"
+	"serviceNest/model"
+	"time"
+)
 
 type ServiceProviderRepository interface {
-	UpdateServiceProvider(provider *model.ServiceProvider) error
-	GetProviderByServiceID(serviceID string) (*model.ServiceProvider, error)
-	GetProvidersByServiceType(serviceType string) ([]model.ServiceProvider, error)
-	GetProviderByID(providerID string) (*model.ServiceProvider, error)
-	SaveServiceProvider(provider model.ServiceProvider) error
-	GetProviderDetailByID(providerID string) (*model.ServiceProviderDetails, error)
-	SaveServiceProviderDetail(provider *model.ServiceProviderDetails, requestID string) error
-	UpdateServiceProviderDetailByRequestID(provider *model.ServiceProviderDetails, requestID string) error
-	IsProviderApproved(providerID string) (bool, error)
-	AddReview(review model.Review) error
-	UpdateProviderRating(providerID string) error
-	GetReviewsByProviderID(providerID string) ([]model.Review, error)
+	UpdateServiceProvider(ctx context.Context, provider *model.ServiceProvider) error
+	GetProviderByServiceID(ctx context.Context, serviceID string) (*model.ServiceProvider, error)
+	GetProvidersByServiceType(ctx context.Context, serviceType string) ([]model.ServiceProvider, error)
+	GetProviderByID(ctx context.Context, providerID string) (*model.ServiceProvider, error)
+	SaveServiceProvider(ctx context.Context, provider model.ServiceProvider) error
+	GetProviderDetailByID(ctx context.Context, providerID string) (*model.ServiceProviderDetails, error)
+	SaveServiceProviderDetail(ctx context.Context, provider *model.ServiceProviderDetails, requestID string) error
+	UpdateServiceProviderDetailByRequestID(ctx context.Context, provider *model.ServiceProviderDetails, requestID string) error
+	IsProviderApproved(ctx context.Context, providerID string) (bool, error)
+	AddReview(ctx context.Context, review model.Review) error
+	GetReviewByID(ctx context.Context, reviewID string) (*model.Review, error)
+	LockReviewByID(ctx context.Context, reviewID string) (*model.Review, error)
+	UpdateReview(ctx context.Context, review model.Review) error
+	SaveReviewReply(ctx context.Context, reviewID, providerID, reply string, replyDate time.Time) error
+	UpdateReviewModeration(ctx context.Context, review model.Review) error
+	GetHiddenReviews(ctx context.Context) ([]model.Review, error)
+	GetReviewsByProviderID(ctx context.Context, providerID string) ([]model.Review, error)
+	GetProvidersByVerificationStatus(ctx context.Context, status string) ([]model.ServiceProvider, error)
+	UpdateVerificationStatus(ctx context.Context, providerID, status, note, reviewedBy string) error
+	GetReviewsByHouseholderID(ctx context.Context, householderID string) ([]model.Review, error)
+	ClearReviewComments(ctx context.Context, userID string) error
 }
@@ -1,15 +1,19 @@
 package interfaces
 
-import "serviceNest/model"
+import (
+	"context"
+	"serviceNest/model"
+)
 
 type ServiceRepository interface {
-	RemoveService(serviceID string) error
-	SaveAllServices(services []model.Service) error
-	SaveService(service model.Service) error
-	GetAllServices() ([]model.Service, error)
-	GetServiceByID(serviceID string) (*model.Service, error)
-	GetServiceByName(serviceName string) (*model.Service, error)
-	GetServiceByProviderID(providerID string) ([]model.Service, error)
-	UpdateService(providerID string, updatedService model.Service) error
-	RemoveServiceByProviderID(providerID string, serviceID string) error
+	RemoveService(ctx context.Context, serviceID string) error
+	SaveAllServices(ctx context.Context, services []model.Service) error
+	SaveService(ctx context.Context, service model.Service) error
+	GetAllServices(ctx context.Context, opts model.QueryOptions) ([]model.Service, error)
+	GetServiceByID(ctx context.Context, serviceID string) (*model.Service, error)
+	GetServiceByName(ctx context.Context, serviceName string) (*model.Service, error)
+	GetServiceByProviderID(ctx context.Context, providerID string) ([]model.Service, error)
+	UpdateService(ctx context.Context, providerID string, updatedService model.Service) error
+	RemoveServiceByProviderID(ctx context.Context, providerID string, serviceID string) error
+	RenameCategory(ctx context.Context, oldName, newName string) error
 }
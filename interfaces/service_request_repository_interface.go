@@ -1,14 +1,23 @@
 package interfaces
 
-import "serviceNest/model"
+import (
+	"context"
+	"serviceNest/model"
+)
 
 type ServiceRequestRepository interface {
 	//SaveAllServiceRequests(serviceRequests []model.ServiceRequest) error
-	GetAllServiceRequests() ([]model.ServiceRequest, error)
-	UpdateServiceRequest(updatedRequest *model.ServiceRequest) error
-	GetServiceRequestsByHouseholderID(householderID string) ([]model.ServiceRequest, error)
-	GetServiceRequestByID(requestID string) (*model.ServiceRequest, error)
-	SaveServiceRequest(request model.ServiceRequest) error
-	GetServiceRequestsByProviderID(providerID string) ([]model.ServiceRequest, error)
-	GetServiceProviderByRequestID(requestID, providerID string) (*model.ServiceRequest, error)
+	GetAllServiceRequests(ctx context.Context, opts model.QueryOptions) ([]model.ServiceRequest, error)
+	UpdateServiceRequest(ctx context.Context, updatedRequest *model.ServiceRequest) error
+	GetServiceRequestsByHouseholderID(ctx context.Context, householderID string, opts model.QueryOptions) ([]model.ServiceRequest, error)
+	GetServiceRequestByID(ctx context.Context, requestID string) (*model.ServiceRequest, error)
+	SaveServiceRequest(ctx context.Context, request model.ServiceRequest) error
+	GetServiceRequestsByProviderID(ctx context.Context, providerID string) ([]model.ServiceRequest, error)
+	GetServiceProviderByRequestID(ctx context.Context, requestID, providerID string) (*model.ServiceRequest, error)
+	GetApprovedProvider(ctx context.Context, requestID string) (*model.ServiceProviderDetails, error)
+	CountCompletedJobs(ctx context.Context, providerID string) (int, error)
+	OpenToAllProviders(ctx context.Context, requestID, providerID string) error
+	ReleaseDirectBookings(ctx context.Context, providerID string) ([]model.ServiceRequest, error)
+	AnonymiseHouseholder(ctx context.Context, householderID, name string) error
+	AnonymiseProviderDetails(ctx context.Context, providerID, name string) error
 }
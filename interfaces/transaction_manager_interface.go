@@ -0,0 +1,10 @@
+package interfaces
+
+import "context"
+
+type TransactionManager interface {
+	// WithinTransaction runs fn in a single database transaction. Repository calls made with
+	// the ctx handed to fn take part in that transaction, which is committed when fn returns
+	// nil and rolled back otherwise.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
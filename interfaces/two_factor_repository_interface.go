@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+	"serviceNest/model"
+	"time"
+)
+
+type TwoFactorRepository interface {
+	SaveSecret(ctx context.Context, twoFactor model.TwoFactor) error
+	GetTwoFactor(ctx context.Context, userID string) (*model.TwoFactor, error)
+	EnableTwoFactor(ctx context.Context, userID string, step int64, confirmedAt time.Time) error
+	UseStep(ctx context.Context, userID string, step int64) error
+	DeleteTwoFactor(ctx context.Context, userID string) error
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codes []model.RecoveryCode) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string) error
+	CountUnusedRecoveryCodes(ctx context.Context, userID string) (int, error)
+}
@@ -1,12 +1,21 @@
 package interfaces
 
 import (
+	"context"
 	"serviceNest/model"
+	"time"
 )
 
 type UserRepository interface {
-	SaveUser(user *model.User) error
-	GetUserByID(userID string) (*model.User, error)
-	UpdateUser(updatedUser *model.User) error
-	GetUserByEmail(email string) (*model.User, error)
+	SaveUser(ctx context.Context, user *model.User) error
+	GetUserByID(ctx context.Context, userID string) (*model.User, error)
+	UpdateUser(ctx context.Context, updatedUser *model.User) error
+	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
+	MarkEmailVerified(ctx context.Context, userID string) error
+	UpdatePassword(ctx context.Context, userID, passwordHash string) error
+	UpdateAccountStatus(ctx context.Context, userID, fromStatus, status, reason string, expiresAt *time.Time) error
+	UpdateRole(ctx context.Context, userID, fromRole, role string) error
+	GetUsersByRole(ctx context.Context, role string) ([]model.User, error)
+	AnonymiseUser(ctx context.Context, userID, name, email string) error
+	GetUsersByContact(ctx context.Context, contact string) ([]model.User, error)
 }
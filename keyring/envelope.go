@@ -0,0 +1,200 @@
+package keyring
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"strings"
+)
+
+// prefix marks an encrypted value. A stored value reads prefix + keyID + ":" + wrapped data key + ":" +
+// sealed value, both base64 encoded.
+const prefix = "enc1:"
+
+// blindIndexSize is how many bytes of the HMAC are kept. A shorter index lets unrelated values collide now
+// and then, which lookups filter out after decrypting and which makes the index less useful for guessing.
+const blindIndexSize = 16
+
+var encoding = base64.RawStdEncoding
+
+// ErrMalformed is returned for a value that carries the encryption prefix but cannot be taken apart
+var ErrMalformed = errors.New("encrypted value is malformed")
+
+// Envelope encrypts column values with AES-256-GCM under a fresh data key per value
+type Envelope struct {
+	keys interfaces.KeyProvider
+}
+
+// NewEnvelope creates an Envelope whose data keys are wrapped with the keys of provider
+func NewEnvelope(provider interfaces.KeyProvider) *Envelope {
+	return &Envelope{keys: provider}
+}
+
+// Encrypt seals plaintext under a new data key wrapped with the current key. Empty values stay empty so
+// that "not set" can still be told apart without decrypting.
+func (e *Envelope) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	keyID, kek, err := e.keys.CurrentKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := newKey()
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := seal(kek, dataKey, []byte(keyID))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataKey, []byte(plaintext), nil)
+	if err != nil {
+		return "", err
+	}
+	return prefix + keyID + ":" + encoding.EncodeToString(wrapped) + ":" + encoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value written by Encrypt. Values without the prefix were stored before encryption was
+// turned on and are returned unchanged.
+func (e *Envelope) Decrypt(ctx context.Context, stored string) (string, error) {
+	if !strings.HasPrefix(stored, prefix) {
+		return stored, nil
+	}
+	keyID, wrapped, sealed, err := split(stored)
+	if err != nil {
+		return "", err
+	}
+	dataKey, err := e.unwrap(ctx, keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dataKey, sealed, nil)
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt value: %v", err)
+	}
+	return string(plaintext), nil
+}
+
+// Rewrap moves a value to the current key. Only the data key is encrypted again; a value that was never
+// encrypted is encrypted now.
+func (e *Envelope) Rewrap(ctx context.Context, stored string) (string, bool, error) {
+	if !strings.HasPrefix(stored, prefix) {
+		if stored == "" {
+			return "", false, nil
+		}
+		encrypted, err := e.Encrypt(ctx, stored)
+		return encrypted, true, err
+	}
+	keyID, wrapped, sealed, err := split(stored)
+	if err != nil {
+		return "", false, err
+	}
+	currentID, kek, err := e.keys.CurrentKey(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	if keyID == currentID {
+		return stored, false, nil
+	}
+	dataKey, err := e.unwrap(ctx, keyID, wrapped)
+	if err != nil {
+		return "", false, err
+	}
+	rewrapped, err := seal(kek, dataKey, []byte(currentID))
+	if err != nil {
+		return "", false, err
+	}
+	return prefix + currentID + ":" + encoding.EncodeToString(rewrapped) + ":" + encoding.EncodeToString(sealed), true, nil
+}
+
+// KeyID returns the ID of the key a value was encrypted with, empty for a value that is not encrypted
+func (e *Envelope) KeyID(stored string) string {
+	if !strings.HasPrefix(stored, prefix) {
+		return ""
+	}
+	keyID, _, _, err := split(stored)
+	if err != nil {
+		return ""
+	}
+	return keyID
+}
+
+// BlindIndex returns a truncated HMAC-SHA256 of value under the index key, hex encoded
+func (e *Envelope) BlindIndex(ctx context.Context, value string) (string, error) {
+	key, err := e.keys.IndexKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil)[:blindIndexSize]), nil
+}
+
+func (e *Envelope) unwrap(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	kek, err := e.keys.Key(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	// The key ID is authenticated with the data key, a value cannot be passed off as belonging to another key
+	dataKey, err := open(kek, wrapped, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("could not unwrap data key: %v", err)
+	}
+	return dataKey, nil
+}
+
+func split(stored string) (keyID string, wrapped, sealed []byte, err error) {
+	parts := strings.Split(strings.TrimPrefix(stored, prefix), ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", nil, nil, ErrMalformed
+	}
+	if wrapped, err = encoding.DecodeString(parts[1]); err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	if sealed, err = encoding.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, ErrMalformed
+	}
+	return parts[0], wrapped, sealed, nil
+}
+
+// seal encrypts plaintext with AES-GCM under key and returns the nonce followed by the ciphertext
+func seal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func open(key, sealed, additionalData []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	return gcm.Open(nil, nonce, ciphertext, additionalData)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,238 @@
+// Package keyring encrypts personal data at rest. Each value is sealed with its own data key, which is in
+// turn encrypted (wrapped) with a key-encryption key from a KeyProvider. Rotating the key-encryption key
+// only means re-wrapping the small data keys, the values themselves are not encrypted again.
+package keyring
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// KeySize is the size of every key in bytes, selecting AES-256
+const KeySize = 32
+
+// keyFileData is the JSON layout of a key file
+type keyFileData struct {
+	Current  string            `json:"current"`
+	Keys     map[string]string `json:"keys"` // base64 encoded key-encryption keys by ID
+	IndexKey string            `json:"index_key"`
+}
+
+// LocalKeyFile keeps the keys in a JSON file readable only by its owner. It suits a single server; a
+// deployment with a key management service implements interfaces.KeyProvider against that instead.
+type LocalKeyFile struct {
+	path string
+
+	mu       sync.RWMutex
+	current  string
+	keys     map[string][]byte
+	indexKey []byte
+}
+
+// CreateLocalKeyFile writes a new key file with a fresh key to path. It refuses to replace an existing
+// file, whose keys may be the only way to read the stored data.
+func CreateLocalKeyFile(path string) (*LocalKeyFile, error) {
+	if _, err := os.Stat(path); err == nil {
+		return nil, fmt.Errorf("key file %s already exists", path)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("could not read key file: %v", err)
+	}
+	file := &LocalKeyFile{path: path, keys: map[string][]byte{}}
+	var err error
+	if file.indexKey, err = newKey(); err != nil {
+		return nil, err
+	}
+	if _, err := file.addKey(); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// OpenLocalKeyFile loads the keys from path. A missing file is not created here: errors.Is(err,
+// fs.ErrNotExist) holds and the caller decides whether a new one may be made with CreateLocalKeyFile.
+func OpenLocalKeyFile(path string) (*LocalKeyFile, error) {
+	file := &LocalKeyFile{path: path, keys: map[string][]byte{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("key file %s does not exist: %w", path, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read key file: %v", err)
+	}
+
+	var stored keyFileData
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, fmt.Errorf("key file %s is damaged: %v", path, err)
+	}
+	for id, encoded := range stored.Keys {
+		key, err := decodeKey(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key %s in %s: %v", id, path, err)
+		}
+		file.keys[id] = key
+	}
+	if file.indexKey, err = decodeKey(stored.IndexKey); err != nil {
+		return nil, fmt.Errorf("index key in %s: %v", path, err)
+	}
+	if _, ok := file.keys[stored.Current]; !ok {
+		return nil, fmt.Errorf("key file %s names %q as current key but does not contain it", path, stored.Current)
+	}
+	file.current = stored.Current
+	return file, nil
+}
+
+// CurrentKey returns the key new values are encrypted with
+func (f *LocalKeyFile) CurrentKey(ctx context.Context) (string, []byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.current, f.keys[f.current], nil
+}
+
+// Key returns the key with the given ID
+func (f *LocalKeyFile) Key(ctx context.Context, keyID string) ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	key, ok := f.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("encryption key %q is not in the key file", keyID)
+	}
+	return key, nil
+}
+
+// IndexKey returns the key blind indexes are computed with
+func (f *LocalKeyFile) IndexKey(ctx context.Context) ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	return f.indexKey, nil
+}
+
+// KeyIDs lists the IDs of all keys in the file, oldest first
+func (f *LocalKeyFile) KeyIDs() []string {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	ids := make([]string, 0, len(f.keys))
+	for id := range f.keys {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return keyNumber(ids[i]) < keyNumber(ids[j]) })
+	return ids
+}
+
+// Rotate adds a new key and makes it the current one. Older keys stay in the file until they are retired.
+func (f *LocalKeyFile) Rotate() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.addKey()
+}
+
+// Retire removes keys that no stored value uses any more. The current key cannot be retired.
+func (f *LocalKeyFile) Retire(keyIDs ...string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, id := range keyIDs {
+		if id == f.current {
+			return fmt.Errorf("key %s is the current key and cannot be retired", id)
+		}
+		if _, ok := f.keys[id]; !ok {
+			return fmt.Errorf("key %s is not in the key file", id)
+		}
+	}
+	for _, id := range keyIDs {
+		delete(f.keys, id)
+	}
+	return f.save()
+}
+
+// addKey generates the next key, makes it current and saves the file. The caller holds the lock.
+func (f *LocalKeyFile) addKey() (string, error) {
+	key, err := newKey()
+	if err != nil {
+		return "", err
+	}
+	next := 1
+	for id := range f.keys {
+		if n := keyNumber(id); n >= next {
+			next = n + 1
+		}
+	}
+	id := "k" + strconv.Itoa(next)
+	previous := f.current
+	f.keys[id], f.current = key, id
+	if err := f.save(); err != nil {
+		delete(f.keys, id)
+		f.current = previous
+		return "", err
+	}
+	return id, nil
+}
+
+// save writes the file next to its final location and renames it, so that a crash never leaves a
+// half-written key file behind
+func (f *LocalKeyFile) save() error {
+	stored := keyFileData{Current: f.current, Keys: map[string]string{}, IndexKey: base64.StdEncoding.EncodeToString(f.indexKey)}
+	for id, key := range f.keys {
+		stored.Keys[id] = base64.StdEncoding.EncodeToString(key)
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.path), 0o700); err != nil {
+		return fmt.Errorf("could not create key directory: %v", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), ".keys-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
+
+func newKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func decodeKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes, got %d", KeySize, len(key))
+	}
+	return key, nil
+}
+
+// keyNumber returns n for a key ID "kn", 0 for IDs not generated by this file
+func keyNumber(id string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(id, "k"))
+	if err != nil || !strings.HasPrefix(id, "k") {
+		return 0
+	}
+	return n
+}
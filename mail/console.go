@@ -0,0 +1,29 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// ConsoleMailer prints messages instead of sending them
+type ConsoleMailer struct {
+	mu  sync.Mutex
+	out io.Writer
+}
+
+// NewConsoleMailer creates a mailer that writes every message to out
+func NewConsoleMailer(out io.Writer) *ConsoleMailer {
+	return &ConsoleMailer{out: out}
+}
+
+func (m *ConsoleMailer) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, err := fmt.Fprintf(m.out, "----- email to %s -----\nSubject: %s\n\n%s\n-----\n", to, subject, body)
+	return err
+}
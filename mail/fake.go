@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"sync"
+)
+
+// Message is an email captured by FakeMailer
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// FakeMailer records messages instead of delivering them, for tests
+type FakeMailer struct {
+	mu       sync.Mutex
+	messages []Message
+	Err      error // returned by Send when set
+}
+
+func (m *FakeMailer) Send(ctx context.Context, to, subject, body string) error {
+	if m.Err != nil {
+		return m.Err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, Message{To: to, Subject: subject, Body: body})
+	return nil
+}
+
+// Messages returns the messages sent so far
+func (m *FakeMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.messages...)
+}
@@ -0,0 +1,17 @@
+package mail
+
+import (
+	"fmt"
+	"io"
+	"serviceNest/config"
+	"serviceNest/interfaces"
+)
+
+// New returns the mailer for the configured notification channel. Messages go to out unless
+// notifications are enabled on the smtp channel, so that codes can still be redeemed during development.
+func New(cfg config.NotificationConfig, out io.Writer) interfaces.Mailer {
+	if cfg.Enabled && cfg.Channel == "smtp" {
+		return NewSMTPMailer(fmt.Sprintf("%s:%d", cfg.SMTPHost, cfg.SMTPPort), cfg.From)
+	}
+	return NewConsoleMailer(out)
+}
@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"strings"
+)
+
+// SMTPMailer relays messages through an SMTP server that accepts mail from this host without authentication
+type SMTPMailer struct {
+	addr string
+	from string
+}
+
+// NewSMTPMailer creates a mailer that sends through the server at addr ("host:port")
+func NewSMTPMailer(addr, from string) *SMTPMailer {
+	return &SMTPMailer{addr: addr, from: from}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, to, subject, body string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if strings.ContainsAny(to, "\r\n") || strings.ContainsAny(subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+	message := "From: " + m.from + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + subject + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: text/plain; charset=UTF-8\r\n" +
+		"\r\n" +
+		strings.ReplaceAll(body, "\n", "\r\n")
+	if err := smtp.SendMail(m.addr, nil, m.from, []string{to}, []byte(message)); err != nil {
+		return fmt.Errorf("could not send email: %v", err)
+	}
+	return nil
+}
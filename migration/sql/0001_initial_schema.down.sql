@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS service_provider_details;
+DROP TABLE IF EXISTS service_requests;
+DROP TABLE IF EXISTS service_providers_services;
+DROP TABLE IF EXISTS services;
+DROP TABLE IF EXISTS service_providers;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,76 @@
+CREATE TABLE IF NOT EXISTS users (
+    id        VARCHAR(64)  NOT NULL PRIMARY KEY,
+    name      VARCHAR(255) NOT NULL,
+    email     VARCHAR(255) NOT NULL UNIQUE,
+    password  VARCHAR(255) NOT NULL,
+    role      VARCHAR(32)  NOT NULL,
+    address   VARCHAR(512) NOT NULL DEFAULT '',
+    contact   VARCHAR(32)  NOT NULL DEFAULT '',
+    latitude  DOUBLE       NOT NULL DEFAULT 0,
+    longitude DOUBLE       NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS service_providers (
+    user_id      VARCHAR(64) NOT NULL PRIMARY KEY,
+    rating       DOUBLE      NOT NULL DEFAULT 0,
+    availability BOOLEAN     NOT NULL DEFAULT TRUE,
+    is_active    BOOLEAN     NOT NULL DEFAULT TRUE,
+    CONSTRAINT fk_service_providers_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS services (
+    id          VARCHAR(64)  NOT NULL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT         NOT NULL,
+    price       DOUBLE       NOT NULL DEFAULT 0,
+    provider_id VARCHAR(64)  NULL,
+    category    VARCHAR(255) NOT NULL,
+    INDEX idx_services_name (name),
+    INDEX idx_services_category (category),
+    INDEX idx_services_provider (provider_id)
+);
+
+CREATE TABLE IF NOT EXISTS service_providers_services (
+    service_provider_id VARCHAR(64) NOT NULL,
+    service_id          VARCHAR(64) NOT NULL,
+    PRIMARY KEY (service_provider_id, service_id)
+);
+
+CREATE TABLE IF NOT EXISTS service_requests (
+    id                  VARCHAR(64)  NOT NULL PRIMARY KEY,
+    householder_id      VARCHAR(64)  NULL,
+    householder_name    VARCHAR(255) NOT NULL DEFAULT '',
+    householder_address VARCHAR(512) NULL,
+    service_id          VARCHAR(64)  NOT NULL,
+    requested_time      DATETIME     NOT NULL,
+    scheduled_time      DATETIME     NOT NULL,
+    status              VARCHAR(32)  NOT NULL,
+    approve_status      BOOLEAN      NOT NULL DEFAULT FALSE,
+    INDEX idx_service_requests_householder (householder_id),
+    INDEX idx_service_requests_service (service_id)
+);
+
+CREATE TABLE IF NOT EXISTS service_provider_details (
+    id                  VARCHAR(64)  NOT NULL PRIMARY KEY,
+    service_request_id  VARCHAR(64)  NOT NULL,
+    service_provider_id VARCHAR(64)  NOT NULL,
+    name                VARCHAR(255) NOT NULL DEFAULT '',
+    contact             VARCHAR(32)  NOT NULL DEFAULT '',
+    address             VARCHAR(512) NOT NULL DEFAULT '',
+    price               VARCHAR(32)  NOT NULL DEFAULT '',
+    rating              DOUBLE       NOT NULL DEFAULT 0,
+    approve             BOOLEAN      NOT NULL DEFAULT FALSE,
+    INDEX idx_spd_request (service_request_id),
+    INDEX idx_spd_provider (service_provider_id)
+);
+
+CREATE TABLE IF NOT EXISTS reviews (
+    id             VARCHAR(64) NOT NULL PRIMARY KEY,
+    provider_id    VARCHAR(64) NOT NULL,
+    service_id     VARCHAR(64) NOT NULL,
+    householder_id VARCHAR(64) NOT NULL,
+    rating         DOUBLE      NOT NULL,
+    comments       TEXT        NOT NULL,
+    review_date    DATETIME    NOT NULL,
+    INDEX idx_reviews_provider (provider_id)
+);
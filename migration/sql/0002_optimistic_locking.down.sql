@@ -0,0 +1,2 @@
+ALTER TABLE service_provider_details DROP COLUMN version;
+ALTER TABLE service_requests DROP COLUMN version;
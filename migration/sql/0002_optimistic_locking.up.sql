@@ -0,0 +1,2 @@
+ALTER TABLE service_requests ADD COLUMN version INT NOT NULL DEFAULT 1;
+ALTER TABLE service_provider_details ADD COLUMN version INT NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+DROP INDEX idx_service_requests_status ON service_requests;
+DROP INDEX idx_service_requests_requested_time ON service_requests;
@@ -0,0 +1,2 @@
+CREATE INDEX idx_service_requests_requested_time ON service_requests (requested_time);
+CREATE INDEX idx_service_requests_status ON service_requests (status);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS categories (
+    id          VARCHAR(64)  NOT NULL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT         NOT NULL,
+    parent_id   VARCHAR(64)  NULL,
+    UNIQUE INDEX idx_categories_name (name),
+    INDEX idx_categories_parent (parent_id),
+    CONSTRAINT fk_categories_parent FOREIGN KEY (parent_id) REFERENCES categories (id)
+);
@@ -0,0 +1,3 @@
+-- Placeholder services removed by the up migration are not recreated
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS custom_requests;
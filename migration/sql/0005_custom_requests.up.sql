@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS custom_requests (
+    id                  VARCHAR(64)  NOT NULL PRIMARY KEY,
+    householder_id      VARCHAR(64)  NOT NULL,
+    householder_name    VARCHAR(255) NOT NULL DEFAULT '',
+    householder_address VARCHAR(512) NOT NULL DEFAULT '',
+    service_name        VARCHAR(255) NOT NULL,
+    requested_time      DATETIME     NOT NULL,
+    scheduled_time      DATETIME     NOT NULL,
+    status              VARCHAR(32)  NOT NULL,
+    category_id         VARCHAR(64)  NULL,
+    resolution_note     TEXT         NULL,
+    resolved_by         VARCHAR(64)  NULL,
+    resolved_at         DATETIME     NULL,
+    INDEX idx_custom_requests_status (status, requested_time),
+    INDEX idx_custom_requests_householder (householder_id)
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id         VARCHAR(64) NOT NULL PRIMARY KEY,
+    user_id    VARCHAR(64) NOT NULL,
+    message    TEXT        NOT NULL,
+    created_at DATETIME    NOT NULL,
+    is_read    BOOLEAN     NOT NULL DEFAULT FALSE,
+    INDEX idx_notifications_user (user_id, created_at)
+);
+
+-- Requests for unknown services used to create placeholder "Custom" services without a provider.
+-- Move those requests into the triage queue and drop the placeholders. Only pending requests are
+-- still waiting on a decision; anything already cancelled or closed is carried over as rejected.
+-- Requests whose householder account no longer exists have nobody to triage for and are dropped.
+INSERT INTO custom_requests (id, householder_id, householder_name, householder_address, service_name, requested_time, scheduled_time, status, resolution_note, resolved_at)
+SELECT sr.id, sr.householder_id, sr.householder_name, COALESCE(sr.householder_address, ''), s.name, sr.requested_time, sr.scheduled_time,
+       CASE WHEN sr.status = 'Pending' THEN 'Open' ELSE 'Rejected' END,
+       CASE WHEN sr.status = 'Pending' THEN NULL ELSE CONCAT('Closed before triage existed (was ', sr.status, ')') END,
+       CASE WHEN sr.status = 'Pending' THEN NULL ELSE UTC_TIMESTAMP() END
+FROM service_requests AS sr
+INNER JOIN services AS s ON s.id = sr.service_id
+WHERE s.category = 'Custom' AND s.provider_id IS NULL AND sr.householder_id IS NOT NULL;
+
+DELETE spd FROM service_provider_details AS spd
+INNER JOIN service_requests AS sr ON sr.id = spd.service_request_id
+INNER JOIN services AS s ON s.id = sr.service_id
+WHERE s.category = 'Custom' AND s.provider_id IS NULL;
+
+DELETE sr FROM service_requests AS sr
+INNER JOIN services AS s ON s.id = sr.service_id
+WHERE s.category = 'Custom' AND s.provider_id IS NULL;
+
+DELETE FROM services WHERE category = 'Custom' AND provider_id IS NULL;
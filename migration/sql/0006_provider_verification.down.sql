@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS provider_documents;
+
+ALTER TABLE service_providers
+    DROP INDEX idx_service_providers_verification,
+    DROP COLUMN reviewed_at,
+    DROP COLUMN reviewed_by,
+    DROP COLUMN verification_note,
+    DROP COLUMN verification_status;
@@ -0,0 +1,21 @@
+ALTER TABLE service_providers
+    ADD COLUMN verification_status VARCHAR(32) NOT NULL DEFAULT 'PendingVerification',
+    ADD COLUMN verification_note   TEXT        NULL,
+    ADD COLUMN reviewed_by         VARCHAR(64) NULL,
+    ADD COLUMN reviewed_at         DATETIME    NULL,
+    ADD INDEX idx_service_providers_verification (verification_status);
+
+-- Providers that were already trading before onboarding existed keep working
+UPDATE service_providers SET verification_status = 'Verified';
+
+CREATE TABLE IF NOT EXISTS provider_documents (
+    id          VARCHAR(64)  NOT NULL PRIMARY KEY,
+    provider_id VARCHAR(64)  NOT NULL,
+    type        VARCHAR(32)  NOT NULL,
+    file_name   VARCHAR(255) NOT NULL,
+    blob_key    VARCHAR(512) NOT NULL,
+    size        BIGINT       NOT NULL,
+    uploaded_at DATETIME     NOT NULL,
+    INDEX idx_provider_documents_provider (provider_id, uploaded_at),
+    CONSTRAINT fk_provider_documents_provider FOREIGN KEY (provider_id) REFERENCES service_providers (user_id) ON DELETE CASCADE
+);
@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS account_status_history;
+
+-- Keep providers that cannot sign in deactivated
+UPDATE service_providers sp
+INNER JOIN users u ON u.id = sp.user_id
+SET sp.is_active = FALSE
+WHERE u.status <> 'Active';
+
+ALTER TABLE users
+    DROP INDEX idx_users_status,
+    DROP COLUMN status_expires_at,
+    DROP COLUMN status_reason,
+    DROP COLUMN status;
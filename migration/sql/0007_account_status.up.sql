@@ -0,0 +1,23 @@
+ALTER TABLE users
+    ADD COLUMN status            VARCHAR(32) NOT NULL DEFAULT 'Active',
+    ADD COLUMN status_reason     TEXT        NULL,
+    ADD COLUMN status_expires_at DATETIME    NULL,
+    ADD INDEX idx_users_status (status);
+
+-- Providers deactivated before account statuses existed become suspended accounts
+UPDATE users u
+INNER JOIN service_providers sp ON sp.user_id = u.id
+SET u.status = 'Suspended', u.status_reason = 'Deactivated by admin'
+WHERE sp.is_active = FALSE;
+
+CREATE TABLE IF NOT EXISTS account_status_history (
+    id          VARCHAR(64) NOT NULL PRIMARY KEY,
+    user_id     VARCHAR(64) NOT NULL,
+    from_status VARCHAR(32) NOT NULL,
+    to_status   VARCHAR(32) NOT NULL,
+    reason      TEXT        NOT NULL,
+    expires_at  DATETIME    NULL,
+    changed_by  VARCHAR(64) NOT NULL,
+    changed_at  DATETIME    NOT NULL,
+    INDEX idx_account_status_history_user (user_id, changed_at)
+);
@@ -0,0 +1,18 @@
+ALTER TABLE users
+    ADD COLUMN email_verified BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Accounts created before email verification existed keep signing in as before
+UPDATE users SET email_verified = TRUE;
+
+CREATE TABLE IF NOT EXISTS auth_tokens (
+    id         VARCHAR(64) NOT NULL PRIMARY KEY,
+    user_id    VARCHAR(64) NOT NULL,
+    purpose    VARCHAR(32) NOT NULL,
+    token_hash CHAR(64)    NOT NULL,
+    expires_at DATETIME    NOT NULL,
+    used_at    DATETIME    NULL,
+    created_at DATETIME    NOT NULL,
+    UNIQUE INDEX idx_auth_tokens_hash (token_hash),
+    INDEX idx_auth_tokens_user (user_id, purpose),
+    CONSTRAINT fk_auth_tokens_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
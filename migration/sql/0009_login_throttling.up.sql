@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS login_throttles (
+    scope           VARCHAR(16)  NOT NULL,
+    throttle_key    VARCHAR(255) NOT NULL,
+    failures        INT          NOT NULL DEFAULT 0,
+    last_failure_at DATETIME     NOT NULL,
+    locked_until    DATETIME     NULL,
+    PRIMARY KEY (scope, throttle_key),
+    INDEX idx_login_throttles_locked (scope, locked_until)
+);
+
+-- Kept without a foreign key so that attempts against unknown or erased accounts stay on record
+CREATE TABLE IF NOT EXISTS login_audit (
+    id         VARCHAR(64)  NOT NULL PRIMARY KEY,
+    email      VARCHAR(255) NOT NULL,
+    user_id    VARCHAR(64)  NULL,
+    source     VARCHAR(255) NOT NULL,
+    outcome    VARCHAR(32)  NOT NULL,
+    created_at DATETIME     NOT NULL,
+    INDEX idx_login_audit_email (email, created_at)
+);
@@ -0,0 +1,3 @@
+DROP INDEX idx_users_role ON users;
+
+DROP TABLE IF EXISTS role_history;
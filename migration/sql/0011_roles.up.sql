@@ -0,0 +1,15 @@
+-- Admins used to be created by hand with any role other than Householder or ServiceProvider
+UPDATE users SET role = 'Admin' WHERE LOWER(role) IN ('admin', 'administrator');
+
+CREATE TABLE IF NOT EXISTS role_history (
+    id         VARCHAR(64) NOT NULL PRIMARY KEY,
+    user_id    VARCHAR(64) NOT NULL,
+    from_role  VARCHAR(32) NOT NULL,
+    to_role    VARCHAR(32) NOT NULL,
+    changed_by VARCHAR(64) NOT NULL,
+    changed_at DATETIME    NOT NULL,
+    INDEX idx_role_history_user (user_id, changed_at),
+    CONSTRAINT fk_role_history_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_users_role ON users (role);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS audit_log (
+    seq         BIGINT       NOT NULL PRIMARY KEY,
+    id          VARCHAR(64)  NOT NULL UNIQUE,
+    actor_id    VARCHAR(64)  NOT NULL,
+    action      VARCHAR(64)  NOT NULL,
+    entity_type VARCHAR(32)  NOT NULL,
+    entity_id   VARCHAR(64)  NOT NULL,
+    before_data MEDIUMTEXT   NULL,
+    after_data  MEDIUMTEXT   NULL,
+    created_at  DATETIME     NOT NULL,
+    prev_hash   CHAR(64)     NOT NULL,
+    hash        CHAR(64)     NOT NULL,
+    INDEX idx_audit_log_actor (actor_id, created_at),
+    INDEX idx_audit_log_entity (entity_type, entity_id, created_at),
+    INDEX idx_audit_log_created (created_at)
+);
+
+-- A single row holding the newest entry, locked while an entry is appended so that the chain never forks
+CREATE TABLE IF NOT EXISTS audit_chain_head (
+    id        TINYINT  NOT NULL PRIMARY KEY,
+    last_seq  BIGINT   NOT NULL,
+    last_hash CHAR(64) NOT NULL
+);
+
+INSERT INTO audit_chain_head (id, last_seq, last_hash) VALUES (1, 0, '');
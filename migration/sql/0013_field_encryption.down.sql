@@ -0,0 +1,4 @@
+-- The columns keep their width, encrypted values would not fit the old one
+ALTER TABLE users
+    DROP INDEX idx_users_contact_index,
+    DROP COLUMN contact_index;
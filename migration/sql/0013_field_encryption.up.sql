@@ -0,0 +1,14 @@
+-- Addresses and contact numbers are stored encrypted, which makes them several times longer
+ALTER TABLE users
+    MODIFY COLUMN address VARCHAR(3072) NOT NULL DEFAULT '',
+    MODIFY COLUMN contact VARCHAR(255)  NOT NULL DEFAULT '',
+    ADD COLUMN contact_index CHAR(32) NULL,
+    ADD INDEX idx_users_contact_index (contact_index);
+
+ALTER TABLE service_provider_details
+    MODIFY COLUMN contact VARCHAR(255)  NOT NULL DEFAULT '',
+    MODIFY COLUMN address VARCHAR(3072) NOT NULL DEFAULT '';
+
+ALTER TABLE service_requests MODIFY COLUMN householder_address VARCHAR(3072) NULL;
+
+ALTER TABLE custom_requests MODIFY COLUMN householder_address VARCHAR(3072) NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE reviews
+    DROP INDEX uq_reviews_request,
+    DROP COLUMN updated_at,
+    DROP COLUMN request_id;
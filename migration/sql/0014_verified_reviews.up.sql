@@ -0,0 +1,5 @@
+-- Reviews belong to a completed request, at most one each. Reviews written before have no request.
+ALTER TABLE reviews
+    ADD COLUMN request_id VARCHAR(64) NULL,
+    ADD COLUMN updated_at DATETIME    NULL,
+    ADD UNIQUE INDEX uq_reviews_request (request_id);
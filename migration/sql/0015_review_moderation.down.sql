@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS review_flags;
+
+ALTER TABLE reviews
+    DROP COLUMN moderated_at,
+    DROP COLUMN moderated_by,
+    DROP COLUMN moderation_note,
+    DROP COLUMN hidden,
+    DROP COLUMN reply_date,
+    DROP COLUMN reply;
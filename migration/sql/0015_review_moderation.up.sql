@@ -0,0 +1,20 @@
+ALTER TABLE reviews
+    ADD COLUMN reply            TEXT         NULL,
+    ADD COLUMN reply_date       DATETIME     NULL,
+    ADD COLUMN hidden           BOOLEAN      NOT NULL DEFAULT FALSE,
+    ADD COLUMN moderation_note  TEXT         NULL,
+    ADD COLUMN moderated_by     VARCHAR(64)  NULL,
+    ADD COLUMN moderated_at     DATETIME     NULL;
+
+-- One flag per user and review. Flags stay open until an admin hides or restores the review.
+CREATE TABLE IF NOT EXISTS review_flags (
+    id          VARCHAR(64) NOT NULL PRIMARY KEY,
+    review_id   VARCHAR(64) NOT NULL,
+    user_id     VARCHAR(64) NOT NULL,
+    reason      TEXT        NOT NULL,
+    created_at  DATETIME    NOT NULL,
+    resolved    BOOLEAN     NOT NULL DEFAULT FALSE,
+    UNIQUE INDEX uq_review_flags_user (review_id, user_id),
+    INDEX idx_review_flags_open (resolved, created_at),
+    CONSTRAINT fk_review_flags_review FOREIGN KEY (review_id) REFERENCES reviews (id) ON DELETE CASCADE
+);
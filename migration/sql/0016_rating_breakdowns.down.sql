@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS provider_ratings;
+
+DROP TABLE IF EXISTS review_scores;
+
+ALTER TABLE reviews
+    DROP COLUMN category;
@@ -0,0 +1,53 @@
+-- The category is kept with the review so that its share of a category rating can be taken back later
+ALTER TABLE reviews
+    ADD COLUMN category VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE reviews AS r
+INNER JOIN services AS s ON s.id = r.service_id
+SET r.category = s.category;
+
+CREATE TABLE IF NOT EXISTS review_scores (
+    review_id VARCHAR(64) NOT NULL,
+    criterion VARCHAR(32) NOT NULL,
+    score     DOUBLE      NOT NULL,
+    PRIMARY KEY (review_id, criterion),
+    CONSTRAINT fk_review_scores_review FOREIGN KEY (review_id) REFERENCES reviews (id) ON DELETE CASCADE
+);
+
+-- Running totals per provider, category and criterion. An empty category covers all categories.
+CREATE TABLE IF NOT EXISTS provider_ratings (
+    provider_id  VARCHAR(64)  NOT NULL,
+    category     VARCHAR(255) NOT NULL,
+    criterion    VARCHAR(32)  NOT NULL,
+    weighted_sum DOUBLE       NOT NULL,
+    weight_total DOUBLE       NOT NULL,
+    review_count INT          NOT NULL,
+    score_sum    DOUBLE       NOT NULL,
+    anchor_at    DATETIME     NOT NULL,
+    PRIMARY KEY (provider_id, category, criterion)
+);
+
+-- Seed the totals from the visible reviews with the default half-life of 365 days. Run
+-- "serviceNest ratings rebuild" after changing the rating settings.
+INSERT INTO provider_ratings (provider_id, category, criterion, weighted_sum, weight_total, review_count, score_sum, anchor_at)
+SELECT provider_id, '', 'overall',
+       SUM(rating * POW(0.5, GREATEST(TIMESTAMPDIFF(SECOND, review_date, UTC_TIMESTAMP()), 0) / 31536000)),
+       SUM(POW(0.5, GREATEST(TIMESTAMPDIFF(SECOND, review_date, UTC_TIMESTAMP()), 0) / 31536000)),
+       COUNT(*), SUM(rating), UTC_TIMESTAMP()
+FROM reviews
+WHERE hidden = FALSE
+GROUP BY provider_id;
+
+INSERT INTO provider_ratings (provider_id, category, criterion, weighted_sum, weight_total, review_count, score_sum, anchor_at)
+SELECT provider_id, category, 'overall',
+       SUM(rating * POW(0.5, GREATEST(TIMESTAMPDIFF(SECOND, review_date, UTC_TIMESTAMP()), 0) / 31536000)),
+       SUM(POW(0.5, GREATEST(TIMESTAMPDIFF(SECOND, review_date, UTC_TIMESTAMP()), 0) / 31536000)),
+       COUNT(*), SUM(rating), UTC_TIMESTAMP()
+FROM reviews
+WHERE hidden = FALSE AND category <> ''
+GROUP BY provider_id, category;
+
+-- Stored ratings become the Bayesian score with the default prior of 5 reviews of 3.5 stars
+UPDATE service_providers AS sp
+INNER JOIN provider_ratings AS pr ON pr.provider_id = sp.user_id AND pr.category = '' AND pr.criterion = 'overall'
+SET sp.rating = (5 * 3.5 + pr.weighted_sum) / (5 + pr.weight_total);
@@ -0,0 +1,12 @@
+-- Before and after photos attached to a review. The files live in the blob store under blob_key.
+CREATE TABLE IF NOT EXISTS review_photos (
+    id          VARCHAR(64)  NOT NULL PRIMARY KEY,
+    review_id   VARCHAR(64)  NOT NULL,
+    kind        VARCHAR(16)  NOT NULL,
+    file_name   VARCHAR(255) NOT NULL,
+    blob_key    VARCHAR(512) NOT NULL,
+    size        BIGINT       NOT NULL,
+    uploaded_at DATETIME     NOT NULL,
+    INDEX idx_review_photos_review (review_id, uploaded_at),
+    CONSTRAINT fk_review_photos_review FOREIGN KEY (review_id) REFERENCES reviews (id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS provider_portfolio;
+DROP TABLE IF EXISTS provider_certifications;
+DROP TABLE IF EXISTS provider_profiles;
@@ -0,0 +1,32 @@
+-- What a provider tells householders about themselves. Languages are stored comma separated.
+CREATE TABLE IF NOT EXISTS provider_profiles (
+    provider_id      VARCHAR(64)  NOT NULL PRIMARY KEY,
+    bio              TEXT         NOT NULL,
+    years_experience INT          NOT NULL DEFAULT 0,
+    languages        VARCHAR(512) NOT NULL DEFAULT '',
+    updated_at       DATETIME     NOT NULL,
+    CONSTRAINT fk_provider_profiles_provider FOREIGN KEY (provider_id) REFERENCES service_providers (user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS provider_certifications (
+    id          VARCHAR(64)  NOT NULL PRIMARY KEY,
+    provider_id VARCHAR(64)  NOT NULL,
+    name        VARCHAR(255) NOT NULL,
+    issuer      VARCHAR(255) NOT NULL DEFAULT '',
+    year        INT          NOT NULL DEFAULT 0,
+    INDEX idx_provider_certifications_provider (provider_id),
+    CONSTRAINT fk_provider_certifications_provider FOREIGN KEY (provider_id) REFERENCES service_providers (user_id) ON DELETE CASCADE
+);
+
+-- Photos of past work. The files live in the blob store under blob_key.
+CREATE TABLE IF NOT EXISTS provider_portfolio (
+    id          VARCHAR(64)  NOT NULL PRIMARY KEY,
+    provider_id VARCHAR(64)  NOT NULL,
+    caption     VARCHAR(255) NOT NULL DEFAULT '',
+    file_name   VARCHAR(255) NOT NULL,
+    blob_key    VARCHAR(512) NOT NULL,
+    size        BIGINT       NOT NULL,
+    uploaded_at DATETIME     NOT NULL,
+    INDEX idx_provider_portfolio_provider (provider_id, uploaded_at),
+    CONSTRAINT fk_provider_portfolio_provider FOREIGN KEY (provider_id) REFERENCES service_providers (user_id) ON DELETE CASCADE
+);
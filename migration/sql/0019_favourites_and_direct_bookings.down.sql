@@ -0,0 +1,6 @@
+ALTER TABLE service_requests
+    DROP FOREIGN KEY fk_service_requests_target_provider,
+    DROP INDEX idx_service_requests_target_provider,
+    DROP COLUMN target_provider_id;
+
+DROP TABLE IF EXISTS favourite_providers;
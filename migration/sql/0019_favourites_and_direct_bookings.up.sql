@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS favourite_providers (
+    householder_id VARCHAR(64) NOT NULL,
+    provider_id    VARCHAR(64) NOT NULL,
+    added_at       DATETIME    NOT NULL,
+    PRIMARY KEY (householder_id, provider_id),
+    INDEX idx_favourite_providers_provider (provider_id),
+    CONSTRAINT fk_favourite_providers_householder FOREIGN KEY (householder_id) REFERENCES users (id) ON DELETE CASCADE,
+    CONSTRAINT fk_favourite_providers_provider FOREIGN KEY (provider_id) REFERENCES service_providers (user_id) ON DELETE CASCADE
+);
+
+-- A request booked directly with one provider is hidden from the others until that provider declines it
+ALTER TABLE service_requests
+    ADD COLUMN target_provider_id VARCHAR(64) NULL,
+    ADD INDEX idx_service_requests_target_provider (target_provider_id),
+    ADD CONSTRAINT fk_service_requests_target_provider FOREIGN KEY (target_provider_id) REFERENCES service_providers (user_id) ON DELETE SET NULL;
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Lifecycle status of a user account
+const (
+	AccountActive    = "Active"
+	AccountSuspended = "Suspended"
+	AccountBanned    = "Banned"
+	AccountDeleted   = "Deleted"
+)
+
+// AccountStatusChange is an entry of the status history of an account
+type AccountStatusChange struct {
+	ID         string     `json:"id"`
+	UserID     string     `json:"user_id"`
+	FromStatus string     `json:"from_status"`
+	ToStatus   string     `json:"to_status"`
+	Reason     string     `json:"reason"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	ChangedBy  string     `json:"changed_by"` // admin ID, or "system" when a suspension expired
+	ChangedAt  time.Time  `json:"changed_at"`
+}
+
+// ErrAccountInactive is returned when a suspended, banned or deleted account tries to sign in or act
+var ErrAccountInactive = errors.New("account is not active")
+
+// AccountInactiveError tells the user why their account cannot be used; errors.Is(err, ErrAccountInactive) holds for it
+type AccountInactiveError struct {
+	Status    string
+	Reason    string
+	ExpiresAt *time.Time
+}
+
+func (e *AccountInactiveError) Error() string {
+	message := "your account has been deleted"
+	switch e.Status {
+	case AccountSuspended:
+		message = "your account is suspended"
+	case AccountBanned:
+		message = "your account is banned"
+	}
+	if e.ExpiresAt != nil {
+		message += " until " + e.ExpiresAt.Format("2006-01-02 15:04")
+	}
+	if e.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, e.Reason)
+	}
+	return message
+}
+
+func (e *AccountInactiveError) Is(target error) bool {
+	return target == ErrAccountInactive
+}
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entity types recorded in the audit log
+const (
+	EntityUser           = "User"
+	EntityService        = "Service"
+	EntityServiceRequest = "ServiceRequest"
+	EntityCustomRequest  = "CustomRequest"
+	EntityCategory       = "Category"
+	EntityProvider       = "ServiceProvider"
+	EntityReview         = "Review"
+)
+
+// AuditEntry records one state-changing action. Each entry carries the hash of the one before it, so that
+// editing or removing an entry breaks the chain from that point on.
+type AuditEntry struct {
+	Seq        int64     `json:"seq"`
+	ID         string    `json:"id"`
+	ActorID    string    `json:"actor_id"` // user ID, or "system" for actions nobody triggered by hand
+	Action     string    `json:"action"`
+	EntityType string    `json:"entity_type"`
+	EntityID   string    `json:"entity_id"`
+	Before     string    `json:"before,omitempty"` // JSON snapshot, empty when the entity did not exist
+	After      string    `json:"after,omitempty"`  // JSON snapshot, empty when the entity was removed
+	CreatedAt  time.Time `json:"created_at"`
+	PrevHash   string    `json:"prev_hash"`
+	Hash       string    `json:"hash"`
+}
+
+// ComputeHash returns the SHA-256 of the entry's content and PrevHash, hex encoded. CreatedAt is taken at
+// second precision in UTC, as stored.
+func (e *AuditEntry) ComputeHash() string {
+	fields := []string{
+		strconv.FormatInt(e.Seq, 10), e.ID, e.ActorID, e.Action, e.EntityType, e.EntityID, e.Before, e.After,
+		e.CreatedAt.UTC().Format("2006-01-02 15:04:05"), e.PrevHash,
+	}
+	// Each field is prefixed with its length so that no two different entries hash the same content
+	var content strings.Builder
+	for _, field := range fields {
+		content.WriteString(strconv.Itoa(len(field)))
+		content.WriteByte(':')
+		content.WriteString(field)
+	}
+	sum := sha256.Sum256([]byte(content.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditFilter narrows down the audit log; empty fields match everything. Entries come newest first.
+type AuditFilter struct {
+	ActorID    string
+	EntityType string
+	EntityID   string
+	From       time.Time // inclusive lower bound on the creation time
+	To         time.Time // exclusive upper bound on the creation time
+
+	// Limit is the page size (0 means no limit) and Offset the number of entries to skip
+	Limit  int
+	Offset int
+}
+
+// NextPage returns the filter for the page following the current one
+func (f AuditFilter) NextPage() AuditFilter {
+	f.Offset += f.Limit
+	return f
+}
+
+// AuditVerification is the outcome of checking the hash chain of the audit log
+type AuditVerification struct {
+	Checked  int    // entries checked
+	HeadSeq  int64  // sequence number of the newest entry
+	HeadHash string // hash of the newest entry, worth keeping outside the database
+	BrokenAt int64  // sequence number of the first entry that does not fit, 0 when the chain is intact
+	Problem  string
+}
+
+// Intact reports whether the whole chain checked out
+func (v *AuditVerification) Intact() bool {
+	return v.BrokenAt == 0
+}
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// What an AuthToken may be redeemed for
+const (
+	TokenEmailVerification = "EmailVerification"
+	TokenPasswordReset     = "PasswordReset"
+	TokenLoginChallenge    = "LoginChallenge" // the password was right, a second factor is still missing
+)
+
+// AuthToken is a single-use code sent by email, or handed out between the two steps of a login. Only the SHA-256 hash of the code is stored.
+type AuthToken struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Purpose   string     `json:"purpose"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// ErrInvalidToken is returned for a code that is unknown, expired, already used or meant for something else.
+// The cases are not told apart so that guessing codes reveals nothing.
+var ErrInvalidToken = errors.New("the code is invalid or has expired")
+
+// ErrInvalidCredentials is returned at login for an unknown email address or a wrong password alike
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrEmailNotVerified is returned at login when verified email addresses are required
+var ErrEmailNotVerified = errors.New("please verify your email address before logging in")
+
+// What a LoginThrottle counts failed logins for
+const (
+	ThrottleAccount = "account" // keyed by the normalized email address, whether or not it is registered
+	ThrottleSource  = "source"  // keyed by where the attempts come from
+)
+
+// localSourcePrefix marks the login source of a terminal on the machine running ServiceNest
+const localSourcePrefix = "local:"
+
+// LocalSource is the login source of a terminal on the machine host. Everyone using that machine shares it,
+// so it is kept in the login audit but never throttled.
+func LocalSource(host string) string {
+	return localSourcePrefix + host
+}
+
+// IsLocalSource tells whether source is a terminal on the machine itself rather than a remote client
+func IsLocalSource(source string) bool {
+	return source == "" || strings.HasPrefix(source, localSourcePrefix)
+}
+
+// LoginThrottle counts recent failed logins for an account or a source
+type LoginThrottle struct {
+	Scope         string     `json:"scope"`
+	Key           string     `json:"key"`
+	Failures      int        `json:"failures"`
+	LastFailureAt time.Time  `json:"last_failure_at"`
+	LockedUntil   *time.Time `json:"locked_until,omitempty"`
+}
+
+// Outcome of a LoginAuditEntry
+const (
+	LoginInvalidCredentials = "InvalidCredentials"
+	LoginInvalidCode        = "InvalidSecondFactor"
+	LoginThrottled          = "Throttled"
+	LoginUnlocked           = "Unlocked"
+)
+
+// LoginAuditEntry records a failed or throttled login attempt, or an admin unlocking an account
+type LoginAuditEntry struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	UserID    string    `json:"user_id,omitempty"` // empty when the address is not registered
+	Source    string    `json:"source"`            // where the attempt came from, or "admin:<id>" for an unlock
+	Outcome   string    `json:"outcome"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ErrTooManyAttempts is returned when an account or a source has to wait before trying to log in again
+var ErrTooManyAttempts = errors.New("too many failed login attempts")
+
+// LoginThrottledError tells how long to wait; errors.Is(err, ErrTooManyAttempts) holds for it
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, try again in %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Is(target error) bool {
+	return target == ErrTooManyAttempts
+}
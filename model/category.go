@@ -1,6 +1,10 @@
 package model
 
+// Category groups services in the catalogue. Sub-categories point at their parent through ParentID,
+// top-level categories have an empty ParentID.
 type Category struct {
+	ID          string `json:"ID,omitempty"`
 	Name        string `json:"Name"`
 	Description string `json:"Description"`
+	ParentID    string `json:"ParentID,omitempty"`
 }
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// MaskedContact replaces a phone number or address that is only shared once a booking is approved
+const MaskedContact = "shared once the booking is approved"
+
+// MaxRelayMessageLength bounds a message passed on through ServiceNest
+const MaxRelayMessageLength = 500
+
+// ContactReference is the reference a householder and a provider quote to reach each other through
+// ServiceNest while their contact details are hidden. It is derived from the request, so both sides and
+// support staff see the same one.
+func ContactReference(requestID string) string {
+	sum := sha256.Sum256([]byte(requestID))
+	return "SN-" + strings.ToUpper(hex.EncodeToString(sum[:4]))
+}
+
+// MaskContact hides the contact details of the provider in a service listing
+func (s *Service) MaskContact() {
+	s.ProviderContact, s.ProviderAddress = MaskedContact, MaskedContact
+}
+
+// SharesContactWith reports whether the householder of the request and the provider see each other's
+// contact details, which is the case once the householder approved that provider
+func (r *ServiceRequest) SharesContactWith(providerID string) bool {
+	if !r.ApproveStatus {
+		return false
+	}
+	for _, provider := range r.ProviderDetails {
+		if provider.ServiceProviderID == providerID {
+			return provider.Approve
+		}
+	}
+	return false
+}
+
+// MaskForHouseholder hides the contact details of every provider on the request except the approved one
+func (r *ServiceRequest) MaskForHouseholder() {
+	r.ContactReference = ContactReference(r.ID)
+	for i := range r.ProviderDetails {
+		if !r.SharesContactWith(r.ProviderDetails[i].ServiceProviderID) {
+			r.ProviderDetails[i].Contact, r.ProviderDetails[i].Address = MaskedContact, MaskedContact
+		}
+	}
+}
+
+// MaskForProvider hides the householder address unless providerID was approved on the request, and the
+// contact details of every other provider. An empty providerID masks everything.
+func (r *ServiceRequest) MaskForProvider(providerID string) {
+	r.ContactReference = ContactReference(r.ID)
+	if r.HouseholderAddress != nil && (providerID == "" || !r.SharesContactWith(providerID)) {
+		masked := MaskedContact
+		r.HouseholderAddress = &masked
+	}
+	for i := range r.ProviderDetails {
+		if r.ProviderDetails[i].ServiceProviderID != providerID {
+			r.ProviderDetails[i].Contact, r.ProviderDetails[i].Address = MaskedContact, MaskedContact
+		}
+	}
+}
+
+var (
+	// ErrNotRequestParty is returned when a message is sent between users who are not the householder of a
+	// request and a provider who quoted on it
+	ErrNotRequestParty = errors.New("messages can only be sent between the householder and a provider who quoted on the request")
+	// ErrEmptyMessage is returned for a message without text
+	ErrEmptyMessage = errors.New("the message is empty")
+	// ErrMessageTooLong is returned for a message over MaxRelayMessageLength characters
+	ErrMessageTooLong = errors.New("the message is too long")
+)
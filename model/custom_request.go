@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Status of a custom request in the admin triage queue
+const (
+	CustomRequestOpen     = "Open"
+	CustomRequestMapped   = "Mapped"
+	CustomRequestRejected = "Rejected"
+)
+
+// CustomRequest is a householder request for a service that is not offered yet. It waits in the
+// triage queue until an admin maps it to a category or rejects it.
+type CustomRequest struct {
+	ID                 string     `json:"id"`
+	HouseholderID      string     `json:"householder_id"`
+	HouseholderName    string     `json:"householder_name"`
+	HouseholderAddress string     `json:"householder_address"`
+	ServiceName        string     `json:"service_name"` // What the householder asked for
+	RequestedTime      time.Time  `json:"requested_time"`
+	ScheduledTime      time.Time  `json:"scheduled_time"`
+	Status             string     `json:"status"` // Open, Mapped, Rejected
+	CategoryID         string     `json:"category_id,omitempty"`
+	ResolutionNote     string     `json:"resolution_note,omitempty"`
+	ResolvedBy         string     `json:"resolved_by,omitempty"`
+	ResolvedAt         *time.Time `json:"resolved_at,omitempty"`
+}
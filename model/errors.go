@@ -0,0 +1,29 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrConflict is returned when a conditional update finds that the row changed after it was read
+var ErrConflict = errors.New("record was modified by another user")
+
+// ConflictError identifies the record whose version no longer matched; errors.Is(err, ErrConflict) holds for it
+type ConflictError struct {
+	Entity string
+	ID     string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%s %s was modified by another user, please refresh and try again", e.Entity, e.ID)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// ErrServiceNotOffered is returned when a householder asks for a service name that no provider offers
+var ErrServiceNotOffered = errors.New("no service with this name is offered yet")
+
+// ErrProviderNotVerified is returned when a provider whose documents have not been approved yet tries to trade
+var ErrProviderNotVerified = errors.New("your account is awaiting verification, upload your documents and wait for an admin to approve them")
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// FavouriteProvider is a provider a householder saved to book again
+type FavouriteProvider struct {
+	HouseholderID string    `json:"householder_id"`
+	ProviderID    string    `json:"provider_id"`
+	ProviderName  string    `json:"provider_name"`
+	Available     bool      `json:"available"`
+	AddedAt       time.Time `json:"added_at"`
+}
+
+var (
+	// ErrProviderUnavailable is returned when a householder books a provider who is not taking new jobs
+	ErrProviderUnavailable = errors.New("this provider is not taking new jobs")
+	// ErrNotOfferedByProvider is returned when a householder books a provider for a service they do not offer
+	ErrNotOfferedByProvider = errors.New("the provider does not offer this service")
+)
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Notification is a message kept in a user's inbox until it has been read
+type Notification struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+	Read      bool      `json:"read"`
+}
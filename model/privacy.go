@@ -0,0 +1,49 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// ErasedName replaces the name of an erased user wherever it was stored or copied
+const ErasedName = "Deleted user"
+
+// ErasedEmail is the placeholder address of an erased account. It keeps the email column unique and can
+// never receive mail.
+func ErasedEmail(userID string) string {
+	return "erased-" + userID + "@erased.invalid"
+}
+
+// IsErased reports whether the personal data of the account has been erased
+func (u *User) IsErased() bool {
+	return u.Email == ErasedEmail(u.ID)
+}
+
+// UserDataExport bundles everything ServiceNest holds about a user. Details of other people, such as the
+// householders of a provider's requests, are left out.
+type UserDataExport struct {
+	ExportedAt      time.Time               `json:"exported_at"`
+	Profile         User                    `json:"profile"` // without the password hash
+	StatusHistory   []AccountStatusChange   `json:"status_history"`
+	RoleHistory     []RoleChange            `json:"role_history"`
+	Notifications   []Notification          `json:"notifications"`
+	LoginHistory    []LoginAuditEntry       `json:"login_history"` // failed and refused logins
+	ServiceRequests []ServiceRequest        `json:"service_requests,omitempty"`
+	CustomRequests  []CustomRequest         `json:"custom_requests,omitempty"`
+	ReviewsWritten  []Review                `json:"reviews_written,omitempty"`
+	ReviewPhotos    []ReviewPhoto           `json:"review_photos,omitempty"` // the files themselves are not included
+	Favourites      []FavouriteProvider     `json:"favourites,omitempty"`
+	Provider        *ServiceProvider        `json:"provider,omitempty"`
+	ProviderJobs    []ServiceRequest        `json:"provider_jobs,omitempty"`
+	ReviewsReceived []Review                `json:"reviews_received,omitempty"`
+	Documents       []ProviderDocument      `json:"documents,omitempty"` // the files themselves are not included
+	PublicProfile   *ProviderProfileDetails `json:"public_profile,omitempty"`
+	Portfolio       []PortfolioImage        `json:"portfolio,omitempty"` // the files themselves are not included
+}
+
+var (
+	// ErrAlreadyErased is returned when the personal data of an account is erased a second time
+	ErrAlreadyErased = errors.New("the personal data of this account has already been erased")
+	// ErrStaffErasure is returned when the data of a staff account is to be erased while it still has its role
+	ErrStaffErasure = errors.New("staff accounts must lose their role before their data can be erased")
+)
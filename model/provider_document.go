@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Kinds of document a provider submits for verification
+const (
+	DocumentIdentity  = "ID"
+	DocumentLicence   = "Licence"
+	DocumentInsurance = "Insurance"
+)
+
+// ProviderDocument describes a verification document, the file itself lives in the blob store under BlobKey
+type ProviderDocument struct {
+	ID         string    `json:"id"`
+	ProviderID string    `json:"provider_id"`
+	Type       string    `json:"type"` // ID, Licence or Insurance
+	FileName   string    `json:"file_name"`
+	BlobKey    string    `json:"blob_key"`
+	Size       int64     `json:"size"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Limits of what a provider can put on their public profile
+const (
+	MaxBioLength        = 2000
+	MaxYearsExperience  = 80
+	MaxPortfolioImages  = 12
+	MaxCertificationLen = 255
+)
+
+// ProviderProfileDetails is what a provider writes about themselves
+type ProviderProfileDetails struct {
+	ProviderID      string          `json:"provider_id"`
+	Bio             string          `json:"bio"`
+	YearsExperience int             `json:"years_experience"`
+	Languages       []string        `json:"languages,omitempty"`
+	Certifications  []Certification `json:"certifications,omitempty"`
+	UpdatedAt       *time.Time      `json:"updated_at,omitempty"` // nil until the provider first saves a profile
+}
+
+// Certification is a qualification a provider lists on their profile
+type Certification struct {
+	ID         string `json:"id"`
+	ProviderID string `json:"provider_id"`
+	Name       string `json:"name"`
+	Issuer     string `json:"issuer,omitempty"`
+	Year       int    `json:"year,omitempty"`
+}
+
+// PortfolioImage describes a photo of a provider's past work, the file itself lives in the blob store under BlobKey
+type PortfolioImage struct {
+	ID         string    `json:"id"`
+	ProviderID string    `json:"provider_id"`
+	Caption    string    `json:"caption,omitempty"`
+	FileName   string    `json:"file_name"`
+	BlobKey    string    `json:"blob_key"`
+	Size       int64     `json:"size"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// ProviderProfile is the public profile of a provider that householders see before approving a quote. Contact
+// details are left out, they are shared once a booking is approved.
+type ProviderProfile struct {
+	ProviderID      string           `json:"provider_id"`
+	Name            string           `json:"name"`
+	Available       bool             `json:"available"`
+	Bio             string           `json:"bio,omitempty"`
+	YearsExperience int              `json:"years_experience,omitempty"`
+	Languages       []string         `json:"languages,omitempty"`
+	Certifications  []Certification  `json:"certifications,omitempty"`
+	Portfolio       []PortfolioImage `json:"portfolio,omitempty"`
+	Services        []Service        `json:"services"` // with their prices
+	Rating          ProviderRating   `json:"rating"`
+	CompletedJobs   int              `json:"completed_jobs"`
+}
+
+var (
+	// ErrBioTooLong is returned for a bio longer than MaxBioLength
+	ErrBioTooLong = errors.New("the bio is too long")
+	// ErrTooManyPortfolioImages is returned when a provider already shows MaxPortfolioImages images
+	ErrTooManyPortfolioImages = errors.New("your portfolio already has the maximum number of images")
+)
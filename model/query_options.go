@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// QueryOptions narrows and orders a listing. The zero value returns every row in the default order.
+type QueryOptions struct {
+	// Filters; empty values are ignored
+	Status     string
+	From       time.Time // inclusive lower bound on the requested time
+	To         time.Time // exclusive upper bound on the requested time
+	Category   string
+	ProviderID string
+	OfferedTo  string // leaves out requests booked directly with another provider
+
+	// SortBy is one of the sort keys supported by the listing, Descending reverses it
+	SortBy     string
+	Descending bool
+
+	// Limit is the page size (0 means no limit) and Offset the number of rows to skip
+	Limit  int
+	Offset int
+}
+
+// NextPage returns the options for the page following the current one
+func (o QueryOptions) NextPage() QueryOptions {
+	o.Offset += o.Limit
+	return o
+}
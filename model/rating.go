@@ -0,0 +1,136 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// Criteria a review can score besides the overall rating
+const (
+	CriterionOverall       = "overall"
+	CriterionQuality       = "quality"
+	CriterionPunctuality   = "punctuality"
+	CriterionCommunication = "communication"
+	CriterionValue         = "value"
+)
+
+// RatingCriteria are the sub-scores a householder may give with a review, in display order
+var RatingCriteria = []string{CriterionQuality, CriterionPunctuality, CriterionCommunication, CriterionValue}
+
+// AllCategories is the category of the aggregates that cover every review of a provider
+const AllCategories = ""
+
+// ErrUnknownCriterion is returned for a sub-score that is not one of RatingCriteria
+var ErrUnknownCriterion = errors.New("unknown rating criterion")
+
+// ValidCriterion reports whether criterion is one of RatingCriteria
+func ValidCriterion(criterion string) bool {
+	for _, known := range RatingCriteria {
+		if criterion == known {
+			return true
+		}
+	}
+	return false
+}
+
+// RatingOptions describe how reviews are combined into a rating. The score of a provider is the Bayesian
+// average of their reviews and PriorWeight reviews of PriorMean stars; a review counts half as much
+// every HalfLife, or always fully when HalfLife is 0.
+type RatingOptions struct {
+	PriorMean   float64
+	PriorWeight float64
+	HalfLife    time.Duration
+}
+
+// RatingAggregate holds the running totals of one criterion of a provider in one category, or in every
+// category for AllCategories. WeightedSum and WeightTotal are the decayed sums of the scores and of their
+// weights as of AnchorAt, which lets a review be added or removed without reading the other reviews.
+type RatingAggregate struct {
+	ProviderID  string    `json:"provider_id"`
+	Category    string    `json:"category"`
+	Criterion   string    `json:"criterion"`
+	WeightedSum float64   `json:"weighted_sum"`
+	WeightTotal float64   `json:"weight_total"`
+	ReviewCount int       `json:"review_count"`
+	ScoreSum    float64   `json:"score_sum"` // undecayed, for the plain average
+	AnchorAt    time.Time `json:"anchor_at"`
+}
+
+// decay is the factor a weight shrinks by over elapsed
+func decay(elapsed, halfLife time.Duration) float64 {
+	if halfLife <= 0 {
+		return 1
+	}
+	return math.Exp2(-elapsed.Seconds() / halfLife.Seconds())
+}
+
+// Add counts a score given at the time at. Totals are moved forward to at first when it is later than AnchorAt.
+func (a *RatingAggregate) Add(score float64, at time.Time, halfLife time.Duration) {
+	if a.AnchorAt.IsZero() || at.After(a.AnchorAt) {
+		factor := decay(at.Sub(a.AnchorAt), halfLife)
+		a.WeightedSum *= factor
+		a.WeightTotal *= factor
+		a.AnchorAt = at
+	}
+	weight := decay(a.AnchorAt.Sub(at), halfLife)
+	a.WeightedSum += score * weight
+	a.WeightTotal += weight
+	a.ReviewCount++
+	a.ScoreSum += score
+}
+
+// Remove takes back a score added earlier with the same time
+func (a *RatingAggregate) Remove(score float64, at time.Time, halfLife time.Duration) {
+	weight := decay(a.AnchorAt.Sub(at), halfLife)
+	a.WeightedSum -= score * weight
+	a.WeightTotal -= weight
+	a.ReviewCount--
+	a.ScoreSum -= score
+	if a.ReviewCount <= 0 {
+		// Rounding must not leave a rating behind once the last review is gone
+		a.WeightedSum, a.WeightTotal, a.ReviewCount, a.ScoreSum = 0, 0, 0, 0
+	}
+}
+
+// Score is the Bayesian, recency weighted rating at now, 0 when there are no reviews
+func (a RatingAggregate) Score(now time.Time, options RatingOptions) float64 {
+	if a.ReviewCount == 0 {
+		return 0
+	}
+	factor := decay(now.Sub(a.AnchorAt), options.HalfLife)
+	weightTotal := options.PriorWeight + a.WeightTotal*factor
+	if weightTotal <= 0 {
+		return a.Average()
+	}
+	return (options.PriorWeight*options.PriorMean + a.WeightedSum*factor) / weightTotal
+}
+
+// Average is the plain mean of the scores, 0 when there are no reviews
+func (a RatingAggregate) Average() float64 {
+	if a.ReviewCount == 0 {
+		return 0
+	}
+	return a.ScoreSum / float64(a.ReviewCount)
+}
+
+// RatingScore is one line of a rating breakdown
+type RatingScore struct {
+	Score       float64 `json:"score"`   // Bayesian and recency weighted, used for ranking
+	Average     float64 `json:"average"` // plain mean of the stars given
+	ReviewCount int     `json:"review_count"`
+}
+
+// RatingBreakdown is the overall rating and the sub-scores of a provider in one category, or in all of them
+type RatingBreakdown struct {
+	Category string                 `json:"category,omitempty"`
+	Overall  RatingScore            `json:"overall"`
+	Criteria map[string]RatingScore `json:"criteria,omitempty"`
+}
+
+// ProviderRating is the rating breakdown of a provider across all categories and per category
+type ProviderRating struct {
+	ProviderID string            `json:"provider_id"`
+	All        RatingBreakdown   `json:"all"`
+	Categories []RatingBreakdown `json:"categories,omitempty"` // sorted by category
+}
@@ -14,7 +14,17 @@ type ServiceRequest struct {
 	Status             string                   `json:"status" bson:"status"` // Pending, Accepted, Completed, Cancelled
 	ApproveStatus      bool                     `json:"approve_status" bson:"approveStatus"`
 	ProviderDetails    []ServiceProviderDetails `json:"provider_details,omitempty" bson:"providerDetails,omitempty"`
+	Version            int                      `json:"version" bson:"version"`                                         // Incremented on every update, used for optimistic locking
+	ContactReference   string                   `json:"contact_reference,omitempty" bson:"-"`                           // shown instead of masked contact details
+	TargetProviderID   *string                  `json:"target_provider_id,omitempty" bson:"targetProviderID,omitempty"` // set while the request is booked directly with one provider
 }
+
+// OfferedTo reports whether providerID may quote on the request. Requests are open to every provider unless
+// the householder booked one of them directly.
+func (r *ServiceRequest) OfferedTo(providerID string) bool {
+	return r.TargetProviderID == nil || *r.TargetProviderID == providerID
+}
+
 type ServiceProviderDetails struct {
 	ServiceProviderID string   `json:"service_provider_id" bson:"serviceProviderID"`
 	Name              string   `json:"name" bson:"name"`
@@ -24,4 +34,5 @@ type ServiceProviderDetails struct {
 	Rating            float64  `json:"rating" bson:"rating"`
 	Reviews           []Review `json:"reviews" bson:"reviews"`
 	Approve           bool     `json:"approve" bson:"approve"`
+	Version           int      `json:"version" bson:"version"`
 }
@@ -1,13 +1,81 @@
 package model
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 type Review struct {
-	ID            string    `json:"id" bson:"id"`
-	ServiceID     string    `json:"service_id" bson:"service_id"`
-	HouseholderID string    `json:"householder_id" bson:"householder_id"`
-	ProviderID    string    `json:"provider_id"`
-	Rating        float64   `json:"rating" bson:"rating"`
-	Comments      string    `json:"comments" bson:"comments"`
-	ReviewDate    time.Time `json:"review_date" bson:"review_date"`
+	ID            string             `json:"id" bson:"id"`
+	ServiceID     string             `json:"service_id" bson:"service_id"`
+	HouseholderID string             `json:"householder_id" bson:"householder_id"`
+	ProviderID    string             `json:"provider_id"`
+	RequestID     string             `json:"request_id,omitempty"` // the completed request the review is about, empty for older reviews
+	Category      string             `json:"category,omitempty"`   // category of the service when the review was written
+	Rating        float64            `json:"rating" bson:"rating"`
+	Scores        map[string]float64 `json:"scores,omitempty"` // optional sub-scores by criterion, see RatingCriteria
+	Comments      string             `json:"comments" bson:"comments"`
+	ReviewDate    time.Time          `json:"review_date" bson:"review_date"`
+	UpdatedAt     *time.Time         `json:"updated_at,omitempty"` // set when the householder edits the review
+	Photos        []ReviewPhoto      `json:"photos,omitempty"`     // filled in for profile pages only
+
+	// The provider's public answer, at most one per review
+	Reply     string     `json:"reply,omitempty"`
+	ReplyDate *time.Time `json:"reply_date,omitempty"`
+
+	// Hidden reviews are kept but neither shown nor counted in the provider's rating
+	Hidden         bool       `json:"hidden"`
+	ModerationNote string     `json:"moderation_note,omitempty"` // the reason given with the last hide or restore
+	ModeratedBy    string     `json:"moderated_by,omitempty"`
+	ModeratedAt    *time.Time `json:"moderated_at,omitempty"`
+}
+
+// Bounds of a review rating
+const (
+	MinRating = 1
+	MaxRating = 5
+)
+
+// ReviewEditWindow is how long after writing it a householder may still change a review
+const ReviewEditWindow = 7 * 24 * time.Hour
+
+// RequestCompleted is the status of a request once the provider has done the job
+const RequestCompleted = "Completed"
+
+var (
+	// ErrInvalidRating is returned for a rating outside MinRating and MaxRating
+	ErrInvalidRating = errors.New("the rating must be between 1 and 5")
+	// ErrReviewNotAllowed is returned when the householder did not book the provider on a completed request
+	ErrReviewNotAllowed = errors.New("you can only review a provider after they completed a request you booked")
+	// ErrAlreadyReviewed is returned for a second review of the same request
+	ErrAlreadyReviewed = errors.New("this request has already been reviewed")
+	// ErrReviewEditClosed is returned when a review is changed after ReviewEditWindow has passed
+	ErrReviewEditClosed = errors.New("reviews can only be changed within 7 days of writing them")
+)
+
+// ValidRating reports whether rating lies within the allowed bounds
+func ValidRating(rating float64) bool {
+	return rating >= MinRating && rating <= MaxRating
+}
+
+// ValidateScores checks the overall rating and every sub-score of the review
+func (r *Review) ValidateScores() error {
+	if !ValidRating(r.Rating) {
+		return ErrInvalidRating
+	}
+	for criterion, score := range r.Scores {
+		if !ValidCriterion(criterion) {
+			return fmt.Errorf("%w: %s", ErrUnknownCriterion, criterion)
+		}
+		if !ValidRating(score) {
+			return ErrInvalidRating
+		}
+	}
+	return nil
+}
+
+// Editable reports whether the review may still be changed at now
+func (r *Review) Editable(now time.Time) bool {
+	return now.Before(r.ReviewDate.Add(ReviewEditWindow))
 }
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// MaxReplyLength bounds a provider's reply to a review
+const MaxReplyLength = 1000
+
+// ReviewFlag is a user's report that a review is abusive or otherwise breaks the rules
+type ReviewFlag struct {
+	ID        string    `json:"id"`
+	ReviewID  string    `json:"review_id"`
+	UserID    string    `json:"user_id"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	Resolved  bool      `json:"resolved"` // set once an admin has hidden or restored the review
+}
+
+// FlaggedReview is an entry of the moderation queue: a review with its open flags, oldest flag first
+type FlaggedReview struct {
+	Review Review       `json:"review"`
+	Flags  []ReviewFlag `json:"flags"`
+}
+
+var (
+	// ErrAlreadyReplied is returned when a provider replies to a review a second time
+	ErrAlreadyReplied = errors.New("you have already replied to this review")
+	// ErrReplyTooLong is returned for a reply longer than MaxReplyLength
+	ErrReplyTooLong = errors.New("the reply is too long")
+	// ErrAlreadyFlagged is returned when a user flags the same review twice
+	ErrAlreadyFlagged = errors.New("you have already flagged this review")
+)
+
+// VisibleReviews returns the reviews that have not been hidden by a moderator
+func VisibleReviews(reviews []Review) []Review {
+	var visible []Review
+	for _, review := range reviews {
+		if !review.Hidden {
+			visible = append(visible, review)
+		}
+	}
+	return visible
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Kinds of photo a householder attaches to a review
+const (
+	PhotoBefore = "before"
+	PhotoAfter  = "after"
+)
+
+// MaxReviewPhotos is the number of photos a single review can carry
+const MaxReviewPhotos = 6
+
+// ReviewPhoto describes a before or after photo of a job, the file itself lives in the blob store under BlobKey
+type ReviewPhoto struct {
+	ID         string    `json:"id"`
+	ReviewID   string    `json:"review_id"`
+	Kind       string    `json:"kind"` // before or after
+	FileName   string    `json:"file_name"`
+	BlobKey    string    `json:"blob_key"`
+	Size       int64     `json:"size"`
+	UploadedAt time.Time `json:"uploaded_at"`
+}
+
+// ProviderReviewSummary is the review section of a provider's profile: the rating of every criterion, the
+// visible reviews with their photos and a gallery of all those photos, newest first
+type ProviderReviewSummary struct {
+	ProviderID string         `json:"provider_id"`
+	Rating     ProviderRating `json:"rating"`
+	Reviews    []Review       `json:"reviews"`
+	Gallery    []ReviewPhoto  `json:"gallery,omitempty"`
+}
+
+// ErrTooManyPhotos is returned when a review already carries MaxReviewPhotos photos
+var ErrTooManyPhotos = errors.New("this review already has the maximum number of photos")
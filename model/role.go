@@ -0,0 +1,48 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// Roles a user account can have. Admins and support staff use the admin dashboard, support staff cannot
+// change services, categories, accounts or roles.
+const (
+	RoleHouseholder     = "Householder"
+	RoleServiceProvider = "ServiceProvider"
+	RoleAdmin           = "Admin"
+	RoleSupport         = "Support"
+)
+
+// ValidRole reports whether role is one of the known roles
+func ValidRole(role string) bool {
+	switch role {
+	case RoleHouseholder, RoleServiceProvider, RoleAdmin, RoleSupport:
+		return true
+	}
+	return false
+}
+
+// IsStaffRole reports whether role belongs to the people running the platform
+func IsStaffRole(role string) bool {
+	return role == RoleAdmin || role == RoleSupport
+}
+
+// RoleChange is an entry of the role history of an account
+type RoleChange struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	FromRole  string    `json:"from_role"` // empty for the bootstrapped admin
+	ToRole    string    `json:"to_role"`
+	ChangedBy string    `json:"changed_by"` // admin ID, or "system" for the bootstrapped admin
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+var (
+	// ErrUnknownRole is returned when an account has a role the application does not know
+	ErrUnknownRole = errors.New("account has an unknown role, contact an administrator")
+	// ErrAdminRequired is returned when someone other than an admin tries an admin-only action
+	ErrAdminRequired = errors.New("only an admin can do this")
+	// ErrAdminExists is returned when the first admin is bootstrapped a second time
+	ErrAdminExists = errors.New("an admin already exists, ask them to grant the role instead")
+)
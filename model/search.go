@@ -0,0 +1,27 @@
+package model
+
+// SearchDocument is the searchable view of a service together with the provider offering it
+type SearchDocument struct {
+	Service        Service
+	ProviderName   string
+	ProviderRating float64
+	Latitude       float64
+	Longitude      float64
+}
+
+// SearchQuery is a free-text search; when UseLocation is set closer providers rank higher
+type SearchQuery struct {
+	Text        string
+	Latitude    float64
+	Longitude   float64
+	UseLocation bool
+	Limit       int
+}
+
+// SearchResult is a matching service with its scores. DistanceKm is negative when it is unknown.
+type SearchResult struct {
+	Service    Service
+	Score      float64
+	Relevance  float64
+	DistanceKm float64
+}
@@ -1,10 +1,32 @@
 package model
 
+import "time"
+
+// Verification status of a service provider account
+const (
+	VerificationPending  = "PendingVerification"
+	VerificationVerified = "Verified"
+	VerificationRejected = "Rejected"
+)
+
 type ServiceProvider struct {
 	User
-	ServicesOffered []Service `json:"services_offered" bson:"services_offered"`
-	Rating          float64   `json:"rating" bson:"rating"`
-	Reviews         []*Review `json:"reviews" bson:"reviews"`
-	Availability    bool      `json:"availability" bson:"availability"`
-	IsActive        bool      `json:"is_active" bson:"is_active"`
+	ServicesOffered    []Service `json:"services_offered" bson:"services_offered"`
+	Rating             float64   `json:"rating" bson:"rating"`
+	Reviews            []*Review `json:"reviews" bson:"reviews"`
+	Availability       bool      `json:"availability" bson:"availability"`
+	IsActive           bool      `json:"is_active" bson:"is_active"`
+	VerificationStatus string    `json:"verification_status" bson:"verification_status"` // PendingVerification, Verified, Rejected
+	VerificationNote   string    `json:"verification_note,omitempty" bson:"verification_note,omitempty"`
+}
+
+// IsListed reports whether householders can find and book the provider: the account is active, verified and
+// not suspended or banned
+func (p *ServiceProvider) IsListed(now time.Time) bool {
+	return p.IsActive && p.IsVerified() && p.HasAccess(now)
+}
+
+// IsVerified reports whether an admin has approved the provider's documents
+func (p *ServiceProvider) IsVerified() bool {
+	return p.VerificationStatus == VerificationVerified
 }
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// TwoFactor holds the TOTP secret of a user. It stays disabled until the user proves their authenticator
+// app produces valid codes.
+type TwoFactor struct {
+	UserID       string     `json:"user_id"`
+	Secret       string     `json:"-"`
+	Enabled      bool       `json:"enabled"`
+	LastUsedStep int64      `json:"-"` // codes from this time step or earlier are not accepted again
+	CreatedAt    time.Time  `json:"created_at"`
+	ConfirmedAt  *time.Time `json:"confirmed_at,omitempty"`
+}
+
+// RecoveryCode lets a user log in once without their authenticator app. Only its hash is stored.
+type RecoveryCode struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// TOTPEnrolment is what a user needs to add their account to an authenticator app
+type TOTPEnrolment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"` // otpauth:// URI
+}
+
+// ErrSecondFactorRequired is returned by a login whose password was right but which still needs a code
+var ErrSecondFactorRequired = errors.New("a two-factor authentication code is required")
+
+// SecondFactorRequiredError carries the challenge to complete the login with; errors.Is(err,
+// ErrSecondFactorRequired) holds for it. EnrolmentRequired is set for admins who have not set up
+// two-factor authentication yet and have to do so before they can log in.
+type SecondFactorRequiredError struct {
+	UserID            string
+	Challenge         string
+	EnrolmentRequired bool
+}
+
+func (e *SecondFactorRequiredError) Error() string {
+	if e.EnrolmentRequired {
+		return "two-factor authentication has to be set up before you can log in"
+	}
+	return ErrSecondFactorRequired.Error()
+}
+
+func (e *SecondFactorRequiredError) Is(target error) bool {
+	return target == ErrSecondFactorRequired
+}
+
+// ErrInvalidSecondFactor is returned for a wrong, expired or already used authentication or recovery code
+var ErrInvalidSecondFactor = errors.New("invalid authentication code")
+
+// ErrLoginExpired is returned when the second step of a login comes too late or was completed already
+var ErrLoginExpired = errors.New("the login has expired, please log in again")
+
+// ErrTwoFactorEnabled is returned when enrolling an account that already uses two-factor authentication
+var ErrTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+
+// ErrTwoFactorNotEnabled is returned when managing two-factor authentication that was never set up
+var ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+
+// ErrTwoFactorMandatory is returned when an admin tries to turn two-factor authentication off
+var ErrTwoFactorMandatory = errors.New("two-factor authentication is mandatory for admins")
@@ -1,13 +1,34 @@
 package model
 
+import "time"
+
 type User struct {
 	ID        string  `json:"id" bson:"id"`
 	Name      string  `json:"name" bson:"name"`
 	Email     string  `json:"email" bson:"email"`
 	Password  string  `json:"password" bson:"password"`
-	Role      string  `json:"role" bson:"role"` // Householder or ServiceProvider
+	Role      string  `json:"role" bson:"role"` // one of the Role constants
 	Address   string  `json:"address" bson:"address"`
 	Contact   string  `json:"contact" bson:"contact"`
 	Latitude  float64 `json:"latitude" bson:"latitude"`
 	Longitude float64 `json:"longitude" bson:"longitude"`
+
+	EmailVerified bool `json:"email_verified" bson:"email_verified"`
+
+	Status          string     `json:"status" bson:"status"` // Active, Suspended, Banned or Deleted
+	StatusReason    string     `json:"status_reason,omitempty" bson:"status_reason,omitempty"`
+	StatusExpiresAt *time.Time `json:"status_expires_at,omitempty" bson:"status_expires_at,omitempty"`
+}
+
+// HasAccess reports whether the account may be used at the given time. A suspension or ban whose expiry
+// has passed no longer applies.
+func (u *User) HasAccess(now time.Time) bool {
+	switch u.Status {
+	case "", AccountActive:
+		return true
+	case AccountSuspended, AccountBanned:
+		return u.StatusExpiresAt != nil && !now.Before(*u.StatusExpiresAt)
+	default:
+		return false
+	}
 }
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type AccountStatusRepository struct {
+	db *sql.DB
+}
+
+// NewAccountStatusRepository creates an AccountStatusRepository backed by MySQL
+func NewAccountStatusRepository(db *sql.DB) interfaces.AccountStatusRepository {
+	return &AccountStatusRepository{db: db}
+}
+
+// SaveStatusChange appends an entry to the status history of an account
+func (repo *AccountStatusRepository) SaveStatusChange(ctx context.Context, change model.AccountStatusChange) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO account_status_history (id, user_id, from_status, to_status, reason, expires_at, changed_by, changed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, change.ID, change.UserID, change.FromStatus, change.ToStatus, change.Reason,
+		change.ExpiresAt, change.ChangedBy, change.ChangedAt)
+	return err
+}
+
+// GetStatusHistory lists the status changes of an account, most recent first
+func (repo *AccountStatusRepository) GetStatusHistory(ctx context.Context, userID string) ([]model.AccountStatusChange, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, user_id, from_status, to_status, reason, expires_at, changed_by, changed_at
+		FROM account_status_history WHERE user_id = ? ORDER BY changed_at DESC, id`
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []model.AccountStatusChange
+	for rows.Next() {
+		var change model.AccountStatusChange
+		var expiresAt, changedAt []uint8
+		err := rows.Scan(&change.ID, &change.UserID, &change.FromStatus, &change.ToStatus, &change.Reason, &expiresAt, &change.ChangedBy, &changedAt)
+		if err != nil {
+			return nil, err
+		}
+		if change.ChangedAt, err = util.ParseTime(changedAt); err != nil {
+			return nil, fmt.Errorf("error parsing changed_at: %v", err)
+		}
+		if expiresAt != nil {
+			expires, err := util.ParseTime(expiresAt)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing expires_at: %v", err)
+			}
+			change.ExpiresAt = &expires
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
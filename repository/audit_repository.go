@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates an AuditRepository backed by MySQL
+func NewAuditRepository(db *sql.DB) interfaces.AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+const auditColumns = "seq, id, actor_id, action, entity_type, entity_id, before_data, after_data, created_at, prev_hash, hash"
+
+// AppendEntry links the entry to the newest one and stores it, filling in Seq, PrevHash and Hash. The
+// chain head stays locked until the surrounding transaction ends, so entries are appended one at a time
+// and disappear together with the change they describe when it is rolled back.
+func (repo *AuditRepository) AppendEntry(ctx context.Context, entry *model.AuditEntry) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return inTransaction(ctx, repo.db, func(ctx context.Context) error {
+		var lastSeq int64
+		var lastHash string
+		err := conn(ctx, repo.db).QueryRowContext(ctx, "SELECT last_seq, last_hash FROM audit_chain_head WHERE id = 1 FOR UPDATE").
+			Scan(&lastSeq, &lastHash)
+		if err != nil {
+			return fmt.Errorf("could not lock the audit chain: %v", err)
+		}
+
+		entry.Seq = lastSeq + 1
+		entry.PrevHash = lastHash
+		entry.Hash = entry.ComputeHash()
+
+		query := "INSERT INTO audit_log (" + auditColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		_, err = conn(ctx, repo.db).ExecContext(ctx, query, entry.Seq, entry.ID, entry.ActorID, entry.Action, entry.EntityType, entry.EntityID,
+			nullableString(entry.Before), nullableString(entry.After), entry.CreatedAt, entry.PrevHash, entry.Hash)
+		if err != nil {
+			return err
+		}
+		_, err = conn(ctx, repo.db).ExecContext(ctx, "UPDATE audit_chain_head SET last_seq = ?, last_hash = ? WHERE id = 1", entry.Seq, entry.Hash)
+		return err
+	})
+}
+
+// GetEntries returns the entries matching the filter, newest first
+func (repo *AuditRepository) GetEntries(ctx context.Context, filter model.AuditFilter) ([]model.AuditEntry, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	q := &listQuery{}
+	if filter.ActorID != "" {
+		q.where("actor_id = ?", filter.ActorID)
+	}
+	if filter.EntityType != "" {
+		q.where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		q.where("entity_id = ?", filter.EntityID)
+	}
+	if !filter.From.IsZero() {
+		q.where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		q.where("created_at < ?", filter.To)
+	}
+	limit, limitArgs, err := limitClause(model.QueryOptions{Limit: filter.Limit, Offset: filter.Offset})
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT " + auditColumns + " FROM audit_log" + q.whereClause() + " ORDER BY seq DESC" + limit
+	return repo.queryEntries(ctx, query, append(q.args, limitArgs...)...)
+}
+
+// GetEntriesAfter returns up to limit entries following afterSeq, oldest first
+func (repo *AuditRepository) GetEntriesAfter(ctx context.Context, afterSeq int64, limit int) ([]model.AuditEntry, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + auditColumns + " FROM audit_log WHERE seq > ? ORDER BY seq LIMIT ?"
+	return repo.queryEntries(ctx, query, afterSeq, limit)
+}
+
+// GetChainHead returns the sequence number and hash of the newest entry
+func (repo *AuditRepository) GetChainHead(ctx context.Context) (int64, string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var seq int64
+	var hash string
+	err := conn(ctx, repo.db).QueryRowContext(ctx, "SELECT last_seq, last_hash FROM audit_chain_head WHERE id = 1").Scan(&seq, &hash)
+	return seq, hash, err
+}
+
+func (repo *AuditRepository) queryEntries(ctx context.Context, query string, args ...interface{}) ([]model.AuditEntry, error) {
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.AuditEntry
+	for rows.Next() {
+		var entry model.AuditEntry
+		var before, after sql.NullString
+		var createdAt []uint8
+		err := rows.Scan(&entry.Seq, &entry.ID, &entry.ActorID, &entry.Action, &entry.EntityType, &entry.EntityID, &before, &after,
+			&createdAt, &entry.PrevHash, &entry.Hash)
+		if err != nil {
+			return nil, err
+		}
+		entry.Before = before.String
+		entry.After = after.String
+		if entry.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("error parsing created_at: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type AuthTokenRepository struct {
+	db *sql.DB
+}
+
+// NewAuthTokenRepository creates an AuthTokenRepository backed by MySQL
+func NewAuthTokenRepository(db *sql.DB) interfaces.AuthTokenRepository {
+	return &AuthTokenRepository{db: db}
+}
+
+// SaveToken stores a newly issued token
+func (repo *AuthTokenRepository) SaveToken(ctx context.Context, token model.AuthToken) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO auth_tokens (id, user_id, purpose, token_hash, expires_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, token.ID, token.UserID, token.Purpose, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+// GetTokenByHash looks a token up by the SHA-256 hash of its code
+func (repo *AuthTokenRepository) GetTokenByHash(ctx context.Context, tokenHash string) (*model.AuthToken, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, user_id, purpose, token_hash, expires_at, used_at, created_at FROM auth_tokens WHERE token_hash = ?`
+	var token model.AuthToken
+	var expiresAt, usedAt, createdAt []uint8
+	err := conn(ctx, repo.db).QueryRowContext(ctx, query, tokenHash).
+		Scan(&token.ID, &token.UserID, &token.Purpose, &token.TokenHash, &expiresAt, &usedAt, &createdAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("token not found")
+		}
+		return nil, err
+	}
+	if token.ExpiresAt, err = util.ParseTime(expiresAt); err != nil {
+		return nil, fmt.Errorf("error parsing expires_at: %v", err)
+	}
+	if token.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("error parsing created_at: %v", err)
+	}
+	if usedAt != nil {
+		used, err := util.ParseTime(usedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing used_at: %v", err)
+		}
+		token.UsedAt = &used
+	}
+	return &token, nil
+}
+
+// MarkTokenUsed redeems a token. A token that was used in the meantime is reported as a conflict so that
+// a code can never be redeemed twice.
+func (repo *AuthTokenRepository) MarkTokenUsed(ctx context.Context, tokenID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE auth_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL"
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, time.Now(), tokenID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return &model.ConflictError{Entity: "token", ID: tokenID}
+	}
+	return nil
+}
+
+// DeleteTokens removes every token of a user, used or not
+func (repo *AuthTokenRepository) DeleteTokens(ctx context.Context, userID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM auth_tokens WHERE user_id = ?", userID)
+	return err
+}
+
+// InvalidateTokens marks every unused token of a user for the given purpose as used
+func (repo *AuthTokenRepository) InvalidateTokens(ctx context.Context, userID, purpose string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE auth_tokens SET used_at = ? WHERE user_id = ? AND purpose = ? AND used_at IS NULL"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, time.Now(), userID, purpose)
+	return err
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+)
+
+type CategoryRepository struct {
+	db *sql.DB
+}
+
+// NewCategoryRepository creates a CategoryRepository backed by MySQL
+func NewCategoryRepository(db *sql.DB) interfaces.CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// SaveCategory inserts a new category, an empty ParentID makes it a top-level category
+func (repo *CategoryRepository) SaveCategory(ctx context.Context, category model.Category) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO categories (id, name, description, parent_id) VALUES (?, ?, ?, ?)"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, category.ID, category.Name, category.Description, nullableString(category.ParentID))
+	return err
+}
+
+// GetCategoryByID retrieves a category by its ID
+func (repo *CategoryRepository) GetCategoryByID(ctx context.Context, categoryID string) (*model.Category, error) {
+	return repo.getCategory(ctx, "id", categoryID)
+}
+
+// GetCategoryByName retrieves a category by its name
+func (repo *CategoryRepository) GetCategoryByName(ctx context.Context, name string) (*model.Category, error) {
+	return repo.getCategory(ctx, "name", name)
+}
+
+func (repo *CategoryRepository) getCategory(ctx context.Context, column, value string) (*model.Category, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT id, name, description, parent_id FROM categories WHERE " + column + " = ?"
+	var category model.Category
+	var parentID sql.NullString
+	err := conn(ctx, repo.db).QueryRowContext(ctx, query, value).Scan(&category.ID, &category.Name, &category.Description, &parentID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("category not found")
+		}
+		return nil, err
+	}
+	category.ParentID = parentID.String
+	return &category, nil
+}
+
+// GetAllCategories retrieves the whole catalogue ordered by name
+func (repo *CategoryRepository) GetAllCategories(ctx context.Context) ([]model.Category, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, "SELECT id, name, description, parent_id FROM categories ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []model.Category
+	for rows.Next() {
+		var category model.Category
+		var parentID sql.NullString
+		if err := rows.Scan(&category.ID, &category.Name, &category.Description, &parentID); err != nil {
+			return nil, err
+		}
+		category.ParentID = parentID.String
+		categories = append(categories, category)
+	}
+	return categories, rows.Err()
+}
+
+// UpdateCategory saves the name, description and parent of a category
+func (repo *CategoryRepository) UpdateCategory(ctx context.Context, category model.Category) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE categories SET name = ?, description = ?, parent_id = ? WHERE id = ?"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, category.Name, category.Description, nullableString(category.ParentID), category.ID)
+	return err
+}
+
+// RemoveCategory deletes a category
+func (repo *CategoryRepository) RemoveCategory(ctx context.Context, categoryID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	result, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM categories WHERE id = ?", categoryID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("category not found")
+	}
+	return nil
+}
+
+// nullableString stores an empty reference as NULL
+func nullableString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
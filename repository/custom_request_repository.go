@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type CustomRequestRepository struct {
+	db *sql.DB
+}
+
+// NewCustomRequestRepository creates a CustomRequestRepository backed by MySQL
+func NewCustomRequestRepository(db *sql.DB) interfaces.CustomRequestRepository {
+	return &CustomRequestRepository{db: db}
+}
+
+const customRequestColumns = `id, householder_id, householder_name, householder_address, service_name, requested_time,
+	scheduled_time, status, category_id, resolution_note, resolved_by, resolved_at`
+
+// SaveCustomRequest adds a request to the triage queue
+func (repo *CustomRequestRepository) SaveCustomRequest(ctx context.Context, request model.CustomRequest) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	address, err := sealField(ctx, request.HouseholderAddress)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO custom_requests (id, householder_id, householder_name, householder_address, service_name, requested_time, scheduled_time, status)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = conn(ctx, repo.db).ExecContext(ctx, query, request.ID, request.HouseholderID, request.HouseholderName, address,
+		request.ServiceName, request.RequestedTime, request.ScheduledTime, request.Status)
+	return err
+}
+
+// GetCustomRequestByID retrieves a custom request by its ID
+func (repo *CustomRequestRepository) GetCustomRequestByID(ctx context.Context, requestID string) (*model.CustomRequest, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + customRequestColumns + " FROM custom_requests WHERE id = ?"
+	request, err := scanCustomRequest(ctx, conn(ctx, repo.db).QueryRowContext(ctx, query, requestID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("custom request not found")
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// GetCustomRequestsByStatus lists the custom requests with the given status, oldest first
+func (repo *CustomRequestRepository) GetCustomRequestsByStatus(ctx context.Context, status string) ([]model.CustomRequest, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + customRequestColumns + " FROM custom_requests WHERE status = ? ORDER BY requested_time, id"
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, status)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []model.CustomRequest
+	for rows.Next() {
+		request, err := scanCustomRequest(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, *request)
+	}
+	return requests, rows.Err()
+}
+
+// GetCustomRequestsByHouseholderID lists the custom requests of a householder, oldest first
+func (repo *CustomRequestRepository) GetCustomRequestsByHouseholderID(ctx context.Context, householderID string) ([]model.CustomRequest, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + customRequestColumns + " FROM custom_requests WHERE householder_id = ? ORDER BY requested_time, id"
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, householderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var requests []model.CustomRequest
+	for rows.Next() {
+		request, err := scanCustomRequest(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, *request)
+	}
+	return requests, rows.Err()
+}
+
+// AnonymiseHouseholder replaces the name and address copied onto the custom requests of a householder
+func (repo *CustomRequestRepository) AnonymiseHouseholder(ctx context.Context, householderID, name string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE custom_requests SET householder_name = ?, householder_address = '' WHERE householder_id = ?"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, name, householderID)
+	return err
+}
+
+// ResolveCustomRequest records the outcome of an open request. A request that has been resolved in the
+// meantime is reported as a conflict so that it is never resolved twice.
+func (repo *CustomRequestRepository) ResolveCustomRequest(ctx context.Context, request model.CustomRequest) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE custom_requests SET status = ?, category_id = ?, resolution_note = ?, resolved_by = ?, resolved_at = ?
+		WHERE id = ? AND status = ?`
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, request.Status, nullableString(request.CategoryID), request.ResolutionNote,
+		nullableString(request.ResolvedBy), request.ResolvedAt, request.ID, model.CustomRequestOpen)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return &model.ConflictError{Entity: "custom request", ID: request.ID}
+	}
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanCustomRequest(ctx context.Context, row rowScanner) (*model.CustomRequest, error) {
+	var request model.CustomRequest
+	var requestedTime, scheduledTime, resolvedAt []uint8
+	var categoryID, note, resolvedBy sql.NullString
+	err := row.Scan(&request.ID, &request.HouseholderID, &request.HouseholderName, &request.HouseholderAddress, &request.ServiceName,
+		&requestedTime, &scheduledTime, &request.Status, &categoryID, &note, &resolvedBy, &resolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	if request.HouseholderAddress, err = openField(ctx, request.HouseholderAddress); err != nil {
+		return nil, err
+	}
+
+	if request.RequestedTime, err = util.ParseTime(requestedTime); err != nil {
+		return nil, fmt.Errorf("error parsing requested_time: %v", err)
+	}
+	if request.ScheduledTime, err = util.ParseTime(scheduledTime); err != nil {
+		return nil, fmt.Errorf("error parsing scheduled_time: %v", err)
+	}
+	if resolvedAt != nil {
+		resolved, err := util.ParseTime(resolvedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing resolved_at: %v", err)
+		}
+		request.ResolvedAt = &resolved
+	}
+	request.CategoryID = categoryID.String
+	request.ResolutionNote = note.String
+	request.ResolvedBy = resolvedBy.String
+	return &request, nil
+}
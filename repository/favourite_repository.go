@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type FavouriteRepository struct {
+	db *sql.DB
+}
+
+// NewFavouriteRepository creates a FavouriteRepository backed by MySQL
+func NewFavouriteRepository(db *sql.DB) interfaces.FavouriteRepository {
+	return &FavouriteRepository{db: db}
+}
+
+// AddFavourite saves a provider to the householder's favourites. Saving a favourite twice keeps the first one.
+func (repo *FavouriteRepository) AddFavourite(ctx context.Context, favourite model.FavouriteProvider) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT IGNORE INTO favourite_providers (householder_id, provider_id, added_at) VALUES (?, ?, ?)"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, favourite.HouseholderID, favourite.ProviderID, favourite.AddedAt)
+	return err
+}
+
+// RemoveFavourite takes a provider off the householder's favourites
+func (repo *FavouriteRepository) RemoveFavourite(ctx context.Context, householderID, providerID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE FROM favourite_providers WHERE householder_id = ? AND provider_id = ?"
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, householderID, providerID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("favourite not found")
+	}
+	return nil
+}
+
+// GetFavourites lists the householder's favourite providers by name, with whether they take new jobs
+func (repo *FavouriteRepository) GetFavourites(ctx context.Context, householderID string) ([]model.FavouriteProvider, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT f.householder_id, f.provider_id, u.name, sp.availability, f.added_at
+	FROM favourite_providers f
+	INNER JOIN users u ON u.id = f.provider_id
+	INNER JOIN service_providers sp ON sp.user_id = f.provider_id
+	WHERE f.householder_id = ?
+	ORDER BY u.name, f.provider_id`
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, householderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var favourites []model.FavouriteProvider
+	for rows.Next() {
+		var favourite model.FavouriteProvider
+		var addedAt []uint8
+		err := rows.Scan(&favourite.HouseholderID, &favourite.ProviderID, &favourite.ProviderName, &favourite.Available, &addedAt)
+		if err != nil {
+			return nil, err
+		}
+		if favourite.AddedAt, err = util.ParseTime(addedAt); err != nil {
+			return nil, fmt.Errorf("error parsing added_at: %v", err)
+		}
+		favourites = append(favourites, favourite)
+	}
+	return favourites, rows.Err()
+}
+
+// DeleteFavouritesByUserID removes the favourites a householder saved and, for a provider, every favourite
+// that points at them
+func (repo *FavouriteRepository) DeleteFavouritesByUserID(ctx context.Context, userID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE FROM favourite_providers WHERE householder_id = ? OR provider_id = ?"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, userID, userID)
+	return err
+}
@@ -0,0 +1,281 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"serviceNest/interfaces"
+	"strings"
+	"unicode"
+)
+
+// fieldCipher encrypts addresses and contact numbers before they are written and decrypts them after they
+// are read; configured at startup. Without one the columns hold plaintext.
+var fieldCipher interfaces.FieldCipher
+
+// SetFieldCipher sets the cipher every repository uses for personal data columns
+func SetFieldCipher(cipher interfaces.FieldCipher) {
+	fieldCipher = cipher
+}
+
+// reencryptBatchSize is how many rows a table is read in at a time when values are moved to a new key
+const reencryptBatchSize = 500
+
+// encryptedTable names a table and its encrypted columns
+type encryptedTable struct {
+	name    string
+	columns []string
+	// indexed maps a column to the column holding its blind index
+	indexed map[string]string
+}
+
+var encryptedTables = []encryptedTable{
+	{name: "users", columns: []string{"address", "contact"}, indexed: map[string]string{"contact": "contact_index"}},
+	{name: "service_provider_details", columns: []string{"contact", "address"}},
+	{name: "service_requests", columns: []string{"householder_address"}},
+	{name: "custom_requests", columns: []string{"householder_address"}},
+}
+
+func sealField(ctx context.Context, value string) (string, error) {
+	if fieldCipher == nil {
+		return value, nil
+	}
+	return fieldCipher.Encrypt(ctx, value)
+}
+
+func openField(ctx context.Context, stored string) (string, error) {
+	if fieldCipher == nil {
+		return stored, nil
+	}
+	return fieldCipher.Decrypt(ctx, stored)
+}
+
+// sealFields encrypts each value in place
+func sealFields(ctx context.Context, values ...*string) error {
+	for _, value := range values {
+		sealed, err := sealField(ctx, *value)
+		if err != nil {
+			return err
+		}
+		*value = sealed
+	}
+	return nil
+}
+
+// openFields decrypts each value in place
+func openFields(ctx context.Context, values ...*string) error {
+	for _, value := range values {
+		opened, err := openField(ctx, *value)
+		if err != nil {
+			return err
+		}
+		*value = opened
+	}
+	return nil
+}
+
+// sealNullableField encrypts an optional value, nil stays nil
+func sealNullableField(ctx context.Context, value *string) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	sealed, err := sealField(ctx, *value)
+	if err != nil {
+		return nil, err
+	}
+	return &sealed, nil
+}
+
+// openNullableField decrypts an optional value in place
+func openNullableField(ctx context.Context, value *string) error {
+	if value == nil {
+		return nil
+	}
+	return openFields(ctx, value)
+}
+
+// normalizeContact keeps only the digits of a contact number so that "98765 43210" finds "9876543210"
+func normalizeContact(contact string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return r
+		}
+		return -1
+	}, contact)
+}
+
+// contactIndex returns the blind index stored next to an encrypted contact number. It is NULL when
+// encryption is off or there is no number, lookups then compare the contact column itself.
+func contactIndex(ctx context.Context, contact string) (*string, error) {
+	contact = normalizeContact(contact)
+	if fieldCipher == nil || contact == "" {
+		return nil, nil
+	}
+	index, err := fieldCipher.BlindIndex(ctx, contact)
+	if err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+type FieldEncryptionRepository struct {
+	db *sql.DB
+}
+
+func NewFieldEncryptionRepository(db *sql.DB) interfaces.FieldEncryptionRepository {
+	return &FieldEncryptionRepository{db: db}
+}
+
+// Reencrypt rewraps every value that is not under the current key and encrypts values stored before
+// encryption was turned on. Rows are updated only if they still hold what was read, a row written in the
+// meantime already uses the current key.
+func (repo *FieldEncryptionRepository) Reencrypt(ctx context.Context) (int, error) {
+	if fieldCipher == nil {
+		return 0, fmt.Errorf("field encryption is not configured")
+	}
+	changed := 0
+	for _, table := range encryptedTables {
+		err := repo.eachBatch(ctx, table, func(ctx context.Context, id string, values []string) error {
+			updated := make([]string, len(values))
+			rowChanged := false
+			for i, value := range values {
+				rewrapped, ok, err := fieldCipher.Rewrap(ctx, value)
+				if err != nil {
+					return fmt.Errorf("%s %s: %v", table.name, id, err)
+				}
+				updated[i] = rewrapped
+				rowChanged = rowChanged || ok
+			}
+			if !rowChanged {
+				return nil
+			}
+			ok, err := repo.updateRow(ctx, table, id, values, updated)
+			if ok {
+				changed++
+			}
+			return err
+		})
+		if err != nil {
+			return changed, err
+		}
+	}
+	return changed, nil
+}
+
+// KeyUsage counts the non-empty values of every encrypted column by the key they are encrypted with
+func (repo *FieldEncryptionRepository) KeyUsage(ctx context.Context) (map[string]int, error) {
+	if fieldCipher == nil {
+		return nil, fmt.Errorf("field encryption is not configured")
+	}
+	usage := map[string]int{}
+	for _, table := range encryptedTables {
+		err := repo.eachBatch(ctx, table, func(ctx context.Context, id string, values []string) error {
+			for _, value := range values {
+				if value != "" {
+					usage[fieldCipher.KeyID(value)]++
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return usage, nil
+}
+
+// eachBatch reads the encrypted columns of table in primary key order, a batch at a time so that large
+// tables neither hold a long transaction nor run into the query timeout
+func (repo *FieldEncryptionRepository) eachBatch(ctx context.Context, table encryptedTable, fn func(ctx context.Context, id string, values []string) error) error {
+	selected := make([]string, len(table.columns))
+	for i, column := range table.columns {
+		selected[i] = "COALESCE(" + column + ", '')"
+	}
+	query := fmt.Sprintf("SELECT id, %s FROM %s WHERE id > ? ORDER BY id LIMIT ?", strings.Join(selected, ", "), table.name)
+
+	lastID := ""
+	for {
+		ids, rowValues, err := repo.readBatch(ctx, query, lastID, len(table.columns))
+		if err != nil {
+			return err
+		}
+		for i, id := range ids {
+			if err := fn(ctx, id, rowValues[i]); err != nil {
+				return err
+			}
+		}
+		if len(ids) < reencryptBatchSize {
+			return nil
+		}
+		lastID = ids[len(ids)-1]
+	}
+}
+
+func (repo *FieldEncryptionRepository) readBatch(ctx context.Context, query, afterID string, columns int) ([]string, [][]string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, afterID, reencryptBatchSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	var rowValues [][]string
+	for rows.Next() {
+		var id string
+		values := make([]string, columns)
+		dest := []interface{}{&id}
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, id)
+		rowValues = append(rowValues, values)
+	}
+	return ids, rowValues, rows.Err()
+}
+
+// updateRow writes the rewrapped values if the row still holds the old ones. Blind indexes are refreshed
+// with them, they are missing for values stored before encryption was turned on.
+func (repo *FieldEncryptionRepository) updateRow(ctx context.Context, table encryptedTable, id string, old, updated []string) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var set, match []string
+	var setArgs, matchArgs []interface{}
+	for i, column := range table.columns {
+		set = append(set, column+" = ?")
+		setArgs = append(setArgs, updated[i])
+		match = append(match, "COALESCE("+column+", '') = ?")
+		matchArgs = append(matchArgs, old[i])
+
+		if indexColumn, ok := table.indexed[column]; ok {
+			value, err := openField(ctx, updated[i])
+			if err != nil {
+				return false, err
+			}
+			index, err := contactIndex(ctx, value)
+			if err != nil {
+				return false, err
+			}
+			set = append(set, indexColumn+" = ?")
+			setArgs = append(setArgs, index)
+		}
+	}
+
+	query := fmt.Sprintf("UPDATE %s SET %s WHERE id = ? AND %s", table.name, strings.Join(set, ", "), strings.Join(match, " AND "))
+	args := append(append(setArgs, id), matchArgs...)
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
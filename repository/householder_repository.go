@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"serviceNest/interfaces"
 	"serviceNest/model"
@@ -17,21 +18,34 @@ func NewHouseholderRepository(client *sql.DB) interfaces.HouseholderRepository {
 	}
 }
 
-func (repo *MySQLHouseholderRepository) SaveHouseholder(householder *model.Householder) error {
-	query := "INSERT INTO users (id, name, email, password, role, address, contact, latitude, longitude) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"
-	_, err := repo.db.Exec(query, householder.ID, householder.Name, householder.Email, householder.Password, householder.Role, householder.Address, householder.Contact, householder.Latitude, householder.Longitude)
+func (repo *MySQLHouseholderRepository) SaveHouseholder(ctx context.Context, householder *model.Householder) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	address, contact, index, err := sealUserFields(ctx, &householder.User)
+	if err != nil {
+		return err
+	}
+	query := "INSERT INTO users (id, name, email, password, role, address, contact, contact_index, latitude, longitude) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	_, err = conn(ctx, repo.db).ExecContext(ctx, query, householder.ID, householder.Name, householder.Email, householder.Password, householder.Role, address, contact, index, householder.Latitude, householder.Longitude)
 	return err
 }
 
-func (repo *MySQLHouseholderRepository) GetHouseholderByID(id string) (*model.Householder, error) {
+func (repo *MySQLHouseholderRepository) GetHouseholderByID(ctx context.Context, id string) (*model.Householder, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT id, name, email, password, role, address, contact, latitude, longitude FROM users WHERE id = ?"
-	row := repo.db.QueryRow(query, id)
+	row := conn(ctx, repo.db).QueryRowContext(ctx, query, id)
 
 	var householder model.Householder
 	err := row.Scan(&householder.ID, &householder.Name, &householder.Email, &householder.Password, &householder.Role, &householder.Address, &householder.Contact, &householder.Latitude, &householder.Longitude)
 	if err != nil {
 		return nil, err
 	}
+	if err := openFields(ctx, &householder.Address, &householder.Contact); err != nil {
+		return nil, err
+	}
 
 	return &householder, nil
 }
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+)
+
+// IndexedServiceRepository keeps the search index in sync with every service write. Reads are
+// served by the wrapped repository; index updates are applied once the write has committed.
+type IndexedServiceRepository struct {
+	interfaces.ServiceRepository
+	index        interfaces.ServiceIndex
+	providerRepo interfaces.ServiceProviderRepository
+	userRepo     interfaces.UserRepository
+}
+
+// NewIndexedServiceRepository wraps serviceRepo so that its writes are reflected in index
+func NewIndexedServiceRepository(serviceRepo interfaces.ServiceRepository, index interfaces.ServiceIndex, providerRepo interfaces.ServiceProviderRepository, userRepo interfaces.UserRepository) *IndexedServiceRepository {
+	return &IndexedServiceRepository{
+		ServiceRepository: serviceRepo,
+		index:             index,
+		providerRepo:      providerRepo,
+		userRepo:          userRepo,
+	}
+}
+
+// Reindex rebuilds the index from every stored service
+func (repo *IndexedServiceRepository) Reindex(ctx context.Context) error {
+	services, err := repo.ServiceRepository.GetAllServices(ctx, model.QueryOptions{})
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		repo.indexService(ctx, service)
+	}
+	return nil
+}
+
+// ReindexProvider refreshes the services of one provider, used when the provider can no longer or can
+// again be booked
+func (repo *IndexedServiceRepository) ReindexProvider(ctx context.Context, providerID string) error {
+	services, err := repo.ServiceRepository.GetServiceByProviderID(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		repo.indexService(ctx, service)
+	}
+	return nil
+}
+
+func (repo *IndexedServiceRepository) SaveService(ctx context.Context, service model.Service) error {
+	if err := repo.ServiceRepository.SaveService(ctx, service); err != nil {
+		return err
+	}
+	repo.indexService(ctx, service)
+	return nil
+}
+
+func (repo *IndexedServiceRepository) SaveAllServices(ctx context.Context, services []model.Service) error {
+	if err := repo.ServiceRepository.SaveAllServices(ctx, services); err != nil {
+		return err
+	}
+	for _, service := range services {
+		repo.indexService(ctx, service)
+	}
+	return nil
+}
+
+func (repo *IndexedServiceRepository) UpdateService(ctx context.Context, providerID string, updatedService model.Service) error {
+	if err := repo.ServiceRepository.UpdateService(ctx, providerID, updatedService); err != nil {
+		return err
+	}
+	// Reload the row since an update only carries the edited fields
+	service, err := repo.ServiceRepository.GetServiceByID(ctx, updatedService.ID)
+	if err != nil {
+		slog.Warn("could not reload service for the search index", "service_id", updatedService.ID, "error", err)
+		return nil
+	}
+	repo.indexService(ctx, *service)
+	return nil
+}
+
+func (repo *IndexedServiceRepository) RemoveService(ctx context.Context, serviceID string) error {
+	if err := repo.ServiceRepository.RemoveService(ctx, serviceID); err != nil {
+		return err
+	}
+	afterCommit(ctx, func() { repo.index.Remove(serviceID) })
+	return nil
+}
+
+func (repo *IndexedServiceRepository) RemoveServiceByProviderID(ctx context.Context, providerID string, serviceID string) error {
+	if err := repo.ServiceRepository.RemoveServiceByProviderID(ctx, providerID, serviceID); err != nil {
+		return err
+	}
+	afterCommit(ctx, func() { repo.index.Remove(serviceID) })
+	return nil
+}
+
+// RenameCategory reindexes the services of the renamed category, the category is one of the indexed fields
+func (repo *IndexedServiceRepository) RenameCategory(ctx context.Context, oldName, newName string) error {
+	if err := repo.ServiceRepository.RenameCategory(ctx, oldName, newName); err != nil {
+		return err
+	}
+	services, err := repo.ServiceRepository.GetAllServices(ctx, model.QueryOptions{Category: newName})
+	if err != nil {
+		return err
+	}
+	for _, service := range services {
+		repo.indexService(ctx, service)
+	}
+	return nil
+}
+
+// indexService builds the search document of a service and schedules it for indexing. Services
+// without a provider are placeholders and services of unverified or deactivated providers are hidden,
+// none of them is searchable. Failing to load the user never fails the write, the service is then
+// indexed without provider details.
+func (repo *IndexedServiceRepository) indexService(ctx context.Context, service model.Service) {
+	if service.ProviderID == "" {
+		return
+	}
+
+	doc := model.SearchDocument{Service: service}
+	provider, err := repo.providerRepo.GetProviderByID(ctx, service.ProviderID)
+	if err != nil {
+		slog.Warn("could not load provider for the search index", "provider_id", service.ProviderID, "error", err)
+		return
+	}
+	if !provider.IsVerified() || !provider.IsActive {
+		afterCommit(ctx, func() { repo.index.Remove(service.ID) })
+		return
+	}
+	doc.ProviderRating = provider.Rating
+	if user, err := repo.userRepo.GetUserByID(ctx, service.ProviderID); err == nil {
+		doc.ProviderName = user.Name
+		doc.Latitude = user.Latitude
+		doc.Longitude = user.Longitude
+	} else {
+		slog.Warn("could not load provider for the search index", "provider_id", service.ProviderID, "error", err)
+	}
+
+	afterCommit(ctx, func() { repo.index.Index(doc) })
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type LoginAttemptRepository struct {
+	db *sql.DB
+}
+
+// NewLoginAttemptRepository creates a LoginAttemptRepository backed by MySQL
+func NewLoginAttemptRepository(db *sql.DB) interfaces.LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// SaveAuditEntry appends an entry to the login audit
+func (repo *LoginAttemptRepository) SaveAuditEntry(ctx context.Context, entry model.LoginAuditEntry) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO login_audit (id, email, user_id, source, outcome, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, entry.ID, entry.Email, nullableString(entry.UserID), entry.Source, entry.Outcome, entry.CreatedAt)
+	return err
+}
+
+// GetAuditEntries lists the most recent login audit entries for an email address, newest first
+func (repo *LoginAttemptRepository) GetAuditEntries(ctx context.Context, email string, limit int) ([]model.LoginAuditEntry, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, email, user_id, source, outcome, created_at FROM login_audit WHERE email = ? ORDER BY created_at DESC, id LIMIT ?`
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, email, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.LoginAuditEntry
+	for rows.Next() {
+		var entry model.LoginAuditEntry
+		var userID sql.NullString
+		var createdAt []uint8
+		if err := rows.Scan(&entry.ID, &entry.Email, &userID, &entry.Source, &entry.Outcome, &createdAt); err != nil {
+			return nil, err
+		}
+		entry.UserID = userID.String
+		if entry.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("error parsing created_at: %v", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// AnonymiseAuditEntries moves the entries of an address to erasedEmail and forgets where the attempts came
+// from. The admin who unlocked an account stays on record.
+func (repo *LoginAttemptRepository) AnonymiseAuditEntries(ctx context.Context, email, erasedEmail string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE login_audit SET email = ?, source = CASE WHEN source LIKE 'admin:%' THEN source ELSE '' END WHERE email = ?`
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, erasedEmail, email)
+	return err
+}
+
+// GetThrottle returns the failure count of an account or source. One without failures on record comes back
+// with a zero count.
+func (repo *LoginAttemptRepository) GetThrottle(ctx context.Context, scope, key string) (*model.LoginThrottle, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT scope, throttle_key, failures, last_failure_at, locked_until FROM login_throttles WHERE scope = ? AND throttle_key = ?`
+	throttle, err := scanThrottle(conn(ctx, repo.db).QueryRowContext(ctx, query, scope, key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return &model.LoginThrottle{Scope: scope, Key: key}, nil
+	}
+	return throttle, err
+}
+
+// IncrementFailures counts a failed login and returns the new count. Failures from before windowStart are
+// forgotten, the count starts again at one.
+func (repo *LoginAttemptRepository) IncrementFailures(ctx context.Context, scope, key string, now, windowStart time.Time) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO login_throttles (scope, throttle_key, failures, last_failure_at) VALUES (?, ?, 1, ?)
+		ON DUPLICATE KEY UPDATE failures = IF(last_failure_at < ?, 1, failures + 1), last_failure_at = VALUES(last_failure_at)`
+	if _, err := conn(ctx, repo.db).ExecContext(ctx, query, scope, key, now, windowStart); err != nil {
+		return 0, err
+	}
+
+	var failures int
+	err := conn(ctx, repo.db).QueryRowContext(ctx, "SELECT failures FROM login_throttles WHERE scope = ? AND throttle_key = ?", scope, key).Scan(&failures)
+	return failures, err
+}
+
+// LockThrottle locks an account or source until the given time and starts counting failures afresh
+func (repo *LoginAttemptRepository) LockThrottle(ctx context.Context, scope, key string, until time.Time) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE login_throttles SET failures = 0, locked_until = ? WHERE scope = ? AND throttle_key = ?"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, until, scope, key)
+	return err
+}
+
+// ResetThrottle forgets the failures and any lock of an account or source
+func (repo *LoginAttemptRepository) ResetThrottle(ctx context.Context, scope, key string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM login_throttles WHERE scope = ? AND throttle_key = ?", scope, key)
+	return err
+}
+
+// GetLockedThrottles lists the accounts or sources that are locked at the given time, longest lock first
+func (repo *LoginAttemptRepository) GetLockedThrottles(ctx context.Context, scope string, now time.Time) ([]model.LoginThrottle, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT scope, throttle_key, failures, last_failure_at, locked_until FROM login_throttles
+		WHERE scope = ? AND locked_until > ? ORDER BY locked_until DESC`
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, scope, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var throttles []model.LoginThrottle
+	for rows.Next() {
+		throttle, err := scanThrottle(rows)
+		if err != nil {
+			return nil, err
+		}
+		throttles = append(throttles, *throttle)
+	}
+	return throttles, rows.Err()
+}
+
+func scanThrottle(row rowScanner) (*model.LoginThrottle, error) {
+	var throttle model.LoginThrottle
+	var lastFailureAt, lockedUntil []uint8
+	if err := row.Scan(&throttle.Scope, &throttle.Key, &throttle.Failures, &lastFailureAt, &lockedUntil); err != nil {
+		return nil, err
+	}
+	var err error
+	if throttle.LastFailureAt, err = util.ParseTime(lastFailureAt); err != nil {
+		return nil, fmt.Errorf("error parsing last_failure_at: %v", err)
+	}
+	if lockedUntil != nil {
+		until, err := util.ParseTime(lockedUntil)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing locked_until: %v", err)
+		}
+		throttle.LockedUntil = &until
+	}
+	return &throttle, nil
+}
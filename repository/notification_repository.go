@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type NotificationRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationRepository creates a NotificationRepository backed by MySQL
+func NewNotificationRepository(db *sql.DB) interfaces.NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// SaveNotification adds a message to the inbox of a user
+func (repo *NotificationRepository) SaveNotification(ctx context.Context, notification model.Notification) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO notifications (id, user_id, message, created_at, is_read) VALUES (?, ?, ?, ?, ?)"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, notification.ID, notification.UserID, notification.Message, notification.CreatedAt, notification.Read)
+	return err
+}
+
+// GetNotificationsByUserID lists the inbox of a user, newest first
+func (repo *NotificationRepository) GetNotificationsByUserID(ctx context.Context, userID string, unreadOnly bool) ([]model.Notification, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT id, user_id, message, created_at, is_read FROM notifications WHERE user_id = ?"
+	if unreadOnly {
+		query += " AND is_read = FALSE"
+	}
+	query += " ORDER BY created_at DESC, id"
+
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var notifications []model.Notification
+	for rows.Next() {
+		var notification model.Notification
+		var createdAt []uint8
+		if err := rows.Scan(&notification.ID, &notification.UserID, &notification.Message, &createdAt, &notification.Read); err != nil {
+			return nil, err
+		}
+		if notification.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+			return nil, fmt.Errorf("error parsing created_at: %v", err)
+		}
+		notifications = append(notifications, notification)
+	}
+	return notifications, rows.Err()
+}
+
+// MarkNotificationsRead marks the whole inbox of a user as read
+func (repo *NotificationRepository) MarkNotificationsRead(ctx context.Context, userID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.db).ExecContext(ctx, "UPDATE notifications SET is_read = TRUE WHERE user_id = ? AND is_read = FALSE", userID)
+	return err
+}
+
+// DeleteNotifications empties the inbox of a user
+func (repo *NotificationRepository) DeleteNotifications(ctx context.Context, userID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM notifications WHERE user_id = ?", userID)
+	return err
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type ProviderDocumentRepository struct {
+	db *sql.DB
+}
+
+// NewProviderDocumentRepository creates a ProviderDocumentRepository backed by MySQL
+func NewProviderDocumentRepository(db *sql.DB) interfaces.ProviderDocumentRepository {
+	return &ProviderDocumentRepository{db: db}
+}
+
+const providerDocumentColumns = "id, provider_id, type, file_name, blob_key, size, uploaded_at"
+
+// SaveDocument records a verification document whose file has been stored in the blob store
+func (repo *ProviderDocumentRepository) SaveDocument(ctx context.Context, document model.ProviderDocument) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO provider_documents (" + providerDocumentColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?)"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, document.ID, document.ProviderID, document.Type, document.FileName,
+		document.BlobKey, document.Size, document.UploadedAt)
+	return err
+}
+
+// GetDocumentByID retrieves a verification document by its ID
+func (repo *ProviderDocumentRepository) GetDocumentByID(ctx context.Context, documentID string) (*model.ProviderDocument, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + providerDocumentColumns + " FROM provider_documents WHERE id = ?"
+	document, err := scanProviderDocument(conn(ctx, repo.db).QueryRowContext(ctx, query, documentID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("document not found")
+		}
+		return nil, err
+	}
+	return document, nil
+}
+
+// GetDocumentsByProviderID lists the documents a provider has submitted, oldest first
+func (repo *ProviderDocumentRepository) GetDocumentsByProviderID(ctx context.Context, providerID string) ([]model.ProviderDocument, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + providerDocumentColumns + " FROM provider_documents WHERE provider_id = ? ORDER BY uploaded_at, id"
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, providerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var documents []model.ProviderDocument
+	for rows.Next() {
+		document, err := scanProviderDocument(rows)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, *document)
+	}
+	return documents, rows.Err()
+}
+
+// DeleteDocumentsByProviderID forgets the documents of a provider. Their files have to be removed from the
+// blob store separately.
+func (repo *ProviderDocumentRepository) DeleteDocumentsByProviderID(ctx context.Context, providerID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM provider_documents WHERE provider_id = ?", providerID)
+	return err
+}
+
+func scanProviderDocument(row rowScanner) (*model.ProviderDocument, error) {
+	var document model.ProviderDocument
+	var uploadedAt []uint8
+	err := row.Scan(&document.ID, &document.ProviderID, &document.Type, &document.FileName, &document.BlobKey, &document.Size, &uploadedAt)
+	if err != nil {
+		return nil, err
+	}
+	if document.UploadedAt, err = util.ParseTime(uploadedAt); err != nil {
+		return nil, fmt.Errorf("error parsing uploaded_at: %v", err)
+	}
+	return &document, nil
+}
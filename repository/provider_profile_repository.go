@@ -0,0 +1,228 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"strings"
+)
+
+type ProviderProfileRepository struct {
+	db *sql.DB
+}
+
+// NewProviderProfileRepository creates a ProviderProfileRepository backed by MySQL
+func NewProviderProfileRepository(db *sql.DB) interfaces.ProviderProfileRepository {
+	return &ProviderProfileRepository{db: db}
+}
+
+const portfolioColumns = "id, provider_id, caption, file_name, blob_key, size, uploaded_at"
+
+// GetProfileDetails reads the bio, experience, languages and certifications of a provider. A provider who
+// has not written a profile yet gets empty details.
+func (repo *ProviderProfileRepository) GetProfileDetails(ctx context.Context, providerID string) (*model.ProviderProfileDetails, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	details := model.ProviderProfileDetails{ProviderID: providerID}
+	var languages string
+	var updatedAt []uint8
+	query := "SELECT bio, years_experience, languages, updated_at FROM provider_profiles WHERE provider_id = ?"
+	err := conn(ctx, repo.db).QueryRowContext(ctx, query, providerID).Scan(&details.Bio, &details.YearsExperience, &languages, &updatedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if err == nil {
+		if details.UpdatedAt, err = parseOptionalTime(updatedAt); err != nil {
+			return nil, err
+		}
+		if languages != "" {
+			details.Languages = strings.Split(languages, ",")
+		}
+	}
+
+	query = "SELECT id, provider_id, name, issuer, year FROM provider_certifications WHERE provider_id = ? ORDER BY year DESC, name"
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, providerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var certification model.Certification
+		if err := rows.Scan(&certification.ID, &certification.ProviderID, &certification.Name, &certification.Issuer, &certification.Year); err != nil {
+			return nil, err
+		}
+		details.Certifications = append(details.Certifications, certification)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &details, nil
+}
+
+// SaveProfileDetails creates or overwrites the bio, experience and languages of a provider. Certifications
+// are saved one by one with SaveCertification.
+func (repo *ProviderProfileRepository) SaveProfileDetails(ctx context.Context, details model.ProviderProfileDetails) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO provider_profiles (provider_id, bio, years_experience, languages, updated_at) VALUES (?, ?, ?, ?, ?)
+	ON DUPLICATE KEY UPDATE bio = VALUES(bio), years_experience = VALUES(years_experience), languages = VALUES(languages),
+	updated_at = VALUES(updated_at)`
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, details.ProviderID, details.Bio, details.YearsExperience,
+		strings.Join(details.Languages, ","), details.UpdatedAt)
+	return err
+}
+
+// SaveCertification adds a certification to a provider's profile
+func (repo *ProviderProfileRepository) SaveCertification(ctx context.Context, certification model.Certification) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO provider_certifications (id, provider_id, name, issuer, year) VALUES (?, ?, ?, ?, ?)"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, certification.ID, certification.ProviderID, certification.Name,
+		certification.Issuer, certification.Year)
+	return err
+}
+
+// GetCertificationByID retrieves a certification by its ID
+func (repo *ProviderProfileRepository) GetCertificationByID(ctx context.Context, certificationID string) (*model.Certification, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var certification model.Certification
+	query := "SELECT id, provider_id, name, issuer, year FROM provider_certifications WHERE id = ?"
+	err := conn(ctx, repo.db).QueryRowContext(ctx, query, certificationID).
+		Scan(&certification.ID, &certification.ProviderID, &certification.Name, &certification.Issuer, &certification.Year)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("certification not found")
+		}
+		return nil, err
+	}
+	return &certification, nil
+}
+
+// DeleteCertification removes one of the provider's certifications
+func (repo *ProviderProfileRepository) DeleteCertification(ctx context.Context, providerID, certificationID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE FROM provider_certifications WHERE id = ? AND provider_id = ?"
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, certificationID, providerID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("certification not found")
+	}
+	return nil
+}
+
+// SavePortfolioImage records a portfolio image whose file has been stored in the blob store
+func (repo *ProviderProfileRepository) SavePortfolioImage(ctx context.Context, image model.PortfolioImage) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO provider_portfolio (" + portfolioColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?)"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, image.ID, image.ProviderID, image.Caption, image.FileName,
+		image.BlobKey, image.Size, image.UploadedAt)
+	return err
+}
+
+// GetPortfolioImageByID retrieves a portfolio image by its ID
+func (repo *ProviderProfileRepository) GetPortfolioImageByID(ctx context.Context, imageID string) (*model.PortfolioImage, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + portfolioColumns + " FROM provider_portfolio WHERE id = ?"
+	image, err := scanPortfolioImage(conn(ctx, repo.db).QueryRowContext(ctx, query, imageID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("image not found")
+		}
+		return nil, err
+	}
+	return image, nil
+}
+
+// GetPortfolioImages lists the portfolio of a provider, newest first
+func (repo *ProviderProfileRepository) GetPortfolioImages(ctx context.Context, providerID string) ([]model.PortfolioImage, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + portfolioColumns + " FROM provider_portfolio WHERE provider_id = ? ORDER BY uploaded_at DESC, id"
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, providerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var images []model.PortfolioImage
+	for rows.Next() {
+		image, err := scanPortfolioImage(rows)
+		if err != nil {
+			return nil, err
+		}
+		images = append(images, *image)
+	}
+	return images, rows.Err()
+}
+
+// DeletePortfolioImage forgets one of the provider's portfolio images. Its file has to be removed from the
+// blob store separately.
+func (repo *ProviderProfileRepository) DeletePortfolioImage(ctx context.Context, providerID, imageID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE FROM provider_portfolio WHERE id = ? AND provider_id = ?"
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, imageID, providerID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("image not found")
+	}
+	return nil
+}
+
+// DeleteProfile removes the profile, certifications and portfolio of a provider. The files of the portfolio
+// have to be removed from the blob store separately.
+func (repo *ProviderProfileRepository) DeleteProfile(ctx context.Context, providerID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return inTransaction(ctx, repo.db, func(ctx context.Context) error {
+		for _, table := range []string{"provider_portfolio", "provider_certifications", "provider_profiles"} {
+			if _, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM "+table+" WHERE provider_id = ?", providerID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func scanPortfolioImage(row rowScanner) (*model.PortfolioImage, error) {
+	var image model.PortfolioImage
+	var uploadedAt []uint8
+	err := row.Scan(&image.ID, &image.ProviderID, &image.Caption, &image.FileName, &image.BlobKey, &image.Size, &uploadedAt)
+	if err != nil {
+		return nil, err
+	}
+	if image.UploadedAt, err = util.ParseTime(uploadedAt); err != nil {
+		return nil, fmt.Errorf("error parsing uploaded_at: %v", err)
+	}
+	return &image, nil
+}
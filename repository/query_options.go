@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"fmt"
+	"serviceNest/model"
+	"strings"
+)
+
+// listQuery collects the WHERE conditions and arguments of a listing built from model.QueryOptions
+type listQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (q *listQuery) where(condition string, args ...interface{}) {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+}
+
+// whereClause returns the combined conditions, or an empty string when there are none
+func (q *listQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+// orderByClause maps opts.SortBy onto one of the allowed columns; sort keys are never interpolated
+// directly so that they cannot be used for SQL injection. tieBreaker keeps pages stable.
+func orderByClause(opts model.QueryOptions, sortColumns map[string]string, defaultKey, tieBreaker string) (string, error) {
+	key := opts.SortBy
+	if key == "" {
+		key = defaultKey
+	}
+	column, ok := sortColumns[key]
+	if !ok {
+		return "", fmt.Errorf("unsupported sort key %q", key)
+	}
+
+	direction := "ASC"
+	if opts.Descending {
+		direction = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s, %s %s", column, direction, tieBreaker, direction), nil
+}
+
+// limitClause returns the LIMIT/OFFSET clause for opts. Offset is only honoured together with a limit.
+func limitClause(opts model.QueryOptions) (string, []interface{}, error) {
+	if opts.Limit < 0 || opts.Offset < 0 {
+		return "", nil, fmt.Errorf("page size and offset must not be negative")
+	}
+	if opts.Limit == 0 {
+		return "", nil, nil
+	}
+	return " LIMIT ? OFFSET ?", []interface{}{opts.Limit, opts.Offset}, nil
+}
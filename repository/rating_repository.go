@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type RatingRepository struct {
+	db *sql.DB
+}
+
+// NewRatingRepository creates a RatingRepository backed by MySQL
+func NewRatingRepository(db *sql.DB) interfaces.RatingRepository {
+	return &RatingRepository{db: db}
+}
+
+const ratingColumns = "provider_id, category, criterion, weighted_sum, weight_total, review_count, score_sum, anchor_at"
+
+// LockProviderRatings reads the rating totals of a provider for an update in the caller's transaction.
+// The provider row is locked as well, so that two reviews of a provider without totals yet cannot both
+// start from zero.
+func (repo *RatingRepository) LockProviderRatings(ctx context.Context, providerID string) ([]model.RatingAggregate, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var lockedID string
+	err := conn(ctx, repo.db).QueryRowContext(ctx, "SELECT user_id FROM service_providers WHERE user_id = ? FOR UPDATE", providerID).Scan(&lockedID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	return repo.queryRatings(ctx, "SELECT "+ratingColumns+" FROM provider_ratings WHERE provider_id = ? FOR UPDATE", providerID)
+}
+
+// GetProviderRatings reads the rating totals of a provider
+func (repo *RatingRepository) GetProviderRatings(ctx context.Context, providerID string) ([]model.RatingAggregate, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return repo.queryRatings(ctx, "SELECT "+ratingColumns+" FROM provider_ratings WHERE provider_id = ? ORDER BY category, criterion", providerID)
+}
+
+// SaveRatingAggregates inserts or overwrites rating totals
+func (repo *RatingRepository) SaveRatingAggregates(ctx context.Context, aggregates []model.RatingAggregate) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO provider_ratings (" + ratingColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE weighted_sum = VALUES(weighted_sum), weight_total = VALUES(weight_total),
+		review_count = VALUES(review_count), score_sum = VALUES(score_sum), anchor_at = VALUES(anchor_at)`
+	for _, aggregate := range aggregates {
+		_, err := conn(ctx, repo.db).ExecContext(ctx, query, aggregate.ProviderID, aggregate.Category, aggregate.Criterion,
+			aggregate.WeightedSum, aggregate.WeightTotal, aggregate.ReviewCount, aggregate.ScoreSum, aggregate.AnchorAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReplaceProviderRatings drops the rating totals of a provider and writes the given ones instead
+func (repo *RatingRepository) ReplaceProviderRatings(ctx context.Context, providerID string, aggregates []model.RatingAggregate) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return inTransaction(ctx, repo.db, func(ctx context.Context) error {
+		if _, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM provider_ratings WHERE provider_id = ?", providerID); err != nil {
+			return err
+		}
+		return repo.SaveRatingAggregates(ctx, aggregates)
+	})
+}
+
+// RenameCategory moves the reviews and rating totals kept under oldName to newName, so that the
+// category ratings of a renamed category carry on
+func (repo *RatingRepository) RenameCategory(ctx context.Context, oldName, newName string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return inTransaction(ctx, repo.db, func(ctx context.Context) error {
+		if _, err := conn(ctx, repo.db).ExecContext(ctx, "UPDATE reviews SET category = ? WHERE category = ?", newName, oldName); err != nil {
+			return err
+		}
+		_, err := conn(ctx, repo.db).ExecContext(ctx, "UPDATE provider_ratings SET category = ? WHERE category = ?", newName, oldName)
+		return err
+	})
+}
+
+// SetProviderRating stores the overall score used to rank and display a provider
+func (repo *RatingRepository) SetProviderRating(ctx context.Context, providerID string, rating float64) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.db).ExecContext(ctx, "UPDATE service_providers SET rating = ? WHERE user_id = ?", rating, providerID)
+	return err
+}
+
+// GetRatedProviderIDs lists the providers that have reviews or rating totals
+func (repo *RatingRepository) GetRatedProviderIDs(ctx context.Context) ([]string, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT provider_id FROM reviews UNION SELECT provider_id FROM provider_ratings ORDER BY provider_id"
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var providerIDs []string
+	for rows.Next() {
+		var providerID string
+		if err := rows.Scan(&providerID); err != nil {
+			return nil, err
+		}
+		providerIDs = append(providerIDs, providerID)
+	}
+	return providerIDs, rows.Err()
+}
+
+func (repo *RatingRepository) queryRatings(ctx context.Context, query string, args ...interface{}) ([]model.RatingAggregate, error) {
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var aggregates []model.RatingAggregate
+	for rows.Next() {
+		var aggregate model.RatingAggregate
+		var anchorAt []uint8
+		err := rows.Scan(&aggregate.ProviderID, &aggregate.Category, &aggregate.Criterion, &aggregate.WeightedSum,
+			&aggregate.WeightTotal, &aggregate.ReviewCount, &aggregate.ScoreSum, &anchorAt)
+		if err != nil {
+			return nil, err
+		}
+		if aggregate.AnchorAt, err = util.ParseTime(anchorAt); err != nil {
+			return nil, err
+		}
+		aggregates = append(aggregates, aggregate)
+	}
+	return aggregates, rows.Err()
+}
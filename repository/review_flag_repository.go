@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type ReviewFlagRepository struct {
+	db *sql.DB
+}
+
+// NewReviewFlagRepository creates a ReviewFlagRepository backed by MySQL
+func NewReviewFlagRepository(db *sql.DB) interfaces.ReviewFlagRepository {
+	return &ReviewFlagRepository{db: db}
+}
+
+// SaveFlag records a user's report about a review. A user can flag each review once.
+func (repo *ReviewFlagRepository) SaveFlag(ctx context.Context, flag model.ReviewFlag) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO review_flags (id, review_id, user_id, reason, created_at, resolved) VALUES (?, ?, ?, ?, ?, ?)"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, flag.ID, flag.ReviewID, flag.UserID, flag.Reason, flag.CreatedAt, flag.Resolved)
+	if isDuplicateKey(err) {
+		return model.ErrAlreadyFlagged
+	}
+	return err
+}
+
+// GetOpenFlags lists the flags no moderator has acted on yet, oldest first
+func (repo *ReviewFlagRepository) GetOpenFlags(ctx context.Context) ([]model.ReviewFlag, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT id, review_id, user_id, reason, created_at, resolved FROM review_flags WHERE resolved = FALSE ORDER BY created_at, id"
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flags []model.ReviewFlag
+	for rows.Next() {
+		var flag model.ReviewFlag
+		var createdAt []uint8
+		if err := rows.Scan(&flag.ID, &flag.ReviewID, &flag.UserID, &flag.Reason, &createdAt, &flag.Resolved); err != nil {
+			return nil, err
+		}
+		if flag.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+	return flags, rows.Err()
+}
+
+// ResolveFlags closes the open flags of a review once a moderator has decided on it
+func (repo *ReviewFlagRepository) ResolveFlags(ctx context.Context, reviewID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.db).ExecContext(ctx, "UPDATE review_flags SET resolved = TRUE WHERE review_id = ? AND resolved = FALSE", reviewID)
+	return err
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type ReviewPhotoRepository struct {
+	db *sql.DB
+}
+
+// NewReviewPhotoRepository creates a ReviewPhotoRepository backed by MySQL
+func NewReviewPhotoRepository(db *sql.DB) interfaces.ReviewPhotoRepository {
+	return &ReviewPhotoRepository{db: db}
+}
+
+const reviewPhotoColumns = "p.id, p.review_id, p.kind, p.file_name, p.blob_key, p.size, p.uploaded_at"
+
+// SavePhoto records a review photo whose file has been stored in the blob store
+func (repo *ReviewPhotoRepository) SavePhoto(ctx context.Context, photo model.ReviewPhoto) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "INSERT INTO review_photos (id, review_id, kind, file_name, blob_key, size, uploaded_at) VALUES (?, ?, ?, ?, ?, ?, ?)"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, photo.ID, photo.ReviewID, photo.Kind, photo.FileName, photo.BlobKey,
+		photo.Size, photo.UploadedAt)
+	return err
+}
+
+// GetPhotoByID retrieves a review photo by its ID
+func (repo *ReviewPhotoRepository) GetPhotoByID(ctx context.Context, photoID string) (*model.ReviewPhoto, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + reviewPhotoColumns + " FROM review_photos p WHERE p.id = ?"
+	photo, err := scanReviewPhoto(conn(ctx, repo.db).QueryRowContext(ctx, query, photoID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("photo not found")
+		}
+		return nil, err
+	}
+	return photo, nil
+}
+
+// GetPhotosByReviewID lists the photos of a review, oldest first
+func (repo *ReviewPhotoRepository) GetPhotosByReviewID(ctx context.Context, reviewID string) ([]model.ReviewPhoto, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + reviewPhotoColumns + " FROM review_photos p WHERE p.review_id = ? ORDER BY p.uploaded_at, p.id"
+	return repo.queryPhotos(ctx, query, reviewID)
+}
+
+// GetPhotosByProviderID lists the photos of the visible reviews a provider received, newest first
+func (repo *ReviewPhotoRepository) GetPhotosByProviderID(ctx context.Context, providerID string) ([]model.ReviewPhoto, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + reviewPhotoColumns + ` FROM review_photos p
+	INNER JOIN reviews r ON r.id = p.review_id
+	WHERE r.provider_id = ? AND r.hidden = FALSE
+	ORDER BY p.uploaded_at DESC, p.id`
+	return repo.queryPhotos(ctx, query, providerID)
+}
+
+// GetPhotosByHouseholderID lists the photos attached to the reviews a householder wrote, oldest first
+func (repo *ReviewPhotoRepository) GetPhotosByHouseholderID(ctx context.Context, householderID string) ([]model.ReviewPhoto, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + reviewPhotoColumns + ` FROM review_photos p
+	INNER JOIN reviews r ON r.id = p.review_id
+	WHERE r.householder_id = ?
+	ORDER BY p.uploaded_at, p.id`
+	return repo.queryPhotos(ctx, query, householderID)
+}
+
+// DeletePhoto forgets a review photo. Its file has to be removed from the blob store separately.
+func (repo *ReviewPhotoRepository) DeletePhoto(ctx context.Context, photoID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	result, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM review_photos WHERE id = ?", photoID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("photo not found")
+	}
+	return nil
+}
+
+// DeletePhotosByHouseholderID forgets the photos of every review a householder wrote. Their files have to be
+// removed from the blob store separately.
+func (repo *ReviewPhotoRepository) DeletePhotosByHouseholderID(ctx context.Context, householderID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "DELETE p FROM review_photos p INNER JOIN reviews r ON r.id = p.review_id WHERE r.householder_id = ?"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, householderID)
+	return err
+}
+
+func (repo *ReviewPhotoRepository) queryPhotos(ctx context.Context, query string, args ...interface{}) ([]model.ReviewPhoto, error) {
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var photos []model.ReviewPhoto
+	for rows.Next() {
+		photo, err := scanReviewPhoto(rows)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, *photo)
+	}
+	return photos, rows.Err()
+}
+
+func scanReviewPhoto(row rowScanner) (*model.ReviewPhoto, error) {
+	var photo model.ReviewPhoto
+	var uploadedAt []uint8
+	err := row.Scan(&photo.ID, &photo.ReviewID, &photo.Kind, &photo.FileName, &photo.BlobKey, &photo.Size, &uploadedAt)
+	if err != nil {
+		return nil, err
+	}
+	if photo.UploadedAt, err = util.ParseTime(uploadedAt); err != nil {
+		return nil, fmt.Errorf("error parsing uploaded_at: %v", err)
+	}
+	return &photo, nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+)
+
+type RoleHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewRoleHistoryRepository creates a RoleHistoryRepository backed by MySQL
+func NewRoleHistoryRepository(db *sql.DB) interfaces.RoleHistoryRepository {
+	return &RoleHistoryRepository{db: db}
+}
+
+// SaveRoleChange appends an entry to the role history of an account
+func (repo *RoleHistoryRepository) SaveRoleChange(ctx context.Context, change model.RoleChange) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO role_history (id, user_id, from_role, to_role, changed_by, changed_at) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, change.ID, change.UserID, change.FromRole, change.ToRole, change.ChangedBy, change.ChangedAt)
+	return err
+}
+
+// GetRoleHistory lists the role changes of an account, most recent first
+func (repo *RoleHistoryRepository) GetRoleHistory(ctx context.Context, userID string) ([]model.RoleChange, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT id, user_id, from_role, to_role, changed_by, changed_at
+		FROM role_history WHERE user_id = ? ORDER BY changed_at DESC, id`
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []model.RoleChange
+	for rows.Next() {
+		var change model.RoleChange
+		var changedAt []uint8
+		if err := rows.Scan(&change.ID, &change.UserID, &change.FromRole, &change.ToRole, &change.ChangedBy, &changedAt); err != nil {
+			return nil, err
+		}
+		if change.ChangedAt, err = util.ParseTime(changedAt); err != nil {
+			return nil, fmt.Errorf("error parsing changed_at: %v", err)
+		}
+		history = append(history, change)
+	}
+	return history, rows.Err()
+}
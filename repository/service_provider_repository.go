@@ -1,12 +1,16 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"serviceNest/interfaces"
 	"serviceNest/model"
 	"serviceNest/util"
+	"sort"
+	"strings"
+	"time"
 )
 
 type ServiceProviderRepository struct {
@@ -18,29 +22,44 @@ func NewServiceProviderRepository(collection *sql.DB) interfaces.ServiceProvider
 	return &ServiceProviderRepository{Collection: collection}
 }
 
-func (repo *ServiceProviderRepository) SaveServiceProvider(provider model.ServiceProvider) error {
-	query := "INSERT INTO service_providers (user_id, rating, availability, is_active) VALUES (?, ?, ?, ?)"
-	_, err := repo.Collection.Exec(query, provider.User.ID, provider.Rating, provider.Availability, provider.IsActive)
+func (repo *ServiceProviderRepository) SaveServiceProvider(ctx context.Context, provider model.ServiceProvider) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	status := provider.VerificationStatus
+	if status == "" {
+		status = model.VerificationPending
+	}
+	query := "INSERT INTO service_providers (user_id, rating, availability, is_active, verification_status) VALUES (?, ?, ?, ?, ?)"
+	_, err := conn(ctx, repo.Collection).ExecContext(ctx, query, provider.User.ID, provider.Rating, provider.Availability, provider.IsActive, status)
 	return err
 }
 
-func (repo *ServiceProviderRepository) GetProviderByID(providerID string) (*model.ServiceProvider, error) {
-	query := "SELECT user_id, rating, availability, is_active FROM service_providers WHERE user_id = ?"
-	row := repo.Collection.QueryRow(query, providerID)
+func (repo *ServiceProviderRepository) GetProviderByID(ctx context.Context, providerID string) (*model.ServiceProvider, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT user_id, rating, availability, is_active, verification_status, verification_note FROM service_providers WHERE user_id = ?"
+	row := conn(ctx, repo.Collection).QueryRowContext(ctx, query, providerID)
 
 	var provider model.ServiceProvider
-	err := row.Scan(&provider.User.ID, &provider.Rating, &provider.Availability, &provider.IsActive)
+	var note sql.NullString
+	err := row.Scan(&provider.User.ID, &provider.Rating, &provider.Availability, &provider.IsActive, &provider.VerificationStatus, &note)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("provider not found")
 		}
 		return nil, err
 	}
+	provider.VerificationNote = note.String
 
 	return &provider, nil
 }
 
-func (repo *ServiceProviderRepository) GetProvidersByServiceType(serviceType string) ([]model.ServiceProvider, error) {
+func (repo *ServiceProviderRepository) GetProvidersByServiceType(ctx context.Context, serviceType string) ([]model.ServiceProvider, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := `
 	SELECT sp.user_id, sp.rating, sp.availability, sp.is_active 
 	FROM service_providers sp
@@ -48,7 +67,7 @@ func (repo *ServiceProviderRepository) GetProvidersByServiceType(serviceType str
 	INNER JOIN services s ON sps.service_id = s.id
 	WHERE s.name = ?
 	`
-	rows, err := repo.Collection.Query(query, serviceType)
+	rows, err := conn(ctx, repo.Collection).QueryContext(ctx, query, serviceType)
 	if err != nil {
 		return nil, err
 	}
@@ -67,14 +86,17 @@ func (repo *ServiceProviderRepository) GetProvidersByServiceType(serviceType str
 	return providers, nil
 }
 
-func (repo *ServiceProviderRepository) GetProviderByServiceID(serviceID string) (*model.ServiceProvider, error) {
+func (repo *ServiceProviderRepository) GetProviderByServiceID(ctx context.Context, serviceID string) (*model.ServiceProvider, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := `
 	SELECT sp.user_id, sp.rating, sp.availability, sp.is_active 
 	FROM service_providers sp
 	INNER JOIN service_providers_services sps ON sp.user_id = sps.service_provider_id
 	WHERE sps.service_id = ?
 	`
-	row := repo.Collection.QueryRow(query, serviceID)
+	row := conn(ctx, repo.Collection).QueryRowContext(ctx, query, serviceID)
 
 	var provider model.ServiceProvider
 	err := row.Scan(&provider.User.ID, &provider.Rating, &provider.Availability, &provider.IsActive)
@@ -88,19 +110,27 @@ func (repo *ServiceProviderRepository) GetProviderByServiceID(serviceID string)
 	return &provider, nil
 }
 
-func (repo *ServiceProviderRepository) UpdateServiceProvider(provider *model.ServiceProvider) error {
+// UpdateServiceProvider saves the availability and status of a provider. The rating is left alone: it is
+// kept by the RatingRepository as reviews come in.
+func (repo *ServiceProviderRepository) UpdateServiceProvider(ctx context.Context, provider *model.ServiceProvider) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := `
 	UPDATE service_providers
-	SET rating = ?, availability = ?, is_active = ?
+	SET availability = ?, is_active = ?
 	WHERE user_id = ?
 	`
-	_, err := repo.Collection.Exec(query, provider.Rating, provider.Availability, provider.IsActive, provider.ID)
+	_, err := conn(ctx, repo.Collection).ExecContext(ctx, query, provider.Availability, provider.IsActive, provider.ID)
 	return err
 }
 
-func (repo *ServiceProviderRepository) GetProviderDetailByID(providerID string) (*model.ServiceProviderDetails, error) {
+func (repo *ServiceProviderRepository) GetProviderDetailByID(ctx context.Context, providerID string) (*model.ServiceProviderDetails, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT name, address, contact,rating FROM users INNER JOIN service_providers ON id=user_id WHERE id = ?"
-	row := repo.Collection.QueryRow(query, providerID)
+	row := conn(ctx, repo.Collection).QueryRowContext(ctx, query, providerID)
 
 	var provider model.ServiceProviderDetails
 	err := row.Scan(&provider.Name, &provider.Address, &provider.Contact, &provider.Rating)
@@ -110,15 +140,21 @@ func (repo *ServiceProviderRepository) GetProviderDetailByID(providerID string)
 		}
 		return nil, err
 	}
+	if err := openFields(ctx, &provider.Address, &provider.Contact); err != nil {
+		return nil, err
+	}
 
 	return &provider, nil
 }
 
-func (repo *ServiceProviderRepository) SaveServiceProviderDetail(provider *model.ServiceProviderDetails, requestID string) error {
+func (repo *ServiceProviderRepository) SaveServiceProviderDetail(ctx context.Context, provider *model.ServiceProviderDetails, requestID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	// Check if the service provider exists in the service_providers table
 	existsQuery := "SELECT COUNT(*) FROM service_providers WHERE user_id = ?"
 	var count int
-	err := repo.Collection.QueryRow(existsQuery, provider.ServiceProviderID).Scan(&count)
+	err := conn(ctx, repo.Collection).QueryRowContext(ctx, existsQuery, provider.ServiceProviderID).Scan(&count)
 	if err != nil {
 		return err
 	}
@@ -130,28 +166,51 @@ func (repo *ServiceProviderRepository) SaveServiceProviderDetail(provider *model
 	// Proceed with the insertion
 	id := util.GenerateUniqueID()
 
+	contact, address := provider.Contact, provider.Address
+	if err := sealFields(ctx, &contact, &address); err != nil {
+		return err
+	}
+
 	query := "INSERT INTO service_provider_details (id,service_request_id,service_provider_id,name,contact,address,price,rating,approve) VALUES (?, ?, ?, ?,?,?,?,?,?)"
-	_, err = repo.Collection.Exec(query, id, requestID, provider.ServiceProviderID, provider.Name, provider.Contact, provider.Address, provider.Price, provider.Rating, provider.Approve)
+	_, err = conn(ctx, repo.Collection).ExecContext(ctx, query, id, requestID, provider.ServiceProviderID, provider.Name, contact, address, provider.Price, provider.Rating, provider.Approve)
 	return err
 }
 
-func (repo *ServiceProviderRepository) UpdateServiceProviderDetailByRequestID(provider *model.ServiceProviderDetails, requestID string) error {
+// UpdateServiceProviderDetailByRequestID updates the provider's entry on a request if its version is unchanged
+func (repo *ServiceProviderRepository) UpdateServiceProviderDetailByRequestID(ctx context.Context, provider *model.ServiceProviderDetails, requestID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := `
 	UPDATE service_provider_details
-	SET approve= ?
-	WHERE service_provider_id = ? and service_request_id=?
+	SET approve= ?, version = version + 1
+	WHERE service_provider_id = ? and service_request_id=? and version = ?
 	`
-	_, err := repo.Collection.Exec(query, provider.Approve, provider.ServiceProviderID, requestID)
-	return err
+	result, err := conn(ctx, repo.Collection).ExecContext(ctx, query, provider.Approve, provider.ServiceProviderID, requestID, provider.Version)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return &model.ConflictError{Entity: "service provider detail", ID: requestID}
+	}
+	provider.Version++
+	return nil
 }
 
-func (repo *ServiceProviderRepository) IsProviderApproved(providerID string) (bool, error) {
+func (repo *ServiceProviderRepository) IsProviderApproved(ctx context.Context, providerID string) (bool, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	var approveStatus bool
 	query := `
 	SELECT approve FROM service_provider_details
 	WHERE service_provider_id = ? AND approve = 1
 	`
-	err := repo.Collection.QueryRow(query, providerID).Scan(&approveStatus)
+	err := conn(ctx, repo.Collection).QueryRowContext(ctx, query, providerID).Scan(&approveStatus)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return false, errors.New("service provider not found")
@@ -162,92 +221,356 @@ func (repo *ServiceProviderRepository) IsProviderApproved(providerID string) (bo
 }
 
 // AddReview adds a review to the reviews table
-func (repo *ServiceProviderRepository) AddReview(review model.Review) error {
-	tx, err := repo.Collection.Begin() // Start a transaction
+func (repo *ServiceProviderRepository) AddReview(ctx context.Context, review model.Review) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	// Runs in its own transaction unless the caller already started one
+	return inTransaction(ctx, repo.Collection, func(ctx context.Context) error {
+		// Insert the review into the reviews table with providerID
+		reviewQuery := `
+	INSERT INTO reviews (id, provider_id, service_id, householder_id, request_id, category, rating, comments, review_date)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+		_, err := conn(ctx, repo.Collection).ExecContext(ctx, reviewQuery, review.ID, review.ProviderID, review.ServiceID, review.HouseholderID, nullableString(review.RequestID), review.Category, review.Rating, review.Comments, review.ReviewDate)
+		if isDuplicateKey(err) {
+			// The unique index on request_id allows one review per request
+			return model.ErrAlreadyReviewed
+		}
+		if err != nil {
+			return err
+		}
+		return repo.saveReviewScores(ctx, review)
+	})
+}
+
+// saveReviewScores writes the sub-scores of a review, sorted by criterion so that the statements are predictable
+func (repo *ServiceProviderRepository) saveReviewScores(ctx context.Context, review model.Review) error {
+	criteria := make([]string, 0, len(review.Scores))
+	for criterion := range review.Scores {
+		criteria = append(criteria, criterion)
+	}
+	sort.Strings(criteria)
+	for _, criterion := range criteria {
+		query := "INSERT INTO review_scores (review_id, criterion, score) VALUES (?, ?, ?)"
+		if _, err := conn(ctx, repo.Collection).ExecContext(ctx, query, review.ID, criterion, review.Scores[criterion]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetReviewByID retrieves a single review
+func (repo *ServiceProviderRepository) GetReviewByID(ctx context.Context, reviewID string) (*model.Review, error) {
+	return repo.getReview(ctx, "", reviewID)
+}
+
+// LockReviewByID reads a review for an update in the caller's transaction, so that concurrent changes to
+// the review are applied one after the other
+func (repo *ServiceProviderRepository) LockReviewByID(ctx context.Context, reviewID string) (*model.Review, error) {
+	return repo.getReview(ctx, "FOR UPDATE", reviewID)
+}
+
+func (repo *ServiceProviderRepository) getReview(ctx context.Context, lock, reviewID string) (*model.Review, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `
+	SELECT ` + reviewColumns + `
+	FROM reviews
+	WHERE id = ?
+	` + lock
+	reviews, err := repo.queryReviews(ctx, query, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return nil, errors.New("review not found")
+	}
+	return &reviews[0], nil
+}
+
+// UpdateReview saves a changed rating, sub-scores and comments together with the time of the change
+func (repo *ServiceProviderRepository) UpdateReview(ctx context.Context, review model.Review) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	return inTransaction(ctx, repo.Collection, func(ctx context.Context) error {
+		query := "UPDATE reviews SET rating = ?, comments = ?, updated_at = ? WHERE id = ?"
+		result, err := conn(ctx, repo.Collection).ExecContext(ctx, query, review.Rating, review.Comments, review.UpdatedAt, review.ID)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if rowsAffected == 0 {
+			return errors.New("review not found")
+		}
+		if _, err := conn(ctx, repo.Collection).ExecContext(ctx, "DELETE FROM review_scores WHERE review_id = ?", review.ID); err != nil {
+			return err
+		}
+		return repo.saveReviewScores(ctx, review)
+	})
+}
+
+// SaveReviewReply stores the provider's reply to one of their reviews. A review that already has a reply
+// is left alone and model.ErrAlreadyReplied is returned.
+func (repo *ServiceProviderRepository) SaveReviewReply(ctx context.Context, reviewID, providerID, reply string, replyDate time.Time) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE reviews SET reply = ?, reply_date = ? WHERE id = ? AND provider_id = ? AND reply IS NULL"
+	result, err := conn(ctx, repo.Collection).ExecContext(ctx, query, reply, replyDate, reviewID, providerID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
+	if rowsAffected == 0 {
+		return model.ErrAlreadyReplied
+	}
+	return nil
+}
 
-	// Insert the review into the reviews table with providerID
-	reviewQuery := `
-	INSERT INTO reviews (id, provider_id, service_id, householder_id, rating, comments, review_date)
-	VALUES (?, ?, ?, ?, ?, ?, ?)
-	`
-	_, err = tx.Exec(reviewQuery, review.ID, review.ProviderID, review.ServiceID, review.HouseholderID, review.Rating, review.Comments, review.ReviewDate)
+// UpdateReviewModeration saves whether a review is hidden together with who decided it, when and why
+func (repo *ServiceProviderRepository) UpdateReviewModeration(ctx context.Context, review model.Review) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE reviews SET hidden = ?, moderation_note = ?, moderated_by = ?, moderated_at = ? WHERE id = ?"
+	result, err := conn(ctx, repo.Collection).ExecContext(ctx, query, review.Hidden, review.ModerationNote, review.ModeratedBy, review.ModeratedAt, review.ID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		tx.Rollback()
 		return err
 	}
+	if rowsAffected == 0 {
+		return errors.New("review not found")
+	}
+	return nil
+}
 
-	return tx.Commit() // Commit the transaction
+// GetHiddenReviews lists the reviews moderators have hidden, most recently hidden first
+func (repo *ServiceProviderRepository) GetHiddenReviews(ctx context.Context) ([]model.Review, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + reviewColumns + " FROM reviews WHERE hidden = TRUE ORDER BY moderated_at DESC, id"
+	return repo.queryReviews(ctx, query)
 }
 
-// UpdateProviderRating recalculates and updates the provider's average rating
-func (repo *ServiceProviderRepository) UpdateProviderRating(providerID string) error {
-	// Calculate the average rating from the reviews table
-	ratingQuery := `
-	SELECT AVG(r.rating)
-	FROM reviews r
-	WHERE r.provider_id = ?
+func (repo *ServiceProviderRepository) GetReviewsByProviderID(ctx context.Context, providerID string) ([]model.Review, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `
+	SELECT ` + reviewColumns + `
+	FROM reviews
+	WHERE provider_id = ?
 	`
-	var avgRating float64
-	err := repo.Collection.QueryRow(ratingQuery, providerID).Scan(&avgRating)
+	return repo.queryReviews(ctx, query, providerID)
+}
+
+// GetReviewsByHouseholderID lists the reviews a householder has written, oldest first
+func (repo *ServiceProviderRepository) GetReviewsByHouseholderID(ctx context.Context, householderID string) ([]model.Review, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `
+	SELECT ` + reviewColumns + `
+	FROM reviews
+	WHERE householder_id = ?
+	ORDER BY review_date, id
+	`
+	return repo.queryReviews(ctx, query, householderID)
+}
+
+// ClearReviewComments removes the comments of the reviews a householder has written, and the replies of a
+// provider to the reviews they received. The ratings are kept so that the providers' averages do not change.
+func (repo *ServiceProviderRepository) ClearReviewComments(ctx context.Context, userID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.Collection).ExecContext(ctx, "UPDATE reviews SET comments = '' WHERE householder_id = ?", userID)
 	if err != nil {
-		return fmt.Errorf("failed to calculate average rating: %v", err)
+		return err
 	}
+	_, err = conn(ctx, repo.Collection).ExecContext(ctx, "UPDATE reviews SET reply = '' WHERE provider_id = ? AND reply IS NOT NULL", userID)
+	return err
+}
 
-	// Update the rating in the service_providers table
-	updateServiceProviderQuery := `
-	UPDATE service_providers
-	SET rating = ?
-	WHERE user_id = ?
-	`
-	_, err = repo.Collection.Exec(updateServiceProviderQuery, avgRating, providerID)
+// reviewColumns are the columns scanReview reads, in order
+const reviewColumns = `id, provider_id, service_id, householder_id, request_id, category, rating, comments, review_date,
+	updated_at, reply, reply_date, hidden, moderation_note, moderated_by, moderated_at`
+
+func (repo *ServiceProviderRepository) queryReviews(ctx context.Context, query string, args ...interface{}) ([]model.Review, error) {
+	rows, err := conn(ctx, repo.Collection).QueryContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to update rating in service_providers table: %v", err)
+		return nil, err
 	}
+	defer rows.Close()
 
-	// Update the rating in the service_provider_details table
-	updateServiceProviderDetailsQuery := `
-	UPDATE service_provider_details
-	SET rating = ?
-	WHERE service_provider_id = ?
-	`
-	_, err = repo.Collection.Exec(updateServiceProviderDetailsQuery, avgRating, providerID)
+	var reviews []model.Review
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, *review)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err := repo.loadReviewScores(ctx, reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// loadReviewScores fills in the sub-scores of the reviews with a single query
+func (repo *ServiceProviderRepository) loadReviewScores(ctx context.Context, reviews []model.Review) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+	index := make(map[string]int, len(reviews))
+	placeholders := make([]string, len(reviews))
+	args := make([]interface{}, len(reviews))
+	for i, review := range reviews {
+		index[review.ID] = i
+		placeholders[i] = "?"
+		args[i] = review.ID
+	}
+
+	query := "SELECT review_id, criterion, score FROM review_scores WHERE review_id IN (" + strings.Join(placeholders, ", ") + ")"
+	rows, err := conn(ctx, repo.Collection).QueryContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("failed to update rating in service_provider_details table: %v", err)
+		return err
 	}
+	defer rows.Close()
 
-	return nil
+	for rows.Next() {
+		var reviewID, criterion string
+		var score float64
+		if err := rows.Scan(&reviewID, &criterion, &score); err != nil {
+			return err
+		}
+		review := &reviews[index[reviewID]]
+		if review.Scores == nil {
+			review.Scores = make(map[string]float64)
+		}
+		review.Scores[criterion] = score
+	}
+	return rows.Err()
+}
+
+func scanReview(row rowScanner) (*model.Review, error) {
+	var review model.Review
+	var requestID, reply, note, moderatedBy sql.NullString
+	var reviewDate, updatedAt, replyDate, moderatedAt []uint8
+	err := row.Scan(&review.ID, &review.ProviderID, &review.ServiceID, &review.HouseholderID, &requestID, &review.Category,
+		&review.Rating, &review.Comments, &reviewDate, &updatedAt, &reply, &replyDate, &review.Hidden, &note, &moderatedBy, &moderatedAt)
+	if err != nil {
+		return nil, err
+	}
+	review.RequestID = requestID.String
+	review.Reply = reply.String
+	review.ModerationNote = note.String
+	review.ModeratedBy = moderatedBy.String
+
+	if review.ReviewDate, err = util.ParseTime(reviewDate); err != nil {
+		return nil, err
+	}
+	if review.UpdatedAt, err = parseOptionalTime(updatedAt); err != nil {
+		return nil, fmt.Errorf("error parsing updated_at: %v", err)
+	}
+	if review.ReplyDate, err = parseOptionalTime(replyDate); err != nil {
+		return nil, fmt.Errorf("error parsing reply_date: %v", err)
+	}
+	if review.ModeratedAt, err = parseOptionalTime(moderatedAt); err != nil {
+		return nil, fmt.Errorf("error parsing moderated_at: %v", err)
+	}
+	return &review, nil
+}
+
+// parseOptionalTime parses a nullable DATETIME column, NULL becomes nil
+func parseOptionalTime(value []uint8) (*time.Time, error) {
+	if value == nil {
+		return nil, nil
+	}
+	parsed, err := util.ParseTime(value)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
 }
 
-func (repo *ServiceProviderRepository) GetReviewsByProviderID(providerID string) ([]model.Review, error) {
+// GetProvidersByVerificationStatus lists the providers with the given verification status together with
+// their account details, oldest account first
+func (repo *ServiceProviderRepository) GetProvidersByVerificationStatus(ctx context.Context, status string) ([]model.ServiceProvider, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := `
-	SELECT id, provider_id, service_id, householder_id, rating, comments, review_date
-	FROM reviews
-	WHERE provider_id = ?
+	SELECT sp.user_id, u.name, u.email, u.contact, u.address, sp.rating, sp.availability, sp.is_active, sp.verification_status, sp.verification_note
+	FROM service_providers sp
+	INNER JOIN users u ON u.id = sp.user_id
+	WHERE sp.verification_status = ?
+	ORDER BY u.name, sp.user_id
 	`
-	rows, err := repo.Collection.Query(query, providerID)
+	rows, err := conn(ctx, repo.Collection).QueryContext(ctx, query, status)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var reviews []model.Review
+	var providers []model.ServiceProvider
 	for rows.Next() {
-		var review model.Review
-		var reviewDate []uint8
-		err := rows.Scan(&review.ID, &review.ProviderID, &review.ServiceID, &review.HouseholderID, &review.Rating, &review.Comments, &reviewDate)
+		var provider model.ServiceProvider
+		var note sql.NullString
+		err := rows.Scan(&provider.User.ID, &provider.Name, &provider.Email, &provider.Contact, &provider.Address, &provider.Rating,
+			&provider.Availability, &provider.IsActive, &provider.VerificationStatus, &note)
 		if err != nil {
 			return nil, err
 		}
-		parsedDate, err := util.ParseTime(reviewDate)
-		if err != nil {
+		if err := openFields(ctx, &provider.Contact, &provider.Address); err != nil {
 			return nil, err
 		}
-		review.ReviewDate = parsedDate
-		reviews = append(reviews, review)
+		provider.VerificationNote = note.String
+		providers = append(providers, provider)
 	}
+	return providers, rows.Err()
+}
 
-	return reviews, nil
+// UpdateVerificationStatus records the outcome of a verification step. reviewedBy is empty when the
+// provider resubmits documents, the previous review is then cleared.
+func (repo *ServiceProviderRepository) UpdateVerificationStatus(ctx context.Context, providerID, status, note, reviewedBy string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var reviewedAt *time.Time
+	if reviewedBy != "" {
+		now := time.Now()
+		reviewedAt = &now
+	}
+	query := "UPDATE service_providers SET verification_status = ?, verification_note = ?, reviewed_by = ?, reviewed_at = ? WHERE user_id = ?"
+	result, err := conn(ctx, repo.Collection).ExecContext(ctx, query, status, nullableString(note), nullableString(reviewedBy), reviewedAt, providerID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("provider not found")
+	}
+	return nil
 }
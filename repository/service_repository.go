@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"log"
@@ -17,9 +18,36 @@ func NewServiceRepository(client *sql.DB) interfaces.ServiceRepository {
 	return &ServiceRepository{db: client}
 }
 
-func (repo *ServiceRepository) GetAllServices() ([]model.Service, error) {
-	query := "SELECT id, name, description, price, provider_id, category FROM services"
-	rows, err := repo.db.Query(query)
+// serviceSortColumns are the sort keys accepted by GetAllServices
+var serviceSortColumns = map[string]string{
+	"name":     "name",
+	"price":    "price",
+	"category": "category",
+}
+
+// GetAllServices returns one page of services, filtered by category and provider and sorted as opts describe
+func (repo *ServiceRepository) GetAllServices(ctx context.Context, opts model.QueryOptions) ([]model.Service, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var q listQuery
+	if opts.Category != "" {
+		q.where("category = ?", opts.Category)
+	}
+	if opts.ProviderID != "" {
+		q.where("provider_id = ?", opts.ProviderID)
+	}
+	orderBy, err := orderByClause(opts, serviceSortColumns, "name", "id")
+	if err != nil {
+		return nil, err
+	}
+	limit, limitArgs, err := limitClause(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT id, name, description, price, provider_id, category FROM services" + q.whereClause() + orderBy + limit
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, append(q.args, limitArgs...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -51,10 +79,13 @@ func (repo *ServiceRepository) GetAllServices() ([]model.Service, error) {
 }
 
 // GetServiceByID retrieves a service by its ID
-func (repo *ServiceRepository) GetServiceByID(serviceID string) (*model.Service, error) {
+func (repo *ServiceRepository) GetServiceByID(ctx context.Context, serviceID string) (*model.Service, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT id, name, description, price, provider_id, category FROM services WHERE id = ?"
 	var service model.Service
-	err := repo.db.QueryRow(query, serviceID).Scan(&service.ID, &service.Name, &service.Description, &service.Price, &service.ProviderID, &service.Category)
+	err := conn(ctx, repo.db).QueryRowContext(ctx, query, serviceID).Scan(&service.ID, &service.Name, &service.Description, &service.Price, &service.ProviderID, &service.Category)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("service not found")
@@ -66,7 +97,10 @@ func (repo *ServiceRepository) GetServiceByID(serviceID string) (*model.Service,
 }
 
 // SaveService adds a new service to the MySQL database
-func (repo *ServiceRepository) SaveService(service model.Service) error {
+func (repo *ServiceRepository) SaveService(ctx context.Context, service model.Service) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "INSERT INTO services (id, name, description, price, provider_id, category) VALUES (?, ?, ?, ?, ?, ?)"
 	var providerID *string
 	if service.ProviderID == "" {
@@ -74,44 +108,48 @@ func (repo *ServiceRepository) SaveService(service model.Service) error {
 	} else {
 		providerID = &service.ProviderID
 	}
-	_, err := repo.db.Exec(query, service.ID, service.Name, service.Description, service.Price, providerID, service.Category)
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, service.ID, service.Name, service.Description, service.Price, providerID, service.Category)
 	return err
 }
 
 // SaveAllServices saves the entire list of services to the MySQL database
-func (repo *ServiceRepository) SaveAllServices(services []model.Service) error {
-	tx, err := repo.db.Begin()
-	if err != nil {
-		return err
-	}
+func (repo *ServiceRepository) SaveAllServices(ctx context.Context, services []model.Service) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 
-	stmt, err := tx.Prepare("INSERT INTO services (id, name, description, price, provider_id, category) VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE name=VALUES(name), description=VALUES(description), price=VALUES(price), provider_id=VALUES(provider_id), category=VALUES(category)")
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	defer stmt.Close()
-
-	for _, service := range services {
-		if _, err := stmt.Exec(service.ID, service.Name, service.Description, service.Price, service.ProviderID, service.Category); err != nil {
-			tx.Rollback()
+	return inTransaction(ctx, repo.db, func(ctx context.Context) error {
+		tx := ctx.Value(txKey{}).(*txState).tx
+		stmt, err := tx.PrepareContext(ctx, "INSERT INTO services (id, name, description, price, provider_id, category) VALUES (?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE name=VALUES(name), description=VALUES(description), price=VALUES(price), provider_id=VALUES(provider_id), category=VALUES(category)")
+		if err != nil {
 			return err
 		}
-	}
+		defer stmt.Close()
 
-	return tx.Commit()
+		for _, service := range services {
+			if _, err := stmt.ExecContext(ctx, service.ID, service.Name, service.Description, service.Price, service.ProviderID, service.Category); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // RemoveService removes a service from the MySQL database
-func (repo *ServiceRepository) RemoveService(serviceID string) error {
+func (repo *ServiceRepository) RemoveService(ctx context.Context, serviceID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "DELETE FROM services WHERE id = ?"
-	_, err := repo.db.Exec(query, serviceID)
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, serviceID)
 	return err
 }
-func (repo *ServiceRepository) GetServiceByName(serviceName string) (*model.Service, error) {
+func (repo *ServiceRepository) GetServiceByName(ctx context.Context, serviceName string) (*model.Service, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT id, name, description, price, provider_id, category FROM services WHERE name = ?"
 	var service model.Service
-	err := repo.db.QueryRow(query, serviceName).Scan(&service.ID, &service.Name, &service.Description, &service.Price, &service.ProviderID, &service.Category)
+	err := conn(ctx, repo.db).QueryRowContext(ctx, query, serviceName).Scan(&service.ID, &service.Name, &service.Description, &service.Price, &service.ProviderID, &service.Category)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, errors.New("service not found")
@@ -122,9 +160,12 @@ func (repo *ServiceRepository) GetServiceByName(serviceName string) (*model.Serv
 }
 
 // GetServiceByProviderID retrieves a service by its ProviderID
-func (repo *ServiceRepository) GetServiceByProviderID(providerID string) ([]model.Service, error) {
+func (repo *ServiceRepository) GetServiceByProviderID(ctx context.Context, providerID string) ([]model.Service, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "SELECT id, name, description, price, provider_id, category FROM services WHERE provider_id = ?"
-	rows, err := repo.db.Query(query, providerID)
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, providerID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, errors.New("service not found")
@@ -145,9 +186,12 @@ func (repo *ServiceRepository) GetServiceByProviderID(providerID string) ([]mode
 	return services, nil
 }
 
-func (repo *ServiceRepository) UpdateService(providerID string, updatedService model.Service) error {
+func (repo *ServiceRepository) UpdateService(ctx context.Context, providerID string, updatedService model.Service) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "UPDATE services SET name = ?, description = ?, price = ? WHERE provider_id= ? AND id=?;"
-	result, err := repo.db.Exec(query, updatedService.Name, updatedService.Description, updatedService.Price, providerID, updatedService.ID)
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, updatedService.Name, updatedService.Description, updatedService.Price, providerID, updatedService.ID)
 	// Check how many rows were affected
 	if err != nil {
 		log.Println("Error executing update query:", err)
@@ -164,9 +208,12 @@ func (repo *ServiceRepository) UpdateService(providerID string, updatedService m
 	return nil
 }
 
-func (repo *ServiceRepository) RemoveServiceByProviderID(providerID string, serviceID string) error {
+func (repo *ServiceRepository) RemoveServiceByProviderID(ctx context.Context, providerID string, serviceID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := "DELETE FROM services WHERE id = ? AND provider_id = ?"
-	result, err := repo.db.Exec(query, serviceID, providerID)
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, serviceID, providerID)
 	if err != nil {
 		return err
 	}
@@ -183,3 +230,12 @@ func (repo *ServiceRepository) RemoveServiceByProviderID(providerID string, serv
 
 	return nil
 }
+
+// RenameCategory moves the services filed under oldName to newName, services refer to their category by name
+func (repo *ServiceRepository) RenameCategory(ctx context.Context, oldName, newName string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	_, err := conn(ctx, repo.db).ExecContext(ctx, "UPDATE services SET category = ? WHERE category = ?", newName, oldName)
+	return err
+}
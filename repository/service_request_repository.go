@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -19,22 +20,32 @@ func NewServiceRequestRepository(db *sql.DB) interfaces.ServiceRequestRepository
 }
 
 // SaveServiceRequest saves a service request to the MySQL database
-func (repo *ServiceRequestRepository) SaveServiceRequest(request model.ServiceRequest) error {
+func (repo *ServiceRequestRepository) SaveServiceRequest(ctx context.Context, request model.ServiceRequest) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := `
 		INSERT INTO service_requests 
-		(id, householder_id, householder_name, householder_address, service_id, requested_time, scheduled_time, status, approve_status) 
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		(id, householder_id, householder_name, householder_address, service_id, requested_time, scheduled_time, status, approve_status, target_provider_id) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := repo.db.Exec(query, request.ID, request.HouseholderID, request.HouseholderName, request.HouseholderAddress, request.ServiceID, request.RequestedTime, request.ScheduledTime, request.Status, request.ApproveStatus)
+	address, err := sealNullableField(ctx, request.HouseholderAddress)
+	if err != nil {
+		return err
+	}
+	_, err = conn(ctx, repo.db).ExecContext(ctx, query, request.ID, request.HouseholderID, request.HouseholderName, address, request.ServiceID, request.RequestedTime, request.ScheduledTime, request.Status, request.ApproveStatus, request.TargetProviderID)
 	return err
 }
 
 // GetServiceRequestByID retrieves a service request by its ID from MySQL
-func (repo *ServiceRequestRepository) GetServiceRequestByID(requestID string) (*model.ServiceRequest, error) {
+func (repo *ServiceRequestRepository) GetServiceRequestByID(ctx context.Context, requestID string) (*model.ServiceRequest, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := `
 		SELECT sr.id, sr.householder_id, sr.householder_name, sr.householder_address, sr.service_id, 
-		       s.name as service_name, sr.requested_time, sr.scheduled_time, sr.status, sr.approve_status 
+		       s.name as service_name, sr.requested_time, sr.scheduled_time, sr.status, sr.approve_status, sr.version, sr.target_provider_id 
 		FROM service_requests sr
 		INNER JOIN services s ON sr.service_id = s.id
 		WHERE sr.id = ?
@@ -45,9 +56,10 @@ func (repo *ServiceRequestRepository) GetServiceRequestByID(requestID string) (*
 	var scheduledTime []uint8
 
 	// Execute the query
-	err := repo.db.QueryRow(query, requestID).Scan(
+	err := conn(ctx, repo.db).QueryRowContext(ctx, query, requestID).Scan(
 		&request.ID, &request.HouseholderID, &request.HouseholderName, &request.HouseholderAddress,
 		&request.ServiceID, &request.ServiceName, &requestedTime, &scheduledTime, &request.Status, &request.ApproveStatus,
+		&request.Version, &request.TargetProviderID,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -55,6 +67,9 @@ func (repo *ServiceRequestRepository) GetServiceRequestByID(requestID string) (*
 		}
 		return nil, err
 	}
+	if err := openNullableField(ctx, request.HouseholderAddress); err != nil {
+		return nil, err
+	}
 
 	// Parse the times
 	request.RequestedTime, err = util.ParseTime(requestedTime)
@@ -70,107 +85,97 @@ func (repo *ServiceRequestRepository) GetServiceRequestByID(requestID string) (*
 	return &request, nil
 }
 
-func (repo *ServiceRequestRepository) GetServiceRequestsByHouseholderID(householderID string) ([]model.ServiceRequest, error) {
+// GetServiceRequestsByHouseholderID returns one page of the householder's requests, filtered and sorted as opts describe
+func (repo *ServiceRequestRepository) GetServiceRequestsByHouseholderID(ctx context.Context, householderID string, opts model.QueryOptions) ([]model.ServiceRequest, error) {
+	var q listQuery
+	q.where("sr.householder_id = ?", householderID)
+	return repo.listServiceRequests(ctx, &q, opts)
+}
+
+// UpdateServiceRequest updates an existing service request in MySQL. The update only applies when the
+// stored version still matches updatedRequest.Version, otherwise a *model.ConflictError is returned.
+func (repo *ServiceRequestRepository) UpdateServiceRequest(ctx context.Context, updatedRequest *model.ServiceRequest) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	address, err := sealNullableField(ctx, updatedRequest.HouseholderAddress)
+	if err != nil {
+		return err
+	}
 	query := `
-		SELECT sr.id, sr.householder_id, sr.householder_name, sr.householder_address, sr.service_id, 
-		       sr.requested_time, sr.scheduled_time, sr.status, sr.approve_status, spd.service_provider_id, spd.name,
-		       spd.contact, spd.address, spd.price, spd.rating, spd.approve
-		FROM service_requests AS sr 
-		LEFT JOIN service_provider_details AS spd ON sr.id = spd.service_request_id
-		WHERE householder_id = ?
+		UPDATE service_requests 
+		SET householder_id = ?, householder_name = ?, householder_address = ?, service_id = ?, requested_time = ?, scheduled_time = ?, status = ?, approve_status = ?, version = version + 1 
+		WHERE id = ? AND version = ?
 	`
 
-	rows, err := repo.db.Query(query, householderID)
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, updatedRequest.HouseholderID, updatedRequest.HouseholderName, address, updatedRequest.ServiceID, updatedRequest.RequestedTime, updatedRequest.ScheduledTime, updatedRequest.Status, updatedRequest.ApproveStatus, updatedRequest.ID, updatedRequest.Version)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	defer rows.Close()
-
-	var requests []model.ServiceRequest
-	for rows.Next() {
-		var request model.ServiceRequest
-		var requestedTime []uint8
-		var scheduledTime []uint8
-
-		// Using sql.NullString and sql.NullFloat64 for nullable columns
-		var providerID sql.NullString
-		var providerName sql.NullString
-		var providerContact sql.NullString
-		var providerAddress sql.NullString
-		var providerPrice sql.NullString
-		var providerRating sql.NullFloat64
-		var providerApprove sql.NullBool
-
-		// Scan the row data
-		err := rows.Scan(
-			&request.ID, &request.HouseholderID, &request.HouseholderName, &request.HouseholderAddress,
-			&request.ServiceID, &requestedTime, &scheduledTime, &request.Status, &request.ApproveStatus,
-			&providerID, &providerName, &providerContact, &providerAddress, &providerPrice, &providerRating,
-			&providerApprove,
-		)
-		if err != nil {
-			return nil, err
-		}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return &model.ConflictError{Entity: "service request", ID: updatedRequest.ID}
+	}
+	updatedRequest.Version++
+	return nil
+}
 
-		// Parse the requested and scheduled times
-		request.RequestedTime, err = util.ParseTime(requestedTime)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing requested_time: %v", err)
-		}
+// GetAllServiceRequests returns one page of all service requests, filtered and sorted as opts describe
+func (repo *ServiceRequestRepository) GetAllServiceRequests(ctx context.Context, opts model.QueryOptions) ([]model.ServiceRequest, error) {
+	return repo.listServiceRequests(ctx, &listQuery{}, opts)
+}
 
-		request.ScheduledTime, err = util.ParseTime(scheduledTime)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing scheduled_time: %v", err)
-		}
+// serviceRequestSortColumns are the sort keys accepted by the request listings
+var serviceRequestSortColumns = map[string]string{
+	"requested_time": "sr.requested_time",
+	"scheduled_time": "sr.scheduled_time",
+	"status":         "sr.status",
+}
 
-		// Check if provider details are valid (non-NULL)
-		if providerID.Valid {
-			provider := model.ServiceProviderDetails{
-				ServiceProviderID: providerID.String,
-				Name:              providerName.String,
-				Contact:           providerContact.String,
-				Address:           providerAddress.String,
-				Price:             providerPrice.String,
-				Rating:            providerRating.Float64,
-				Approve:           providerApprove.Bool,
-			}
-			request.ProviderDetails = append(request.ProviderDetails, provider)
-		}
+// listServiceRequests pages over service_requests in SQL and then attaches the provider details of
+// the selected requests, so a page always holds opts.Limit requests regardless of how many providers each has
+func (repo *ServiceRequestRepository) listServiceRequests(ctx context.Context, q *listQuery, opts model.QueryOptions) ([]model.ServiceRequest, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 
-		// Append the request to the slice
-		requests = append(requests, request)
+	if opts.Status != "" {
+		q.where("sr.status = ?", opts.Status)
+	}
+	if !opts.From.IsZero() {
+		q.where("sr.requested_time >= ?", opts.From)
+	}
+	if !opts.To.IsZero() {
+		q.where("sr.requested_time < ?", opts.To)
+	}
+	if opts.Category != "" {
+		q.where("EXISTS (SELECT 1 FROM services AS s WHERE s.id = sr.service_id AND s.category = ?)", opts.Category)
+	}
+	if opts.ProviderID != "" {
+		q.where("EXISTS (SELECT 1 FROM service_provider_details AS p WHERE p.service_request_id = sr.id AND p.service_provider_id = ?)", opts.ProviderID)
+	}
+	if opts.OfferedTo != "" {
+		q.where("(sr.target_provider_id IS NULL OR sr.target_provider_id = ?)", opts.OfferedTo)
 	}
 
-	// Check for errors during iteration
-	if err := rows.Err(); err != nil {
+	orderBy, err := orderByClause(opts, serviceRequestSortColumns, "requested_time", "sr.id")
+	if err != nil {
+		return nil, err
+	}
+	limit, limitArgs, err := limitClause(opts)
+	if err != nil {
 		return nil, err
 	}
 
-	return requests, nil
-}
-
-// UpdateServiceRequest updates an existing service request in MySQL
-func (repo *ServiceRequestRepository) UpdateServiceRequest(updatedRequest *model.ServiceRequest) error {
-	query := `
-		UPDATE service_requests 
-		SET householder_id = ?, householder_name = ?, householder_address = ?, service_id = ?, requested_time = ?, scheduled_time = ?, status = ?, approve_status = ? 
-		WHERE id = ?
-	`
-
-	_, err := repo.db.Exec(query, updatedRequest.HouseholderID, updatedRequest.HouseholderName, updatedRequest.HouseholderAddress, updatedRequest.ServiceID, updatedRequest.RequestedTime, updatedRequest.ScheduledTime, updatedRequest.Status, updatedRequest.ApproveStatus, updatedRequest.ID)
-	return err
-}
-
-// GetAllServiceRequests retrieves all service requests from MySQL
-func (repo *ServiceRequestRepository) GetAllServiceRequests() ([]model.ServiceRequest, error) {
 	query := `
 		SELECT sr.id, sr.householder_id, sr.householder_name, sr.householder_address, sr.service_id, sr.requested_time, sr.scheduled_time, sr.status, sr.approve_status,
-		       spd.service_provider_id, spd.name, spd.contact, spd.address, spd.price, spd.rating, spd.approve
-		FROM service_requests AS sr
-		LEFT JOIN service_provider_details AS spd ON sr.id = spd.service_request_id
-	`
+		       sr.target_provider_id, spd.service_provider_id, spd.name, spd.contact, spd.address, spd.price, spd.rating, spd.approve
+		FROM (SELECT sr.* FROM service_requests AS sr` + q.whereClause() + orderBy + limit + `) AS sr
+		LEFT JOIN service_provider_details AS spd ON sr.id = spd.service_request_id` + orderBy
 
-	rows, err := repo.db.Query(query)
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, append(q.args, limitArgs...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -190,12 +195,15 @@ func (repo *ServiceRequestRepository) GetAllServiceRequests() ([]model.ServiceRe
 		err := rows.Scan(
 			&request.ID, &request.HouseholderID, &request.HouseholderName, &request.HouseholderAddress,
 			&request.ServiceID, &requestedTime, &scheduledTime, &request.Status, &request.ApproveStatus,
-			&providerID, &providerName, &providerContact, &providerAddress, &providerPrice,
+			&request.TargetProviderID, &providerID, &providerName, &providerContact, &providerAddress, &providerPrice,
 			&providerRating, &providerApprove,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if err := openNullableField(ctx, request.HouseholderAddress); err != nil {
+			return nil, err
+		}
 
 		// Parse the requested_time
 		request.RequestedTime, err = util.ParseTime(requestedTime)
@@ -210,26 +218,23 @@ func (repo *ServiceRequestRepository) GetAllServiceRequests() ([]model.ServiceRe
 		}
 
 		// Assign values to provider struct only if they are not NULL
-		if providerID.Valid {
-			provider.ServiceProviderID = providerID.String
-		}
-		if providerName.Valid {
-			provider.Name = providerName.String
-		}
-		if providerContact.Valid {
-			provider.Contact = providerContact.String
-		}
-		if providerAddress.Valid {
-			provider.Address = providerAddress.String
-		}
-		if providerPrice.Valid {
-			provider.Price = providerPrice.String
-		}
-		if providerRating.Valid {
-			provider.Rating = providerRating.Float64
+		provider.ServiceProviderID = providerID.String
+		provider.Name = providerName.String
+		provider.Contact = providerContact.String
+		provider.Address = providerAddress.String
+		if err := openFields(ctx, &provider.Contact, &provider.Address); err != nil {
+			return nil, err
 		}
-		if providerApprove.Valid {
-			provider.Approve = providerApprove.Bool
+		provider.Price = providerPrice.String
+		provider.Rating = providerRating.Float64
+		provider.Approve = providerApprove.Bool
+
+		// Rows of the same request are adjacent because of the ORDER BY, merge their providers
+		if n := len(requests); n > 0 && requests[n-1].ID == request.ID {
+			if providerID.Valid {
+				requests[n-1].ProviderDetails = append(requests[n-1].ProviderDetails, provider)
+			}
+			continue
 		}
 
 		// Append provider details if a valid provider is found
@@ -249,7 +254,10 @@ func (repo *ServiceRequestRepository) GetAllServiceRequests() ([]model.ServiceRe
 }
 
 // GetServiceRequestsByProviderID retrieves service requests by the provider ID from MySQL
-func (repo *ServiceRequestRepository) GetServiceRequestsByProviderID(providerID string) ([]model.ServiceRequest, error) {
+func (repo *ServiceRequestRepository) GetServiceRequestsByProviderID(ctx context.Context, providerID string) ([]model.ServiceRequest, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	query := `
 	SELECT sr.id, sr.householder_id, sr.householder_name, sr.householder_address, sr.service_id, sr.requested_time, sr.scheduled_time, sr.status, sr.approve_status 
 ,spd.service_provider_id,spd.name,spd.contact,spd.address
@@ -257,7 +265,7 @@ func (repo *ServiceRequestRepository) GetServiceRequestsByProviderID(providerID
 		WHERE spd.service_provider_id=?;
 	`
 
-	rows, err := repo.db.Query(query, providerID)
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, providerID)
 	if err != nil {
 		return nil, err
 	}
@@ -278,6 +286,9 @@ func (repo *ServiceRequestRepository) GetServiceRequestsByProviderID(providerID
 		if err != nil {
 			return nil, err
 		}
+		if err := openServiceRequestFields(ctx, &request, &provider); err != nil {
+			return nil, err
+		}
 		// Parse the requested_time
 		request.RequestedTime, err = util.ParseTime(requestedTime)
 		if err != nil {
@@ -296,14 +307,17 @@ func (repo *ServiceRequestRepository) GetServiceRequestsByProviderID(providerID
 	return requests, nil
 }
 
-func (repo *ServiceRequestRepository) GetServiceProviderByRequestID(requestID, providerID string) (*model.ServiceRequest, error) {
-	query := `SELECT sr.id, sr.householder_id, sr.householder_name, sr.householder_address, sr.service_id, sr.requested_time, sr.scheduled_time, sr.status, sr.approve_status,
-	spd.service_provider_id, spd.name, spd.contact, spd.address, spd.price, spd.rating, spd.approve
+func (repo *ServiceRequestRepository) GetServiceProviderByRequestID(ctx context.Context, requestID, providerID string) (*model.ServiceRequest, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT sr.id, sr.householder_id, sr.householder_name, sr.householder_address, sr.service_id, sr.requested_time, sr.scheduled_time, sr.status, sr.approve_status, sr.version,
+	spd.service_provider_id, spd.name, spd.contact, spd.address, spd.price, spd.rating, spd.approve, spd.version
 	FROM service_requests AS sr
 	INNER JOIN service_provider_details AS spd ON sr.id = spd.service_request_id
 	WHERE spd.service_provider_id = ? AND sr.id = ?`
 
-	rows, err := repo.db.Query(query, providerID, requestID)
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, providerID, requestID)
 	if err != nil {
 		return nil, err
 	}
@@ -319,13 +333,16 @@ func (repo *ServiceRequestRepository) GetServiceProviderByRequestID(requestID, p
 		// Scan the row data
 		err = rows.Scan(
 			&request.ID, &request.HouseholderID, &request.HouseholderName, &request.HouseholderAddress,
-			&request.ServiceID, &requestedTime, &scheduledTime, &request.Status, &request.ApproveStatus,
+			&request.ServiceID, &requestedTime, &scheduledTime, &request.Status, &request.ApproveStatus, &request.Version,
 			&provider.ServiceProviderID, &provider.Name, &provider.Contact, &provider.Address, &provider.Price,
-			&provider.Rating, &provider.Approve,
+			&provider.Rating, &provider.Approve, &provider.Version,
 		)
 		if err != nil {
 			return nil, err
 		}
+		if err := openServiceRequestFields(ctx, &request, &provider); err != nil {
+			return nil, err
+		}
 
 		// Parse the requested_time
 		request.RequestedTime, err = util.ParseTime(requestedTime)
@@ -347,3 +364,135 @@ func (repo *ServiceRequestRepository) GetServiceProviderByRequestID(requestID, p
 	// If no rows found, return an error
 	return nil, fmt.Errorf("no service request found for request ID: %s and provider ID: %s", requestID, providerID)
 }
+
+// GetApprovedProvider returns the offer the householder approved on a request
+func (repo *ServiceRequestRepository) GetApprovedProvider(ctx context.Context, requestID string) (*model.ServiceProviderDetails, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT service_provider_id, name, contact, address, price, rating, approve, version
+	FROM service_provider_details
+	WHERE service_request_id = ? AND approve = 1`
+
+	var provider model.ServiceProviderDetails
+	err := conn(ctx, repo.db).QueryRowContext(ctx, query, requestID).Scan(
+		&provider.ServiceProviderID, &provider.Name, &provider.Contact, &provider.Address, &provider.Price,
+		&provider.Rating, &provider.Approve, &provider.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("approved provider not found")
+		}
+		return nil, err
+	}
+	if err := openFields(ctx, &provider.Contact, &provider.Address); err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+
+// CountCompletedJobs counts the requests a provider was approved for and has completed
+func (repo *ServiceRequestRepository) CountCompletedJobs(ctx context.Context, providerID string) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT COUNT(*)
+	FROM service_requests sr
+	INNER JOIN service_provider_details spd ON spd.service_request_id = sr.id
+	WHERE spd.service_provider_id = ? AND spd.approve = 1 AND sr.status = ?`
+
+	var count int
+	if err := conn(ctx, repo.db).QueryRowContext(ctx, query, providerID, model.RequestCompleted).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// OpenToAllProviders turns a request booked directly with providerID into an open request every provider
+// can quote on. Only pending requests still booked with providerID change, otherwise a
+// *model.ConflictError is returned.
+func (repo *ServiceRequestRepository) OpenToAllProviders(ctx context.Context, requestID, providerID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE service_requests SET target_provider_id = NULL, version = version + 1
+	WHERE id = ? AND target_provider_id = ? AND status = 'Pending'`
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, requestID, providerID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return &model.ConflictError{Entity: "service request", ID: requestID}
+	}
+	return nil
+}
+
+// ReleaseDirectBookings opens every pending request booked directly with the provider to all providers and
+// returns the released requests with their ID and householder
+func (repo *ServiceRequestRepository) ReleaseDirectBookings(ctx context.Context, providerID string) ([]model.ServiceRequest, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var released []model.ServiceRequest
+	err := inTransaction(ctx, repo.db, func(ctx context.Context) error {
+		query := "SELECT id, householder_id FROM service_requests WHERE target_provider_id = ? AND status = 'Pending' FOR UPDATE"
+		rows, err := conn(ctx, repo.db).QueryContext(ctx, query, providerID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var request model.ServiceRequest
+			if err := rows.Scan(&request.ID, &request.HouseholderID); err != nil {
+				return err
+			}
+			released = append(released, request)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		query = `UPDATE service_requests SET target_provider_id = NULL, version = version + 1
+		WHERE target_provider_id = ? AND status = 'Pending'`
+		_, err = conn(ctx, repo.db).ExecContext(ctx, query, providerID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return released, nil
+}
+
+// AnonymiseHouseholder replaces the name and address copied onto the requests of a householder
+func (repo *ServiceRequestRepository) AnonymiseHouseholder(ctx context.Context, householderID, name string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE service_requests SET householder_name = ?, householder_address = NULL, version = version + 1 WHERE householder_id = ?"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, name, householderID)
+	return err
+}
+
+// AnonymiseProviderDetails replaces the name, contact and address copied into the offers of a provider. Prices
+// and ratings are kept.
+func (repo *ServiceRequestRepository) AnonymiseProviderDetails(ctx context.Context, providerID, name string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE service_provider_details SET name = ?, contact = '', address = '', version = version + 1 WHERE service_provider_id = ?"
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, name, providerID)
+	return err
+}
+
+// openServiceRequestFields decrypts the householder address of a request and the contact details of the
+// provider offer read with it
+func openServiceRequestFields(ctx context.Context, request *model.ServiceRequest, provider *model.ServiceProviderDetails) error {
+	if err := openNullableField(ctx, request.HouseholderAddress); err != nil {
+		return err
+	}
+	return openFields(ctx, &provider.Contact, &provider.Address)
+}
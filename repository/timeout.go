@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// queryTimeout is the deadline applied to every repository call; configured at startup.
+var queryTimeout = 5 * time.Second
+
+// SetQueryTimeout sets the per-call deadline applied by every repository method.
+func SetQueryTimeout(timeout time.Duration) {
+	queryTimeout = timeout
+}
+
+// withTimeout derives a context that is cancelled once the per-call deadline passes.
+func withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeout)
+}
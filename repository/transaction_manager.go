@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"serviceNest/interfaces"
+)
+
+type txKey struct{}
+
+// txState is stored in the context of a unit of work
+type txState struct {
+	tx          *sql.Tx
+	afterCommit []func()
+}
+
+// executor is the subset of *sql.DB and *sql.Tx used by the repositories.
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns the transaction carried by ctx, or db when the call is not part of one.
+func conn(ctx context.Context, db *sql.DB) executor {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx
+	}
+	return db
+}
+
+// afterCommit defers fn until the transaction carried by ctx has committed, it is dropped on rollback.
+// Outside a transaction fn runs immediately.
+func afterCommit(ctx context.Context, fn func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, fn)
+		return
+	}
+	fn()
+}
+
+type TransactionManager struct {
+	db *sql.DB
+}
+
+// NewTransactionManager creates a TransactionManager for the given MySQL connection pool
+func NewTransactionManager(db *sql.DB) interfaces.TransactionManager {
+	return &TransactionManager{db: db}
+}
+
+// WithinTransaction runs fn inside a transaction. Nested calls join the outer transaction.
+func (m *TransactionManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return inTransaction(ctx, m.db, fn)
+}
+
+func inTransaction(ctx context.Context, db *sql.DB, fn func(ctx context.Context) error) (err error) {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not start transaction: %v", err)
+	}
+	state := &txState{tx: tx}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+		if err = tx.Commit(); err != nil {
+			return
+		}
+		for _, hook := range state.afterCommit {
+			hook()
+		}
+	}()
+
+	return fn(context.WithValue(ctx, txKey{}, state))
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"time"
+)
+
+type TwoFactorRepository struct {
+	db *sql.DB
+}
+
+// NewTwoFactorRepository creates a TwoFactorRepository backed by MySQL
+func NewTwoFactorRepository(db *sql.DB) interfaces.TwoFactorRepository {
+	return &TwoFactorRepository{db: db}
+}
+
+// SaveSecret stores a new, not yet enabled secret for the user, replacing an unconfirmed one. The secret
+// of an enabled account is left alone.
+func (repo *TwoFactorRepository) SaveSecret(ctx context.Context, twoFactor model.TwoFactor) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `INSERT INTO user_totp (user_id, secret, enabled, last_used_step, created_at) VALUES (?, ?, FALSE, 0, ?)
+		ON DUPLICATE KEY UPDATE secret = IF(enabled, secret, VALUES(secret)), created_at = IF(enabled, created_at, VALUES(created_at))`
+	_, err := conn(ctx, repo.db).ExecContext(ctx, query, twoFactor.UserID, twoFactor.Secret, twoFactor.CreatedAt)
+	return err
+}
+
+// GetTwoFactor returns the TOTP settings of a user
+func (repo *TwoFactorRepository) GetTwoFactor(ctx context.Context, userID string) (*model.TwoFactor, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT user_id, secret, enabled, last_used_step, created_at, confirmed_at FROM user_totp WHERE user_id = ?`
+	var twoFactor model.TwoFactor
+	var createdAt, confirmedAt []uint8
+	err := conn(ctx, repo.db).QueryRowContext(ctx, query, userID).
+		Scan(&twoFactor.UserID, &twoFactor.Secret, &twoFactor.Enabled, &twoFactor.LastUsedStep, &createdAt, &confirmedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, errors.New("two-factor settings not found")
+		}
+		return nil, err
+	}
+	if twoFactor.CreatedAt, err = util.ParseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("error parsing created_at: %v", err)
+	}
+	if confirmedAt != nil {
+		confirmed, err := util.ParseTime(confirmedAt)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing confirmed_at: %v", err)
+		}
+		twoFactor.ConfirmedAt = &confirmed
+	}
+	return &twoFactor, nil
+}
+
+// EnableTwoFactor turns on a confirmed secret. The code used to confirm it counts as used.
+func (repo *TwoFactorRepository) EnableTwoFactor(ctx context.Context, userID string, step int64, confirmedAt time.Time) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE user_totp SET enabled = TRUE, last_used_step = ?, confirmed_at = ? WHERE user_id = ? AND enabled = FALSE"
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, step, confirmedAt, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return &model.ConflictError{Entity: "two-factor settings", ID: userID}
+	}
+	return nil
+}
+
+// UseStep records that the code of a time step was used. A code from the same or an earlier step is
+// reported as a conflict, so that an intercepted code cannot be replayed.
+func (repo *TwoFactorRepository) UseStep(ctx context.Context, userID string, step int64) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE user_totp SET last_used_step = ? WHERE user_id = ? AND enabled = TRUE AND last_used_step < ?"
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, step, userID, step)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return &model.ConflictError{Entity: "two-factor settings", ID: userID}
+	}
+	return nil
+}
+
+// DeleteTwoFactor removes the secret and the recovery codes of a user
+func (repo *TwoFactorRepository) DeleteTwoFactor(ctx context.Context, userID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	if _, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+	_, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM user_totp WHERE user_id = ?", userID)
+	return err
+}
+
+// ReplaceRecoveryCodes throws away the recovery codes of a user and stores new ones
+func (repo *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codes []model.RecoveryCode) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	if _, err := conn(ctx, repo.db).ExecContext(ctx, "DELETE FROM recovery_codes WHERE user_id = ?", userID); err != nil {
+		return err
+	}
+	query := "INSERT INTO recovery_codes (id, user_id, code_hash, created_at) VALUES (?, ?, ?, ?)"
+	for _, code := range codes {
+		if _, err := conn(ctx, repo.db).ExecContext(ctx, query, code.ID, userID, code.CodeHash, code.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode redeems an unused recovery code of the user
+func (repo *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL"
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, time.Now(), userID, codeHash)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return errors.New("recovery code not found")
+	}
+	return nil
+}
+
+// CountUnusedRecoveryCodes returns how many recovery codes the user has left
+func (repo *TwoFactorRepository) CountUnusedRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	var count int
+	query := "SELECT COUNT(*) FROM recovery_codes WHERE user_id = ? AND used_at IS NULL"
+	err := conn(ctx, repo.db).QueryRowContext(ctx, query, userID).Scan(&count)
+	return count, err
+}
@@ -1,11 +1,14 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 	"serviceNest/interfaces"
 	"serviceNest/model"
+	"serviceNest/util"
+	"time"
 )
 
 type UserRepository struct {
@@ -16,46 +19,62 @@ func NewUserRepository(db *sql.DB) interfaces.UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (repo *UserRepository) SaveUser(user *model.User) error {
-	query := `INSERT INTO users (id, name, email, password, role, address, contact, latitude, longitude) 
-              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := repo.db.Exec(query, user.ID, user.Name, user.Email, user.Password, user.Role, user.Address, user.Contact, user.Latitude, user.Longitude)
+const userColumns = "id, name, email, password, role, address, contact, latitude, longitude, email_verified, status, status_reason, status_expires_at"
+
+func (repo *UserRepository) SaveUser(ctx context.Context, user *model.User) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	address, contact, index, err := sealUserFields(ctx, user)
+	if err != nil {
+		return err
+	}
+	query := `INSERT INTO users (id, name, email, password, role, address, contact, contact_index, latitude, longitude, email_verified) 
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = conn(ctx, repo.db).ExecContext(ctx, query, user.ID, user.Name, user.Email, user.Password, user.Role, address, contact, index, user.Latitude, user.Longitude, user.EmailVerified)
 	return err
 }
 
-func (repo *UserRepository) GetUserByEmail(email string) (*model.User, error) {
-	query := `SELECT id, name, email, password, role, address, contact, latitude, longitude FROM users WHERE email = ?`
-	row := repo.db.QueryRow(query, email)
+func (repo *UserRepository) GetUserByEmail(ctx context.Context, email string) (*model.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 
-	var user model.User
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.Address, &user.Contact, &user.Latitude, &user.Longitude)
+	query := "SELECT " + userColumns + " FROM users WHERE email = ?"
+	user, err := scanUser(ctx, conn(ctx, repo.db).QueryRowContext(ctx, query, email))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("user not found")
 		}
 		return nil, err
 	}
-	return &user, nil
+	return user, nil
 }
 
-func (repo *UserRepository) UpdateUser(updatedUser *model.User) error {
+func (repo *UserRepository) UpdateUser(ctx context.Context, updatedUser *model.User) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
 	// Ensure the new email doesn't already exist in the system
-	existingUser, err := repo.GetUserByEmail(updatedUser.Email)
+	existingUser, err := repo.GetUserByEmail(ctx, updatedUser.Email)
 	if err == nil && existingUser.ID != updatedUser.ID {
 		return fmt.Errorf("email already in use")
 	}
 
-	query := `UPDATE users SET name=?, email=?, password=?, role=?, address=?, contact=?, latitude=?, longitude=? WHERE id=?`
-	_, err = repo.db.Exec(query, updatedUser.Name, updatedUser.Email, updatedUser.Password, updatedUser.Role, updatedUser.Address, updatedUser.Contact, updatedUser.Latitude, updatedUser.Longitude, updatedUser.ID)
+	address, contact, index, err := sealUserFields(ctx, updatedUser)
+	if err != nil {
+		return err
+	}
+	query := `UPDATE users SET name=?, email=?, password=?, role=?, address=?, contact=?, contact_index=?, latitude=?, longitude=?, email_verified=? WHERE id=?`
+	_, err = conn(ctx, repo.db).ExecContext(ctx, query, updatedUser.Name, updatedUser.Email, updatedUser.Password, updatedUser.Role, address, contact, index, updatedUser.Latitude, updatedUser.Longitude, updatedUser.EmailVerified, updatedUser.ID)
 	return err
 }
 
-func (repo *UserRepository) GetUserByID(userID string) (*model.User, error) {
-	query := `SELECT id, name, email, password, role, address, contact, latitude, longitude FROM users WHERE id = ?`
-	row := repo.db.QueryRow(query, userID)
+func (repo *UserRepository) GetUserByID(ctx context.Context, userID string) (*model.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
 
-	var user model.User
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.Address, &user.Contact, &user.Latitude, &user.Longitude)
+	query := "SELECT " + userColumns + " FROM users WHERE id = ?"
+	user, err := scanUser(ctx, conn(ctx, repo.db).QueryRowContext(ctx, query, userID))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("user not found")
@@ -63,5 +82,206 @@ func (repo *UserRepository) GetUserByID(userID string) (*model.User, error) {
 		return nil, err
 	}
 
+	return user, nil
+}
+
+// MarkEmailVerified records that the user proved they own their email address
+func (repo *UserRepository) MarkEmailVerified(ctx context.Context, userID string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	result, err := conn(ctx, repo.db).ExecContext(ctx, "UPDATE users SET email_verified = TRUE WHERE id = ?", userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// UpdatePassword replaces the password hash of a user
+func (repo *UserRepository) UpdatePassword(ctx context.Context, userID, passwordHash string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	result, err := conn(ctx, repo.db).ExecContext(ctx, "UPDATE users SET password = ? WHERE id = ?", passwordHash, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// UpdateAccountStatus moves an account from fromStatus to status. An account whose status changed in the
+// meantime is reported as a conflict so that two admins cannot overwrite each other's decision.
+func (repo *UserRepository) UpdateAccountStatus(ctx context.Context, userID, fromStatus, status, reason string, expiresAt *time.Time) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "UPDATE users SET status = ?, status_reason = ?, status_expires_at = ? WHERE id = ? AND status = ?"
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, status, nullableString(reason), expiresAt, userID, fromStatus)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return &model.ConflictError{Entity: "account", ID: userID}
+	}
+	return nil
+}
+
+// UpdateRole moves an account from fromRole to role. An account whose role changed in the meantime is
+// reported as a conflict.
+func (repo *UserRepository) UpdateRole(ctx context.Context, userID, fromRole, role string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	result, err := conn(ctx, repo.db).ExecContext(ctx, "UPDATE users SET role = ? WHERE id = ? AND role = ?", role, userID, fromRole)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return &model.ConflictError{Entity: "account", ID: userID}
+	}
+	return nil
+}
+
+// GetUsersByRole lists the accounts with the given role, ordered by name
+func (repo *UserRepository) GetUsersByRole(ctx context.Context, role string) ([]model.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := "SELECT " + userColumns + " FROM users WHERE role = ? ORDER BY name, id"
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		user, err := scanUser(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+	return users, rows.Err()
+}
+
+// AnonymiseUser replaces the personal data of an account with placeholders and removes its password, so
+// that nobody can log in to it any more
+func (repo *UserRepository) AnonymiseUser(ctx context.Context, userID, name, email string) error {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	query := `UPDATE users SET name = ?, email = ?, password = '', address = '', contact = '', contact_index = NULL, latitude = 0,
+		longitude = 0, email_verified = FALSE WHERE id = ?`
+	result, err := conn(ctx, repo.db).ExecContext(ctx, query, name, email, userID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+	return nil
+}
+
+// GetUsersByContact finds the accounts with the given contact number. With encryption turned on the
+// number is looked up by its blind index, which can collide, so the decrypted numbers are compared again.
+func (repo *UserRepository) GetUsersByContact(ctx context.Context, contact string) ([]model.User, error) {
+	ctx, cancel := withTimeout(ctx)
+	defer cancel()
+
+	contact = normalizeContact(contact)
+	if contact == "" {
+		return nil, nil
+	}
+	query := "SELECT " + userColumns + " FROM users WHERE contact = ? ORDER BY name, id"
+	arg := contact
+	index, err := contactIndex(ctx, contact)
+	if err != nil {
+		return nil, err
+	}
+	if index != nil {
+		query = "SELECT " + userColumns + " FROM users WHERE contact_index = ? ORDER BY name, id"
+		arg = *index
+	}
+
+	rows, err := conn(ctx, repo.db).QueryContext(ctx, query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []model.User
+	for rows.Next() {
+		user, err := scanUser(ctx, rows)
+		if err != nil {
+			return nil, err
+		}
+		if normalizeContact(user.Contact) == contact {
+			users = append(users, *user)
+		}
+	}
+	return users, rows.Err()
+}
+
+// sealUserFields returns the encrypted address and contact of user and the blind index of the contact
+func sealUserFields(ctx context.Context, user *model.User) (address, contact string, index *string, err error) {
+	if address, err = sealField(ctx, user.Address); err != nil {
+		return "", "", nil, err
+	}
+	if contact, err = sealField(ctx, user.Contact); err != nil {
+		return "", "", nil, err
+	}
+	if index, err = contactIndex(ctx, user.Contact); err != nil {
+		return "", "", nil, err
+	}
+	return address, contact, index, nil
+}
+
+func scanUser(ctx context.Context, row rowScanner) (*model.User, error) {
+	var user model.User
+	var reason sql.NullString
+	var expiresAt []uint8
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.Address, &user.Contact, &user.Latitude, &user.Longitude,
+		&user.EmailVerified, &user.Status, &reason, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	if err := openFields(ctx, &user.Address, &user.Contact); err != nil {
+		return nil, err
+	}
+	user.StatusReason = reason.String
+	if expiresAt != nil {
+		expires, err := util.ParseTime(expiresAt)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing status_expires_at: %v", err)
+		}
+		user.StatusExpiresAt = &expires
+	}
 	return &user, nil
 }
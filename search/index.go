@@ -0,0 +1,185 @@
+package search
+
+import (
+	"math"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"sort"
+	"sync"
+)
+
+// Weight of a term depending on the field it was found in
+const (
+	nameWeight        = 3.0
+	categoryWeight    = 2.0
+	providerWeight    = 1.5
+	descriptionWeight = 1.0
+)
+
+// Share of text relevance, provider rating and proximity in the final score
+const (
+	relevanceWeight = 0.6
+	ratingWeight    = 0.25
+	distanceWeight  = 0.15
+
+	// distanceScaleKm is the distance at which the proximity score has dropped to one half
+	distanceScaleKm = 10.0
+	maxRating       = 5.0
+)
+
+// Index is an in-memory inverted index over services. It is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	docs     map[string]model.SearchDocument
+	postings map[string]map[string]float64 // term -> service ID -> weighted term frequency
+	docTerms map[string][]string           // service ID -> indexed terms, used for removal
+}
+
+// NewIndex creates an empty search index
+func NewIndex() interfaces.ServiceIndex {
+	return &Index{
+		docs:     make(map[string]model.SearchDocument),
+		postings: make(map[string]map[string]float64),
+		docTerms: make(map[string][]string),
+	}
+}
+
+// Index adds the document or replaces the one indexed under the same service ID
+func (idx *Index) Index(doc model.SearchDocument) {
+	weights := make(map[string]float64)
+	addField(weights, doc.Service.Name, nameWeight)
+	addField(weights, doc.Service.Category, categoryWeight)
+	addField(weights, doc.ProviderName, providerWeight)
+	addField(weights, doc.Service.Description, descriptionWeight)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.remove(doc.Service.ID)
+	terms := make([]string, 0, len(weights))
+	for term, weight := range weights {
+		posting, ok := idx.postings[term]
+		if !ok {
+			posting = make(map[string]float64)
+			idx.postings[term] = posting
+		}
+		posting[doc.Service.ID] = weight
+		terms = append(terms, term)
+	}
+	idx.docs[doc.Service.ID] = doc
+	idx.docTerms[doc.Service.ID] = terms
+}
+
+// Remove drops the service from the index, unknown IDs are ignored
+func (idx *Index) Remove(serviceID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(serviceID)
+}
+
+// Len returns the number of indexed services
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+func (idx *Index) remove(serviceID string) {
+	for _, term := range idx.docTerms[serviceID] {
+		delete(idx.postings[term], serviceID)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.docTerms, serviceID)
+	delete(idx.docs, serviceID)
+}
+
+func addField(weights map[string]float64, text string, weight float64) {
+	for _, term := range tokenize(text) {
+		weights[term] += weight
+	}
+}
+
+// Search returns the services matching the query ordered by score, best first. Every query term
+// may match exactly, as a prefix or with a few typos; documents matching more terms rank higher.
+func (idx *Index) Search(query model.SearchQuery) []model.SearchResult {
+	queryTerms := tokenize(query.Text)
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	total := float64(len(idx.docs))
+	relevance := make(map[string]float64)
+	matchedTerms := make(map[string]int)
+	for _, queryTerm := range queryTerms {
+		// Only the best matching indexed term counts for each document
+		best := make(map[string]float64)
+		for term, posting := range idx.postings {
+			quality := matchQuality(queryTerm, term)
+			if quality == 0 {
+				continue
+			}
+			idf := math.Log(1 + total/float64(len(posting)))
+			for serviceID, weight := range posting {
+				if score := quality * weight * idf; score > best[serviceID] {
+					best[serviceID] = score
+				}
+			}
+		}
+		for serviceID, score := range best {
+			relevance[serviceID] += score
+			matchedTerms[serviceID]++
+		}
+	}
+
+	var maxRelevance float64
+	for serviceID, score := range relevance {
+		score *= float64(matchedTerms[serviceID]) / float64(len(queryTerms))
+		relevance[serviceID] = score
+		maxRelevance = math.Max(maxRelevance, score)
+	}
+
+	results := make([]model.SearchResult, 0, len(relevance))
+	for serviceID, score := range relevance {
+		doc := idx.docs[serviceID]
+		result := model.SearchResult{
+			Service:    doc.Service,
+			Relevance:  score / maxRelevance,
+			DistanceKm: -1,
+		}
+		result.Service.ProviderName = doc.ProviderName
+		result.Service.ProviderRating = doc.ProviderRating
+
+		if query.UseLocation && (doc.Latitude != 0 || doc.Longitude != 0) {
+			result.DistanceKm = distanceKm(query.Latitude, query.Longitude, doc.Latitude, doc.Longitude)
+		}
+		result.Score = combinedScore(result.Relevance, doc.ProviderRating, result.DistanceKm)
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Service.ID < results[j].Service.ID
+	})
+	if query.Limit > 0 && len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+	return results
+}
+
+// combinedScore blends relevance, rating and proximity into a value between 0 and 1. When the
+// distance is unknown its share is left out instead of counting it as far away.
+func combinedScore(relevance, rating, distance float64) float64 {
+	rating = math.Min(math.Max(rating, 0), maxRating) / maxRating
+	if distance < 0 {
+		return (relevanceWeight*relevance + ratingWeight*rating) / (relevanceWeight + ratingWeight)
+	}
+	proximity := distanceScaleKm / (distanceScaleKm + distance)
+	return relevanceWeight*relevance + ratingWeight*rating + distanceWeight*proximity
+}
@@ -0,0 +1,121 @@
+package search
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Quality of a match between a query term and an indexed term
+const (
+	exactMatch       = 1.0
+	prefixMatch      = 0.8
+	fuzzyMatch       = 0.6
+	fuzzyPrefixMatch = 0.5
+)
+
+// minPrefixLength is the shortest query term that is matched as a prefix
+const minPrefixLength = 2
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "at": true, "for": true, "in": true,
+	"of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// tokenize lower-cases text and splits it into words, dropping stop words
+func tokenize(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := words[:0]
+	for _, word := range words {
+		if !stopWords[word] {
+			tokens = append(tokens, word)
+		}
+	}
+	return tokens
+}
+
+// maxEdits is the number of typos tolerated for a query term of the given length
+func maxEdits(length int) int {
+	switch {
+	case length < 4:
+		return 0
+	case length < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// matchQuality scores how well the query term q matches the indexed term t, 0 meaning no match
+func matchQuality(q, t string) float64 {
+	if q == t {
+		return exactMatch
+	}
+
+	queryRunes, termRunes := []rune(q), []rune(t)
+	if len(queryRunes) >= minPrefixLength && strings.HasPrefix(t, q) {
+		return prefixMatch
+	}
+
+	edits := maxEdits(len(queryRunes))
+	if edits == 0 {
+		return 0
+	}
+	if abs(len(queryRunes)-len(termRunes)) <= edits && editDistance(queryRunes, termRunes) <= edits {
+		return fuzzyMatch
+	}
+	// A typo in a partially typed word, e.g. "plumm" for "plumbing"
+	if len(termRunes) > len(queryRunes) && editDistance(queryRunes, termRunes[:len(queryRunes)]) <= edits {
+		return fuzzyPrefixMatch
+	}
+	return 0
+}
+
+// editDistance is the optimal string alignment distance: insertions, deletions, substitutions
+// and transpositions of adjacent characters each count as one edit
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+const earthRadiusKm = 6371.0
+
+// distanceKm is the great-circle distance between two coordinates
+func distanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
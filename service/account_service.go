@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"strings"
+	"time"
+)
+
+// systemActor is recorded as the author of status changes nobody made by hand, such as an expired suspension
+const systemActor = "system"
+
+// AccountService lets admins suspend, ban, delete and reactivate accounts of any role and checks that an
+// account may still be used
+type AccountService struct {
+	userRepo           interfaces.UserRepository
+	accountStatusRepo  interfaces.AccountStatusRepository
+	providerRepo       interfaces.ServiceProviderRepository
+	serviceRequestRepo interfaces.ServiceRequestRepository
+	notificationRepo   interfaces.NotificationRepository
+	providerIndexer    interfaces.ProviderIndexer
+	auditRepo          interfaces.AuditRepository
+	txManager          interfaces.TransactionManager
+}
+
+// NewAccountService initializes a new AccountService
+func NewAccountService(userRepo interfaces.UserRepository, accountStatusRepo interfaces.AccountStatusRepository, providerRepo interfaces.ServiceProviderRepository, serviceRequestRepo interfaces.ServiceRequestRepository, notificationRepo interfaces.NotificationRepository, providerIndexer interfaces.ProviderIndexer, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *AccountService {
+	return &AccountService{
+		userRepo:           userRepo,
+		accountStatusRepo:  accountStatusRepo,
+		providerRepo:       providerRepo,
+		serviceRequestRepo: serviceRequestRepo,
+		notificationRepo:   notificationRepo,
+		providerIndexer:    providerIndexer,
+		auditRepo:          auditRepo,
+		txManager:          txManager,
+	}
+}
+
+// EnsureActive returns a *model.AccountInactiveError when the account may not be used. A suspension or
+// ban whose expiry has passed is lifted on the way.
+func (s *AccountService) EnsureActive(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	status := accountStatus(user)
+	if !user.HasAccess(time.Now()) {
+		return &model.AccountInactiveError{Status: status, Reason: user.StatusReason, ExpiresAt: user.StatusExpiresAt}
+	}
+	if status == model.AccountActive {
+		return nil
+	}
+
+	reason := "suspension expired"
+	if status == model.AccountBanned {
+		reason = "ban expired"
+	}
+	err = s.changeStatus(ctx, systemActor, user, model.AccountActive, reason, nil)
+	if errors.Is(err, model.ErrConflict) {
+		// An admin changed the account at the same time, their decision stands
+		return s.EnsureActive(ctx, userID)
+	}
+	return err
+}
+
+// GetAccount returns a user together with the current status of the account
+func (s *AccountService) GetAccount(ctx context.Context, userID string) (*model.User, error) {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	user.Status = accountStatus(user)
+	return user, nil
+}
+
+// GetStatusHistory lists the status changes of an account, most recent first
+func (s *AccountService) GetStatusHistory(ctx context.Context, userID string) ([]model.AccountStatusChange, error) {
+	return s.accountStatusRepo.GetStatusHistory(ctx, userID)
+}
+
+// FindAccountsByContact lists the accounts registered with a contact number, for example to find the
+// account behind a support call
+func (s *AccountService) FindAccountsByContact(ctx context.Context, contact string) ([]model.User, error) {
+	users, err := s.userRepo.GetUsersByContact(ctx, contact)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		users[i].Status = accountStatus(&users[i])
+	}
+	return users, nil
+}
+
+// SuspendAccount blocks an account, until the given time when until is set. Open requests of the account
+// are cancelled.
+func (s *AccountService) SuspendAccount(ctx context.Context, adminID, userID, reason string, until *time.Time) error {
+	return s.adminChange(ctx, adminID, userID, model.AccountSuspended, reason, until, model.AccountActive, model.AccountSuspended)
+}
+
+// BanAccount blocks an account for a serious breach, permanently unless until is set. Open requests of
+// the account are cancelled.
+func (s *AccountService) BanAccount(ctx context.Context, adminID, userID, reason string, until *time.Time) error {
+	return s.adminChange(ctx, adminID, userID, model.AccountBanned, reason, until, model.AccountActive, model.AccountSuspended, model.AccountBanned)
+}
+
+// DeleteAccount closes an account for good, a deleted account cannot be reactivated
+func (s *AccountService) DeleteAccount(ctx context.Context, adminID, userID, reason string) error {
+	return s.adminChange(ctx, adminID, userID, model.AccountDeleted, reason, nil, model.AccountActive, model.AccountSuspended, model.AccountBanned)
+}
+
+// ReactivateAccount lifts a suspension or ban ahead of its expiry
+func (s *AccountService) ReactivateAccount(ctx context.Context, adminID, userID, reason string) error {
+	return s.adminChange(ctx, adminID, userID, model.AccountActive, reason, nil, model.AccountSuspended, model.AccountBanned)
+}
+
+// adminChange validates a status change requested by an admin; allowedFrom lists the statuses the
+// account may currently have
+func (s *AccountService) adminChange(ctx context.Context, adminID, userID, status, reason string, until *time.Time, allowedFrom ...string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required to change the status of an account")
+	}
+	if until != nil && !until.After(time.Now()) {
+		return errors.New("the expiry must be in the future")
+	}
+	if adminID == userID {
+		return errors.New("you cannot change the status of your own account")
+	}
+	if err := s.EnsureActive(ctx, adminID); err != nil {
+		return err
+	}
+
+	return retryOnConflict(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		current := accountStatus(user)
+		allowed := false
+		for _, from := range allowedFrom {
+			allowed = allowed || current == from
+		}
+		if !allowed {
+			return fmt.Errorf("cannot change an account from %s to %s", current, status)
+		}
+		return s.changeStatus(ctx, adminID, user, status, reason, until)
+	})
+}
+
+// changeStatus records the new status with its history entry, keeps the provider profile in step and,
+// when the account loses access, cancels its open requests and opens the requests booked directly with a
+// provider to all providers. The user is told what happened.
+func (s *AccountService) changeStatus(ctx context.Context, actorID string, user *model.User, status, reason string, until *time.Time) error {
+	from := accountStatus(user)
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateAccountStatus(ctx, user.ID, from, status, reason, until); err != nil {
+			return err
+		}
+		err := s.accountStatusRepo.SaveStatusChange(ctx, model.AccountStatusChange{
+			ID:         GetUniqueID(),
+			UserID:     user.ID,
+			FromStatus: from,
+			ToStatus:   status,
+			Reason:     reason,
+			ExpiresAt:  until,
+			ChangedBy:  actorID,
+			ChangedAt:  time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		after := *user
+		after.Status, after.StatusReason, after.StatusExpiresAt = status, reason, until
+		if err := audit(WithActor(ctx, actorID), s.auditRepo, "change status", model.EntityUser, user.ID, user, after); err != nil {
+			return err
+		}
+
+		if user.Role == model.RoleServiceProvider {
+			if err := s.updateProvider(ctx, user.ID, status == model.AccountActive); err != nil {
+				return err
+			}
+		}
+		if status != model.AccountActive {
+			if err := s.cancelOpenRequests(ctx, user); err != nil {
+				return err
+			}
+			if user.Role == model.RoleServiceProvider {
+				if err := s.releaseDirectBookings(ctx, user.ID); err != nil {
+					return err
+				}
+			}
+		}
+		return notify(ctx, s.notificationRepo, user.ID, statusMessage(status, reason, until))
+	})
+}
+
+// updateProvider takes a provider off or back on the market. Providers who never opened their dashboard
+// have no profile yet and nothing to update.
+func (s *AccountService) updateProvider(ctx context.Context, providerID string, active bool) error {
+	provider, err := s.providerRepo.GetProviderByID(ctx, providerID)
+	if err != nil {
+		if err.Error() == "provider not found" {
+			return nil
+		}
+		return err
+	}
+	if provider.IsActive != active {
+		provider.IsActive = active
+		if err := s.providerRepo.UpdateServiceProvider(ctx, provider); err != nil {
+			return err
+		}
+	}
+	return s.providerIndexer.ReindexProvider(ctx, providerID)
+}
+
+// cancelOpenRequests cancels the pending and accepted requests of a householder, or the requests a
+// provider has been approved for, and tells the other party
+func (s *AccountService) cancelOpenRequests(ctx context.Context, user *model.User) error {
+	var requests []model.ServiceRequest
+	var err error
+	if user.Role == model.RoleServiceProvider {
+		requests, err = s.serviceRequestRepo.GetAllServiceRequests(ctx, model.QueryOptions{ProviderID: user.ID})
+	} else {
+		requests, err = s.serviceRequestRepo.GetServiceRequestsByHouseholderID(ctx, user.ID, model.QueryOptions{})
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, listed := range requests {
+		if !isOpenRequest(listed.Status) {
+			continue
+		}
+		if user.Role == model.RoleServiceProvider && !approvedProvider(listed, user.ID) {
+			continue
+		}
+
+		// Listings carry no version, the request is reloaded for the conditional update. It may have been
+		// completed, declined or handed to another provider since it was listed.
+		request, err := s.reloadRequest(ctx, user, listed.ID)
+		if err != nil {
+			return err
+		}
+		if !isOpenRequest(request.Status) || user.Role == model.RoleServiceProvider && !approvedProvider(*request, user.ID) {
+			continue
+		}
+		request.Status = "Cancelled"
+		err = s.serviceRequestRepo.UpdateServiceRequest(ctx, request)
+		if errors.Is(err, model.ErrConflict) {
+			// Changed by the other party in the meantime, their change stands
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if user.Role == model.RoleServiceProvider {
+			if request.HouseholderID != nil {
+				message := fmt.Sprintf("Service request %s was cancelled because the provider is no longer available. Please request the service again.", request.ID)
+				if err := notify(ctx, s.notificationRepo, *request.HouseholderID, message); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		for _, provider := range listed.ProviderDetails {
+			message := fmt.Sprintf("Service request %s was cancelled because the householder's account is no longer active.", request.ID)
+			if err := notify(ctx, s.notificationRepo, provider.ServiceProviderID, message); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// releaseDirectBookings opens the pending requests booked directly with a provider to all providers and
+// tells their householders
+func (s *AccountService) releaseDirectBookings(ctx context.Context, providerID string) error {
+	released, err := s.serviceRequestRepo.ReleaseDirectBookings(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	for _, request := range released {
+		if request.HouseholderID == nil {
+			continue
+		}
+		message := fmt.Sprintf("The provider you booked directly is no longer available for request %s. It is now open to all providers.", request.ID)
+		if err := notify(ctx, s.notificationRepo, *request.HouseholderID, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reloadRequest reads a request again, for a provider together with their offer on it
+func (s *AccountService) reloadRequest(ctx context.Context, user *model.User, requestID string) (*model.ServiceRequest, error) {
+	if user.Role == model.RoleServiceProvider {
+		return s.serviceRequestRepo.GetServiceProviderByRequestID(ctx, requestID, user.ID)
+	}
+	return s.serviceRequestRepo.GetServiceRequestByID(ctx, requestID)
+}
+
+func isOpenRequest(status string) bool {
+	return status == "Pending" || status == "Accepted"
+}
+
+func approvedProvider(request model.ServiceRequest, providerID string) bool {
+	for _, provider := range request.ProviderDetails {
+		if provider.ServiceProviderID == providerID {
+			return provider.Approve
+		}
+	}
+	return false
+}
+
+// accountStatus treats accounts created before statuses existed as active
+func accountStatus(user *model.User) string {
+	if user.Status == "" {
+		return model.AccountActive
+	}
+	return user.Status
+}
+
+func statusMessage(status, reason string, until *time.Time) string {
+	var message string
+	switch status {
+	case model.AccountActive:
+		return "Your account has been reactivated."
+	case model.AccountSuspended:
+		message = "Your account has been suspended"
+	case model.AccountBanned:
+		message = "Your account has been banned"
+	default:
+		message = "Your account has been deleted"
+	}
+	if until != nil {
+		message += " until " + until.Format("2006-01-02 15:04")
+	}
+	return fmt.Sprintf("%s: %s. Your open requests have been cancelled.", message, reason)
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"serviceNest/interfaces"
 	"serviceNest/model"
 )
@@ -11,39 +12,43 @@ type AdminService struct {
 	//serviceAreaRepo *repository_test.ServiceAreaRepository
 	providerRepo       interfaces.ServiceProviderRepository
 	serviceRequestRepo interfaces.ServiceRequestRepository
+	auditRepo          interfaces.AuditRepository
+	txManager          interfaces.TransactionManager
 }
 
-func NewAdminService(serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository, userRepo interfaces.UserRepository, providerRepo interfaces.ServiceProviderRepository) *AdminService {
+func NewAdminService(serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository, userRepo interfaces.UserRepository, providerRepo interfaces.ServiceProviderRepository, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *AdminService {
 	return &AdminService{
 		serviceRepo: serviceRepo,
 		userRepo:    userRepo,
 		//serviceAreaRepo: serviceAreaRepo,
 		providerRepo:       providerRepo,
 		serviceRequestRepo: serviceRequestRepo,
+		auditRepo:          auditRepo,
+		txManager:          txManager,
 	}
 }
 
 // View reports
-func (s *AdminService) ViewReports() ([]model.ServiceRequest, error) {
+func (s *AdminService) ViewReports(ctx context.Context, opts model.QueryOptions) ([]model.ServiceRequest, error) {
 
-	return s.serviceRequestRepo.GetAllServiceRequests()
+	return s.serviceRequestRepo.GetAllServiceRequests(ctx, opts)
 
 }
-func (s *AdminService) DeleteService(serviceID string) error {
-	return s.serviceRepo.RemoveService(serviceID)
-}
 
-// Deactivate account
-func (s *AdminService) DeactivateAccount(userID string) error {
-	provider, err := s.providerRepo.GetProviderByID(userID)
+// DeleteService removes a service from the catalogue, whoever offers it
+func (s *AdminService) DeleteService(ctx context.Context, serviceID string) error {
+	service, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
 	if err != nil {
 		return err
 	}
-
-	provider.IsActive = false
-	return s.providerRepo.UpdateServiceProvider(provider)
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.serviceRepo.RemoveService(ctx, serviceID); err != nil {
+			return err
+		}
+		return audit(ctx, s.auditRepo, "delete", model.EntityService, serviceID, service, nil)
+	})
 }
 
-func (s *AdminService) GetAllService() ([]model.Service, error) {
-	return s.serviceRepo.GetAllServices()
+func (s *AdminService) GetAllService(ctx context.Context, opts model.QueryOptions) ([]model.Service, error) {
+	return s.serviceRepo.GetAllServices(ctx, opts)
 }
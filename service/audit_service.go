@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"time"
+)
+
+// auditVerifyBatch is how many entries are read at a time while checking the chain
+const auditVerifyBatch = 500
+
+// redactedFields are never written to audit snapshots. Addresses and contact numbers are encrypted in
+// their own tables, the append-only log must not keep plaintext copies of them.
+var redactedFields = map[string]bool{
+	"password":            true,
+	"address":             true,
+	"contact":             true,
+	"householder_address": true,
+	"provider_address":    true,
+	"provider_contact":    true,
+}
+
+// personalFields are left out of the snapshots of the entity types that hold them. Names, emails and the
+// text people write are erased with their account and the log cannot be rewritten later, so it keeps the
+// IDs instead. Service, category and certification names are not personal and stay.
+var personalFields = map[string]map[string]bool{
+	model.EntityUser:           {"name": true, "email": true, "provider_name": true},
+	model.EntityProvider:       {"bio": true},
+	model.EntityService:        {"provider_name": true},
+	model.EntityServiceRequest: {"householder_name": true, "name": true, "comments": true, "reply": true},
+	model.EntityCustomRequest:  {"householder_name": true},
+	model.EntityReview:         {"comments": true, "reply": true},
+}
+
+type actorKey struct{}
+
+// WithActor returns a context acting on behalf of userID. Changes made with it are recorded in the audit
+// log under that user, changes made without one under "system".
+func WithActor(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, actorKey{}, userID)
+}
+
+func actorFrom(ctx context.Context) string {
+	if userID, ok := ctx.Value(actorKey{}).(string); ok && userID != "" {
+		return userID
+	}
+	return systemActor
+}
+
+// audit appends an entry to the audit log. before and after are snapshots of the entity, nil when it did
+// not exist before or does not exist after the change. Call it inside the transaction of the change so
+// that both are kept or dropped together.
+func audit(ctx context.Context, auditRepo interfaces.AuditRepository, action, entityType, entityID string, before, after interface{}) error {
+	entry := model.AuditEntry{
+		ID:         GetUniqueID(),
+		ActorID:    actorFrom(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		CreatedAt:  time.Now().UTC().Truncate(time.Second),
+	}
+	var err error
+	if entry.Before, err = snapshot(entityType, before); err != nil {
+		return err
+	}
+	if entry.After, err = snapshot(entityType, after); err != nil {
+		return err
+	}
+	return auditRepo.AppendEntry(ctx, &entry)
+}
+
+// snapshot encodes v as JSON without secrets such as password hashes or the personal details of its entity type
+func snapshot(entityType string, v interface{}) (string, error) {
+	if v == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("could not snapshot %T for the audit log: %v", v, err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return "", err
+	}
+	if decoded == nil {
+		return "", nil
+	}
+	data, err = json.Marshal(redact(decoded, personalFields[entityType]))
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func redact(v interface{}, personal map[string]bool) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		for key, field := range value {
+			if redactedFields[key] || personal[key] {
+				delete(value, key)
+				continue
+			}
+			value[key] = redact(field, personal)
+		}
+	case []interface{}:
+		for i, item := range value {
+			value[i] = redact(item, personal)
+		}
+	}
+	return v
+}
+
+// AuditService lets admins search the audit log and check that it has not been tampered with
+type AuditService struct {
+	auditRepo interfaces.AuditRepository
+}
+
+// NewAuditService initializes a new AuditService
+func NewAuditService(auditRepo interfaces.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// GetEntries returns the entries matching the filter, newest first
+func (s *AuditService) GetEntries(ctx context.Context, filter model.AuditFilter) ([]model.AuditEntry, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && !filter.From.Before(filter.To) {
+		return nil, fmt.Errorf("the start of the time range must be before its end")
+	}
+	return s.auditRepo.GetEntries(ctx, filter)
+}
+
+// VerifyChain walks the whole log from the first entry and reports the first one that was changed,
+// removed or inserted out of turn
+func (s *AuditService) VerifyChain(ctx context.Context) (*model.AuditVerification, error) {
+	headSeq, headHash, err := s.auditRepo.GetChainHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := &model.AuditVerification{HeadSeq: headSeq, HeadHash: headHash}
+
+	var prevSeq int64
+	var prevHash string
+	for {
+		entries, err := s.auditRepo.GetEntriesAfter(ctx, prevSeq, auditVerifyBatch)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			switch {
+			case entry.Seq != prevSeq+1:
+				result.BrokenAt, result.Problem = prevSeq+1, "entry is missing"
+			case entry.PrevHash != prevHash:
+				result.BrokenAt, result.Problem = entry.Seq, "entry does not link to the one before it"
+			case entry.ComputeHash() != entry.Hash:
+				result.BrokenAt, result.Problem = entry.Seq, "entry content does not match its hash"
+			}
+			if !result.Intact() {
+				return result, nil
+			}
+			result.Checked++
+			prevSeq, prevHash = entry.Seq, entry.Hash
+		}
+		if len(entries) < auditVerifyBatch {
+			break
+		}
+	}
+
+	if prevSeq != headSeq || prevHash != headHash {
+		result.BrokenAt, result.Problem = prevSeq+1, "newest entries are missing"
+	}
+	return result, nil
+}
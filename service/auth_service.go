@@ -0,0 +1,512 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"log/slog"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuthOptions controls how AuthService signs users in and how long emailed codes stay valid
+type AuthOptions struct {
+	RequireVerifiedEmail bool
+	VerificationTokenTTL time.Duration
+	ResetTokenTTL        time.Duration
+
+	MaxFailedLogins          int // failures before an account is locked, 0 turns locking off
+	MaxFailedLoginsPerSource int // failures before a remote source is locked, 0 turns locking off
+	LockoutDuration          time.Duration
+}
+
+const (
+	// failedLoginWindow is how long a failed login counts towards delays and lockouts
+	failedLoginWindow = time.Hour
+	// loginChallengeTTL is how long a user has to enter their second factor after the password
+	loginChallengeTTL = 5 * time.Minute
+)
+
+// loginDelay is how long an account or source has to wait after its latest failure. The first failures are
+// free, then the wait doubles with every failure up to a minute.
+func loginDelay(failures int) time.Duration {
+	const freeFailures = 2
+	if failures <= freeFailures {
+		return 0
+	}
+	delay := time.Second << (failures - freeFailures - 1)
+	if delay <= 0 || delay > time.Minute {
+		return time.Minute
+	}
+	return delay
+}
+
+// AuthService checks credentials, slows down and locks out repeated failed logins, and runs the email
+// verification and password reset flows. Codes are sent by email, are single-use and expire; only their
+// hashes are stored.
+type AuthService struct {
+	userRepo         interfaces.UserRepository
+	tokenRepo        interfaces.AuthTokenRepository
+	loginAttemptRepo interfaces.LoginAttemptRepository
+	twoFactor        *TwoFactorService
+	mailer           interfaces.Mailer
+	accountGuard     interfaces.AccountGuard
+	auditRepo        interfaces.AuditRepository
+	options          AuthOptions
+	txManager        interfaces.TransactionManager
+}
+
+// NewAuthService initializes a new AuthService
+func NewAuthService(userRepo interfaces.UserRepository, tokenRepo interfaces.AuthTokenRepository, loginAttemptRepo interfaces.LoginAttemptRepository, twoFactor *TwoFactorService, mailer interfaces.Mailer, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, options AuthOptions, txManager interfaces.TransactionManager) *AuthService {
+	return &AuthService{
+		userRepo:         userRepo,
+		tokenRepo:        tokenRepo,
+		loginAttemptRepo: loginAttemptRepo,
+		twoFactor:        twoFactor,
+		mailer:           mailer,
+		accountGuard:     accountGuard,
+		auditRepo:        auditRepo,
+		options:          options,
+		txManager:        txManager,
+	}
+}
+
+// Login returns the user with the given credentials. Unknown addresses and wrong passwords both return
+// model.ErrInvalidCredentials and count towards the lockout of the address and of the source, so the
+// answers never tell whether an account exists. Blocked accounts get a *model.AccountInactiveError once
+// the password has been checked. While either has to wait, a *model.LoginThrottledError is
+// returned without checking the password.
+//
+// Users with two-factor authentication, and admins who still have to set it up, get a
+// *model.SecondFactorRequiredError instead of the user; its challenge is passed on to CompleteLogin or
+// CompleteEnrolment.
+func (s *AuthService) Login(ctx context.Context, email, password, source string) (*model.User, error) {
+	now := time.Now()
+	accountKey := normalizeEmail(email)
+
+	retryAfter, err := s.loginWait(ctx, now, accountKey, source)
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter > 0 {
+		if err := s.audit(ctx, email, "", source, model.LoginThrottled, now); err != nil {
+			return nil, err
+		}
+		return nil, &model.LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil && err.Error() != "user not found" {
+		return nil, err
+	}
+	if !passwordMatches(user, password) {
+		if err := s.recordFailedLogin(ctx, now, email, source, user, model.LoginInvalidCredentials); err != nil {
+			return nil, err
+		}
+		return nil, model.ErrInvalidCredentials
+	}
+	if !model.ValidRole(user.Role) {
+		// Nothing decides what such an account may do, so it gets nothing
+		slog.Warn("login refused for an account with an unknown role", "user_id", user.ID, "role", user.Role)
+		return nil, model.ErrUnknownRole
+	}
+	if err := s.accountGuard.EnsureActive(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	if s.options.RequireVerifiedEmail && !user.EmailVerified {
+		return nil, model.ErrEmailNotVerified
+	}
+	enabled, err := s.twoFactor.IsEnabled(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled || requiresTwoFactor(user) {
+		challenge, _, err := s.saveToken(ctx, user.ID, model.TokenLoginChallenge, loginChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &model.SecondFactorRequiredError{UserID: user.ID, Challenge: challenge, EnrolmentRequired: !enabled}
+	}
+
+	if err := s.loginAttemptRepo.ResetThrottle(ctx, model.ThrottleAccount, accountKey); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// CompleteLogin finishes a login with an authentication code or a recovery code. A wrong code counts as a
+// failed login.
+func (s *AuthService) CompleteLogin(ctx context.Context, challenge, code, source string) (*model.User, error) {
+	return s.secondStep(ctx, challenge, source, func(user *model.User) error {
+		return s.twoFactor.Verify(ctx, user.ID, code)
+	})
+}
+
+// CompleteEnrolment finishes the login of an admin who set up two-factor authentication on the way, see
+// TwoFactorService.StartEnrolment. It returns the new recovery codes along with the user.
+func (s *AuthService) CompleteEnrolment(ctx context.Context, challenge, code, source string) (*model.User, []string, error) {
+	var recoveryCodes []string
+	user, err := s.secondStep(ctx, challenge, source, func(user *model.User) error {
+		var err error
+		recoveryCodes, err = s.twoFactor.ConfirmEnrolment(ctx, user.ID, code)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, recoveryCodes, nil
+}
+
+// secondStep checks the second factor of the login behind challenge and redeems the challenge when check
+// accepts it
+func (s *AuthService) secondStep(ctx context.Context, challenge, source string, check func(user *model.User) error) (*model.User, error) {
+	now := time.Now()
+	token, err := s.tokenRepo.GetTokenByHash(ctx, hashTokenCode(challenge))
+	if err != nil {
+		if err.Error() == "token not found" {
+			return nil, model.ErrLoginExpired
+		}
+		return nil, err
+	}
+	if token.Purpose != model.TokenLoginChallenge || token.UsedAt != nil || !now.Before(token.ExpiresAt) {
+		return nil, model.ErrLoginExpired
+	}
+	user, err := s.userRepo.GetUserByID(ctx, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// The account may have been blocked since the password was checked
+	if err := s.accountGuard.EnsureActive(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	accountKey := normalizeEmail(user.Email)
+	retryAfter, err := s.loginWait(ctx, now, accountKey, source)
+	if err != nil {
+		return nil, err
+	}
+	if retryAfter > 0 {
+		if err := s.audit(ctx, user.Email, user.ID, source, model.LoginThrottled, now); err != nil {
+			return nil, err
+		}
+		return nil, &model.LoginThrottledError{RetryAfter: retryAfter}
+	}
+
+	if err := check(user); err != nil {
+		if errors.Is(err, model.ErrInvalidSecondFactor) {
+			if err := s.recordFailedLogin(ctx, now, user.Email, source, user, model.LoginInvalidCode); err != nil {
+				return nil, err
+			}
+		}
+		return nil, err
+	}
+
+	if err := s.tokenRepo.MarkTokenUsed(ctx, token.ID); err != nil {
+		if errors.Is(err, model.ErrConflict) {
+			return nil, model.ErrLoginExpired
+		}
+		return nil, err
+	}
+	if err := s.loginAttemptRepo.ResetThrottle(ctx, model.ThrottleAccount, accountKey); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetLockedAccounts lists the email addresses that are locked out at the moment
+func (s *AuthService) GetLockedAccounts(ctx context.Context) ([]model.LoginThrottle, error) {
+	return s.loginAttemptRepo.GetLockedThrottles(ctx, model.ThrottleAccount, time.Now())
+}
+
+// GetLoginAudit lists the recent failed logins and unlocks of an email address, newest first
+func (s *AuthService) GetLoginAudit(ctx context.Context, email string, limit int) ([]model.LoginAuditEntry, error) {
+	return s.loginAttemptRepo.GetAuditEntries(ctx, normalizeEmail(email), limit)
+}
+
+// UnlockAccount lifts the lockout of an email address and forgets its failed logins
+func (s *AuthService) UnlockAccount(ctx context.Context, adminID, email string) error {
+	if err := s.accountGuard.EnsureActive(ctx, adminID); err != nil {
+		return err
+	}
+	accountKey := normalizeEmail(email)
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.loginAttemptRepo.ResetThrottle(ctx, model.ThrottleAccount, accountKey); err != nil {
+			return err
+		}
+		if err := audit(WithActor(ctx, adminID), s.auditRepo, "unlock login", model.EntityUser, accountKey, nil, nil); err != nil {
+			return err
+		}
+		return s.audit(ctx, email, "", "admin:"+adminID, model.LoginUnlocked, time.Now())
+	})
+}
+
+// loginWait returns how long the account or the source still has to wait before the next attempt
+func (s *AuthService) loginWait(ctx context.Context, now time.Time, accountKey, source string) (time.Duration, error) {
+	var wait time.Duration
+	for _, throttle := range s.loginThrottles(accountKey, source) {
+		state, err := s.loginAttemptRepo.GetThrottle(ctx, throttle.scope, throttle.key)
+		if err != nil {
+			return 0, err
+		}
+		if state.LockedUntil != nil && now.Before(*state.LockedUntil) {
+			wait = max(wait, state.LockedUntil.Sub(now))
+		}
+		if state.Failures > 0 && now.Sub(state.LastFailureAt) < failedLoginWindow {
+			wait = max(wait, state.LastFailureAt.Add(loginDelay(state.Failures)).Sub(now))
+		}
+	}
+	return wait, nil
+}
+
+// loginThrottle is a counter of failed logins together with the number of failures that locks it
+type loginThrottle struct {
+	scope, key string
+	max        int
+}
+
+// loginThrottles lists the counters a login attempt is checked against: always the account, and the source
+// unless it is a terminal on this machine, which everyone using the machine shares
+func (s *AuthService) loginThrottles(accountKey, source string) []loginThrottle {
+	throttles := []loginThrottle{{model.ThrottleAccount, accountKey, s.options.MaxFailedLogins}}
+	if !model.IsLocalSource(source) {
+		throttles = append(throttles, loginThrottle{model.ThrottleSource, source, s.options.MaxFailedLoginsPerSource})
+	}
+	return throttles
+}
+
+// recordFailedLogin audits a failed login and counts it for the account and a remote source, locking either
+// once it reaches its limit. The owner of a locked account is told by email.
+func (s *AuthService) recordFailedLogin(ctx context.Context, now time.Time, email, source string, user *model.User, outcome string) error {
+	var userID string
+	if user != nil {
+		userID = user.ID
+	}
+	accountLocked := false
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.audit(ctx, email, userID, source, outcome, now); err != nil {
+			return err
+		}
+		for _, limit := range s.loginThrottles(normalizeEmail(email), source) {
+			failures, err := s.loginAttemptRepo.IncrementFailures(ctx, limit.scope, limit.key, now, now.Add(-failedLoginWindow))
+			if err != nil {
+				return err
+			}
+			if limit.max <= 0 || failures < limit.max {
+				continue
+			}
+			if err := s.loginAttemptRepo.LockThrottle(ctx, limit.scope, limit.key, now.Add(s.options.LockoutDuration)); err != nil {
+				return err
+			}
+			accountLocked = accountLocked || limit.scope == model.ThrottleAccount
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if accountLocked && user != nil {
+		body := fmt.Sprintf("Hello %s,\n\nAfter several failed login attempts your account has been locked until %s. "+
+			"If this was not you, reset your password once the lock ends or ask an admin to unlock the account.",
+			user.Name, now.Add(s.options.LockoutDuration).Format("2006-01-02 15:04"))
+		if err := s.mailer.Send(ctx, user.Email, "Your ServiceNest account has been locked", body); err != nil {
+			slog.Warn("could not send lockout email", "user_id", user.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *AuthService) audit(ctx context.Context, email, userID, source, outcome string, at time.Time) error {
+	return s.loginAttemptRepo.SaveAuditEntry(ctx, model.LoginAuditEntry{
+		ID:        GetUniqueID(),
+		Email:     normalizeEmail(email),
+		UserID:    userID,
+		Source:    source,
+		Outcome:   outcome,
+		CreatedAt: at,
+	})
+}
+
+// dummyPasswordHash is compared against when the email address is unknown, so that a failed login takes
+// as long whether or not the account exists
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("serviceNest"), bcrypt.DefaultCost)
+	return hash
+})
+
+func passwordMatches(user *model.User, password string) bool {
+	if user == nil {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// SendEmailVerification emails a verification code to the address. Nothing is sent for an unknown or
+// already verified address, and the caller is not told so that the flow cannot be used to probe accounts.
+func (s *AuthService) SendEmailVerification(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil
+		}
+		return err
+	}
+	if user.EmailVerified {
+		return nil
+	}
+	return s.issueToken(ctx, user, model.TokenEmailVerification, s.options.VerificationTokenTTL,
+		"Verify your ServiceNest email address",
+		"Use this code to verify your email address")
+}
+
+// VerifyEmail redeems a verification code and marks the address of its owner as verified
+func (s *AuthService) VerifyEmail(ctx context.Context, code string) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		token, err := s.redeemToken(ctx, code, model.TokenEmailVerification)
+		if err != nil {
+			return err
+		}
+		if err := s.userRepo.MarkEmailVerified(ctx, token.UserID); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, token.UserID), s.auditRepo, "verify email", model.EntityUser, token.UserID, nil, nil)
+	})
+}
+
+// RequestPasswordReset emails a password reset code to the address. As with verification, an unknown
+// address is not reported.
+func (s *AuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		if err.Error() == "user not found" {
+			return nil
+		}
+		return err
+	}
+	return s.issueToken(ctx, user, model.TokenPasswordReset, s.options.ResetTokenTTL,
+		"Reset your ServiceNest password",
+		"Use this code to choose a new password. If you did not ask for it, you can ignore this email")
+}
+
+// ResetPassword redeems a reset code and sets a new password. Receiving the code proves the user owns the
+// address, so it is marked as verified as well.
+func (s *AuthService) ResetPassword(ctx context.Context, code, newPassword string) error {
+	if err := util.ValidatePassword(newPassword); err != nil {
+		return err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		token, err := s.redeemToken(ctx, code, model.TokenPasswordReset)
+		if err != nil {
+			return err
+		}
+		if err := s.userRepo.UpdatePassword(ctx, token.UserID, string(hashedPassword)); err != nil {
+			return err
+		}
+		if err := s.userRepo.MarkEmailVerified(ctx, token.UserID); err != nil {
+			return err
+		}
+		// Other reset codes that are still in someone's inbox stop working
+		if err := s.tokenRepo.InvalidateTokens(ctx, token.UserID, model.TokenPasswordReset); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, token.UserID), s.auditRepo, "reset password", model.EntityUser, token.UserID, nil, nil)
+	})
+}
+
+// issueToken replaces the user's outstanding codes for purpose with a new one and emails it
+func (s *AuthService) issueToken(ctx context.Context, user *model.User, purpose string, ttl time.Duration, subject, intro string) error {
+	code, expiresAt, err := s.saveToken(ctx, user.ID, purpose, ttl)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hello %s,\n\n%s:\n\n    %s\n\nThe code can be used once and expires at %s.",
+		user.Name, intro, code, expiresAt.Format("2006-01-02 15:04"))
+	if err := s.mailer.Send(ctx, user.Email, subject, body); err != nil {
+		return fmt.Errorf("could not send the email, please try again later: %v", err)
+	}
+	return nil
+}
+
+// saveToken replaces the user's outstanding codes for purpose with a new one and returns it
+func (s *AuthService) saveToken(ctx context.Context, userID, purpose string, ttl time.Duration) (string, time.Time, error) {
+	code, err := newTokenCode()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	now := time.Now()
+	token := model.AuthToken{
+		ID:        GetUniqueID(),
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashTokenCode(code),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.tokenRepo.InvalidateTokens(ctx, userID, purpose); err != nil {
+			return err
+		}
+		return s.tokenRepo.SaveToken(ctx, token)
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return code, token.ExpiresAt, nil
+}
+
+// redeemToken marks the token behind code as used. Every reason a code cannot be redeemed is reported as
+// model.ErrInvalidToken.
+func (s *AuthService) redeemToken(ctx context.Context, code, purpose string) (*model.AuthToken, error) {
+	token, err := s.tokenRepo.GetTokenByHash(ctx, hashTokenCode(strings.TrimSpace(code)))
+	if err != nil {
+		if err.Error() == "token not found" {
+			return nil, model.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if token.Purpose != purpose || token.UsedAt != nil || !time.Now().Before(token.ExpiresAt) {
+		return nil, model.ErrInvalidToken
+	}
+	if err := s.tokenRepo.MarkTokenUsed(ctx, token.ID); err != nil {
+		if errors.Is(err, model.ErrConflict) {
+			return nil, model.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// newTokenCode returns 128 random bits as a hex string
+func newTokenCode() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func hashTokenCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"strings"
+)
+
+type CategoryService struct {
+	categoryRepo interfaces.CategoryRepository
+	serviceRepo  interfaces.ServiceRepository
+	ratingRepo   interfaces.RatingRepository
+	auditRepo    interfaces.AuditRepository
+	txManager    interfaces.TransactionManager
+}
+
+// NewCategoryService initializes a new CategoryService
+func NewCategoryService(categoryRepo interfaces.CategoryRepository, serviceRepo interfaces.ServiceRepository, ratingRepo interfaces.RatingRepository, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *CategoryService {
+	return &CategoryService{
+		categoryRepo: categoryRepo,
+		serviceRepo:  serviceRepo,
+		ratingRepo:   ratingRepo,
+		auditRepo:    auditRepo,
+		txManager:    txManager,
+	}
+}
+
+// GetAllCategories returns the whole catalogue, sub-categories included
+func (s *CategoryService) GetAllCategories(ctx context.Context) ([]model.Category, error) {
+	return s.categoryRepo.GetAllCategories(ctx)
+}
+
+// FindCategory looks up a category of the catalogue by name
+func (s *CategoryService) FindCategory(ctx context.Context, name string) (*model.Category, error) {
+	return findCategory(ctx, s.categoryRepo, name)
+}
+
+// AddCategory creates a category. When parentName is set the category becomes a sub-category of it.
+func (s *CategoryService) AddCategory(ctx context.Context, name, description, parentName string) (*model.Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("category name is required")
+	}
+	if err := s.ensureNameAvailable(ctx, name, ""); err != nil {
+		return nil, err
+	}
+
+	category := model.Category{
+		ID:          util.GenerateUniqueID(),
+		Name:        name,
+		Description: strings.TrimSpace(description),
+	}
+	if parentName != "" {
+		parent, err := findCategory(ctx, s.categoryRepo, parentName)
+		if err != nil {
+			return nil, err
+		}
+		category.ParentID = parent.ID
+	}
+
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.SaveCategory(ctx, category); err != nil {
+			return err
+		}
+		return audit(ctx, s.auditRepo, "create", model.EntityCategory, category.ID, nil, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// UpdateCategory renames, describes or moves a category. Empty name and description keep the current
+// values; parentName "-" turns the category into a top-level one. Services, reviews and category ratings
+// refer to their category by name, so a rename is carried over to them in the same transaction.
+func (s *CategoryService) UpdateCategory(ctx context.Context, categoryID, name, description, parentName string) error {
+	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+	before := *category
+
+	if name = strings.TrimSpace(name); name != "" && name != category.Name {
+		if err := s.ensureNameAvailable(ctx, name, category.ID); err != nil {
+			return err
+		}
+		category.Name = name
+	}
+	if description = strings.TrimSpace(description); description != "" {
+		category.Description = description
+	}
+
+	switch parentName {
+	case "":
+	case "-":
+		category.ParentID = ""
+	default:
+		parent, err := findCategory(ctx, s.categoryRepo, parentName)
+		if err != nil {
+			return err
+		}
+		if err := s.ensureNotDescendant(ctx, parent.ID, category.ID); err != nil {
+			return err
+		}
+		category.ParentID = parent.ID
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.UpdateCategory(ctx, *category); err != nil {
+			return err
+		}
+		if category.Name != before.Name {
+			if err := s.serviceRepo.RenameCategory(ctx, before.Name, category.Name); err != nil {
+				return err
+			}
+			if err := s.ratingRepo.RenameCategory(ctx, before.Name, category.Name); err != nil {
+				return err
+			}
+		}
+		return audit(ctx, s.auditRepo, "update", model.EntityCategory, category.ID, before, category)
+	})
+}
+
+// RemoveCategory deletes a category that has no sub-categories and is not used by any service
+func (s *CategoryService) RemoveCategory(ctx context.Context, categoryID string) error {
+	category, err := s.categoryRepo.GetCategoryByID(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+
+	categories, err := s.categoryRepo.GetAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+	for _, other := range categories {
+		if other.ParentID == category.ID {
+			return fmt.Errorf("category %q still has sub-categories", category.Name)
+		}
+	}
+
+	services, err := s.serviceRepo.GetAllServices(ctx, model.QueryOptions{Category: category.Name, Limit: 1})
+	if err != nil {
+		return err
+	}
+	if len(services) > 0 {
+		return fmt.Errorf("category %q is still used by services", category.Name)
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.categoryRepo.RemoveCategory(ctx, category.ID); err != nil {
+			return err
+		}
+		return audit(ctx, s.auditRepo, "delete", model.EntityCategory, category.ID, category, nil)
+	})
+}
+
+// ImportCategories loads the categories of a legacy service_category.json file as top-level categories.
+// Categories that already exist are skipped, so running the import again is harmless.
+func (s *CategoryService) ImportCategories(ctx context.Context, data []byte) (int, error) {
+	var categories []model.Category
+	if err := json.Unmarshal(data, &categories); err != nil {
+		return 0, fmt.Errorf("could not parse category file: %v", err)
+	}
+
+	imported := 0
+	for _, category := range categories {
+		_, err := s.categoryRepo.GetCategoryByName(ctx, category.Name)
+		if err == nil {
+			continue
+		}
+		if err.Error() != "category not found" {
+			return imported, err
+		}
+		if _, err := s.AddCategory(ctx, category.Name, category.Description, ""); err != nil {
+			return imported, fmt.Errorf("could not import category %q: %v", category.Name, err)
+		}
+		imported++
+	}
+	return imported, nil
+}
+
+func (s *CategoryService) ensureNameAvailable(ctx context.Context, name, categoryID string) error {
+	existing, err := s.categoryRepo.GetCategoryByName(ctx, name)
+	if err != nil {
+		if err.Error() == "category not found" {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != categoryID {
+		return fmt.Errorf("category %q already exists", name)
+	}
+	return nil
+}
+
+// ensureNotDescendant rejects moving a category below itself or one of its own sub-categories
+func (s *CategoryService) ensureNotDescendant(ctx context.Context, parentID, categoryID string) error {
+	categories, err := s.categoryRepo.GetAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+	parents := make(map[string]string, len(categories))
+	for _, category := range categories {
+		parents[category.ID] = category.ParentID
+	}
+
+	// The step limit guards against a cycle already present in the stored data
+	for id, steps := parentID, 0; id != "" && steps <= len(categories); id, steps = parents[id], steps+1 {
+		if id == categoryID {
+			return errors.New("a category cannot be moved below itself")
+		}
+	}
+	return nil
+}
+
+// findCategory looks up a category of the catalogue by name
+func findCategory(ctx context.Context, categoryRepo interfaces.CategoryRepository, name string) (*model.Category, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("category is required")
+	}
+	category, err := categoryRepo.GetCategoryByName(ctx, name)
+	if err != nil {
+		if err.Error() == "category not found" {
+			return nil, fmt.Errorf("unknown category %q", name)
+		}
+		return nil, err
+	}
+	return category, nil
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"strings"
+	"time"
+)
+
+// CustomRequestService lets admins triage requests for services that are not offered yet
+type CustomRequestService struct {
+	customRequestRepo interfaces.CustomRequestRepository
+	notificationRepo  interfaces.NotificationRepository
+	categoryService   *CategoryService
+	accountGuard      interfaces.AccountGuard
+	auditRepo         interfaces.AuditRepository
+	txManager         interfaces.TransactionManager
+}
+
+// NewCustomRequestService initializes a new CustomRequestService
+func NewCustomRequestService(customRequestRepo interfaces.CustomRequestRepository, notificationRepo interfaces.NotificationRepository, categoryService *CategoryService, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *CustomRequestService {
+	return &CustomRequestService{
+		customRequestRepo: customRequestRepo,
+		notificationRepo:  notificationRepo,
+		categoryService:   categoryService,
+		accountGuard:      accountGuard,
+		auditRepo:         auditRepo,
+		txManager:         txManager,
+	}
+}
+
+// GetOpenCustomRequests returns the triage queue, oldest request first
+func (s *CustomRequestService) GetOpenCustomRequests(ctx context.Context) ([]model.CustomRequest, error) {
+	return s.customRequestRepo.GetCustomRequestsByStatus(ctx, model.CustomRequestOpen)
+}
+
+// MapToCategory resolves a custom request with an existing category of the catalogue
+func (s *CustomRequestService) MapToCategory(ctx context.Context, adminID, requestID, categoryName string) error {
+	request, err := s.openRequest(ctx, adminID, requestID)
+	if err != nil {
+		return err
+	}
+	category, err := s.categoryService.FindCategory(ctx, categoryName)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		return s.resolveAsMapped(ctx, adminID, request, category)
+	})
+}
+
+// MapToNewCategory adds a category to the catalogue for a custom request and resolves the request with it.
+// The category is only kept if the request could be resolved.
+func (s *CustomRequestService) MapToNewCategory(ctx context.Context, adminID, requestID, name, description, parentName string) (*model.Category, error) {
+	request, err := s.openRequest(ctx, adminID, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	var category *model.Category
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if category, err = s.categoryService.AddCategory(ctx, name, description, parentName); err != nil {
+			return err
+		}
+		return s.resolveAsMapped(ctx, adminID, request, category)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+// RejectCustomRequest closes a custom request, the reason is passed on to the householder
+func (s *CustomRequestService) RejectCustomRequest(ctx context.Context, adminID, requestID, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required to reject a request")
+	}
+	request, err := s.openRequest(ctx, adminID, requestID)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.resolve(ctx, adminID, request, model.CustomRequestRejected, "", reason); err != nil {
+			return err
+		}
+		return notify(ctx, s.notificationRepo, request.HouseholderID,
+			fmt.Sprintf("Your request for %q could not be accepted: %s", request.ServiceName, reason))
+	})
+}
+
+// openRequest loads a request that is still waiting for triage by the admin
+func (s *CustomRequestService) openRequest(ctx context.Context, adminID, requestID string) (*model.CustomRequest, error) {
+	if err := s.accountGuard.EnsureActive(ctx, adminID); err != nil {
+		return nil, err
+	}
+	request, err := s.customRequestRepo.GetCustomRequestByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != model.CustomRequestOpen {
+		return nil, fmt.Errorf("custom request has already been %s", strings.ToLower(request.Status))
+	}
+	return request, nil
+}
+
+func (s *CustomRequestService) resolveAsMapped(ctx context.Context, adminID string, request *model.CustomRequest, category *model.Category) error {
+	note := fmt.Sprintf("mapped to category %s", category.Name)
+	if err := s.resolve(ctx, adminID, request, model.CustomRequestMapped, category.ID, note); err != nil {
+		return err
+	}
+	return notify(ctx, s.notificationRepo, request.HouseholderID,
+		fmt.Sprintf("Your request for %q is now covered by the %q category, you can book a service from it.", request.ServiceName, category.Name))
+}
+
+func (s *CustomRequestService) resolve(ctx context.Context, adminID string, request *model.CustomRequest, status, categoryID, note string) error {
+	before := *request
+	resolvedAt := time.Now()
+	request.Status = status
+	request.CategoryID = categoryID
+	request.ResolutionNote = note
+	request.ResolvedBy = adminID
+	request.ResolvedAt = &resolvedAt
+	if err := s.customRequestRepo.ResolveCustomRequest(ctx, *request); err != nil {
+		return err
+	}
+	return audit(WithActor(ctx, adminID), s.auditRepo, "resolve", model.EntityCustomRequest, request.ID, before, request)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"time"
+)
+
+// FavouriteService keeps the providers a householder saved and lets them book one of them again directly,
+// without broadcasting the request to every provider
+type FavouriteService struct {
+	favouriteRepo      interfaces.FavouriteRepository
+	providerRepo       interfaces.ServiceProviderRepository
+	serviceRepo        interfaces.ServiceRepository
+	serviceRequestRepo interfaces.ServiceRequestRepository
+	notificationRepo   interfaces.NotificationRepository
+	accountGuard       interfaces.AccountGuard
+	auditRepo          interfaces.AuditRepository
+	txManager          interfaces.TransactionManager
+}
+
+// NewFavouriteService initializes a new FavouriteService
+func NewFavouriteService(favouriteRepo interfaces.FavouriteRepository, providerRepo interfaces.ServiceProviderRepository, serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository, notificationRepo interfaces.NotificationRepository, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *FavouriteService {
+	return &FavouriteService{
+		favouriteRepo:      favouriteRepo,
+		providerRepo:       providerRepo,
+		serviceRepo:        serviceRepo,
+		serviceRequestRepo: serviceRequestRepo,
+		notificationRepo:   notificationRepo,
+		accountGuard:       accountGuard,
+		auditRepo:          auditRepo,
+		txManager:          txManager,
+	}
+}
+
+// AddFavourite saves a listed provider to the householder's favourites
+func (s *FavouriteService) AddFavourite(ctx context.Context, householderID, providerID string) error {
+	if err := s.accountGuard.EnsureActive(ctx, householderID); err != nil {
+		return err
+	}
+	if _, err := s.listedProvider(ctx, providerID); err != nil {
+		return err
+	}
+
+	favourite := model.FavouriteProvider{HouseholderID: householderID, ProviderID: providerID, AddedAt: time.Now().Truncate(time.Second)}
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.favouriteRepo.AddFavourite(ctx, favourite); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, householderID), s.auditRepo, "add favourite", model.EntityUser, householderID, nil, favourite)
+	})
+}
+
+// RemoveFavourite takes a provider off the householder's favourites
+func (s *FavouriteService) RemoveFavourite(ctx context.Context, householderID, providerID string) error {
+	if err := s.accountGuard.EnsureActive(ctx, householderID); err != nil {
+		return err
+	}
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.favouriteRepo.RemoveFavourite(ctx, householderID, providerID); err != nil {
+			return err
+		}
+		favourite := model.FavouriteProvider{HouseholderID: householderID, ProviderID: providerID}
+		return audit(WithActor(ctx, householderID), s.auditRepo, "remove favourite", model.EntityUser, householderID, favourite, nil)
+	})
+}
+
+// GetFavourites lists the householder's favourite providers
+func (s *FavouriteService) GetFavourites(ctx context.Context, householderID string) ([]model.FavouriteProvider, error) {
+	return s.favouriteRepo.GetFavourites(ctx, householderID)
+}
+
+// BookAgain creates a request for one of the provider's services that only this provider sees. The provider
+// is notified and quotes on it as on any other request; if they decline, the request is opened to every
+// provider.
+func (s *FavouriteService) BookAgain(ctx context.Context, householder *model.Householder, providerID, serviceID string, scheduleTime time.Time) (string, error) {
+	if err := s.accountGuard.EnsureActive(ctx, householder.User.ID); err != nil {
+		return "", err
+	}
+	provider, err := s.listedProvider(ctx, providerID)
+	if err != nil {
+		return "", err
+	}
+	if !provider.Availability {
+		return "", model.ErrProviderUnavailable
+	}
+	offered, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
+	if err != nil {
+		return "", err
+	}
+	if offered.ProviderID != providerID {
+		return "", model.ErrNotOfferedByProvider
+	}
+
+	request := model.ServiceRequest{
+		ID:                 GetUniqueID(),
+		HouseholderName:    householder.Name,
+		HouseholderID:      &householder.User.ID,
+		HouseholderAddress: &householder.Address,
+		ServiceID:          serviceID,
+		RequestedTime:      time.Now(),
+		ScheduledTime:      scheduleTime,
+		Status:             "Pending",
+		TargetProviderID:   &providerID,
+	}
+	message := fmt.Sprintf("%s booked you directly for %s on %s (request %s). Accept it with a price or decline it.",
+		householder.Name, offered.Name, scheduleTime.Format("2006-01-02 15:04"), request.ID)
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.serviceRequestRepo.SaveServiceRequest(ctx, request); err != nil {
+			return err
+		}
+		if err := notify(ctx, s.notificationRepo, providerID, message); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, householder.User.ID), s.auditRepo, "book again", model.EntityServiceRequest, request.ID, nil, request)
+	})
+	if err != nil {
+		return "", err
+	}
+	return request.ID, nil
+}
+
+// listedProvider loads a provider householders can find and book
+func (s *FavouriteService) listedProvider(ctx context.Context, providerID string) (*model.ServiceProvider, error) {
+	provider, err := s.providerRepo.GetProviderByID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	if !provider.IsListed(time.Now()) {
+		return nil, errors.New("provider not found")
+	}
+	return provider, nil
+}
@@ -1,12 +1,14 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/fatih/color"
 	"serviceNest/interfaces"
 	"serviceNest/model"
 	"serviceNest/util"
+	"strings"
 	"time"
 )
 
@@ -17,59 +19,89 @@ type HouseholderService struct {
 	providerRepo       interfaces.ServiceProviderRepository
 	serviceRepo        interfaces.ServiceRepository
 	serviceRequestRepo interfaces.ServiceRequestRepository
+	customRequestRepo  interfaces.CustomRequestRepository
+	ratingService      *RatingService
+	accountGuard       interfaces.AccountGuard
+	auditRepo          interfaces.AuditRepository
+	txManager          interfaces.TransactionManager
 }
 
-func NewHouseholderService(householderRepo interfaces.HouseholderRepository, providerRepo interfaces.ServiceProviderRepository, serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository) *HouseholderService {
+func NewHouseholderService(householderRepo interfaces.HouseholderRepository, providerRepo interfaces.ServiceProviderRepository, serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository, customRequestRepo interfaces.CustomRequestRepository, ratingService *RatingService, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *HouseholderService {
 	return &HouseholderService{
 		householderRepo:    householderRepo,
 		providerRepo:       providerRepo,
 		serviceRepo:        serviceRepo,
 		serviceRequestRepo: serviceRequestRepo,
+		customRequestRepo:  customRequestRepo,
+		ratingService:      ratingService,
+		accountGuard:       accountGuard,
+		auditRepo:          auditRepo,
+		txManager:          txManager,
 	}
 }
-func (s *HouseholderService) ViewStatus(serviceRequestRepo *HouseholderService, householder *model.Householder) ([]model.ServiceRequest, error) {
+func (s *HouseholderService) ViewStatus(ctx context.Context, serviceRequestRepo *HouseholderService, householder *model.Householder) ([]model.ServiceRequest, error) {
 	// Fetch all service requests for the householder
-	requests, err := s.serviceRequestRepo.GetServiceRequestsByHouseholderID(householder.ID)
+	requests, err := s.serviceRequestRepo.GetServiceRequestsByHouseholderID(ctx, householder.ID, model.QueryOptions{})
 	if err != nil {
 		color.Red("Error fetching service requests: %v", err)
 		return nil, err
 	}
+	maskForHouseholder(requests)
 	return requests, nil
 }
 
 // CancelAcceptedRequest allows a householder to cancel a request that has been accepted by a service_test provider
-func (s *HouseholderService) CancelAcceptedRequest(requestID, householderID string) error {
-	// Fetch the service_test request by ID
-	serviceRequest, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
-	if err != nil {
+func (s *HouseholderService) CancelAcceptedRequest(ctx context.Context, requestID, householderID string) error {
+	if err := s.accountGuard.EnsureActive(ctx, householderID); err != nil {
 		return err
 	}
+	return retryOnConflict(ctx, func(ctx context.Context) error {
+		// Fetch the service_test request by ID
+		serviceRequest, err := s.serviceRequestRepo.GetServiceRequestByID(ctx, requestID)
+		if err != nil {
+			return err
+		}
 
-	// Ensure the service_test request belongs to the householder
-	if serviceRequest.HouseholderID == nil || *serviceRequest.HouseholderID != householderID {
-		return errors.New("service request does not belong to the householder")
-	}
+		// Ensure the service_test request belongs to the householder
+		if serviceRequest.HouseholderID == nil || *serviceRequest.HouseholderID != householderID {
+			return errors.New("service request does not belong to the householder")
+		}
 
-	// Check if the service_test request is in "Accepted" status
-	if serviceRequest.Status != "Accepted" {
-		return errors.New("only accepted service requests can be canceled")
-	}
+		// Check if the service_test request is in "Accepted" status
+		if serviceRequest.Status != "Accepted" {
+			return errors.New("only accepted service requests can be canceled")
+		}
 
-	// Update the status to "Cancelled"
-	serviceRequest.Status = "Cancelled"
+		// Update the status to "Cancelled"
+		before := *serviceRequest
+		serviceRequest.Status = "Cancelled"
 
-	// Save the updated service_test request
-	err = s.serviceRequestRepo.UpdateServiceRequest(serviceRequest)
-	if err != nil {
-		return err
+		// Save the updated service_test request
+		return s.updateRequest(WithActor(ctx, householderID), "cancel", before, serviceRequest)
+	})
+}
+
+// ensureOwnerActive refuses changes to a request whose householder may no longer use their account
+func (s *HouseholderService) ensureOwnerActive(ctx context.Context, request *model.ServiceRequest) error {
+	if request.HouseholderID == nil {
+		return nil
 	}
+	return s.accountGuard.EnsureActive(ctx, *request.HouseholderID)
+}
 
-	return nil
+// updateRequest saves a changed service request together with its audit entry
+func (s *HouseholderService) updateRequest(ctx context.Context, action string, before model.ServiceRequest, request *model.ServiceRequest) error {
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.serviceRequestRepo.UpdateServiceRequest(ctx, request); err != nil {
+			return err
+		}
+		return audit(ctx, s.auditRepo, action, model.EntityServiceRequest, request.ID, before, request)
+	})
 }
 
 // SearchService searches for available service_test providers based on service_test type and proximity
-func (s *HouseholderService) SearchService(householder *model.Householder, serviceType string) ([]model.ServiceProvider, error) {
-	providers, err := s.providerRepo.GetProvidersByServiceType(serviceType)
+func (s *HouseholderService) SearchService(ctx context.Context, householder *model.Householder, serviceType string) ([]model.ServiceProvider, error) {
+	providers, err := s.providerRepo.GetProvidersByServiceType(ctx, serviceType)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +110,8 @@ func (s *HouseholderService) SearchService(householder *model.Householder, servi
 	nearbyProviders := []model.ServiceProvider{}
 	for _, provider := range providers {
 		if s.isNearby(householder, &provider) {
+			// Contact details are only shared with a householder who booked the provider
+			provider.Contact, provider.Address = model.MaskedContact, model.MaskedContact
 			nearbyProviders = append(nearbyProviders, provider)
 		}
 	}
@@ -86,41 +120,38 @@ func (s *HouseholderService) SearchService(householder *model.Householder, servi
 
 }
 
-func (s *HouseholderService) GetServicesByCategory(category string) ([]model.Service, error) {
-	// Fetch all services from the service_test repository_test
-	services, err := s.serviceRepo.GetAllServices()
+func (s *HouseholderService) GetServicesByCategory(ctx context.Context, category string) ([]model.Service, error) {
+	// Fetch the services of the category, the filter is applied by the repository
+	services, err := s.serviceRepo.GetAllServices(ctx, model.QueryOptions{Category: category})
 	if err != nil {
 		return nil, err
 	}
 
-	// Initialize a slice to hold the filtered services
+	// Initialize a slice to hold the services with their provider details
 	var filteredServices []model.Service
 
-	// Iterate over each service_test and filter by category
 	for _, service := range services {
-		if service.Category == category {
-			//// Fetch the service_test provider details using the ProviderID from the service_test
-			//provider, err := s.getProviderDetails(service_test.ProviderID)
-			//if err != nil {
-			//	return nil, err
-			//}
-			//
-			//// Attach the provider details to the service_test object
-			//service_test.ProviderName = provider.Name
-			//service_test.ProviderContact = provider.Contact
-			//service_test.ProviderAddress = provider.Address
-			//service_test.ProviderRating = provider.Rating
-
-			// Add the service_test with the provider details to the filtered services slice
-			provider, err := s.providerRepo.GetProviderDetailByID(service.ProviderID)
-			if err != nil {
-				return nil, err
-			}
-			service.ProviderName = provider.Name
-			service.ProviderContact = provider.Contact
-			service.ProviderAddress = provider.Address
-			filteredServices = append(filteredServices, service)
+		//// Fetch the service_test provider details using the ProviderID from the service_test
+		//provider, err := s.getProviderDetails(service_test.ProviderID)
+		//if err != nil {
+		//	return nil, err
+		//}
+		//
+		//// Attach the provider details to the service_test object
+		//service_test.ProviderName = provider.Name
+		//service_test.ProviderContact = provider.Contact
+		//service_test.ProviderAddress = provider.Address
+		//service_test.ProviderRating = provider.Rating
+
+		// Add the service_test with the provider details to the filtered services slice
+		provider, err := s.providerRepo.GetProviderDetailByID(ctx, service.ProviderID)
+		if err != nil {
+			return nil, err
 		}
+		service.ProviderName = provider.Name
+		// Contact details are shared once a booking is approved, requests are made through ServiceNest
+		service.MaskContact()
+		filteredServices = append(filteredServices, service)
 	}
 
 	return filteredServices, nil
@@ -144,34 +175,20 @@ func (s *HouseholderService) GetServicesByCategory(category string) ([]model.Ser
 //	return &provider, nil
 //}
 
-// RequestService allows the householder to request a service_test from a provider
-func (s *HouseholderService) RequestService(householder *model.Householder, serviceName string, scheduleTime *time.Time) (string, error) {
-	// Check if the service already exists
-	service, err := s.serviceRepo.GetServiceByName(serviceName)
-	if err != nil && err.Error() != "service not found" {
+// RequestService allows the householder to request a service_test from a provider. Names that no provider
+// offers return model.ErrServiceNotOffered, such requests go through RequestCustomService instead.
+func (s *HouseholderService) RequestService(ctx context.Context, householder *model.Householder, serviceName string, scheduleTime *time.Time) (string, error) {
+	if err := s.accountGuard.EnsureActive(ctx, householder.ID); err != nil {
 		return "", err
 	}
 
-	var serviceID string
-	if service == nil {
-		// Service does not exist, create a custom service entry
-		customServiceID := util.GenerateUniqueID() // Function to generate a unique ID
-		customService := model.Service{
-			ID:          customServiceID,
-			Name:        serviceName,
-			Description: "Custom Service Request",
-			Price:       0.0,      // Placeholder price
-			ProviderID:  "",       // No provider assigned
-			Category:    "Custom", // Assign a category if needed
-		}
-		// Save the custom service to the repository
-		err := s.serviceRepo.SaveService(customService)
-		if err != nil {
-			return "", err
+	// Check if the service already exists
+	service, err := s.serviceRepo.GetServiceByName(ctx, serviceName)
+	if err != nil {
+		if err.Error() == "service not found" {
+			return "", model.ErrServiceNotOffered
 		}
-		serviceID = customServiceID
-	} else {
-		serviceID = service.ID
+		return "", err
 	}
 
 	// Generate a unique ID for the service request
@@ -183,7 +200,7 @@ func (s *HouseholderService) RequestService(householder *model.Householder, serv
 		HouseholderName:    householder.Name,
 		HouseholderID:      &householder.User.ID,
 		HouseholderAddress: &householder.Address,
-		ServiceID:          serviceID,
+		ServiceID:          service.ID,
 		RequestedTime:      time.Now(),
 		ScheduledTime:      *scheduleTime,
 		Status:             "Pending",
@@ -191,17 +208,60 @@ func (s *HouseholderService) RequestService(householder *model.Householder, serv
 	}
 
 	// Save the service request to the repository
-	err = s.serviceRequestRepo.SaveServiceRequest(serviceRequest)
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.serviceRequestRepo.SaveServiceRequest(ctx, serviceRequest); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, householder.User.ID), s.auditRepo, "create", model.EntityServiceRequest, requestID, nil, serviceRequest)
+	})
 	if err != nil {
 		return "", err
 	}
 
-	return serviceRequest.ID, nil
+	return requestID, nil
+}
+
+// RequestCustomService puts a request for a service that is not offered yet in the admin triage queue.
+// The householder is notified once an admin has mapped or rejected it.
+func (s *HouseholderService) RequestCustomService(ctx context.Context, householder *model.Householder, serviceName string, scheduleTime *time.Time) (string, error) {
+	serviceName = strings.TrimSpace(serviceName)
+	if serviceName == "" {
+		return "", errors.New("service name is required")
+	}
+	if err := s.accountGuard.EnsureActive(ctx, householder.ID); err != nil {
+		return "", err
+	}
+
+	request := model.CustomRequest{
+		ID:                 GetUniqueID(),
+		HouseholderID:      householder.ID,
+		HouseholderName:    householder.Name,
+		HouseholderAddress: householder.Address,
+		ServiceName:        serviceName,
+		RequestedTime:      time.Now(),
+		ScheduledTime:      *scheduleTime,
+		Status:             model.CustomRequestOpen,
+	}
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.customRequestRepo.SaveCustomRequest(ctx, request); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, householder.ID), s.auditRepo, "create", model.EntityCustomRequest, request.ID, nil, request)
+	})
+	if err != nil {
+		return "", err
+	}
+	return request.ID, nil
 }
 
-// ViewBookingHistory returns the booking history for a householder
-func (s *HouseholderService) ViewBookingHistory(householderID string) ([]model.ServiceRequest, error) {
-	return s.serviceRequestRepo.GetServiceRequestsByHouseholderID(householderID)
+// ViewBookingHistory returns one page of the booking history for a householder
+func (s *HouseholderService) ViewBookingHistory(ctx context.Context, householderID string, opts model.QueryOptions) ([]model.ServiceRequest, error) {
+	requests, err := s.serviceRequestRepo.GetServiceRequestsByHouseholderID(ctx, householderID, opts)
+	if err != nil {
+		return nil, err
+	}
+	maskForHouseholder(requests)
+	return requests, nil
 }
 
 // ReviewServiceProvider allows the householder to leave a review for a service_test provider
@@ -217,43 +277,57 @@ func (s *HouseholderService) isNearby(householder *model.Householder, provider *
 }
 
 // GetAvailableServices fetches all available services from the repository_test
-func (s *HouseholderService) GetAvailableServices() ([]model.Service, error) {
-	return s.serviceRepo.GetAllServices()
+func (s *HouseholderService) GetAvailableServices(ctx context.Context) ([]model.Service, error) {
+	return s.serviceRepo.GetAllServices(ctx, model.QueryOptions{})
 }
 
 // CancelServiceRequest allows the householder to cancel a service_test request
-func (s *HouseholderService) CancelServiceRequest(requestID string) error {
-	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
-	if err != nil {
-		return err
-	}
+func (s *HouseholderService) CancelServiceRequest(ctx context.Context, requestID string) error {
+	return retryOnConflict(ctx, func(ctx context.Context) error {
+		request, err := s.serviceRequestRepo.GetServiceRequestByID(ctx, requestID)
+		if err != nil {
+			return err
+		}
 
-	if request.Status == "Cancelled" {
-		return fmt.Errorf("service request is already cancelled")
-	}
+		if err := s.ensureOwnerActive(ctx, request); err != nil {
+			return err
+		}
 
-	request.Status = "Cancelled"
-	return s.serviceRequestRepo.UpdateServiceRequest(request)
+		if request.Status == "Cancelled" {
+			return fmt.Errorf("service request is already cancelled")
+		}
+
+		before := *request
+		request.Status = "Cancelled"
+		return s.updateRequest(ctx, "cancel", before, request)
+	})
 }
 
 // RescheduleServiceRequest allows the householder to reschedule a service_test request
-func (s *HouseholderService) RescheduleServiceRequest(requestID string, newTime time.Time) error {
-	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
-	if err != nil {
-		return err
-	}
+func (s *HouseholderService) RescheduleServiceRequest(ctx context.Context, requestID string, newTime time.Time) error {
+	return retryOnConflict(ctx, func(ctx context.Context) error {
+		request, err := s.serviceRequestRepo.GetServiceRequestByID(ctx, requestID)
+		if err != nil {
+			return err
+		}
 
-	if request.Status != "Pending" && request.Status != "Accepted" {
-		return fmt.Errorf("only pending or accepted requests can be rescheduled")
-	}
+		if err := s.ensureOwnerActive(ctx, request); err != nil {
+			return err
+		}
 
-	request.ScheduledTime = newTime
-	return s.serviceRequestRepo.UpdateServiceRequest(request)
+		if request.Status != "Pending" && request.Status != "Accepted" {
+			return fmt.Errorf("only pending or accepted requests can be rescheduled")
+		}
+
+		before := *request
+		request.ScheduledTime = newTime
+		return s.updateRequest(ctx, "reschedule", before, request)
+	})
 }
 
 // ViewServiceRequestStatus returns the status of a specific service_test request
-func (s *HouseholderService) ViewServiceRequestStatus(requestID string) (string, error) {
-	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+func (s *HouseholderService) ViewServiceRequestStatus(ctx context.Context, requestID string) (string, error) {
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(ctx, requestID)
 	if err != nil {
 		return "", err
 	}
@@ -303,31 +377,101 @@ func (s *HouseholderService) ViewServiceRequestStatus(requestID string) (string,
 //	return nil
 //}
 
-func (s *HouseholderService) AddReview(providerID, householderID, serviceID, comments string, rating float64) error {
-	// Create the review object
-	review := model.Review{
-		ID:            GetUniqueID(),
-		ProviderID:    providerID, // Include providerID
-		ServiceID:     serviceID,
-		HouseholderID: householderID,
-		Rating:        rating,
-		Comments:      comments,
-		ReviewDate:    time.Now(),
+// AddReview reviews the provider who completed a request the householder booked. Each request can be
+// reviewed once; the provider and the service are taken from the request. Sub-scores per criterion are
+// optional.
+func (s *HouseholderService) AddReview(ctx context.Context, householderID, requestID, comments string, rating float64, scores map[string]float64) error {
+	review := model.Review{Rating: rating, Scores: scores}
+	if err := review.ValidateScores(); err != nil {
+		return err
+	}
+	if err := s.accountGuard.EnsureActive(ctx, householderID); err != nil {
+		return err
 	}
 
-	// Save the review in the repository
-	err := s.providerRepo.AddReview(review)
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(ctx, requestID)
 	if err != nil {
 		return err
 	}
-
-	// Recalculate and update the provider's rating
-	err = s.providerRepo.UpdateProviderRating(providerID)
+	if request.HouseholderID == nil || *request.HouseholderID != householderID || request.Status != model.RequestCompleted {
+		return model.ErrReviewNotAllowed
+	}
+	provider, err := s.serviceRequestRepo.GetApprovedProvider(ctx, requestID)
 	if err != nil {
-		return errors.New("failed to update provider rating")
+		return model.ErrReviewNotAllowed
+	}
+
+	// Complete the review object
+	review.ID = GetUniqueID()
+	review.ProviderID = provider.ServiceProviderID
+	review.ServiceID = request.ServiceID
+	review.HouseholderID = householderID
+	review.RequestID = requestID
+	review.Comments = comments
+	review.ReviewDate = time.Now().Truncate(time.Second)
+	// The category rating is best effort: a service that has since been removed leaves it out
+	if service, err := s.serviceRepo.GetServiceByID(ctx, request.ServiceID); err == nil {
+		review.Category = service.Category
+	}
+
+	// The review and the provider's rating totals are written together
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Save the review in the repository
+		if err := s.providerRepo.AddReview(ctx, review); err != nil {
+			return err
+		}
+
+		if err := s.ratingService.ApplyReviewChange(ctx, nil, &review); err != nil {
+			return errors.New("failed to update provider rating")
+		}
+		return audit(WithActor(ctx, householderID), s.auditRepo, "create", model.EntityReview, review.ID, nil, review)
+	})
+}
+
+// EditReview changes the rating, sub-scores and comments of one of the householder's reviews while it is
+// still within the edit window
+func (s *HouseholderService) EditReview(ctx context.Context, householderID, reviewID, comments string, rating float64, scores map[string]float64) error {
+	if err := (&model.Review{Rating: rating, Scores: scores}).ValidateScores(); err != nil {
+		return err
+	}
+	if err := s.accountGuard.EnsureActive(ctx, householderID); err != nil {
+		return err
 	}
 
-	return nil
+	// The review is read under a lock so that the rating totals move from exactly what was counted before,
+	// even when a moderator changes the review at the same time
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		review, err := s.providerRepo.LockReviewByID(ctx, reviewID)
+		if err != nil {
+			return err
+		}
+		if review.HouseholderID != householderID {
+			return errors.New("review not found")
+		}
+		now := time.Now()
+		if !review.Editable(now) {
+			return model.ErrReviewEditClosed
+		}
+
+		before := *review
+		review.Rating = rating
+		review.Scores = scores
+		review.Comments = comments
+		review.UpdatedAt = &now
+
+		if err := s.providerRepo.UpdateReview(ctx, *review); err != nil {
+			return err
+		}
+		if err := s.ratingService.ApplyReviewChange(ctx, &before, review); err != nil {
+			return errors.New("failed to update provider rating")
+		}
+		return audit(WithActor(ctx, householderID), s.auditRepo, "update", model.EntityReview, review.ID, before, review)
+	})
+}
+
+// GetMyReviews lists the reviews the householder has written
+func (s *HouseholderService) GetMyReviews(ctx context.Context, householderID string) ([]model.Review, error) {
+	return s.providerRepo.GetReviewsByHouseholderID(ctx, householderID)
 }
 
 // ApproveServiceRequest allows the householder to approve a service request.
@@ -360,39 +504,64 @@ func (s *HouseholderService) AddReview(providerID, householderID, serviceID, com
 //
 //		return nil
 //	}
-func (s *HouseholderService) ApproveServiceRequest(requestID string, providerID string) error {
-	// Retrieve the service request by ID
-	serviceRequest, err := s.serviceRequestRepo.GetServiceProviderByRequestID(requestID, providerID)
+func (s *HouseholderService) ApproveServiceRequest(ctx context.Context, requestID string, providerID string) error {
+	// A provider whose account has been suspended keeps their earlier quotes but cannot be booked
+	provider, err := s.providerRepo.GetProviderByID(ctx, providerID)
 	if err != nil {
-		return fmt.Errorf("could not find service request: %v", err)
+		return err
 	}
-
-	// Check if the request has already been approved
-	if serviceRequest.ApproveStatus {
-		return errors.New("service request has already been approved")
+	if !provider.IsActive {
+		return errors.New("the provider is no longer available, please choose another quote")
 	}
 
-	// Set the approval status to true
-	serviceRequest.ApproveStatus = true
-	for _, provider := range serviceRequest.ProviderDetails {
-		if provider.ServiceProviderID == providerID {
-			provider.Approve = true
-			if err := s.providerRepo.UpdateServiceProviderDetailByRequestID(&provider, requestID); err != nil {
-				return fmt.Errorf("could not update service provider detail")
-			}
-			break
+	return retryOnConflict(ctx, func(ctx context.Context) error {
+		// Retrieve the service request by ID
+		serviceRequest, err := s.serviceRequestRepo.GetServiceProviderByRequestID(ctx, requestID, providerID)
+		if err != nil {
+			return fmt.Errorf("could not find service request: %v", err)
+		}
+		if err := s.ensureOwnerActive(ctx, serviceRequest); err != nil {
+			return err
+		}
+
+		// Check if the request has already been approved
+		if serviceRequest.ApproveStatus {
+			return errors.New("service request has already been approved")
+		}
+		if serviceRequest.Status == "Cancelled" {
+			return errors.New("service request has been cancelled")
 		}
-	}
-	// Update the service request in the repository
-	if err := s.serviceRequestRepo.UpdateServiceRequest(serviceRequest); err != nil {
-		return fmt.Errorf("could not update service request: %v", err)
-	}
 
-	return nil
+		// Set the approval status to true
+		before := *serviceRequest
+		serviceRequest.ApproveStatus = true
+
+		// The provider detail and the request are updated atomically so a failure cannot leave
+		// a provider approved on a request that is still open
+		return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			for _, provider := range serviceRequest.ProviderDetails {
+				if provider.ServiceProviderID == providerID {
+					provider.Approve = true
+					if err := s.providerRepo.UpdateServiceProviderDetailByRequestID(ctx, &provider, requestID); err != nil {
+						if errors.Is(err, model.ErrConflict) {
+							return err
+						}
+						return fmt.Errorf("could not update service provider detail")
+					}
+					break
+				}
+			}
+			// Update the service request in the repository
+			if err := s.serviceRequestRepo.UpdateServiceRequest(ctx, serviceRequest); err != nil {
+				return fmt.Errorf("could not update service request: %w", err)
+			}
+			return audit(ctx, s.auditRepo, "approve", model.EntityServiceRequest, requestID, before, serviceRequest)
+		})
+	})
 }
-func (s *HouseholderService) ViewApprovedRequests(householderID string) ([]model.ServiceRequest, error) {
+func (s *HouseholderService) ViewApprovedRequests(ctx context.Context, householderID string) ([]model.ServiceRequest, error) {
 	// Retrieve all service requests for the householder
-	serviceRequests, err := s.serviceRequestRepo.GetServiceRequestsByHouseholderID(householderID)
+	serviceRequests, err := s.serviceRequestRepo.GetServiceRequestsByHouseholderID(ctx, householderID, model.QueryOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("could not retrieve service requests: %v", err)
 	}
@@ -408,6 +577,14 @@ func (s *HouseholderService) ViewApprovedRequests(householderID string) ([]model
 	if len(approvedRequests) == 0 {
 		return nil, errors.New("no approved service requests found")
 	}
+	maskForHouseholder(approvedRequests)
 
 	return approvedRequests, nil
 }
+
+// maskForHouseholder hides the contact details of the providers a householder has not booked
+func maskForHouseholder(requests []model.ServiceRequest) {
+	for i := range requests {
+		requests[i].MaskForHouseholder()
+	}
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"strings"
+	"unicode/utf8"
+)
+
+// MessageRelayService passes messages between the householder of a request and the providers who quoted
+// on it, so that they can talk before the booking is approved without seeing each other's contact details
+type MessageRelayService struct {
+	userRepo           interfaces.UserRepository
+	serviceRequestRepo interfaces.ServiceRequestRepository
+	notificationRepo   interfaces.NotificationRepository
+	accountGuard       interfaces.AccountGuard
+	auditRepo          interfaces.AuditRepository
+	txManager          interfaces.TransactionManager
+}
+
+// NewMessageRelayService initializes a new MessageRelayService
+func NewMessageRelayService(userRepo interfaces.UserRepository, serviceRequestRepo interfaces.ServiceRequestRepository, notificationRepo interfaces.NotificationRepository, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *MessageRelayService {
+	return &MessageRelayService{
+		userRepo:           userRepo,
+		serviceRequestRepo: serviceRequestRepo,
+		notificationRepo:   notificationRepo,
+		accountGuard:       accountGuard,
+		auditRepo:          auditRepo,
+		txManager:          txManager,
+	}
+}
+
+// SendMessage delivers text to the inbox of recipientID under the contact reference of the request. One of
+// the two must be the householder of the request and the other a provider who quoted on it. The text is
+// not written to the audit log, only that a message was sent.
+func (s *MessageRelayService) SendMessage(ctx context.Context, senderID, requestID, recipientID, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return model.ErrEmptyMessage
+	}
+	if utf8.RuneCountInString(text) > model.MaxRelayMessageLength {
+		return model.ErrMessageTooLong
+	}
+	if err := s.accountGuard.EnsureActive(ctx, senderID); err != nil {
+		return err
+	}
+
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	if request.HouseholderID == nil {
+		return model.ErrNotRequestParty
+	}
+	providerID := ""
+	switch *request.HouseholderID {
+	case senderID:
+		providerID = recipientID
+	case recipientID:
+		providerID = senderID
+	default:
+		return model.ErrNotRequestParty
+	}
+	if providerID == *request.HouseholderID {
+		return model.ErrNotRequestParty
+	}
+	if _, err := s.serviceRequestRepo.GetServiceProviderByRequestID(ctx, requestID, providerID); err != nil {
+		return model.ErrNotRequestParty
+	}
+
+	sender, err := s.userRepo.GetUserByID(ctx, senderID)
+	if err != nil {
+		return err
+	}
+	message := fmt.Sprintf("Message from %s about request %s (%s): %s", sender.Name, model.ContactReference(requestID), request.ServiceName, text)
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := notify(ctx, s.notificationRepo, recipientID, message); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, senderID), s.auditRepo, "relay message", model.EntityServiceRequest, requestID, nil,
+			map[string]string{"recipient_id": recipientID})
+	})
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"time"
+)
+
+type NotificationService struct {
+	notificationRepo interfaces.NotificationRepository
+}
+
+// NewNotificationService initializes a new NotificationService
+func NewNotificationService(notificationRepo interfaces.NotificationRepository) *NotificationService {
+	return &NotificationService{notificationRepo: notificationRepo}
+}
+
+// GetNotifications returns the inbox of a user and marks it as read. The returned notifications still
+// tell which ones were unread before this call.
+func (s *NotificationService) GetNotifications(ctx context.Context, userID string) ([]model.Notification, error) {
+	notifications, err := s.notificationRepo.GetNotificationsByUserID(ctx, userID, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, notification := range notifications {
+		if !notification.Read {
+			return notifications, s.notificationRepo.MarkNotificationsRead(ctx, userID)
+		}
+	}
+	return notifications, nil
+}
+
+// CountUnread returns the number of notifications the user has not seen yet
+func (s *NotificationService) CountUnread(ctx context.Context, userID string) (int, error) {
+	notifications, err := s.notificationRepo.GetNotificationsByUserID(ctx, userID, true)
+	if err != nil {
+		return 0, err
+	}
+	return len(notifications), nil
+}
+
+// notify drops a message in the inbox of a user. Called inside a transaction the message is only
+// delivered if the change it announces is committed.
+func notify(ctx context.Context, notificationRepo interfaces.NotificationRepository, userID, message string) error {
+	return notificationRepo.SaveNotification(ctx, model.Notification{
+		ID:        GetUniqueID(),
+		UserID:    userID,
+		Message:   message,
+		CreatedAt: time.Now(),
+	})
+}
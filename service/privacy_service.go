@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"time"
+)
+
+// erasureReason is recorded in the status history of an account that was closed by erasing its data
+const erasureReason = "personal data erased"
+
+// exportLoginHistoryLimit bounds the login audit entries included in an export
+const exportLoginHistoryLimit = 1000
+
+// PrivacyService hands users a copy of the data held about them and erases it on request. Erasure replaces
+// personal details with placeholders instead of deleting rows, so that request history and ratings stay intact.
+type PrivacyService struct {
+	userRepo           interfaces.UserRepository
+	accountStatusRepo  interfaces.AccountStatusRepository
+	roleHistoryRepo    interfaces.RoleHistoryRepository
+	serviceRequestRepo interfaces.ServiceRequestRepository
+	customRequestRepo  interfaces.CustomRequestRepository
+	providerRepo       interfaces.ServiceProviderRepository
+	documentRepo       interfaces.ProviderDocumentRepository
+	photoRepo          interfaces.ReviewPhotoRepository
+	profileRepo        interfaces.ProviderProfileRepository
+	favouriteRepo      interfaces.FavouriteRepository
+	notificationRepo   interfaces.NotificationRepository
+	loginAttemptRepo   interfaces.LoginAttemptRepository
+	tokenRepo          interfaces.AuthTokenRepository
+	twoFactorRepo      interfaces.TwoFactorRepository
+	blobStore          interfaces.BlobStore
+	accountService     *AccountService
+	auditRepo          interfaces.AuditRepository
+	txManager          interfaces.TransactionManager
+}
+
+// NewPrivacyService initializes a new PrivacyService
+func NewPrivacyService(userRepo interfaces.UserRepository, accountStatusRepo interfaces.AccountStatusRepository, roleHistoryRepo interfaces.RoleHistoryRepository, serviceRequestRepo interfaces.ServiceRequestRepository, customRequestRepo interfaces.CustomRequestRepository, providerRepo interfaces.ServiceProviderRepository, documentRepo interfaces.ProviderDocumentRepository, photoRepo interfaces.ReviewPhotoRepository, profileRepo interfaces.ProviderProfileRepository, favouriteRepo interfaces.FavouriteRepository, notificationRepo interfaces.NotificationRepository, loginAttemptRepo interfaces.LoginAttemptRepository, tokenRepo interfaces.AuthTokenRepository, twoFactorRepo interfaces.TwoFactorRepository, blobStore interfaces.BlobStore, accountService *AccountService, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *PrivacyService {
+	return &PrivacyService{
+		userRepo:           userRepo,
+		accountStatusRepo:  accountStatusRepo,
+		roleHistoryRepo:    roleHistoryRepo,
+		serviceRequestRepo: serviceRequestRepo,
+		customRequestRepo:  customRequestRepo,
+		providerRepo:       providerRepo,
+		documentRepo:       documentRepo,
+		photoRepo:          photoRepo,
+		profileRepo:        profileRepo,
+		favouriteRepo:      favouriteRepo,
+		notificationRepo:   notificationRepo,
+		loginAttemptRepo:   loginAttemptRepo,
+		tokenRepo:          tokenRepo,
+		twoFactorRepo:      twoFactorRepo,
+		blobStore:          blobStore,
+		accountService:     accountService,
+		auditRepo:          auditRepo,
+		txManager:          txManager,
+	}
+}
+
+// ExportUserData collects everything held about a user. Users can export their own data, admins anyone's.
+func (s *PrivacyService) ExportUserData(ctx context.Context, requesterID, userID string) (*model.UserDataExport, error) {
+	if requesterID != userID {
+		if err := s.requireAdmin(ctx, requesterID); err != nil {
+			return nil, err
+		}
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &model.UserDataExport{ExportedAt: time.Now().UTC(), Profile: *user}
+	export.Profile.Password = ""
+	export.Profile.Status = accountStatus(user)
+	if export.StatusHistory, err = s.accountStatusRepo.GetStatusHistory(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.RoleHistory, err = s.roleHistoryRepo.GetRoleHistory(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Notifications, err = s.notificationRepo.GetNotificationsByUserID(ctx, userID, false); err != nil {
+		return nil, err
+	}
+	if export.LoginHistory, err = s.loginAttemptRepo.GetAuditEntries(ctx, normalizeEmail(user.Email), exportLoginHistoryLimit); err != nil {
+		return nil, err
+	}
+	if export.ServiceRequests, err = s.serviceRequestRepo.GetServiceRequestsByHouseholderID(ctx, userID, model.QueryOptions{}); err != nil {
+		return nil, err
+	}
+	if export.CustomRequests, err = s.customRequestRepo.GetCustomRequestsByHouseholderID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.ReviewsWritten, err = s.providerRepo.GetReviewsByHouseholderID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.ReviewPhotos, err = s.photoRepo.GetPhotosByHouseholderID(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.Favourites, err = s.favouriteRepo.GetFavourites(ctx, userID); err != nil {
+		return nil, err
+	}
+	if user.Role == model.RoleServiceProvider {
+		if err := s.exportProviderData(ctx, export); err != nil {
+			return nil, err
+		}
+	}
+
+	err = audit(WithActor(ctx, requesterID), s.auditRepo, "export personal data", model.EntityUser, userID, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// exportProviderData adds the provider profile, jobs, reviews and documents. The householders of the jobs
+// and the authors of the reviews are not part of the provider's data and are left out.
+func (s *PrivacyService) exportProviderData(ctx context.Context, export *model.UserDataExport) error {
+	providerID := export.Profile.ID
+	provider, err := s.providerRepo.GetProviderByID(ctx, providerID)
+	if err != nil && err.Error() != "provider not found" {
+		return err
+	}
+	if provider != nil {
+		provider.User = export.Profile
+		export.Provider = provider
+	}
+
+	jobs, err := s.serviceRequestRepo.GetServiceRequestsByProviderID(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	for i := range jobs {
+		jobs[i].HouseholderID, jobs[i].HouseholderName, jobs[i].HouseholderAddress = nil, "", nil
+	}
+	export.ProviderJobs = jobs
+
+	reviews, err := s.providerRepo.GetReviewsByProviderID(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	for i := range reviews {
+		reviews[i].HouseholderID = ""
+	}
+	export.ReviewsReceived = reviews
+
+	if export.Documents, err = s.documentRepo.GetDocumentsByProviderID(ctx, providerID); err != nil {
+		return err
+	}
+	if export.PublicProfile, err = s.profileRepo.GetProfileDetails(ctx, providerID); err != nil {
+		return err
+	}
+	export.Portfolio, err = s.profileRepo.GetPortfolioImages(ctx, providerID)
+	return err
+}
+
+// EraseOwnData closes the account of the user and erases their personal data. The password is asked again
+// because the erasure cannot be undone.
+func (s *PrivacyService) EraseOwnData(ctx context.Context, userID, password string) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !passwordMatches(user, password) {
+		return model.ErrInvalidCredentials
+	}
+	return s.erase(ctx, userID, userID)
+}
+
+// EraseUserData lets an admin close an account and erase its personal data, for example on a request
+// received by mail
+func (s *PrivacyService) EraseUserData(ctx context.Context, adminID, userID string) error {
+	if err := s.requireAdmin(ctx, adminID); err != nil {
+		return err
+	}
+	return s.erase(ctx, adminID, userID)
+}
+
+// erase deletes the account if it is still open and replaces its personal data, and every copy of it on
+// requests, offers, reviews and the login audit, with placeholders. Notifications, favourites, review photos,
+// verification and reset codes, two-factor secrets, verification documents and the public profile of a
+// provider are removed. Deleting the account opened the requests booked directly with a provider to all
+// providers. Ratings, prices and the request history are kept.
+func (s *PrivacyService) erase(ctx context.Context, actorID, userID string) error {
+	var blobKeys []string
+	err := retryOnConflict(ctx, func(ctx context.Context) error {
+		user, err := s.userRepo.GetUserByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.IsErased() {
+			return model.ErrAlreadyErased
+		}
+		if model.IsStaffRole(user.Role) {
+			return model.ErrStaffErasure
+		}
+
+		blobKeys = nil
+		return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			erased := *user
+			erased.Name, erased.Email, erased.Password = model.ErasedName, model.ErasedEmail(userID), ""
+			erased.Address, erased.Contact, erased.Latitude, erased.Longitude = "", "", 0, 0
+			if accountStatus(user) != model.AccountDeleted {
+				// The snapshot in the audit log must not keep what is being erased
+				if err := s.accountService.changeStatus(ctx, actorID, &erased, model.AccountDeleted, erasureReason, nil); err != nil {
+					return err
+				}
+			}
+
+			if err := s.userRepo.AnonymiseUser(ctx, userID, erased.Name, erased.Email); err != nil {
+				return err
+			}
+			if err := s.serviceRequestRepo.AnonymiseHouseholder(ctx, userID, model.ErasedName); err != nil {
+				return err
+			}
+			if err := s.serviceRequestRepo.AnonymiseProviderDetails(ctx, userID, model.ErasedName); err != nil {
+				return err
+			}
+			if err := s.customRequestRepo.AnonymiseHouseholder(ctx, userID, model.ErasedName); err != nil {
+				return err
+			}
+			if err := s.providerRepo.ClearReviewComments(ctx, userID); err != nil {
+				return err
+			}
+			photos, err := s.photoRepo.GetPhotosByHouseholderID(ctx, userID)
+			if err != nil {
+				return err
+			}
+			for _, photo := range photos {
+				blobKeys = append(blobKeys, photo.BlobKey)
+			}
+			if err := s.photoRepo.DeletePhotosByHouseholderID(ctx, userID); err != nil {
+				return err
+			}
+			if err := s.favouriteRepo.DeleteFavouritesByUserID(ctx, userID); err != nil {
+				return err
+			}
+			if err := s.notificationRepo.DeleteNotifications(ctx, userID); err != nil {
+				return err
+			}
+			if err := s.loginAttemptRepo.AnonymiseAuditEntries(ctx, normalizeEmail(user.Email), erased.Email); err != nil {
+				return err
+			}
+			if err := s.loginAttemptRepo.ResetThrottle(ctx, model.ThrottleAccount, normalizeEmail(user.Email)); err != nil {
+				return err
+			}
+			if err := s.tokenRepo.DeleteTokens(ctx, userID); err != nil {
+				return err
+			}
+			if err := s.twoFactorRepo.DeleteTwoFactor(ctx, userID); err != nil {
+				return err
+			}
+
+			if user.Role == model.RoleServiceProvider {
+				documents, err := s.documentRepo.GetDocumentsByProviderID(ctx, userID)
+				if err != nil {
+					return err
+				}
+				for _, document := range documents {
+					blobKeys = append(blobKeys, document.BlobKey)
+				}
+				if err := s.documentRepo.DeleteDocumentsByProviderID(ctx, userID); err != nil {
+					return err
+				}
+				portfolio, err := s.profileRepo.GetPortfolioImages(ctx, userID)
+				if err != nil {
+					return err
+				}
+				for _, image := range portfolio {
+					blobKeys = append(blobKeys, image.BlobKey)
+				}
+				if err := s.profileRepo.DeleteProfile(ctx, userID); err != nil {
+					return err
+				}
+			}
+			return audit(WithActor(ctx, actorID), s.auditRepo, "erase personal data", model.EntityUser, userID, nil, nil)
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	// The rows are gone, a file left behind by a failed delete is no longer reachable
+	for _, key := range blobKeys {
+		if err := s.blobStore.Delete(ctx, key); err != nil {
+			slog.Warn("could not delete file of an erased account", "key", key, "error", err)
+		}
+	}
+	return nil
+}
+
+// requireAdmin checks that userID is an admin whose account may still be used. Users keep the right to
+// export and erase their own data while their account is blocked.
+func (s *PrivacyService) requireAdmin(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Role != model.RoleAdmin {
+		return model.ErrAdminRequired
+	}
+	return s.accountService.EnsureActive(ctx, userID)
+}
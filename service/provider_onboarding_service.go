@@ -0,0 +1,230 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"strings"
+	"time"
+)
+
+// MaxDocumentSize is the largest verification document a provider may upload
+const MaxDocumentSize = 10 << 20
+
+var documentExtensions = map[string]bool{".pdf": true, ".jpg": true, ".jpeg": true, ".png": true}
+
+var documentTypes = map[string]bool{model.DocumentIdentity: true, model.DocumentLicence: true, model.DocumentInsurance: true}
+
+// ProviderOnboardingService takes new providers through document verification. Until an admin approves
+// their documents providers cannot list services or quote on requests.
+type ProviderOnboardingService struct {
+	providerRepo     interfaces.ServiceProviderRepository
+	documentRepo     interfaces.ProviderDocumentRepository
+	notificationRepo interfaces.NotificationRepository
+	blobStore        interfaces.BlobStore
+	accountGuard     interfaces.AccountGuard
+	auditRepo        interfaces.AuditRepository
+	txManager        interfaces.TransactionManager
+}
+
+// NewProviderOnboardingService initializes a new ProviderOnboardingService
+func NewProviderOnboardingService(providerRepo interfaces.ServiceProviderRepository, documentRepo interfaces.ProviderDocumentRepository, notificationRepo interfaces.NotificationRepository, blobStore interfaces.BlobStore, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *ProviderOnboardingService {
+	return &ProviderOnboardingService{
+		providerRepo:     providerRepo,
+		documentRepo:     documentRepo,
+		notificationRepo: notificationRepo,
+		blobStore:        blobStore,
+		accountGuard:     accountGuard,
+		auditRepo:        auditRepo,
+		txManager:        txManager,
+	}
+}
+
+// GetOrCreateProvider loads the provider profile of a user, creating it on first login. New providers
+// start out pending verification.
+func (s *ProviderOnboardingService) GetOrCreateProvider(ctx context.Context, user *model.User) (*model.ServiceProvider, error) {
+	provider, err := s.providerRepo.GetProviderByID(ctx, user.ID)
+	if err == nil {
+		provider.User = *user
+		return provider, nil
+	}
+	if err.Error() != "provider not found" {
+		return nil, err
+	}
+
+	provider = &model.ServiceProvider{
+		User:               *user,
+		ServicesOffered:    []model.Service{},
+		Reviews:            []*model.Review{},
+		Availability:       true,
+		IsActive:           true,
+		VerificationStatus: model.VerificationPending,
+	}
+	if err := s.providerRepo.SaveServiceProvider(ctx, *provider); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+// SubmitDocument stores a verification document for the provider. A rejected provider who submits a
+// new document is put back in the verification queue.
+func (s *ProviderOnboardingService) SubmitDocument(ctx context.Context, providerID, documentType, fileName string, content io.Reader) (*model.ProviderDocument, error) {
+	if !documentTypes[documentType] {
+		return nil, fmt.Errorf("unknown document type %q", documentType)
+	}
+	fileName = filepath.Base(strings.TrimSpace(fileName))
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if !documentExtensions[ext] {
+		return nil, errors.New("documents must be PDF, JPEG or PNG files")
+	}
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return nil, err
+	}
+
+	provider, err := s.providerRepo.GetProviderByID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	if provider.IsVerified() {
+		return nil, errors.New("your account is already verified")
+	}
+
+	document := model.ProviderDocument{
+		ID:         GetUniqueID(),
+		ProviderID: providerID,
+		Type:       documentType,
+		FileName:   fileName,
+		UploadedAt: time.Now(),
+	}
+	document.BlobKey = "provider-documents/" + providerID + "/" + document.ID + ext
+
+	size, err := storeUpload(ctx, s.blobStore, document.BlobKey, content, MaxDocumentSize, "document")
+	if err != nil {
+		return nil, err
+	}
+	document.Size = size
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.documentRepo.SaveDocument(ctx, document); err != nil {
+			return err
+		}
+		if err := audit(WithActor(ctx, providerID), s.auditRepo, "submit document", model.EntityProvider, providerID, nil, document); err != nil {
+			return err
+		}
+		if provider.VerificationStatus == model.VerificationRejected {
+			return s.providerRepo.UpdateVerificationStatus(ctx, providerID, model.VerificationPending, "", "")
+		}
+		return nil
+	})
+	if err != nil {
+		discardBlob(ctx, s.blobStore, document.BlobKey)
+		return nil, err
+	}
+	return &document, nil
+}
+
+// GetDocuments lists the documents a provider has submitted
+func (s *ProviderOnboardingService) GetDocuments(ctx context.Context, providerID string) ([]model.ProviderDocument, error) {
+	return s.documentRepo.GetDocumentsByProviderID(ctx, providerID)
+}
+
+// OpenDocument returns a document together with its content, the caller closes the reader
+func (s *ProviderOnboardingService) OpenDocument(ctx context.Context, documentID string) (*model.ProviderDocument, io.ReadCloser, error) {
+	document, err := s.documentRepo.GetDocumentByID(ctx, documentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	content, err := s.blobStore.Open(ctx, document.BlobKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return document, content, nil
+}
+
+// GetPendingProviders returns the verification queue
+func (s *ProviderOnboardingService) GetPendingProviders(ctx context.Context) ([]model.ServiceProvider, error) {
+	return s.providerRepo.GetProvidersByVerificationStatus(ctx, model.VerificationPending)
+}
+
+// ApproveProvider verifies a pending provider. At least an identity document must have been submitted.
+func (s *ProviderOnboardingService) ApproveProvider(ctx context.Context, adminID, providerID, note string) error {
+	if err := s.accountGuard.EnsureActive(ctx, adminID); err != nil {
+		return err
+	}
+	provider, err := s.pendingProvider(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	documents, err := s.documentRepo.GetDocumentsByProviderID(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	hasIdentity := false
+	for _, document := range documents {
+		if document.Type == model.DocumentIdentity {
+			hasIdentity = true
+			break
+		}
+	}
+	if !hasIdentity {
+		return errors.New("the provider has not submitted an identity document")
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.providerRepo.UpdateVerificationStatus(ctx, providerID, model.VerificationVerified, strings.TrimSpace(note), adminID); err != nil {
+			return err
+		}
+		if err := s.auditVerification(ctx, adminID, provider, model.VerificationVerified, strings.TrimSpace(note)); err != nil {
+			return err
+		}
+		return notify(ctx, s.notificationRepo, providerID, "Your account has been verified, you can now list services and quote on requests.")
+	})
+}
+
+// RejectProvider turns down a pending provider, the reason is passed on to the provider
+func (s *ProviderOnboardingService) RejectProvider(ctx context.Context, adminID, providerID, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required to reject a provider")
+	}
+	if err := s.accountGuard.EnsureActive(ctx, adminID); err != nil {
+		return err
+	}
+	provider, err := s.pendingProvider(ctx, providerID)
+	if err != nil {
+		return err
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.providerRepo.UpdateVerificationStatus(ctx, providerID, model.VerificationRejected, reason, adminID); err != nil {
+			return err
+		}
+		if err := s.auditVerification(ctx, adminID, provider, model.VerificationRejected, reason); err != nil {
+			return err
+		}
+		return notify(ctx, s.notificationRepo, providerID,
+			fmt.Sprintf("Your verification was rejected: %s. Upload new documents to be reviewed again.", reason))
+	})
+}
+
+func (s *ProviderOnboardingService) pendingProvider(ctx context.Context, providerID string) (*model.ServiceProvider, error) {
+	provider, err := s.providerRepo.GetProviderByID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	if provider.VerificationStatus != model.VerificationPending {
+		return nil, fmt.Errorf("provider is not pending verification (status %s)", provider.VerificationStatus)
+	}
+	return provider, nil
+}
+
+// auditVerification records the verification decision of an admin
+func (s *ProviderOnboardingService) auditVerification(ctx context.Context, adminID string, provider *model.ServiceProvider, status, note string) error {
+	before := map[string]string{"verification_status": provider.VerificationStatus}
+	after := map[string]string{"verification_status": status, "verification_note": note}
+	return audit(WithActor(ctx, adminID), s.auditRepo, "review verification", model.EntityProvider, provider.User.ID, before, after)
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ProviderProfileService manages the public profile of a provider: what they write about themselves, their
+// certifications and portfolio, and the aggregate page householders look at before approving a quote
+type ProviderProfileService struct {
+	profileRepo        interfaces.ProviderProfileRepository
+	providerRepo       interfaces.ServiceProviderRepository
+	serviceRepo        interfaces.ServiceRepository
+	serviceRequestRepo interfaces.ServiceRequestRepository
+	blobStore          interfaces.BlobStore
+	ratingService      *RatingService
+	accountGuard       interfaces.AccountGuard
+	auditRepo          interfaces.AuditRepository
+	txManager          interfaces.TransactionManager
+}
+
+// NewProviderProfileService initializes a new ProviderProfileService
+func NewProviderProfileService(profileRepo interfaces.ProviderProfileRepository, providerRepo interfaces.ServiceProviderRepository, serviceRepo interfaces.ServiceRepository, serviceRequestRepo interfaces.ServiceRequestRepository, blobStore interfaces.BlobStore, ratingService *RatingService, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *ProviderProfileService {
+	return &ProviderProfileService{
+		profileRepo:        profileRepo,
+		providerRepo:       providerRepo,
+		serviceRepo:        serviceRepo,
+		serviceRequestRepo: serviceRequestRepo,
+		blobStore:          blobStore,
+		ratingService:      ratingService,
+		accountGuard:       accountGuard,
+		auditRepo:          auditRepo,
+		txManager:          txManager,
+	}
+}
+
+// GetProviderProfile assembles the public profile of a provider. Only active, verified providers have one
+// and their contact details are left out.
+func (s *ProviderProfileService) GetProviderProfile(ctx context.Context, providerID string) (*model.ProviderProfile, error) {
+	provider, err := s.providerRepo.GetProviderByID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	if !provider.IsListed(time.Now()) {
+		return nil, errors.New("provider not found")
+	}
+
+	details, err := s.profileRepo.GetProfileDetails(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	portfolio, err := s.profileRepo.GetPortfolioImages(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	services, err := s.serviceRepo.GetServiceByProviderID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	rating, err := s.ratingService.GetProviderRating(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	completedJobs, err := s.serviceRequestRepo.CountCompletedJobs(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ProviderProfile{
+		ProviderID:      providerID,
+		Name:            provider.Name,
+		Available:       provider.Availability,
+		Bio:             details.Bio,
+		YearsExperience: details.YearsExperience,
+		Languages:       details.Languages,
+		Certifications:  details.Certifications,
+		Portfolio:       portfolio,
+		Services:        services,
+		Rating:          *rating,
+		CompletedJobs:   completedJobs,
+	}, nil
+}
+
+// GetProfileDetails returns what the provider wrote about themselves, for editing
+func (s *ProviderProfileService) GetProfileDetails(ctx context.Context, providerID string) (*model.ProviderProfileDetails, error) {
+	return s.profileRepo.GetProfileDetails(ctx, providerID)
+}
+
+// UpdateProfileDetails replaces the bio, years of experience and languages on the provider's profile
+func (s *ProviderProfileService) UpdateProfileDetails(ctx context.Context, providerID, bio string, yearsExperience int, languages []string) error {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return err
+	}
+	bio = strings.TrimSpace(bio)
+	if utf8.RuneCountInString(bio) > model.MaxBioLength {
+		return model.ErrBioTooLong
+	}
+	if yearsExperience < 0 || yearsExperience > model.MaxYearsExperience {
+		return fmt.Errorf("years of experience must be between 0 and %d", model.MaxYearsExperience)
+	}
+
+	before, err := s.profileRepo.GetProfileDetails(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	now := time.Now().Truncate(time.Second)
+	after := model.ProviderProfileDetails{
+		ProviderID:      providerID,
+		Bio:             bio,
+		YearsExperience: yearsExperience,
+		Languages:       normalizeLanguages(languages),
+		UpdatedAt:       &now,
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.profileRepo.SaveProfileDetails(ctx, after); err != nil {
+			return err
+		}
+		before.Certifications = nil
+		return audit(WithActor(ctx, providerID), s.auditRepo, "update profile", model.EntityProvider, providerID, before, after)
+	})
+}
+
+// AddCertification lists a qualification on the provider's profile
+func (s *ProviderProfileService) AddCertification(ctx context.Context, providerID, name, issuer string, year int) (*model.Certification, error) {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	issuer = strings.TrimSpace(issuer)
+	if name == "" {
+		return nil, errors.New("the certification needs a name")
+	}
+	if utf8.RuneCountInString(name) > model.MaxCertificationLen || utf8.RuneCountInString(issuer) > model.MaxCertificationLen {
+		return nil, fmt.Errorf("the name and issuer may not be longer than %d characters", model.MaxCertificationLen)
+	}
+	if year < 0 || year > time.Now().Year() {
+		return nil, errors.New("the year of the certification is not valid")
+	}
+
+	certification := model.Certification{
+		ID:         GetUniqueID(),
+		ProviderID: providerID,
+		Name:       name,
+		Issuer:     issuer,
+		Year:       year,
+	}
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.profileRepo.SaveCertification(ctx, certification); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, providerID), s.auditRepo, "add certification", model.EntityProvider, providerID, nil, certification)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &certification, nil
+}
+
+// RemoveCertification takes a certification off the provider's profile
+func (s *ProviderProfileService) RemoveCertification(ctx context.Context, providerID, certificationID string) error {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return err
+	}
+	certification, err := s.profileRepo.GetCertificationByID(ctx, certificationID)
+	if err != nil {
+		return err
+	}
+	if certification.ProviderID != providerID {
+		return errors.New("certification not found")
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.profileRepo.DeleteCertification(ctx, providerID, certificationID); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, providerID), s.auditRepo, "remove certification", model.EntityProvider, providerID, certification, nil)
+	})
+}
+
+// AddPortfolioImage stores a photo of the provider's past work and shows it on their profile
+func (s *ProviderProfileService) AddPortfolioImage(ctx context.Context, providerID, caption, fileName string, content io.Reader) (*model.PortfolioImage, error) {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return nil, err
+	}
+	fileName = filepath.Base(strings.TrimSpace(fileName))
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if !photoExtensions[ext] {
+		return nil, errors.New("photos must be JPEG or PNG files")
+	}
+	caption = strings.TrimSpace(caption)
+	if utf8.RuneCountInString(caption) > model.MaxCertificationLen {
+		return nil, fmt.Errorf("the caption may not be longer than %d characters", model.MaxCertificationLen)
+	}
+
+	images, err := s.profileRepo.GetPortfolioImages(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	if len(images) >= model.MaxPortfolioImages {
+		return nil, model.ErrTooManyPortfolioImages
+	}
+
+	image := model.PortfolioImage{
+		ID:         GetUniqueID(),
+		ProviderID: providerID,
+		Caption:    caption,
+		FileName:   fileName,
+		UploadedAt: time.Now(),
+	}
+	image.BlobKey = "provider-portfolio/" + providerID + "/" + image.ID + ext
+
+	size, err := storeUpload(ctx, s.blobStore, image.BlobKey, content, MaxPhotoSize, "photo")
+	if err != nil {
+		return nil, err
+	}
+	image.Size = size
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.profileRepo.SavePortfolioImage(ctx, image); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, providerID), s.auditRepo, "add portfolio image", model.EntityProvider, providerID, nil, image)
+	})
+	if err != nil {
+		discardBlob(ctx, s.blobStore, image.BlobKey)
+		return nil, err
+	}
+	return &image, nil
+}
+
+// RemovePortfolioImage takes a photo off the provider's portfolio
+func (s *ProviderProfileService) RemovePortfolioImage(ctx context.Context, providerID, imageID string) error {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return err
+	}
+	image, err := s.profileRepo.GetPortfolioImageByID(ctx, imageID)
+	if err != nil {
+		return err
+	}
+	if image.ProviderID != providerID {
+		return errors.New("image not found")
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.profileRepo.DeletePortfolioImage(ctx, providerID, imageID); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, providerID), s.auditRepo, "remove portfolio image", model.EntityProvider, providerID, image, nil)
+	})
+	if err != nil {
+		return err
+	}
+	discardBlob(ctx, s.blobStore, image.BlobKey)
+	return nil
+}
+
+// OpenPortfolioImage returns a portfolio image together with its content, the caller closes the reader.
+// Like the rest of the profile, the portfolio of a provider who is not listed is not shown.
+func (s *ProviderProfileService) OpenPortfolioImage(ctx context.Context, imageID string) (*model.PortfolioImage, io.ReadCloser, error) {
+	image, err := s.profileRepo.GetPortfolioImageByID(ctx, imageID)
+	if err != nil {
+		return nil, nil, err
+	}
+	provider, err := s.providerRepo.GetProviderByID(ctx, image.ProviderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !provider.IsListed(time.Now()) {
+		return nil, nil, errors.New("image not found")
+	}
+	content, err := s.blobStore.Open(ctx, image.BlobKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return image, content, nil
+}
+
+// normalizeLanguages trims the languages and drops empty entries and duplicates. Languages are stored
+// comma separated so a comma inside one splits it.
+func normalizeLanguages(languages []string) []string {
+	var normalized []string
+	seen := make(map[string]bool)
+	for _, entry := range languages {
+		for _, language := range strings.Split(entry, ",") {
+			language = strings.TrimSpace(language)
+			key := strings.ToLower(language)
+			if language == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			normalized = append(normalized, language)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"sort"
+	"time"
+)
+
+// RatingService keeps the rating totals of providers in step with their reviews and turns them into
+// Bayesian, recency weighted scores. The score stored on the provider, which search ranks by, is a
+// snapshot: it is taken whenever the totals change and again by RefreshScores as reviews age.
+type RatingService struct {
+	ratingRepo      interfaces.RatingRepository
+	providerRepo    interfaces.ServiceProviderRepository
+	providerIndexer interfaces.ProviderIndexer
+	options         model.RatingOptions
+	txManager       interfaces.TransactionManager
+}
+
+// NewRatingService initializes a new RatingService
+func NewRatingService(ratingRepo interfaces.RatingRepository, providerRepo interfaces.ServiceProviderRepository, providerIndexer interfaces.ProviderIndexer, options model.RatingOptions, txManager interfaces.TransactionManager) *RatingService {
+	return &RatingService{
+		ratingRepo:      ratingRepo,
+		providerRepo:    providerRepo,
+		providerIndexer: providerIndexer,
+		options:         options,
+		txManager:       txManager,
+	}
+}
+
+// ApplyReviewChange moves the provider's totals from before to after: before is nil for a new review and
+// after is nil for one that is taken away. Hidden reviews do not count. It must run in the transaction that
+// saves the review, with before read through LockReviewByID in that same transaction; only the totals of
+// the provider are read, never their other reviews.
+func (s *RatingService) ApplyReviewChange(ctx context.Context, before, after *model.Review) error {
+	if before != nil && before.Hidden {
+		before = nil
+	}
+	if after != nil && after.Hidden {
+		after = nil
+	}
+	if before == nil && after == nil {
+		return nil
+	}
+	providerID := reviewProvider(before, after)
+
+	stored, err := s.ratingRepo.LockProviderRatings(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	totals := newRatingTotals(providerID, stored)
+	if before != nil {
+		totals.apply(*before, s.options.HalfLife, (*model.RatingAggregate).Remove)
+	}
+	if after != nil {
+		totals.apply(*after, s.options.HalfLife, (*model.RatingAggregate).Add)
+	}
+
+	if err := s.ratingRepo.SaveRatingAggregates(ctx, totals.changed()); err != nil {
+		return err
+	}
+	return s.setProviderRating(ctx, providerID, totals.overall())
+}
+
+// GetProviderRating returns the rating breakdown of a provider as of now
+func (s *RatingService) GetProviderRating(ctx context.Context, providerID string) (*model.ProviderRating, error) {
+	aggregates, err := s.ratingRepo.GetProviderRatings(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	return s.breakdown(providerID, aggregates, time.Now()), nil
+}
+
+// Rebuild recomputes the totals of every provider from their reviews, for use after the rating settings
+// changed. It returns the number of providers rebuilt.
+func (s *RatingService) Rebuild(ctx context.Context) (int, error) {
+	providerIDs, err := s.ratingRepo.GetRatedProviderIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i, providerID := range providerIDs {
+		reviews, err := s.providerRepo.GetReviewsByProviderID(ctx, providerID)
+		if err != nil {
+			return i, err
+		}
+		sort.Slice(reviews, func(a, b int) bool { return reviews[a].ReviewDate.Before(reviews[b].ReviewDate) })
+
+		totals := newRatingTotals(providerID, nil)
+		for _, review := range model.VisibleReviews(reviews) {
+			totals.apply(review, s.options.HalfLife, (*model.RatingAggregate).Add)
+		}
+		err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.ratingRepo.ReplaceProviderRatings(ctx, providerID, totals.changed()); err != nil {
+				return err
+			}
+			return s.setProviderRating(ctx, providerID, totals.overall())
+		})
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(providerIDs), nil
+}
+
+// RefreshScores stores the score of every rated provider as of now. Reviews lose weight as they age
+// while the prior does not, so the stored scores drift from the real ones between reviews; the app
+// refreshes them every rating.refresh_interval. It returns the number of providers refreshed.
+func (s *RatingService) RefreshScores(ctx context.Context) (int, error) {
+	providerIDs, err := s.ratingRepo.GetRatedProviderIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	for i, providerID := range providerIDs {
+		err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			stored, err := s.ratingRepo.LockProviderRatings(ctx, providerID)
+			if err != nil {
+				return err
+			}
+			return s.setProviderRating(ctx, providerID, newRatingTotals(providerID, stored).overall())
+		})
+		if err != nil {
+			return i, err
+		}
+	}
+	return len(providerIDs), nil
+}
+
+// setProviderRating stores the provider's overall score as of now and refreshes their services in the
+// search index once the transaction commits
+func (s *RatingService) setProviderRating(ctx context.Context, providerID string, overall model.RatingAggregate) error {
+	if err := s.ratingRepo.SetProviderRating(ctx, providerID, overall.Score(time.Now(), s.options)); err != nil {
+		return err
+	}
+	return s.providerIndexer.ReindexProvider(ctx, providerID)
+}
+
+func (s *RatingService) breakdown(providerID string, aggregates []model.RatingAggregate, now time.Time) *model.ProviderRating {
+	rating := &model.ProviderRating{ProviderID: providerID, All: model.RatingBreakdown{Category: model.AllCategories}}
+	byCategory := make(map[string]*model.RatingBreakdown)
+	var categories []string
+	for _, aggregate := range aggregates {
+		if aggregate.ReviewCount == 0 {
+			continue
+		}
+		breakdown := &rating.All
+		if aggregate.Category != model.AllCategories {
+			if byCategory[aggregate.Category] == nil {
+				byCategory[aggregate.Category] = &model.RatingBreakdown{Category: aggregate.Category}
+				categories = append(categories, aggregate.Category)
+			}
+			breakdown = byCategory[aggregate.Category]
+		}
+
+		score := model.RatingScore{Score: aggregate.Score(now, s.options), Average: aggregate.Average(), ReviewCount: aggregate.ReviewCount}
+		if aggregate.Criterion == model.CriterionOverall {
+			breakdown.Overall = score
+			continue
+		}
+		if breakdown.Criteria == nil {
+			breakdown.Criteria = make(map[string]model.RatingScore)
+		}
+		breakdown.Criteria[aggregate.Criterion] = score
+	}
+
+	sort.Strings(categories)
+	for _, category := range categories {
+		rating.Categories = append(rating.Categories, *byCategory[category])
+	}
+	return rating
+}
+
+func reviewProvider(before, after *model.Review) string {
+	if after != nil {
+		return after.ProviderID
+	}
+	return before.ProviderID
+}
+
+// ratingTotals are the aggregates of one provider being changed, keyed by category and criterion
+type ratingTotals struct {
+	providerID string
+	aggregates map[[2]string]*model.RatingAggregate
+	touched    map[[2]string]bool
+}
+
+func newRatingTotals(providerID string, stored []model.RatingAggregate) *ratingTotals {
+	totals := &ratingTotals{
+		providerID: providerID,
+		aggregates: make(map[[2]string]*model.RatingAggregate),
+		touched:    make(map[[2]string]bool),
+	}
+	for i := range stored {
+		totals.aggregates[[2]string{stored[i].Category, stored[i].Criterion}] = &stored[i]
+	}
+	return totals
+}
+
+// apply adds or removes the overall rating and the sub-scores of a review, across all categories and in
+// the review's own category
+func (t *ratingTotals) apply(review model.Review, halfLife time.Duration, change func(*model.RatingAggregate, float64, time.Time, time.Duration)) {
+	categories := []string{model.AllCategories}
+	if review.Category != model.AllCategories {
+		categories = append(categories, review.Category)
+	}
+	for _, category := range categories {
+		change(t.get(category, model.CriterionOverall), review.Rating, review.ReviewDate, halfLife)
+		for criterion, score := range review.Scores {
+			change(t.get(category, criterion), score, review.ReviewDate, halfLife)
+		}
+	}
+}
+
+func (t *ratingTotals) get(category, criterion string) *model.RatingAggregate {
+	key := [2]string{category, criterion}
+	aggregate, ok := t.aggregates[key]
+	if !ok {
+		aggregate = &model.RatingAggregate{ProviderID: t.providerID, Category: category, Criterion: criterion}
+		t.aggregates[key] = aggregate
+	}
+	t.touched[key] = true
+	return aggregate
+}
+
+func (t *ratingTotals) overall() model.RatingAggregate {
+	if aggregate, ok := t.aggregates[[2]string{model.AllCategories, model.CriterionOverall}]; ok {
+		return *aggregate
+	}
+	return model.RatingAggregate{}
+}
+
+// changed lists the aggregates that were added to or taken from, in a stable order
+func (t *ratingTotals) changed() []model.RatingAggregate {
+	var changed []model.RatingAggregate
+	for key := range t.touched {
+		changed = append(changed, *t.aggregates[key])
+	}
+	sort.Slice(changed, func(i, j int) bool {
+		if changed[i].Category != changed[j].Category {
+			return changed[i].Category < changed[j].Category
+		}
+		return changed[i].Criterion < changed[j].Criterion
+	})
+	return changed
+}
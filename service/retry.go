@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"serviceNest/model"
+)
+
+// maxConflictRetries bounds how often a read-modify-write is replayed after losing a race with another writer
+const maxConflictRetries = 3
+
+// retryOnConflict runs fn again while it fails with model.ErrConflict. fn must re-read the records it
+// modifies on every attempt so that its checks are made against the latest state.
+func retryOnConflict(ctx context.Context, fn func(ctx context.Context) error) error {
+	var err error
+	for attempt := 0; attempt < maxConflictRetries; attempt++ {
+		if err = fn(ctx); !errors.Is(err, model.ErrConflict) {
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+	}
+	return err
+}
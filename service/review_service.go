@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// MaxPhotoSize is the largest photo a householder may attach to a review
+const MaxPhotoSize = 10 << 20
+
+var photoExtensions = map[string]bool{".jpg": true, ".jpeg": true, ".png": true}
+
+var photoKinds = map[string]bool{model.PhotoBefore: true, model.PhotoAfter: true}
+
+// ReviewService handles what happens to a review after it was written: its photos, the provider's reply,
+// flags raised by users and the admins' decision to hide or restore it
+type ReviewService struct {
+	providerRepo     interfaces.ServiceProviderRepository
+	flagRepo         interfaces.ReviewFlagRepository
+	photoRepo        interfaces.ReviewPhotoRepository
+	notificationRepo interfaces.NotificationRepository
+	blobStore        interfaces.BlobStore
+	ratingService    *RatingService
+	accountGuard     interfaces.AccountGuard
+	auditRepo        interfaces.AuditRepository
+	txManager        interfaces.TransactionManager
+}
+
+// NewReviewService initializes a new ReviewService
+func NewReviewService(providerRepo interfaces.ServiceProviderRepository, flagRepo interfaces.ReviewFlagRepository, photoRepo interfaces.ReviewPhotoRepository, notificationRepo interfaces.NotificationRepository, blobStore interfaces.BlobStore, ratingService *RatingService, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *ReviewService {
+	return &ReviewService{
+		providerRepo:     providerRepo,
+		flagRepo:         flagRepo,
+		photoRepo:        photoRepo,
+		notificationRepo: notificationRepo,
+		blobStore:        blobStore,
+		ratingService:    ratingService,
+		accountGuard:     accountGuard,
+		auditRepo:        auditRepo,
+		txManager:        txManager,
+	}
+}
+
+// ReplyToReview publishes the provider's answer to a review they received. Each review takes one reply.
+func (s *ReviewService) ReplyToReview(ctx context.Context, providerID, reviewID, reply string) error {
+	reply = strings.TrimSpace(reply)
+	if reply == "" {
+		return errors.New("the reply is empty")
+	}
+	if utf8.RuneCountInString(reply) > model.MaxReplyLength {
+		return model.ErrReplyTooLong
+	}
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return err
+	}
+
+	review, err := s.providerRepo.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+	if review.ProviderID != providerID {
+		return errors.New("review not found")
+	}
+	if review.Reply != "" {
+		return model.ErrAlreadyReplied
+	}
+
+	before := *review
+	replyDate := time.Now()
+	review.Reply = reply
+	review.ReplyDate = &replyDate
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.providerRepo.SaveReviewReply(ctx, reviewID, providerID, reply, replyDate); err != nil {
+			return err
+		}
+		if err := notify(ctx, s.notificationRepo, review.HouseholderID, "The provider has replied to your review: "+reply); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, providerID), s.auditRepo, "reply", model.EntityReview, reviewID, before, review)
+	})
+}
+
+// FlagReview reports a review to the moderators; the reason is shown to them in the moderation queue
+func (s *ReviewService) FlagReview(ctx context.Context, userID, reviewID, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required to flag a review")
+	}
+	if err := s.accountGuard.EnsureActive(ctx, userID); err != nil {
+		return err
+	}
+	if _, err := s.providerRepo.GetReviewByID(ctx, reviewID); err != nil {
+		return err
+	}
+
+	flag := model.ReviewFlag{
+		ID:        GetUniqueID(),
+		ReviewID:  reviewID,
+		UserID:    userID,
+		Reason:    reason,
+		CreatedAt: time.Now(),
+	}
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.flagRepo.SaveFlag(ctx, flag); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, userID), s.auditRepo, "flag", model.EntityReview, reviewID, nil, flag)
+	})
+}
+
+// GetModerationQueue lists the reviews with open flags, the review flagged longest ago first
+func (s *ReviewService) GetModerationQueue(ctx context.Context) ([]model.FlaggedReview, error) {
+	flags, err := s.flagRepo.GetOpenFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var queue []model.FlaggedReview
+	position := make(map[string]int)
+	for _, flag := range flags {
+		if i, ok := position[flag.ReviewID]; ok {
+			queue[i].Flags = append(queue[i].Flags, flag)
+			continue
+		}
+		review, err := s.providerRepo.GetReviewByID(ctx, flag.ReviewID)
+		if err != nil {
+			return nil, err
+		}
+		position[flag.ReviewID] = len(queue)
+		queue = append(queue, model.FlaggedReview{Review: *review, Flags: []model.ReviewFlag{flag}})
+	}
+	return queue, nil
+}
+
+// GetHiddenReviews lists the reviews moderators have hidden, so that they can be restored
+func (s *ReviewService) GetHiddenReviews(ctx context.Context) ([]model.Review, error) {
+	return s.providerRepo.GetHiddenReviews(ctx)
+}
+
+// HideReview takes a review out of public view and out of the provider's rating
+func (s *ReviewService) HideReview(ctx context.Context, adminID, reviewID, reason string) error {
+	return s.moderate(ctx, adminID, reviewID, reason, true)
+}
+
+// RestoreReview makes a review public again. It also dismisses the flags of a review that was never hidden.
+func (s *ReviewService) RestoreReview(ctx context.Context, adminID, reviewID, reason string) error {
+	return s.moderate(ctx, adminID, reviewID, reason, false)
+}
+
+// moderate records the admin's decision on a review, closes its open flags and recalculates the provider's
+// rating, all in one transaction. The householder who wrote the review is told about the decision.
+func (s *ReviewService) moderate(ctx context.Context, adminID, reviewID, reason string, hidden bool) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("a reason is required to moderate a review")
+	}
+	if err := s.accountGuard.EnsureActive(ctx, adminID); err != nil {
+		return err
+	}
+	action, message := "restore", "Your review is visible again: %s"
+	if hidden {
+		action, message = "hide", "Your review has been hidden by a moderator: %s"
+	}
+
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Locked so that an edit by the householder cannot change the review between this read and the update
+		review, err := s.providerRepo.LockReviewByID(ctx, reviewID)
+		if err != nil {
+			return err
+		}
+		before := *review
+		moderatedAt := time.Now()
+		review.Hidden = hidden
+		review.ModerationNote = reason
+		review.ModeratedBy = adminID
+		review.ModeratedAt = &moderatedAt
+
+		if err := s.providerRepo.UpdateReviewModeration(ctx, *review); err != nil {
+			return err
+		}
+		if err := s.flagRepo.ResolveFlags(ctx, reviewID); err != nil {
+			return err
+		}
+		if err := s.ratingService.ApplyReviewChange(ctx, &before, review); err != nil {
+			return errors.New("failed to update provider rating")
+		}
+		if before.Hidden != hidden {
+			if err := notify(ctx, s.notificationRepo, review.HouseholderID, fmt.Sprintf(message, reason)); err != nil {
+				return err
+			}
+		}
+		return audit(WithActor(ctx, adminID), s.auditRepo, action, model.EntityReview, reviewID, before, review)
+	})
+}
+
+// AttachPhoto adds a before or after photo of the job to one of the householder's reviews while the review
+// can still be edited
+func (s *ReviewService) AttachPhoto(ctx context.Context, householderID, reviewID, kind, fileName string, content io.Reader) (*model.ReviewPhoto, error) {
+	if !photoKinds[kind] {
+		return nil, fmt.Errorf("unknown photo kind %q", kind)
+	}
+	fileName = filepath.Base(strings.TrimSpace(fileName))
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if !photoExtensions[ext] {
+		return nil, errors.New("photos must be JPEG or PNG files")
+	}
+	if err := s.accountGuard.EnsureActive(ctx, householderID); err != nil {
+		return nil, err
+	}
+
+	review, err := s.ownReview(ctx, householderID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if !review.Editable(time.Now()) {
+		return nil, model.ErrReviewEditClosed
+	}
+	photos, err := s.photoRepo.GetPhotosByReviewID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if len(photos) >= model.MaxReviewPhotos {
+		return nil, model.ErrTooManyPhotos
+	}
+
+	photo := model.ReviewPhoto{
+		ID:         GetUniqueID(),
+		ReviewID:   reviewID,
+		Kind:       kind,
+		FileName:   fileName,
+		UploadedAt: time.Now(),
+	}
+	photo.BlobKey = "review-photos/" + reviewID + "/" + photo.ID + ext
+
+	size, err := storeUpload(ctx, s.blobStore, photo.BlobKey, content, MaxPhotoSize, "photo")
+	if err != nil {
+		return nil, err
+	}
+	photo.Size = size
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.photoRepo.SavePhoto(ctx, photo); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, householderID), s.auditRepo, "attach photo", model.EntityReview, reviewID, nil, photo)
+	})
+	if err != nil {
+		discardBlob(ctx, s.blobStore, photo.BlobKey)
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// RemovePhoto takes a photo off one of the householder's reviews
+func (s *ReviewService) RemovePhoto(ctx context.Context, householderID, photoID string) error {
+	if err := s.accountGuard.EnsureActive(ctx, householderID); err != nil {
+		return err
+	}
+	photo, err := s.photoRepo.GetPhotoByID(ctx, photoID)
+	if err != nil {
+		return err
+	}
+	if _, err := s.ownReview(ctx, householderID, photo.ReviewID); err != nil {
+		return errors.New("photo not found")
+	}
+
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.photoRepo.DeletePhoto(ctx, photoID); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, householderID), s.auditRepo, "remove photo", model.EntityReview, photo.ReviewID, photo, nil)
+	})
+	if err != nil {
+		return err
+	}
+	discardBlob(ctx, s.blobStore, photo.BlobKey)
+	return nil
+}
+
+// GetPhotos lists the photos of a review, oldest first
+func (s *ReviewService) GetPhotos(ctx context.Context, reviewID string) ([]model.ReviewPhoto, error) {
+	return s.photoRepo.GetPhotosByReviewID(ctx, reviewID)
+}
+
+// OpenPhoto returns a photo together with its content, the caller closes the reader. Photos of hidden
+// reviews are not shown.
+func (s *ReviewService) OpenPhoto(ctx context.Context, photoID string) (*model.ReviewPhoto, io.ReadCloser, error) {
+	photo, err := s.photoRepo.GetPhotoByID(ctx, photoID)
+	if err != nil {
+		return nil, nil, err
+	}
+	review, err := s.providerRepo.GetReviewByID(ctx, photo.ReviewID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if review.Hidden {
+		return nil, nil, errors.New("photo not found")
+	}
+	content, err := s.blobStore.Open(ctx, photo.BlobKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return photo, content, nil
+}
+
+// GetProviderReviewSummary assembles the review section of a provider's profile page: the rating of each
+// criterion, the visible reviews with their photos and the photo gallery
+func (s *ReviewService) GetProviderReviewSummary(ctx context.Context, providerID string) (*model.ProviderReviewSummary, error) {
+	rating, err := s.ratingService.GetProviderRating(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.providerRepo.GetReviewsByProviderID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+	gallery, err := s.photoRepo.GetPhotosByProviderID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &model.ProviderReviewSummary{ProviderID: providerID, Rating: *rating, Reviews: model.VisibleReviews(reviews), Gallery: gallery}
+	position := make(map[string]int, len(summary.Reviews))
+	for i, review := range summary.Reviews {
+		position[review.ID] = i
+	}
+	// The gallery is newest first, the photos of a single review are shown oldest first
+	for i := len(gallery) - 1; i >= 0; i-- {
+		if j, ok := position[gallery[i].ReviewID]; ok {
+			summary.Reviews[j].Photos = append(summary.Reviews[j].Photos, gallery[i])
+		}
+	}
+	return summary, nil
+}
+
+// ownReview loads a review written by the householder
+func (s *ReviewService) ownReview(ctx context.Context, householderID, reviewID string) (*model.Review, error) {
+	review, err := s.providerRepo.GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, err
+	}
+	if review.HouseholderID != householderID {
+		return nil, errors.New("review not found")
+	}
+	return review, nil
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/util"
+	"strings"
+	"time"
+)
+
+// RoleService creates the first admin and lets admins hand out and take back the admin and support roles
+type RoleService struct {
+	userRepo         interfaces.UserRepository
+	roleHistoryRepo  interfaces.RoleHistoryRepository
+	notificationRepo interfaces.NotificationRepository
+	accountGuard     interfaces.AccountGuard
+	auditRepo        interfaces.AuditRepository
+	txManager        interfaces.TransactionManager
+}
+
+// NewRoleService initializes a new RoleService
+func NewRoleService(userRepo interfaces.UserRepository, roleHistoryRepo interfaces.RoleHistoryRepository, notificationRepo interfaces.NotificationRepository, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *RoleService {
+	return &RoleService{
+		userRepo:         userRepo,
+		roleHistoryRepo:  roleHistoryRepo,
+		notificationRepo: notificationRepo,
+		accountGuard:     accountGuard,
+		auditRepo:        auditRepo,
+		txManager:        txManager,
+	}
+}
+
+// BootstrapAdmin creates the first admin account. It refuses once any admin exists, later admins are
+// granted the role by an existing one.
+func (s *RoleService) BootstrapAdmin(ctx context.Context, name, email, password string) (*model.User, error) {
+	name = strings.TrimSpace(name)
+	email = strings.TrimSpace(email)
+	if name == "" {
+		return nil, errors.New("a name is required")
+	}
+	if err := util.ValidateEmail(email); err != nil {
+		return nil, err
+	}
+	if err := util.ValidatePassword(password); err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &model.User{
+		ID:       GetUniqueID(),
+		Name:     name,
+		Email:    email,
+		Password: string(hashedPassword),
+		Role:     model.RoleAdmin,
+		// The operator running the command vouches for the address
+		EmailVerified: true,
+	}
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		admins, err := s.userRepo.GetUsersByRole(ctx, model.RoleAdmin)
+		if err != nil {
+			return err
+		}
+		if len(admins) > 0 {
+			return model.ErrAdminExists
+		}
+		if existing, _ := s.userRepo.GetUserByEmail(ctx, email); existing != nil {
+			return errors.New("email already registered")
+		}
+		if err := s.userRepo.SaveUser(ctx, user); err != nil {
+			return err
+		}
+		err = s.roleHistoryRepo.SaveRoleChange(ctx, model.RoleChange{
+			ID:        GetUniqueID(),
+			UserID:    user.ID,
+			ToRole:    model.RoleAdmin,
+			ChangedBy: systemActor,
+			ChangedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		return audit(ctx, s.auditRepo, "bootstrap admin", model.EntityUser, user.ID, nil, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetStaff lists the admins followed by the support staff
+func (s *RoleService) GetStaff(ctx context.Context) ([]model.User, error) {
+	admins, err := s.userRepo.GetUsersByRole(ctx, model.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	support, err := s.userRepo.GetUsersByRole(ctx, model.RoleSupport)
+	if err != nil {
+		return nil, err
+	}
+	return append(admins, support...), nil
+}
+
+// GetRoleHistory lists the role changes of an account, most recent first
+func (s *RoleService) GetRoleHistory(ctx context.Context, userID string) ([]model.RoleChange, error) {
+	return s.roleHistoryRepo.GetRoleHistory(ctx, userID)
+}
+
+// GrantRole makes a householder account, or another staff account, an admin or support account.
+// Provider accounts keep their role, staff work is done from a separate account.
+func (s *RoleService) GrantRole(ctx context.Context, adminID, userID, role string) error {
+	if !model.IsStaffRole(role) {
+		return fmt.Errorf("only the %s and %s roles can be granted", model.RoleAdmin, model.RoleSupport)
+	}
+	return s.changeRole(ctx, adminID, userID, role, func(user *model.User) error {
+		if user.Role == role {
+			return fmt.Errorf("the account already has the %s role", role)
+		}
+		if user.Role != model.RoleHouseholder && !model.IsStaffRole(user.Role) {
+			return fmt.Errorf("the %s role cannot be granted to a %s account", role, user.Role)
+		}
+		return nil
+	})
+}
+
+// RevokeRole turns an admin or support account back into a householder account. The last admin cannot
+// be revoked.
+func (s *RoleService) RevokeRole(ctx context.Context, adminID, userID string) error {
+	return s.changeRole(ctx, adminID, userID, model.RoleHouseholder, func(user *model.User) error {
+		if !model.IsStaffRole(user.Role) {
+			return errors.New("the account has no admin or support role")
+		}
+		return nil
+	})
+}
+
+// changeRole checks that an admin other than the user asked for the change, lets check validate the
+// current role and records the change with its history entry
+func (s *RoleService) changeRole(ctx context.Context, adminID, userID, role string, check func(user *model.User) error) error {
+	if adminID == userID {
+		return errors.New("you cannot change the role of your own account")
+	}
+	if err := s.accountGuard.EnsureActive(ctx, adminID); err != nil {
+		return err
+	}
+	return retryOnConflict(ctx, func(ctx context.Context) error {
+		return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			admin, err := s.userRepo.GetUserByID(ctx, adminID)
+			if err != nil {
+				return err
+			}
+			if admin.Role != model.RoleAdmin {
+				return model.ErrAdminRequired
+			}
+			user, err := s.userRepo.GetUserByID(ctx, userID)
+			if err != nil {
+				return err
+			}
+			if err := check(user); err != nil {
+				return err
+			}
+			if user.Role == model.RoleAdmin {
+				admins, err := s.userRepo.GetUsersByRole(ctx, model.RoleAdmin)
+				if err != nil {
+					return err
+				}
+				if len(admins) <= 1 {
+					return errors.New("the last admin cannot lose the role")
+				}
+			}
+
+			if err := s.userRepo.UpdateRole(ctx, user.ID, user.Role, role); err != nil {
+				return err
+			}
+			err = s.roleHistoryRepo.SaveRoleChange(ctx, model.RoleChange{
+				ID:        GetUniqueID(),
+				UserID:    user.ID,
+				FromRole:  user.Role,
+				ToRole:    role,
+				ChangedBy: adminID,
+				ChangedAt: time.Now(),
+			})
+			if err != nil {
+				return err
+			}
+			after := *user
+			after.Role = role
+			if err := audit(WithActor(ctx, adminID), s.auditRepo, "change role", model.EntityUser, user.ID, user, after); err != nil {
+				return err
+			}
+			return notify(ctx, s.notificationRepo, user.ID, fmt.Sprintf("Your account role changed from %s to %s", user.Role, role))
+		})
+	})
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"strings"
+)
+
+// defaultSearchLimit caps the number of results when the caller does not ask for a limit
+const defaultSearchLimit = 20
+
+type SearchService struct {
+	index interfaces.ServiceIndex
+}
+
+// NewSearchService initializes a new SearchService over the given index
+func NewSearchService(index interfaces.ServiceIndex) *SearchService {
+	return &SearchService{index: index}
+}
+
+// SearchServices runs a free-text search for the householder. Providers close to the householder
+// rank higher when both locations are known.
+func (s *SearchService) SearchServices(ctx context.Context, householder *model.Householder, text string, limit int) ([]model.SearchResult, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, errors.New("search text is required")
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	query := model.SearchQuery{Text: text, Limit: limit}
+	if householder != nil && (householder.Latitude != 0 || householder.Longitude != 0) {
+		query.Latitude = householder.Latitude
+		query.Longitude = householder.Longitude
+		query.UseLocation = true
+	}
+	return s.index.Search(query), nil
+}
@@ -1,9 +1,9 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"github.com/fatih/color"
 	"serviceNest/interfaces"
 	"serviceNest/model"
 )
@@ -12,143 +12,303 @@ type ServiceProviderService struct {
 	serviceProviderRepo interfaces.ServiceProviderRepository
 	serviceRequestRepo  interfaces.ServiceRequestRepository
 	serviceRepo         interfaces.ServiceRepository
+	categoryRepo        interfaces.CategoryRepository
+	notificationRepo    interfaces.NotificationRepository
+	accountGuard        interfaces.AccountGuard
+	auditRepo           interfaces.AuditRepository
+	txManager           interfaces.TransactionManager
 }
 
 // NewServiceProviderService initializes a new ServiceProviderService
-func NewServiceProviderService(serviceProviderRepo interfaces.ServiceProviderRepository, serviceRequestRepo interfaces.ServiceRequestRepository, serviceRepo interfaces.ServiceRepository) *ServiceProviderService {
+func NewServiceProviderService(serviceProviderRepo interfaces.ServiceProviderRepository, serviceRequestRepo interfaces.ServiceRequestRepository, serviceRepo interfaces.ServiceRepository, categoryRepo interfaces.CategoryRepository, notificationRepo interfaces.NotificationRepository, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *ServiceProviderService {
 	return &ServiceProviderService{
 		serviceProviderRepo: serviceProviderRepo,
 		serviceRequestRepo:  serviceRequestRepo,
 		serviceRepo:         serviceRepo,
+		categoryRepo:        categoryRepo,
+		notificationRepo:    notificationRepo,
+		accountGuard:        accountGuard,
+		auditRepo:           auditRepo,
+		txManager:           txManager,
 	}
 }
 
-// AddService adds a new service_test to the provider's list of offered services
-func (s *ServiceProviderService) AddService(providerID string, newService model.Service) error {
+// AddService adds a new service_test to the provider's list of offered services. The category must be
+// part of the catalogue and is stored under its catalogue spelling.
+func (s *ServiceProviderService) AddService(ctx context.Context, providerID string, newService model.Service) error {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return err
+	}
+	category, err := findCategory(ctx, s.categoryRepo, newService.Category)
+	if err != nil {
+		return err
+	}
+	newService.Category = category.Name
+
 	// Get the service_test provider
-	provider, err := s.serviceProviderRepo.GetProviderByID(providerID)
+	provider, err := s.serviceProviderRepo.GetProviderByID(ctx, providerID)
 	if err != nil {
 		return err
 	}
+	if !provider.IsVerified() {
+		return model.ErrProviderNotVerified
+	}
 
 	// Add the new service_test to the provider's list
 	provider.ServicesOffered = append(provider.ServicesOffered, newService)
 
-	// Save the updated service_test provider information
-	err = s.serviceProviderRepo.UpdateServiceProvider(provider)
-	if err != nil {
-		return err
-	}
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Save the updated service_test provider information
+		if err := s.serviceProviderRepo.UpdateServiceProvider(ctx, provider); err != nil {
+			return err
+		}
 
-	// Save the new service_test to the service_test repository_test
-	return s.serviceRepo.SaveService(newService)
+		// Save the new service_test to the service_test repository_test
+		if err := s.serviceRepo.SaveService(ctx, newService); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, providerID), s.auditRepo, "create", model.EntityService, newService.ID, nil, newService)
+	})
 }
 
 // UpdateService updates an existing service_test offered by the provider
-func (s *ServiceProviderService) UpdateService(providerID, serviceID string, updatedService model.Service) error {
-	// Save the updated service provider information
-	err := s.serviceRepo.UpdateService(providerID, updatedService)
+func (s *ServiceProviderService) UpdateService(ctx context.Context, providerID, serviceID string, updatedService model.Service) error {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return err
+	}
+	before, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
 	if err != nil {
 		return err
 	}
 
-	// Update the service in the service repository
-	return nil
-}
-func (s *ServiceProviderService) GetAllServiceRequests() ([]model.ServiceRequest, error) {
-	return s.serviceRequestRepo.GetAllServiceRequests()
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Update the service in the service repository
+		if err := s.serviceRepo.UpdateService(ctx, providerID, updatedService); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, providerID), s.auditRepo, "update", model.EntityService, serviceID, before, updatedService)
+	})
 }
 
-func (s *ServiceProviderService) RemoveService(providerID, serviceID string) error {
-	err := s.serviceRepo.RemoveServiceByProviderID(providerID, serviceID)
+// GetAllServiceRequests lists the requests the provider may quote on, leaving out those booked directly with
+// another provider. Householder addresses and the contact details of other providers are masked, they are
+// only shared once a householder approves a provider.
+func (s *ServiceProviderService) GetAllServiceRequests(ctx context.Context, providerID string, opts model.QueryOptions) ([]model.ServiceRequest, error) {
+	opts.OfferedTo = providerID
+	requests, err := s.serviceRequestRepo.GetAllServiceRequests(ctx, opts)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+	for i := range requests {
+		requests[i].MaskForProvider("")
+	}
+	return requests, nil
 }
 
-func (s *ServiceProviderService) AcceptServiceRequest(providerID, requestID string) error {
-	serviceRequest, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
+func (s *ServiceProviderService) RemoveService(ctx context.Context, providerID, serviceID string) error {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return err
+	}
+	before, err := s.serviceRepo.GetServiceByID(ctx, serviceID)
 	if err != nil {
 		return err
 	}
 
-	if serviceRequest.ApproveStatus {
-		return fmt.Errorf("service request has already been approved")
-	}
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.serviceRepo.RemoveServiceByProviderID(ctx, providerID, serviceID); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, providerID), s.auditRepo, "delete", model.EntityService, serviceID, before, nil)
+	})
+}
 
-	// Update the service request status to "Accepted"
-	serviceRequest.Status = "Accepted"
+// AcceptServiceRequest records the provider's quote on a request; the request and the provider detail are saved atomically.
+// If another user changes the request concurrently the request is re-read and the checks are repeated.
+func (s *ServiceProviderService) AcceptServiceRequest(ctx context.Context, providerID, requestID, estimatedPrice string) error {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return err
+	}
+	if err := s.requireVerified(ctx, providerID); err != nil {
+		return err
+	}
 
 	// Get the ServiceProvider details
-	provider, err := s.serviceProviderRepo.GetProviderDetailByID(providerID)
+	provider, err := s.serviceProviderRepo.GetProviderDetailByID(ctx, providerID)
 	if err != nil {
 		return err
 	}
-	providerReviews, err := s.serviceProviderRepo.GetReviewsByProviderID(providerID)
+	providerReviews, err := s.serviceProviderRepo.GetReviewsByProviderID(ctx, providerID)
 	if err != nil {
 		return err
 	}
 	provider.ServiceProviderID = providerID
-	var estimatedPrice string
-	color.Cyan("Enter the Price for service:")
-	fmt.Scanln(&estimatedPrice)
-
 	provider.Price = estimatedPrice
 
-	// Add ServiceProvider details to the ServiceRequest
-	serviceRequest.ProviderDetails = append(serviceRequest.ProviderDetails, model.ServiceProviderDetails{
-		ServiceProviderID: providerID,
-		Name:              provider.Name,
-		Contact:           provider.Contact,
-		Address:           provider.Address,
-		Price:             estimatedPrice,
-		Rating:            provider.Rating,
-		Reviews:           providerReviews,
-	})
+	return retryOnConflict(ctx, func(ctx context.Context) error {
+		serviceRequest, err := s.serviceRequestRepo.GetServiceRequestByID(ctx, requestID)
+		if err != nil {
+			return err
+		}
 
-	// Save the updated service request
-	err = s.serviceRequestRepo.UpdateServiceRequest(serviceRequest)
+		if !serviceRequest.OfferedTo(providerID) {
+			return errors.New("service request not found")
+		}
+		if serviceRequest.ApproveStatus {
+			return fmt.Errorf("service request has already been approved")
+		}
+		if serviceRequest.Status == "Cancelled" {
+			return fmt.Errorf("service request has been cancelled")
+		}
+		before := *serviceRequest
+
+		// Update the service request status to "Accepted"
+		serviceRequest.Status = "Accepted"
+
+		// Add ServiceProvider details to the ServiceRequest
+		serviceRequest.ProviderDetails = append(serviceRequest.ProviderDetails, model.ServiceProviderDetails{
+			ServiceProviderID: providerID,
+			Name:              provider.Name,
+			Contact:           provider.Contact,
+			Address:           provider.Address,
+			Price:             estimatedPrice,
+			Rating:            provider.Rating,
+			Reviews:           model.VisibleReviews(providerReviews),
+		})
+
+		return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			// Save the updated service request
+			if err := s.serviceRequestRepo.UpdateServiceRequest(ctx, serviceRequest); err != nil {
+				return err
+			}
+			if err := s.serviceProviderRepo.SaveServiceProviderDetail(ctx, provider, requestID); err != nil {
+				return err
+			}
+			return audit(WithActor(ctx, providerID), s.auditRepo, "quote", model.EntityServiceRequest, requestID, before, serviceRequest)
+		})
+	})
+}
 
-	err = s.serviceProviderRepo.SaveServiceProviderDetail(provider, requestID)
+// GetServiceRequestByID returns a request as a provider deciding whether to quote sees it, with the
+// householder address masked. Requests booked directly with another provider are not shown.
+func (s *ServiceProviderService) GetServiceRequestByID(ctx context.Context, providerID, requestID string) (*model.ServiceRequest, error) {
+	request, err := s.serviceRequestRepo.GetServiceRequestByID(ctx, requestID)
 	if err != nil {
+		return nil, err
+	}
+	if !request.OfferedTo(providerID) {
+		return nil, errors.New("service request not found")
+	}
+	request.MaskForProvider("")
+	return request, nil
+}
+
+// DeclineServiceRequest allows the provider to decline a service_test request. A request booked directly with
+// the provider is not declined but opened to every provider, and the householder is told so.
+func (s *ServiceProviderService) DeclineServiceRequest(ctx context.Context, providerID, requestID string) error {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
 		return err
 	}
+	return retryOnConflict(ctx, func(ctx context.Context) error {
+		// Get the service request
+		request, err := s.serviceRequestRepo.GetServiceRequestByID(ctx, requestID)
+		if err != nil {
+			return err
+		}
+		if !request.OfferedTo(providerID) {
+			return errors.New("service request not found")
+		}
 
-	return nil
+		if request.Status != "Pending" {
+			return fmt.Errorf("service request is not pending")
+		}
+		if request.TargetProviderID != nil {
+			return s.openToAllProviders(ctx, providerID, request)
+		}
+
+		// Decline the service_test request
+		before := *request
+		request.Status = "Declined"
+		return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.serviceRequestRepo.UpdateServiceRequest(ctx, request); err != nil {
+				return err
+			}
+			return audit(WithActor(ctx, providerID), s.auditRepo, "decline", model.EntityServiceRequest, requestID, before, request)
+		})
+	})
 }
-func (s *ServiceProviderService) GetServiceRequestByID(requestID string) (*model.ServiceRequest, error) {
-	return s.serviceRequestRepo.GetServiceRequestByID(requestID)
+
+// openToAllProviders hands a direct booking the provider declined over to the open marketplace
+func (s *ServiceProviderService) openToAllProviders(ctx context.Context, providerID string, request *model.ServiceRequest) error {
+	before := *request
+	request.TargetProviderID = nil
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.serviceRequestRepo.OpenToAllProviders(ctx, request.ID, providerID); err != nil {
+			return err
+		}
+		if request.HouseholderID != nil {
+			message := fmt.Sprintf("The provider you booked directly could not take request %s. It is now open to all providers.", request.ID)
+			if err := notify(ctx, s.notificationRepo, *request.HouseholderID, message); err != nil {
+				return err
+			}
+		}
+		return audit(WithActor(ctx, providerID), s.auditRepo, "decline direct booking", model.EntityServiceRequest, request.ID, before, request)
+	})
 }
 
-// DeclineServiceRequest allows the provider to decline a service_test request
-func (s *ServiceProviderService) DeclineServiceRequest(providerID, requestID string) error {
-	// Get the service request
-	request, err := s.serviceRequestRepo.GetServiceRequestByID(requestID)
-	if err != nil {
+// CompleteServiceRequest marks a request the householder booked with this provider as done, which lets the
+// householder review the provider
+func (s *ServiceProviderService) CompleteServiceRequest(ctx context.Context, providerID, requestID string) error {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
 		return err
 	}
+	return retryOnConflict(ctx, func(ctx context.Context) error {
+		request, err := s.serviceRequestRepo.GetServiceProviderByRequestID(ctx, requestID, providerID)
+		if err != nil {
+			return err
+		}
 
-	if request.Status != "Pending" {
-		return fmt.Errorf("service request is not pending")
-	}
+		if !request.ApproveStatus || !request.ProviderDetails[0].Approve {
+			return fmt.Errorf("only requests approved for you can be completed")
+		}
+		switch request.Status {
+		case "Cancelled":
+			return fmt.Errorf("service request has been cancelled")
+		case model.RequestCompleted:
+			return fmt.Errorf("service request has already been completed")
+		}
 
-	// Decline the service_test request
-	request.Status = "Declined"
-	return s.serviceRequestRepo.UpdateServiceRequest(request)
+		before := *request
+		request.Status = model.RequestCompleted
+		return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.serviceRequestRepo.UpdateServiceRequest(ctx, request); err != nil {
+				return err
+			}
+			return audit(WithActor(ctx, providerID), s.auditRepo, "complete", model.EntityServiceRequest, requestID, before, request)
+		})
+	})
 }
 
 // UpdateAvailability updates the provider's availability status
-func (s *ServiceProviderService) UpdateAvailability(providerID string, availability bool) error {
+func (s *ServiceProviderService) UpdateAvailability(ctx context.Context, providerID string, availability bool) error {
+	if err := s.accountGuard.EnsureActive(ctx, providerID); err != nil {
+		return err
+	}
 	// Get the service_test provider
-	provider, err := s.serviceProviderRepo.GetProviderByID(providerID)
+	provider, err := s.serviceProviderRepo.GetProviderByID(ctx, providerID)
 	if err != nil {
 		return err
 	}
 
 	// Update the availability status
+	before := map[string]bool{"availability": provider.Availability}
 	provider.Availability = availability
-	return s.serviceProviderRepo.UpdateServiceProvider(provider)
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.serviceProviderRepo.UpdateServiceProvider(ctx, provider); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, providerID), s.auditRepo, "update availability", model.EntityProvider, providerID,
+			before, map[string]bool{"availability": availability})
+	})
 }
 
 //// ViewServices returns all services offered by a specific service_test provider
@@ -161,21 +321,21 @@ func (s *ServiceProviderService) UpdateAvailability(providerID string, availabil
 //	return provider.ServicesOffered, nil
 //}
 
-func (s *ServiceProviderService) ViewServices(providerID string) ([]model.Service, error) {
-	providerService, err := s.serviceRepo.GetServiceByProviderID(providerID)
+func (s *ServiceProviderService) ViewServices(ctx context.Context, providerID string) ([]model.Service, error) {
+	providerService, err := s.serviceRepo.GetServiceByProviderID(ctx, providerID)
 	if err != nil {
 		return nil, err
 	}
 
 	return providerService, nil
 }
-func (s *ServiceProviderService) GetServiceByID(serviceID string) (*model.Service, error) {
-	return s.serviceRepo.GetServiceByID(serviceID)
+func (s *ServiceProviderService) GetServiceByID(ctx context.Context, serviceID string) (*model.Service, error) {
+	return s.serviceRepo.GetServiceByID(ctx, serviceID)
 }
 
-func (s *ServiceProviderService) ViewApprovedRequestsByHouseholder(providerID string) ([]model.ServiceRequest, error) {
+func (s *ServiceProviderService) ViewApprovedRequestsByHouseholder(ctx context.Context, providerID string) ([]model.ServiceRequest, error) {
 	// Fetch all service requests related to the provider
-	serviceRequests, err := s.serviceRequestRepo.GetServiceRequestsByProviderID(providerID)
+	serviceRequests, err := s.serviceRequestRepo.GetServiceRequestsByProviderID(ctx, providerID)
 	if err != nil {
 		return nil, fmt.Errorf("could not retrieve service requests: %v", err)
 	}
@@ -196,14 +356,29 @@ func (s *ServiceProviderService) ViewApprovedRequestsByHouseholder(providerID st
 	if len(approvedRequests) == 0 {
 		return nil, errors.New("no approved requests found for this provider")
 	}
+	for i := range approvedRequests {
+		approvedRequests[i].MaskForProvider(providerID)
+	}
 
 	return approvedRequests, nil
 }
 
-func (s *ServiceProviderService) GetReviews(providerID string) ([]model.Review, error) {
-	reviews, err := s.serviceProviderRepo.GetReviewsByProviderID(providerID)
+func (s *ServiceProviderService) GetReviews(ctx context.Context, providerID string) ([]model.Review, error) {
+	reviews, err := s.serviceProviderRepo.GetReviewsByProviderID(ctx, providerID)
 	if err != nil {
 		return nil, err
 	}
 	return reviews, nil
 }
+
+// requireVerified stops providers whose documents have not been approved from trading
+func (s *ServiceProviderService) requireVerified(ctx context.Context, providerID string) error {
+	provider, err := s.serviceProviderRepo.GetProviderByID(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	if !provider.IsVerified() {
+		return model.ErrProviderNotVerified
+	}
+	return nil
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/totp"
+	"strings"
+	"time"
+)
+
+const (
+	// totpIssuer names the account in authenticator apps
+	totpIssuer = "ServiceNest"
+	// totpSkew is how many time steps a code may be early or late, to allow for clock drift
+	totpSkew = 1
+	// recoveryCodeCount is how many recovery codes a user gets at a time
+	recoveryCodeCount = 10
+	// recoveryCodeAlphabet has 32 characters and leaves out i, l, o and 0, which are easily confused
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz123456789"
+)
+
+// TwoFactorService manages TOTP enrolment and checks authentication and recovery codes. Two-factor
+// authentication is optional except for admins, who cannot log in without it.
+type TwoFactorService struct {
+	userRepo      interfaces.UserRepository
+	twoFactorRepo interfaces.TwoFactorRepository
+	accountGuard  interfaces.AccountGuard
+	auditRepo     interfaces.AuditRepository
+	txManager     interfaces.TransactionManager
+}
+
+// NewTwoFactorService initializes a new TwoFactorService
+func NewTwoFactorService(userRepo interfaces.UserRepository, twoFactorRepo interfaces.TwoFactorRepository, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *TwoFactorService {
+	return &TwoFactorService{
+		userRepo:      userRepo,
+		twoFactorRepo: twoFactorRepo,
+		accountGuard:  accountGuard,
+		auditRepo:     auditRepo,
+		txManager:     txManager,
+	}
+}
+
+// IsEnabled reports whether the user has confirmed an authenticator app
+func (s *TwoFactorService) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	twoFactor, err := s.getTwoFactor(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return twoFactor != nil && twoFactor.Enabled, nil
+}
+
+// RecoveryCodesLeft returns how many unused recovery codes the user has
+func (s *TwoFactorService) RecoveryCodesLeft(ctx context.Context, userID string) (int, error) {
+	return s.twoFactorRepo.CountUnusedRecoveryCodes(ctx, userID)
+}
+
+// StartEnrolment generates a new secret for the user. It only takes effect once ConfirmEnrolment is called
+// with a code from the authenticator app.
+func (s *TwoFactorService) StartEnrolment(ctx context.Context, userID string) (*model.TOTPEnrolment, error) {
+	if err := s.accountGuard.EnsureActive(ctx, userID); err != nil {
+		return nil, err
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	enabled, err := s.IsEnabled(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if enabled {
+		return nil, model.ErrTwoFactorEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.twoFactorRepo.SaveSecret(ctx, model.TwoFactor{UserID: userID, Secret: secret, CreatedAt: time.Now()}); err != nil {
+		return nil, err
+	}
+	return &model.TOTPEnrolment{Secret: secret, URI: totp.URI(totpIssuer, user.Email, secret)}, nil
+}
+
+// ConfirmEnrolment enables two-factor authentication once code matches the new secret. It returns the
+// recovery codes, which are shown this one time only.
+func (s *TwoFactorService) ConfirmEnrolment(ctx context.Context, userID, code string) ([]string, error) {
+	if err := s.accountGuard.EnsureActive(ctx, userID); err != nil {
+		return nil, err
+	}
+	twoFactor, err := s.getTwoFactor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor == nil {
+		return nil, errors.New("start the enrolment first")
+	}
+	if twoFactor.Enabled {
+		return nil, model.ErrTwoFactorEnabled
+	}
+	now := time.Now()
+	step, ok := totp.Verify(twoFactor.Secret, code, now, totpSkew)
+	if !ok {
+		return nil, model.ErrInvalidSecondFactor
+	}
+
+	var codes []string
+	err = s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.twoFactorRepo.EnableTwoFactor(ctx, userID, step, now); err != nil {
+			if errors.Is(err, model.ErrConflict) {
+				return model.ErrTwoFactorEnabled
+			}
+			return err
+		}
+		if codes, err = s.replaceRecoveryCodes(ctx, userID, now); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, userID), s.auditRepo, "enable two-factor", model.EntityUser, userID, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Verify checks an authentication code, or a recovery code which is used up by it. Every reason a code
+// is not accepted, including a code that was used before, returns model.ErrInvalidSecondFactor.
+func (s *TwoFactorService) Verify(ctx context.Context, userID, code string) error {
+	twoFactor, err := s.getTwoFactor(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if twoFactor == nil || !twoFactor.Enabled {
+		return model.ErrInvalidSecondFactor
+	}
+
+	code = strings.TrimSpace(code)
+	if isRecoveryCode(code) {
+		err := s.twoFactorRepo.UseRecoveryCode(ctx, userID, hashTokenCode(normalizeRecoveryCode(code)))
+		if err != nil && err.Error() == "recovery code not found" {
+			return model.ErrInvalidSecondFactor
+		}
+		return err
+	}
+
+	step, ok := totp.Verify(twoFactor.Secret, code, time.Now(), totpSkew)
+	if !ok || step <= twoFactor.LastUsedStep {
+		return model.ErrInvalidSecondFactor
+	}
+	if err := s.twoFactorRepo.UseStep(ctx, userID, step); err != nil {
+		if errors.Is(err, model.ErrConflict) {
+			return model.ErrInvalidSecondFactor
+		}
+		return err
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the user after checking a current code
+func (s *TwoFactorService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	if err := s.accountGuard.EnsureActive(ctx, userID); err != nil {
+		return nil, err
+	}
+	if err := s.requireEnabled(ctx, userID); err != nil {
+		return nil, err
+	}
+	var codes []string
+	err := s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Verify(ctx, userID, code); err != nil {
+			return err
+		}
+		var err error
+		if codes, err = s.replaceRecoveryCodes(ctx, userID, time.Now()); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, userID), s.auditRepo, "regenerate recovery codes", model.EntityUser, userID, nil, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// Disable turns two-factor authentication off after checking a current code. Admins cannot turn it off.
+func (s *TwoFactorService) Disable(ctx context.Context, userID, code string) error {
+	if err := s.accountGuard.EnsureActive(ctx, userID); err != nil {
+		return err
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if requiresTwoFactor(user) {
+		return model.ErrTwoFactorMandatory
+	}
+	if err := s.requireEnabled(ctx, userID); err != nil {
+		return err
+	}
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Verify(ctx, userID, code); err != nil {
+			return err
+		}
+		if err := s.twoFactorRepo.DeleteTwoFactor(ctx, userID); err != nil {
+			return err
+		}
+		return audit(WithActor(ctx, userID), s.auditRepo, "disable two-factor", model.EntityUser, userID, nil, nil)
+	})
+}
+
+func (s *TwoFactorService) requireEnabled(ctx context.Context, userID string) error {
+	enabled, err := s.IsEnabled(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !enabled {
+		return model.ErrTwoFactorNotEnabled
+	}
+	return nil
+}
+
+// getTwoFactor returns nil for a user who never started an enrolment
+func (s *TwoFactorService) getTwoFactor(ctx context.Context, userID string) (*model.TwoFactor, error) {
+	twoFactor, err := s.twoFactorRepo.GetTwoFactor(ctx, userID)
+	if err != nil {
+		if err.Error() == "two-factor settings not found" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return twoFactor, nil
+}
+
+func (s *TwoFactorService) replaceRecoveryCodes(ctx context.Context, userID string, now time.Time) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	stored := make([]model.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		code, err := newRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		stored[i] = model.RecoveryCode{ID: GetUniqueID(), UserID: userID, CodeHash: hashTokenCode(normalizeRecoveryCode(code)), CreatedAt: now}
+	}
+	if err := s.twoFactorRepo.ReplaceRecoveryCodes(ctx, userID, stored); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// requiresTwoFactor reports whether the user may not log in without a second factor, which holds for
+// everyone who reaches the admin dashboard
+func requiresTwoFactor(user *model.User) bool {
+	return model.IsStaffRole(user.Role)
+}
+
+// newRecoveryCode returns ten random characters written as two groups of five
+func newRecoveryCode() (string, error) {
+	buf := make([]byte, 10)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = recoveryCodeAlphabet[b&31]
+	}
+	return string(buf[:5]) + "-" + string(buf[5:]), nil
+}
+
+// isRecoveryCode tells recovery codes apart from the six digit codes of authenticator apps
+func isRecoveryCode(code string) bool {
+	return len(normalizeRecoveryCode(code)) == 10
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"serviceNest/interfaces"
+)
+
+// storeUpload writes an uploaded file to the blob store under key and returns its size. A file larger than
+// maxSize bytes or an empty one is removed again and refused; kind names the file in the error, e.g. "photo".
+func storeUpload(ctx context.Context, store interfaces.BlobStore, key string, content io.Reader, maxSize int64, kind string) (int64, error) {
+	// Read one byte past the limit to tell an oversized file from one of exactly the maximum size
+	size, err := store.Put(ctx, key, io.LimitReader(content, maxSize+1))
+	if err != nil {
+		return 0, err
+	}
+	if size > maxSize {
+		discardBlob(ctx, store, key)
+		return 0, fmt.Errorf("%ss may not be larger than %d MB", kind, maxSize>>20)
+	}
+	if size == 0 {
+		discardBlob(ctx, store, key)
+		return 0, fmt.Errorf("the %s is empty", kind)
+	}
+	return size, nil
+}
+
+// discardBlob removes a blob that no record refers to. Failing to do so only leaves an orphaned file
+// behind, so it is logged rather than returned.
+func discardBlob(ctx context.Context, store interfaces.BlobStore, key string) {
+	if err := store.Delete(ctx, key); err != nil {
+		slog.Warn("could not delete unreferenced blob", "key", key, "error", err)
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"serviceNest/interfaces"
@@ -9,21 +10,24 @@ import (
 )
 
 type UserService struct {
-	userRepo interfaces.UserRepository
+	userRepo     interfaces.UserRepository
+	accountGuard interfaces.AccountGuard
+	auditRepo    interfaces.AuditRepository
+	txManager    interfaces.TransactionManager
 }
 
-func NewUserService(userRepo interfaces.UserRepository) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(userRepo interfaces.UserRepository, accountGuard interfaces.AccountGuard, auditRepo interfaces.AuditRepository, txManager interfaces.TransactionManager) *UserService {
+	return &UserService{userRepo: userRepo, accountGuard: accountGuard, auditRepo: auditRepo, txManager: txManager}
 }
 
 // View User
 
-func (s *UserService) ViewProfileByID(userID string) (*model.User, error) {
+func (s *UserService) ViewProfileByID(ctx context.Context, userID string) (*model.User, error) {
 	//if err := s.userRepo.EnsureConnection(); err != nil {
 	//	return nil, err
 	//}
 
-	user, err := s.userRepo.GetUserByID(userID)
+	user, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		return nil, fmt.Errorf("could not find user: %v", err)
 	}
@@ -35,21 +39,29 @@ func (s *UserService) ViewProfileByID(userID string) (*model.User, error) {
 	return user, nil
 }
 
-func (s *UserService) UpdateUser(userID string, newEmail, newPassword, newAddress, newPhone *string) error {
-	user, err := s.userRepo.GetUserByID(userID)
+func (s *UserService) UpdateUser(ctx context.Context, userID string, newEmail, newPassword, newAddress, newPhone *string) error {
+	if err := s.accountGuard.EnsureActive(ctx, userID); err != nil {
+		return err
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("could not find user: %v", err)
 	}
+	before := *user
 
 	// Update email
 	if newEmail != nil {
 		if err := util.ValidateEmail(*newEmail); err != nil {
 			return err
 		}
-		existingUser, err := s.userRepo.GetUserByEmail(*newEmail)
+		existingUser, err := s.userRepo.GetUserByEmail(ctx, *newEmail)
 		if err == nil && existingUser.ID != userID {
 			return errors.New("email already in use by another user")
 		}
+		if *newEmail != user.Email {
+			// The new address has not been proven yet
+			user.EmailVerified = false
+		}
 		user.Email = *newEmail
 	}
 
@@ -74,9 +86,10 @@ func (s *UserService) UpdateUser(userID string, newEmail, newPassword, newAddres
 	}
 
 	// Save the updated user back to the repository_test
-	if err := s.userRepo.UpdateUser(user); err != nil {
-		return fmt.Errorf("could not update user: %v", err)
-	}
-
-	return nil
+	return s.txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.userRepo.UpdateUser(ctx, user); err != nil {
+			return fmt.Errorf("could not update user: %v", err)
+		}
+		return audit(ctx, s.auditRepo, "update profile", model.EntityUser, user.ID, before, user)
+	})
 }
@@ -0,0 +1,71 @@
+package blob_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"serviceNest/blob"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore_PutOpenDelete(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "blobs")
+	store, err := blob.NewLocalStore(root)
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	size, err := store.Put(ctx, "provider-documents/p1/d1.pdf", strings.NewReader("licence"))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), size)
+	assert.FileExists(t, filepath.Join(root, "provider-documents", "p1", "d1.pdf"))
+
+	// Putting the same key again replaces the blob
+	_, err = store.Put(ctx, "provider-documents/p1/d1.pdf", strings.NewReader("renewed licence"))
+	assert.NoError(t, err)
+
+	reader, err := store.Open(ctx, "provider-documents/p1/d1.pdf")
+	assert.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+	assert.Equal(t, "renewed licence", string(content))
+
+	assert.NoError(t, store.Delete(ctx, "provider-documents/p1/d1.pdf"))
+	assert.NoError(t, store.Delete(ctx, "provider-documents/p1/d1.pdf"))
+	_, err = store.Open(ctx, "provider-documents/p1/d1.pdf")
+	assert.EqualError(t, err, "blob not found")
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(filepath.Join(root, "provider-documents", "p1"))
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLocalStore_RejectsKeysOutsideRoot(t *testing.T) {
+	store, err := blob.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	ctx := context.Background()
+
+	for _, key := range []string{"", ".", "../escape.pdf", "a/../../escape.pdf", "/etc/passwd", `a\b.pdf`} {
+		_, err := store.Put(ctx, key, strings.NewReader("x"))
+		assert.Error(t, err, key)
+		_, err = store.Open(ctx, key)
+		assert.Error(t, err, key)
+		assert.Error(t, store.Delete(ctx, key), key)
+	}
+}
+
+func TestLocalStore_CancelledContext(t *testing.T) {
+	store, err := blob.NewLocalStore(t.TempDir())
+	assert.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = store.Put(ctx, "a.pdf", strings.NewReader("x"))
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = store.Open(context.Background(), "a.pdf")
+	assert.EqualError(t, err, "blob not found")
+}
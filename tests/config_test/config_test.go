@@ -0,0 +1,156 @@
+package config_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"serviceNest/config"
+	"testing"
+	"time"
+)
+
+func envFrom(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, args, err := config.Load([]string{"-db-dsn", "root@tcp(localhost:3306)/servicenest"}, envFrom(nil))
+	assert.NoError(t, err)
+	assert.Empty(t, args)
+	assert.Equal(t, 10, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 5*time.Minute, cfg.Database.ConnMaxLifetime.Duration)
+	assert.Equal(t, 5*time.Second, cfg.Database.QueryTimeout.Duration)
+	assert.Equal(t, "mysql", cfg.Storage.Backend)
+	assert.Equal(t, "service_category.json", cfg.CategoryFile)
+	assert.Equal(t, "info", cfg.LogLevel)
+}
+
+func TestLoad_YAMLFile(t *testing.T) {
+	path := writeFile(t, "servicenest.yaml", `
+database:
+  dsn: "file-dsn"
+  max_open_conns: 20
+  max_idle_conns: 4
+  conn_max_lifetime: 90s
+category_file: categories.json
+log_level: debug
+`)
+
+	cfg, _, err := config.Load([]string{"-config", path}, envFrom(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "file-dsn", cfg.Database.DSN)
+	assert.Equal(t, 20, cfg.Database.MaxOpenConns)
+	assert.Equal(t, 4, cfg.Database.MaxIdleConns)
+	assert.Equal(t, 90*time.Second, cfg.Database.ConnMaxLifetime.Duration)
+	assert.Equal(t, "categories.json", cfg.CategoryFile)
+	assert.Equal(t, "debug", cfg.LogLevel)
+}
+
+func TestLoad_JSONFileFromEnv(t *testing.T) {
+	path := writeFile(t, "servicenest.json", `{"database": {"dsn": "json-dsn", "conn_max_lifetime": "1m"}}`)
+
+	cfg, _, err := config.Load(nil, envFrom(map[string]string{"SERVICENEST_CONFIG": path}))
+	assert.NoError(t, err)
+	assert.Equal(t, "json-dsn", cfg.Database.DSN)
+	assert.Equal(t, time.Minute, cfg.Database.ConnMaxLifetime.Duration)
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	path := writeFile(t, "servicenest.yaml", "database:\n  dsn: file-dsn\nlog_level: debug\ncategory_file: file.json\n")
+	env := envFrom(map[string]string{
+		"SERVICENEST_DB_DSN":            "env-dsn",
+		"SERVICENEST_LOG_LEVEL":         "warn",
+		"SERVICENEST_DB_MAX_OPEN_CONNS": "30",
+		"SERVICENEST_DB_QUERY_TIMEOUT":  "2s",
+	})
+
+	cfg, args, err := config.Load([]string{"-config", path, "-log-level", "error", "-db-query-timeout", "3s", "migrate", "up"}, env)
+	assert.NoError(t, err)
+	assert.Equal(t, "env-dsn", cfg.Database.DSN)
+	assert.Equal(t, "file.json", cfg.CategoryFile)
+	assert.Equal(t, 30, cfg.Database.MaxOpenConns)
+	assert.Equal(t, "error", cfg.LogLevel)
+	assert.Equal(t, 3*time.Second, cfg.Database.QueryTimeout.Duration)
+	assert.Equal(t, []string{"migrate", "up"}, args)
+}
+
+func TestLoad_InvalidEnvValue(t *testing.T) {
+	env := envFrom(map[string]string{
+		"SERVICENEST_DB_DSN":            "env-dsn",
+		"SERVICENEST_DB_MAX_OPEN_CONNS": "many",
+	})
+
+	_, _, err := config.Load(nil, env)
+	assert.EqualError(t, err, "SERVICENEST_DB_MAX_OPEN_CONNS must be an integer")
+}
+
+func TestLoad_ValidationErrors(t *testing.T) {
+	_, _, err := config.Load([]string{"-storage-backend", "mongo", "-log-level", "verbose"}, envFrom(nil))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "database.dsn is required")
+	assert.Contains(t, err.Error(), `storage.backend "mongo" is not supported`)
+	assert.Contains(t, err.Error(), `log_level "verbose" is not supported`)
+}
+
+func TestLoad_SMTPRequiresHost(t *testing.T) {
+	args := []string{"-db-dsn", "dsn", "-notification-enabled", "-notification-channel", "smtp"}
+
+	_, _, err := config.Load(args, envFrom(nil))
+	assert.EqualError(t, err, "invalid configuration: notification.smtp_host and notification.from are required for the smtp channel")
+}
+
+func TestLoad_UnsupportedFileType(t *testing.T) {
+	path := writeFile(t, "servicenest.toml", "dsn = 'x'")
+
+	_, _, err := config.Load([]string{"-config", path}, envFrom(nil))
+	assert.EqualError(t, err, `unsupported config file type ".toml"`)
+}
+
+func TestLoad_AuthSettings(t *testing.T) {
+	env := envFrom(map[string]string{
+		"SERVICENEST_DB_DSN":               "env-dsn",
+		"SERVICENEST_AUTH_RESET_TOKEN_TTL": "15m",
+	})
+
+	cfg, _, err := config.Load([]string{"-auth-require-verified-email"}, env)
+	assert.NoError(t, err)
+	assert.True(t, cfg.Auth.RequireVerifiedEmail)
+	assert.Equal(t, 24*time.Hour, cfg.Auth.VerificationTokenTTL.Duration)
+	assert.Equal(t, 15*time.Minute, cfg.Auth.ResetTokenTTL.Duration)
+	assert.Equal(t, 10, cfg.Auth.MaxFailedLogins)
+	assert.Equal(t, 15*time.Minute, cfg.Auth.LockoutDuration.Duration)
+}
+
+func TestLoad_InvalidLockout(t *testing.T) {
+	env := envFrom(map[string]string{
+		"SERVICENEST_DB_DSN":                 "env-dsn",
+		"SERVICENEST_AUTH_MAX_FAILED_LOGINS": "-1",
+	})
+
+	_, _, err := config.Load(nil, env)
+	assert.EqualError(t, err, "invalid configuration: auth.max_failed_logins and auth.max_failed_logins_per_source must not be negative")
+}
+
+func TestLoad_RatingSettings(t *testing.T) {
+	path := writeFile(t, "servicenest.yaml", "database:\n  dsn: file-dsn\nrating:\n  prior_weight: 10\n  half_life: 720h\n")
+
+	cfg, _, err := config.Load([]string{"-config", path}, envFrom(map[string]string{"SERVICENEST_RATING_PRIOR_MEAN": "4"}))
+	assert.NoError(t, err)
+	assert.Equal(t, 4.0, cfg.Rating.PriorMean)
+	assert.Equal(t, 10.0, cfg.Rating.PriorWeight)
+	assert.Equal(t, 720*time.Hour, cfg.Rating.HalfLife.Duration)
+
+	_, _, err = config.Load(nil, envFrom(map[string]string{"SERVICENEST_DB_DSN": "env-dsn", "SERVICENEST_RATING_PRIOR_MEAN": "7"}))
+	assert.EqualError(t, err, "invalid configuration: rating.prior_mean must be between 1 and 5")
+}
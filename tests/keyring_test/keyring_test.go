@@ -0,0 +1,181 @@
+package keyring_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"serviceNest/keyring"
+	"strings"
+	"testing"
+)
+
+func openKeyFile(t *testing.T) (*keyring.LocalKeyFile, string) {
+	path := filepath.Join(t.TempDir(), "keys", "keys.json")
+	file, err := keyring.CreateLocalKeyFile(path)
+	require.NoError(t, err)
+	return file, path
+}
+
+func TestEnvelope_EncryptDecrypt(t *testing.T) {
+	file, _ := openKeyFile(t)
+	envelope := keyring.NewEnvelope(file)
+	ctx := context.Background()
+
+	first, err := envelope.Encrypt(ctx, "12 Baker Street")
+	assert.NoError(t, err)
+	second, err := envelope.Encrypt(ctx, "12 Baker Street")
+	assert.NoError(t, err)
+	assert.NotContains(t, first, "Baker")
+	// Every value gets its own data key and nonce
+	assert.NotEqual(t, first, second)
+	assert.Equal(t, "k1", envelope.KeyID(first))
+
+	plaintext, err := envelope.Decrypt(ctx, first)
+	assert.NoError(t, err)
+	assert.Equal(t, "12 Baker Street", plaintext)
+
+	empty, err := envelope.Encrypt(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "", empty)
+}
+
+func TestEnvelope_DecryptLegacyPlaintext(t *testing.T) {
+	file, _ := openKeyFile(t)
+	envelope := keyring.NewEnvelope(file)
+
+	plaintext, err := envelope.Decrypt(context.Background(), "9876543210")
+	assert.NoError(t, err)
+	assert.Equal(t, "9876543210", plaintext)
+	assert.Equal(t, "", envelope.KeyID("9876543210"))
+}
+
+func TestEnvelope_RewrapAfterRotation(t *testing.T) {
+	file, path := openKeyFile(t)
+	envelope := keyring.NewEnvelope(file)
+	ctx := context.Background()
+
+	stored, err := envelope.Encrypt(ctx, "9876543210")
+	require.NoError(t, err)
+	unchanged, changed, err := envelope.Rewrap(ctx, stored)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, stored, unchanged)
+
+	id, err := file.Rotate()
+	require.NoError(t, err)
+	assert.Equal(t, "k2", id)
+
+	rewrapped, changed, err := envelope.Rewrap(ctx, stored)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "k2", envelope.KeyID(rewrapped))
+	// Only the data key is encrypted again, the sealed value is kept
+	assert.Equal(t, stored[strings.LastIndex(stored, ":"):], rewrapped[strings.LastIndex(rewrapped, ":"):])
+
+	// Once nothing uses the old key it can be retired, the value stays readable
+	require.NoError(t, file.Retire("k1"))
+	reopened, err := keyring.OpenLocalKeyFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k2"}, reopened.KeyIDs())
+	plaintext, err := keyring.NewEnvelope(reopened).Decrypt(ctx, rewrapped)
+	assert.NoError(t, err)
+	assert.Equal(t, "9876543210", plaintext)
+
+	_, err = keyring.NewEnvelope(reopened).Decrypt(ctx, stored)
+	assert.EqualError(t, err, `encryption key "k1" is not in the key file`)
+
+	legacy, changed, err := envelope.Rewrap(ctx, "legacy address")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "k2", envelope.KeyID(legacy))
+}
+
+func TestEnvelope_RejectsTamperedValues(t *testing.T) {
+	file, _ := openKeyFile(t)
+	envelope := keyring.NewEnvelope(file)
+	ctx := context.Background()
+
+	stored, err := envelope.Encrypt(ctx, "9876543210")
+	require.NoError(t, err)
+	_, err = file.Rotate()
+	require.NoError(t, err)
+
+	// Claiming the value belongs to another key fails, the key ID is authenticated with the data key
+	relabelled := strings.Replace(stored, "enc1:k1:", "enc1:k2:", 1)
+	_, err = envelope.Decrypt(ctx, relabelled)
+	assert.ErrorContains(t, err, "could not unwrap data key")
+
+	tampered := stored[:len(stored)-2] + "AA"
+	if tampered == stored {
+		tampered = stored[:len(stored)-2] + "BB"
+	}
+	_, err = envelope.Decrypt(ctx, tampered)
+	assert.ErrorContains(t, err, "could not decrypt value")
+
+	_, err = envelope.Decrypt(ctx, "enc1:k1:not-base64")
+	assert.ErrorIs(t, err, keyring.ErrMalformed)
+}
+
+func TestEnvelope_BlindIndex(t *testing.T) {
+	file, _ := openKeyFile(t)
+	envelope := keyring.NewEnvelope(file)
+	ctx := context.Background()
+
+	index, err := envelope.BlindIndex(ctx, "9876543210")
+	assert.NoError(t, err)
+	assert.Len(t, index, 32)
+	again, err := envelope.BlindIndex(ctx, "9876543210")
+	assert.NoError(t, err)
+	assert.Equal(t, index, again)
+	other, err := envelope.BlindIndex(ctx, "9876543211")
+	assert.NoError(t, err)
+	assert.NotEqual(t, index, other)
+
+	// Rotating the encryption keys leaves the index key alone, stored indexes stay valid
+	_, err = file.Rotate()
+	require.NoError(t, err)
+	afterRotation, err := envelope.BlindIndex(ctx, "9876543210")
+	assert.NoError(t, err)
+	assert.Equal(t, index, afterRotation)
+}
+
+func TestLocalKeyFile(t *testing.T) {
+	file, path := openKeyFile(t)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	assert.EqualError(t, file.Retire("k1"), "key k1 is the current key and cannot be retired")
+	assert.EqualError(t, file.Retire("k7"), "key k7 is not in the key file")
+
+	_, err = file.Rotate()
+	require.NoError(t, err)
+	reopened, err := keyring.OpenLocalKeyFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k1", "k2"}, reopened.KeyIDs())
+	current, _, err := reopened.CurrentKey(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "k2", current)
+
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0o600))
+	_, err = keyring.OpenLocalKeyFile(path)
+	assert.ErrorContains(t, err, "is damaged")
+}
+
+func TestLocalKeyFile_IsNeverCreatedImplicitly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+
+	_, err := keyring.OpenLocalKeyFile(path)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	_, statErr := os.Stat(path)
+	assert.ErrorIs(t, statErr, fs.ErrNotExist)
+
+	_, err = keyring.CreateLocalKeyFile(path)
+	require.NoError(t, err)
+	_, err = keyring.CreateLocalKeyFile(path)
+	assert.EqualError(t, err, "key file "+path+" already exists")
+}
@@ -0,0 +1,179 @@
+package migration_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/migration"
+	"testing"
+	"testing/fstest"
+)
+
+func testFiles() fstest.MapFS {
+	return fstest.MapFS{
+		"0001_create_users.up.sql":     {Data: []byte("CREATE TABLE users (id VARCHAR(64));")},
+		"0001_create_users.down.sql":   {Data: []byte("DROP TABLE users;")},
+		"0002_create_reviews.up.sql":   {Data: []byte("CREATE TABLE reviews (id VARCHAR(64));\nCREATE INDEX idx ON reviews (id);")},
+		"0002_create_reviews.down.sql": {Data: []byte("DROP TABLE reviews;")},
+	}
+}
+
+func expectApplied(mock sqlmock.Sqlmock, versions ...int) {
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").WillReturnResult(sqlmock.NewResult(0, 0))
+	rows := sqlmock.NewRows([]string{"version", "applied_at"})
+	for _, version := range versions {
+		rows.AddRow(version, []byte("2024-09-01 10:00:00"))
+	}
+	mock.ExpectQuery("SELECT version, applied_at FROM schema_migrations").WillReturnRows(rows)
+}
+
+func TestMigrationsAreEmbeddedInOrder(t *testing.T) {
+	migrator, err := migration.NewMigrator(nil)
+	assert.NoError(t, err)
+
+	migrations := migrator.Migrations()
+	assert.NotEmpty(t, migrations)
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version)
+		assert.NotEmpty(t, m.Up)
+		assert.NotEmpty(t, m.Down)
+	}
+}
+
+func TestMigratorUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migration.NewMigratorFromFS(db, testFiles())
+	assert.NoError(t, err)
+
+	expectApplied(mock, 1)
+	mock.ExpectExec("CREATE TABLE reviews").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("CREATE INDEX idx ON reviews").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(2, "create_reviews", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	applied, err := migrator.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.Equal(t, 2, applied[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorUp_StopsOnFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migration.NewMigratorFromFS(db, testFiles())
+	assert.NoError(t, err)
+
+	expectApplied(mock)
+	mock.ExpectExec("CREATE TABLE users").WillReturnError(errors.New("syntax error"))
+
+	applied, err := migrator.Up(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "0001_create_users")
+	assert.Empty(t, applied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorUp_KeepsSemicolonsInStringsAndComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	script := "-- Seed the notes; the second one is quoted\n" +
+		"INSERT INTO notes (body) VALUES ('a;b'), (\"it's; fine\"), ('don''t; stop');\n" +
+		"/* kept; for later */ UPDATE notes SET body = 'c\\';d' WHERE `odd;column` = 1;\n" +
+		"# nothing left to run;\n"
+	migrator, err := migration.NewMigratorFromFS(db, fstest.MapFS{
+		"0001_seed_notes.up.sql":   {Data: []byte(script)},
+		"0001_seed_notes.down.sql": {Data: []byte("DELETE FROM notes;")},
+	})
+	assert.NoError(t, err)
+
+	expectApplied(mock)
+	mock.ExpectExec(regexp.QuoteMeta("-- Seed the notes; the second one is quoted\n" +
+		"INSERT INTO notes (body) VALUES ('a;b'), (\"it's; fine\"), ('don''t; stop')")).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("/* kept; for later */ UPDATE notes SET body = 'c\\';d' WHERE `odd;column` = 1")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(1, "seed_notes", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	applied, err := migrator.Up(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, applied, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorDown(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migration.NewMigratorFromFS(db, testFiles())
+	assert.NoError(t, err)
+
+	expectApplied(mock, 1, 2)
+	mock.ExpectExec("DROP TABLE reviews").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	rolledBack, err := migrator.Down(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rolledBack.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMigratorStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migration.NewMigratorFromFS(db, testFiles())
+	assert.NoError(t, err)
+
+	expectApplied(mock, 1)
+
+	statuses, err := migrator.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Applied)
+	assert.Equal(t, 2024, statuses[0].AppliedAt.Year())
+	assert.False(t, statuses[1].Applied)
+}
+
+func TestEnsureUpToDate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := migration.NewMigratorFromFS(db, testFiles())
+	assert.NoError(t, err)
+
+	expectApplied(mock, 1)
+	err = migrator.EnsureUpToDate(context.Background())
+	assert.True(t, errors.Is(err, migration.ErrSchemaOutdated))
+	assert.Contains(t, err.Error(), "0002_create_reviews")
+
+	expectApplied(mock, 1, 2)
+	assert.NoError(t, migrator.EnsureUpToDate(context.Background()))
+
+	expectApplied(mock, 1, 2, 3)
+	assert.EqualError(t, migrator.EnsureUpToDate(context.Background()), "database schema version 0003 is newer than this build")
+}
+
+func TestNewMigratorFromFS_RejectsIncompleteMigration(t *testing.T) {
+	files := fstest.MapFS{
+		"0001_create_users.up.sql": {Data: []byte("CREATE TABLE users (id VARCHAR(64));")},
+	}
+
+	_, err := migration.NewMigratorFromFS(nil, files)
+	assert.EqualError(t, err, "migration 0001_create_users needs both an up and a down script")
+}
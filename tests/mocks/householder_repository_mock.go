@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 	model "serviceNest/model"
 
@@ -35,30 +36,30 @@ func (m *MockHouseholderRepository) EXPECT() *MockHouseholderRepositoryMockRecor
 }
 
 // GetHouseholderByID mocks base method.
-func (m *MockHouseholderRepository) GetHouseholderByID(id string) (*model.Householder, error) {
+func (m *MockHouseholderRepository) GetHouseholderByID(ctx context.Context, id string) (*model.Householder, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetHouseholderByID", id)
+	ret := m.ctrl.Call(m, "GetHouseholderByID", ctx, id)
 	ret0, _ := ret[0].(*model.Householder)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetHouseholderByID indicates an expected call of GetHouseholderByID.
-func (mr *MockHouseholderRepositoryMockRecorder) GetHouseholderByID(id interface{}) *gomock.Call {
+func (mr *MockHouseholderRepositoryMockRecorder) GetHouseholderByID(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholderByID", reflect.TypeOf((*MockHouseholderRepository)(nil).GetHouseholderByID), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHouseholderByID", reflect.TypeOf((*MockHouseholderRepository)(nil).GetHouseholderByID), ctx, id)
 }
 
 // SaveHouseholder mocks base method.
-func (m *MockHouseholderRepository) SaveHouseholder(householder *model.Householder) error {
+func (m *MockHouseholderRepository) SaveHouseholder(ctx context.Context, householder *model.Householder) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveHouseholder", householder)
+	ret := m.ctrl.Call(m, "SaveHouseholder", ctx, householder)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveHouseholder indicates an expected call of SaveHouseholder.
-func (mr *MockHouseholderRepositoryMockRecorder) SaveHouseholder(householder interface{}) *gomock.Call {
+func (mr *MockHouseholderRepositoryMockRecorder) SaveHouseholder(ctx, householder interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveHouseholder", reflect.TypeOf((*MockHouseholderRepository)(nil).SaveHouseholder), householder)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveHouseholder", reflect.TypeOf((*MockHouseholderRepository)(nil).SaveHouseholder), ctx, householder)
 }
@@ -5,8 +5,10 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 	model "serviceNest/model"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,175 +37,306 @@ func (m *MockServiceProviderRepository) EXPECT() *MockServiceProviderRepositoryM
 }
 
 // AddReview mocks base method.
-func (m *MockServiceProviderRepository) AddReview(review model.Review) error {
+func (m *MockServiceProviderRepository) AddReview(ctx context.Context, review model.Review) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddReview", review)
+	ret := m.ctrl.Call(m, "AddReview", ctx, review)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // AddReview indicates an expected call of AddReview.
-func (mr *MockServiceProviderRepositoryMockRecorder) AddReview(review interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) AddReview(ctx, review interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReview", reflect.TypeOf((*MockServiceProviderRepository)(nil).AddReview), review)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReview", reflect.TypeOf((*MockServiceProviderRepository)(nil).AddReview), ctx, review)
+}
+
+// ClearReviewComments mocks base method.
+func (m *MockServiceProviderRepository) ClearReviewComments(ctx context.Context, userID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearReviewComments", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearReviewComments indicates an expected call of ClearReviewComments.
+func (mr *MockServiceProviderRepositoryMockRecorder) ClearReviewComments(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearReviewComments", reflect.TypeOf((*MockServiceProviderRepository)(nil).ClearReviewComments), ctx, userID)
+}
+
+// GetHiddenReviews mocks base method.
+func (m *MockServiceProviderRepository) GetHiddenReviews(ctx context.Context) ([]model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHiddenReviews", ctx)
+	ret0, _ := ret[0].([]model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHiddenReviews indicates an expected call of GetHiddenReviews.
+func (mr *MockServiceProviderRepositoryMockRecorder) GetHiddenReviews(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHiddenReviews", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetHiddenReviews), ctx)
 }
 
 // GetProviderByID mocks base method.
-func (m *MockServiceProviderRepository) GetProviderByID(providerID string) (*model.ServiceProvider, error) {
+func (m *MockServiceProviderRepository) GetProviderByID(ctx context.Context, providerID string) (*model.ServiceProvider, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProviderByID", providerID)
+	ret := m.ctrl.Call(m, "GetProviderByID", ctx, providerID)
 	ret0, _ := ret[0].(*model.ServiceProvider)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetProviderByID indicates an expected call of GetProviderByID.
-func (mr *MockServiceProviderRepositoryMockRecorder) GetProviderByID(providerID interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) GetProviderByID(ctx, providerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProviderByID", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetProviderByID), providerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProviderByID", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetProviderByID), ctx, providerID)
 }
 
 // GetProviderByServiceID mocks base method.
-func (m *MockServiceProviderRepository) GetProviderByServiceID(serviceID string) (*model.ServiceProvider, error) {
+func (m *MockServiceProviderRepository) GetProviderByServiceID(ctx context.Context, serviceID string) (*model.ServiceProvider, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProviderByServiceID", serviceID)
+	ret := m.ctrl.Call(m, "GetProviderByServiceID", ctx, serviceID)
 	ret0, _ := ret[0].(*model.ServiceProvider)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetProviderByServiceID indicates an expected call of GetProviderByServiceID.
-func (mr *MockServiceProviderRepositoryMockRecorder) GetProviderByServiceID(serviceID interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) GetProviderByServiceID(ctx, serviceID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProviderByServiceID", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetProviderByServiceID), serviceID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProviderByServiceID", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetProviderByServiceID), ctx, serviceID)
 }
 
 // GetProviderDetailByID mocks base method.
-func (m *MockServiceProviderRepository) GetProviderDetailByID(providerID string) (*model.ServiceProviderDetails, error) {
+func (m *MockServiceProviderRepository) GetProviderDetailByID(ctx context.Context, providerID string) (*model.ServiceProviderDetails, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProviderDetailByID", providerID)
+	ret := m.ctrl.Call(m, "GetProviderDetailByID", ctx, providerID)
 	ret0, _ := ret[0].(*model.ServiceProviderDetails)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetProviderDetailByID indicates an expected call of GetProviderDetailByID.
-func (mr *MockServiceProviderRepositoryMockRecorder) GetProviderDetailByID(providerID interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) GetProviderDetailByID(ctx, providerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProviderDetailByID", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetProviderDetailByID), providerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProviderDetailByID", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetProviderDetailByID), ctx, providerID)
 }
 
 // GetProvidersByServiceType mocks base method.
-func (m *MockServiceProviderRepository) GetProvidersByServiceType(serviceType string) ([]model.ServiceProvider, error) {
+func (m *MockServiceProviderRepository) GetProvidersByServiceType(ctx context.Context, serviceType string) ([]model.ServiceProvider, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProvidersByServiceType", serviceType)
+	ret := m.ctrl.Call(m, "GetProvidersByServiceType", ctx, serviceType)
 	ret0, _ := ret[0].([]model.ServiceProvider)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetProvidersByServiceType indicates an expected call of GetProvidersByServiceType.
-func (mr *MockServiceProviderRepositoryMockRecorder) GetProvidersByServiceType(serviceType interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) GetProvidersByServiceType(ctx, serviceType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvidersByServiceType", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetProvidersByServiceType), ctx, serviceType)
+}
+
+// GetProvidersByVerificationStatus mocks base method.
+func (m *MockServiceProviderRepository) GetProvidersByVerificationStatus(ctx context.Context, status string) ([]model.ServiceProvider, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProvidersByVerificationStatus", ctx, status)
+	ret0, _ := ret[0].([]model.ServiceProvider)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProvidersByVerificationStatus indicates an expected call of GetProvidersByVerificationStatus.
+func (mr *MockServiceProviderRepositoryMockRecorder) GetProvidersByVerificationStatus(ctx, status interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvidersByServiceType", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetProvidersByServiceType), serviceType)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProvidersByVerificationStatus", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetProvidersByVerificationStatus), ctx, status)
+}
+
+// GetReviewByID mocks base method.
+func (m *MockServiceProviderRepository) GetReviewByID(ctx context.Context, reviewID string) (*model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewByID", ctx, reviewID)
+	ret0, _ := ret[0].(*model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewByID indicates an expected call of GetReviewByID.
+func (mr *MockServiceProviderRepositoryMockRecorder) GetReviewByID(ctx, reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewByID", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetReviewByID), ctx, reviewID)
+}
+
+// GetReviewsByHouseholderID mocks base method.
+func (m *MockServiceProviderRepository) GetReviewsByHouseholderID(ctx context.Context, householderID string) ([]model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewsByHouseholderID", ctx, householderID)
+	ret0, _ := ret[0].([]model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewsByHouseholderID indicates an expected call of GetReviewsByHouseholderID.
+func (mr *MockServiceProviderRepositoryMockRecorder) GetReviewsByHouseholderID(ctx, householderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByHouseholderID", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetReviewsByHouseholderID), ctx, householderID)
 }
 
 // GetReviewsByProviderID mocks base method.
-func (m *MockServiceProviderRepository) GetReviewsByProviderID(providerID string) ([]model.Review, error) {
+func (m *MockServiceProviderRepository) GetReviewsByProviderID(ctx context.Context, providerID string) ([]model.Review, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetReviewsByProviderID", providerID)
+	ret := m.ctrl.Call(m, "GetReviewsByProviderID", ctx, providerID)
 	ret0, _ := ret[0].([]model.Review)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetReviewsByProviderID indicates an expected call of GetReviewsByProviderID.
-func (mr *MockServiceProviderRepositoryMockRecorder) GetReviewsByProviderID(providerID interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) GetReviewsByProviderID(ctx, providerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByProviderID", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetReviewsByProviderID), providerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewsByProviderID", reflect.TypeOf((*MockServiceProviderRepository)(nil).GetReviewsByProviderID), ctx, providerID)
 }
 
 // IsProviderApproved mocks base method.
-func (m *MockServiceProviderRepository) IsProviderApproved(providerID string) (bool, error) {
+func (m *MockServiceProviderRepository) IsProviderApproved(ctx context.Context, providerID string) (bool, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IsProviderApproved", providerID)
+	ret := m.ctrl.Call(m, "IsProviderApproved", ctx, providerID)
 	ret0, _ := ret[0].(bool)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // IsProviderApproved indicates an expected call of IsProviderApproved.
-func (mr *MockServiceProviderRepositoryMockRecorder) IsProviderApproved(providerID interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) IsProviderApproved(ctx, providerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsProviderApproved", reflect.TypeOf((*MockServiceProviderRepository)(nil).IsProviderApproved), providerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsProviderApproved", reflect.TypeOf((*MockServiceProviderRepository)(nil).IsProviderApproved), ctx, providerID)
+}
+
+// LockReviewByID mocks base method.
+func (m *MockServiceProviderRepository) LockReviewByID(ctx context.Context, reviewID string) (*model.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockReviewByID", ctx, reviewID)
+	ret0, _ := ret[0].(*model.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockReviewByID indicates an expected call of LockReviewByID.
+func (mr *MockServiceProviderRepositoryMockRecorder) LockReviewByID(ctx, reviewID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockReviewByID", reflect.TypeOf((*MockServiceProviderRepository)(nil).LockReviewByID), ctx, reviewID)
+}
+
+// SaveReviewReply mocks base method.
+func (m *MockServiceProviderRepository) SaveReviewReply(ctx context.Context, reviewID, providerID, reply string, replyDate time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveReviewReply", ctx, reviewID, providerID, reply, replyDate)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveReviewReply indicates an expected call of SaveReviewReply.
+func (mr *MockServiceProviderRepositoryMockRecorder) SaveReviewReply(ctx, reviewID, providerID, reply, replyDate interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveReviewReply", reflect.TypeOf((*MockServiceProviderRepository)(nil).SaveReviewReply), ctx, reviewID, providerID, reply, replyDate)
 }
 
 // SaveServiceProvider mocks base method.
-func (m *MockServiceProviderRepository) SaveServiceProvider(provider model.ServiceProvider) error {
+func (m *MockServiceProviderRepository) SaveServiceProvider(ctx context.Context, provider model.ServiceProvider) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveServiceProvider", provider)
+	ret := m.ctrl.Call(m, "SaveServiceProvider", ctx, provider)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveServiceProvider indicates an expected call of SaveServiceProvider.
-func (mr *MockServiceProviderRepositoryMockRecorder) SaveServiceProvider(provider interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) SaveServiceProvider(ctx, provider interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveServiceProvider", reflect.TypeOf((*MockServiceProviderRepository)(nil).SaveServiceProvider), provider)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveServiceProvider", reflect.TypeOf((*MockServiceProviderRepository)(nil).SaveServiceProvider), ctx, provider)
 }
 
 // SaveServiceProviderDetail mocks base method.
-func (m *MockServiceProviderRepository) SaveServiceProviderDetail(provider *model.ServiceProviderDetails, requestID string) error {
+func (m *MockServiceProviderRepository) SaveServiceProviderDetail(ctx context.Context, provider *model.ServiceProviderDetails, requestID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveServiceProviderDetail", provider, requestID)
+	ret := m.ctrl.Call(m, "SaveServiceProviderDetail", ctx, provider, requestID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveServiceProviderDetail indicates an expected call of SaveServiceProviderDetail.
-func (mr *MockServiceProviderRepositoryMockRecorder) SaveServiceProviderDetail(provider, requestID interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) SaveServiceProviderDetail(ctx, provider, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveServiceProviderDetail", reflect.TypeOf((*MockServiceProviderRepository)(nil).SaveServiceProviderDetail), ctx, provider, requestID)
+}
+
+// UpdateReview mocks base method.
+func (m *MockServiceProviderRepository) UpdateReview(ctx context.Context, review model.Review) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateReview", ctx, review)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateReview indicates an expected call of UpdateReview.
+func (mr *MockServiceProviderRepositoryMockRecorder) UpdateReview(ctx, review interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveServiceProviderDetail", reflect.TypeOf((*MockServiceProviderRepository)(nil).SaveServiceProviderDetail), provider, requestID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockServiceProviderRepository)(nil).UpdateReview), ctx, review)
 }
 
-// UpdateProviderRating mocks base method.
-func (m *MockServiceProviderRepository) UpdateProviderRating(providerID string) error {
+// UpdateReviewModeration mocks base method.
+func (m *MockServiceProviderRepository) UpdateReviewModeration(ctx context.Context, review model.Review) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateProviderRating", providerID)
+	ret := m.ctrl.Call(m, "UpdateReviewModeration", ctx, review)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateProviderRating indicates an expected call of UpdateProviderRating.
-func (mr *MockServiceProviderRepositoryMockRecorder) UpdateProviderRating(providerID interface{}) *gomock.Call {
+// UpdateReviewModeration indicates an expected call of UpdateReviewModeration.
+func (mr *MockServiceProviderRepositoryMockRecorder) UpdateReviewModeration(ctx, review interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProviderRating", reflect.TypeOf((*MockServiceProviderRepository)(nil).UpdateProviderRating), providerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReviewModeration", reflect.TypeOf((*MockServiceProviderRepository)(nil).UpdateReviewModeration), ctx, review)
 }
 
 // UpdateServiceProvider mocks base method.
-func (m *MockServiceProviderRepository) UpdateServiceProvider(provider *model.ServiceProvider) error {
+func (m *MockServiceProviderRepository) UpdateServiceProvider(ctx context.Context, provider *model.ServiceProvider) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateServiceProvider", provider)
+	ret := m.ctrl.Call(m, "UpdateServiceProvider", ctx, provider)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateServiceProvider indicates an expected call of UpdateServiceProvider.
-func (mr *MockServiceProviderRepositoryMockRecorder) UpdateServiceProvider(provider interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) UpdateServiceProvider(ctx, provider interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceProvider", reflect.TypeOf((*MockServiceProviderRepository)(nil).UpdateServiceProvider), provider)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceProvider", reflect.TypeOf((*MockServiceProviderRepository)(nil).UpdateServiceProvider), ctx, provider)
 }
 
 // UpdateServiceProviderDetailByRequestID mocks base method.
-func (m *MockServiceProviderRepository) UpdateServiceProviderDetailByRequestID(provider *model.ServiceProviderDetails, requestID string) error {
+func (m *MockServiceProviderRepository) UpdateServiceProviderDetailByRequestID(ctx context.Context, provider *model.ServiceProviderDetails, requestID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateServiceProviderDetailByRequestID", provider, requestID)
+	ret := m.ctrl.Call(m, "UpdateServiceProviderDetailByRequestID", ctx, provider, requestID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateServiceProviderDetailByRequestID indicates an expected call of UpdateServiceProviderDetailByRequestID.
-func (mr *MockServiceProviderRepositoryMockRecorder) UpdateServiceProviderDetailByRequestID(provider, requestID interface{}) *gomock.Call {
+func (mr *MockServiceProviderRepositoryMockRecorder) UpdateServiceProviderDetailByRequestID(ctx, provider, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceProviderDetailByRequestID", reflect.TypeOf((*MockServiceProviderRepository)(nil).UpdateServiceProviderDetailByRequestID), ctx, provider, requestID)
+}
+
+// UpdateVerificationStatus mocks base method.
+func (m *MockServiceProviderRepository) UpdateVerificationStatus(ctx context.Context, providerID, status, note, reviewedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateVerificationStatus", ctx, providerID, status, note, reviewedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateVerificationStatus indicates an expected call of UpdateVerificationStatus.
+func (mr *MockServiceProviderRepositoryMockRecorder) UpdateVerificationStatus(ctx, providerID, status, note, reviewedBy interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceProviderDetailByRequestID", reflect.TypeOf((*MockServiceProviderRepository)(nil).UpdateServiceProviderDetailByRequestID), provider, requestID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateVerificationStatus", reflect.TypeOf((*MockServiceProviderRepository)(nil).UpdateVerificationStatus), ctx, providerID, status, note, reviewedBy)
 }
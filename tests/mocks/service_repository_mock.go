@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 	model "serviceNest/model"
 
@@ -35,131 +36,145 @@ func (m *MockServiceRepository) EXPECT() *MockServiceRepositoryMockRecorder {
 }
 
 // GetAllServices mocks base method.
-func (m *MockServiceRepository) GetAllServices() ([]model.Service, error) {
+func (m *MockServiceRepository) GetAllServices(ctx context.Context, opts model.QueryOptions) ([]model.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllServices")
+	ret := m.ctrl.Call(m, "GetAllServices", ctx, opts)
 	ret0, _ := ret[0].([]model.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllServices indicates an expected call of GetAllServices.
-func (mr *MockServiceRepositoryMockRecorder) GetAllServices() *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) GetAllServices(ctx, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllServices", reflect.TypeOf((*MockServiceRepository)(nil).GetAllServices))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllServices", reflect.TypeOf((*MockServiceRepository)(nil).GetAllServices), ctx, opts)
 }
 
 // GetServiceByID mocks base method.
-func (m *MockServiceRepository) GetServiceByID(serviceID string) (*model.Service, error) {
+func (m *MockServiceRepository) GetServiceByID(ctx context.Context, serviceID string) (*model.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServiceByID", serviceID)
+	ret := m.ctrl.Call(m, "GetServiceByID", ctx, serviceID)
 	ret0, _ := ret[0].(*model.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServiceByID indicates an expected call of GetServiceByID.
-func (mr *MockServiceRepositoryMockRecorder) GetServiceByID(serviceID interface{}) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) GetServiceByID(ctx, serviceID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceByID", reflect.TypeOf((*MockServiceRepository)(nil).GetServiceByID), serviceID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceByID", reflect.TypeOf((*MockServiceRepository)(nil).GetServiceByID), ctx, serviceID)
 }
 
 // GetServiceByName mocks base method.
-func (m *MockServiceRepository) GetServiceByName(serviceName string) (*model.Service, error) {
+func (m *MockServiceRepository) GetServiceByName(ctx context.Context, serviceName string) (*model.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServiceByName", serviceName)
+	ret := m.ctrl.Call(m, "GetServiceByName", ctx, serviceName)
 	ret0, _ := ret[0].(*model.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServiceByName indicates an expected call of GetServiceByName.
-func (mr *MockServiceRepositoryMockRecorder) GetServiceByName(serviceName interface{}) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) GetServiceByName(ctx, serviceName interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceByName", reflect.TypeOf((*MockServiceRepository)(nil).GetServiceByName), serviceName)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceByName", reflect.TypeOf((*MockServiceRepository)(nil).GetServiceByName), ctx, serviceName)
 }
 
 // GetServiceByProviderID mocks base method.
-func (m *MockServiceRepository) GetServiceByProviderID(providerID string) ([]model.Service, error) {
+func (m *MockServiceRepository) GetServiceByProviderID(ctx context.Context, providerID string) ([]model.Service, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServiceByProviderID", providerID)
+	ret := m.ctrl.Call(m, "GetServiceByProviderID", ctx, providerID)
 	ret0, _ := ret[0].([]model.Service)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServiceByProviderID indicates an expected call of GetServiceByProviderID.
-func (mr *MockServiceRepositoryMockRecorder) GetServiceByProviderID(providerID interface{}) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) GetServiceByProviderID(ctx, providerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceByProviderID", reflect.TypeOf((*MockServiceRepository)(nil).GetServiceByProviderID), providerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceByProviderID", reflect.TypeOf((*MockServiceRepository)(nil).GetServiceByProviderID), ctx, providerID)
 }
 
 // RemoveService mocks base method.
-func (m *MockServiceRepository) RemoveService(serviceID string) error {
+func (m *MockServiceRepository) RemoveService(ctx context.Context, serviceID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveService", serviceID)
+	ret := m.ctrl.Call(m, "RemoveService", ctx, serviceID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveService indicates an expected call of RemoveService.
-func (mr *MockServiceRepositoryMockRecorder) RemoveService(serviceID interface{}) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) RemoveService(ctx, serviceID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveService", reflect.TypeOf((*MockServiceRepository)(nil).RemoveService), serviceID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveService", reflect.TypeOf((*MockServiceRepository)(nil).RemoveService), ctx, serviceID)
 }
 
 // RemoveServiceByProviderID mocks base method.
-func (m *MockServiceRepository) RemoveServiceByProviderID(providerID, serviceID string) error {
+func (m *MockServiceRepository) RemoveServiceByProviderID(ctx context.Context, providerID, serviceID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RemoveServiceByProviderID", providerID, serviceID)
+	ret := m.ctrl.Call(m, "RemoveServiceByProviderID", ctx, providerID, serviceID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RemoveServiceByProviderID indicates an expected call of RemoveServiceByProviderID.
-func (mr *MockServiceRepositoryMockRecorder) RemoveServiceByProviderID(providerID, serviceID interface{}) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) RemoveServiceByProviderID(ctx, providerID, serviceID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveServiceByProviderID", reflect.TypeOf((*MockServiceRepository)(nil).RemoveServiceByProviderID), providerID, serviceID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveServiceByProviderID", reflect.TypeOf((*MockServiceRepository)(nil).RemoveServiceByProviderID), ctx, providerID, serviceID)
+}
+
+// RenameCategory mocks base method.
+func (m *MockServiceRepository) RenameCategory(ctx context.Context, oldName, newName string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameCategory", ctx, oldName, newName)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameCategory indicates an expected call of RenameCategory.
+func (mr *MockServiceRepositoryMockRecorder) RenameCategory(ctx, oldName, newName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameCategory", reflect.TypeOf((*MockServiceRepository)(nil).RenameCategory), ctx, oldName, newName)
 }
 
 // SaveAllServices mocks base method.
-func (m *MockServiceRepository) SaveAllServices(services []model.Service) error {
+func (m *MockServiceRepository) SaveAllServices(ctx context.Context, services []model.Service) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveAllServices", services)
+	ret := m.ctrl.Call(m, "SaveAllServices", ctx, services)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveAllServices indicates an expected call of SaveAllServices.
-func (mr *MockServiceRepositoryMockRecorder) SaveAllServices(services interface{}) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) SaveAllServices(ctx, services interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAllServices", reflect.TypeOf((*MockServiceRepository)(nil).SaveAllServices), services)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAllServices", reflect.TypeOf((*MockServiceRepository)(nil).SaveAllServices), ctx, services)
 }
 
 // SaveService mocks base method.
-func (m *MockServiceRepository) SaveService(service model.Service) error {
+func (m *MockServiceRepository) SaveService(ctx context.Context, service model.Service) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveService", service)
+	ret := m.ctrl.Call(m, "SaveService", ctx, service)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveService indicates an expected call of SaveService.
-func (mr *MockServiceRepositoryMockRecorder) SaveService(service interface{}) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) SaveService(ctx, service interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveService", reflect.TypeOf((*MockServiceRepository)(nil).SaveService), service)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveService", reflect.TypeOf((*MockServiceRepository)(nil).SaveService), ctx, service)
 }
 
 // UpdateService mocks base method.
-func (m *MockServiceRepository) UpdateService(providerID string, updatedService model.Service) error {
+func (m *MockServiceRepository) UpdateService(ctx context.Context, providerID string, updatedService model.Service) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateService", providerID, updatedService)
+	ret := m.ctrl.Call(m, "UpdateService", ctx, providerID, updatedService)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateService indicates an expected call of UpdateService.
-func (mr *MockServiceRepositoryMockRecorder) UpdateService(providerID, updatedService interface{}) *gomock.Call {
+func (mr *MockServiceRepositoryMockRecorder) UpdateService(ctx, providerID, updatedService interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockServiceRepository)(nil).UpdateService), providerID, updatedService)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateService", reflect.TypeOf((*MockServiceRepository)(nil).UpdateService), ctx, providerID, updatedService)
 }
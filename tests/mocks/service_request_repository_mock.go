@@ -5,6 +5,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 	model "serviceNest/model"
 
@@ -34,119 +35,192 @@ func (m *MockServiceRequestRepository) EXPECT() *MockServiceRequestRepositoryMoc
 	return m.recorder
 }
 
+// AnonymiseHouseholder mocks base method.
+func (m *MockServiceRequestRepository) AnonymiseHouseholder(ctx context.Context, householderID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymiseHouseholder", ctx, householderID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymiseHouseholder indicates an expected call of AnonymiseHouseholder.
+func (mr *MockServiceRequestRepositoryMockRecorder) AnonymiseHouseholder(ctx, householderID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymiseHouseholder", reflect.TypeOf((*MockServiceRequestRepository)(nil).AnonymiseHouseholder), ctx, householderID, name)
+}
+
+// AnonymiseProviderDetails mocks base method.
+func (m *MockServiceRequestRepository) AnonymiseProviderDetails(ctx context.Context, providerID, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymiseProviderDetails", ctx, providerID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AnonymiseProviderDetails indicates an expected call of AnonymiseProviderDetails.
+func (mr *MockServiceRequestRepositoryMockRecorder) AnonymiseProviderDetails(ctx, providerID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymiseProviderDetails", reflect.TypeOf((*MockServiceRequestRepository)(nil).AnonymiseProviderDetails), ctx, providerID, name)
+}
+
+// CountCompletedJobs mocks base method.
+func (m *MockServiceRequestRepository) CountCompletedJobs(ctx context.Context, providerID string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCompletedJobs", ctx, providerID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCompletedJobs indicates an expected call of CountCompletedJobs.
+func (mr *MockServiceRequestRepositoryMockRecorder) CountCompletedJobs(ctx, providerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCompletedJobs", reflect.TypeOf((*MockServiceRequestRepository)(nil).CountCompletedJobs), ctx, providerID)
+}
+
 // GetAllServiceRequests mocks base method.
-func (m *MockServiceRequestRepository) GetAllServiceRequests() ([]model.ServiceRequest, error) {
+func (m *MockServiceRequestRepository) GetAllServiceRequests(ctx context.Context, opts model.QueryOptions) ([]model.ServiceRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllServiceRequests")
+	ret := m.ctrl.Call(m, "GetAllServiceRequests", ctx, opts)
 	ret0, _ := ret[0].([]model.ServiceRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllServiceRequests indicates an expected call of GetAllServiceRequests.
-func (mr *MockServiceRequestRepositoryMockRecorder) GetAllServiceRequests() *gomock.Call {
+func (mr *MockServiceRequestRepositoryMockRecorder) GetAllServiceRequests(ctx, opts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllServiceRequests", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetAllServiceRequests), ctx, opts)
+}
+
+// GetApprovedProvider mocks base method.
+func (m *MockServiceRequestRepository) GetApprovedProvider(ctx context.Context, requestID string) (*model.ServiceProviderDetails, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetApprovedProvider", ctx, requestID)
+	ret0, _ := ret[0].(*model.ServiceProviderDetails)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetApprovedProvider indicates an expected call of GetApprovedProvider.
+func (mr *MockServiceRequestRepositoryMockRecorder) GetApprovedProvider(ctx, requestID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllServiceRequests", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetAllServiceRequests))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetApprovedProvider", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetApprovedProvider), ctx, requestID)
 }
 
 // GetServiceProviderByRequestID mocks base method.
-func (m *MockServiceRequestRepository) GetServiceProviderByRequestID(requestID, providerID string) (*model.ServiceRequest, error) {
+func (m *MockServiceRequestRepository) GetServiceProviderByRequestID(ctx context.Context, requestID, providerID string) (*model.ServiceRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServiceProviderByRequestID", requestID, providerID)
+	ret := m.ctrl.Call(m, "GetServiceProviderByRequestID", ctx, requestID, providerID)
 	ret0, _ := ret[0].(*model.ServiceRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServiceProviderByRequestID indicates an expected call of GetServiceProviderByRequestID.
-func (mr *MockServiceRequestRepositoryMockRecorder) GetServiceProviderByRequestID(requestID, providerID interface{}) *gomock.Call {
+func (mr *MockServiceRequestRepositoryMockRecorder) GetServiceProviderByRequestID(ctx, requestID, providerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceProviderByRequestID", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetServiceProviderByRequestID), requestID, providerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceProviderByRequestID", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetServiceProviderByRequestID), ctx, requestID, providerID)
 }
 
 // GetServiceRequestByID mocks base method.
-func (m *MockServiceRequestRepository) GetServiceRequestByID(requestID string) (*model.ServiceRequest, error) {
+func (m *MockServiceRequestRepository) GetServiceRequestByID(ctx context.Context, requestID string) (*model.ServiceRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServiceRequestByID", requestID)
+	ret := m.ctrl.Call(m, "GetServiceRequestByID", ctx, requestID)
 	ret0, _ := ret[0].(*model.ServiceRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServiceRequestByID indicates an expected call of GetServiceRequestByID.
-func (mr *MockServiceRequestRepositoryMockRecorder) GetServiceRequestByID(requestID interface{}) *gomock.Call {
+func (mr *MockServiceRequestRepositoryMockRecorder) GetServiceRequestByID(ctx, requestID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceRequestByID", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetServiceRequestByID), requestID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceRequestByID", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetServiceRequestByID), ctx, requestID)
 }
 
 // GetServiceRequestsByHouseholderID mocks base method.
-func (m *MockServiceRequestRepository) GetServiceRequestsByHouseholderID(householderID string) ([]model.ServiceRequest, error) {
+func (m *MockServiceRequestRepository) GetServiceRequestsByHouseholderID(ctx context.Context, householderID string, opts model.QueryOptions) ([]model.ServiceRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServiceRequestsByHouseholderID", householderID)
+	ret := m.ctrl.Call(m, "GetServiceRequestsByHouseholderID", ctx, householderID, opts)
 	ret0, _ := ret[0].([]model.ServiceRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServiceRequestsByHouseholderID indicates an expected call of GetServiceRequestsByHouseholderID.
-func (mr *MockServiceRequestRepositoryMockRecorder) GetServiceRequestsByHouseholderID(householderID interface{}) *gomock.Call {
+func (mr *MockServiceRequestRepositoryMockRecorder) GetServiceRequestsByHouseholderID(ctx, householderID, opts interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceRequestsByHouseholderID", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetServiceRequestsByHouseholderID), householderID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceRequestsByHouseholderID", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetServiceRequestsByHouseholderID), ctx, householderID, opts)
 }
 
 // GetServiceRequestsByProviderID mocks base method.
-func (m *MockServiceRequestRepository) GetServiceRequestsByProviderID(providerID string) ([]model.ServiceRequest, error) {
+func (m *MockServiceRequestRepository) GetServiceRequestsByProviderID(ctx context.Context, providerID string) ([]model.ServiceRequest, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetServiceRequestsByProviderID", providerID)
+	ret := m.ctrl.Call(m, "GetServiceRequestsByProviderID", ctx, providerID)
 	ret0, _ := ret[0].([]model.ServiceRequest)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetServiceRequestsByProviderID indicates an expected call of GetServiceRequestsByProviderID.
-func (mr *MockServiceRequestRepositoryMockRecorder) GetServiceRequestsByProviderID(providerID interface{}) *gomock.Call {
+func (mr *MockServiceRequestRepositoryMockRecorder) GetServiceRequestsByProviderID(ctx, providerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceRequestsByProviderID", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetServiceRequestsByProviderID), providerID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetServiceRequestsByProviderID", reflect.TypeOf((*MockServiceRequestRepository)(nil).GetServiceRequestsByProviderID), ctx, providerID)
 }
 
-// SaveAllServiceRequests mocks base method.
-func (m *MockServiceRequestRepository) SaveAllServiceRequests(serviceRequests []model.ServiceRequest) error {
+// OpenToAllProviders mocks base method.
+func (m *MockServiceRequestRepository) OpenToAllProviders(ctx context.Context, requestID, providerID string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveAllServiceRequests", serviceRequests)
+	ret := m.ctrl.Call(m, "OpenToAllProviders", ctx, requestID, providerID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// SaveAllServiceRequests indicates an expected call of SaveAllServiceRequests.
-func (mr *MockServiceRequestRepositoryMockRecorder) SaveAllServiceRequests(serviceRequests interface{}) *gomock.Call {
+// OpenToAllProviders indicates an expected call of OpenToAllProviders.
+func (mr *MockServiceRequestRepositoryMockRecorder) OpenToAllProviders(ctx, requestID, providerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenToAllProviders", reflect.TypeOf((*MockServiceRequestRepository)(nil).OpenToAllProviders), ctx, requestID, providerID)
+}
+
+// ReleaseDirectBookings mocks base method.
+func (m *MockServiceRequestRepository) ReleaseDirectBookings(ctx context.Context, providerID string) ([]model.ServiceRequest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseDirectBookings", ctx, providerID)
+	ret0, _ := ret[0].([]model.ServiceRequest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseDirectBookings indicates an expected call of ReleaseDirectBookings.
+func (mr *MockServiceRequestRepositoryMockRecorder) ReleaseDirectBookings(ctx, providerID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveAllServiceRequests", reflect.TypeOf((*MockServiceRequestRepository)(nil).SaveAllServiceRequests), serviceRequests)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseDirectBookings", reflect.TypeOf((*MockServiceRequestRepository)(nil).ReleaseDirectBookings), ctx, providerID)
 }
 
 // SaveServiceRequest mocks base method.
-func (m *MockServiceRequestRepository) SaveServiceRequest(request model.ServiceRequest) error {
+func (m *MockServiceRequestRepository) SaveServiceRequest(ctx context.Context, request model.ServiceRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SaveServiceRequest", request)
+	ret := m.ctrl.Call(m, "SaveServiceRequest", ctx, request)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SaveServiceRequest indicates an expected call of SaveServiceRequest.
-func (mr *MockServiceRequestRepositoryMockRecorder) SaveServiceRequest(request interface{}) *gomock.Call {
+func (mr *MockServiceRequestRepositoryMockRecorder) SaveServiceRequest(ctx, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveServiceRequest", reflect.TypeOf((*MockServiceRequestRepository)(nil).SaveServiceRequest), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveServiceRequest", reflect.TypeOf((*MockServiceRequestRepository)(nil).SaveServiceRequest), ctx, request)
 }
 
 // UpdateServiceRequest mocks base method.
-func (m *MockServiceRequestRepository) UpdateServiceRequest(updatedRequest *model.ServiceRequest) error {
+func (m *MockServiceRequestRepository) UpdateServiceRequest(ctx context.Context, updatedRequest *model.ServiceRequest) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateServiceRequest", updatedRequest)
+	ret := m.ctrl.Call(m, "UpdateServiceRequest", ctx, updatedRequest)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateServiceRequest indicates an expected call of UpdateServiceRequest.
-func (mr *MockServiceRequestRepositoryMockRecorder) UpdateServiceRequest(updatedRequest interface{}) *gomock.Call {
+func (mr *MockServiceRequestRepositoryMockRecorder) UpdateServiceRequest(ctx, updatedRequest interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceRequest", reflect.TypeOf((*MockServiceRequestRepository)(nil).UpdateServiceRequest), updatedRequest)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateServiceRequest", reflect.TypeOf((*MockServiceRequestRepository)(nil).UpdateServiceRequest), ctx, updatedRequest)
 }
@@ -0,0 +1,53 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+func TestSaveStatusChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAccountStatusRepository(db)
+	change := model.AccountStatusChange{ID: "h1", UserID: "u1", FromStatus: model.AccountActive, ToStatus: model.AccountBanned,
+		Reason: "fraud", ChangedBy: "admin1", ChangedAt: time.Now()}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO account_status_history")).
+		WithArgs("h1", "u1", "Active", "Banned", "fraud", nil, "admin1", change.ChangedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.SaveStatusChange(context.Background(), change))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetStatusHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAccountStatusRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "user_id", "from_status", "to_status", "reason", "expires_at", "changed_by", "changed_at"}).
+		AddRow("h2", "u1", "Suspended", "Active", "suspension expired", nil, "system", []byte("2024-06-01 00:05:00")).
+		AddRow("h1", "u1", "Active", "Suspended", "spam", []byte("2024-06-01 00:00:00"), "admin1", []byte("2024-05-01 09:00:00"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM account_status_history WHERE user_id = ? ORDER BY changed_at DESC, id")).
+		WithArgs("u1").
+		WillReturnRows(rows)
+
+	history, err := repo.GetStatusHistory(context.Background(), "u1")
+
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Nil(t, history[0].ExpiresAt)
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), *history[1].ExpiresAt)
+	assert.Equal(t, time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC), history[1].ChangedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,97 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+var auditColumnNames = []string{"seq", "id", "actor_id", "action", "entity_type", "entity_id", "before_data", "after_data",
+	"created_at", "prev_hash", "hash"}
+
+func TestAppendEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuditRepository(db)
+	entry := &model.AuditEntry{ID: "e1", ActorID: "a1", Action: "delete", EntityType: model.EntityService, EntityID: "s1",
+		Before: `{"name":"Plumbing"}`, CreatedAt: time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC)}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT last_seq, last_hash FROM audit_chain_head WHERE id = 1 FOR UPDATE")).
+		WillReturnRows(sqlmock.NewRows([]string{"last_seq", "last_hash"}).AddRow(int64(4), "hash4"))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO audit_log")).
+		WithArgs(int64(5), "e1", "a1", "delete", model.EntityService, "s1", `{"name":"Plumbing"}`, nil, entry.CreatedAt, "hash4", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE audit_chain_head SET last_seq = ?, last_hash = ? WHERE id = 1")).
+		WithArgs(int64(5), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = repo.AppendEntry(context.Background(), entry)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), entry.Seq)
+	assert.Equal(t, "hash4", entry.PrevHash)
+	assert.Equal(t, entry.ComputeHash(), entry.Hash)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAppendEntry_ChainUnavailable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuditRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("FROM audit_chain_head")).WillReturnError(assert.AnError)
+	mock.ExpectRollback()
+
+	err = repo.AppendEntry(context.Background(), &model.AuditEntry{ID: "e1"})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuditRepository(db)
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows(auditColumnNames).
+		AddRow(int64(2), "e2", "a1", "update", model.EntityCategory, "c1", `{"name":"Old"}`, `{"name":"New"}`,
+			[]byte("2024-06-02 10:00:00"), "hash1", "hash2")
+	mock.ExpectQuery(regexp.QuoteMeta("FROM audit_log WHERE actor_id = ? AND entity_type = ? AND created_at >= ? ORDER BY seq DESC LIMIT ? OFFSET ?")).
+		WithArgs("a1", model.EntityCategory, from, 20, 0).
+		WillReturnRows(rows)
+
+	entries, err := repo.GetEntries(context.Background(), model.AuditFilter{ActorID: "a1", EntityType: model.EntityCategory, From: from, Limit: 20})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, `{"name":"New"}`, entries[0].After)
+	assert.Equal(t, time.Date(2024, 6, 2, 10, 0, 0, 0, time.UTC), entries[0].CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetEntriesAfter(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuditRepository(db)
+	rows := sqlmock.NewRows(auditColumnNames).
+		AddRow(int64(1), "e1", "system", "create", model.EntityUser, "u1", nil, `{"id":"u1"}`, []byte("2024-06-01 10:00:00"), "", "hash1")
+	mock.ExpectQuery(regexp.QuoteMeta("FROM audit_log WHERE seq > ? ORDER BY seq LIMIT ?")).WithArgs(int64(0), 500).WillReturnRows(rows)
+
+	entries, err := repo.GetEntriesAfter(context.Background(), 0, 500)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Empty(t, entries[0].Before)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
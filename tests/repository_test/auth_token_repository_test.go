@@ -0,0 +1,96 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+func TestSaveToken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuthTokenRepository(db)
+	now := time.Now()
+	token := model.AuthToken{ID: "t1", UserID: "u1", Purpose: model.TokenPasswordReset, TokenHash: "hash",
+		ExpiresAt: now.Add(time.Hour), CreatedAt: now}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO auth_tokens")).
+		WithArgs("t1", "u1", "PasswordReset", "hash", token.ExpiresAt, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.SaveToken(context.Background(), token))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetTokenByHash(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuthTokenRepository(db)
+	query := regexp.QuoteMeta("FROM auth_tokens WHERE token_hash = ?")
+	rows := sqlmock.NewRows([]string{"id", "user_id", "purpose", "token_hash", "expires_at", "used_at", "created_at"}).
+		AddRow("t1", "u1", "EmailVerification", "hash", []byte("2024-06-02 10:00:00"), []byte("2024-06-01 11:00:00"), []byte("2024-06-01 10:00:00"))
+	mock.ExpectQuery(query).WithArgs("hash").WillReturnRows(rows)
+	mock.ExpectQuery(query).WithArgs("other").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	token, err := repo.GetTokenByHash(context.Background(), "hash")
+	assert.NoError(t, err)
+	assert.Equal(t, model.TokenEmailVerification, token.Purpose)
+	assert.Equal(t, time.Date(2024, 6, 2, 10, 0, 0, 0, time.UTC), token.ExpiresAt)
+	assert.Equal(t, time.Date(2024, 6, 1, 11, 0, 0, 0, time.UTC), *token.UsedAt)
+
+	_, err = repo.GetTokenByHash(context.Background(), "other")
+	assert.EqualError(t, err, "token not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkTokenUsed(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuthTokenRepository(db)
+	query := regexp.QuoteMeta("UPDATE auth_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL")
+	mock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), "t1").WillReturnResult(sqlmock.NewResult(0, 1))
+	// Redeemed by someone else in the meantime
+	mock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), "t1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.MarkTokenUsed(context.Background(), "t1"))
+	assert.ErrorIs(t, repo.MarkTokenUsed(context.Background(), "t1"), model.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestInvalidateTokens(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuthTokenRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE auth_tokens SET used_at = ? WHERE user_id = ? AND purpose = ? AND used_at IS NULL")).
+		WithArgs(sqlmock.AnyArg(), "u1", "PasswordReset").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, repo.InvalidateTokens(context.Background(), "u1", model.TokenPasswordReset))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteTokens(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewAuthTokenRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM auth_tokens WHERE user_id = ?")).WithArgs("u1").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	assert.NoError(t, repo.DeleteTokens(context.Background(), "u1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,109 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+)
+
+var categoryColumns = []string{"id", "name", "description", "parent_id"}
+
+func TestSaveCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCategoryRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO categories (id, name, description, parent_id) VALUES (?, ?, ?, ?)")).
+		WithArgs("c1", "electrician", "Electrical work", nil).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO categories (id, name, description, parent_id) VALUES (?, ?, ?, ?)")).
+		WithArgs("c2", "wiring", "Wiring repairs", "c1").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.SaveCategory(context.Background(), model.Category{ID: "c1", Name: "electrician", Description: "Electrical work"}))
+	assert.NoError(t, repo.SaveCategory(context.Background(), model.Category{ID: "c2", Name: "wiring", Description: "Wiring repairs", ParentID: "c1"}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCategoryByName(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCategoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, description, parent_id FROM categories WHERE name = ?")).
+		WithArgs("wiring").
+		WillReturnRows(sqlmock.NewRows(categoryColumns).AddRow("c2", "wiring", "Wiring repairs", "c1"))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, description, parent_id FROM categories WHERE name = ?")).
+		WithArgs("unknown").
+		WillReturnRows(sqlmock.NewRows(categoryColumns))
+
+	category, err := repo.GetCategoryByName(context.Background(), "wiring")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Category{ID: "c2", Name: "wiring", Description: "Wiring repairs", ParentID: "c1"}, category)
+
+	_, err = repo.GetCategoryByName(context.Background(), "unknown")
+	assert.EqualError(t, err, "category not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllCategories(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCategoryRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, description, parent_id FROM categories ORDER BY name")).
+		WillReturnRows(sqlmock.NewRows(categoryColumns).
+			AddRow("c1", "electrician", "Electrical work", nil).
+			AddRow("c2", "wiring", "Wiring repairs", "c1"))
+
+	categories, err := repo.GetAllCategories(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Category{
+		{ID: "c1", Name: "electrician", Description: "Electrical work"},
+		{ID: "c2", Name: "wiring", Description: "Wiring repairs", ParentID: "c1"},
+	}, categories)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCategoryRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE categories SET name = ?, description = ?, parent_id = ? WHERE id = ?")).
+		WithArgs("wiring", "Wiring repairs", "c1", "c2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.UpdateCategory(context.Background(), model.Category{ID: "c2", Name: "wiring", Description: "Wiring repairs", ParentID: "c1"})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveCategory_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCategoryRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM categories WHERE id = ?")).
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.EqualError(t, repo.RemoveCategory(context.Background(), "missing"), "category not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
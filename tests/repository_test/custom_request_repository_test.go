@@ -0,0 +1,125 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+var customRequestRowColumns = []string{"id", "householder_id", "householder_name", "householder_address", "service_name", "requested_time",
+	"scheduled_time", "status", "category_id", "resolution_note", "resolved_by", "resolved_at"}
+
+func TestSaveCustomRequest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCustomRequestRepository(db)
+	now := time.Now()
+	request := model.CustomRequest{ID: "cr1", HouseholderID: "h1", HouseholderName: "John", HouseholderAddress: "Main St",
+		ServiceName: "Pool Cleaning", RequestedTime: now, ScheduledTime: now.Add(time.Hour), Status: model.CustomRequestOpen}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO custom_requests")).
+		WithArgs("cr1", "h1", "John", "Main St", "Pool Cleaning", request.RequestedTime, request.ScheduledTime, "Open").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.SaveCustomRequest(context.Background(), request))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCustomRequestsByStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCustomRequestRepository(db)
+
+	rows := sqlmock.NewRows(customRequestRowColumns).
+		AddRow("cr1", "h1", "John", "Main St", "Pool Cleaning", []byte("2024-05-01 10:00:00"), []byte("2024-05-03 09:00:00"), "Open", nil, nil, nil, nil).
+		AddRow("cr2", "h2", "Jane", "High St", "Tiling", []byte("2024-05-02 10:00:00"), []byte("2024-05-04 09:00:00"), "Open", nil, nil, nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM custom_requests WHERE status = ? ORDER BY requested_time, id")).
+		WithArgs("Open").
+		WillReturnRows(rows)
+
+	requests, err := repo.GetCustomRequestsByStatus(context.Background(), model.CustomRequestOpen)
+
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+	assert.Equal(t, "Pool Cleaning", requests[0].ServiceName)
+	assert.Equal(t, time.Date(2024, 5, 3, 9, 0, 0, 0, time.UTC), requests[0].ScheduledTime)
+	assert.Nil(t, requests[0].ResolvedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetCustomRequestByID_Resolved(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCustomRequestRepository(db)
+
+	rows := sqlmock.NewRows(customRequestRowColumns).
+		AddRow("cr1", "h1", "John", "Main St", "Pool Cleaning", []byte("2024-05-01 10:00:00"), []byte("2024-05-03 09:00:00"),
+			"Mapped", "c9", "mapped to category cleaning", "admin1", []byte("2024-05-02 08:00:00"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM custom_requests WHERE id = ?")).WithArgs("cr1").WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM custom_requests WHERE id = ?")).WithArgs("missing").WillReturnRows(sqlmock.NewRows(customRequestRowColumns))
+
+	request, err := repo.GetCustomRequestByID(context.Background(), "cr1")
+	assert.NoError(t, err)
+	assert.Equal(t, "c9", request.CategoryID)
+	assert.Equal(t, "admin1", request.ResolvedBy)
+	assert.Equal(t, time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC), *request.ResolvedAt)
+
+	_, err = repo.GetCustomRequestByID(context.Background(), "missing")
+	assert.EqualError(t, err, "custom request not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestResolveCustomRequest_AlreadyResolved(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewCustomRequestRepository(db)
+	resolvedAt := time.Now()
+	request := model.CustomRequest{ID: "cr1", Status: model.CustomRequestRejected, ResolutionNote: "duplicate", ResolvedBy: "admin1", ResolvedAt: &resolvedAt}
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE custom_requests SET status = ?, category_id = ?, resolution_note = ?, resolved_by = ?, resolved_at = ? WHERE id = ? AND status = ?")).
+		WithArgs("Rejected", nil, "duplicate", "admin1", &resolvedAt, "cr1", "Open").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.ResolveCustomRequest(context.Background(), request)
+
+	assert.True(t, errors.Is(err, model.ErrConflict))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNotificationRepository_Inbox(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewNotificationRepository(db)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, user_id, message, created_at, is_read FROM notifications WHERE user_id = ? AND is_read = FALSE ORDER BY created_at DESC, id")).
+		WithArgs("h1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "message", "created_at", "is_read"}).
+			AddRow("n1", "h1", "Your request was accepted", []byte("2024-05-02 08:00:00"), false))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE notifications SET is_read = TRUE WHERE user_id = ? AND is_read = FALSE")).
+		WithArgs("h1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	notifications, err := repo.GetNotificationsByUserID(context.Background(), "h1", true)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Notification{{ID: "n1", UserID: "h1", Message: "Your request was accepted",
+		CreatedAt: time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC)}}, notifications)
+
+	assert.NoError(t, repo.MarkNotificationsRead(context.Background(), "h1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,55 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+func TestFavouriteRepository_AddAndGetFavourites(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewFavouriteRepository(db)
+	addedAt := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT IGNORE INTO favourite_providers (householder_id, provider_id, added_at) VALUES (?, ?, ?)")).
+		WithArgs("h1", "p1", addedAt).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE f.householder_id = ?")).WithArgs("h1").
+		WillReturnRows(sqlmock.NewRows([]string{"householder_id", "provider_id", "name", "availability", "added_at"}).
+			AddRow("h1", "p1", "Ravi", true, []byte("2026-10-01 09:00:00")))
+
+	assert.NoError(t, repo.AddFavourite(context.Background(), model.FavouriteProvider{HouseholderID: "h1", ProviderID: "p1", AddedAt: addedAt}))
+	favourites, err := repo.GetFavourites(context.Background(), "h1")
+	assert.NoError(t, err)
+	if assert.Len(t, favourites, 1) {
+		assert.Equal(t, "Ravi", favourites[0].ProviderName)
+		assert.True(t, favourites[0].Available)
+		assert.Equal(t, 2026, favourites[0].AddedAt.Year())
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFavouriteRepository_Remove(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewFavouriteRepository(db)
+	query := regexp.QuoteMeta("DELETE FROM favourite_providers WHERE householder_id = ? AND provider_id = ?")
+	mock.ExpectExec(query).WithArgs("h1", "p1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs("h1", "p1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM favourite_providers WHERE householder_id = ? OR provider_id = ?")).
+		WithArgs("p1", "p1").WillReturnResult(sqlmock.NewResult(0, 3))
+
+	assert.NoError(t, repo.RemoveFavourite(context.Background(), "h1", "p1"))
+	assert.EqualError(t, repo.RemoveFavourite(context.Background(), "h1", "p1"), "favourite not found")
+	assert.NoError(t, repo.DeleteFavouritesByUserID(context.Background(), "p1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,177 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"path/filepath"
+	"regexp"
+	"serviceNest/keyring"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"strings"
+	"testing"
+)
+
+// useFieldCipher turns on encryption with a fresh key file for the duration of the test
+func useFieldCipher(t *testing.T) (*keyring.LocalKeyFile, *keyring.Envelope) {
+	file, err := keyring.CreateLocalKeyFile(filepath.Join(t.TempDir(), "keys.json"))
+	require.NoError(t, err)
+	envelope := keyring.NewEnvelope(file)
+	repository.SetFieldCipher(envelope)
+	t.Cleanup(func() { repository.SetFieldCipher(nil) })
+	return file, envelope
+}
+
+// capturedArg matches any string argument and remembers it
+type capturedArg struct {
+	value *string
+}
+
+func (c capturedArg) Match(v driver.Value) bool {
+	s, ok := v.(string)
+	*c.value = s
+	return ok
+}
+
+func TestSaveUser_EncryptsAddressAndContact(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	_, envelope := useFieldCipher(t)
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	var address, contact string
+	index, err := envelope.BlindIndex(ctx, "9876543210")
+	require.NoError(t, err)
+	mock.ExpectExec("INSERT INTO users").
+		WithArgs("u1", "Jane", "jane@example.com", "hash", "Householder", capturedArg{&address}, capturedArg{&contact}, index, 1.5, 2.5, false).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	user := &model.User{ID: "u1", Name: "Jane", Email: "jane@example.com", Password: "hash", Role: "Householder",
+		Address: "12 Baker Street", Contact: "9876543210", Latitude: 1.5, Longitude: 2.5}
+	require.NoError(t, repo.SaveUser(ctx, user))
+	assert.True(t, strings.HasPrefix(address, "enc1:k1:"))
+	assert.True(t, strings.HasPrefix(contact, "enc1:k1:"))
+	assert.NotContains(t, contact, "9876543210")
+
+	// Reading the row back returns the plaintext
+	rows := sqlmock.NewRows(userRowColumns).
+		AddRow("u1", "Jane", "jane@example.com", "hash", "Householder", address, contact, 1.5, 2.5, false, "Active", nil, nil)
+	mock.ExpectQuery("SELECT id, name, email, password").WithArgs("u1").WillReturnRows(rows)
+	loaded, err := repo.GetUserByID(ctx, "u1")
+	require.NoError(t, err)
+	assert.Equal(t, "12 Baker Street", loaded.Address)
+	assert.Equal(t, "9876543210", loaded.Contact)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUsersByContact(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	repo := repository.NewUserRepository(db)
+	ctx := context.Background()
+
+	// Without encryption the column itself is compared
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE contact = ?")).WithArgs("9876543210").
+		WillReturnRows(sqlmock.NewRows(userRowColumns).
+			AddRow("u1", "Jane", "jane@example.com", "hash", "Householder", "", "9876543210", 0, 0, false, "Active", nil, nil))
+	users, err := repo.GetUsersByContact(ctx, "98765 43210")
+	require.NoError(t, err)
+	assert.Len(t, users, 1)
+
+	_, envelope := useFieldCipher(t)
+	index, err := envelope.BlindIndex(ctx, "9876543210")
+	require.NoError(t, err)
+	match, err := envelope.Encrypt(ctx, "9876543210")
+	require.NoError(t, err)
+	collision, err := envelope.Encrypt(ctx, "1111111111")
+	require.NoError(t, err)
+
+	// A row whose index collides is filtered out once its number is decrypted
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE contact_index = ?")).WithArgs(index).
+		WillReturnRows(sqlmock.NewRows(userRowColumns).
+			AddRow("u1", "Jane", "jane@example.com", "hash", "Householder", "", match, 0, 0, false, "Active", nil, nil).
+			AddRow("u2", "Joe", "joe@example.com", "hash", "Householder", "", collision, 0, 0, false, "Active", nil, nil))
+	users, err = repo.GetUsersByContact(ctx, "9876543210")
+	require.NoError(t, err)
+	if assert.Len(t, users, 1) {
+		assert.Equal(t, "u1", users[0].ID)
+		assert.Equal(t, "9876543210", users[0].Contact)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReencrypt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	file, envelope := useFieldCipher(t)
+	repo := repository.NewFieldEncryptionRepository(db)
+	ctx := context.Background()
+
+	oldContact, err := envelope.Encrypt(ctx, "9876543210")
+	require.NoError(t, err)
+	_, err = file.Rotate()
+	require.NoError(t, err)
+	current, err := envelope.Encrypt(ctx, "Flat 4")
+	require.NoError(t, err)
+	index, err := envelope.BlindIndex(ctx, "9876543210")
+	require.NoError(t, err)
+
+	// u1 has a legacy address and a contact under the old key, u2 is already on the current key
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, COALESCE(address, ''), COALESCE(contact, '') FROM users WHERE id > ? ORDER BY id LIMIT ?")).
+		WithArgs("", 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "address", "contact"}).
+			AddRow("u1", "12 Baker Street", oldContact).
+			AddRow("u2", current, ""))
+	var address, contact string
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET address = ?, contact = ?, contact_index = ? WHERE id = ? AND COALESCE(address, '') = ? AND COALESCE(contact, '') = ?")).
+		WithArgs(capturedArg{&address}, capturedArg{&contact}, index, "u1", "12 Baker Street", oldContact).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	for _, table := range []string{"service_provider_details", "service_requests", "custom_requests"} {
+		mock.ExpectQuery("FROM "+table+" WHERE id > ?").WithArgs("", 500).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	}
+
+	changed, err := repo.Reencrypt(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, changed)
+	assert.Equal(t, "k2", envelope.KeyID(address))
+	assert.Equal(t, "k2", envelope.KeyID(contact))
+	plaintext, err := envelope.Decrypt(ctx, contact)
+	assert.NoError(t, err)
+	assert.Equal(t, "9876543210", plaintext)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestKeyUsage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	file, envelope := useFieldCipher(t)
+	repo := repository.NewFieldEncryptionRepository(db)
+	ctx := context.Background()
+
+	old, err := envelope.Encrypt(ctx, "9876543210")
+	require.NoError(t, err)
+	_, err = file.Rotate()
+	require.NoError(t, err)
+	current, err := envelope.Encrypt(ctx, "12 Baker Street")
+	require.NoError(t, err)
+
+	mock.ExpectQuery("FROM users WHERE id > ?").WithArgs("", 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "address", "contact"}).AddRow("u1", current, old).AddRow("u2", "", ""))
+	mock.ExpectQuery("FROM service_provider_details WHERE id > ?").WithArgs("", 500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "contact", "address"}).AddRow("d1", "9876543210", current))
+	mock.ExpectQuery("FROM service_requests WHERE id > ?").WithArgs("", 500).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery("FROM custom_requests WHERE id > ?").WithArgs("", 500).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	usage, err := repo.KeyUsage(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"k1": 1, "k2": 2, "": 1}, usage)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
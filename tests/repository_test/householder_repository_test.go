@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -35,11 +36,11 @@ func TestSaveHouseholder(t *testing.T) {
 
 	// Set up the expectation for the INSERT query
 	mock.ExpectExec("INSERT INTO users").
-		WithArgs(householder.ID, householder.Name, householder.Email, householder.Password, householder.Role, householder.Address, householder.Contact, householder.Latitude, householder.Longitude).
+		WithArgs(householder.ID, householder.Name, householder.Email, householder.Password, householder.Role, householder.Address, householder.Contact, nil, householder.Latitude, householder.Longitude).
 		WillReturnResult(sqlmock.NewResult(1, 1)) // Return result as if one row was inserted
 
 	// Call the method under test
-	err = repo.SaveHouseholder(householder)
+	err = repo.SaveHouseholder(context.Background(), householder)
 
 	// Assert that there was no error and expectations were met
 	assert.NoError(t, err)
@@ -79,7 +80,7 @@ func TestGetHouseholderByID(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Call the method under test
-	householder, err := repo.GetHouseholderByID("1")
+	householder, err := repo.GetHouseholderByID(context.Background(), "1")
 
 	// Assert that there was no error and the householder matches the expected result
 	assert.NoError(t, err)
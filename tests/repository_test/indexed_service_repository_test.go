@@ -0,0 +1,215 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"serviceNest/search"
+	"serviceNest/tests/mocks"
+	"testing"
+)
+
+func expectProviderLookup(userRepo *mocks.MockUserRepository, providerRepo *mocks.MockServiceProviderRepository, providerID string) {
+	userRepo.EXPECT().GetUserByID(gomock.Any(), providerID).
+		Return(&model.User{ID: providerID, Name: "Mario Rossi", Latitude: 12.97, Longitude: 77.59}, nil).AnyTimes()
+	providerRepo.EXPECT().GetProviderByID(gomock.Any(), providerID).
+		Return(&model.ServiceProvider{User: model.User{ID: providerID}, Rating: 4.5, IsActive: true, VerificationStatus: model.VerificationVerified}, nil).AnyTimes()
+}
+
+func TestIndexedServiceRepository_SyncsWrites(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	expectProviderLookup(mockUserRepo, mockProviderRepo, "provider1")
+
+	index := search.NewIndex()
+	repo := repository.NewIndexedServiceRepository(mockServiceRepo, index, mockProviderRepo, mockUserRepo)
+	service := model.Service{ID: "s1", Name: "Pipe Repair", Category: "Plumbing", ProviderID: "provider1"}
+
+	mockServiceRepo.EXPECT().SaveService(gomock.Any(), service).Return(nil)
+	assert.NoError(t, repo.SaveService(context.Background(), service))
+
+	results := index.Search(model.SearchQuery{Text: "pipe"})
+	assert.Len(t, results, 1)
+	assert.Equal(t, "Mario Rossi", results[0].Service.ProviderName)
+	assert.Equal(t, 4.5, results[0].Service.ProviderRating)
+
+	// Updates only carry the edited fields, so the stored row is indexed
+	updated := model.Service{ID: "s1", Name: "Tap Fitting"}
+	mockServiceRepo.EXPECT().UpdateService(gomock.Any(), "provider1", updated).Return(nil)
+	mockServiceRepo.EXPECT().GetServiceByID(gomock.Any(), "s1").
+		Return(&model.Service{ID: "s1", Name: "Tap Fitting", Category: "Plumbing", ProviderID: "provider1"}, nil)
+	assert.NoError(t, repo.UpdateService(context.Background(), "provider1", updated))
+	assert.Empty(t, index.Search(model.SearchQuery{Text: "pipe"}))
+	assert.Len(t, index.Search(model.SearchQuery{Text: "plumbing fitting"}), 1)
+
+	mockServiceRepo.EXPECT().RemoveServiceByProviderID(gomock.Any(), "provider1", "s1").Return(nil)
+	assert.NoError(t, repo.RemoveServiceByProviderID(context.Background(), "provider1", "s1"))
+	assert.Equal(t, 0, index.Len())
+}
+
+func TestIndexedServiceRepository_RenameCategoryReindexesServices(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	expectProviderLookup(mockUserRepo, mockProviderRepo, "provider1")
+
+	index := search.NewIndex()
+	repo := repository.NewIndexedServiceRepository(mockServiceRepo, index, mockProviderRepo, mockUserRepo)
+	service := model.Service{ID: "s1", Name: "Pipe Repair", Category: "plumber", ProviderID: "provider1"}
+	mockServiceRepo.EXPECT().SaveService(gomock.Any(), service).Return(nil)
+	assert.NoError(t, repo.SaveService(context.Background(), service))
+
+	mockServiceRepo.EXPECT().RenameCategory(gomock.Any(), "plumber", "plumbing").Return(nil)
+	mockServiceRepo.EXPECT().GetAllServices(gomock.Any(), model.QueryOptions{Category: "plumbing"}).
+		Return([]model.Service{{ID: "s1", Name: "Pipe Repair", Category: "plumbing", ProviderID: "provider1"}}, nil)
+	assert.NoError(t, repo.RenameCategory(context.Background(), "plumber", "plumbing"))
+
+	assert.Empty(t, index.Search(model.SearchQuery{Text: "plumber"}))
+	results := index.Search(model.SearchQuery{Text: "plumbing"})
+	assert.Len(t, results, 1)
+	assert.Equal(t, "plumbing", results[0].Service.Category)
+}
+
+func TestIndexedServiceRepository_FailedWriteLeavesIndexUntouched(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	index := search.NewIndex()
+	repo := repository.NewIndexedServiceRepository(mockServiceRepo, index, nil, nil)
+
+	mockServiceRepo.EXPECT().SaveService(gomock.Any(), gomock.Any()).Return(errors.New("duplicate entry"))
+
+	err := repo.SaveService(context.Background(), model.Service{ID: "s1", Name: "Pipe Repair", ProviderID: "provider1"})
+	assert.EqualError(t, err, "duplicate entry")
+	assert.Equal(t, 0, index.Len())
+}
+
+func TestIndexedServiceRepository_SkipsServicesWithoutProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	index := search.NewIndex()
+	repo := repository.NewIndexedServiceRepository(mockServiceRepo, index, nil, nil)
+
+	mockServiceRepo.EXPECT().SaveService(gomock.Any(), gomock.Any()).Return(nil)
+
+	assert.NoError(t, repo.SaveService(context.Background(), model.Service{ID: "s1", Name: "Custom Request"}))
+	assert.Equal(t, 0, index.Len())
+}
+
+func TestIndexedServiceRepository_SkipsUnverifiedProviders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	index := search.NewIndex()
+	repo := repository.NewIndexedServiceRepository(mockServiceRepo, index, mockProviderRepo, nil)
+
+	mockServiceRepo.EXPECT().SaveService(gomock.Any(), gomock.Any()).Return(nil)
+	mockProviderRepo.EXPECT().GetProviderByID(gomock.Any(), "provider1").
+		Return(&model.ServiceProvider{User: model.User{ID: "provider1"}, VerificationStatus: model.VerificationPending}, nil)
+
+	assert.NoError(t, repo.SaveService(context.Background(), model.Service{ID: "s1", Name: "Pipe Repair", ProviderID: "provider1"}))
+	assert.Equal(t, 0, index.Len())
+}
+
+func TestIndexedServiceRepository_IndexesOnlyAfterCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	expectProviderLookup(mockUserRepo, mockProviderRepo, "provider1")
+
+	index := search.NewIndex()
+	repo := repository.NewIndexedServiceRepository(repository.NewServiceRepository(db), index, mockProviderRepo, mockUserRepo)
+	txManager := repository.NewTransactionManager(db)
+	service := model.Service{ID: "s1", Name: "Pipe Repair", ProviderID: "provider1"}
+
+	// The insert succeeds but a later step fails, so the transaction is rolled back
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO services")).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	err = txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := repo.SaveService(ctx, service); err != nil {
+			return err
+		}
+		assert.Equal(t, 0, index.Len())
+		return errors.New("provider update failed")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 0, index.Len())
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO services")).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err = txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		return repo.SaveService(ctx, service)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, index.Len())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIndexedServiceRepository_Reindex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	expectProviderLookup(mockUserRepo, mockProviderRepo, "provider1")
+
+	mockServiceRepo.EXPECT().GetAllServices(gomock.Any(), model.QueryOptions{}).Return([]model.Service{
+		{ID: "s1", Name: "Pipe Repair", ProviderID: "provider1"},
+		{ID: "s2", Name: "Drain Unblocking", ProviderID: "provider1"},
+		{ID: "s3", Name: "Custom Request"},
+	}, nil)
+
+	index := search.NewIndex()
+	repo := repository.NewIndexedServiceRepository(mockServiceRepo, index, mockProviderRepo, mockUserRepo)
+
+	assert.NoError(t, repo.Reindex(context.Background()))
+	assert.Equal(t, 2, index.Len())
+}
+
+func TestIndexedServiceRepository_ReindexProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	index := search.NewIndex()
+	index.Index(model.SearchDocument{Service: model.Service{ID: "s1", Name: "Pipe Repair", ProviderID: "provider1"}})
+	repo := repository.NewIndexedServiceRepository(mockServiceRepo, index, mockProviderRepo, nil)
+
+	// The provider's account has been suspended, their services leave the index
+	mockServiceRepo.EXPECT().GetServiceByProviderID(gomock.Any(), "provider1").
+		Return([]model.Service{{ID: "s1", Name: "Pipe Repair", ProviderID: "provider1"}}, nil)
+	mockProviderRepo.EXPECT().GetProviderByID(gomock.Any(), "provider1").
+		Return(&model.ServiceProvider{User: model.User{ID: "provider1"}, IsActive: false, VerificationStatus: model.VerificationVerified}, nil)
+
+	assert.NoError(t, repo.ReindexProvider(context.Background(), "provider1"))
+	assert.Equal(t, 0, index.Len())
+}
@@ -0,0 +1,151 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+var throttleColumns = []string{"scope", "throttle_key", "failures", "last_failure_at", "locked_until"}
+
+func TestSaveAuditEntry(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptRepository(db)
+	now := time.Now()
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO login_audit")).
+		WithArgs("a1", "nobody@example.com", nil, "10.0.0.1", "InvalidCredentials", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.SaveAuditEntry(context.Background(), model.LoginAuditEntry{ID: "a1", Email: "nobody@example.com", Source: "10.0.0.1",
+		Outcome: model.LoginInvalidCredentials, CreatedAt: now})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAuditEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptRepository(db)
+	rows := sqlmock.NewRows([]string{"id", "email", "user_id", "source", "outcome", "created_at"}).
+		AddRow("a2", "jane@example.com", nil, "admin:a1", "Unlocked", []byte("2024-06-01 10:05:00")).
+		AddRow("a1", "jane@example.com", "u1", "10.0.0.1", "InvalidCredentials", []byte("2024-06-01 10:00:00"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM login_audit WHERE email = ? ORDER BY created_at DESC, id LIMIT ?")).
+		WithArgs("jane@example.com", 20).
+		WillReturnRows(rows)
+
+	entries, err := repo.GetAuditEntries(context.Background(), "jane@example.com", 20)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Empty(t, entries[0].UserID)
+	assert.Equal(t, "u1", entries[1].UserID)
+	assert.Equal(t, time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), entries[1].CreatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetThrottle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptRepository(db)
+	query := regexp.QuoteMeta("FROM login_throttles WHERE scope = ? AND throttle_key = ?")
+	mock.ExpectQuery(query).WithArgs("account", "jane@example.com").WillReturnRows(sqlmock.NewRows(throttleColumns).
+		AddRow("account", "jane@example.com", 3, []byte("2024-06-01 10:00:00"), []byte("2024-06-01 10:15:00")))
+	mock.ExpectQuery(query).WithArgs("source", "10.0.0.1").WillReturnRows(sqlmock.NewRows(throttleColumns))
+
+	throttle, err := repo.GetThrottle(context.Background(), model.ThrottleAccount, "jane@example.com")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, throttle.Failures)
+	assert.Equal(t, time.Date(2024, 6, 1, 10, 15, 0, 0, time.UTC), *throttle.LockedUntil)
+
+	// No failures on record
+	throttle, err = repo.GetThrottle(context.Background(), model.ThrottleSource, "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, &model.LoginThrottle{Scope: "source", Key: "10.0.0.1"}, throttle)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIncrementFailures(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptRepository(db)
+	now := time.Now()
+	windowStart := now.Add(-time.Hour)
+
+	mock.ExpectExec(regexp.QuoteMeta("ON DUPLICATE KEY UPDATE failures = IF(last_failure_at < ?, 1, failures + 1)")).
+		WithArgs("account", "jane@example.com", now, windowStart).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT failures FROM login_throttles")).
+		WithArgs("account", "jane@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"failures"}).AddRow(4))
+
+	failures, err := repo.IncrementFailures(context.Background(), model.ThrottleAccount, "jane@example.com", now, windowStart)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, failures)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestLockAndResetThrottle(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptRepository(db)
+	until := time.Now().Add(15 * time.Minute)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE login_throttles SET failures = 0, locked_until = ?")).
+		WithArgs(until, "account", "jane@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM login_throttles WHERE scope = ? AND throttle_key = ?")).
+		WithArgs("account", "jane@example.com").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.LockThrottle(context.Background(), model.ThrottleAccount, "jane@example.com", until))
+	assert.NoError(t, repo.ResetThrottle(context.Background(), model.ThrottleAccount, "jane@example.com"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetLockedThrottles(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptRepository(db)
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE scope = ? AND locked_until > ? ORDER BY locked_until DESC")).
+		WithArgs("account", now).
+		WillReturnRows(sqlmock.NewRows(throttleColumns).
+			AddRow("account", "jane@example.com", 0, []byte("2024-06-01 10:00:00"), []byte("2024-06-01 10:15:00")))
+
+	locked, err := repo.GetLockedThrottles(context.Background(), model.ThrottleAccount, now)
+	assert.NoError(t, err)
+	assert.Len(t, locked, 1)
+	assert.Equal(t, "jane@example.com", locked[0].Key)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnonymiseAuditEntries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewLoginAttemptRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE login_audit SET email = ?, source = CASE WHEN source LIKE 'admin:%' THEN source ELSE '' END WHERE email = ?")).
+		WithArgs("erased-u1@erased.invalid", "asha@example.com").WillReturnResult(sqlmock.NewResult(0, 4))
+
+	assert.NoError(t, repo.AnonymiseAuditEntries(context.Background(), "asha@example.com", "erased-u1@erased.invalid"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,108 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+var providerDocumentRowColumns = []string{"id", "provider_id", "type", "file_name", "blob_key", "size", "uploaded_at"}
+
+func TestSaveDocument(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewProviderDocumentRepository(db)
+	document := model.ProviderDocument{ID: "d1", ProviderID: "p1", Type: model.DocumentIdentity, FileName: "passport.pdf",
+		BlobKey: "provider-documents/p1/d1.pdf", Size: 2048, UploadedAt: time.Now()}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO provider_documents")).
+		WithArgs("d1", "p1", "ID", "passport.pdf", "provider-documents/p1/d1.pdf", int64(2048), document.UploadedAt).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	assert.NoError(t, repo.SaveDocument(context.Background(), document))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDocumentsByProviderID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewProviderDocumentRepository(db)
+
+	rows := sqlmock.NewRows(providerDocumentRowColumns).
+		AddRow("d1", "p1", "ID", "passport.pdf", "provider-documents/p1/d1.pdf", 2048, []byte("2024-05-01 10:00:00")).
+		AddRow("d2", "p1", "Licence", "licence.png", "provider-documents/p1/d2.png", 512, []byte("2024-05-02 10:00:00"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM provider_documents WHERE provider_id = ? ORDER BY uploaded_at, id")).
+		WithArgs("p1").
+		WillReturnRows(rows)
+
+	documents, err := repo.GetDocumentsByProviderID(context.Background(), "p1")
+
+	assert.NoError(t, err)
+	assert.Len(t, documents, 2)
+	assert.Equal(t, model.DocumentLicence, documents[1].Type)
+	assert.Equal(t, time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC), documents[0].UploadedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDocumentByID_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewProviderDocumentRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM provider_documents WHERE id = ?")).WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows(providerDocumentRowColumns))
+
+	_, err = repo.GetDocumentByID(context.Background(), "missing")
+	assert.EqualError(t, err, "document not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetProvidersByVerificationStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceProviderRepository(db)
+
+	rows := sqlmock.NewRows([]string{"user_id", "name", "email", "contact", "address", "rating", "availability", "is_active", "verification_status", "verification_note"}).
+		AddRow("p1", "Mario Rossi", "mario@example.com", "9876543210", "Main St", 0.0, true, true, "PendingVerification", nil).
+		AddRow("p2", "Luigi Verdi", "luigi@example.com", "9876543211", "High St", 0.0, true, true, "PendingVerification", "licence expired")
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE sp.verification_status = ?")).WithArgs(model.VerificationPending).WillReturnRows(rows)
+
+	providers, err := repo.GetProvidersByVerificationStatus(context.Background(), model.VerificationPending)
+
+	assert.NoError(t, err)
+	assert.Len(t, providers, 2)
+	assert.Equal(t, "Mario Rossi", providers[0].Name)
+	assert.Equal(t, "licence expired", providers[1].VerificationNote)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateVerificationStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceProviderRepository(db)
+	query := regexp.QuoteMeta("UPDATE service_providers SET verification_status = ?, verification_note = ?, reviewed_by = ?, reviewed_at = ? WHERE user_id = ?")
+
+	mock.ExpectExec(query).WithArgs("Verified", "all good", "admin1", sqlmock.AnyArg(), "p1").WillReturnResult(sqlmock.NewResult(0, 1))
+	// A resubmission clears the previous review
+	mock.ExpectExec(query).WithArgs("PendingVerification", nil, nil, nil, "p2").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs("Rejected", "blurry", "admin1", sqlmock.AnyArg(), "missing").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.UpdateVerificationStatus(context.Background(), "p1", model.VerificationVerified, "all good", "admin1"))
+	assert.NoError(t, repo.UpdateVerificationStatus(context.Background(), "p2", model.VerificationPending, "", ""))
+	assert.EqualError(t, repo.UpdateVerificationStatus(context.Background(), "missing", model.VerificationRejected, "blurry", "admin1"), "provider not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
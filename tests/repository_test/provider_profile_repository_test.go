@@ -0,0 +1,160 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+func certificationRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "provider_id", "name", "issuer", "year"})
+}
+
+func portfolioRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "provider_id", "caption", "file_name", "blob_key", "size", "uploaded_at"})
+}
+
+func TestProviderProfileRepository_GetProfileDetails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewProviderProfileRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT bio, years_experience, languages, updated_at FROM provider_profiles WHERE provider_id = ?")).
+		WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"bio", "years_experience", "languages", "updated_at"}).
+			AddRow("Plumber", 12, "English,Hindi", []byte("2024-06-01 10:00:00")))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM provider_certifications WHERE provider_id = ?")).WithArgs("p1").
+		WillReturnRows(certificationRows().AddRow("c1", "p1", "Gas Safe", "HSE", 2015))
+
+	details, err := repo.GetProfileDetails(context.Background(), "p1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Plumber", details.Bio)
+	assert.Equal(t, 12, details.YearsExperience)
+	assert.Equal(t, []string{"English", "Hindi"}, details.Languages)
+	require.NotNil(t, details.UpdatedAt)
+	require.Len(t, details.Certifications, 1)
+	assert.Equal(t, "Gas Safe", details.Certifications[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProviderProfileRepository_GetProfileDetails_NoProfileYet(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewProviderProfileRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM provider_profiles WHERE provider_id = ?")).WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"bio", "years_experience", "languages", "updated_at"}))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM provider_certifications WHERE provider_id = ?")).WithArgs("p1").
+		WillReturnRows(certificationRows())
+
+	details, err := repo.GetProfileDetails(context.Background(), "p1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", details.ProviderID)
+	assert.Empty(t, details.Languages)
+	assert.Nil(t, details.UpdatedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProviderProfileRepository_SaveProfileDetails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewProviderProfileRepository(db)
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO provider_profiles (provider_id, bio, years_experience, languages, updated_at) VALUES (?, ?, ?, ?, ?)")).
+		WithArgs("p1", "Plumber", 12, "English,Hindi", &now).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err = repo.SaveProfileDetails(context.Background(), model.ProviderProfileDetails{
+		ProviderID: "p1", Bio: "Plumber", YearsExperience: 12, Languages: []string{"English", "Hindi"}, UpdatedAt: &now,
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProviderProfileRepository_GetCertificationByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewProviderProfileRepository(db)
+	query := regexp.QuoteMeta("SELECT id, provider_id, name, issuer, year FROM provider_certifications WHERE id = ?")
+	mock.ExpectQuery(query).WithArgs("c1").WillReturnRows(certificationRows().AddRow("c1", "p1", "Gas Safe", "Gas Safe Register", 2015))
+	mock.ExpectQuery(query).WithArgs("missing").WillReturnRows(certificationRows())
+
+	certification, err := repo.GetCertificationByID(context.Background(), "c1")
+	require.NoError(t, err)
+	assert.Equal(t, model.Certification{ID: "c1", ProviderID: "p1", Name: "Gas Safe", Issuer: "Gas Safe Register", Year: 2015}, *certification)
+	_, err = repo.GetCertificationByID(context.Background(), "missing")
+	assert.EqualError(t, err, "certification not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProviderProfileRepository_DeleteCertification(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewProviderProfileRepository(db)
+	query := regexp.QuoteMeta("DELETE FROM provider_certifications WHERE id = ? AND provider_id = ?")
+	mock.ExpectExec(query).WithArgs("c1", "p1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs("c1", "p2").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.DeleteCertification(context.Background(), "p1", "c1"))
+	assert.EqualError(t, repo.DeleteCertification(context.Background(), "p2", "c1"), "certification not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProviderProfileRepository_PortfolioImages(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewProviderProfileRepository(db)
+	image := model.PortfolioImage{ID: "i1", ProviderID: "p1", Caption: "Bathroom", FileName: "bath.jpg",
+		BlobKey: "provider-portfolio/p1/i1.jpg", Size: 1024, UploadedAt: time.Now()}
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO provider_portfolio (id, provider_id, caption, file_name, blob_key, size, uploaded_at) VALUES (?, ?, ?, ?, ?, ?, ?)")).
+		WithArgs("i1", "p1", "Bathroom", "bath.jpg", "provider-portfolio/p1/i1.jpg", int64(1024), image.UploadedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM provider_portfolio WHERE provider_id = ?")).WithArgs("p1").
+		WillReturnRows(portfolioRows().AddRow("i1", "p1", "Bathroom", "bath.jpg", "provider-portfolio/p1/i1.jpg", 1024, []byte("2024-06-01 10:00:00")))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM provider_portfolio WHERE id = ?")).WithArgs("missing").WillReturnRows(portfolioRows())
+
+	assert.NoError(t, repo.SavePortfolioImage(context.Background(), image))
+	images, err := repo.GetPortfolioImages(context.Background(), "p1")
+	assert.NoError(t, err)
+	if assert.Len(t, images, 1) {
+		assert.Equal(t, int64(1024), images[0].Size)
+		assert.Equal(t, 2024, images[0].UploadedAt.Year())
+	}
+	_, err = repo.GetPortfolioImageByID(context.Background(), "missing")
+	assert.EqualError(t, err, "image not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProviderProfileRepository_DeleteProfile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewProviderProfileRepository(db)
+	mock.ExpectBegin()
+	for _, table := range []string{"provider_portfolio", "provider_certifications", "provider_profiles"} {
+		mock.ExpectExec(regexp.QuoteMeta("DELETE FROM " + table + " WHERE provider_id = ?")).WithArgs("p1").WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.DeleteProfile(context.Background(), "p1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,97 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+func ratingRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"provider_id", "category", "criterion", "weighted_sum", "weight_total", "review_count", "score_sum", "anchor_at"})
+}
+
+func TestRatingRepository_LockProviderRatings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewRatingRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id FROM service_providers WHERE user_id = ? FOR UPDATE")).WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow("p1"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM provider_ratings WHERE provider_id = ? FOR UPDATE")).WithArgs("p1").
+		WillReturnRows(ratingRows().AddRow("p1", "", "overall", 8.5, 1.9, 2, 9.0, []byte("2024-06-01 10:00:00")))
+
+	aggregates, err := repo.LockProviderRatings(context.Background(), "p1")
+
+	assert.NoError(t, err)
+	require.Len(t, aggregates, 1)
+	assert.Equal(t, model.CriterionOverall, aggregates[0].Criterion)
+	assert.Equal(t, 2, aggregates[0].ReviewCount)
+	assert.Equal(t, 2024, aggregates[0].AnchorAt.Year())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRatingRepository_ReplaceProviderRatingsAndSetRating(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewRatingRepository(db)
+	anchorAt := time.Now()
+	aggregate := model.RatingAggregate{ProviderID: "p1", Category: "Plumbing", Criterion: model.CriterionQuality,
+		WeightedSum: 4, WeightTotal: 1, ReviewCount: 1, ScoreSum: 4, AnchorAt: anchorAt}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM provider_ratings WHERE provider_id = ?")).WithArgs("p1").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO provider_ratings")).
+		WithArgs("p1", "Plumbing", model.CriterionQuality, 4.0, 1.0, 1, 4.0, anchorAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE service_providers SET rating = ? WHERE user_id = ?")).WithArgs(3.75, "p1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.ReplaceProviderRatings(context.Background(), "p1", []model.RatingAggregate{aggregate}))
+	assert.NoError(t, repo.SetProviderRating(context.Background(), "p1", 3.75))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRatingRepository_RenameCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewRatingRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reviews SET category = ? WHERE category = ?")).WithArgs("Plumbing", "Plumber").
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE provider_ratings SET category = ? WHERE category = ?")).WithArgs("Plumbing", "Plumber").
+		WillReturnResult(sqlmock.NewResult(0, 6))
+	mock.ExpectCommit()
+
+	assert.NoError(t, repo.RenameCategory(context.Background(), "Plumber", "Plumbing"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRatingRepository_GetRatedProviderIDs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewRatingRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT provider_id FROM reviews UNION SELECT provider_id FROM provider_ratings")).
+		WillReturnRows(sqlmock.NewRows([]string{"provider_id"}).AddRow("p1").AddRow("p2"))
+
+	providerIDs, err := repo.GetRatedProviderIDs(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"p1", "p2"}, providerIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
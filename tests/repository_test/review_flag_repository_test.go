@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+func TestReviewFlagRepository_SaveFlag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewReviewFlagRepository(db)
+	flag := model.ReviewFlag{ID: "f1", ReviewID: "review1", UserID: "h1", Reason: "Spam", CreatedAt: time.Now()}
+	query := regexp.QuoteMeta("INSERT INTO review_flags (id, review_id, user_id, reason, created_at, resolved) VALUES (?, ?, ?, ?, ?, ?)")
+	mock.ExpectExec(query).WithArgs("f1", "review1", "h1", "Spam", flag.CreatedAt, false).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry"})
+
+	assert.NoError(t, repo.SaveFlag(context.Background(), flag))
+	assert.ErrorIs(t, repo.SaveFlag(context.Background(), flag), model.ErrAlreadyFlagged)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewFlagRepository_GetOpenFlagsAndResolve(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewReviewFlagRepository(db)
+	rows := sqlmock.NewRows([]string{"id", "review_id", "user_id", "reason", "created_at", "resolved"}).
+		AddRow("f1", "review1", "h1", "Spam", []byte("2024-06-01 10:00:00"), false).
+		AddRow("f2", "review2", "p1", "Insulting", []byte("2024-06-02 10:00:00"), false)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM review_flags WHERE resolved = FALSE ORDER BY created_at, id")).WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE review_flags SET resolved = TRUE WHERE review_id = ? AND resolved = FALSE")).
+		WithArgs("review1").WillReturnResult(sqlmock.NewResult(0, 1))
+
+	flags, err := repo.GetOpenFlags(context.Background())
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	assert.Equal(t, "Spam", flags[0].Reason)
+	assert.Equal(t, 2024, flags[1].CreatedAt.Year())
+	assert.NoError(t, repo.ResolveFlags(context.Background(), "review1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,79 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+func reviewPhotoRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "review_id", "kind", "file_name", "blob_key", "size", "uploaded_at"})
+}
+
+func TestReviewPhotoRepository_SaveAndGetPhoto(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewReviewPhotoRepository(db)
+	photo := model.ReviewPhoto{ID: "ph1", ReviewID: "review1", Kind: model.PhotoBefore, FileName: "sink.jpg",
+		BlobKey: "review-photos/review1/ph1.jpg", Size: 2048, UploadedAt: time.Now()}
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO review_photos (id, review_id, kind, file_name, blob_key, size, uploaded_at) VALUES (?, ?, ?, ?, ?, ?, ?)")).
+		WithArgs("ph1", "review1", model.PhotoBefore, "sink.jpg", "review-photos/review1/ph1.jpg", int64(2048), photo.UploadedAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM review_photos p WHERE p.id = ?")).WithArgs("ph1").
+		WillReturnRows(reviewPhotoRows().AddRow("ph1", "review1", "before", "sink.jpg", "review-photos/review1/ph1.jpg", 2048, []byte("2024-06-01 10:00:00")))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM review_photos p WHERE p.id = ?")).WithArgs("missing").WillReturnRows(reviewPhotoRows())
+
+	assert.NoError(t, repo.SavePhoto(context.Background(), photo))
+	saved, err := repo.GetPhotoByID(context.Background(), "ph1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2048), saved.Size)
+	assert.Equal(t, 2024, saved.UploadedAt.Year())
+	_, err = repo.GetPhotoByID(context.Background(), "missing")
+	assert.EqualError(t, err, "photo not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewPhotoRepository_GetPhotosByProviderID(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewReviewPhotoRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE r.provider_id = ? AND r.hidden = FALSE")).WithArgs("p1").
+		WillReturnRows(reviewPhotoRows().
+			AddRow("ph2", "review1", "after", "done.jpg", "review-photos/review1/ph2.jpg", 10, []byte("2024-06-02 10:00:00")).
+			AddRow("ph1", "review1", "before", "sink.jpg", "review-photos/review1/ph1.jpg", 10, []byte("2024-06-01 10:00:00")))
+
+	photos, err := repo.GetPhotosByProviderID(context.Background(), "p1")
+
+	assert.NoError(t, err)
+	require.Len(t, photos, 2)
+	assert.Equal(t, model.PhotoAfter, photos[0].Kind)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewPhotoRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewReviewPhotoRepository(db)
+	query := regexp.QuoteMeta("DELETE FROM review_photos WHERE id = ?")
+	mock.ExpectExec(query).WithArgs("ph1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs("ph1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE p FROM review_photos p INNER JOIN reviews r ON r.id = p.review_id WHERE r.householder_id = ?")).
+		WithArgs("h1").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, repo.DeletePhoto(context.Background(), "ph1"))
+	assert.EqualError(t, repo.DeletePhoto(context.Background(), "ph1"), "photo not found")
+	assert.NoError(t, repo.DeletePhotosByHouseholderID(context.Background(), "h1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,48 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+func TestSaveRoleChange(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewRoleHistoryRepository(db)
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO role_history")).
+		WithArgs("r1", "u1", "Householder", "Support", "a1", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.SaveRoleChange(context.Background(), model.RoleChange{ID: "r1", UserID: "u1", FromRole: model.RoleHouseholder,
+		ToRole: model.RoleSupport, ChangedBy: "a1", ChangedAt: now})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetRoleHistory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewRoleHistoryRepository(db)
+	rows := sqlmock.NewRows([]string{"id", "user_id", "from_role", "to_role", "changed_by", "changed_at"}).
+		AddRow("r2", "u1", "Support", "Admin", "a1", []byte("2024-06-02 10:00:00")).
+		AddRow("r1", "u1", "Householder", "Support", "a1", []byte("2024-06-01 10:00:00"))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM role_history WHERE user_id = ? ORDER BY changed_at DESC")).WithArgs("u1").WillReturnRows(rows)
+
+	history, err := repo.GetRoleHistory(context.Background(), "u1")
+	assert.NoError(t, err)
+	assert.Len(t, history, 2)
+	assert.Equal(t, model.RoleAdmin, history[0].ToRole)
+	assert.Equal(t, time.Date(2024, 6, 1, 10, 0, 0, 0, time.UTC), history[1].ChangedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,9 +1,11 @@
 package repository_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-sql-driver/mysql"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,11 +33,11 @@ func TestServiceProviderRepository_AddReview(t *testing.T) {
 
 	// Expectations
 	mockRepo.EXPECT().
-		AddReview(review).
+		AddReview(gomock.Any(), review).
 		Return(nil)
 
 	// Execute
-	err := mockRepo.AddReview(review)
+	err := mockRepo.AddReview(context.Background(), review)
 
 	// Verify
 	assert.NoError(t, err)
@@ -58,11 +60,11 @@ func TestServiceProviderRepository_GetProviderByID(t *testing.T) {
 
 	// Expectations
 	mockRepo.EXPECT().
-		GetProviderByID(providerID).
+		GetProviderByID(gomock.Any(), providerID).
 		Return(expectedProvider, nil)
 
 	// Execute
-	result, err := mockRepo.GetProviderByID(providerID)
+	result, err := mockRepo.GetProviderByID(context.Background(), providerID)
 
 	// Verify
 	assert.NoError(t, err)
@@ -81,11 +83,11 @@ func TestServiceProviderRepository_GetProviderByID_Error(t *testing.T) {
 
 	// Expectations
 	mockRepo.EXPECT().
-		GetProviderByID(providerID).
+		GetProviderByID(gomock.Any(), providerID).
 		Return(nil, expectedError)
 
 	// Execute
-	result, err := mockRepo.GetProviderByID(providerID)
+	result, err := mockRepo.GetProviderByID(context.Background(), providerID)
 
 	// Verify
 	assert.Nil(t, result)
@@ -117,11 +119,11 @@ func TestServiceProviderRepository_GetProvidersByServiceType(t *testing.T) {
 
 	// Expectations
 	mockRepo.EXPECT().
-		GetProvidersByServiceType(serviceType).
+		GetProvidersByServiceType(gomock.Any(), serviceType).
 		Return(expectedProviders, nil)
 
 	// Execute
-	result, err := mockRepo.GetProvidersByServiceType(serviceType)
+	result, err := mockRepo.GetProvidersByServiceType(context.Background(), serviceType)
 
 	// Verify
 	assert.NoError(t, err)
@@ -144,11 +146,11 @@ func TestServiceProviderRepository_SaveServiceProvider(t *testing.T) {
 
 	// Expectations
 	mockRepo.EXPECT().
-		SaveServiceProvider(provider).
+		SaveServiceProvider(gomock.Any(), provider).
 		Return(nil)
 
 	// Execute
-	err := mockRepo.SaveServiceProvider(provider)
+	err := mockRepo.SaveServiceProvider(context.Background(), provider)
 
 	// Verify
 	assert.NoError(t, err)
@@ -170,11 +172,11 @@ func TestServiceProviderRepository_UpdateServiceProvider(t *testing.T) {
 
 	// Expectations
 	mockRepo.EXPECT().
-		UpdateServiceProvider(provider).
+		UpdateServiceProvider(gomock.Any(), provider).
 		Return(nil)
 
 	// Execute
-	err := mockRepo.UpdateServiceProvider(provider)
+	err := mockRepo.UpdateServiceProvider(context.Background(), provider)
 
 	// Verify
 	assert.NoError(t, err)
@@ -202,7 +204,7 @@ func TestGetProviderDetailByID(t *testing.T) {
 		WithArgs(providerID).
 		WillReturnRows(rows)
 
-	provider, err := repo.GetProviderDetailByID(providerID)
+	provider, err := repo.GetProviderDetailByID(context.Background(), providerID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedProvider, provider)
@@ -237,7 +239,7 @@ func TestSaveServiceProviderDetail(t *testing.T) {
 		WithArgs(sqlmock.AnyArg(), requestID, provider.ServiceProviderID, provider.Name, provider.Contact, provider.Address, provider.Price, provider.Rating, provider.Approve).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.SaveServiceProviderDetail(provider, requestID)
+	err = repo.SaveServiceProviderDetail(context.Background(), provider, requestID)
 
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -252,17 +254,36 @@ func TestUpdateServiceProviderDetailByRequestID(t *testing.T) {
 	provider := &model.ServiceProviderDetails{
 		ServiceProviderID: "provider123",
 		Approve:           true,
+		Version:           1,
 	}
 	requestID := "request123"
 
 	// Mock the update
 	mock.ExpectExec("UPDATE service_provider_details").
-		WithArgs(provider.Approve, provider.ServiceProviderID, requestID).
+		WithArgs(provider.Approve, provider.ServiceProviderID, requestID, 1).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.UpdateServiceProviderDetailByRequestID(provider, requestID)
+	err = repo.UpdateServiceProviderDetailByRequestID(context.Background(), provider, requestID)
 
 	assert.NoError(t, err)
+	assert.Equal(t, 2, provider.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+func TestUpdateServiceProviderDetailByRequestID_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceProviderRepository(db)
+	provider := &model.ServiceProviderDetails{ServiceProviderID: "provider123", Approve: true, Version: 1}
+
+	mock.ExpectExec("UPDATE service_provider_details").
+		WithArgs(true, "provider123", "request123", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateServiceProviderDetailByRequestID(context.Background(), provider, "request123")
+
+	assert.True(t, errors.Is(err, model.ErrConflict))
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 func TestIsProviderApproved(t *testing.T) {
@@ -279,12 +300,19 @@ func TestIsProviderApproved(t *testing.T) {
 		WithArgs(providerID).
 		WillReturnRows(sqlmock.NewRows([]string{"approve"}).AddRow(true))
 
-	isApproved, err := repo.IsProviderApproved(providerID)
+	isApproved, err := repo.IsProviderApproved(context.Background(), providerID)
 
 	assert.NoError(t, err)
 	assert.True(t, isApproved)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+// reviewRows has the columns the review queries read
+func reviewRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "provider_id", "service_id", "householder_id", "request_id", "category", "rating", "comments", "review_date",
+		"updated_at", "reply", "reply_date", "hidden", "moderation_note", "moderated_by", "moderated_at"})
+}
+
 func TestAddReview(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -297,53 +325,107 @@ func TestAddReview(t *testing.T) {
 		ProviderID:    "provider123",
 		ServiceID:     "service123",
 		HouseholderID: "householder123",
+		RequestID:     "request123",
+		Category:      "Plumbing",
 		Rating:        4,
+		Scores:        map[string]float64{model.CriterionValue: 3, model.CriterionPunctuality: 5},
 		Comments:      "Great service",
 		ReviewDate:    time.Now(),
 	}
 
-	// Mock the transaction and insert review
+	// Mock the transaction, the review and its sub-scores
 	mock.ExpectBegin()
 	mock.ExpectExec("INSERT INTO reviews").
-		WithArgs(review.ID, review.ProviderID, review.ServiceID, review.HouseholderID, review.Rating, review.Comments, sqlmock.AnyArg()).
+		WithArgs(review.ID, review.ProviderID, review.ServiceID, review.HouseholderID, "request123", "Plumbing", review.Rating, review.Comments, sqlmock.AnyArg()).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	scoreQuery := regexp.QuoteMeta("INSERT INTO review_scores (review_id, criterion, score) VALUES (?, ?, ?)")
+	mock.ExpectExec(scoreQuery).WithArgs("review123", model.CriterionPunctuality, 5.0).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(scoreQuery).WithArgs("review123", model.CriterionValue, 3.0).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
-	err = repo.AddReview(review)
+	err = repo.AddReview(context.Background(), review)
 
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
-func TestUpdateProviderRating(t *testing.T) {
+
+func TestAddReview_DuplicateRequest(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
 	defer db.Close()
 
 	repo := repository.NewServiceProviderRepository(db)
 
-	providerID := "provider123"
-	avgRating := 4.5
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO reviews").
+		WillReturnError(&mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'request123' for key 'uq_reviews_request'"})
+	mock.ExpectRollback()
 
-	// Mock the query that calculates the average rating
-	mock.ExpectQuery("SELECT AVG\\(r.rating\\) FROM reviews").
-		WithArgs(providerID).
-		WillReturnRows(sqlmock.NewRows([]string{"AVG(r.rating)"}).AddRow(avgRating))
+	err = repo.AddReview(context.Background(), model.Review{ID: "review456", RequestID: "request123", Rating: 3, ReviewDate: time.Now()})
 
-	// Mock the update for service_providers
-	mock.ExpectExec("UPDATE service_providers").
-		WithArgs(avgRating, providerID).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	assert.ErrorIs(t, err, model.ErrAlreadyReviewed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	// Mock the update for service_provider_details
-	mock.ExpectExec("UPDATE service_provider_details").
-		WithArgs(avgRating, providerID).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+func TestGetReviewByIDAndUpdateReview(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceProviderRepository(db)
+	rows := reviewRows().
+		AddRow("review123", "provider123", "service123", "householder123", "request123", "Plumbing", 4.0, "Great service", []byte("2024-06-01 10:00:00"), nil,
+			nil, nil, false, nil, nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM reviews WHERE id = ?")).WithArgs("review123").WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM review_scores WHERE review_id IN (?)")).WithArgs("review123").
+		WillReturnRows(sqlmock.NewRows([]string{"review_id", "criterion", "score"}).AddRow("review123", model.CriterionQuality, 4.0))
+
+	review, err := repo.GetReviewByID(context.Background(), "review123")
+	require.NoError(t, err)
+	assert.Equal(t, "request123", review.RequestID)
+	assert.Equal(t, "Plumbing", review.Category)
+	assert.Equal(t, map[string]float64{model.CriterionQuality: 4}, review.Scores)
+	assert.Nil(t, review.UpdatedAt)
+
+	updatedAt := time.Now()
+	review.Rating = 5
+	review.Scores = nil
+	review.UpdatedAt = &updatedAt
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reviews SET rating = ?, comments = ?, updated_at = ? WHERE id = ?")).
+		WithArgs(5.0, "Great service", &updatedAt, "review123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM review_scores WHERE review_id = ?")).WithArgs("review123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.UpdateReview(context.Background(), *review))
 
-	err = repo.UpdateProviderRating(providerID)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM reviews WHERE id = ?")).WithArgs("missing").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err = repo.GetReviewByID(context.Background(), "missing")
+	assert.EqualError(t, err, "review not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
+func TestLockReviewByID(t *testing.T) {
+	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceProviderRepository(db)
+	rows := reviewRows().
+		AddRow("review123", "provider123", "service123", "householder123", "request123", "Plumbing", 4.0, "Great service", []byte("2024-06-01 10:00:00"), nil,
+			nil, nil, false, nil, nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM reviews WHERE id = ? FOR UPDATE")).WithArgs("review123").WillReturnRows(rows)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM review_scores WHERE review_id IN (?)")).WithArgs("review123").
+		WillReturnRows(sqlmock.NewRows([]string{"review_id", "criterion", "score"}))
+
+	review, err := repo.LockReviewByID(context.Background(), "review123")
+	require.NoError(t, err)
+	assert.Equal(t, 4.0, review.Rating)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
 func TestGetReviewsByProviderID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -355,18 +437,22 @@ func TestGetReviewsByProviderID(t *testing.T) {
 	reviewDate := time.Now().Format(time.RFC3339) // RFC3339 format
 
 	// Mock the query that fetches reviews
-	rows := sqlmock.NewRows([]string{"id", "provider_id", "service_id", "householder_id", "rating", "comments", "review_date"}).
-		AddRow("review123", providerID, "service123", "householder123", 4, "Great service", reviewDate)
+	rows := reviewRows().
+		AddRow("review123", providerID, "service123", "householder123", nil, "", 4, "Great service", reviewDate, nil,
+			"Thank you", reviewDate, false, nil, nil, nil)
 
-	mock.ExpectQuery("SELECT id, provider_id, service_id, householder_id, rating, comments, review_date FROM reviews").
+	mock.ExpectQuery(regexp.QuoteMeta("FROM reviews WHERE provider_id = ?")).
 		WithArgs(providerID).
 		WillReturnRows(rows)
+	mock.ExpectQuery("FROM review_scores").WillReturnRows(sqlmock.NewRows([]string{"review_id", "criterion", "score"}))
 
-	reviews, err := repo.GetReviewsByProviderID(providerID)
+	reviews, err := repo.GetReviewsByProviderID(context.Background(), providerID)
 
 	assert.NoError(t, err)
 	assert.Len(t, reviews, 1)
 	assert.Equal(t, "review123", reviews[0].ID)
+	assert.Equal(t, "Thank you", reviews[0].Reply)
+	assert.NotNil(t, reviews[0].ReplyDate)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 func TestSaveServiceProvider_Success(t *testing.T) {
@@ -386,12 +472,13 @@ func TestSaveServiceProvider_Success(t *testing.T) {
 	}
 
 	// Expect the SQL statement to be executed
-	query := regexp.QuoteMeta("INSERT INTO service_providers (user_id, rating, availability, is_active) VALUES (?, ?, ?, ?)")
-	mock.ExpectExec(query).WithArgs(provider.User.ID, provider.Rating, provider.Availability, provider.IsActive).
+	// Without a status the provider starts out pending verification
+	query := regexp.QuoteMeta("INSERT INTO service_providers (user_id, rating, availability, is_active, verification_status) VALUES (?, ?, ?, ?, ?)")
+	mock.ExpectExec(query).WithArgs(provider.User.ID, provider.Rating, provider.Availability, provider.IsActive, model.VerificationPending).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Call the function
-	err = repo.SaveServiceProvider(provider)
+	err = repo.SaveServiceProvider(context.Background(), provider)
 	assert.NoError(t, err)
 
 	// Ensure all expectations were met
@@ -409,20 +496,21 @@ func TestGetProviderByID_Success(t *testing.T) {
 
 	// Mock provider data
 	expectedProvider := model.ServiceProvider{
-		User:         model.User{ID: "user123"},
-		Rating:       4.5,
-		Availability: true,
-		IsActive:     true,
+		User:               model.User{ID: "user123"},
+		Rating:             4.5,
+		Availability:       true,
+		IsActive:           true,
+		VerificationStatus: model.VerificationVerified,
 	}
 
 	// Expect the query to be executed and return the result
-	query := regexp.QuoteMeta("SELECT user_id, rating, availability, is_active FROM service_providers WHERE user_id = ?")
-	rows := sqlmock.NewRows([]string{"user_id", "rating", "availability", "is_active"}).
-		AddRow(expectedProvider.User.ID, expectedProvider.Rating, expectedProvider.Availability, expectedProvider.IsActive)
+	query := regexp.QuoteMeta("SELECT user_id, rating, availability, is_active, verification_status, verification_note FROM service_providers WHERE user_id = ?")
+	rows := sqlmock.NewRows([]string{"user_id", "rating", "availability", "is_active", "verification_status", "verification_note"}).
+		AddRow(expectedProvider.User.ID, expectedProvider.Rating, expectedProvider.Availability, expectedProvider.IsActive, expectedProvider.VerificationStatus, nil)
 	mock.ExpectQuery(query).WithArgs("user123").WillReturnRows(rows)
 
 	// Call the function
-	provider, err := repo.GetProviderByID("user123")
+	provider, err := repo.GetProviderByID(context.Background(), "user123")
 	assert.NoError(t, err)
 	assert.NotNil(t, provider)
 	assert.Equal(t, expectedProvider, *provider)
@@ -441,11 +529,11 @@ func TestGetProviderByID_NotFound(t *testing.T) {
 	repo := repository.NewServiceProviderRepository(db)
 
 	// Expect the query to return no rows
-	query := regexp.QuoteMeta("SELECT user_id, rating, availability, is_active FROM service_providers WHERE user_id = ?")
+	query := regexp.QuoteMeta("SELECT user_id, rating, availability, is_active, verification_status, verification_note FROM service_providers WHERE user_id = ?")
 	mock.ExpectQuery(query).WithArgs("user123").WillReturnError(sql.ErrNoRows)
 
 	// Call the function
-	provider, err := repo.GetProviderByID("user123")
+	provider, err := repo.GetProviderByID(context.Background(), "user123")
 
 	// Assert the provider was not found
 	assert.Nil(t, provider)
@@ -483,7 +571,7 @@ func TestGetProvidersByServiceType_Success(t *testing.T) {
 	mock.ExpectQuery(query).WithArgs("Plumbing").WillReturnRows(rows)
 
 	// Call the function
-	providers, err := repo.GetProvidersByServiceType("Plumbing")
+	providers, err := repo.GetProvidersByServiceType(context.Background(), "Plumbing")
 	assert.NoError(t, err)
 	assert.Len(t, providers, 2)
 	assert.Equal(t, expectedProviders, providers)
@@ -512,13 +600,13 @@ func TestUpdateServiceProvider_Success(t *testing.T) {
 	// Expect the update query
 	query := regexp.QuoteMeta(`
 		UPDATE service_providers
-		SET rating = ?, availability = ?, is_active = ?
+		SET availability = ?, is_active = ?
 		WHERE user_id = ?`)
-	mock.ExpectExec(query).WithArgs(provider.Rating, provider.Availability, provider.IsActive, provider.User.ID).
+	mock.ExpectExec(query).WithArgs(provider.Availability, provider.IsActive, provider.User.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	// Call the function
-	err = repo.UpdateServiceProvider(provider)
+	err = repo.UpdateServiceProvider(context.Background(), provider)
 	assert.NoError(t, err)
 
 	// Ensure all expectations were met
@@ -546,7 +634,7 @@ func TestGetProviderByServiceID_Success(t *testing.T) {
 	mock.ExpectQuery(query).WithArgs("service1").WillReturnRows(rows)
 
 	// Call the method
-	provider, err := repo.GetProviderByServiceID("service1")
+	provider, err := repo.GetProviderByServiceID(context.Background(), "service1")
 
 	// Assertions
 	require.NoError(t, err)
@@ -577,7 +665,7 @@ func TestGetProviderByServiceID_ProviderNotFound(t *testing.T) {
 	mock.ExpectQuery(query).WithArgs("service1").WillReturnRows(sqlmock.NewRows([]string{}))
 
 	// Call the method
-	provider, err := repo.GetProviderByServiceID("service1")
+	provider, err := repo.GetProviderByServiceID(context.Background(), "service1")
 
 	// Assertions
 	assert.Nil(t, provider)
@@ -604,7 +692,7 @@ func TestGetProviderByServiceID_QueryError(t *testing.T) {
 	mock.ExpectQuery(query).WithArgs("service1").WillReturnError(errors.New("query error"))
 
 	// Call the method
-	provider, err := repo.GetProviderByServiceID("service1")
+	provider, err := repo.GetProviderByServiceID(context.Background(), "service1")
 
 	// Assertions
 	assert.Nil(t, provider)
@@ -625,7 +713,7 @@ func TestGetProviderDetailByID_ProviderNotFound(t *testing.T) {
 		WithArgs(providerID).
 		WillReturnRows(sqlmock.NewRows([]string{}))
 
-	provider, err := repo.GetProviderDetailByID(providerID)
+	provider, err := repo.GetProviderDetailByID(context.Background(), providerID)
 	assert.Nil(t, provider)
 	assert.EqualError(t, err, "provider not found")
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -644,9 +732,76 @@ func TestGetProviderDetailByID_QueryError(t *testing.T) {
 		WithArgs(providerID).
 		WillReturnError(errors.New("query error"))
 
-	provider, err := repo.GetProviderDetailByID(providerID)
+	provider, err := repo.GetProviderDetailByID(context.Background(), providerID)
 
 	assert.Nil(t, provider)
 	assert.EqualError(t, err, "query error")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestServiceProviderRepository_GetReviewsByHouseholderIDAndClearComments(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceProviderRepository(db)
+	rows := reviewRows().
+		AddRow("rv1", "p1", "s1", "h1", "r1", "Cleaning", 4.0, "Tidy work", []byte("2024-06-01 10:00:00"), []byte("2024-06-02 09:00:00"),
+			nil, nil, true, "Abusive", "admin1", []byte("2024-06-03 08:00:00"))
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE householder_id = ?")).WithArgs("h1").WillReturnRows(rows)
+	mock.ExpectQuery("FROM review_scores").WillReturnRows(sqlmock.NewRows([]string{"review_id", "criterion", "score"}))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reviews SET comments = '' WHERE householder_id = ?")).WithArgs("h1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reviews SET reply = '' WHERE provider_id = ? AND reply IS NOT NULL")).WithArgs("h1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reviews, err := repo.GetReviewsByHouseholderID(context.Background(), "h1")
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 1)
+	assert.Equal(t, "Tidy work", reviews[0].Comments)
+	assert.True(t, reviews[0].Hidden)
+	assert.Equal(t, "Abusive", reviews[0].ModerationNote)
+	assert.NoError(t, repo.ClearReviewComments(context.Background(), "h1"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveReviewReply_OnlyOnce(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceProviderRepository(db)
+	replyDate := time.Now()
+	query := regexp.QuoteMeta("UPDATE reviews SET reply = ?, reply_date = ? WHERE id = ? AND provider_id = ? AND reply IS NULL")
+	mock.ExpectExec(query).WithArgs("Thanks", replyDate, "review123", "provider123").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs("Thanks again", replyDate, "review123", "provider123").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.SaveReviewReply(context.Background(), "review123", "provider123", "Thanks", replyDate))
+	assert.ErrorIs(t, repo.SaveReviewReply(context.Background(), "review123", "provider123", "Thanks again", replyDate), model.ErrAlreadyReplied)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateReviewModerationAndGetHiddenReviews(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceProviderRepository(db)
+	moderatedAt := time.Now()
+	review := model.Review{ID: "review123", Hidden: true, ModerationNote: "Abusive", ModeratedBy: "admin1", ModeratedAt: &moderatedAt}
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE reviews SET hidden = ?, moderation_note = ?, moderated_by = ?, moderated_at = ? WHERE id = ?")).
+		WithArgs(true, "Abusive", "admin1", &moderatedAt, "review123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("FROM reviews WHERE hidden = TRUE ORDER BY moderated_at DESC, id")).
+		WillReturnRows(reviewRows().AddRow("review123", "provider123", "service123", "householder123", nil, "", 1.0, "Awful", []byte("2024-06-01 10:00:00"), nil,
+			nil, nil, true, "Abusive", "admin1", []byte("2024-06-03 08:00:00")))
+	mock.ExpectQuery("FROM review_scores").WillReturnRows(sqlmock.NewRows([]string{"review_id", "criterion", "score"}))
+
+	assert.NoError(t, repo.UpdateReviewModeration(context.Background(), review))
+	hidden, err := repo.GetHiddenReviews(context.Background())
+	assert.NoError(t, err)
+	require.Len(t, hidden, 1)
+	assert.Equal(t, "admin1", hidden[0].ModeratedBy)
+	assert.NotNil(t, hidden[0].ModeratedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,6 +1,7 @@
 package repository_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
@@ -26,7 +27,7 @@ func TestGetAllServices(t *testing.T) {
 	mock.ExpectQuery("SELECT id, name, description, price, provider_id, category FROM services").
 		WillReturnRows(rows)
 
-	services, err := repo.GetAllServices()
+	services, err := repo.GetAllServices(context.Background(), model.QueryOptions{})
 
 	assert.NoError(t, err)
 	assert.Len(t, services, 2)
@@ -34,6 +35,29 @@ func TestGetAllServices(t *testing.T) {
 	assert.Equal(t, "", services[1].ProviderID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetAllServices_CategoryFilterAndPage(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRepository(db)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "description", "price", "provider_id", "category"}).
+		AddRow("3", "Deep Clean", "Whole house", 300.0, "provider123", "Cleaning")
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, description, price, provider_id, category FROM services WHERE category = ? ORDER BY price DESC, id DESC LIMIT ? OFFSET ?")).
+		WithArgs("Cleaning", 10, 20).
+		WillReturnRows(rows)
+
+	opts := model.QueryOptions{Category: "Cleaning", SortBy: "price", Descending: true, Limit: 10}
+	services, err := repo.GetAllServices(context.Background(), opts.NextPage().NextPage())
+
+	assert.NoError(t, err)
+	assert.Len(t, services, 1)
+	assert.Equal(t, "Cleaning", services[0].Category)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 func TestGetServiceByID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	assert.NoError(t, err)
@@ -49,7 +73,7 @@ func TestGetServiceByID(t *testing.T) {
 		WithArgs(serviceID).
 		WillReturnRows(row)
 
-	service, err := repo.GetServiceByID(serviceID)
+	service, err := repo.GetServiceByID(context.Background(), serviceID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "Service A", service.Name)
@@ -76,7 +100,7 @@ func TestSaveService(t *testing.T) {
 		WithArgs(service.ID, service.Name, service.Description, service.Price, service.ProviderID, service.Category).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.SaveService(service)
+	err = repo.SaveService(context.Background(), service)
 
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -94,7 +118,7 @@ func TestRemoveService(t *testing.T) {
 		WithArgs(serviceID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
-	err = repo.RemoveService(serviceID)
+	err = repo.RemoveService(context.Background(), serviceID)
 
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -115,7 +139,7 @@ func TestGetServiceByProviderID(t *testing.T) {
 		WithArgs(providerID).
 		WillReturnRows(rows)
 
-	services, err := repo.GetServiceByProviderID(providerID)
+	services, err := repo.GetServiceByProviderID(context.Background(), providerID)
 
 	assert.NoError(t, err)
 	assert.Len(t, services, 2)
@@ -151,7 +175,7 @@ func TestSaveAllServices_Success(t *testing.T) {
 	mock.ExpectCommit()
 
 	// Call the function
-	err = repo.SaveAllServices(services)
+	err = repo.SaveAllServices(context.Background(), services)
 	assert.NoError(t, err)
 
 	// Ensure all expectations were met
@@ -181,7 +205,7 @@ func TestSaveAllServices_FailureOnPrepare(t *testing.T) {
 	mock.ExpectRollback()
 
 	// Call the function
-	err = repo.SaveAllServices(services)
+	err = repo.SaveAllServices(context.Background(), services)
 	assert.Error(t, err)
 	assert.Equal(t, sql.ErrConnDone, err)
 
@@ -216,7 +240,7 @@ func TestSaveAllServices_FailureOnExec(t *testing.T) {
 	mock.ExpectRollback()
 
 	// Call the function
-	err = repo.SaveAllServices(services)
+	err = repo.SaveAllServices(context.Background(), services)
 	assert.Error(t, err)
 	assert.Equal(t, sql.ErrNoRows, err)
 
@@ -249,7 +273,7 @@ func TestGetServiceByName_Success(t *testing.T) {
 	mock.ExpectQuery(query).WithArgs("Plumbing").WillReturnRows(rows)
 
 	// Call the function
-	actualService, err := repo.GetServiceByName("Plumbing")
+	actualService, err := repo.GetServiceByName(context.Background(), "Plumbing")
 	assert.NoError(t, err)
 	assert.NotNil(t, actualService)
 
@@ -274,7 +298,7 @@ func TestGetServiceByName_NoRows(t *testing.T) {
 	mock.ExpectQuery(query).WithArgs("NonExistingService").WillReturnError(sql.ErrNoRows)
 
 	// Call the function
-	actualService, err := repo.GetServiceByName("NonExistingService")
+	actualService, err := repo.GetServiceByName(context.Background(), "NonExistingService")
 
 	// Assert that the service was not found and the error is correct
 	assert.Nil(t, actualService)
@@ -298,7 +322,7 @@ func TestGetServiceByName_QueryError(t *testing.T) {
 	mock.ExpectQuery(query).WithArgs("Plumbing").WillReturnError(errors.New("database error"))
 
 	// Call the function
-	actualService, err := repo.GetServiceByName("Plumbing")
+	actualService, err := repo.GetServiceByName(context.Background(), "Plumbing")
 
 	// Assert that an error was returned
 	assert.Nil(t, actualService)
@@ -332,7 +356,7 @@ func TestUpdateService_Success(t *testing.T) {
 	}
 
 	// Call the method
-	err = repo.UpdateService("provider1", service)
+	err = repo.UpdateService(context.Background(), "provider1", service)
 
 	// Assert the expectations
 	assert.NoError(t, err)
@@ -363,7 +387,7 @@ func TestUpdateService_NoRowsAffected(t *testing.T) {
 	}
 
 	// Call the method
-	err = repo.UpdateService("provider1", service)
+	err = repo.UpdateService(context.Background(), "provider1", service)
 
 	// Assert the expectations
 	assert.EqualError(t, err, "The service ID may not exist.")
@@ -393,7 +417,7 @@ func TestUpdateService_Error(t *testing.T) {
 	}
 
 	// Call the method
-	err = repo.UpdateService("provider1", service)
+	err = repo.UpdateService(context.Background(), "provider1", service)
 
 	// Assert the expectations
 	assert.EqualError(t, err, "some database error")
@@ -414,7 +438,7 @@ func TestRemoveServiceByProviderID_Success(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Call the method
-	err = repo.RemoveServiceByProviderID("provider1", "service1")
+	err = repo.RemoveServiceByProviderID(context.Background(), "provider1", "service1")
 
 	// Assert the expectations
 	assert.NoError(t, err)
@@ -437,7 +461,7 @@ func TestRemoveServiceByProviderID_NoRowsAffected(t *testing.T) {
 		WillReturnResult(sqlmock.NewResult(1, 0))
 
 	// Call the method
-	err = repo.RemoveServiceByProviderID("provider1", "service1")
+	err = repo.RemoveServiceByProviderID(context.Background(), "provider1", "service1")
 
 	// Assert the expectations
 	assert.EqualError(t, err, "Invalid service ID")
@@ -459,9 +483,26 @@ func TestRemoveServiceByProviderID_Error(t *testing.T) {
 		WillReturnError(errors.New("some database error"))
 
 	// Call the method
-	err = repo.RemoveServiceByProviderID("provider1", "service1")
+	err = repo.RemoveServiceByProviderID(context.Background(), "provider1", "service1")
 
 	// Assert the expectations
 	assert.EqualError(t, err, "some database error")
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestRenameCategory(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRepository(db)
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE services SET category = ? WHERE category = ?")).
+		WithArgs("plumbing", "plumber").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err = repo.RenameCategory(context.Background(), "plumber", "plumbing")
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
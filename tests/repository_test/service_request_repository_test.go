@@ -1,10 +1,13 @@
 package repository_test
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"regexp"
 	"serviceNest/model"
 	"serviceNest/repository"
 	"testing"
@@ -41,12 +44,12 @@ func TestSaveServiceRequest(t *testing.T) {
 
 	// Expecting a query that inserts the service request into the database
 	mock.ExpectExec("INSERT INTO service_requests").
-		WithArgs(request.ID, request.HouseholderID, request.HouseholderName, request.HouseholderAddress, request.ServiceID, request.RequestedTime, request.ScheduledTime, request.Status, request.ApproveStatus).
+		WithArgs(request.ID, request.HouseholderID, request.HouseholderName, request.HouseholderAddress, request.ServiceID, request.RequestedTime, request.ScheduledTime, request.Status, request.ApproveStatus, request.TargetProviderID).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Initialize the repository and call SaveServiceRequest
 	repo := repository.NewServiceRequestRepository(db)
-	err = repo.SaveServiceRequest(request)
+	err = repo.SaveServiceRequest(context.Background(), request)
 	if err != nil {
 		t.Errorf("expected no error, but got: %v", err)
 	}
@@ -68,20 +71,24 @@ func TestGetServiceRequestByID(t *testing.T) {
 	householderAddress := "123 Street"
 
 	row := sqlmock.NewRows([]string{"id", "householder_id", "householder_name", "householder_address", "service_id",
-		"service_name", "requested_time", "scheduled_time", "status", "approve_status"}).
+		"service_name", "requested_time", "scheduled_time", "status", "approve_status", "version", "target_provider_id"}).
 		AddRow(requestID, &householderID, "John Doe", &householderAddress, "service123", "Service A",
-			time.Now(), time.Now().Add(24*time.Hour), "Pending", false)
+			time.Now(), time.Now().Add(24*time.Hour), "Pending", false, 3, "provider1")
 
 	mock.ExpectQuery("SELECT sr.id, sr.householder_id").
 		WithArgs(requestID).
 		WillReturnRows(row)
 
-	request, err := repo.GetServiceRequestByID(requestID)
+	request, err := repo.GetServiceRequestByID(context.Background(), requestID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, "request123", request.ID)
 	assert.NotNil(t, request.HouseholderID)
 	assert.Equal(t, "householder123", *request.HouseholderID)
+	assert.Equal(t, 3, request.Version)
+	if assert.NotNil(t, request.TargetProviderID) {
+		assert.Equal(t, "provider1", *request.TargetProviderID)
+	}
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -99,11 +106,11 @@ func TestGetServiceRequestsByHouseholderID(t *testing.T) {
 	// Mock rows returned by the query
 	rows := sqlmock.NewRows([]string{
 		"id", "householder_id", "householder_name", "householder_address", "service_id",
-		"requested_time", "scheduled_time", "status", "approve_status", "service_provider_id",
+		"requested_time", "scheduled_time", "status", "approve_status", "target_provider_id", "service_provider_id",
 		"name", "contact", "address", "price", "rating", "approve",
 	}).
 		AddRow(1, "1001", "John Doe", "123 Main St", "2001", []byte("2024-09-01 12:00:00"),
-			[]byte("2024-09-02 14:00:00"), "Pending", true, "3001", "Provider 1",
+			[]byte("2024-09-02 14:00:00"), "Pending", true, nil, "3001", "Provider 1",
 			"1234567890", "456 Provider St", "100.00", 4.5, true)
 
 	// Set up expectation for the query
@@ -112,7 +119,7 @@ func TestGetServiceRequestsByHouseholderID(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Call the method
-	requests, err := repo.GetServiceRequestsByHouseholderID("1001")
+	requests, err := repo.GetServiceRequestsByHouseholderID(context.Background(), "1001", model.QueryOptions{})
 
 	// Assert that no error occurred
 	assert.NoError(t, err)
@@ -146,25 +153,49 @@ func TestUpdateServiceRequest(t *testing.T) {
 		ScheduledTime:      time.Now().Add(24 * time.Hour),
 		Status:             "Pending",
 		ApproveStatus:      false,
+		Version:            2,
 	}
 
 	// Mock the Exec query for the update operation
 	mock.ExpectExec("UPDATE service_requests").
 		WithArgs(request.HouseholderID, request.HouseholderName, request.HouseholderAddress,
 			request.ServiceID, request.RequestedTime, request.ScheduledTime, request.Status,
-			request.ApproveStatus, request.ID).
+			request.ApproveStatus, request.ID, 2).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Call the method
-	err = repo.UpdateServiceRequest(request)
+	err = repo.UpdateServiceRequest(context.Background(), request)
 
-	// Assert that no error occurred
+	// Assert that no error occurred and the in-memory version follows the stored one
 	assert.NoError(t, err)
+	assert.Equal(t, 3, request.Version)
 
 	// Ensure all expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestUpdateServiceRequest_VersionConflict(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRequestRepository(db)
+	request := &model.ServiceRequest{ID: "1001", Status: "Accepted", Version: 1}
+
+	// Another writer already bumped the version, so the conditional update matches no row
+	mock.ExpectExec("UPDATE service_requests").
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), "Accepted", false, "1001", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	err = repo.UpdateServiceRequest(context.Background(), request)
+
+	assert.True(t, errors.Is(err, model.ErrConflict))
+	assert.EqualError(t, err, "service request 1001 was modified by another user, please refresh and try again")
+	assert.Equal(t, 1, request.Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetAllServiceRequests(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -177,12 +208,12 @@ func TestGetAllServiceRequests(t *testing.T) {
 	// Mock rows
 	rows := sqlmock.NewRows([]string{
 		"id", "householder_id", "householder_name", "householder_address", "service_id",
-		"requested_time", "scheduled_time", "status", "approve_status",
+		"requested_time", "scheduled_time", "status", "approve_status", "target_provider_id",
 		"service_provider_id", "name", "contact", "address", "price", "rating", "approve",
 	}).
 		AddRow(1, "1001", "John Doe", "123 Main St", "2001",
 			[]byte("2024-09-01 12:00:00"), []byte("2024-09-02 14:00:00"),
-			"Pending", true, "3001", "Provider 1", "1234567890",
+			"Pending", true, nil, "3001", "Provider 1", "1234567890",
 			"456 Provider St", "100.00", 4.5, true)
 
 	// Expect the query to return the rows
@@ -190,7 +221,7 @@ func TestGetAllServiceRequests(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Call the method
-	requests, err := repo.GetAllServiceRequests()
+	requests, err := repo.GetAllServiceRequests(context.Background(), model.QueryOptions{})
 
 	// Assert no error and check result
 	assert.NoError(t, err)
@@ -201,6 +232,79 @@ func TestGetAllServiceRequests(t *testing.T) {
 	// Ensure expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+func TestGetAllServiceRequests_FiltersSortAndPageInSQL(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRequestRepository(db)
+
+	from := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	opts := model.QueryOptions{
+		Status:     "Pending",
+		From:       from,
+		To:         to,
+		Category:   "Plumbing",
+		ProviderID: "3001",
+		OfferedTo:  "3001",
+		SortBy:     "scheduled_time",
+		Descending: true,
+		Limit:      2,
+		Offset:     4,
+	}
+
+	rows := sqlmock.NewRows([]string{
+		"id", "householder_id", "householder_name", "householder_address", "service_id",
+		"requested_time", "scheduled_time", "status", "approve_status", "target_provider_id",
+		"service_provider_id", "name", "contact", "address", "price", "rating", "approve",
+	}).
+		AddRow("1", "1001", "John Doe", "123 Main St", "2001", []byte("2024-09-01 12:00:00"), []byte("2024-09-03 14:00:00"),
+			"Pending", false, nil, "3001", "Provider 1", "1234567890", "456 Provider St", "100.00", 4.5, false).
+		AddRow("1", "1001", "John Doe", "123 Main St", "2001", []byte("2024-09-01 12:00:00"), []byte("2024-09-03 14:00:00"),
+			"Pending", false, nil, "3002", "Provider 2", "0987654321", "789 Provider St", "120.00", 4.0, false).
+		AddRow("2", "1002", "Jane Doe", "9 High St", "2002", []byte("2024-09-02 12:00:00"), []byte("2024-09-02 14:00:00"),
+			"Pending", false, nil, nil, nil, nil, nil, nil, nil, nil)
+
+	mock.ExpectQuery(regexp.QuoteMeta("FROM (SELECT sr.* FROM service_requests AS sr WHERE sr.status = ? AND sr.requested_time >= ? AND sr.requested_time < ? AND EXISTS (SELECT 1 FROM services AS s WHERE s.id = sr.service_id AND s.category = ?) AND EXISTS (SELECT 1 FROM service_provider_details AS p WHERE p.service_request_id = sr.id AND p.service_provider_id = ?) AND (sr.target_provider_id IS NULL OR sr.target_provider_id = ?) ORDER BY sr.scheduled_time DESC, sr.id DESC LIMIT ? OFFSET ?) AS sr")).
+		WithArgs("Pending", from, to, "Plumbing", "3001", "3001", 2, 4).
+		WillReturnRows(rows)
+
+	requests, err := repo.GetAllServiceRequests(context.Background(), opts)
+
+	assert.NoError(t, err)
+	// Both provider rows of request 1 are merged, so the page holds two requests
+	assert.Len(t, requests, 2)
+	assert.Len(t, requests[0].ProviderDetails, 2)
+	assert.Equal(t, "Provider 2", requests[0].ProviderDetails[1].Name)
+	assert.Empty(t, requests[1].ProviderDetails)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetAllServiceRequests_RejectsUnknownSortKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRequestRepository(db)
+
+	_, err = repo.GetAllServiceRequests(context.Background(), model.QueryOptions{SortBy: "id; DROP TABLE users"})
+
+	assert.EqualError(t, err, `unsupported sort key "id; DROP TABLE users"`)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetServiceRequestByHouseholderID_RejectsNegativePage(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRequestRepository(db)
+
+	_, err = repo.GetServiceRequestsByHouseholderID(context.Background(), "1001", model.QueryOptions{Limit: 5, Offset: -5})
+	assert.EqualError(t, err, "page size and offset must not be negative")
+}
+
 func TestGetServiceRequestsByProviderID(t *testing.T) {
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -227,7 +331,7 @@ func TestGetServiceRequestsByProviderID(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Call the method
-	requests, err := repo.GetServiceRequestsByProviderID("3001")
+	requests, err := repo.GetServiceRequestsByProviderID(context.Background(), "3001")
 
 	// Assert no error and check result
 	assert.NoError(t, err)
@@ -252,13 +356,13 @@ func TestGetServiceProviderByRequestID(t *testing.T) {
 	// Mock the row returned by the query
 	rows := sqlmock.NewRows([]string{
 		"id", "householder_id", "householder_name", "householder_address", "service_id",
-		"requested_time", "scheduled_time", "status", "approve_status",
-		"service_provider_id", "name", "contact", "address", "price", "rating", "approve",
+		"requested_time", "scheduled_time", "status", "approve_status", "version",
+		"service_provider_id", "name", "contact", "address", "price", "rating", "approve", "version",
 	}).
 		AddRow("1001", "householder-123", "John Doe", "123 Main St", "service-456",
 			[]byte("2024-09-01 12:00:00"), []byte("2024-09-02 14:00:00"),
-			"Pending", true, "provider-789", "Provider Name", "1234567890",
-			"456 Provider St", "100.00", 4.5, true)
+			"Pending", true, 1, "provider-789", "Provider Name", "1234567890",
+			"456 Provider St", "100.00", 4.5, true, 1)
 
 	// Set up expectation for the query
 	mock.ExpectQuery("SELECT sr.id, sr.householder_id").
@@ -266,7 +370,7 @@ func TestGetServiceProviderByRequestID(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Call the method
-	result, err := repo.GetServiceProviderByRequestID("1001", "provider-789")
+	result, err := repo.GetServiceProviderByRequestID(context.Background(), "1001", "provider-789")
 
 	// Assert that no error occurred
 	assert.NoError(t, err)
@@ -298,7 +402,7 @@ func TestGetServiceProviderByRequestID_NoRows(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows(nil)) // Empty result set
 
 	// Call the method
-	result, err := repo.GetServiceProviderByRequestID("1001", "provider-789")
+	result, err := repo.GetServiceProviderByRequestID(context.Background(), "1001", "provider-789")
 
 	// Assert that error occurred due to no rows found
 	assert.Error(t, err)
@@ -320,18 +424,18 @@ func TestGetServiceProviderByRequestID_ParseRequestedTimeError(t *testing.T) {
 	// Mock the row with invalid time format
 	rows := sqlmock.NewRows([]string{
 		"id", "householder_id", "householder_name", "householder_address", "service_id",
-		"requested_time", "scheduled_time", "status", "approve_status",
-		"service_provider_id", "name", "contact", "address", "price", "rating", "approve",
+		"requested_time", "scheduled_time", "status", "approve_status", "version",
+		"service_provider_id", "name", "contact", "address", "price", "rating", "approve", "version",
 	}).AddRow("1001", "householder-123", "John Doe", "123 Main St", "service-456",
 		[]byte("invalid-time"), []byte("2024-09-02 14:00:00"),
-		"Pending", true, "provider-789", "Provider Name", "1234567890",
-		"456 Provider St", "100.00", 4.5, true)
+		"Pending", true, 1, "provider-789", "Provider Name", "1234567890",
+		"456 Provider St", "100.00", 4.5, true, 1)
 
 	mock.ExpectQuery("SELECT sr.id, sr.householder_id").
 		WithArgs("provider-789", "1001").
 		WillReturnRows(rows)
 
-	result, err := repo.GetServiceProviderByRequestID("1001", "provider-789")
+	result, err := repo.GetServiceProviderByRequestID(context.Background(), "1001", "provider-789")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -351,18 +455,18 @@ func TestGetServiceProviderByRequestID_ParseScheduledTimeError(t *testing.T) {
 	// Mock the row with invalid time format
 	rows := sqlmock.NewRows([]string{
 		"id", "householder_id", "householder_name", "householder_address", "service_id",
-		"requested_time", "scheduled_time", "status", "approve_status",
-		"service_provider_id", "name", "contact", "address", "price", "rating", "approve",
+		"requested_time", "scheduled_time", "status", "approve_status", "version",
+		"service_provider_id", "name", "contact", "address", "price", "rating", "approve", "version",
 	}).AddRow("1001", "householder-123", "John Doe", "123 Main St", "service-456",
 		[]byte("2024-09-01 12:00:00"), []byte("invalid-time"),
-		"Pending", true, "provider-789", "Provider Name", "1234567890",
-		"456 Provider St", "100.00", 4.5, true)
+		"Pending", true, 1, "provider-789", "Provider Name", "1234567890",
+		"456 Provider St", "100.00", 4.5, true, 1)
 
 	mock.ExpectQuery("SELECT sr.id, sr.householder_id").
 		WithArgs("provider-789", "1001").
 		WillReturnRows(rows)
 
-	result, err := repo.GetServiceProviderByRequestID("1001", "provider-789")
+	result, err := repo.GetServiceProviderByRequestID(context.Background(), "1001", "provider-789")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -384,7 +488,7 @@ func TestGetServiceProviderByRequestID_NoRowsReturned(t *testing.T) {
 		WithArgs("provider-789", "1001").
 		WillReturnRows(sqlmock.NewRows([]string{}))
 
-	result, err := repo.GetServiceProviderByRequestID("1001", "provider-789")
+	result, err := repo.GetServiceProviderByRequestID(context.Background(), "1001", "provider-789")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -406,7 +510,7 @@ func TestGetServiceProviderByRequestID_QueryError(t *testing.T) {
 		WithArgs("provider-789", "1001").
 		WillReturnError(fmt.Errorf("query error"))
 
-	result, err := repo.GetServiceProviderByRequestID("1001", "provider-789")
+	result, err := repo.GetServiceProviderByRequestID(context.Background(), "1001", "provider-789")
 
 	assert.Error(t, err)
 	assert.Nil(t, result)
@@ -428,7 +532,7 @@ func TestGetServiceRequestsByProviderID_QueryError(t *testing.T) {
 		WillReturnError(fmt.Errorf("query error"))
 
 	// Call the method
-	requests, err := repo.GetServiceRequestsByProviderID("3001")
+	requests, err := repo.GetServiceRequestsByProviderID(context.Background(), "3001")
 
 	// Assert error and check no results
 	assert.Error(t, err)
@@ -461,7 +565,7 @@ func TestGetServiceRequestsByProviderID_ParseRequestedTimeError(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Call the method
-	requests, err := repo.GetServiceRequestsByProviderID("3001")
+	requests, err := repo.GetServiceRequestsByProviderID(context.Background(), "3001")
 
 	// Assert error and check no results
 	assert.Error(t, err)
@@ -494,7 +598,7 @@ func TestGetServiceRequestsByProviderID_ParseScheduledTimeError(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Call the method
-	requests, err := repo.GetServiceRequestsByProviderID("3001")
+	requests, err := repo.GetServiceRequestsByProviderID(context.Background(), "3001")
 
 	// Assert error and check no results
 	assert.Error(t, err)
@@ -517,10 +621,81 @@ func TestGetServiceRequestsByProviderID_NoRowsReturned(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{}))
 
 	// Call the method
-	requests, err := repo.GetServiceRequestsByProviderID("3001")
+	requests, err := repo.GetServiceRequestsByProviderID(context.Background(), "3001")
 
 	// Assert no error and check empty result
 	assert.NoError(t, err)
 	assert.Len(t, requests, 0)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestAnonymiseHouseholderAndProviderDetails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRequestRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE service_requests SET householder_name = ?, householder_address = NULL, version = version + 1 WHERE householder_id = ?")).
+		WithArgs(model.ErasedName, "h1").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE service_provider_details SET name = ?, contact = '', address = '', version = version + 1 WHERE service_provider_id = ?")).
+		WithArgs(model.ErasedName, "p1").WillReturnResult(sqlmock.NewResult(0, 2))
+
+	assert.NoError(t, repo.AnonymiseHouseholder(context.Background(), "h1", model.ErasedName))
+	assert.NoError(t, repo.AnonymiseProviderDetails(context.Background(), "p1", model.ErasedName))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountCompletedJobs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRequestRepository(db)
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE spd.service_provider_id = ? AND spd.approve = 1 AND sr.status = ?")).
+		WithArgs("p1", model.RequestCompleted).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(4))
+
+	count, err := repo.CountCompletedJobs(context.Background(), "p1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOpenToAllProviders(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRequestRepository(db)
+	query := regexp.QuoteMeta("WHERE id = ? AND target_provider_id = ? AND status = 'Pending'")
+	mock.ExpectExec(query).WithArgs("r1", "p1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs("r1", "p1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.OpenToAllProviders(context.Background(), "r1", "p1"))
+	var conflict *model.ConflictError
+	assert.ErrorAs(t, repo.OpenToAllProviders(context.Background(), "r1", "p1"), &conflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReleaseDirectBookings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRequestRepository(db)
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("WHERE target_provider_id = ? AND status = 'Pending' FOR UPDATE")).WithArgs("p1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "householder_id"}).AddRow("r1", "h1").AddRow("r2", nil))
+	mock.ExpectExec(regexp.QuoteMeta("WHERE target_provider_id = ? AND status = 'Pending'")).WithArgs("p1").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	released, err := repo.ReleaseDirectBookings(context.Background(), "p1")
+
+	assert.NoError(t, err)
+	if assert.Len(t, released, 2) {
+		assert.Equal(t, "r1", released[0].ID)
+		assert.Equal(t, "h1", *released[0].HouseholderID)
+		assert.Nil(t, released[1].HouseholderID)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
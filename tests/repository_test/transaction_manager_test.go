@@ -0,0 +1,121 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+func TestWithinTransaction_CommitsOnSuccess(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRequestRepository(db)
+	txManager := repository.NewTransactionManager(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE service_requests")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		return repo.UpdateServiceRequest(ctx, &model.ServiceRequest{ID: "req1", Status: "Accepted"})
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTransaction_RollsBackWhenLaterWriteFails(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	requestRepo := repository.NewServiceRequestRepository(db)
+	providerRepo := repository.NewServiceProviderRepository(db)
+	txManager := repository.NewTransactionManager(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE service_requests")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM service_providers WHERE user_id = ?")).
+		WithArgs("provider1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO service_provider_details")).WillReturnError(errors.New("connection lost"))
+	mock.ExpectRollback()
+
+	err = txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		request := &model.ServiceRequest{ID: "req1", Status: "Accepted", ScheduledTime: time.Now()}
+		if err := requestRepo.UpdateServiceRequest(ctx, request); err != nil {
+			return err
+		}
+		return providerRepo.SaveServiceProviderDetail(ctx, &model.ServiceProviderDetails{ServiceProviderID: "provider1", Price: "150"}, "req1")
+	})
+	assert.EqualError(t, err, "connection lost")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTransaction_RollsBackOnPanic(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	txManager := repository.NewTransactionManager(db)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	assert.Panics(t, func() {
+		txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+			panic("boom")
+		})
+	})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTransaction_NestedCallsJoinOuterTransaction(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewServiceRequestRepository(db)
+	txManager := repository.NewTransactionManager(db)
+
+	// Only one BEGIN/COMMIT pair is expected even though two units of work are nested
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE service_requests")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE service_requests")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		if err := repo.UpdateServiceRequest(ctx, &model.ServiceRequest{ID: "req1"}); err != nil {
+			return err
+		}
+		return txManager.WithinTransaction(ctx, func(ctx context.Context) error {
+			return repo.UpdateServiceRequest(ctx, &model.ServiceRequest{ID: "req2"})
+		})
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestWithinTransaction_BeginFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	txManager := repository.NewTransactionManager(db)
+	mock.ExpectBegin().WillReturnError(errors.New("too many connections"))
+
+	called := false
+	err = txManager.WithinTransaction(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+	assert.EqualError(t, err, "could not start transaction: too many connections")
+	assert.False(t, called)
+}
@@ -0,0 +1,87 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"serviceNest/model"
+	"serviceNest/repository"
+	"testing"
+	"time"
+)
+
+func TestGetTwoFactor(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+	query := regexp.QuoteMeta("FROM user_totp WHERE user_id = ?")
+	rows := sqlmock.NewRows([]string{"user_id", "secret", "enabled", "last_used_step", "created_at", "confirmed_at"}).
+		AddRow("u1", "SECRET", true, 42, []byte("2024-06-01 10:00:00"), []byte("2024-06-01 10:05:00"))
+	mock.ExpectQuery(query).WithArgs("u1").WillReturnRows(rows)
+	mock.ExpectQuery(query).WithArgs("u2").WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+
+	twoFactor, err := repo.GetTwoFactor(context.Background(), "u1")
+	assert.NoError(t, err)
+	assert.True(t, twoFactor.Enabled)
+	assert.Equal(t, int64(42), twoFactor.LastUsedStep)
+	assert.Equal(t, time.Date(2024, 6, 1, 10, 5, 0, 0, time.UTC), *twoFactor.ConfirmedAt)
+
+	_, err = repo.GetTwoFactor(context.Background(), "u2")
+	assert.EqualError(t, err, "two-factor settings not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUseStep(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+	query := regexp.QuoteMeta("UPDATE user_totp SET last_used_step = ? WHERE user_id = ? AND enabled = TRUE AND last_used_step < ?")
+	mock.ExpectExec(query).WithArgs(int64(100), "u1", int64(100)).WillReturnResult(sqlmock.NewResult(0, 1))
+	// The same step again is a replay
+	mock.ExpectExec(query).WithArgs(int64(100), "u1", int64(100)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.UseStep(context.Background(), "u1", 100))
+	assert.ErrorIs(t, repo.UseStep(context.Background(), "u1", 100), model.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplaceRecoveryCodes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+	now := time.Now()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM recovery_codes WHERE user_id = ?")).WithArgs("u1").
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	insert := regexp.QuoteMeta("INSERT INTO recovery_codes (id, user_id, code_hash, created_at) VALUES (?, ?, ?, ?)")
+	mock.ExpectExec(insert).WithArgs("r1", "u1", "h1", now).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(insert).WithArgs("r2", "u1", "h2", now).WillReturnResult(sqlmock.NewResult(1, 1))
+
+	err = repo.ReplaceRecoveryCodes(context.Background(), "u1", []model.RecoveryCode{
+		{ID: "r1", CodeHash: "h1", CreatedAt: now},
+		{ID: "r2", CodeHash: "h2", CreatedAt: now},
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUseRecoveryCode(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewTwoFactorRepository(db)
+	query := regexp.QuoteMeta("UPDATE recovery_codes SET used_at = ? WHERE user_id = ? AND code_hash = ? AND used_at IS NULL")
+	mock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), "u1", "h1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs(sqlmock.AnyArg(), "u1", "h1").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.UseRecoveryCode(context.Background(), "u1", "h1"))
+	assert.EqualError(t, repo.UseRecoveryCode(context.Background(), "u1", "h1"), "recovery code not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
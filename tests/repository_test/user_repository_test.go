@@ -1,14 +1,19 @@
 package repository_test
 
 import (
+	"context"
 	"database/sql"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"regexp"
 	"serviceNest/model"
 	"serviceNest/repository"
 	"testing"
+	"time"
 )
 
+var userRowColumns = []string{"id", "name", "email", "password", "role", "address", "contact", "latitude", "longitude", "email_verified", "status", "status_reason", "status_expires_at"}
+
 func TestSaveUser(t *testing.T) {
 	// Step 1: Create a new mock database connection using sqlmock
 	db, mock, err := sqlmock.New()
@@ -20,7 +25,7 @@ func TestSaveUser(t *testing.T) {
 
 	// Step 3: Define the mock behavior for the INSERT INTO users query
 	mock.ExpectExec("INSERT INTO users").
-		WithArgs("123", "John Doe", "john@example.com", "hashed_password", "Householder", "123 Main St", "1234567890", 12.34, 56.78).
+		WithArgs("123", "John Doe", "john@example.com", "hashed_password", "Householder", "123 Main St", "1234567890", nil, 12.34, 56.78, false).
 		WillReturnResult(sqlmock.NewResult(1, 1)) // Simulate successful insert
 
 	// Step 4: Define the user object that we want to save
@@ -37,7 +42,7 @@ func TestSaveUser(t *testing.T) {
 	}
 
 	// Step 5: Call the SaveUser method from the repository
-	err = repo.SaveUser(user)
+	err = repo.SaveUser(context.Background(), user)
 
 	// Step 6: Assert that no error occurred
 	assert.NoError(t, err)
@@ -57,8 +62,8 @@ func TestGetUserByEmail(t *testing.T) {
 	repo := repository.NewUserRepository(db)
 
 	// Mock row returned by query
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "address", "contact", "latitude", "longitude"}).
-		AddRow("123", "John Doe", "john@example.com", "hashed_password", "user", "123 Main St", "1234567890", 12.34, 56.78)
+	rows := sqlmock.NewRows(userRowColumns).
+		AddRow("123", "John Doe", "john@example.com", "hashed_password", "user", "123 Main St", "1234567890", 12.34, 56.78, true, "Active", nil, nil)
 
 	// Expect the query with the provided email
 	mock.ExpectQuery("SELECT id, name, email, password").
@@ -66,7 +71,7 @@ func TestGetUserByEmail(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Call GetUserByEmail and assert no error
-	user, err := repo.GetUserByEmail("john@example.com")
+	user, err := repo.GetUserByEmail(context.Background(), "john@example.com")
 	assert.NoError(t, err)
 
 	// Assert the user details are as expected
@@ -92,7 +97,7 @@ func TestGetUserByEmail_UserNotFound(t *testing.T) {
 		WillReturnError(sql.ErrNoRows)
 
 	// Call GetUserByEmail and assert the error
-	user, err := repo.GetUserByEmail("john@example.com")
+	user, err := repo.GetUserByEmail(context.Background(), "john@example.com")
 	assert.Error(t, err)
 	assert.Nil(t, user)
 	assert.EqualError(t, err, "user not found")
@@ -111,8 +116,8 @@ func TestUpdateUser(t *testing.T) {
 	repo := repository.NewUserRepository(db)
 
 	// Mock row for existing user check
-	existingUserRows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "address", "contact", "latitude", "longitude"}).
-		AddRow("123", "John Doe", "john@example.com", "hashed_password", "user", "123 Main St", "1234567890", 12.34, 56.78)
+	existingUserRows := sqlmock.NewRows(userRowColumns).
+		AddRow("123", "John Doe", "john@example.com", "hashed_password", "user", "123 Main St", "1234567890", 12.34, 56.78, true, "Active", nil, nil)
 
 	// Expect GetUserByEmail query and return existing user
 	mock.ExpectQuery("SELECT id, name, email, password").
@@ -121,7 +126,7 @@ func TestUpdateUser(t *testing.T) {
 
 	// Mock Exec for updating user
 	mock.ExpectExec("UPDATE users").
-		WithArgs("John Updated", "john@example.com", "new_hashed_password", "admin", "123 New St", "0987654321", 21.43, 65.87, "123").
+		WithArgs("John Updated", "john@example.com", "new_hashed_password", "admin", "123 New St", "0987654321", nil, 21.43, 65.87, false, "123").
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Create updated user
@@ -138,7 +143,7 @@ func TestUpdateUser(t *testing.T) {
 	}
 
 	// Call UpdateUser and assert no error
-	err = repo.UpdateUser(updatedUser)
+	err = repo.UpdateUser(context.Background(), updatedUser)
 	assert.NoError(t, err)
 
 	// Ensure all expectations were met
@@ -155,8 +160,8 @@ func TestGetUserByID(t *testing.T) {
 	repo := repository.NewUserRepository(db)
 
 	// Mock row returned by query
-	rows := sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "address", "contact", "latitude", "longitude"}).
-		AddRow("123", "John Doe", "john@example.com", "hashed_password", "user", "123 Main St", "1234567890", 12.34, 56.78)
+	rows := sqlmock.NewRows(userRowColumns).
+		AddRow("123", "John Doe", "john@example.com", "hashed_password", "user", "123 Main St", "1234567890", 12.34, 56.78, true, "Active", nil, nil)
 
 	// Expect the query with the provided user ID
 	mock.ExpectQuery("SELECT id, name, email, password").
@@ -164,7 +169,7 @@ func TestGetUserByID(t *testing.T) {
 		WillReturnRows(rows)
 
 	// Call GetUserByID and assert no error
-	user, err := repo.GetUserByID("123")
+	user, err := repo.GetUserByID(context.Background(), "123")
 	assert.NoError(t, err)
 
 	// Assert the user details are as expected
@@ -190,7 +195,7 @@ func TestGetUserByID_UserNotFound(t *testing.T) {
 		WillReturnError(sql.ErrNoRows)
 
 	// Call GetUserByID and assert the error
-	user, err := repo.GetUserByID("123")
+	user, err := repo.GetUserByID(context.Background(), "123")
 	assert.Error(t, err)
 	assert.Nil(t, user)
 	assert.EqualError(t, err, "user not found")
@@ -198,3 +203,170 @@ func TestGetUserByID_UserNotFound(t *testing.T) {
 	// Ensure all expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestGetUserByID_QueryTimeout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repository.SetQueryTimeout(20 * time.Millisecond)
+	defer repository.SetQueryTimeout(5 * time.Second)
+
+	repo := repository.NewUserRepository(db)
+
+	// The query takes longer than the configured per-call deadline
+	mock.ExpectQuery("SELECT id, name, email, password").
+		WithArgs("123").
+		WillDelayFor(200 * time.Millisecond).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	start := time.Now()
+	user, err := repo.GetUserByID(context.Background(), "123")
+	assert.Nil(t, user)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 200*time.Millisecond)
+}
+
+func TestGetUserByID_CancelledContext(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+
+	mock.ExpectQuery("SELECT id, name, email, password").
+		WithArgs("123").
+		WillDelayFor(time.Second).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Cancelling the caller's context aborts the in-flight query
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		cancel()
+	}()
+
+	start := time.Now()
+	user, err := repo.GetUserByID(ctx, "123")
+	assert.Nil(t, user)
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestGetUserByID_SuspendedAccount(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+
+	rows := sqlmock.NewRows(userRowColumns).
+		AddRow("123", "John Doe", "john@example.com", "hashed_password", "Householder", "123 Main St", "1234567890", 12.34, 56.78,
+			false, "Suspended", "abusive messages", []byte("2024-06-01 00:00:00"))
+	mock.ExpectQuery("SELECT id, name, email, password").WithArgs("123").WillReturnRows(rows)
+
+	user, err := repo.GetUserByID(context.Background(), "123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.AccountSuspended, user.Status)
+	assert.Equal(t, "abusive messages", user.StatusReason)
+	assert.Equal(t, time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), *user.StatusExpiresAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateAccountStatus(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+	until := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta("UPDATE users SET status = ?, status_reason = ?, status_expires_at = ? WHERE id = ? AND status = ?")
+
+	mock.ExpectExec(query).WithArgs("Suspended", "spam", &until, "123", "Active").WillReturnResult(sqlmock.NewResult(0, 1))
+	// Another admin changed the account in the meantime
+	mock.ExpectExec(query).WithArgs("Banned", "spam", nil, "123", "Active").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.UpdateAccountStatus(context.Background(), "123", model.AccountActive, model.AccountSuspended, "spam", &until))
+	err = repo.UpdateAccountStatus(context.Background(), "123", model.AccountActive, model.AccountBanned, "spam", nil)
+	assert.ErrorIs(t, err, model.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkEmailVerified(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+	query := regexp.QuoteMeta("UPDATE users SET email_verified = TRUE WHERE id = ?")
+	mock.ExpectExec(query).WithArgs("123").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs("missing").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.MarkEmailVerified(context.Background(), "123"))
+	assert.EqualError(t, repo.MarkEmailVerified(context.Background(), "missing"), "user not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdatePassword(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE users SET password = ? WHERE id = ?")).
+		WithArgs("new-hash", "123").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.UpdatePassword(context.Background(), "123", "new-hash"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUpdateRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+	query := regexp.QuoteMeta("UPDATE users SET role = ? WHERE id = ? AND role = ?")
+	mock.ExpectExec(query).WithArgs("Support", "123", "Householder").WillReturnResult(sqlmock.NewResult(0, 1))
+	// Another admin changed the role in the meantime
+	mock.ExpectExec(query).WithArgs("Admin", "123", "Householder").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.UpdateRole(context.Background(), "123", model.RoleHouseholder, model.RoleSupport))
+	assert.ErrorIs(t, repo.UpdateRole(context.Background(), "123", model.RoleHouseholder, model.RoleAdmin), model.ErrConflict)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUsersByRole(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+	rows := sqlmock.NewRows(userRowColumns).
+		AddRow("1", "Ada", "ada@example.com", "hash", "Admin", "", "", 0.0, 0.0, true, "Active", nil, nil).
+		AddRow("2", "Bob", "bob@example.com", "hash", "Admin", "", "", 0.0, 0.0, true, "Active", nil, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("FROM users WHERE role = ? ORDER BY name, id")).WithArgs("Admin").WillReturnRows(rows)
+
+	users, err := repo.GetUsersByRole(context.Background(), model.RoleAdmin)
+	assert.NoError(t, err)
+	assert.Len(t, users, 2)
+	assert.Equal(t, "Bob", users[1].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAnonymiseUser(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	repo := repository.NewUserRepository(db)
+	query := regexp.QuoteMeta("UPDATE users SET name = ?, email = ?, password = '', address = '', contact = ''")
+	mock.ExpectExec(query).WithArgs(model.ErasedName, model.ErasedEmail("123"), "123").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(query).WithArgs(model.ErasedName, model.ErasedEmail("404"), "404").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.NoError(t, repo.AnonymiseUser(context.Background(), "123", model.ErasedName, model.ErasedEmail("123")))
+	assert.EqualError(t, repo.AnonymiseUser(context.Background(), "404", model.ErasedName, model.ErasedEmail("404")), "user not found")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
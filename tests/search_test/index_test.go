@@ -0,0 +1,127 @@
+package search_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/search"
+	"testing"
+)
+
+func doc(id, name, category, description, provider string, rating float64) model.SearchDocument {
+	return model.SearchDocument{
+		Service: model.Service{
+			ID:          id,
+			Name:        name,
+			Category:    category,
+			Description: description,
+			ProviderID:  "provider-" + id,
+		},
+		ProviderName:   provider,
+		ProviderRating: rating,
+	}
+}
+
+func ids(results []model.SearchResult) []string {
+	var out []string
+	for _, result := range results {
+		out = append(out, result.Service.ID)
+	}
+	return out
+}
+
+func newTestIndex() *search.Index {
+	index := search.NewIndex().(*search.Index)
+	index.Index(doc("1", "Pipe Repair", "Plumbing", "Fix leaking pipes and taps", "Mario Rossi", 4.0))
+	index.Index(doc("2", "Deep Cleaning", "Cleaning", "Kitchen and bathroom deep clean", "Sparkle Ltd", 4.5))
+	index.Index(doc("3", "Drain Unblocking", "Plumbing", "Unblock sinks and drains", "Luigi Verdi", 3.0))
+	index.Index(doc("4", "Wiring Check", "Electrical", "Inspect home wiring", "Volt Co", 5.0))
+	return index
+}
+
+func TestSearch_ExactTermAcrossFields(t *testing.T) {
+	index := newTestIndex()
+
+	results := index.Search(model.SearchQuery{Text: "plumbing"})
+
+	assert.ElementsMatch(t, []string{"1", "3"}, ids(results))
+	for _, result := range results {
+		assert.Equal(t, 1.0, result.Relevance)
+		assert.Equal(t, -1.0, result.DistanceKm)
+	}
+	// Equal relevance, the better rated provider comes first
+	assert.Equal(t, "1", results[0].Service.ID)
+	assert.Equal(t, "Mario Rossi", results[0].Service.ProviderName)
+}
+
+func TestSearch_PrefixAndTypoTolerance(t *testing.T) {
+	index := newTestIndex()
+
+	assert.Equal(t, []string{"2"}, ids(index.Search(model.SearchQuery{Text: "clea"})))
+	assert.Equal(t, []string{"4"}, ids(index.Search(model.SearchQuery{Text: "wirnig"})))
+	assert.Equal(t, []string{"3"}, ids(index.Search(model.SearchQuery{Text: "unblcok"})))
+	assert.ElementsMatch(t, []string{"1", "3"}, ids(index.Search(model.SearchQuery{Text: "plumm"})))
+	assert.Equal(t, []string{"1"}, ids(index.Search(model.SearchQuery{Text: "mario"})))
+}
+
+func TestSearch_ShortTermsAreNotFuzzy(t *testing.T) {
+	index := newTestIndex()
+
+	assert.Empty(t, index.Search(model.SearchQuery{Text: "tep"}))
+	assert.Empty(t, index.Search(model.SearchQuery{Text: "the and"}))
+}
+
+func TestSearch_NameMatchOutranksDescriptionMatch(t *testing.T) {
+	index := search.NewIndex()
+	index.Index(doc("a", "Garden Care", "Gardening", "Hedge trimming and lawn mowing", "Green", 3.0))
+	index.Index(doc("b", "Lawn Mowing", "Gardening", "Weekly garden visits", "Green", 3.0))
+
+	results := index.Search(model.SearchQuery{Text: "lawn"})
+
+	assert.Equal(t, []string{"b", "a"}, ids(results))
+	assert.Greater(t, results[0].Relevance, results[1].Relevance)
+}
+
+func TestSearch_MoreMatchedTermsRankHigher(t *testing.T) {
+	index := newTestIndex()
+
+	results := index.Search(model.SearchQuery{Text: "drain plumbing"})
+
+	assert.Equal(t, "3", results[0].Service.ID)
+}
+
+func TestSearch_CloserProviderRanksHigher(t *testing.T) {
+	index := search.NewIndex()
+	near := doc("near", "House Painting", "Painting", "", "Near Painters", 4.0)
+	near.Latitude, near.Longitude = 12.9716, 77.5946
+	far := doc("far", "House Painting", "Painting", "", "Far Painters", 4.0)
+	far.Latitude, far.Longitude = 13.3409, 77.1010
+	index.Index(far)
+	index.Index(near)
+
+	results := index.Search(model.SearchQuery{Text: "painting", Latitude: 12.9720, Longitude: 77.5950, UseLocation: true})
+
+	assert.Equal(t, []string{"near", "far"}, ids(results))
+	assert.Less(t, results[0].DistanceKm, 1.0)
+	assert.Greater(t, results[1].DistanceKm, 50.0)
+}
+
+func TestIndex_ReplaceAndRemove(t *testing.T) {
+	index := newTestIndex()
+
+	index.Index(doc("1", "Tap Fitting", "Plumbing", "New taps", "Mario Rossi", 4.0))
+	assert.Empty(t, index.Search(model.SearchQuery{Text: "pipe"}))
+	assert.Equal(t, []string{"1"}, ids(index.Search(model.SearchQuery{Text: "fitting"})))
+
+	index.Remove("1")
+	index.Remove("unknown")
+	assert.Equal(t, 3, index.Len())
+	assert.Empty(t, index.Search(model.SearchQuery{Text: "fitting"}))
+}
+
+func TestSearch_Limit(t *testing.T) {
+	index := newTestIndex()
+
+	results := index.Search(model.SearchQuery{Text: "plumbing", Limit: 1})
+
+	assert.Len(t, results, 1)
+}
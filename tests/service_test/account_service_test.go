@@ -0,0 +1,247 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/service"
+	"testing"
+	"time"
+)
+
+func newAccountService(ctrl *gomock.Controller) (*service.AccountService, serviceMocks) {
+	m := newServiceMocks(ctrl)
+	accountService := service.NewAccountService(m.userRepo, m.accountStatusRepo, m.providerRepo, m.serviceRequestRepo, m.notificationRepo,
+		m.providerIndexer, auditLog(ctrl), passthroughTransactions(ctrl))
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "admin1").Return(&model.User{ID: "admin1", Role: model.RoleAdmin}, nil).AnyTimes()
+	return accountService, m
+}
+
+// collectNotifications records the recipients and messages of every notification
+func collectNotifications(m serviceMocks) map[string]string {
+	sent := map[string]string{}
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notification model.Notification) error {
+			sent[notification.UserID] = notification.Message
+			return nil
+		}).AnyTimes()
+	return sent
+}
+
+func TestSuspendAccount_Householder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	accountService, m := newAccountService(ctrl)
+	sent := collectNotifications(m)
+	until := time.Now().Add(72 * time.Hour)
+
+	householderID := "h1"
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), householderID).Return(&model.User{ID: householderID, Role: "Householder"}, nil)
+	m.userRepo.EXPECT().UpdateAccountStatus(gomock.Any(), householderID, model.AccountActive, model.AccountSuspended, "spam", &until).Return(nil)
+	m.accountStatusRepo.EXPECT().SaveStatusChange(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, change model.AccountStatusChange) error {
+			assert.Equal(t, model.AccountActive, change.FromStatus)
+			assert.Equal(t, model.AccountSuspended, change.ToStatus)
+			assert.Equal(t, "admin1", change.ChangedBy)
+			assert.Equal(t, &until, change.ExpiresAt)
+			return nil
+		})
+	m.serviceRequestRepo.EXPECT().GetServiceRequestsByHouseholderID(gomock.Any(), householderID, model.QueryOptions{}).Return([]model.ServiceRequest{
+		{ID: "r1", Status: "Pending"},
+		{ID: "r2", Status: "Accepted", ProviderDetails: []model.ServiceProviderDetails{{ServiceProviderID: "p1"}}},
+		{ID: "r3", Status: "Cancelled", ProviderDetails: []model.ServiceProviderDetails{{ServiceProviderID: "p2"}}},
+		{ID: "r4", Status: "Accepted"},
+		{ID: "r5", Status: "Pending"},
+	}, nil)
+	for _, id := range []string{"r1", "r2", "r5"} {
+		m.serviceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), id).Return(&model.ServiceRequest{ID: id, Status: "Pending", Version: 3}, nil)
+	}
+	// Completed after the listing was read
+	m.serviceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "r4").Return(&model.ServiceRequest{ID: "r4", Status: "Completed", Version: 4}, nil)
+	var cancelled []string
+	m.serviceRequestRepo.EXPECT().UpdateServiceRequest(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request *model.ServiceRequest) error {
+			assert.Equal(t, "Cancelled", request.Status)
+			assert.Equal(t, 3, request.Version)
+			if request.ID == "r5" {
+				// Rescheduled by the householder between the read and the update
+				return &model.ConflictError{Entity: "service request", ID: request.ID}
+			}
+			cancelled = append(cancelled, request.ID)
+			return nil
+		}).Times(3)
+
+	err := accountService.SuspendAccount(context.Background(), "admin1", householderID, " spam ", &until)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"r1", "r2"}, cancelled)
+	assert.Contains(t, sent["p1"], "r2")
+	assert.NotContains(t, sent, "p2")
+	assert.Contains(t, sent[householderID], "suspended until")
+}
+
+func TestBanAccount_ProviderCancelsApprovedRequests(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	accountService, m := newAccountService(ctrl)
+	sent := collectNotifications(m)
+
+	householderID := "h1"
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "p1").Return(&model.User{ID: "p1", Role: "ServiceProvider", Status: model.AccountSuspended}, nil)
+	m.userRepo.EXPECT().UpdateAccountStatus(gomock.Any(), "p1", model.AccountSuspended, model.AccountBanned, "fraud", nil).Return(nil)
+	m.accountStatusRepo.EXPECT().SaveStatusChange(gomock.Any(), gomock.Any()).Return(nil)
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(&model.ServiceProvider{User: model.User{ID: "p1"}, IsActive: true}, nil)
+	m.providerRepo.EXPECT().UpdateServiceProvider(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, provider *model.ServiceProvider) error {
+			assert.False(t, provider.IsActive)
+			return nil
+		})
+	m.providerIndexer.EXPECT().ReindexProvider(gomock.Any(), "p1").Return(nil)
+	m.serviceRequestRepo.EXPECT().GetAllServiceRequests(gomock.Any(), model.QueryOptions{ProviderID: "p1"}).Return([]model.ServiceRequest{
+		{ID: "r1", Status: "Accepted", ProviderDetails: []model.ServiceProviderDetails{{ServiceProviderID: "p1", Approve: true}}},
+		// Only quoted, the householder can still pick another provider
+		{ID: "r2", Status: "Accepted", ProviderDetails: []model.ServiceProviderDetails{{ServiceProviderID: "p1"}, {ServiceProviderID: "p2"}}},
+	}, nil)
+	m.serviceRequestRepo.EXPECT().GetServiceProviderByRequestID(gomock.Any(), "r1", "p1").
+		Return(&model.ServiceRequest{ID: "r1", HouseholderID: &householderID, Status: "Accepted", ApproveStatus: true,
+			ProviderDetails: []model.ServiceProviderDetails{{ServiceProviderID: "p1", Approve: true}}}, nil)
+	m.serviceRequestRepo.EXPECT().UpdateServiceRequest(gomock.Any(), gomock.Any()).Return(nil)
+	m.serviceRequestRepo.EXPECT().ReleaseDirectBookings(gomock.Any(), "p1").Return(nil, nil)
+
+	err := accountService.BanAccount(context.Background(), "admin1", "p1", "fraud", nil)
+
+	assert.NoError(t, err)
+	assert.Contains(t, sent[householderID], "r1")
+	assert.Contains(t, sent["p1"], "banned: fraud")
+}
+
+func TestSuspendAccount_ProviderReleasesDirectBookings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	accountService, m := newAccountService(ctrl)
+	sent := collectNotifications(m)
+
+	householderID := "h1"
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "p1").Return(&model.User{ID: "p1", Role: "ServiceProvider"}, nil)
+	m.userRepo.EXPECT().UpdateAccountStatus(gomock.Any(), "p1", model.AccountActive, model.AccountSuspended, "complaints", nil).Return(nil)
+	m.accountStatusRepo.EXPECT().SaveStatusChange(gomock.Any(), gomock.Any()).Return(nil)
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(nil, errors.New("provider not found"))
+	m.serviceRequestRepo.EXPECT().GetAllServiceRequests(gomock.Any(), model.QueryOptions{ProviderID: "p1"}).Return(nil, nil)
+	m.serviceRequestRepo.EXPECT().ReleaseDirectBookings(gomock.Any(), "p1").Return([]model.ServiceRequest{
+		{ID: "r1", HouseholderID: &householderID},
+		// Its householder erased their account, there is nobody to tell
+		{ID: "r2"},
+	}, nil)
+
+	err := accountService.SuspendAccount(context.Background(), "admin1", "p1", "complaints", nil)
+
+	assert.NoError(t, err)
+	assert.Contains(t, sent[householderID], "request r1. It is now open to all providers")
+}
+
+func TestReactivateAccount_Provider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	accountService, m := newAccountService(ctrl)
+	sent := collectNotifications(m)
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "p1").Return(&model.User{ID: "p1", Role: "ServiceProvider", Status: model.AccountBanned}, nil)
+	m.userRepo.EXPECT().UpdateAccountStatus(gomock.Any(), "p1", model.AccountBanned, model.AccountActive, "appeal upheld", nil).Return(nil)
+	m.accountStatusRepo.EXPECT().SaveStatusChange(gomock.Any(), gomock.Any()).Return(nil)
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(&model.ServiceProvider{User: model.User{ID: "p1"}, IsActive: false}, nil)
+	m.providerRepo.EXPECT().UpdateServiceProvider(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, provider *model.ServiceProvider) error {
+			assert.True(t, provider.IsActive)
+			return nil
+		})
+	m.providerIndexer.EXPECT().ReindexProvider(gomock.Any(), "p1").Return(nil)
+
+	err := accountService.ReactivateAccount(context.Background(), "admin1", "p1", "appeal upheld")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Your account has been reactivated.", sent["p1"])
+}
+
+func TestAccountStatusChange_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	accountService, m := newAccountService(ctrl)
+	ctx := context.Background()
+	past := time.Now().Add(-time.Hour)
+
+	assert.EqualError(t, accountService.SuspendAccount(ctx, "admin1", "u1", " ", nil), "a reason is required to change the status of an account")
+	assert.EqualError(t, accountService.SuspendAccount(ctx, "admin1", "u1", "spam", &past), "the expiry must be in the future")
+	assert.EqualError(t, accountService.BanAccount(ctx, "admin1", "admin1", "spam", nil), "you cannot change the status of your own account")
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&model.User{ID: "u1", Role: "Householder"}, nil)
+	assert.EqualError(t, accountService.ReactivateAccount(ctx, "admin1", "u1", "mistake"), "cannot change an account from Active to Active")
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "u2").Return(&model.User{ID: "u2", Role: "Householder", Status: model.AccountDeleted}, nil)
+	assert.EqualError(t, accountService.ReactivateAccount(ctx, "admin1", "u2", "mistake"), "cannot change an account from Deleted to Active")
+
+	// An admin who has been suspended can no longer suspend others
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "admin2").Return(&model.User{ID: "admin2", Role: model.RoleAdmin, Status: model.AccountSuspended}, nil)
+	var inactive *model.AccountInactiveError
+	assert.ErrorAs(t, accountService.SuspendAccount(ctx, "admin2", "u1", "spam", nil), &inactive)
+}
+
+func TestEnsureActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	accountService, m := newAccountService(ctrl)
+	ctx := context.Background()
+	until := time.Date(2099, 1, 2, 15, 4, 0, 0, time.UTC)
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "active").Return(&model.User{ID: "active", Status: model.AccountActive}, nil)
+	assert.NoError(t, accountService.EnsureActive(ctx, "active"))
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "suspended").
+		Return(&model.User{ID: "suspended", Status: model.AccountSuspended, StatusReason: "spam", StatusExpiresAt: &until}, nil)
+	err := accountService.EnsureActive(ctx, "suspended")
+	assert.ErrorIs(t, err, model.ErrAccountInactive)
+	assert.EqualError(t, err, "your account is suspended until 2099-01-02 15:04: spam")
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "deleted").Return(&model.User{ID: "deleted", Status: model.AccountDeleted}, nil)
+	assert.EqualError(t, accountService.EnsureActive(ctx, "deleted"), "your account has been deleted")
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "missing").Return(nil, errors.New("user not found"))
+	assert.EqualError(t, accountService.EnsureActive(ctx, "missing"), "user not found")
+}
+
+func TestEnsureActive_LiftsExpiredSuspension(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	accountService, m := newAccountService(ctrl)
+	sent := collectNotifications(m)
+	expired := time.Now().Add(-time.Minute)
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "h1").
+		Return(&model.User{ID: "h1", Role: "Householder", Status: model.AccountSuspended, StatusExpiresAt: &expired}, nil)
+	m.userRepo.EXPECT().UpdateAccountStatus(gomock.Any(), "h1", model.AccountSuspended, model.AccountActive, "suspension expired", nil).Return(nil)
+	m.accountStatusRepo.EXPECT().SaveStatusChange(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, change model.AccountStatusChange) error {
+			assert.Equal(t, "system", change.ChangedBy)
+			return nil
+		})
+
+	assert.NoError(t, accountService.EnsureActive(context.Background(), "h1"))
+	assert.Contains(t, sent, "h1")
+}
+
+func TestFindAccountsByContact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	accountService, m := newAccountService(ctrl)
+
+	m.userRepo.EXPECT().GetUsersByContact(gomock.Any(), "9876543210").
+		Return([]model.User{{ID: "h1", Contact: "9876543210"}, {ID: "h2", Contact: "9876543210", Status: model.AccountBanned}}, nil)
+
+	users, err := accountService.FindAccountsByContact(context.Background(), "9876543210")
+	assert.NoError(t, err)
+	if assert.Len(t, users, 2) {
+		assert.Equal(t, model.AccountActive, users[0].Status)
+		assert.Equal(t, model.AccountBanned, users[1].Status)
+	}
+}
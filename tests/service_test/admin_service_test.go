@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"serviceNest/model"
@@ -18,7 +19,7 @@ func TestViewReports(t *testing.T) {
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
 
-	adminService := service.NewAdminService(mockServiceRepo, mockServiceRequestRepo, mockUserRepo, mockProviderRepo)
+	adminService := service.NewAdminService(mockServiceRepo, mockServiceRequestRepo, mockUserRepo, mockProviderRepo, auditLog(ctrl), passthroughTransactions(ctrl))
 
 	serviceRequests := []model.ServiceRequest{
 		{ID: "request1"},
@@ -26,10 +27,10 @@ func TestViewReports(t *testing.T) {
 	}
 
 	mockServiceRequestRepo.EXPECT().
-		GetAllServiceRequests().
+		GetAllServiceRequests(gomock.Any(), model.QueryOptions{Status: "Pending", Limit: 10}).
 		Return(serviceRequests, nil)
 
-	result, err := adminService.ViewReports()
+	result, err := adminService.ViewReports(context.Background(), model.QueryOptions{Status: "Pending", Limit: 10})
 	assert.NoError(t, err)
 	assert.Equal(t, serviceRequests, result)
 }
@@ -42,46 +43,31 @@ func TestDeleteService(t *testing.T) {
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
 
-	adminService := service.NewAdminService(mockServiceRepo, mockServiceRequestRepo, mockUserRepo, mockProviderRepo)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+
+	adminService := service.NewAdminService(mockServiceRepo, mockServiceRequestRepo, mockUserRepo, mockProviderRepo, mockAuditRepo, passthroughTransactions(ctrl))
 
 	serviceID := "service1"
 
 	mockServiceRepo.EXPECT().
-		RemoveService(serviceID).
-		Return(nil)
-
-	err := adminService.DeleteService(serviceID)
-	assert.NoError(t, err)
-}
-func TestDeactivateAccount(t *testing.T) {
-	ctrl := gomock.NewController(t)
-	defer ctrl.Finish()
-
-	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
-	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
-	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
-
-	adminService := service.NewAdminService(mockServiceRepo, mockServiceRequestRepo, mockUserRepo, mockProviderRepo)
-
-	userID := "provider1"
-	provider := &model.ServiceProvider{
-		User:     model.User{ID: userID},
-		IsActive: true,
-	}
-
-	mockProviderRepo.EXPECT().
-		GetProviderByID(userID).
-		Return(provider, nil)
-
-	mockProviderRepo.EXPECT().
-		UpdateServiceProvider(provider).
-		Do(func(p *model.ServiceProvider) {
-			assert.False(t, p.IsActive)
-		}).
+		GetServiceByID(gomock.Any(), serviceID).
+		Return(&model.Service{ID: serviceID, Name: "Plumbing", ProviderID: "p1"}, nil)
+	mockServiceRepo.EXPECT().
+		RemoveService(gomock.Any(), serviceID).
 		Return(nil)
-
-	err := adminService.DeactivateAccount(userID)
+	mockAuditRepo.EXPECT().
+		AppendEntry(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, entry *model.AuditEntry) error {
+			assert.Equal(t, "admin1", entry.ActorID)
+			assert.Equal(t, "delete", entry.Action)
+			assert.Equal(t, model.EntityService, entry.EntityType)
+			assert.Equal(t, serviceID, entry.EntityID)
+			assert.Contains(t, entry.Before, `"provider_id":"p1"`)
+			assert.Empty(t, entry.After)
+			return nil
+		})
+
+	err := adminService.DeleteService(service.WithActor(context.Background(), "admin1"), serviceID)
 	assert.NoError(t, err)
 }
 func TestGetAllService(t *testing.T) {
@@ -93,7 +79,7 @@ func TestGetAllService(t *testing.T) {
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
 	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
 
-	adminService := service.NewAdminService(mockServiceRepo, mockServiceRequestRepo, mockUserRepo, mockProviderRepo)
+	adminService := service.NewAdminService(mockServiceRepo, mockServiceRequestRepo, mockUserRepo, mockProviderRepo, auditLog(ctrl), passthroughTransactions(ctrl))
 
 	services := []model.Service{
 		{ID: "service1", Name: "Service 1"},
@@ -101,10 +87,10 @@ func TestGetAllService(t *testing.T) {
 	}
 
 	mockServiceRepo.EXPECT().
-		GetAllServices().
+		GetAllServices(gomock.Any(), model.QueryOptions{}).
 		Return(services, nil)
 
-	result, err := adminService.GetAllService()
+	result, err := adminService.GetAllService(context.Background(), model.QueryOptions{})
 	assert.NoError(t, err)
 	assert.Equal(t, services, result)
 }
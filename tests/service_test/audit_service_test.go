@@ -0,0 +1,174 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"testing"
+	"time"
+)
+
+// auditChain builds n correctly linked entries
+func auditChain(n int) []model.AuditEntry {
+	entries := make([]model.AuditEntry, n)
+	prevHash := ""
+	for i := range entries {
+		entries[i] = model.AuditEntry{Seq: int64(i + 1), ID: fmt.Sprintf("e%d", i+1), ActorID: "a1", Action: "update", EntityType: model.EntityCategory,
+			EntityID: "c1", After: `{"name":"Cleaning"}`, CreatedAt: time.Date(2024, 6, 1, 10, i, 0, 0, time.UTC), PrevHash: prevHash}
+		entries[i].Hash = entries[i].ComputeHash()
+		prevHash = entries[i].Hash
+	}
+	return entries
+}
+
+func TestVerifyChain(t *testing.T) {
+	tests := []struct {
+		name       string
+		tamper     func([]model.AuditEntry) []model.AuditEntry
+		headSeq    int64
+		wantIntact bool
+		wantBroken int64
+	}{
+		{
+			name:       "Intact",
+			tamper:     func(e []model.AuditEntry) []model.AuditEntry { return e },
+			headSeq:    3,
+			wantIntact: true,
+		},
+		{
+			name: "Edited Entry",
+			tamper: func(e []model.AuditEntry) []model.AuditEntry {
+				e[1].ActorID = "someone-else"
+				return e
+			},
+			headSeq:    3,
+			wantBroken: 2,
+		},
+		{
+			name: "Deleted Entry",
+			tamper: func(e []model.AuditEntry) []model.AuditEntry {
+				return append(e[:1], e[2:]...)
+			},
+			headSeq:    3,
+			wantBroken: 2,
+		},
+		{
+			name: "Rehashed Entry",
+			tamper: func(e []model.AuditEntry) []model.AuditEntry {
+				e[0].Action = "delete"
+				e[0].Hash = e[0].ComputeHash()
+				return e
+			},
+			headSeq:    3,
+			wantBroken: 2,
+		},
+		{
+			name: "Newest Entry Deleted",
+			tamper: func(e []model.AuditEntry) []model.AuditEntry {
+				return e[:2]
+			},
+			headSeq:    3,
+			wantBroken: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+			auditService := service.NewAuditService(mockAuditRepo)
+
+			original := auditChain(3)
+			headHash := original[tt.headSeq-1].Hash
+			entries := tt.tamper(original)
+			mockAuditRepo.EXPECT().GetChainHead(gomock.Any()).Return(tt.headSeq, headHash, nil)
+			mockAuditRepo.EXPECT().GetEntriesAfter(gomock.Any(), int64(0), gomock.Any()).Return(entries, nil)
+
+			result, err := auditService.VerifyChain(context.Background())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantIntact, result.Intact())
+			assert.Equal(t, tt.wantBroken, result.BrokenAt)
+		})
+	}
+}
+
+func TestGetAuditEntries_InvalidRange(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	auditService := service.NewAuditService(mocks.NewMockAuditRepository(ctrl))
+	now := time.Now()
+
+	_, err := auditService.GetEntries(context.Background(), model.AuditFilter{From: now, To: now.Add(-time.Hour)})
+	assert.Error(t, err)
+}
+
+func TestAudit_RedactsPasswordsAndPersonalDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	userService := service.NewUserService(mockUserRepo, activeAccounts(ctrl), mockAuditRepo, passthroughTransactions(ctrl))
+
+	user := &model.User{ID: "u1", Email: "user@example.com", Password: hashedPassword(t, "Secret@123")}
+	mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(user, nil)
+	mockUserRepo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(nil)
+	var entry *model.AuditEntry
+	mockAuditRepo.EXPECT().AppendEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.AuditEntry) error {
+		entry = e
+		return nil
+	})
+
+	newPassword := "NewSecret@123"
+	newPhone := "9876543210"
+	err := userService.UpdateUser(service.WithActor(context.Background(), "u1"), "u1", nil, &newPassword, nil, &newPhone)
+	assert.NoError(t, err)
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, "u1", entry.ActorID)
+		assert.Equal(t, model.EntityUser, entry.EntityType)
+		assert.NotContains(t, entry.Before, "password")
+		assert.NotContains(t, entry.After, "password")
+		assert.NotContains(t, entry.After, newPhone)
+		assert.NotContains(t, entry.After, "contact")
+		// Erasure cannot reach the log, so it keeps the ID instead of the email
+		assert.NotContains(t, entry.After, "user@example.com")
+		assert.Contains(t, entry.After, `"id":"u1"`)
+	}
+}
+
+func TestAudit_KeepsServiceNamesButNotProviderNames(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	mockAuditRepo := mocks.NewMockAuditRepository(ctrl)
+	svc := service.NewServiceProviderService(nil, nil, mockServiceRepo, nil, nil, activeAccounts(ctrl), mockAuditRepo, passthroughTransactions(ctrl))
+
+	before := &model.Service{ID: "s1", Name: "Tap repair", Category: "plumber", ProviderID: "p1", ProviderName: "Ravi"}
+	after := *before
+	after.Name = "Tap and pipe repair"
+	mockServiceRepo.EXPECT().GetServiceByID(gomock.Any(), "s1").Return(before, nil)
+	mockServiceRepo.EXPECT().UpdateService(gomock.Any(), "p1", after).Return(nil)
+	var entry *model.AuditEntry
+	mockAuditRepo.EXPECT().AppendEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, e *model.AuditEntry) error {
+		entry = e
+		return nil
+	})
+
+	assert.NoError(t, svc.UpdateService(context.Background(), "p1", "s1", after))
+	if assert.NotNil(t, entry) {
+		// A rename is only on record through the names, they are not personal
+		assert.Contains(t, entry.Before, `"name":"Tap repair"`)
+		assert.Contains(t, entry.After, `"name":"Tap and pipe repair"`)
+		assert.Contains(t, entry.After, `"category":"plumber"`)
+		assert.NotContains(t, entry.After, "Ravi")
+		assert.Contains(t, entry.After, `"provider_id":"p1"`)
+	}
+}
@@ -0,0 +1,553 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"regexp"
+	"serviceNest/mail"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"testing"
+	"time"
+)
+
+var authOptions = service.AuthOptions{VerificationTokenTTL: 24 * time.Hour, ResetTokenTTL: 30 * time.Minute}
+
+func newAuthService(ctrl *gomock.Controller, options service.AuthOptions) (*service.AuthService, serviceMocks) {
+	m := newServiceMocks(ctrl)
+	txManager := passthroughTransactions(ctrl)
+	auditRepo := auditLog(ctrl)
+	twoFactorService := service.NewTwoFactorService(m.userRepo, m.twoFactorRepo, activeAccounts(ctrl), auditRepo, txManager)
+	authService := service.NewAuthService(m.userRepo, m.tokenRepo, m.loginAttemptRepo, twoFactorService, m.mailer, activeAccounts(ctrl), auditRepo, options, txManager)
+	return authService, m
+}
+
+// noTwoFactor answers for users who never set up two-factor authentication
+func noTwoFactor(m serviceMocks) {
+	m.twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), gomock.Any()).Return(nil, errors.New("two-factor settings not found")).AnyTimes()
+}
+
+// noFailedLogins lets every login through the throttle
+func noFailedLogins(m serviceMocks) {
+	m.loginAttemptRepo.EXPECT().GetThrottle(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, scope, key string) (*model.LoginThrottle, error) {
+			return &model.LoginThrottle{Scope: scope, Key: key}, nil
+		}).AnyTimes()
+	m.loginAttemptRepo.EXPECT().ResetThrottle(gomock.Any(), model.ThrottleAccount, gomock.Any()).Return(nil).AnyTimes()
+}
+
+// codeFrom extracts the code from an emailed message
+func codeFrom(t *testing.T, message mail.Message) string {
+	code := regexp.MustCompile(`[0-9a-f]{32}`).FindString(message.Body)
+	if code == "" {
+		t.Fatalf("no code in email: %q", message.Body)
+	}
+	return code
+}
+
+func hashOf(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+func hashedPassword(t *testing.T, password string) string {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(hash)
+}
+
+func TestLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+	noFailedLogins(m)
+	noTwoFactor(m)
+	user := &model.User{ID: "u1", Email: "jane@example.com", Role: "Householder", Password: hashedPassword(t, "Secret@123")}
+
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(user, nil)
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "Jane@Example.com").Return(user, nil)
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "nobody@example.com").Return(nil, errors.New("user not found"))
+	var audited []model.LoginAuditEntry
+	m.loginAttemptRepo.EXPECT().SaveAuditEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry model.LoginAuditEntry) error {
+		audited = append(audited, entry)
+		return nil
+	}).Times(2)
+	m.loginAttemptRepo.EXPECT().IncrementFailures(gomock.Any(), model.ThrottleAccount, "jane@example.com", gomock.Any(), gomock.Any()).Return(1, nil)
+	m.loginAttemptRepo.EXPECT().IncrementFailures(gomock.Any(), model.ThrottleAccount, "nobody@example.com", gomock.Any(), gomock.Any()).Return(1, nil)
+	m.loginAttemptRepo.EXPECT().IncrementFailures(gomock.Any(), model.ThrottleSource, "10.0.0.1", gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+
+	loggedIn, err := authService.Login(context.Background(), "jane@example.com", "Secret@123", "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", loggedIn.ID)
+
+	// Unknown addresses and wrong passwords look the same, both are audited
+	_, err = authService.Login(context.Background(), "Jane@Example.com", "Wrong@123", "10.0.0.1")
+	assert.Equal(t, model.ErrInvalidCredentials, err)
+	_, err = authService.Login(context.Background(), "nobody@example.com", "Secret@123", "10.0.0.1")
+	assert.Equal(t, model.ErrInvalidCredentials, err)
+
+	assert.Len(t, audited, 2)
+	assert.Equal(t, model.LoginAuditEntry{ID: audited[0].ID, Email: "jane@example.com", UserID: "u1", Source: "10.0.0.1",
+		Outcome: model.LoginInvalidCredentials, CreatedAt: audited[0].CreatedAt}, audited[0])
+	assert.Equal(t, "nobody@example.com", audited[1].Email)
+	assert.Empty(t, audited[1].UserID)
+}
+
+func TestLogin_RequiresVerifiedEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	options := authOptions
+	options.RequireVerifiedEmail = true
+	authService, m := newAuthService(ctrl, options)
+	noFailedLogins(m)
+
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").
+		Return(&model.User{ID: "u1", Role: model.RoleHouseholder, Password: hashedPassword(t, "Secret@123")}, nil)
+
+	_, err := authService.Login(context.Background(), "jane@example.com", "Secret@123", "10.0.0.1")
+	assert.ErrorIs(t, err, model.ErrEmailNotVerified)
+}
+
+func TestLogin_BlockedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := newServiceMocks(ctrl)
+	accountGuard := mocks.NewMockAccountGuard(ctrl)
+	authService := service.NewAuthService(m.userRepo, m.tokenRepo, m.loginAttemptRepo, nil, m.mailer, accountGuard, auditLog(ctrl), authOptions, passthroughTransactions(ctrl))
+	noFailedLogins(m)
+
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").
+		Return(&model.User{ID: "u1", Role: model.RoleHouseholder, Password: hashedPassword(t, "Secret@123")}, nil)
+	banned := &model.AccountInactiveError{Status: model.AccountBanned, Reason: "fraud"}
+	accountGuard.EXPECT().EnsureActive(gomock.Any(), "u1").Return(banned)
+
+	_, err := authService.Login(context.Background(), "jane@example.com", "Secret@123", "10.0.0.1")
+	assert.Equal(t, banned, err)
+}
+
+func TestLogin_LocksAccountAtLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	options := authOptions
+	options.MaxFailedLogins = 5
+	options.MaxFailedLoginsPerSource = 50
+	options.LockoutDuration = 15 * time.Minute
+	authService, m := newAuthService(ctrl, options)
+	noFailedLogins(m)
+
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").
+		Return(&model.User{ID: "u1", Name: "Jane", Email: "jane@example.com", Password: hashedPassword(t, "Secret@123")}, nil)
+	m.loginAttemptRepo.EXPECT().SaveAuditEntry(gomock.Any(), gomock.Any()).Return(nil)
+	m.loginAttemptRepo.EXPECT().IncrementFailures(gomock.Any(), model.ThrottleAccount, "jane@example.com", gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, now, windowStart time.Time) (int, error) {
+			assert.Equal(t, time.Hour, now.Sub(windowStart))
+			return 5, nil
+		})
+	m.loginAttemptRepo.EXPECT().IncrementFailures(gomock.Any(), model.ThrottleSource, "10.0.0.1", gomock.Any(), gomock.Any()).Return(5, nil)
+	m.loginAttemptRepo.EXPECT().LockThrottle(gomock.Any(), model.ThrottleAccount, "jane@example.com", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ string, until time.Time) error {
+			assert.WithinDuration(t, time.Now().Add(15*time.Minute), until, time.Minute)
+			return nil
+		})
+
+	_, err := authService.Login(context.Background(), "jane@example.com", "Wrong@123", "10.0.0.1")
+	assert.Equal(t, model.ErrInvalidCredentials, err)
+
+	messages := m.mailer.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "Your ServiceNest account has been locked", messages[0].Subject)
+}
+
+func TestLogin_LocalTerminalOnlyCountsAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	options := authOptions
+	options.MaxFailedLogins = 5
+	options.MaxFailedLoginsPerSource = 1
+	authService, m := newAuthService(ctrl, options)
+	source := model.LocalSource("workstation")
+
+	// Only the account is looked up and counted, the shared terminal is never locked
+	m.loginAttemptRepo.EXPECT().GetThrottle(gomock.Any(), model.ThrottleAccount, "jane@example.com").
+		Return(&model.LoginThrottle{Scope: model.ThrottleAccount, Key: "jane@example.com"}, nil)
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").
+		Return(&model.User{ID: "u1", Email: "jane@example.com", Password: hashedPassword(t, "Secret@123")}, nil)
+	m.loginAttemptRepo.EXPECT().SaveAuditEntry(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, entry model.LoginAuditEntry) error {
+			assert.Equal(t, source, entry.Source)
+			return nil
+		})
+	m.loginAttemptRepo.EXPECT().IncrementFailures(gomock.Any(), model.ThrottleAccount, "jane@example.com", gomock.Any(), gomock.Any()).Return(1, nil)
+
+	_, err := authService.Login(context.Background(), "jane@example.com", "Wrong@123", source)
+	assert.Equal(t, model.ErrInvalidCredentials, err)
+}
+
+func TestLogin_Throttled(t *testing.T) {
+	lockedUntil := time.Now().Add(10 * time.Minute)
+	tests := []struct {
+		name       string
+		account    model.LoginThrottle
+		source     model.LoginThrottle
+		retryAfter time.Duration
+	}{
+		{
+			name:       "account locked",
+			account:    model.LoginThrottle{LockedUntil: &lockedUntil, LastFailureAt: time.Now().Add(-time.Minute)},
+			retryAfter: 10 * time.Minute,
+		},
+		{
+			name:       "source locked",
+			source:     model.LoginThrottle{LockedUntil: &lockedUntil, LastFailureAt: time.Now().Add(-time.Minute)},
+			retryAfter: 10 * time.Minute,
+		},
+		{
+			// The fifth failure in a row makes the next attempt wait four seconds
+			name:       "progressive delay",
+			account:    model.LoginThrottle{Failures: 5, LastFailureAt: time.Now()},
+			retryAfter: 4 * time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			authService, m := newAuthService(ctrl, authOptions)
+
+			m.loginAttemptRepo.EXPECT().GetThrottle(gomock.Any(), model.ThrottleAccount, "jane@example.com").Return(&tt.account, nil)
+			m.loginAttemptRepo.EXPECT().GetThrottle(gomock.Any(), model.ThrottleSource, "10.0.0.1").Return(&tt.source, nil)
+			m.loginAttemptRepo.EXPECT().SaveAuditEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry model.LoginAuditEntry) error {
+				assert.Equal(t, model.LoginThrottled, entry.Outcome)
+				return nil
+			})
+
+			// The password is not checked, so the answer is the same for every address
+			_, err := authService.Login(context.Background(), "jane@example.com", "Secret@123", "10.0.0.1")
+			assert.ErrorIs(t, err, model.ErrTooManyAttempts)
+			var throttled *model.LoginThrottledError
+			assert.ErrorAs(t, err, &throttled)
+			assert.InDelta(t, tt.retryAfter.Seconds(), throttled.RetryAfter.Seconds(), 2)
+		})
+	}
+}
+
+func TestLogin_OldFailuresAreForgotten(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+
+	m.loginAttemptRepo.EXPECT().GetThrottle(gomock.Any(), model.ThrottleAccount, "jane@example.com").
+		Return(&model.LoginThrottle{Failures: 9, LastFailureAt: time.Now().Add(-2 * time.Hour)}, nil)
+	m.loginAttemptRepo.EXPECT().GetThrottle(gomock.Any(), model.ThrottleSource, "10.0.0.1").Return(&model.LoginThrottle{}, nil)
+	m.loginAttemptRepo.EXPECT().ResetThrottle(gomock.Any(), model.ThrottleAccount, "jane@example.com").Return(nil)
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").
+		Return(&model.User{ID: "u1", Role: "ServiceProvider", Password: hashedPassword(t, "Secret@123")}, nil)
+	noTwoFactor(m)
+
+	_, err := authService.Login(context.Background(), "jane@example.com", "Secret@123", "10.0.0.1")
+	assert.NoError(t, err)
+}
+
+func TestUnlockAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+
+	m.loginAttemptRepo.EXPECT().ResetThrottle(gomock.Any(), model.ThrottleAccount, "jane@example.com").Return(nil)
+	m.loginAttemptRepo.EXPECT().SaveAuditEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry model.LoginAuditEntry) error {
+		assert.Equal(t, model.LoginUnlocked, entry.Outcome)
+		assert.Equal(t, "admin:a1", entry.Source)
+		return nil
+	})
+
+	assert.NoError(t, authService.UnlockAccount(context.Background(), "a1", " Jane@example.com"))
+}
+
+func TestSendEmailVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(&model.User{ID: "u1", Name: "Jane", Email: "jane@example.com"}, nil)
+	m.tokenRepo.EXPECT().InvalidateTokens(gomock.Any(), "u1", model.TokenEmailVerification).Return(nil)
+	var saved model.AuthToken
+	m.tokenRepo.EXPECT().SaveToken(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, token model.AuthToken) error {
+		saved = token
+		return nil
+	})
+
+	assert.NoError(t, authService.SendEmailVerification(context.Background(), "jane@example.com"))
+
+	messages := m.mailer.Messages()
+	assert.Len(t, messages, 1)
+	assert.Equal(t, "jane@example.com", messages[0].To)
+	code := codeFrom(t, messages[0])
+	assert.Equal(t, hashOf(code), saved.TokenHash, "only the hash of the code is stored")
+	assert.NotContains(t, saved.TokenHash, code)
+	assert.Equal(t, model.TokenEmailVerification, saved.Purpose)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), saved.ExpiresAt, time.Minute)
+}
+
+func TestSendEmailVerification_UnknownOrVerifiedAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "nobody@example.com").Return(nil, errors.New("user not found"))
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(&model.User{ID: "u1", EmailVerified: true}, nil)
+
+	assert.NoError(t, authService.SendEmailVerification(context.Background(), "nobody@example.com"))
+	assert.NoError(t, authService.SendEmailVerification(context.Background(), "jane@example.com"))
+	assert.Empty(t, m.mailer.Messages())
+}
+
+func TestVerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+
+	m.tokenRepo.EXPECT().GetTokenByHash(gomock.Any(), hashOf("abc")).Return(&model.AuthToken{
+		ID: "t1", UserID: "u1", Purpose: model.TokenEmailVerification, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	m.tokenRepo.EXPECT().MarkTokenUsed(gomock.Any(), "t1").Return(nil)
+	m.userRepo.EXPECT().MarkEmailVerified(gomock.Any(), "u1").Return(nil)
+
+	assert.NoError(t, authService.VerifyEmail(context.Background(), " abc \n"))
+}
+
+func TestVerifyEmail_InvalidCodes(t *testing.T) {
+	usedAt := time.Now().Add(-time.Minute)
+	tests := []struct {
+		name  string
+		token *model.AuthToken
+		err   error
+	}{
+		{name: "unknown code", err: errors.New("token not found")},
+		{name: "expired", token: &model.AuthToken{ID: "t1", Purpose: model.TokenEmailVerification, ExpiresAt: time.Now().Add(-time.Second)}},
+		{name: "already used", token: &model.AuthToken{ID: "t1", Purpose: model.TokenEmailVerification, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}},
+		{name: "reset code", token: &model.AuthToken{ID: "t1", Purpose: model.TokenPasswordReset, ExpiresAt: time.Now().Add(time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			authService, m := newAuthService(ctrl, authOptions)
+			m.tokenRepo.EXPECT().GetTokenByHash(gomock.Any(), hashOf("abc")).Return(tt.token, tt.err)
+
+			err := authService.VerifyEmail(context.Background(), "abc")
+			assert.ErrorIs(t, err, model.ErrInvalidToken)
+		})
+	}
+}
+
+func TestVerifyEmail_RedeemedConcurrently(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+
+	m.tokenRepo.EXPECT().GetTokenByHash(gomock.Any(), hashOf("abc")).Return(&model.AuthToken{
+		ID: "t1", UserID: "u1", Purpose: model.TokenEmailVerification, ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	m.tokenRepo.EXPECT().MarkTokenUsed(gomock.Any(), "t1").Return(&model.ConflictError{Entity: "token", ID: "t1"})
+
+	assert.ErrorIs(t, authService.VerifyEmail(context.Background(), "abc"), model.ErrInvalidToken)
+}
+
+func TestPasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(&model.User{ID: "u1", Name: "Jane", Email: "jane@example.com"}, nil)
+	m.tokenRepo.EXPECT().InvalidateTokens(gomock.Any(), "u1", model.TokenPasswordReset).Return(nil).Times(2)
+	var saved model.AuthToken
+	m.tokenRepo.EXPECT().SaveToken(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, token model.AuthToken) error {
+		saved = token
+		return nil
+	})
+
+	assert.NoError(t, authService.RequestPasswordReset(context.Background(), "jane@example.com"))
+	assert.Equal(t, model.TokenPasswordReset, saved.Purpose)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), saved.ExpiresAt, time.Minute)
+	code := codeFrom(t, m.mailer.Messages()[0])
+
+	m.tokenRepo.EXPECT().GetTokenByHash(gomock.Any(), hashOf(code)).Return(&saved, nil)
+	m.tokenRepo.EXPECT().MarkTokenUsed(gomock.Any(), saved.ID).Return(nil)
+	m.userRepo.EXPECT().UpdatePassword(gomock.Any(), "u1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, hash string) error {
+		assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(hash), []byte("NewSecret@123")))
+		return nil
+	})
+	m.userRepo.EXPECT().MarkEmailVerified(gomock.Any(), "u1").Return(nil)
+
+	assert.NoError(t, authService.ResetPassword(context.Background(), code, "NewSecret@123"))
+}
+
+func TestRequestPasswordReset_UnknownAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "nobody@example.com").Return(nil, errors.New("user not found"))
+
+	assert.NoError(t, authService.RequestPasswordReset(context.Background(), "nobody@example.com"))
+	assert.Empty(t, m.mailer.Messages())
+}
+
+func TestResetPassword_WeakPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, _ := newAuthService(ctrl, authOptions)
+
+	// The code is not redeemed when the new password is rejected
+	assert.Error(t, authService.ResetPassword(context.Background(), "abc", "short"))
+}
+
+func TestSendEmailVerification_MailerFailure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+	m.mailer.Err = errors.New("connection refused")
+
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(&model.User{ID: "u1", Email: "jane@example.com"}, nil)
+	m.tokenRepo.EXPECT().InvalidateTokens(gomock.Any(), "u1", model.TokenEmailVerification).Return(nil)
+	m.tokenRepo.EXPECT().SaveToken(gomock.Any(), gomock.Any()).Return(nil)
+
+	err := authService.SendEmailVerification(context.Background(), "jane@example.com")
+	assert.ErrorContains(t, err, "could not send the email")
+}
+
+func TestLogin_SecondFactor(t *testing.T) {
+	tests := []struct {
+		name      string
+		user      *model.User
+		twoFactor *model.TwoFactor
+		enrol     bool
+	}{
+		{name: "provider with two-factor authentication", user: &model.User{ID: "u1", Role: "ServiceProvider"},
+			twoFactor: &model.TwoFactor{UserID: "u1", Secret: testSecret, Enabled: true}},
+		{name: "admin without two-factor authentication", user: &model.User{ID: "u1", Role: "Admin"}, enrol: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			authService, m := newAuthService(ctrl, authOptions)
+			noFailedLogins(m)
+			tt.user.Password = hashedPassword(t, "Secret@123")
+
+			m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").Return(tt.user, nil)
+			if tt.twoFactor != nil {
+				m.twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), "u1").Return(tt.twoFactor, nil)
+			} else {
+				noTwoFactor(m)
+			}
+			m.tokenRepo.EXPECT().InvalidateTokens(gomock.Any(), "u1", model.TokenLoginChallenge).Return(nil)
+			m.tokenRepo.EXPECT().SaveToken(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, token model.AuthToken) error {
+				assert.Equal(t, model.TokenLoginChallenge, token.Purpose)
+				assert.WithinDuration(t, time.Now().Add(5*time.Minute), token.ExpiresAt, time.Minute)
+				return nil
+			})
+
+			user, err := authService.Login(context.Background(), "jane@example.com", "Secret@123", "10.0.0.1")
+
+			assert.Nil(t, user)
+			assert.ErrorIs(t, err, model.ErrSecondFactorRequired)
+			var secondFactor *model.SecondFactorRequiredError
+			assert.ErrorAs(t, err, &secondFactor)
+			assert.Equal(t, "u1", secondFactor.UserID)
+			assert.Equal(t, tt.enrol, secondFactor.EnrolmentRequired)
+			assert.Len(t, secondFactor.Challenge, 32)
+		})
+	}
+}
+
+func TestCompleteLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+	noFailedLogins(m)
+
+	challenge := &model.AuthToken{ID: "t1", UserID: "u1", Purpose: model.TokenLoginChallenge, ExpiresAt: time.Now().Add(time.Minute)}
+	m.tokenRepo.EXPECT().GetTokenByHash(gomock.Any(), hashOf("challenge")).Return(challenge, nil).Times(2)
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&model.User{ID: "u1", Email: "jane@example.com"}, nil).Times(2)
+	m.twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), "u1").Return(&model.TwoFactor{UserID: "u1", Secret: testSecret, Enabled: true}, nil).Times(2)
+
+	// A wrong code counts as a failed login
+	m.loginAttemptRepo.EXPECT().SaveAuditEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry model.LoginAuditEntry) error {
+		assert.Equal(t, model.LoginInvalidCode, entry.Outcome)
+		assert.Equal(t, "u1", entry.UserID)
+		return nil
+	})
+	m.loginAttemptRepo.EXPECT().IncrementFailures(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(1, nil).Times(2)
+	_, err := authService.CompleteLogin(context.Background(), "challenge", "000000", "10.0.0.1")
+	assert.Equal(t, model.ErrInvalidSecondFactor, err)
+
+	m.twoFactorRepo.EXPECT().UseStep(gomock.Any(), "u1", gomock.Any()).Return(nil)
+	m.tokenRepo.EXPECT().MarkTokenUsed(gomock.Any(), "t1").Return(nil)
+	user, err := authService.CompleteLogin(context.Background(), "challenge", currentCode(t, testSecret), "10.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", user.ID)
+}
+
+func TestCompleteLogin_ExpiredChallenge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+
+	m.tokenRepo.EXPECT().GetTokenByHash(gomock.Any(), hashOf("challenge")).
+		Return(&model.AuthToken{ID: "t1", UserID: "u1", Purpose: model.TokenLoginChallenge, ExpiresAt: time.Now().Add(-time.Second)}, nil)
+	m.tokenRepo.EXPECT().GetTokenByHash(gomock.Any(), hashOf("reset")).
+		Return(&model.AuthToken{ID: "t2", UserID: "u1", Purpose: model.TokenPasswordReset, ExpiresAt: time.Now().Add(time.Minute)}, nil)
+
+	_, err := authService.CompleteLogin(context.Background(), "challenge", "123456", "10.0.0.1")
+	assert.Equal(t, model.ErrLoginExpired, err)
+	_, err = authService.CompleteLogin(context.Background(), "reset", "123456", "10.0.0.1")
+	assert.Equal(t, model.ErrLoginExpired, err)
+}
+
+func TestCompleteEnrolment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+	noFailedLogins(m)
+
+	m.tokenRepo.EXPECT().GetTokenByHash(gomock.Any(), hashOf("challenge")).
+		Return(&model.AuthToken{ID: "t1", UserID: "admin1", Purpose: model.TokenLoginChallenge, ExpiresAt: time.Now().Add(time.Minute)}, nil)
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "admin1").Return(&model.User{ID: "admin1", Email: "admin@example.com", Role: "Admin"}, nil)
+	m.twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), "admin1").Return(&model.TwoFactor{UserID: "admin1", Secret: testSecret}, nil)
+	m.twoFactorRepo.EXPECT().EnableTwoFactor(gomock.Any(), "admin1", gomock.Any(), gomock.Any()).Return(nil)
+	m.twoFactorRepo.EXPECT().ReplaceRecoveryCodes(gomock.Any(), "admin1", gomock.Any()).Return(nil)
+	m.tokenRepo.EXPECT().MarkTokenUsed(gomock.Any(), "t1").Return(nil)
+
+	user, recoveryCodes, err := authService.CompleteEnrolment(context.Background(), "challenge", currentCode(t, testSecret), "10.0.0.1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "admin1", user.ID)
+	assert.Len(t, recoveryCodes, 10)
+}
+
+func TestLogin_UnknownRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	authService, m := newAuthService(ctrl, authOptions)
+	noFailedLogins(m)
+
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "jane@example.com").
+		Return(&model.User{ID: "u1", Role: "superuser", Password: hashedPassword(t, "Secret@123")}, nil)
+
+	user, err := authService.Login(context.Background(), "jane@example.com", "Secret@123", "10.0.0.1")
+
+	assert.Nil(t, user)
+	assert.Equal(t, model.ErrUnknownRole, err)
+}
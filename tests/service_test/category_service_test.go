@@ -0,0 +1,178 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"testing"
+)
+
+var catalogue = []model.Category{
+	{ID: "c1", Name: "electrician", Description: "Electrical work"},
+	{ID: "c2", Name: "wiring", Description: "Wiring repairs", ParentID: "c1"},
+	{ID: "c3", Name: "smart switches", Description: "Home automation", ParentID: "c2"},
+	{ID: "c4", Name: "plumber", Description: "Pipes"},
+}
+
+func TestAddCategory_SubCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	categoryService := service.NewCategoryService(mockCategoryRepo, nil, nil, auditLog(ctrl), passthroughTransactions(ctrl))
+
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "wiring").Return(nil, errors.New("category not found"))
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "electrician").Return(&catalogue[0], nil)
+	mockCategoryRepo.EXPECT().SaveCategory(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, category model.Category) error {
+			assert.NotEmpty(t, category.ID)
+			assert.Equal(t, "wiring", category.Name)
+			assert.Equal(t, "c1", category.ParentID)
+			return nil
+		})
+
+	category, err := categoryService.AddCategory(context.Background(), " wiring ", "Wiring repairs", "electrician")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "c1", category.ParentID)
+}
+
+func TestAddCategory_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	categoryService := service.NewCategoryService(mockCategoryRepo, nil, nil, auditLog(ctrl), passthroughTransactions(ctrl))
+
+	_, err := categoryService.AddCategory(context.Background(), "  ", "", "")
+	assert.EqualError(t, err, "category name is required")
+
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "plumber").Return(&catalogue[3], nil)
+	_, err = categoryService.AddCategory(context.Background(), "plumber", "", "")
+	assert.EqualError(t, err, `category "plumber" already exists`)
+
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "tiling").Return(nil, errors.New("category not found"))
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "builder").Return(nil, errors.New("category not found"))
+	_, err = categoryService.AddCategory(context.Background(), "tiling", "", "builder")
+	assert.EqualError(t, err, `unknown category "builder"`)
+}
+
+func TestUpdateCategory_CannotMoveBelowOwnSubCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	categoryService := service.NewCategoryService(mockCategoryRepo, nil, nil, auditLog(ctrl), passthroughTransactions(ctrl))
+
+	electrician := catalogue[0]
+	mockCategoryRepo.EXPECT().GetCategoryByID(gomock.Any(), "c1").Return(&electrician, nil)
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "smart switches").Return(&catalogue[2], nil)
+	mockCategoryRepo.EXPECT().GetAllCategories(gomock.Any()).Return(catalogue, nil)
+
+	err := categoryService.UpdateCategory(context.Background(), "c1", "", "", "smart switches")
+
+	assert.EqualError(t, err, "a category cannot be moved below itself")
+}
+
+func TestUpdateCategory_RenameAndMove(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	mockRatingRepo := mocks.NewMockRatingRepository(ctrl)
+	categoryService := service.NewCategoryService(mockCategoryRepo, mockServiceRepo, mockRatingRepo, auditLog(ctrl), passthroughTransactions(ctrl))
+
+	plumber := catalogue[3]
+	mockCategoryRepo.EXPECT().GetCategoryByID(gomock.Any(), "c4").Return(&plumber, nil)
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "plumbing").Return(nil, errors.New("category not found"))
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "electrician").Return(&catalogue[0], nil)
+	mockCategoryRepo.EXPECT().GetAllCategories(gomock.Any()).Return(catalogue, nil)
+	mockCategoryRepo.EXPECT().UpdateCategory(gomock.Any(), model.Category{ID: "c4", Name: "plumbing", Description: "Pipes", ParentID: "c1"}).Return(nil)
+	mockServiceRepo.EXPECT().RenameCategory(gomock.Any(), "plumber", "plumbing").Return(nil)
+	mockRatingRepo.EXPECT().RenameCategory(gomock.Any(), "plumber", "plumbing").Return(nil)
+
+	err := categoryService.UpdateCategory(context.Background(), "c4", "plumbing", "", "electrician")
+
+	assert.NoError(t, err)
+}
+
+func TestRemoveCategory(t *testing.T) {
+	tests := []struct {
+		name        string
+		categoryID  string
+		services    []model.Service
+		expectError string
+	}{
+		{name: "Has sub-categories", categoryID: "c2", expectError: `category "wiring" still has sub-categories`},
+		{name: "Used by services", categoryID: "c4", services: []model.Service{{ID: "s1"}}, expectError: `category "plumber" is still used by services`},
+		{name: "Unused leaf", categoryID: "c4"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+			mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+			categoryService := service.NewCategoryService(mockCategoryRepo, mockServiceRepo, nil, auditLog(ctrl), passthroughTransactions(ctrl))
+
+			for i := range catalogue {
+				if catalogue[i].ID == tt.categoryID {
+					mockCategoryRepo.EXPECT().GetCategoryByID(gomock.Any(), tt.categoryID).Return(&catalogue[i], nil)
+					mockServiceRepo.EXPECT().GetAllServices(gomock.Any(), model.QueryOptions{Category: catalogue[i].Name, Limit: 1}).
+						Return(tt.services, nil).AnyTimes()
+				}
+			}
+			mockCategoryRepo.EXPECT().GetAllCategories(gomock.Any()).Return(catalogue, nil)
+			if tt.expectError == "" {
+				mockCategoryRepo.EXPECT().RemoveCategory(gomock.Any(), tt.categoryID).Return(nil)
+			}
+
+			err := categoryService.RemoveCategory(context.Background(), tt.categoryID)
+
+			if tt.expectError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectError)
+			}
+		})
+	}
+}
+
+func TestImportCategories_SkipsExisting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	categoryService := service.NewCategoryService(mockCategoryRepo, nil, nil, auditLog(ctrl), passthroughTransactions(ctrl))
+
+	data := []byte(`[{"Name": "plumber", "Description": "Pipes"}, {"Name": "maid", "Description": "Chores"}]`)
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "plumber").Return(&catalogue[3], nil)
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "maid").Return(nil, errors.New("category not found")).Times(2)
+	mockCategoryRepo.EXPECT().SaveCategory(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, category model.Category) error {
+			assert.Equal(t, "maid", category.Name)
+			assert.Equal(t, "Chores", category.Description)
+			assert.Empty(t, category.ParentID)
+			return nil
+		})
+
+	imported, err := categoryService.ImportCategories(context.Background(), data)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, imported)
+}
+
+func TestImportCategories_InvalidFile(t *testing.T) {
+	categoryService := service.NewCategoryService(nil, nil, nil, nil, nil)
+
+	_, err := categoryService.ImportCategories(context.Background(), []byte("not json"))
+
+	assert.ErrorContains(t, err, "could not parse category file")
+}
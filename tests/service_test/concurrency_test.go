@@ -0,0 +1,171 @@
+package service_test
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// versionedRequestRepository keeps a single service request in memory and applies the same
+// conditional update rule as the MySQL repository. The first `readers` reads are held back until
+// all of them have arrived, so every caller starts from the same version.
+type versionedRequestRepository struct {
+	interfaces.ServiceRequestRepository
+
+	mu      sync.Mutex
+	request model.ServiceRequest
+	updates int
+
+	readers int32
+	arrived int32
+	barrier sync.WaitGroup
+}
+
+func newVersionedRequestRepository(request model.ServiceRequest, readers int) *versionedRequestRepository {
+	repo := &versionedRequestRepository{request: request, readers: int32(readers)}
+	repo.barrier.Add(readers)
+	return repo
+}
+
+func (r *versionedRequestRepository) GetServiceRequestByID(ctx context.Context, requestID string) (*model.ServiceRequest, error) {
+	r.mu.Lock()
+	request := r.request
+	r.mu.Unlock()
+
+	if atomic.AddInt32(&r.arrived, 1) <= r.readers {
+		r.barrier.Done()
+		r.barrier.Wait()
+	}
+	return &request, nil
+}
+
+func (r *versionedRequestRepository) UpdateServiceRequest(ctx context.Context, updatedRequest *model.ServiceRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if updatedRequest.Version != r.request.Version {
+		return &model.ConflictError{Entity: "service request", ID: updatedRequest.ID}
+	}
+	updatedRequest.Version++
+	r.request = *updatedRequest
+	r.updates++
+	return nil
+}
+
+func TestConcurrentAcceptAndCancel_NoLostUpdates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	householderID := "householder-1"
+	requestRepo := newVersionedRequestRepository(model.ServiceRequest{
+		ID:            "request-1",
+		HouseholderID: &householderID,
+		Status:        "Accepted",
+		Version:       1,
+	}, 3)
+
+	var savedDetails int32
+	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	expectVerifiedProvider(mockProviderRepo, "provider-1")
+	expectVerifiedProvider(mockProviderRepo, "provider-2")
+	mockProviderRepo.EXPECT().GetProviderDetailByID(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, providerID string) (*model.ServiceProviderDetails, error) {
+			return &model.ServiceProviderDetails{ServiceProviderID: providerID}, nil
+		}).AnyTimes()
+	mockProviderRepo.EXPECT().GetReviewsByProviderID(gomock.Any(), gomock.Any()).Return([]model.Review{}, nil).AnyTimes()
+	mockProviderRepo.EXPECT().SaveServiceProviderDetail(gomock.Any(), gomock.Any(), "request-1").
+		DoAndReturn(func(context.Context, *model.ServiceProviderDetails, string) error {
+			atomic.AddInt32(&savedDetails, 1)
+			return nil
+		}).AnyTimes()
+
+	providerService := service.NewServiceProviderService(mockProviderRepo, requestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+	householderService := service.NewHouseholderService(nil, nil, nil, requestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	var wg sync.WaitGroup
+	acceptErrs := make([]error, 2)
+	var cancelErr error
+	for i, providerID := range []string{"provider-1", "provider-2"} {
+		wg.Add(1)
+		go func(i int, providerID string) {
+			defer wg.Done()
+			acceptErrs[i] = providerService.AcceptServiceRequest(context.Background(), providerID, "request-1", "100")
+		}(i, providerID)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cancelErr = householderService.CancelAcceptedRequest(context.Background(), "request-1", householderID)
+	}()
+	wg.Wait()
+
+	// The cancellation can never be overwritten by a provider that read the request before it
+	assert.NoError(t, cancelErr)
+	assert.Equal(t, "Cancelled", requestRepo.request.Status)
+
+	accepted := 0
+	for _, err := range acceptErrs {
+		if err == nil {
+			accepted++
+		} else {
+			assert.EqualError(t, err, "service request has been cancelled")
+		}
+	}
+	assert.Equal(t, int32(accepted), atomic.LoadInt32(&savedDetails))
+	// Every successful write is reflected in the version, none was silently replaced
+	assert.Equal(t, 1+requestRepo.updates, requestRepo.request.Version)
+	assert.Equal(t, accepted+1, requestRepo.updates)
+}
+
+func TestConcurrentReschedule_AllUpdatesApplied(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const writers = 3
+	requestRepo := newVersionedRequestRepository(model.ServiceRequest{ID: "request-1", Status: "Pending", Version: 1}, writers)
+	householderService := service.NewHouseholderService(nil, nil, nil, requestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	base := time.Date(2024, 9, 1, 10, 0, 0, 0, time.UTC)
+	errs := make([]error, writers)
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = householderService.RescheduleServiceRequest(context.Background(), "request-1", base.Add(time.Duration(i)*time.Hour))
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, writers, requestRepo.updates)
+	assert.Equal(t, 1+writers, requestRepo.request.Version)
+}
+
+func TestRetryGivesUpAfterRepeatedConflicts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "request-1").
+		DoAndReturn(func(context.Context, string) (*model.ServiceRequest, error) {
+			return &model.ServiceRequest{ID: "request-1", Status: "Pending", Version: 1}, nil
+		}).Times(3)
+	mockServiceRequestRepo.EXPECT().UpdateServiceRequest(gomock.Any(), gomock.Any()).
+		Return(&model.ConflictError{Entity: "service request", ID: "request-1"}).Times(3)
+
+	householderService := service.NewHouseholderService(nil, nil, nil, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	err := householderService.CancelServiceRequest(context.Background(), "request-1")
+	assert.ErrorIs(t, err, model.ErrConflict)
+}
@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"testing"
+)
+
+func openCustomRequest() *model.CustomRequest {
+	return &model.CustomRequest{ID: "cr1", HouseholderID: "h1", ServiceName: "Pool Cleaning", Status: model.CustomRequestOpen}
+}
+
+func TestMapToCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomRequestRepo := mocks.NewMockCustomRequestRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	customRequestService := service.NewCustomRequestService(mockCustomRequestRepo, mockNotificationRepo,
+		service.NewCategoryService(mockCategoryRepo, nil, nil, auditLog(ctrl), passthroughTransactions(ctrl)), activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	mockCustomRequestRepo.EXPECT().GetCustomRequestByID(gomock.Any(), "cr1").Return(openCustomRequest(), nil)
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "cleaning").Return(&model.Category{ID: "c9", Name: "cleaning"}, nil)
+	mockCustomRequestRepo.EXPECT().ResolveCustomRequest(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request model.CustomRequest) error {
+			assert.Equal(t, model.CustomRequestMapped, request.Status)
+			assert.Equal(t, "c9", request.CategoryID)
+			assert.Equal(t, "admin1", request.ResolvedBy)
+			assert.NotNil(t, request.ResolvedAt)
+			return nil
+		})
+	mockNotificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notification model.Notification) error {
+			assert.Equal(t, "h1", notification.UserID)
+			assert.Contains(t, notification.Message, `"cleaning" category`)
+			assert.False(t, notification.Read)
+			return nil
+		})
+
+	err := customRequestService.MapToCategory(context.Background(), "admin1", "cr1", "cleaning")
+
+	assert.NoError(t, err)
+}
+
+func TestMapToNewCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomRequestRepo := mocks.NewMockCustomRequestRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	customRequestService := service.NewCustomRequestService(mockCustomRequestRepo, mockNotificationRepo,
+		service.NewCategoryService(mockCategoryRepo, nil, nil, auditLog(ctrl), passthroughTransactions(ctrl)), activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	mockCustomRequestRepo.EXPECT().GetCustomRequestByID(gomock.Any(), "cr1").Return(openCustomRequest(), nil)
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "pool care").Return(nil, errors.New("category not found"))
+	mockCategoryRepo.EXPECT().SaveCategory(gomock.Any(), gomock.Any()).Return(nil)
+	mockCustomRequestRepo.EXPECT().ResolveCustomRequest(gomock.Any(), gomock.Any()).Return(nil)
+	mockNotificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).Return(nil)
+
+	category, err := customRequestService.MapToNewCategory(context.Background(), "admin1", "cr1", "pool care", "Pool maintenance", "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "pool care", category.Name)
+}
+
+func TestMapToNewCategory_ResolveConflict(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomRequestRepo := mocks.NewMockCustomRequestRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	customRequestService := service.NewCustomRequestService(mockCustomRequestRepo, nil,
+		service.NewCategoryService(mockCategoryRepo, nil, nil, auditLog(ctrl), passthroughTransactions(ctrl)), activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	// Another admin resolved the request first, the transaction is rolled back and nobody is notified
+	mockCustomRequestRepo.EXPECT().GetCustomRequestByID(gomock.Any(), "cr1").Return(openCustomRequest(), nil)
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "pool care").Return(nil, errors.New("category not found"))
+	mockCategoryRepo.EXPECT().SaveCategory(gomock.Any(), gomock.Any()).Return(nil)
+	mockCustomRequestRepo.EXPECT().ResolveCustomRequest(gomock.Any(), gomock.Any()).
+		Return(&model.ConflictError{Entity: "custom request", ID: "cr1"})
+
+	_, err := customRequestService.MapToNewCategory(context.Background(), "admin1", "cr1", "pool care", "", "")
+
+	assert.True(t, errors.Is(err, model.ErrConflict))
+}
+
+func TestRejectCustomRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomRequestRepo := mocks.NewMockCustomRequestRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	customRequestService := service.NewCustomRequestService(mockCustomRequestRepo, mockNotificationRepo, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	err := customRequestService.RejectCustomRequest(context.Background(), "admin1", "cr1", "  ")
+	assert.EqualError(t, err, "a reason is required to reject a request")
+
+	mockCustomRequestRepo.EXPECT().GetCustomRequestByID(gomock.Any(), "cr1").Return(openCustomRequest(), nil)
+	mockCustomRequestRepo.EXPECT().ResolveCustomRequest(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request model.CustomRequest) error {
+			assert.Equal(t, model.CustomRequestRejected, request.Status)
+			assert.Equal(t, "Not offered in your area", request.ResolutionNote)
+			assert.Empty(t, request.CategoryID)
+			return nil
+		})
+	mockNotificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notification model.Notification) error {
+			assert.Equal(t, `Your request for "Pool Cleaning" could not be accepted: Not offered in your area`, notification.Message)
+			return nil
+		})
+
+	err = customRequestService.RejectCustomRequest(context.Background(), "admin1", "cr1", "Not offered in your area")
+	assert.NoError(t, err)
+}
+
+func TestRejectCustomRequest_AlreadyResolved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomRequestRepo := mocks.NewMockCustomRequestRepository(ctrl)
+	customRequestService := service.NewCustomRequestService(mockCustomRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	resolved := openCustomRequest()
+	resolved.Status = model.CustomRequestMapped
+	mockCustomRequestRepo.EXPECT().GetCustomRequestByID(gomock.Any(), "cr1").Return(resolved, nil)
+
+	err := customRequestService.RejectCustomRequest(context.Background(), "admin1", "cr1", "duplicate")
+
+	assert.EqualError(t, err, "custom request has already been mapped")
+}
+
+func TestGetNotifications_MarksInboxRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+	notificationService := service.NewNotificationService(mockNotificationRepo)
+
+	inbox := []model.Notification{{ID: "n2", Message: "new"}, {ID: "n1", Message: "old", Read: true}}
+	mockNotificationRepo.EXPECT().GetNotificationsByUserID(gomock.Any(), "h1", false).Return(inbox, nil)
+	mockNotificationRepo.EXPECT().MarkNotificationsRead(gomock.Any(), "h1").Return(nil)
+
+	notifications, err := notificationService.GetNotifications(context.Background(), "h1")
+	assert.NoError(t, err)
+	assert.Equal(t, inbox, notifications)
+
+	// Nothing unread, nothing to mark
+	mockNotificationRepo.EXPECT().GetNotificationsByUserID(gomock.Any(), "h1", false).Return(inbox[1:], nil)
+	_, err = notificationService.GetNotifications(context.Background(), "h1")
+	assert.NoError(t, err)
+}
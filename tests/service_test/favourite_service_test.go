@@ -0,0 +1,125 @@
+package service_test
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"testing"
+	"time"
+)
+
+func newFavouriteService(ctrl *gomock.Controller) (*service.FavouriteService, serviceMocks) {
+	m := newServiceMocks(ctrl)
+	favouriteService := service.NewFavouriteService(m.favouriteRepo, m.providerRepo, m.serviceRepo, m.serviceRequestRepo, m.notificationRepo,
+		activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+	return favouriteService, m
+}
+
+func TestAddFavourite(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	favouriteService, m := newFavouriteService(ctrl)
+
+	unverified := publicProvider()
+	unverified.VerificationStatus = model.VerificationPending
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(publicProvider(), nil)
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p2").Return(unverified, nil)
+	m.favouriteRepo.EXPECT().AddFavourite(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, favourite model.FavouriteProvider) error {
+		assert.Equal(t, "h1", favourite.HouseholderID)
+		assert.Equal(t, "p1", favourite.ProviderID)
+		return nil
+	})
+
+	assert.NoError(t, favouriteService.AddFavourite(context.Background(), "h1", "p1"))
+	assert.EqualError(t, favouriteService.AddFavourite(context.Background(), "h1", "p2"), "provider not found")
+}
+
+func TestBookAgain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	favouriteService, m := newFavouriteService(ctrl)
+
+	householder := &model.Householder{User: model.User{ID: "h1", Name: "Asha", Address: "4 Lake View"}}
+	scheduled := time.Date(2026, 11, 2, 10, 0, 0, 0, time.UTC)
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(publicProvider(), nil)
+	m.serviceRepo.EXPECT().GetServiceByID(gomock.Any(), "s1").Return(&model.Service{ID: "s1", Name: "Leak repair", ProviderID: "p1"}, nil)
+	m.serviceRequestRepo.EXPECT().SaveServiceRequest(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, request model.ServiceRequest) error {
+		assert.Equal(t, "Pending", request.Status)
+		assert.Equal(t, "s1", request.ServiceID)
+		assert.Equal(t, scheduled, request.ScheduledTime)
+		if assert.NotNil(t, request.TargetProviderID) {
+			assert.Equal(t, "p1", *request.TargetProviderID)
+		}
+		return nil
+	})
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, notification model.Notification) error {
+		assert.Equal(t, "p1", notification.UserID)
+		assert.Contains(t, notification.Message, "Asha booked you directly for Leak repair")
+		return nil
+	})
+
+	requestID, err := favouriteService.BookAgain(context.Background(), householder, "p1", "s1", scheduled)
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, requestID)
+}
+
+func TestBookAgain_Refused(t *testing.T) {
+	unavailable := publicProvider()
+	unavailable.Availability = false
+	deactivated := publicProvider()
+	deactivated.IsActive = false
+
+	tests := []struct {
+		name        string
+		provider    *model.ServiceProvider
+		service     *model.Service
+		expectedErr string
+	}{
+		{name: "Provider not listed", provider: deactivated, expectedErr: "provider not found"},
+		{name: "Provider not taking jobs", provider: unavailable, expectedErr: model.ErrProviderUnavailable.Error()},
+		{name: "Service of another provider", provider: publicProvider(), service: &model.Service{ID: "s1", ProviderID: "p2"},
+			expectedErr: model.ErrNotOfferedByProvider.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			favouriteService, m := newFavouriteService(ctrl)
+
+			m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(tt.provider, nil)
+			if tt.service != nil {
+				m.serviceRepo.EXPECT().GetServiceByID(gomock.Any(), "s1").Return(tt.service, nil)
+			}
+			m.serviceRequestRepo.EXPECT().SaveServiceRequest(gomock.Any(), gomock.Any()).Times(0)
+
+			householder := &model.Householder{User: model.User{ID: "h1"}}
+			_, err := favouriteService.BookAgain(context.Background(), householder, "p1", "s1", time.Now().Add(24*time.Hour))
+
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestBookAgain_SuspendedHouseholder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	m := newServiceMocks(ctrl)
+	accountGuard := mocks.NewMockAccountGuard(ctrl)
+	favouriteService := service.NewFavouriteService(m.favouriteRepo, m.providerRepo, m.serviceRepo, m.serviceRequestRepo, m.notificationRepo,
+		accountGuard, auditLog(ctrl), passthroughTransactions(ctrl))
+
+	suspended := &model.AccountInactiveError{Status: model.AccountSuspended, Reason: "complaints"}
+	accountGuard.EXPECT().EnsureActive(gomock.Any(), "h1").Return(suspended)
+	m.serviceRequestRepo.EXPECT().SaveServiceRequest(gomock.Any(), gomock.Any()).Times(0)
+
+	householder := &model.Householder{User: model.User{ID: "h1"}}
+	_, err := favouriteService.BookAgain(context.Background(), householder, "p1", "s1", time.Now().Add(24*time.Hour))
+
+	assert.Equal(t, suspended, err)
+}
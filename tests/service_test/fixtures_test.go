@@ -0,0 +1,65 @@
+package service_test
+
+import (
+	"github.com/golang/mock/gomock"
+	"serviceNest/mail"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+)
+
+// serviceMocks holds a mock of every dependency the services under test are built from. The newXService
+// fixtures wire the ones their service needs, a test sets expectations on those it exercises.
+type serviceMocks struct {
+	userRepo           *mocks.MockUserRepository
+	accountStatusRepo  *mocks.MockAccountStatusRepository
+	roleHistoryRepo    *mocks.MockRoleHistoryRepository
+	tokenRepo          *mocks.MockAuthTokenRepository
+	loginAttemptRepo   *mocks.MockLoginAttemptRepository
+	twoFactorRepo      *mocks.MockTwoFactorRepository
+	providerRepo       *mocks.MockServiceProviderRepository
+	providerIndexer    *mocks.MockProviderIndexer
+	serviceRepo        *mocks.MockServiceRepository
+	serviceRequestRepo *mocks.MockServiceRequestRepository
+	customRequestRepo  *mocks.MockCustomRequestRepository
+	notificationRepo   *mocks.MockNotificationRepository
+	documentRepo       *mocks.MockProviderDocumentRepository
+	profileRepo        *mocks.MockProviderProfileRepository
+	favouriteRepo      *mocks.MockFavouriteRepository
+	ratingRepo         *mocks.MockRatingRepository
+	flagRepo           *mocks.MockReviewFlagRepository
+	photoRepo          *mocks.MockReviewPhotoRepository
+	blobStore          *mocks.MockBlobStore
+	mailer             *mail.FakeMailer
+}
+
+func newServiceMocks(ctrl *gomock.Controller) serviceMocks {
+	return serviceMocks{
+		userRepo:           mocks.NewMockUserRepository(ctrl),
+		accountStatusRepo:  mocks.NewMockAccountStatusRepository(ctrl),
+		roleHistoryRepo:    mocks.NewMockRoleHistoryRepository(ctrl),
+		tokenRepo:          mocks.NewMockAuthTokenRepository(ctrl),
+		loginAttemptRepo:   mocks.NewMockLoginAttemptRepository(ctrl),
+		twoFactorRepo:      mocks.NewMockTwoFactorRepository(ctrl),
+		providerRepo:       mocks.NewMockServiceProviderRepository(ctrl),
+		providerIndexer:    mocks.NewMockProviderIndexer(ctrl),
+		serviceRepo:        mocks.NewMockServiceRepository(ctrl),
+		serviceRequestRepo: mocks.NewMockServiceRequestRepository(ctrl),
+		customRequestRepo:  mocks.NewMockCustomRequestRepository(ctrl),
+		notificationRepo:   mocks.NewMockNotificationRepository(ctrl),
+		documentRepo:       mocks.NewMockProviderDocumentRepository(ctrl),
+		profileRepo:        mocks.NewMockProviderProfileRepository(ctrl),
+		favouriteRepo:      mocks.NewMockFavouriteRepository(ctrl),
+		ratingRepo:         mocks.NewMockRatingRepository(ctrl),
+		flagRepo:           mocks.NewMockReviewFlagRepository(ctrl),
+		photoRepo:          mocks.NewMockReviewPhotoRepository(ctrl),
+		blobStore:          mocks.NewMockBlobStore(ctrl),
+		mailer:             &mail.FakeMailer{},
+	}
+}
+
+// ratingService builds the rating service that reviews record ratings through and profiles read them from. Every
+// rating change refreshes the search index; TestRefreshScores_DecaysStoredScore checks that.
+func (m serviceMocks) ratingService(ctrl *gomock.Controller) *service.RatingService {
+	m.providerIndexer.EXPECT().ReindexProvider(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return service.NewRatingService(m.ratingRepo, m.providerRepo, m.providerIndexer, testRatingOptions, passthroughTransactions(ctrl))
+}
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/golang/mock/gomock"
@@ -22,7 +23,7 @@ func TestViewStatus(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	householder := &model.Householder{User: model.User{ID: "householder1"}}
 	requests := []model.ServiceRequest{
@@ -30,14 +31,49 @@ func TestViewStatus(t *testing.T) {
 	}
 
 	mockServiceRequestRepo.EXPECT().
-		GetServiceRequestsByHouseholderID(householder.ID).
+		GetServiceRequestsByHouseholderID(gomock.Any(), householder.ID, model.QueryOptions{}).
 		Return(requests, nil)
 
-	result, err := service.ViewStatus(service, householder)
+	result, err := service.ViewStatus(context.Background(), service, householder)
 	assert.NoError(t, err)
 	assert.Equal(t, requests, result)
 }
 
+func TestViewStatus_MasksContactsUntilApproved(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	householderService := service.NewHouseholderService(nil, nil, nil, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	householder := &model.Householder{User: model.User{ID: "householder1"}}
+	requests := []model.ServiceRequest{
+		{ID: "request1", Status: "Accepted", ProviderDetails: []model.ServiceProviderDetails{
+			{ServiceProviderID: "p1", Contact: "9876543210", Address: "1 High Street"},
+			{ServiceProviderID: "p2", Contact: "9876543211", Address: "2 High Street"},
+		}},
+		{ID: "request2", Status: "Accepted", ApproveStatus: true, ProviderDetails: []model.ServiceProviderDetails{
+			{ServiceProviderID: "p1", Contact: "9876543210", Address: "1 High Street", Approve: true},
+			{ServiceProviderID: "p2", Contact: "9876543211", Address: "2 High Street"},
+		}},
+	}
+	mockServiceRequestRepo.EXPECT().GetServiceRequestsByHouseholderID(gomock.Any(), householder.ID, model.QueryOptions{}).Return(requests, nil)
+
+	result, err := householderService.ViewStatus(context.Background(), householderService, householder)
+	assert.NoError(t, err)
+	if assert.Len(t, result, 2) {
+		assert.Equal(t, model.ContactReference("request1"), result[0].ContactReference)
+		for _, provider := range result[0].ProviderDetails {
+			assert.Equal(t, model.MaskedContact, provider.Contact)
+			assert.Equal(t, model.MaskedContact, provider.Address)
+		}
+		// Only the approved provider of an approved request is revealed
+		assert.Equal(t, "9876543210", result[1].ProviderDetails[0].Contact)
+		assert.Equal(t, "1 High Street", result[1].ProviderDetails[0].Address)
+		assert.Equal(t, model.MaskedContact, result[1].ProviderDetails[1].Contact)
+	}
+}
+
 func TestCancelAcceptedRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -47,7 +83,7 @@ func TestCancelAcceptedRequest(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	requestID := "request1"
 	householderID := "householder1"
@@ -59,20 +95,20 @@ func TestCancelAcceptedRequest(t *testing.T) {
 
 	// Set up the mock expectations
 	mockServiceRequestRepo.EXPECT().
-		GetServiceRequestByID(requestID).
+		GetServiceRequestByID(gomock.Any(), requestID).
 		Return(serviceRequest, nil) // Return pointer to serviceRequest
 
 	// We use `Do` to verify the argument passed to `UpdateServiceRequest`
 	mockServiceRequestRepo.EXPECT().
-		UpdateServiceRequest(gomock.Any()).             // Accept any pointer argument here
-		Do(func(updatedRequest *model.ServiceRequest) { // Expect pointer type
+		UpdateServiceRequest(gomock.Any(), gomock.Any()).                  // Accept any pointer argument here
+		Do(func(_ context.Context, updatedRequest *model.ServiceRequest) { // Expect pointer type
 			assert.Equal(t, "Cancelled", updatedRequest.Status)
 			assert.Equal(t, requestID, updatedRequest.ID)
 			assert.Equal(t, householderID, *updatedRequest.HouseholderID)
 		}).
 		Return(nil)
 
-	err := service.CancelAcceptedRequest(requestID, householderID)
+	err := service.CancelAcceptedRequest(context.Background(), requestID, householderID)
 	assert.NoError(t, err)
 }
 
@@ -85,7 +121,7 @@ func TestSearchService(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	householder := &model.Householder{User: model.User{ID: "householder1", Latitude: 10, Longitude: 10}}
 	providers := []model.ServiceProvider{
@@ -93,10 +129,10 @@ func TestSearchService(t *testing.T) {
 	}
 
 	mockProviderRepo.EXPECT().
-		GetProvidersByServiceType("Cleaning").
+		GetProvidersByServiceType(gomock.Any(), "Cleaning").
 		Return(providers, nil)
 
-	result, err := service.SearchService(householder, "Cleaning")
+	result, err := service.SearchService(context.Background(), householder, "Cleaning")
 	assert.NoError(t, err)
 	assert.Len(t, result, 1)
 	assert.Equal(t, "provider1", result[0].ID)
@@ -111,7 +147,7 @@ func TestGetServicesByCategory(t *testing.T) {
 	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
 
 	// Create the service object
-	householderService := service.NewHouseholderService(nil, mockProviderRepo, mockServiceRepo, nil)
+	householderService := service.NewHouseholderService(nil, mockProviderRepo, mockServiceRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	// Test data
 	services := []model.Service{
@@ -120,11 +156,6 @@ func TestGetServicesByCategory(t *testing.T) {
 			Category:   "Cleaning",
 			ProviderID: "provider1",
 		},
-		{
-			ID:         "service2",
-			Category:   "Plumbing",
-			ProviderID: "provider2",
-		},
 	}
 
 	provider1 := &model.ServiceProviderDetails{
@@ -135,20 +166,20 @@ func TestGetServicesByCategory(t *testing.T) {
 		Rating:            4.5,
 	}
 
-	// Mock behavior
+	// Mock behavior, the category filter is pushed down to the repository
 	mockServiceRepo.EXPECT().
-		GetAllServices().
+		GetAllServices(gomock.Any(), model.QueryOptions{Category: "Cleaning"}).
 		Return(services, nil)
 
 	mockProviderRepo.EXPECT().
-		GetProviderDetailByID("provider1").
+		GetProviderDetailByID(gomock.Any(), "provider1").
 		Return(provider1, nil)
 
 	// Define the category to filter by
 	category := "Cleaning"
 
 	// Call the function
-	filteredServices, err := householderService.GetServicesByCategory(category)
+	filteredServices, err := householderService.GetServicesByCategory(context.Background(), category)
 
 	// Assert no error
 	assert.NoError(t, err)
@@ -160,8 +191,8 @@ func TestGetServicesByCategory(t *testing.T) {
 			Category:        "Cleaning",
 			ProviderID:      "provider1",
 			ProviderName:    "John Doe",
-			ProviderContact: "123456789",
-			ProviderAddress: "123 Street",
+			ProviderContact: model.MaskedContact,
+			ProviderAddress: model.MaskedContact,
 		},
 	}
 	assert.Equal(t, expectedServices, filteredServices)
@@ -176,32 +207,18 @@ func TestGetServicesByCategory_NoMatchingCategory(t *testing.T) {
 	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
 
 	// Create the service object
-	householderService := service.NewHouseholderService(nil, mockProviderRepo, mockServiceRepo, nil)
-
-	// Test data
-	services := []model.Service{
-		{
-			ID:         "service1",
-			Category:   "Cleaning",
-			ProviderID: "provider1",
-		},
-		{
-			ID:         "service2",
-			Category:   "Plumbing",
-			ProviderID: "provider2",
-		},
-	}
+	householderService := service.NewHouseholderService(nil, mockProviderRepo, mockServiceRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
-	// Mock behavior
+	// Mock behavior, no service is stored under the category
 	mockServiceRepo.EXPECT().
-		GetAllServices().
-		Return(services, nil)
+		GetAllServices(gomock.Any(), model.QueryOptions{Category: "Electrical"}).
+		Return(nil, nil)
 
 	// Define a category that does not match any service
 	category := "Electrical"
 
 	// Call the function
-	filteredServices, err := householderService.GetServicesByCategory(category)
+	filteredServices, err := householderService.GetServicesByCategory(context.Background(), category)
 
 	// Assert no error
 	assert.NoError(t, err)
@@ -219,7 +236,7 @@ func TestGetServicesByCategory_ErrorFetchingProviderDetails(t *testing.T) {
 	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
 
 	// Create the service object
-	householderService := service.NewHouseholderService(nil, mockProviderRepo, mockServiceRepo, nil)
+	householderService := service.NewHouseholderService(nil, mockProviderRepo, mockServiceRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	// Test data
 	services := []model.Service{
@@ -232,20 +249,20 @@ func TestGetServicesByCategory_ErrorFetchingProviderDetails(t *testing.T) {
 
 	// Mock behavior for GetAllServices
 	mockServiceRepo.EXPECT().
-		GetAllServices().
+		GetAllServices(gomock.Any(), model.QueryOptions{Category: "Cleaning"}).
 		Return(services, nil)
 
 	// Simulate an error when fetching provider details
 	// Updated to return *model.ServiceProviderDetails with error
 	mockProviderRepo.EXPECT().
-		GetProviderDetailByID("provider1").
+		GetProviderDetailByID(gomock.Any(), "provider1").
 		Return(&model.ServiceProviderDetails{}, errors.New("provider not found"))
 
 	// Define the category to filter by
 	category := "Cleaning"
 
 	// Call the function
-	_, err := householderService.GetServicesByCategory(category)
+	_, err := householderService.GetServicesByCategory(context.Background(), category)
 
 	// Assert that the error occurred
 	assert.Error(t, err)
@@ -261,7 +278,7 @@ func TestViewBookingHistory(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	householderID := "householder1"
 	requests := []model.ServiceRequest{
@@ -269,10 +286,10 @@ func TestViewBookingHistory(t *testing.T) {
 	}
 
 	mockServiceRequestRepo.EXPECT().
-		GetServiceRequestsByHouseholderID(householderID).
+		GetServiceRequestsByHouseholderID(gomock.Any(), householderID, model.QueryOptions{}).
 		Return(requests, nil)
 
-	result, err := service.ViewBookingHistory(householderID)
+	result, err := service.ViewBookingHistory(context.Background(), householderID, model.QueryOptions{})
 	assert.NoError(t, err)
 	assert.Equal(t, requests, result)
 }
@@ -286,7 +303,7 @@ func TestCancelServiceRequest(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	requestID := "request1"
 	serviceRequest := &model.ServiceRequest{
@@ -295,21 +312,39 @@ func TestCancelServiceRequest(t *testing.T) {
 	}
 
 	mockServiceRequestRepo.EXPECT().
-		GetServiceRequestByID(requestID).
+		GetServiceRequestByID(gomock.Any(), requestID).
 		Return(serviceRequest, nil)
 	mockServiceRequestRepo.EXPECT().
-		UpdateServiceRequest(gomock.Any()).
-		Do(func(req *model.ServiceRequest) {
+		UpdateServiceRequest(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *model.ServiceRequest) {
 			// Ensure that the Status is updated correctly.
 			assert.Equal(t, "Cancelled", req.Status)
 		}).
 		Return(nil)
 
-	err := service.CancelServiceRequest(requestID)
+	err := service.CancelServiceRequest(context.Background(), requestID)
 	assert.NoError(t, err)
 	assert.Equal(t, "Cancelled", serviceRequest.Status)
 }
 
+func TestCancelServiceRequest_SuspendedHouseholder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	accountGuard := mocks.NewMockAccountGuard(ctrl)
+	householderService := service.NewHouseholderService(nil, nil, nil, mockServiceRequestRepo, nil, nil, accountGuard, auditLog(ctrl), passthroughTransactions(ctrl))
+
+	householderID := "householder1"
+	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "request1").
+		Return(&model.ServiceRequest{ID: "request1", HouseholderID: &householderID, Status: "Pending"}, nil)
+	suspended := &model.AccountInactiveError{Status: model.AccountSuspended, Reason: "spam"}
+	accountGuard.EXPECT().EnsureActive(gomock.Any(), householderID).Return(suspended)
+
+	err := householderService.CancelServiceRequest(context.Background(), "request1")
+	assert.Equal(t, suspended, err)
+}
+
 func TestRescheduleServiceRequest(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -319,7 +354,7 @@ func TestRescheduleServiceRequest(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	requestID := "request1"
 	newTime := time.Now().Add(time.Hour * 24)
@@ -330,17 +365,17 @@ func TestRescheduleServiceRequest(t *testing.T) {
 	}
 
 	mockServiceRequestRepo.EXPECT().
-		GetServiceRequestByID(requestID).
+		GetServiceRequestByID(gomock.Any(), requestID).
 		Return(serviceRequest, nil)
 	mockServiceRequestRepo.EXPECT().
-		UpdateServiceRequest(gomock.Any()).
-		Do(func(req *model.ServiceRequest) {
+		UpdateServiceRequest(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, req *model.ServiceRequest) {
 			// Ensure that the ScheduledTime is updated correctly.
 			assert.Equal(t, newTime, req.ScheduledTime)
 		}).
 		Return(nil)
 
-	err := service.RescheduleServiceRequest(requestID, newTime)
+	err := service.RescheduleServiceRequest(context.Background(), requestID, newTime)
 	assert.NoError(t, err)
 	assert.Equal(t, newTime, serviceRequest.ScheduledTime)
 }
@@ -354,7 +389,7 @@ func TestViewServiceRequestStatus(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	requestID := "request1"
 	status := "Accepted"
@@ -364,10 +399,10 @@ func TestViewServiceRequestStatus(t *testing.T) {
 	}
 
 	mockServiceRequestRepo.EXPECT().
-		GetServiceRequestByID(requestID).
+		GetServiceRequestByID(gomock.Any(), requestID).
 		Return(serviceRequest, nil)
 
-	result, err := service.ViewServiceRequestStatus(requestID)
+	result, err := service.ViewServiceRequestStatus(context.Background(), requestID)
 	assert.NoError(t, err)
 	assert.Equal(t, status, result)
 }
@@ -380,7 +415,7 @@ func TestViewApprovedRequests(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	householderID := "householder1"
 
@@ -398,10 +433,10 @@ func TestViewApprovedRequests(t *testing.T) {
 	}
 
 	mockServiceRequestRepo.EXPECT().
-		GetServiceRequestsByHouseholderID(householderID).
+		GetServiceRequestsByHouseholderID(gomock.Any(), householderID, model.QueryOptions{}).
 		Return(serviceRequests, nil)
 
-	approvedRequests, err := service.ViewApprovedRequests(householderID)
+	approvedRequests, err := service.ViewApprovedRequests(context.Background(), householderID)
 	assert.NoError(t, err)
 	assert.Len(t, approvedRequests, 1)
 	assert.Equal(t, "request1", approvedRequests[0].ID)
@@ -416,7 +451,7 @@ func TestViewApprovedRequests_NoApprovedRequests(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	householderID := "householder1"
 
@@ -429,10 +464,10 @@ func TestViewApprovedRequests_NoApprovedRequests(t *testing.T) {
 	}
 
 	mockServiceRequestRepo.EXPECT().
-		GetServiceRequestsByHouseholderID(householderID).
+		GetServiceRequestsByHouseholderID(gomock.Any(), householderID, model.QueryOptions{}).
 		Return(serviceRequests, nil)
 
-	approvedRequests, err := service.ViewApprovedRequests(householderID)
+	approvedRequests, err := service.ViewApprovedRequests(context.Background(), householderID)
 	assert.Error(t, err)
 	assert.Nil(t, approvedRequests)
 	assert.EqualError(t, err, "no approved service requests found")
@@ -447,7 +482,7 @@ func TestApproveServiceRequest(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	requestID := "request123"
 	providerID := "provider123"
@@ -527,18 +562,21 @@ func TestApproveServiceRequest(t *testing.T) {
 		},
 	}
 
+	mockProviderRepo.EXPECT().GetProviderByID(gomock.Any(), providerID).
+		Return(&model.ServiceProvider{User: model.User{ID: providerID}, IsActive: true}, nil).AnyTimes()
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Expectation for retrieving service provider details by request ID
 			mockServiceRequestRepo.EXPECT().
-				GetServiceProviderByRequestID(requestID, providerID).
+				GetServiceProviderByRequestID(gomock.Any(), requestID, providerID).
 				Return(tt.serviceRequest, tt.repoErr).
 				Times(1)
 
 			if tt.repoErr == nil && tt.expectUpdateProvider {
 				// Expectation for updating provider details
 				mockProviderRepo.EXPECT().
-					UpdateServiceProviderDetailByRequestID(gomock.Any(), requestID).
+					UpdateServiceProviderDetailByRequestID(gomock.Any(), gomock.Any(), requestID).
 					Return(tt.providerErr).
 					Times(1)
 			}
@@ -546,13 +584,13 @@ func TestApproveServiceRequest(t *testing.T) {
 			// Expectation for updating the service request if no repo error occurred
 			if tt.repoErr == nil && tt.providerErr == nil && tt.expectUpdateProvider {
 				mockServiceRequestRepo.EXPECT().
-					UpdateServiceRequest(gomock.Any()).
+					UpdateServiceRequest(gomock.Any(), gomock.Any()).
 					Return(tt.repoErr).
 					Times(1)
 			}
 
 			// Call the function under test
-			err := service.ApproveServiceRequest(requestID, providerID)
+			err := service.ApproveServiceRequest(context.Background(), requestID, providerID)
 
 			// Assert the result
 			assert.Equal(t, tt.expectedErr, err)
@@ -560,6 +598,23 @@ func TestApproveServiceRequest(t *testing.T) {
 	}
 }
 
+func TestApproveServiceRequest_InactiveProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	householderService := service.NewHouseholderService(nil, mockProviderRepo, nil, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	mockProviderRepo.EXPECT().GetProviderByID(gomock.Any(), "provider123").
+		Return(&model.ServiceProvider{User: model.User{ID: "provider123"}, IsActive: false}, nil)
+	mockServiceRequestRepo.EXPECT().GetServiceProviderByRequestID(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := householderService.ApproveServiceRequest(context.Background(), "request123", "provider123")
+
+	assert.EqualError(t, err, "the provider is no longer available, please choose another quote")
+}
+
 func TestAddReview(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -575,15 +630,14 @@ func TestAddReview(t *testing.T) {
 		return "uniqueID"
 	}
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
-
-	// Replace util.GenerateUniqueID with a mockable function if necessary
+	ratingService, mockRatingRepo, _ := newRatingService(ctrl)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, ratingService, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	providerID := "provider123"
 	householderID := "householder123"
-	serviceID := "service123"
+	requestID := "request123"
 	comments := "Great service!"
-	rating := 4.5
+	rating := 4.0
 
 	// Define test cases
 	tests := []struct {
@@ -615,20 +669,29 @@ func TestAddReview(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Set up expectations
+			mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), requestID).
+				Return(completedRequest(requestID, householderID), nil)
+			mockServiceRequestRepo.EXPECT().GetApprovedProvider(gomock.Any(), requestID).
+				Return(&model.ServiceProviderDetails{ServiceProviderID: providerID, Approve: true}, nil)
+			mockServiceRepo.EXPECT().GetServiceByID(gomock.Any(), "service123").
+				Return(&model.Service{ID: "service123", Category: "Plumbing"}, nil)
 			mockProviderRepo.EXPECT().
-				AddReview(gomock.Any()). // gomock.Any() is used to match any Review object
+				AddReview(gomock.Any(), gomock.Any()). // gomock.Any() is used to match any Review object
 				Return(tt.addReviewErr).
 				Times(1)
 
 			if tt.addReviewErr == nil {
-				mockProviderRepo.EXPECT().
-					UpdateProviderRating(providerID).
-					Return(tt.updateRatingErr).
+				mockRatingRepo.EXPECT().
+					LockProviderRatings(gomock.Any(), providerID).
+					Return(nil, tt.updateRatingErr).
 					Times(1)
 			}
+			if tt.addReviewErr == nil && tt.updateRatingErr == nil {
+				expectRatingUpdate(mockRatingRepo, providerID)
+			}
 
 			// Call the method under test
-			err := service.AddReview(providerID, householderID, serviceID, comments, rating)
+			err := service.AddReview(context.Background(), householderID, requestID, comments, rating, nil)
 
 			// Assert results
 			assert.Equal(t, tt.expectedErr, err)
@@ -636,6 +699,206 @@ func TestAddReview(t *testing.T) {
 	}
 }
 
+func completedRequest(requestID, householderID string) *model.ServiceRequest {
+	return &model.ServiceRequest{ID: requestID, HouseholderID: &householderID, ServiceID: "service123", Status: model.RequestCompleted, ApproveStatus: true}
+}
+
+func TestAddReview_LinksReviewToRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	ratingService, mockRatingRepo, _ := newRatingService(ctrl)
+	householderService := service.NewHouseholderService(nil, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, ratingService, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "request123").
+		Return(completedRequest("request123", "householder123"), nil)
+	mockServiceRequestRepo.EXPECT().GetApprovedProvider(gomock.Any(), "request123").
+		Return(&model.ServiceProviderDetails{ServiceProviderID: "provider123", Approve: true}, nil)
+	mockServiceRepo.EXPECT().GetServiceByID(gomock.Any(), "service123").
+		Return(&model.Service{ID: "service123", Category: "Plumbing"}, nil)
+	var saved model.Review
+	mockProviderRepo.EXPECT().AddReview(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, review model.Review) error {
+			saved = review
+			return nil
+		})
+	mockRatingRepo.EXPECT().LockProviderRatings(gomock.Any(), "provider123").Return(nil, nil)
+	expectRatingUpdate(mockRatingRepo, "provider123")
+
+	scores := map[string]float64{model.CriterionPunctuality: 4}
+	err := householderService.AddReview(context.Background(), "householder123", "request123", "Tidy work", 5, scores)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "request123", saved.RequestID)
+	assert.Equal(t, "provider123", saved.ProviderID)
+	assert.Equal(t, "service123", saved.ServiceID)
+	assert.Equal(t, "Plumbing", saved.Category)
+	assert.Equal(t, scores, saved.Scores)
+}
+
+func TestAddReview_RejectsUnverifiedReviews(t *testing.T) {
+	otherHouseholder := "householder456"
+	tests := []struct {
+		name        string
+		rating      float64
+		scores      map[string]float64
+		request     *model.ServiceRequest
+		approvedErr error
+		expectedErr error
+	}{
+		{
+			name:        "Rating below the bounds",
+			rating:      0,
+			expectedErr: model.ErrInvalidRating,
+		},
+		{
+			name:        "Rating above the bounds",
+			rating:      6,
+			expectedErr: model.ErrInvalidRating,
+		},
+		{
+			name:        "Sub-score out of bounds",
+			rating:      4,
+			scores:      map[string]float64{model.CriterionQuality: 9},
+			expectedErr: model.ErrInvalidRating,
+		},
+		{
+			name:        "Request of another householder",
+			rating:      4,
+			request:     &model.ServiceRequest{ID: "request123", HouseholderID: &otherHouseholder, Status: model.RequestCompleted, ApproveStatus: true},
+			expectedErr: model.ErrReviewNotAllowed,
+		},
+		{
+			name:        "Request not completed yet",
+			rating:      4,
+			request:     &model.ServiceRequest{ID: "request123", HouseholderID: &otherHouseholder, Status: "Accepted", ApproveStatus: true},
+			expectedErr: model.ErrReviewNotAllowed,
+		},
+		{
+			name:        "No approved provider",
+			rating:      4,
+			request:     completedRequest("request123", "householder123"),
+			approvedErr: errors.New("approved provider not found"),
+			expectedErr: model.ErrReviewNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+			mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+			householderService := service.NewHouseholderService(nil, mockProviderRepo, nil, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), nil, nil)
+
+			if tt.request != nil {
+				mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "request123").Return(tt.request, nil)
+			}
+			if tt.approvedErr != nil {
+				mockServiceRequestRepo.EXPECT().GetApprovedProvider(gomock.Any(), "request123").Return(nil, tt.approvedErr)
+			}
+			mockProviderRepo.EXPECT().AddReview(gomock.Any(), gomock.Any()).Times(0)
+
+			err := householderService.AddReview(context.Background(), "householder123", "request123", "Great", tt.rating, tt.scores)
+
+			assert.ErrorIs(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestAddReview_SecondReviewOfRequest(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	ratingService, mockRatingRepo, _ := newRatingService(ctrl)
+	householderService := service.NewHouseholderService(nil, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, ratingService, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "request123").
+		Return(completedRequest("request123", "householder123"), nil)
+	mockServiceRequestRepo.EXPECT().GetApprovedProvider(gomock.Any(), "request123").
+		Return(&model.ServiceProviderDetails{ServiceProviderID: "provider123", Approve: true}, nil)
+	mockServiceRepo.EXPECT().GetServiceByID(gomock.Any(), "service123").Return(nil, errors.New("service not found"))
+	mockProviderRepo.EXPECT().AddReview(gomock.Any(), gomock.Any()).Return(model.ErrAlreadyReviewed)
+	mockRatingRepo.EXPECT().LockProviderRatings(gomock.Any(), gomock.Any()).Times(0)
+
+	err := householderService.AddReview(context.Background(), "householder123", "request123", "Again", 3, nil)
+
+	assert.ErrorIs(t, err, model.ErrAlreadyReviewed)
+}
+
+func TestEditReview(t *testing.T) {
+	recent := model.Review{ID: "review123", HouseholderID: "householder123", ProviderID: "provider123", Rating: 2, ReviewDate: time.Now().Add(-time.Hour)}
+	old := recent
+	old.ReviewDate = time.Now().Add(-model.ReviewEditWindow - time.Hour)
+
+	tests := []struct {
+		name          string
+		householderID string
+		rating        float64
+		review        model.Review
+		expectUpdate  bool
+		expectedErr   error
+	}{
+		{
+			name:          "Changed within the window",
+			householderID: "householder123",
+			rating:        4,
+			review:        recent,
+			expectUpdate:  true,
+		},
+		{
+			name:          "Window has closed",
+			householderID: "householder123",
+			rating:        4,
+			review:        old,
+			expectedErr:   model.ErrReviewEditClosed,
+		},
+		{
+			name:          "Review of another householder",
+			householderID: "householder456",
+			rating:        4,
+			review:        recent,
+			expectedErr:   errors.New("review not found"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+			ratingService, mockRatingRepo, _ := newRatingService(ctrl)
+			householderService := service.NewHouseholderService(nil, mockProviderRepo, nil, nil, nil, ratingService, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+			review := tt.review
+			mockProviderRepo.EXPECT().LockReviewByID(gomock.Any(), "review123").Return(&review, nil)
+			if tt.expectUpdate {
+				mockProviderRepo.EXPECT().UpdateReview(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, updated model.Review) error {
+						assert.Equal(t, tt.rating, updated.Rating)
+						assert.Equal(t, "Better now", updated.Comments)
+						assert.NotNil(t, updated.UpdatedAt)
+						return nil
+					})
+				mockRatingRepo.EXPECT().LockProviderRatings(gomock.Any(), "provider123").Return(nil, nil)
+				expectRatingUpdate(mockRatingRepo, "provider123")
+			}
+
+			err := householderService.EditReview(context.Background(), tt.householderID, "review123", "Better now", tt.rating, nil)
+
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
 func TestRequestService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -652,7 +915,7 @@ func TestRequestService(t *testing.T) {
 	}
 	defer func() { service.GetUniqueID = originalGenerateUniqueID }()
 
-	householderService := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	householderService := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	householder := &model.Householder{
 		User: model.User{
@@ -669,7 +932,6 @@ func TestRequestService(t *testing.T) {
 		name              string
 		getServiceErr     error
 		service           *model.Service
-		saveServiceErr    error
 		saveRequestErr    error
 		expectedRequestID string
 		expectedErr       error
@@ -679,7 +941,6 @@ func TestRequestService(t *testing.T) {
 			name:              "Service exists, successful request creation",
 			getServiceErr:     nil,
 			service:           &model.Service{ID: "existingServiceID"},
-			saveServiceErr:    nil,
 			saveRequestErr:    nil,
 			expectedRequestID: "uniqueID",
 			expectedErr:       nil,
@@ -688,7 +949,6 @@ func TestRequestService(t *testing.T) {
 			name:              "Error fetching service",
 			getServiceErr:     errors.New("service fetch error"),
 			service:           nil,
-			saveServiceErr:    nil,
 			saveRequestErr:    nil,
 			expectedRequestID: "",
 			expectedErr:       errors.New("service fetch error"),
@@ -699,42 +959,29 @@ func TestRequestService(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Mock the call to GetServiceByName based on the test case
 			mockServiceRepo.EXPECT().
-				GetServiceByName(serviceName).
+				GetServiceByName(gomock.Any(), serviceName).
 				Return(tt.service, tt.getServiceErr).
 				Times(1)
 
-			// If there's an error fetching the service, SaveService and SaveServiceRequest should NOT be called
-			if tt.getServiceErr != nil {
-				mockServiceRepo.EXPECT().
-					SaveService(gomock.Any()).
-					Times(0)
+			// No placeholder service is ever created
+			mockServiceRepo.EXPECT().
+				SaveService(gomock.Any(), gomock.Any()).
+				Times(0)
 
+			// If there's an error fetching the service, SaveServiceRequest should NOT be called
+			if tt.getServiceErr != nil {
 				mockServiceRequestRepo.EXPECT().
-					SaveServiceRequest(gomock.Any()).
+					SaveServiceRequest(gomock.Any(), gomock.Any()).
 					Times(0)
 			} else {
-				// If the service is not found, expect SaveService to be called
-				if tt.service == nil {
-					mockServiceRepo.EXPECT().
-						SaveService(gomock.Any()).
-						Return(tt.saveServiceErr).
-						Times(1)
-				} else {
-					// Ensure SaveService is not called if the service exists
-					mockServiceRepo.EXPECT().
-						SaveService(gomock.Any()).
-						Times(0)
-				}
-
-				// Mock the call to SaveServiceRequest, which should always be called
 				mockServiceRequestRepo.EXPECT().
-					SaveServiceRequest(gomock.Any()).
+					SaveServiceRequest(gomock.Any(), gomock.Any()).
 					Return(tt.saveRequestErr).
 					Times(1)
 			}
 
 			// Call the method under test
-			requestID, err := householderService.RequestService(householder, serviceName, &scheduledTime)
+			requestID, err := householderService.RequestService(context.Background(), householder, serviceName, &scheduledTime)
 
 			// Assert results
 			assert.Equal(t, tt.expectedRequestID, requestID)
@@ -752,7 +999,7 @@ func TestGetAvailableServices(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	service := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	services := []model.Service{
 		{
@@ -761,9 +1008,9 @@ func TestGetAvailableServices(t *testing.T) {
 			Price:       500,
 		},
 	}
-	mockServiceRepo.EXPECT().GetAllServices().Return(services, nil)
+	mockServiceRepo.EXPECT().GetAllServices(gomock.Any(), model.QueryOptions{}).Return(services, nil)
 
-	availableServices, err := service.GetAvailableServices()
+	availableServices, err := service.GetAvailableServices(context.Background())
 	assert.Equal(t, services, availableServices)
 	assert.Nil(t, err)
 }
@@ -783,7 +1030,7 @@ func TestRequestService_ALL(t *testing.T) {
 	}
 	defer func() { service.GetUniqueID = originalGenerateUniqueID }()
 
-	householderService := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo)
+	householderService := service.NewHouseholderService(mockHouseholderRepo, mockProviderRepo, mockServiceRepo, mockServiceRequestRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	householder := &model.Householder{
 		User: model.User{
@@ -800,7 +1047,6 @@ func TestRequestService_ALL(t *testing.T) {
 		name              string
 		getServiceErr     error
 		service           *model.Service
-		saveServiceErr    error
 		saveRequestErr    error
 		expectedRequestID string
 		expectedErr       error
@@ -809,7 +1055,6 @@ func TestRequestService_ALL(t *testing.T) {
 			name:              "Service exists, successful request creation",
 			getServiceErr:     nil,
 			service:           &model.Service{ID: "existingServiceID"},
-			saveServiceErr:    nil,
 			saveRequestErr:    nil,
 			expectedRequestID: "uniqueID",
 			expectedErr:       nil,
@@ -818,28 +1063,25 @@ func TestRequestService_ALL(t *testing.T) {
 			name:              "Error fetching service",
 			getServiceErr:     errors.New("service fetch error"),
 			service:           nil,
-			saveServiceErr:    nil,
 			saveRequestErr:    nil,
 			expectedRequestID: "",
 			expectedErr:       errors.New("service fetch error"),
 		},
 		{
-			name:              "Service does not exist, successful custom service creation",
-			getServiceErr:     nil,
+			name:              "Service does not exist",
+			getServiceErr:     errors.New("service not found"),
 			service:           nil,
-			saveServiceErr:    nil,
 			saveRequestErr:    nil,
-			expectedRequestID: "uniqueID",
-			expectedErr:       nil,
+			expectedRequestID: "",
+			expectedErr:       model.ErrServiceNotOffered,
 		},
 		{
-			name:              "Service does not exist, error creating custom service",
+			name:              "Service exists, error saving request",
 			getServiceErr:     nil,
-			service:           nil,
-			saveServiceErr:    errors.New("save service error"),
-			saveRequestErr:    nil,
+			service:           &model.Service{ID: "existingServiceID"},
+			saveRequestErr:    errors.New("save request error"),
 			expectedRequestID: "",
-			expectedErr:       errors.New("save service error"),
+			expectedErr:       errors.New("save request error"),
 		},
 	}
 
@@ -847,50 +1089,24 @@ func TestRequestService_ALL(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			// Mock the call to GetServiceByName based on the test case
 			mockServiceRepo.EXPECT().
-				GetServiceByName(serviceName).
+				GetServiceByName(gomock.Any(), serviceName).
 				Return(tt.service, tt.getServiceErr).
 				Times(1)
 
-			// If there's an error fetching the service, SaveService and SaveServiceRequest should NOT be called
+			// If there's an error fetching the service, SaveServiceRequest should NOT be called
 			if tt.getServiceErr != nil {
-				mockServiceRepo.EXPECT().
-					SaveService(gomock.Any()).
-					Times(0)
-
 				mockServiceRequestRepo.EXPECT().
-					SaveServiceRequest(gomock.Any()).
+					SaveServiceRequest(gomock.Any(), gomock.Any()).
 					Times(0)
 			} else {
-				// If the service is not found, expect SaveService to be called
-				if tt.service == nil {
-					mockServiceRepo.EXPECT().
-						SaveService(gomock.Any()).
-						Return(tt.saveServiceErr).
-						Times(1)
-					// If there's an error saving the custom service, do not expect SaveServiceRequest to be called
-					if tt.saveServiceErr != nil {
-						mockServiceRequestRepo.EXPECT().
-							SaveServiceRequest(gomock.Any()).
-							Times(0)
-					}
-				} else {
-					// Ensure SaveService is not called if the service exists
-					mockServiceRepo.EXPECT().
-						SaveService(gomock.Any()).
-						Times(0)
-				}
-
-				// Mock the call to SaveServiceRequest
-				if tt.saveServiceErr == nil {
-					mockServiceRequestRepo.EXPECT().
-						SaveServiceRequest(gomock.Any()).
-						Return(tt.saveRequestErr).
-						Times(1)
-				}
+				mockServiceRequestRepo.EXPECT().
+					SaveServiceRequest(gomock.Any(), gomock.Any()).
+					Return(tt.saveRequestErr).
+					Times(1)
 			}
 
 			// Call the method under test
-			requestID, err := householderService.RequestService(householder, serviceName, &scheduledTime)
+			requestID, err := householderService.RequestService(context.Background(), householder, serviceName, &scheduledTime)
 
 			// Assert results
 			assert.Equal(t, tt.expectedRequestID, requestID)
@@ -898,3 +1114,38 @@ func TestRequestService_ALL(t *testing.T) {
 		})
 	}
 }
+
+func TestRequestCustomService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCustomRequestRepo := mocks.NewMockCustomRequestRepository(ctrl)
+
+	originalGenerateUniqueID := service.GetUniqueID
+	service.GetUniqueID = func() string {
+		return "customID"
+	}
+	defer func() { service.GetUniqueID = originalGenerateUniqueID }()
+
+	householderService := service.NewHouseholderService(nil, nil, nil, nil, mockCustomRequestRepo, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+	householder := &model.Householder{User: model.User{ID: "householderID", Name: "John Doe", Address: "123 Main St"}}
+	scheduledTime := time.Now().Add(24 * time.Hour)
+
+	mockCustomRequestRepo.EXPECT().
+		SaveCustomRequest(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, request model.CustomRequest) error {
+			assert.Equal(t, "customID", request.ID)
+			assert.Equal(t, "householderID", request.HouseholderID)
+			assert.Equal(t, "Pool Cleaning", request.ServiceName)
+			assert.Equal(t, model.CustomRequestOpen, request.Status)
+			assert.Equal(t, scheduledTime, request.ScheduledTime)
+			return nil
+		})
+
+	requestID, err := householderService.RequestCustomService(context.Background(), householder, " Pool Cleaning ", &scheduledTime)
+	assert.NoError(t, err)
+	assert.Equal(t, "customID", requestID)
+
+	_, err = householderService.RequestCustomService(context.Background(), householder, " ", &scheduledTime)
+	assert.EqualError(t, err, "service name is required")
+}
@@ -0,0 +1,82 @@
+package service_test
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/service"
+	"strings"
+	"testing"
+)
+
+func newMessageRelayService(ctrl *gomock.Controller) (*service.MessageRelayService, serviceMocks) {
+	m := newServiceMocks(ctrl)
+	relayService := service.NewMessageRelayService(m.userRepo, m.serviceRequestRepo, m.notificationRepo, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+	return relayService, m
+}
+
+func relayRequest() *model.ServiceRequest {
+	householderID := "h1"
+	return &model.ServiceRequest{ID: "request1", HouseholderID: &householderID, ServiceName: "Plumbing", Status: "Accepted"}
+}
+
+func TestSendMessage_HouseholderToProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	relayService, m := newMessageRelayService(ctrl)
+
+	m.serviceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "request1").Return(relayRequest(), nil)
+	m.serviceRequestRepo.EXPECT().GetServiceProviderByRequestID(gomock.Any(), "request1", "p1").Return(relayRequest(), nil)
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "h1").Return(&model.User{ID: "h1", Name: "Jane", Contact: "9876543210"}, nil)
+	var delivered model.Notification
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notification model.Notification) error {
+			delivered = notification
+			return nil
+		})
+
+	err := relayService.SendMessage(context.Background(), "h1", "request1", "p1", "  Is Tuesday morning fine?  ")
+	assert.NoError(t, err)
+	assert.Equal(t, "p1", delivered.UserID)
+	assert.Contains(t, delivered.Message, model.ContactReference("request1"))
+	assert.Contains(t, delivered.Message, "Jane")
+	assert.True(t, strings.HasSuffix(delivered.Message, ": Is Tuesday morning fine?"))
+	assert.NotContains(t, delivered.Message, "9876543210")
+}
+
+func TestSendMessage_ProviderToHouseholder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	relayService, m := newMessageRelayService(ctrl)
+
+	m.serviceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "request1").Return(relayRequest(), nil)
+	m.serviceRequestRepo.EXPECT().GetServiceProviderByRequestID(gomock.Any(), "request1", "p1").Return(relayRequest(), nil)
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "p1").Return(&model.User{ID: "p1", Name: "Bob's Plumbing"}, nil)
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notification model.Notification) error {
+			assert.Equal(t, "h1", notification.UserID)
+			return nil
+		})
+
+	assert.NoError(t, relayService.SendMessage(context.Background(), "p1", "request1", "h1", "I can come at 9"))
+}
+
+func TestSendMessage_OnlyBetweenParties(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	relayService, m := newMessageRelayService(ctrl)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, relayService.SendMessage(ctx, "h1", "request1", "p1", "   "), model.ErrEmptyMessage)
+	assert.ErrorIs(t, relayService.SendMessage(ctx, "h1", "request1", "p1", strings.Repeat("a", model.MaxRelayMessageLength+1)), model.ErrMessageTooLong)
+
+	m.serviceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "request1").Return(relayRequest(), nil).Times(3)
+	// Neither side is the householder
+	assert.ErrorIs(t, relayService.SendMessage(ctx, "p1", "request1", "p2", "hello"), model.ErrNotRequestParty)
+	// The householder cannot message themselves
+	assert.ErrorIs(t, relayService.SendMessage(ctx, "h1", "request1", "h1", "hello"), model.ErrNotRequestParty)
+	// A provider who did not quote on the request is not a party to it
+	m.serviceRequestRepo.EXPECT().GetServiceProviderByRequestID(gomock.Any(), "request1", "p3").Return(nil, assert.AnError)
+	assert.ErrorIs(t, relayService.SendMessage(ctx, "p3", "request1", "h1", "hello"), model.ErrNotRequestParty)
+}
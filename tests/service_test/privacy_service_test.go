@@ -0,0 +1,274 @@
+package service_test
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/interfaces"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"testing"
+)
+
+// newPrivacyService shares its repositories with the account service it closes accounts through, both
+// write to auditRepo
+func newPrivacyService(ctrl *gomock.Controller, auditRepo interfaces.AuditRepository) (*service.PrivacyService, serviceMocks) {
+	m := newServiceMocks(ctrl)
+	txManager := passthroughTransactions(ctrl)
+	accountService := service.NewAccountService(m.userRepo, m.accountStatusRepo, m.providerRepo, m.serviceRequestRepo, m.notificationRepo,
+		m.providerIndexer, auditRepo, txManager)
+	privacyService := service.NewPrivacyService(m.userRepo, m.accountStatusRepo, m.roleHistoryRepo, m.serviceRequestRepo, m.customRequestRepo,
+		m.providerRepo, m.documentRepo, m.photoRepo, m.profileRepo, m.favouriteRepo, m.notificationRepo, m.loginAttemptRepo, m.tokenRepo, m.twoFactorRepo, m.blobStore, accountService, auditRepo, txManager)
+	return privacyService, m
+}
+
+// collectAuditEntries records every entry written to the audit log
+func collectAuditEntries(ctrl *gomock.Controller) (*mocks.MockAuditRepository, *[]model.AuditEntry) {
+	auditRepo := mocks.NewMockAuditRepository(ctrl)
+	var entries []model.AuditEntry
+	auditRepo.EXPECT().AppendEntry(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, entry *model.AuditEntry) error {
+		entries = append(entries, *entry)
+		return nil
+	}).AnyTimes()
+	return auditRepo, &entries
+}
+
+func TestExportUserData_Householder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	auditRepo, entries := collectAuditEntries(ctrl)
+	privacyService, m := newPrivacyService(ctrl, auditRepo)
+
+	user := &model.User{ID: "h1", Name: "Asha", Email: "Asha@Example.com", Password: "hash", Role: model.RoleHouseholder}
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "h1").Return(user, nil)
+	m.accountStatusRepo.EXPECT().GetStatusHistory(gomock.Any(), "h1").Return(nil, nil)
+	m.roleHistoryRepo.EXPECT().GetRoleHistory(gomock.Any(), "h1").Return(nil, nil)
+	m.notificationRepo.EXPECT().GetNotificationsByUserID(gomock.Any(), "h1", false).Return([]model.Notification{{ID: "n1"}}, nil)
+	m.loginAttemptRepo.EXPECT().GetAuditEntries(gomock.Any(), "asha@example.com", gomock.Any()).Return(nil, nil)
+	m.serviceRequestRepo.EXPECT().GetServiceRequestsByHouseholderID(gomock.Any(), "h1", model.QueryOptions{}).
+		Return([]model.ServiceRequest{{ID: "r1"}}, nil)
+	m.customRequestRepo.EXPECT().GetCustomRequestsByHouseholderID(gomock.Any(), "h1").Return([]model.CustomRequest{{ID: "c1"}}, nil)
+	m.providerRepo.EXPECT().GetReviewsByHouseholderID(gomock.Any(), "h1").Return([]model.Review{{ID: "rv1", Rating: 4}}, nil)
+	m.photoRepo.EXPECT().GetPhotosByHouseholderID(gomock.Any(), "h1").Return([]model.ReviewPhoto{{ID: "ph1", ReviewID: "rv1"}}, nil)
+	m.favouriteRepo.EXPECT().GetFavourites(gomock.Any(), "h1").Return([]model.FavouriteProvider{{HouseholderID: "h1", ProviderID: "p1"}}, nil)
+
+	export, err := privacyService.ExportUserData(context.Background(), "h1", "h1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Asha", export.Profile.Name)
+	assert.Empty(t, export.Profile.Password)
+	assert.Equal(t, model.AccountActive, export.Profile.Status)
+	assert.Len(t, export.ServiceRequests, 1)
+	assert.Len(t, export.CustomRequests, 1)
+	assert.Len(t, export.ReviewsWritten, 1)
+	assert.Len(t, export.ReviewPhotos, 1)
+	assert.Len(t, export.Favourites, 1)
+	assert.Len(t, export.Notifications, 1)
+	assert.Nil(t, export.Provider)
+	if assert.Len(t, *entries, 1) {
+		assert.Equal(t, "export personal data", (*entries)[0].Action)
+		assert.Equal(t, "h1", (*entries)[0].ActorID)
+	}
+}
+
+func TestExportUserData_ProviderLeavesOutOtherPeople(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	privacyService, m := newPrivacyService(ctrl, auditLog(ctrl))
+
+	householderID := "h1"
+	address := "12 Park Road"
+	user := &model.User{ID: "p1", Name: "Ravi", Email: "ravi@example.com", Role: model.RoleServiceProvider}
+	// The admin is read for the role and for the account status
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "admin1").Return(&model.User{ID: "admin1", Role: model.RoleAdmin}, nil).Times(2)
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "p1").Return(user, nil)
+	m.accountStatusRepo.EXPECT().GetStatusHistory(gomock.Any(), "p1").Return(nil, nil)
+	m.roleHistoryRepo.EXPECT().GetRoleHistory(gomock.Any(), "p1").Return(nil, nil)
+	m.notificationRepo.EXPECT().GetNotificationsByUserID(gomock.Any(), "p1", false).Return(nil, nil)
+	m.loginAttemptRepo.EXPECT().GetAuditEntries(gomock.Any(), "ravi@example.com", gomock.Any()).Return(nil, nil)
+	m.serviceRequestRepo.EXPECT().GetServiceRequestsByHouseholderID(gomock.Any(), "p1", model.QueryOptions{}).Return(nil, nil)
+	m.customRequestRepo.EXPECT().GetCustomRequestsByHouseholderID(gomock.Any(), "p1").Return(nil, nil)
+	m.providerRepo.EXPECT().GetReviewsByHouseholderID(gomock.Any(), "p1").Return(nil, nil)
+	m.photoRepo.EXPECT().GetPhotosByHouseholderID(gomock.Any(), "p1").Return(nil, nil)
+	m.favouriteRepo.EXPECT().GetFavourites(gomock.Any(), "p1").Return(nil, nil)
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(&model.ServiceProvider{User: model.User{ID: "p1", Password: "hash"}, Rating: 4.5}, nil)
+	m.serviceRequestRepo.EXPECT().GetServiceRequestsByProviderID(gomock.Any(), "p1").Return([]model.ServiceRequest{
+		{ID: "r1", HouseholderID: &householderID, HouseholderName: "Asha", HouseholderAddress: &address},
+	}, nil)
+	m.providerRepo.EXPECT().GetReviewsByProviderID(gomock.Any(), "p1").Return([]model.Review{{ID: "rv1", HouseholderID: householderID, Rating: 5}}, nil)
+	m.documentRepo.EXPECT().GetDocumentsByProviderID(gomock.Any(), "p1").Return([]model.ProviderDocument{{ID: "d1"}}, nil)
+	m.profileRepo.EXPECT().GetProfileDetails(gomock.Any(), "p1").Return(&model.ProviderProfileDetails{ProviderID: "p1", Bio: "Plumber"}, nil)
+	m.profileRepo.EXPECT().GetPortfolioImages(gomock.Any(), "p1").Return([]model.PortfolioImage{{ID: "i1"}}, nil)
+
+	export, err := privacyService.ExportUserData(context.Background(), "admin1", "p1")
+	assert.NoError(t, err)
+	if assert.NotNil(t, export.Provider) {
+		assert.Equal(t, 4.5, export.Provider.Rating)
+		assert.Empty(t, export.Provider.Password)
+	}
+	if assert.Len(t, export.ProviderJobs, 1) {
+		assert.Nil(t, export.ProviderJobs[0].HouseholderID)
+		assert.Empty(t, export.ProviderJobs[0].HouseholderName)
+		assert.Nil(t, export.ProviderJobs[0].HouseholderAddress)
+	}
+	if assert.Len(t, export.ReviewsReceived, 1) {
+		assert.Empty(t, export.ReviewsReceived[0].HouseholderID)
+		assert.Equal(t, 5.0, export.ReviewsReceived[0].Rating)
+	}
+	assert.Len(t, export.Documents, 1)
+	if assert.NotNil(t, export.PublicProfile) {
+		assert.Equal(t, "Plumber", export.PublicProfile.Bio)
+	}
+	assert.Len(t, export.Portfolio, 1)
+}
+
+func TestExportUserData_OthersRequireAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	privacyService, m := newPrivacyService(ctrl, auditLog(ctrl))
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "h2").Return(&model.User{ID: "h2", Role: model.RoleSupport}, nil)
+
+	_, err := privacyService.ExportUserData(context.Background(), "h2", "h1")
+	assert.ErrorIs(t, err, model.ErrAdminRequired)
+}
+
+func TestEraseOwnData(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	auditRepo, entries := collectAuditEntries(ctrl)
+	privacyService, m := newPrivacyService(ctrl, auditRepo)
+
+	user := &model.User{ID: "h1", Name: "Asha", Email: "asha@example.com", Password: hashedPassword(t, "Secret@123"),
+		Address: "12 Park Road", Contact: "9876543210", Role: model.RoleHouseholder}
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "h1").Return(user, nil).Times(2)
+	m.userRepo.EXPECT().UpdateAccountStatus(gomock.Any(), "h1", model.AccountActive, model.AccountDeleted, "personal data erased", nil).Return(nil)
+	m.accountStatusRepo.EXPECT().SaveStatusChange(gomock.Any(), gomock.Any()).Return(nil)
+	m.serviceRequestRepo.EXPECT().GetServiceRequestsByHouseholderID(gomock.Any(), "h1", model.QueryOptions{}).Return(nil, nil)
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).Return(nil)
+
+	erasedEmail := model.ErasedEmail("h1")
+	m.userRepo.EXPECT().AnonymiseUser(gomock.Any(), "h1", model.ErasedName, erasedEmail).Return(nil)
+	m.serviceRequestRepo.EXPECT().AnonymiseHouseholder(gomock.Any(), "h1", model.ErasedName).Return(nil)
+	m.serviceRequestRepo.EXPECT().AnonymiseProviderDetails(gomock.Any(), "h1", model.ErasedName).Return(nil)
+	m.customRequestRepo.EXPECT().AnonymiseHouseholder(gomock.Any(), "h1", model.ErasedName).Return(nil)
+	m.providerRepo.EXPECT().ClearReviewComments(gomock.Any(), "h1").Return(nil)
+	m.photoRepo.EXPECT().GetPhotosByHouseholderID(gomock.Any(), "h1").Return([]model.ReviewPhoto{{ID: "ph1", BlobKey: "review-photos/rv1/ph1.jpg"}}, nil)
+	m.photoRepo.EXPECT().DeletePhotosByHouseholderID(gomock.Any(), "h1").Return(nil)
+	m.blobStore.EXPECT().Delete(gomock.Any(), "review-photos/rv1/ph1.jpg").Return(nil)
+	m.favouriteRepo.EXPECT().DeleteFavouritesByUserID(gomock.Any(), "h1").Return(nil)
+	m.notificationRepo.EXPECT().DeleteNotifications(gomock.Any(), "h1").Return(nil)
+	m.loginAttemptRepo.EXPECT().AnonymiseAuditEntries(gomock.Any(), "asha@example.com", erasedEmail).Return(nil)
+	m.loginAttemptRepo.EXPECT().ResetThrottle(gomock.Any(), model.ThrottleAccount, "asha@example.com").Return(nil)
+	m.tokenRepo.EXPECT().DeleteTokens(gomock.Any(), "h1").Return(nil)
+	m.twoFactorRepo.EXPECT().DeleteTwoFactor(gomock.Any(), "h1").Return(nil)
+
+	err := privacyService.EraseOwnData(context.Background(), "h1", "Secret@123")
+	assert.NoError(t, err)
+
+	var actions []string
+	for _, entry := range *entries {
+		actions = append(actions, entry.Action)
+		for _, personal := range []string{"Asha", "asha@example.com", "12 Park Road", "9876543210"} {
+			assert.NotContains(t, entry.Before+entry.After, personal, "audit entry %q keeps erased data", entry.Action)
+		}
+	}
+	assert.Equal(t, []string{"change status", "erase personal data"}, actions)
+}
+
+func TestEraseOwnData_WrongPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	privacyService, m := newPrivacyService(ctrl, auditLog(ctrl))
+
+	user := &model.User{ID: "h1", Email: "asha@example.com", Password: hashedPassword(t, "Secret@123"), Role: model.RoleHouseholder}
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "h1").Return(user, nil)
+
+	err := privacyService.EraseOwnData(context.Background(), "h1", "Wrong@123")
+	assert.ErrorIs(t, err, model.ErrInvalidCredentials)
+}
+
+func TestEraseUserData_DeletedProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	privacyService, m := newPrivacyService(ctrl, auditLog(ctrl))
+
+	user := &model.User{ID: "p1", Name: "Ravi", Email: "ravi@example.com", Role: model.RoleServiceProvider, Status: model.AccountDeleted}
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "admin1").Return(&model.User{ID: "admin1", Role: model.RoleAdmin}, nil).Times(2)
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "p1").Return(user, nil)
+	m.userRepo.EXPECT().AnonymiseUser(gomock.Any(), "p1", model.ErasedName, model.ErasedEmail("p1")).Return(nil)
+	m.serviceRequestRepo.EXPECT().AnonymiseHouseholder(gomock.Any(), "p1", model.ErasedName).Return(nil)
+	m.serviceRequestRepo.EXPECT().AnonymiseProviderDetails(gomock.Any(), "p1", model.ErasedName).Return(nil)
+	m.customRequestRepo.EXPECT().AnonymiseHouseholder(gomock.Any(), "p1", model.ErasedName).Return(nil)
+	m.providerRepo.EXPECT().ClearReviewComments(gomock.Any(), "p1").Return(nil)
+	m.photoRepo.EXPECT().GetPhotosByHouseholderID(gomock.Any(), "p1").Return(nil, nil)
+	m.photoRepo.EXPECT().DeletePhotosByHouseholderID(gomock.Any(), "p1").Return(nil)
+	m.favouriteRepo.EXPECT().DeleteFavouritesByUserID(gomock.Any(), "p1").Return(nil)
+	m.notificationRepo.EXPECT().DeleteNotifications(gomock.Any(), "p1").Return(nil)
+	m.loginAttemptRepo.EXPECT().AnonymiseAuditEntries(gomock.Any(), "ravi@example.com", model.ErasedEmail("p1")).Return(nil)
+	m.loginAttemptRepo.EXPECT().ResetThrottle(gomock.Any(), model.ThrottleAccount, "ravi@example.com").Return(nil)
+	m.tokenRepo.EXPECT().DeleteTokens(gomock.Any(), "p1").Return(nil)
+	m.twoFactorRepo.EXPECT().DeleteTwoFactor(gomock.Any(), "p1").Return(nil)
+	m.documentRepo.EXPECT().GetDocumentsByProviderID(gomock.Any(), "p1").Return([]model.ProviderDocument{
+		{ID: "d1", BlobKey: "documents/p1/d1"}, {ID: "d2", BlobKey: "documents/p1/d2"},
+	}, nil)
+	m.documentRepo.EXPECT().DeleteDocumentsByProviderID(gomock.Any(), "p1").Return(nil)
+	m.profileRepo.EXPECT().GetPortfolioImages(gomock.Any(), "p1").Return([]model.PortfolioImage{{ID: "i1", BlobKey: "provider-portfolio/p1/i1.jpg"}}, nil)
+	m.profileRepo.EXPECT().DeleteProfile(gomock.Any(), "p1").Return(nil)
+	m.blobStore.EXPECT().Delete(gomock.Any(), "documents/p1/d1").Return(nil)
+	m.blobStore.EXPECT().Delete(gomock.Any(), "provider-portfolio/p1/i1.jpg").Return(nil)
+	m.blobStore.EXPECT().Delete(gomock.Any(), "documents/p1/d2").Return(assert.AnError)
+
+	// The account is already closed, so its status is left alone; a file that cannot be removed does not
+	// undo the erasure
+	err := privacyService.EraseUserData(context.Background(), "admin1", "p1")
+	assert.NoError(t, err)
+}
+
+func TestEraseUserData_Refused(t *testing.T) {
+	tests := []struct {
+		name    string
+		admin   *model.User
+		user    *model.User
+		wantErr error
+	}{
+		{
+			name:    "Not An Admin",
+			admin:   &model.User{ID: "admin1", Role: model.RoleSupport},
+			wantErr: model.ErrAdminRequired,
+		},
+		{
+			name:    "Suspended Admin",
+			admin:   &model.User{ID: "admin1", Role: model.RoleAdmin, Status: model.AccountSuspended},
+			wantErr: model.ErrAccountInactive,
+		},
+		{
+			name:    "Staff Account",
+			admin:   &model.User{ID: "admin1", Role: model.RoleAdmin},
+			user:    &model.User{ID: "u1", Email: "staff@example.com", Role: model.RoleSupport},
+			wantErr: model.ErrStaffErasure,
+		},
+		{
+			name:    "Already Erased",
+			admin:   &model.User{ID: "admin1", Role: model.RoleAdmin},
+			user:    &model.User{ID: "u1", Email: model.ErasedEmail("u1"), Role: model.RoleHouseholder, Status: model.AccountDeleted},
+			wantErr: model.ErrAlreadyErased,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			privacyService, m := newPrivacyService(ctrl, auditLog(ctrl))
+
+			m.userRepo.EXPECT().GetUserByID(gomock.Any(), "admin1").Return(tt.admin, nil).AnyTimes()
+			if tt.user != nil {
+				m.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(tt.user, nil)
+			}
+
+			err := privacyService.EraseUserData(context.Background(), "admin1", "u1")
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"serviceNest/model"
+	"serviceNest/service"
+	"strings"
+	"testing"
+)
+
+func newOnboardingService(ctrl *gomock.Controller) (*service.ProviderOnboardingService, serviceMocks) {
+	m := newServiceMocks(ctrl)
+	return service.NewProviderOnboardingService(m.providerRepo, m.documentRepo, m.notificationRepo, m.blobStore, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl)), m
+}
+
+func providerWithStatus(status string) *model.ServiceProvider {
+	return &model.ServiceProvider{User: model.User{ID: "p1"}, IsActive: true, VerificationStatus: status}
+}
+
+// storeBlob makes the mocked blob store consume the uploaded content like the real store does
+func storeBlob(_ context.Context, _ string, content io.Reader) (int64, error) {
+	return io.Copy(io.Discard, content)
+}
+
+func TestGetOrCreateProvider_NewProviderStartsPending(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	onboardingService, m := newOnboardingService(ctrl)
+
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(nil, errors.New("provider not found"))
+	m.providerRepo.EXPECT().SaveServiceProvider(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, provider model.ServiceProvider) error {
+			assert.Equal(t, model.VerificationPending, provider.VerificationStatus)
+			assert.True(t, provider.IsActive)
+			return nil
+		})
+
+	provider, err := onboardingService.GetOrCreateProvider(context.Background(), &model.User{ID: "p1", Name: "Mario"})
+
+	assert.NoError(t, err)
+	assert.False(t, provider.IsVerified())
+	assert.Equal(t, "Mario", provider.Name)
+}
+
+func TestSubmitDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	onboardingService, m := newOnboardingService(ctrl)
+
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(providerWithStatus(model.VerificationPending), nil)
+	m.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(storeBlob)
+	m.documentRepo.EXPECT().SaveDocument(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, document model.ProviderDocument) error {
+			assert.Equal(t, "p1", document.ProviderID)
+			assert.Equal(t, "passport.pdf", document.FileName)
+			assert.Equal(t, "provider-documents/p1/"+document.ID+".pdf", document.BlobKey)
+			assert.Equal(t, int64(8), document.Size)
+			return nil
+		})
+
+	document, err := onboardingService.SubmitDocument(context.Background(), "p1", model.DocumentIdentity, "/home/mario/passport.pdf", strings.NewReader("%PDF-1.7"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.DocumentIdentity, document.Type)
+}
+
+func TestSubmitDocument_ResubmissionAfterRejection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	onboardingService, m := newOnboardingService(ctrl)
+
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(providerWithStatus(model.VerificationRejected), nil)
+	m.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(storeBlob)
+	m.documentRepo.EXPECT().SaveDocument(gomock.Any(), gomock.Any()).Return(nil)
+	m.providerRepo.EXPECT().UpdateVerificationStatus(gomock.Any(), "p1", model.VerificationPending, "", "").Return(nil)
+
+	_, err := onboardingService.SubmitDocument(context.Background(), "p1", model.DocumentLicence, "licence.png", strings.NewReader("png"))
+
+	assert.NoError(t, err)
+}
+
+func TestSubmitDocument_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	onboardingService, m := newOnboardingService(ctrl)
+
+	_, err := onboardingService.SubmitDocument(context.Background(), "p1", "Passport", "passport.pdf", strings.NewReader("x"))
+	assert.EqualError(t, err, `unknown document type "Passport"`)
+
+	_, err = onboardingService.SubmitDocument(context.Background(), "p1", model.DocumentIdentity, "passport.docx", strings.NewReader("x"))
+	assert.EqualError(t, err, "documents must be PDF, JPEG or PNG files")
+
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(providerWithStatus(model.VerificationVerified), nil)
+	_, err = onboardingService.SubmitDocument(context.Background(), "p1", model.DocumentIdentity, "passport.pdf", strings.NewReader("x"))
+	assert.EqualError(t, err, "your account is already verified")
+}
+
+func TestSubmitDocument_TooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	onboardingService, m := newOnboardingService(ctrl)
+
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(providerWithStatus(model.VerificationPending), nil)
+	m.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(storeBlob)
+	m.blobStore.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+	m.documentRepo.EXPECT().SaveDocument(gomock.Any(), gomock.Any()).Times(0)
+
+	content := io.LimitReader(zeroReader{}, service.MaxDocumentSize+100)
+	_, err := onboardingService.SubmitDocument(context.Background(), "p1", model.DocumentIdentity, "scan.jpg", content)
+
+	assert.EqualError(t, err, "documents may not be larger than 10 MB")
+}
+
+func TestSubmitDocument_DeletesBlobWhenSaveFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	onboardingService, m := newOnboardingService(ctrl)
+
+	var storedKey string
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(providerWithStatus(model.VerificationPending), nil)
+	m.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, key string, content io.Reader) (int64, error) {
+			storedKey = key
+			return storeBlob(ctx, key, content)
+		})
+	m.documentRepo.EXPECT().SaveDocument(gomock.Any(), gomock.Any()).Return(errors.New("connection reset"))
+	m.blobStore.EXPECT().Delete(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string) error {
+			assert.Equal(t, storedKey, key)
+			return nil
+		})
+
+	_, err := onboardingService.SubmitDocument(context.Background(), "p1", model.DocumentIdentity, "passport.pdf", strings.NewReader("%PDF"))
+
+	assert.EqualError(t, err, "connection reset")
+}
+
+func TestApproveProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	onboardingService, m := newOnboardingService(ctrl)
+
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(providerWithStatus(model.VerificationPending), nil)
+	m.documentRepo.EXPECT().GetDocumentsByProviderID(gomock.Any(), "p1").
+		Return([]model.ProviderDocument{{ID: "d1", Type: model.DocumentLicence}, {ID: "d2", Type: model.DocumentIdentity}}, nil)
+	m.providerRepo.EXPECT().UpdateVerificationStatus(gomock.Any(), "p1", model.VerificationVerified, "checked in person", "admin1").Return(nil)
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notification model.Notification) error {
+			assert.Equal(t, "p1", notification.UserID)
+			assert.Contains(t, notification.Message, "verified")
+			return nil
+		})
+
+	assert.NoError(t, onboardingService.ApproveProvider(context.Background(), "admin1", "p1", " checked in person "))
+}
+
+func TestApproveProvider_RequiresIdentityDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	onboardingService, m := newOnboardingService(ctrl)
+
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(providerWithStatus(model.VerificationPending), nil)
+	m.documentRepo.EXPECT().GetDocumentsByProviderID(gomock.Any(), "p1").Return([]model.ProviderDocument{{ID: "d1", Type: model.DocumentInsurance}}, nil)
+	m.providerRepo.EXPECT().UpdateVerificationStatus(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	err := onboardingService.ApproveProvider(context.Background(), "admin1", "p1", "")
+
+	assert.EqualError(t, err, "the provider has not submitted an identity document")
+}
+
+func TestRejectProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	onboardingService, m := newOnboardingService(ctrl)
+
+	assert.EqualError(t, onboardingService.RejectProvider(context.Background(), "admin1", "p1", "  "), "a reason is required to reject a provider")
+
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(providerWithStatus(model.VerificationPending), nil)
+	m.providerRepo.EXPECT().UpdateVerificationStatus(gomock.Any(), "p1", model.VerificationRejected, "ID is unreadable", "admin1").Return(nil)
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notification model.Notification) error {
+			assert.Contains(t, notification.Message, "ID is unreadable")
+			return nil
+		})
+
+	assert.NoError(t, onboardingService.RejectProvider(context.Background(), "admin1", "p1", "ID is unreadable"))
+
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p2").Return(providerWithStatus(model.VerificationVerified), nil)
+	err := onboardingService.RejectProvider(context.Background(), "admin1", "p2", "too late")
+	assert.EqualError(t, err, "provider is not pending verification (status Verified)")
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
@@ -0,0 +1,264 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newProviderProfileService(ctrl *gomock.Controller) (*service.ProviderProfileService, serviceMocks) {
+	m := newServiceMocks(ctrl)
+	profileService := service.NewProviderProfileService(m.profileRepo, m.providerRepo, m.serviceRepo, m.serviceRequestRepo, m.blobStore,
+		m.ratingService(ctrl), activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+	return profileService, m
+}
+
+func publicProvider() *model.ServiceProvider {
+	return &model.ServiceProvider{
+		User:               model.User{ID: "p1", Name: "Ravi", Contact: "9876543210", Address: "12 Park Road"},
+		Availability:       true,
+		IsActive:           true,
+		VerificationStatus: model.VerificationVerified,
+	}
+}
+
+func TestGetProviderProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	profileService, m := newProviderProfileService(ctrl)
+
+	overall := model.RatingAggregate{ProviderID: "p1", Criterion: model.CriterionOverall}
+	overall.Add(5, time.Now(), 0)
+	m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(publicProvider(), nil)
+	m.profileRepo.EXPECT().GetProfileDetails(gomock.Any(), "p1").Return(&model.ProviderProfileDetails{
+		ProviderID: "p1", Bio: "Plumber since 2010", YearsExperience: 14, Languages: []string{"English", "Hindi"},
+		Certifications: []model.Certification{{ID: "c1", Name: "Gas Safe"}},
+	}, nil)
+	m.profileRepo.EXPECT().GetPortfolioImages(gomock.Any(), "p1").Return([]model.PortfolioImage{{ID: "i1"}}, nil)
+	m.serviceRepo.EXPECT().GetServiceByProviderID(gomock.Any(), "p1").Return([]model.Service{{ID: "s1", Name: "Leak repair", Price: 40}}, nil)
+	m.ratingRepo.EXPECT().GetProviderRatings(gomock.Any(), "p1").Return([]model.RatingAggregate{overall}, nil)
+	m.serviceRequestRepo.EXPECT().CountCompletedJobs(gomock.Any(), "p1").Return(7, nil)
+
+	profile, err := profileService.GetProviderProfile(context.Background(), "p1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Ravi", profile.Name)
+	assert.True(t, profile.Available)
+	assert.Equal(t, 14, profile.YearsExperience)
+	assert.Equal(t, []string{"English", "Hindi"}, profile.Languages)
+	assert.Len(t, profile.Certifications, 1)
+	assert.Len(t, profile.Portfolio, 1)
+	assert.Equal(t, 40.0, profile.Services[0].Price)
+	assert.Equal(t, 1, profile.Rating.All.Overall.ReviewCount)
+	assert.Equal(t, 7, profile.CompletedJobs)
+}
+
+func TestGetProviderProfile_NotPublic(t *testing.T) {
+	expired := time.Now().Add(time.Hour)
+	tests := []struct {
+		name   string
+		modify func(provider *model.ServiceProvider)
+	}{
+		{name: "Deactivated", modify: func(provider *model.ServiceProvider) { provider.IsActive = false }},
+		{name: "Not verified", modify: func(provider *model.ServiceProvider) { provider.VerificationStatus = model.VerificationPending }},
+		{name: "Suspended", modify: func(provider *model.ServiceProvider) {
+			provider.Status, provider.StatusExpiresAt = model.AccountSuspended, &expired
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			profileService, m := newProviderProfileService(ctrl)
+
+			provider := publicProvider()
+			tt.modify(provider)
+			m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(provider, nil)
+
+			_, err := profileService.GetProviderProfile(context.Background(), "p1")
+
+			assert.EqualError(t, err, "provider not found")
+		})
+	}
+}
+
+func TestUpdateProfileDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	profileService, m := newProviderProfileService(ctrl)
+
+	m.profileRepo.EXPECT().GetProfileDetails(gomock.Any(), "p1").Return(&model.ProviderProfileDetails{ProviderID: "p1"}, nil)
+	m.profileRepo.EXPECT().SaveProfileDetails(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, details model.ProviderProfileDetails) error {
+		assert.Equal(t, "Tidy and on time", details.Bio)
+		assert.Equal(t, []string{"English", "Hindi", "Marathi"}, details.Languages)
+		assert.NotNil(t, details.UpdatedAt)
+		return nil
+	})
+
+	err := profileService.UpdateProfileDetails(context.Background(), "p1", "  Tidy and on time ", 6, []string{"English, Hindi", "english", " ", "Marathi"})
+
+	assert.NoError(t, err)
+}
+
+func TestUpdateProfileDetails_Rejected(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	profileService, m := newProviderProfileService(ctrl)
+	m.profileRepo.EXPECT().SaveProfileDetails(gomock.Any(), gomock.Any()).Times(0)
+
+	err := profileService.UpdateProfileDetails(context.Background(), "p1", strings.Repeat("a", model.MaxBioLength+1), 1, nil)
+	assert.ErrorIs(t, err, model.ErrBioTooLong)
+	err = profileService.UpdateProfileDetails(context.Background(), "p1", "", -1, nil)
+	assert.EqualError(t, err, "years of experience must be between 0 and 80")
+}
+
+func TestAddCertification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	profileService, m := newProviderProfileService(ctrl)
+
+	m.profileRepo.EXPECT().SaveCertification(gomock.Any(), gomock.Any()).Return(nil)
+
+	certification, err := profileService.AddCertification(context.Background(), "p1", " Gas Safe ", "HSE", 2015)
+	assert.NoError(t, err)
+	assert.Equal(t, "Gas Safe", certification.Name)
+	assert.Equal(t, "p1", certification.ProviderID)
+
+	_, err = profileService.AddCertification(context.Background(), "p1", " ", "", 0)
+	assert.EqualError(t, err, "the certification needs a name")
+	_, err = profileService.AddCertification(context.Background(), "p1", "Gas Safe", "", time.Now().Year()+1)
+	assert.EqualError(t, err, "the year of the certification is not valid")
+}
+
+func TestAddPortfolioImage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	profileService, m := newProviderProfileService(ctrl)
+
+	m.profileRepo.EXPECT().GetPortfolioImages(gomock.Any(), "p1").Return(nil, nil)
+	m.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string, content io.Reader) (int64, error) {
+			assert.True(t, strings.HasPrefix(key, "provider-portfolio/p1/"))
+			assert.True(t, strings.HasSuffix(key, ".jpg"))
+			return storeBlob(context.Background(), key, content)
+		})
+	m.profileRepo.EXPECT().SavePortfolioImage(gomock.Any(), gomock.Any()).Return(nil)
+
+	image, err := profileService.AddPortfolioImage(context.Background(), "p1", "New bathroom", "/tmp/bath.JPG", strings.NewReader("jpeg"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "bath.JPG", image.FileName)
+	assert.Equal(t, "New bathroom", image.Caption)
+	assert.Equal(t, int64(4), image.Size)
+}
+
+func TestAddPortfolioImage_Refused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	profileService, m := newProviderProfileService(ctrl)
+
+	m.profileRepo.EXPECT().GetPortfolioImages(gomock.Any(), "p1").Return(make([]model.PortfolioImage, model.MaxPortfolioImages), nil)
+	m.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	_, err := profileService.AddPortfolioImage(context.Background(), "p1", "", "a.gif", strings.NewReader("x"))
+	assert.EqualError(t, err, "photos must be JPEG or PNG files")
+	_, err = profileService.AddPortfolioImage(context.Background(), "p1", "", "a.png", strings.NewReader("x"))
+	assert.ErrorIs(t, err, model.ErrTooManyPortfolioImages)
+}
+
+func TestAddPortfolioImage_SaveFailsDiscardsBlob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	profileService, m := newProviderProfileService(ctrl)
+
+	m.profileRepo.EXPECT().GetPortfolioImages(gomock.Any(), "p1").Return(nil, nil)
+	m.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(storeBlob)
+	m.profileRepo.EXPECT().SavePortfolioImage(gomock.Any(), gomock.Any()).Return(errors.New("db down"))
+	m.blobStore.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+
+	_, err := profileService.AddPortfolioImage(context.Background(), "p1", "", "a.png", strings.NewReader("x"))
+
+	assert.EqualError(t, err, "db down")
+}
+
+func TestRemovePortfolioImage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	profileService, m := newProviderProfileService(ctrl)
+
+	image := &model.PortfolioImage{ID: "i1", ProviderID: "p1", BlobKey: "provider-portfolio/p1/i1.jpg"}
+	m.profileRepo.EXPECT().GetPortfolioImageByID(gomock.Any(), "i1").Return(image, nil).Times(2)
+	m.profileRepo.EXPECT().DeletePortfolioImage(gomock.Any(), "p1", "i1").Return(nil)
+	m.blobStore.EXPECT().Delete(gomock.Any(), "provider-portfolio/p1/i1.jpg").Return(nil)
+
+	assert.EqualError(t, profileService.RemovePortfolioImage(context.Background(), "p2", "i1"), "image not found")
+	assert.NoError(t, profileService.RemovePortfolioImage(context.Background(), "p1", "i1"))
+}
+
+func TestRemoveCertification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	auditRepo := mocks.NewMockAuditRepository(ctrl)
+	profileRepo := mocks.NewMockProviderProfileRepository(ctrl)
+	profileService := service.NewProviderProfileService(profileRepo, nil, nil, nil, nil, nil, activeAccounts(ctrl), auditRepo, passthroughTransactions(ctrl))
+
+	certification := &model.Certification{ID: "c1", ProviderID: "p1", Name: "Gas Safe", Issuer: "Gas Safe Register", Year: 2015}
+	profileRepo.EXPECT().GetCertificationByID(gomock.Any(), "c1").Return(certification, nil).Times(2)
+	profileRepo.EXPECT().DeleteCertification(gomock.Any(), "p1", "c1").Return(nil)
+	auditRepo.EXPECT().AppendEntry(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, entry *model.AuditEntry) error {
+			assert.Equal(t, "remove certification", entry.Action)
+			assert.Contains(t, entry.Before, `"issuer":"Gas Safe Register"`)
+			assert.Empty(t, entry.After)
+			return nil
+		})
+
+	assert.EqualError(t, profileService.RemoveCertification(context.Background(), "p2", "c1"), "certification not found")
+	assert.NoError(t, profileService.RemoveCertification(context.Background(), "p1", "c1"))
+}
+
+func TestOpenPortfolioImage(t *testing.T) {
+	unlisted := publicProvider()
+	unlisted.IsActive = false
+	tests := []struct {
+		name        string
+		provider    *model.ServiceProvider
+		expectedErr string
+	}{
+		{name: "Listed provider", provider: publicProvider()},
+		{name: "Provider not listed", provider: unlisted, expectedErr: "image not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			profileService, m := newProviderProfileService(ctrl)
+
+			image := &model.PortfolioImage{ID: "i1", ProviderID: "p1", BlobKey: "provider-portfolio/p1/i1.jpg"}
+			m.profileRepo.EXPECT().GetPortfolioImageByID(gomock.Any(), "i1").Return(image, nil)
+			m.providerRepo.EXPECT().GetProviderByID(gomock.Any(), "p1").Return(tt.provider, nil)
+			if tt.expectedErr == "" {
+				m.blobStore.EXPECT().Open(gomock.Any(), image.BlobKey).Return(io.NopCloser(strings.NewReader("jpeg")), nil)
+			}
+
+			_, content, err := profileService.OpenPortfolioImage(context.Background(), "i1")
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			assert.NoError(t, err)
+			content.Close()
+		})
+	}
+}
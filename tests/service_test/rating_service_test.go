@@ -0,0 +1,210 @@
+package service_test
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"testing"
+	"time"
+)
+
+// testRatingOptions leave out the decay so scores do not depend on the clock
+var testRatingOptions = model.RatingOptions{PriorMean: 3.5, PriorWeight: 5}
+
+func newRatingService(ctrl *gomock.Controller) (*service.RatingService, *mocks.MockRatingRepository, *mocks.MockServiceProviderRepository) {
+	m := newServiceMocks(ctrl)
+	return m.ratingService(ctrl), m.ratingRepo, m.providerRepo
+}
+
+// expectRatingUpdate accepts the write of a provider's totals after they were locked and read
+func expectRatingUpdate(ratingRepo *mocks.MockRatingRepository, providerID string) {
+	ratingRepo.EXPECT().SaveRatingAggregates(gomock.Any(), gomock.Any()).Return(nil)
+	ratingRepo.EXPECT().SetProviderRating(gomock.Any(), providerID, gomock.Any()).Return(nil)
+}
+
+// overallAggregate is the stored overall total of a provider holding only review
+func overallAggregate(providerID string, review model.Review) model.RatingAggregate {
+	aggregate := model.RatingAggregate{ProviderID: providerID, Category: model.AllCategories, Criterion: model.CriterionOverall}
+	aggregate.Add(review.Rating, review.ReviewDate, 0)
+	return aggregate
+}
+
+func TestApplyReviewChange_AddsOverallCategoryAndCriteria(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ratingService, ratingRepo, _ := newRatingService(ctrl)
+
+	review := model.Review{ID: "review1", ProviderID: "p1", Category: "Plumbing", Rating: 5,
+		Scores: map[string]float64{model.CriterionPunctuality: 4}, ReviewDate: time.Now()}
+
+	ratingRepo.EXPECT().LockProviderRatings(gomock.Any(), "p1").Return(nil, nil)
+	ratingRepo.EXPECT().SaveRatingAggregates(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, aggregates []model.RatingAggregate) error {
+			assert.Len(t, aggregates, 4)
+			var keys []string
+			for _, aggregate := range aggregates {
+				keys = append(keys, aggregate.Category+"/"+aggregate.Criterion)
+				assert.Equal(t, 1, aggregate.ReviewCount)
+			}
+			assert.Equal(t, []string{"/overall", "/punctuality", "Plumbing/overall", "Plumbing/punctuality"}, keys)
+			return nil
+		})
+	// One five star review against five reviews of 3.5 stars
+	ratingRepo.EXPECT().SetProviderRating(gomock.Any(), "p1", 3.75).Return(nil)
+
+	assert.NoError(t, ratingService.ApplyReviewChange(context.Background(), nil, &review))
+}
+
+func TestApplyReviewChange_EditReplacesTheOldScore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ratingService, ratingRepo, _ := newRatingService(ctrl)
+
+	before := model.Review{ID: "review1", ProviderID: "p1", Rating: 2, ReviewDate: time.Now().Add(-time.Hour)}
+	after := before
+	after.Rating = 4
+
+	ratingRepo.EXPECT().LockProviderRatings(gomock.Any(), "p1").Return([]model.RatingAggregate{overallAggregate("p1", before)}, nil)
+	ratingRepo.EXPECT().SaveRatingAggregates(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, aggregates []model.RatingAggregate) error {
+			assert.Len(t, aggregates, 1)
+			assert.Equal(t, 1, aggregates[0].ReviewCount)
+			assert.Equal(t, 4.0, aggregates[0].Average())
+			return nil
+		})
+	ratingRepo.EXPECT().SetProviderRating(gomock.Any(), "p1", gomock.Any()).Return(nil)
+
+	assert.NoError(t, ratingService.ApplyReviewChange(context.Background(), &before, &after))
+}
+
+func TestApplyReviewChange_HiddenReviewsDoNotCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ratingService, ratingRepo, _ := newRatingService(ctrl)
+
+	review := model.Review{ID: "review1", ProviderID: "p1", Rating: 1, Hidden: true, ReviewDate: time.Now()}
+	ratingRepo.EXPECT().LockProviderRatings(gomock.Any(), gomock.Any()).Times(0)
+
+	assert.NoError(t, ratingService.ApplyReviewChange(context.Background(), nil, &review))
+}
+
+func TestApplyReviewChange_OlderReviewsWeighLess(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ratingRepo := mocks.NewMockRatingRepository(ctrl)
+	halfLife := 365 * 24 * time.Hour
+	providerIndexer := mocks.NewMockProviderIndexer(ctrl)
+	providerIndexer.EXPECT().ReindexProvider(gomock.Any(), "p1").Return(nil)
+	ratingService := service.NewRatingService(ratingRepo, nil, providerIndexer, model.RatingOptions{PriorMean: 3.5, HalfLife: halfLife}, nil)
+
+	now := time.Now()
+	stored := model.RatingAggregate{ProviderID: "p1", Criterion: model.CriterionOverall}
+	stored.Add(1, now.Add(-halfLife), halfLife)
+	review := model.Review{ID: "review2", ProviderID: "p1", Rating: 5, ReviewDate: now}
+
+	ratingRepo.EXPECT().LockProviderRatings(gomock.Any(), "p1").Return([]model.RatingAggregate{stored}, nil)
+	ratingRepo.EXPECT().SaveRatingAggregates(gomock.Any(), gomock.Any()).Return(nil)
+	ratingRepo.EXPECT().SetProviderRating(gomock.Any(), "p1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, rating float64) error {
+			// The year old one star review counts half: (1*0.5 + 5) / 1.5
+			assert.InDelta(t, 11.0/3, rating, 0.001)
+			return nil
+		})
+
+	assert.NoError(t, ratingService.ApplyReviewChange(context.Background(), nil, &review))
+}
+
+func TestGetProviderRating_Breakdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ratingService, ratingRepo, _ := newRatingService(ctrl)
+
+	at := time.Now()
+	aggregate := func(category, criterion string, scores ...float64) model.RatingAggregate {
+		aggregate := model.RatingAggregate{ProviderID: "p1", Category: category, Criterion: criterion}
+		for _, score := range scores {
+			aggregate.Add(score, at, 0)
+		}
+		return aggregate
+	}
+	ratingRepo.EXPECT().GetProviderRatings(gomock.Any(), "p1").Return([]model.RatingAggregate{
+		aggregate(model.AllCategories, model.CriterionOverall, 5, 3),
+		aggregate(model.AllCategories, model.CriterionQuality, 4),
+		aggregate("Cleaning", model.CriterionOverall, 3),
+		aggregate("Plumbing", model.CriterionOverall, 5),
+		aggregate("Roofing", model.CriterionOverall),
+	}, nil)
+
+	rating, err := ratingService.GetProviderRating(context.Background(), "p1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rating.All.Overall.ReviewCount)
+	assert.Equal(t, 4.0, rating.All.Overall.Average)
+	assert.InDelta(t, (5*3.5+8)/7, rating.All.Overall.Score, 0.0001)
+	assert.Equal(t, 1, rating.All.Criteria[model.CriterionQuality].ReviewCount)
+	// Categories without reviews left are not shown
+	assert.Len(t, rating.Categories, 2)
+	assert.Equal(t, "Cleaning", rating.Categories[0].Category)
+	assert.Equal(t, "Plumbing", rating.Categories[1].Category)
+}
+
+func TestRebuild_RecomputesFromVisibleReviews(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ratingService, ratingRepo, providerRepo := newRatingService(ctrl)
+
+	now := time.Now()
+	ratingRepo.EXPECT().GetRatedProviderIDs(gomock.Any()).Return([]string{"p1"}, nil)
+	providerRepo.EXPECT().GetReviewsByProviderID(gomock.Any(), "p1").Return([]model.Review{
+		{ID: "review1", ProviderID: "p1", Category: "Plumbing", Rating: 4, ReviewDate: now},
+		{ID: "review2", ProviderID: "p1", Category: "Plumbing", Rating: 1, Hidden: true, ReviewDate: now},
+	}, nil)
+	ratingRepo.EXPECT().ReplaceProviderRatings(gomock.Any(), "p1", gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, aggregates []model.RatingAggregate) error {
+			assert.Len(t, aggregates, 2)
+			for _, aggregate := range aggregates {
+				assert.Equal(t, 1, aggregate.ReviewCount)
+				assert.Equal(t, 4.0, aggregate.Average())
+			}
+			return nil
+		})
+	ratingRepo.EXPECT().SetProviderRating(gomock.Any(), "p1", gomock.Any()).Return(nil)
+
+	rebuilt, err := ratingService.Rebuild(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, rebuilt)
+}
+
+func TestRefreshScores_DecaysStoredScore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	ratingRepo := mocks.NewMockRatingRepository(ctrl)
+	providerIndexer := mocks.NewMockProviderIndexer(ctrl)
+	halfLife := 365 * 24 * time.Hour
+	ratingService := service.NewRatingService(ratingRepo, nil, providerIndexer, model.RatingOptions{PriorMean: 3.5, PriorWeight: 1, HalfLife: halfLife}, passthroughTransactions(ctrl))
+
+	// One five star review saved a year ago, when it still had its full weight
+	stored := model.RatingAggregate{ProviderID: "p1", Criterion: model.CriterionOverall}
+	stored.Add(5, time.Now().Add(-halfLife), halfLife)
+
+	ratingRepo.EXPECT().GetRatedProviderIDs(gomock.Any()).Return([]string{"p1"}, nil)
+	ratingRepo.EXPECT().LockProviderRatings(gomock.Any(), "p1").Return([]model.RatingAggregate{stored}, nil)
+	gomock.InOrder(
+		ratingRepo.EXPECT().SetProviderRating(gomock.Any(), "p1", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, rating float64) error {
+				// The review now counts half against the full weight of the prior: (3.5 + 5*0.5) / 1.5
+				assert.InDelta(t, 4.0, rating, 0.001)
+				return nil
+			}),
+		providerIndexer.EXPECT().ReindexProvider(gomock.Any(), "p1").Return(nil),
+	)
+
+	refreshed, err := ratingService.RefreshScores(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, refreshed)
+}
@@ -0,0 +1,327 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"serviceNest/model"
+	"serviceNest/service"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newReviewService(ctrl *gomock.Controller) (*service.ReviewService, serviceMocks) {
+	m := newServiceMocks(ctrl)
+	reviewService := service.NewReviewService(m.providerRepo, m.flagRepo, m.photoRepo, m.notificationRepo, m.blobStore, m.ratingService(ctrl), activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+	return reviewService, m
+}
+
+func moderatedReview() *model.Review {
+	return &model.Review{ID: "review1", ProviderID: "p1", HouseholderID: "h1", Rating: 1, Comments: "Rude", ReviewDate: time.Now()}
+}
+
+func TestReplyToReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviewService, m := newReviewService(ctrl)
+
+	m.providerRepo.EXPECT().GetReviewByID(gomock.Any(), "review1").Return(moderatedReview(), nil)
+	m.providerRepo.EXPECT().SaveReviewReply(gomock.Any(), "review1", "p1", "Sorry to hear that", gomock.Any()).Return(nil)
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notification model.Notification) error {
+			assert.Equal(t, "h1", notification.UserID)
+			return nil
+		})
+
+	assert.NoError(t, reviewService.ReplyToReview(context.Background(), "p1", "review1", " Sorry to hear that "))
+}
+
+func TestReplyToReview_Rejected(t *testing.T) {
+	replied := moderatedReview()
+	replied.Reply = "Thanks"
+
+	tests := []struct {
+		name        string
+		providerID  string
+		reply       string
+		review      *model.Review
+		expectedErr error
+	}{
+		{name: "Empty reply", providerID: "p1", reply: "  ", expectedErr: errors.New("the reply is empty")},
+		{name: "Reply too long", providerID: "p1", reply: strings.Repeat("a", model.MaxReplyLength+1), expectedErr: model.ErrReplyTooLong},
+		{name: "Review of another provider", providerID: "p2", reply: "Hi", review: moderatedReview(), expectedErr: errors.New("review not found")},
+		{name: "Second reply", providerID: "p1", reply: "Hi again", review: replied, expectedErr: model.ErrAlreadyReplied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			reviewService, m := newReviewService(ctrl)
+
+			if tt.review != nil {
+				m.providerRepo.EXPECT().GetReviewByID(gomock.Any(), "review1").Return(tt.review, nil)
+			}
+			m.providerRepo.EXPECT().SaveReviewReply(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			err := reviewService.ReplyToReview(context.Background(), tt.providerID, "review1", tt.reply)
+			assert.Equal(t, tt.expectedErr, err)
+		})
+	}
+}
+
+func TestFlagReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviewService, m := newReviewService(ctrl)
+
+	m.providerRepo.EXPECT().GetReviewByID(gomock.Any(), "review1").Return(moderatedReview(), nil).Times(2)
+	m.flagRepo.EXPECT().SaveFlag(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, flag model.ReviewFlag) error {
+			assert.Equal(t, "review1", flag.ReviewID)
+			assert.Equal(t, "p1", flag.UserID)
+			assert.Equal(t, "Insults my staff", flag.Reason)
+			return nil
+		})
+	m.flagRepo.EXPECT().SaveFlag(gomock.Any(), gomock.Any()).Return(model.ErrAlreadyFlagged)
+
+	assert.NoError(t, reviewService.FlagReview(context.Background(), "p1", "review1", "Insults my staff"))
+	assert.ErrorIs(t, reviewService.FlagReview(context.Background(), "p1", "review1", "Again"), model.ErrAlreadyFlagged)
+	assert.EqualError(t, reviewService.FlagReview(context.Background(), "p1", "review1", " "), "a reason is required to flag a review")
+}
+
+func TestGetModerationQueue_GroupsFlagsByReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviewService, m := newReviewService(ctrl)
+
+	m.flagRepo.EXPECT().GetOpenFlags(gomock.Any()).Return([]model.ReviewFlag{
+		{ID: "f1", ReviewID: "review1", UserID: "p1"},
+		{ID: "f2", ReviewID: "review2", UserID: "h2"},
+		{ID: "f3", ReviewID: "review1", UserID: "h3"},
+	}, nil)
+	m.providerRepo.EXPECT().GetReviewByID(gomock.Any(), "review1").Return(moderatedReview(), nil)
+	m.providerRepo.EXPECT().GetReviewByID(gomock.Any(), "review2").Return(&model.Review{ID: "review2"}, nil)
+
+	queue, err := reviewService.GetModerationQueue(context.Background())
+	assert.NoError(t, err)
+	assert.Len(t, queue, 2)
+	assert.Equal(t, "review1", queue[0].Review.ID)
+	assert.Len(t, queue[0].Flags, 2)
+	assert.Equal(t, "review2", queue[1].Review.ID)
+}
+
+func TestHideReview_ResolvesFlagsAndRecalculatesRating(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviewService, m := newReviewService(ctrl)
+
+	review := moderatedReview()
+	m.providerRepo.EXPECT().LockReviewByID(gomock.Any(), "review1").Return(review, nil)
+	gomock.InOrder(
+		m.providerRepo.EXPECT().UpdateReviewModeration(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, review model.Review) error {
+				assert.True(t, review.Hidden)
+				assert.Equal(t, "Abusive language", review.ModerationNote)
+				assert.Equal(t, "admin1", review.ModeratedBy)
+				assert.NotNil(t, review.ModeratedAt)
+				return nil
+			}),
+		m.flagRepo.EXPECT().ResolveFlags(gomock.Any(), "review1").Return(nil),
+		m.ratingRepo.EXPECT().LockProviderRatings(gomock.Any(), "p1").
+			Return([]model.RatingAggregate{overallAggregate("p1", *review)}, nil),
+		m.ratingRepo.EXPECT().SaveRatingAggregates(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, aggregates []model.RatingAggregate) error {
+				// The hidden review no longer counts towards the rating
+				assert.Len(t, aggregates, 1)
+				assert.Equal(t, 0, aggregates[0].ReviewCount)
+				return nil
+			}),
+		m.ratingRepo.EXPECT().SetProviderRating(gomock.Any(), "p1", 0.0).Return(nil),
+	)
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, notification model.Notification) error {
+			assert.Equal(t, "h1", notification.UserID)
+			assert.Contains(t, notification.Message, "Abusive language")
+			return nil
+		})
+
+	assert.NoError(t, reviewService.HideReview(context.Background(), "admin1", "review1", "Abusive language"))
+}
+
+func TestRestoreReview_DismissesFlagsOfVisibleReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviewService, m := newReviewService(ctrl)
+
+	review := moderatedReview()
+	m.providerRepo.EXPECT().LockReviewByID(gomock.Any(), "review1").Return(review, nil)
+	m.providerRepo.EXPECT().UpdateReviewModeration(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, review model.Review) error {
+			assert.False(t, review.Hidden)
+			return nil
+		})
+	m.flagRepo.EXPECT().ResolveFlags(gomock.Any(), "review1").Return(nil)
+	m.ratingRepo.EXPECT().LockProviderRatings(gomock.Any(), "p1").
+		Return([]model.RatingAggregate{overallAggregate("p1", *review)}, nil)
+	m.ratingRepo.EXPECT().SaveRatingAggregates(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, aggregates []model.RatingAggregate) error {
+			assert.Equal(t, 1, aggregates[0].ReviewCount)
+			return nil
+		})
+	m.ratingRepo.EXPECT().SetProviderRating(gomock.Any(), "p1", gomock.Any()).Return(nil)
+	// The review never left public view, so its author is not told anything
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).Times(0)
+
+	assert.NoError(t, reviewService.RestoreReview(context.Background(), "admin1", "review1", "Honest opinion"))
+	assert.EqualError(t, reviewService.RestoreReview(context.Background(), "admin1", "review1", ""), "a reason is required to moderate a review")
+}
+
+func TestAttachPhoto(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviewService, m := newReviewService(ctrl)
+
+	review := moderatedReview()
+	m.providerRepo.EXPECT().GetReviewByID(gomock.Any(), "review1").Return(review, nil)
+	m.photoRepo.EXPECT().GetPhotosByReviewID(gomock.Any(), "review1").Return([]model.ReviewPhoto{{ID: "ph0"}}, nil)
+	m.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, key string, content io.Reader) (int64, error) {
+			assert.True(t, strings.HasPrefix(key, "review-photos/review1/"))
+			assert.True(t, strings.HasSuffix(key, ".png"))
+			return storeBlob(context.Background(), key, content)
+		})
+	m.photoRepo.EXPECT().SavePhoto(gomock.Any(), gomock.Any()).Return(nil)
+
+	photo, err := reviewService.AttachPhoto(context.Background(), "h1", "review1", model.PhotoBefore, "/tmp/Kitchen.PNG", strings.NewReader("png"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Kitchen.PNG", photo.FileName)
+	assert.Equal(t, model.PhotoBefore, photo.Kind)
+	assert.Equal(t, int64(3), photo.Size)
+}
+
+func TestAttachPhoto_Refused(t *testing.T) {
+	closed := moderatedReview()
+	closed.ReviewDate = time.Now().Add(-model.ReviewEditWindow - time.Hour)
+	full := make([]model.ReviewPhoto, model.MaxReviewPhotos)
+
+	tests := []struct {
+		name          string
+		householderID string
+		kind          string
+		fileName      string
+		review        *model.Review
+		photos        []model.ReviewPhoto
+		expectedErr   string
+	}{
+		{name: "Unknown kind", householderID: "h1", kind: "during", fileName: "a.jpg", expectedErr: `unknown photo kind "during"`},
+		{name: "Not an image", householderID: "h1", kind: model.PhotoAfter, fileName: "a.gif", expectedErr: "photos must be JPEG or PNG files"},
+		{name: "Review of someone else", householderID: "h2", kind: model.PhotoAfter, fileName: "a.jpg", review: moderatedReview(), expectedErr: "review not found"},
+		{name: "Edit window closed", householderID: "h1", kind: model.PhotoAfter, fileName: "a.jpg", review: closed, expectedErr: model.ErrReviewEditClosed.Error()},
+		{name: "Too many photos", householderID: "h1", kind: model.PhotoAfter, fileName: "a.jpg", review: moderatedReview(), photos: full, expectedErr: model.ErrTooManyPhotos.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			reviewService, m := newReviewService(ctrl)
+
+			if tt.review != nil {
+				m.providerRepo.EXPECT().GetReviewByID(gomock.Any(), "review1").Return(tt.review, nil)
+			}
+			if tt.photos != nil {
+				m.photoRepo.EXPECT().GetPhotosByReviewID(gomock.Any(), "review1").Return(tt.photos, nil)
+			}
+			m.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+			_, err := reviewService.AttachPhoto(context.Background(), tt.householderID, "review1", tt.kind, tt.fileName, strings.NewReader("x"))
+
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestAttachPhoto_TooLarge(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviewService, m := newReviewService(ctrl)
+
+	m.providerRepo.EXPECT().GetReviewByID(gomock.Any(), "review1").Return(moderatedReview(), nil)
+	m.photoRepo.EXPECT().GetPhotosByReviewID(gomock.Any(), "review1").Return(nil, nil)
+	m.blobStore.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(storeBlob)
+	m.blobStore.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
+	m.photoRepo.EXPECT().SavePhoto(gomock.Any(), gomock.Any()).Times(0)
+
+	content := io.LimitReader(zeroReader{}, service.MaxPhotoSize+1)
+	_, err := reviewService.AttachPhoto(context.Background(), "h1", "review1", model.PhotoAfter, "after.jpg", content)
+
+	assert.EqualError(t, err, fmt.Sprintf("photos may not be larger than %d MB", service.MaxPhotoSize>>20))
+}
+
+func TestRemovePhoto(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviewService, m := newReviewService(ctrl)
+
+	photo := &model.ReviewPhoto{ID: "ph1", ReviewID: "review1", BlobKey: "review-photos/review1/ph1.jpg"}
+	m.photoRepo.EXPECT().GetPhotoByID(gomock.Any(), "ph1").Return(photo, nil).Times(2)
+	m.providerRepo.EXPECT().GetReviewByID(gomock.Any(), "review1").Return(moderatedReview(), nil).Times(2)
+	m.photoRepo.EXPECT().DeletePhoto(gomock.Any(), "ph1").Return(nil)
+	m.blobStore.EXPECT().Delete(gomock.Any(), "review-photos/review1/ph1.jpg").Return(nil)
+
+	assert.EqualError(t, reviewService.RemovePhoto(context.Background(), "h2", "ph1"), "photo not found")
+	assert.NoError(t, reviewService.RemovePhoto(context.Background(), "h1", "ph1"))
+}
+
+func TestOpenPhoto_HiddenReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviewService, m := newReviewService(ctrl)
+
+	hidden := moderatedReview()
+	hidden.Hidden = true
+	m.photoRepo.EXPECT().GetPhotoByID(gomock.Any(), "ph1").Return(&model.ReviewPhoto{ID: "ph1", ReviewID: "review1"}, nil)
+	m.providerRepo.EXPECT().GetReviewByID(gomock.Any(), "review1").Return(hidden, nil)
+	m.blobStore.EXPECT().Open(gomock.Any(), gomock.Any()).Times(0)
+
+	_, _, err := reviewService.OpenPhoto(context.Background(), "ph1")
+
+	assert.EqualError(t, err, "photo not found")
+}
+
+func TestGetProviderReviewSummary(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	reviewService, m := newReviewService(ctrl)
+
+	at := time.Now()
+	overall := model.RatingAggregate{ProviderID: "p1", Criterion: model.CriterionOverall}
+	overall.Add(4, at, 0)
+	communication := model.RatingAggregate{ProviderID: "p1", Criterion: model.CriterionCommunication}
+	communication.Add(5, at, 0)
+	m.ratingRepo.EXPECT().GetProviderRatings(gomock.Any(), "p1").Return([]model.RatingAggregate{communication, overall}, nil)
+	m.providerRepo.EXPECT().GetReviewsByProviderID(gomock.Any(), "p1").Return([]model.Review{
+		{ID: "review1", ProviderID: "p1", Rating: 4},
+		{ID: "review2", ProviderID: "p1", Rating: 1, Hidden: true},
+	}, nil)
+	m.photoRepo.EXPECT().GetPhotosByProviderID(gomock.Any(), "p1").Return([]model.ReviewPhoto{
+		{ID: "ph2", ReviewID: "review1", Kind: model.PhotoAfter},
+		{ID: "ph1", ReviewID: "review1", Kind: model.PhotoBefore},
+	}, nil)
+
+	summary, err := reviewService.GetProviderReviewSummary(context.Background(), "p1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, summary.Rating.All.Criteria[model.CriterionCommunication].ReviewCount)
+	if assert.Len(t, summary.Reviews, 1) {
+		assert.Equal(t, "ph1", summary.Reviews[0].Photos[0].ID)
+		assert.Equal(t, "ph2", summary.Reviews[0].Photos[1].ID)
+	}
+	assert.Len(t, summary.Gallery, 2)
+}
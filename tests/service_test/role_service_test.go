@@ -0,0 +1,153 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+	"serviceNest/model"
+	"serviceNest/service"
+	"testing"
+)
+
+func newRoleService(ctrl *gomock.Controller) (*service.RoleService, serviceMocks) {
+	m := newServiceMocks(ctrl)
+	return service.NewRoleService(m.userRepo, m.roleHistoryRepo, m.notificationRepo, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl)), m
+}
+
+func TestBootstrapAdmin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	roleService, m := newRoleService(ctrl)
+
+	m.userRepo.EXPECT().GetUsersByRole(gomock.Any(), model.RoleAdmin).Return(nil, nil)
+	m.userRepo.EXPECT().GetUserByEmail(gomock.Any(), "admin@example.com").Return(nil, errors.New("user not found"))
+	var saved *model.User
+	m.userRepo.EXPECT().SaveUser(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *model.User) error {
+		saved = user
+		return nil
+	})
+	m.roleHistoryRepo.EXPECT().SaveRoleChange(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, change model.RoleChange) error {
+		assert.Equal(t, "", change.FromRole)
+		assert.Equal(t, model.RoleAdmin, change.ToRole)
+		assert.Equal(t, "system", change.ChangedBy)
+		return nil
+	})
+
+	admin, err := roleService.BootstrapAdmin(context.Background(), "Ada", " admin@example.com ", "Secret@123")
+
+	assert.NoError(t, err)
+	assert.Equal(t, saved, admin)
+	assert.Equal(t, model.RoleAdmin, admin.Role)
+	assert.Equal(t, "admin@example.com", admin.Email)
+	assert.True(t, admin.EmailVerified)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte("Secret@123")))
+}
+
+func TestBootstrapAdmin_AdminExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	roleService, m := newRoleService(ctrl)
+
+	m.userRepo.EXPECT().GetUsersByRole(gomock.Any(), model.RoleAdmin).Return([]model.User{{ID: "a1", Role: model.RoleAdmin}}, nil)
+
+	_, err := roleService.BootstrapAdmin(context.Background(), "Ada", "admin@example.com", "Secret@123")
+	assert.Equal(t, model.ErrAdminExists, err)
+}
+
+func TestBootstrapAdmin_InvalidInput(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	roleService, _ := newRoleService(ctrl)
+
+	_, err := roleService.BootstrapAdmin(context.Background(), "", "admin@example.com", "Secret@123")
+	assert.Error(t, err)
+	_, err = roleService.BootstrapAdmin(context.Background(), "Ada", "not-an-email", "Secret@123")
+	assert.Error(t, err)
+	_, err = roleService.BootstrapAdmin(context.Background(), "Ada", "admin@example.com", "short")
+	assert.Error(t, err)
+}
+
+func TestGrantRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	roleService, m := newRoleService(ctrl)
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "a1").Return(&model.User{ID: "a1", Role: model.RoleAdmin}, nil)
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&model.User{ID: "u1", Role: model.RoleHouseholder}, nil)
+	m.userRepo.EXPECT().UpdateRole(gomock.Any(), "u1", model.RoleHouseholder, model.RoleSupport).Return(nil)
+	m.roleHistoryRepo.EXPECT().SaveRoleChange(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, change model.RoleChange) error {
+		assert.Equal(t, model.RoleHouseholder, change.FromRole)
+		assert.Equal(t, model.RoleSupport, change.ToRole)
+		assert.Equal(t, "a1", change.ChangedBy)
+		return nil
+	})
+	m.notificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).Return(nil)
+
+	assert.NoError(t, roleService.GrantRole(context.Background(), "a1", "u1", model.RoleSupport))
+}
+
+func TestGrantRole_Rejected(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor *model.User
+		user  *model.User
+		role  string
+		err   error
+	}{
+		{name: "unknown role", role: "Owner"},
+		{name: "householder role", role: model.RoleHouseholder},
+		{name: "actor is support", actor: &model.User{ID: "a1", Role: model.RoleSupport}, role: model.RoleAdmin, err: model.ErrAdminRequired},
+		{name: "provider account", actor: &model.User{ID: "a1", Role: model.RoleAdmin},
+			user: &model.User{ID: "u1", Role: model.RoleServiceProvider}, role: model.RoleAdmin},
+		{name: "role already held", actor: &model.User{ID: "a1", Role: model.RoleAdmin},
+			user: &model.User{ID: "u1", Role: model.RoleSupport}, role: model.RoleSupport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			roleService, m := newRoleService(ctrl)
+			if tt.actor != nil {
+				m.userRepo.EXPECT().GetUserByID(gomock.Any(), "a1").Return(tt.actor, nil)
+			}
+			if tt.user != nil {
+				m.userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(tt.user, nil)
+			}
+
+			err := roleService.GrantRole(context.Background(), "a1", "u1", tt.role)
+
+			assert.Error(t, err)
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+			}
+		})
+	}
+}
+
+func TestRevokeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	roleService, m := newRoleService(ctrl)
+	admins := []model.User{{ID: "a1", Role: model.RoleAdmin}, {ID: "a2", Role: model.RoleAdmin}}
+
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "a1").Return(&model.User{ID: "a1", Role: model.RoleAdmin}, nil).Times(2)
+	m.userRepo.EXPECT().GetUserByID(gomock.Any(), "a2").Return(&model.User{ID: "a2", Role: model.RoleAdmin}, nil).Times(2)
+	m.userRepo.EXPECT().GetUsersByRole(gomock.Any(), model.RoleAdmin).Return(admins, nil)
+	// The other admin was revoked in the meantime, a2 is the last one left
+	m.userRepo.EXPECT().GetUsersByRole(gomock.Any(), model.RoleAdmin).Return(admins[1:], nil)
+	m.userRepo.EXPECT().UpdateRole(gomock.Any(), "a2", model.RoleAdmin, model.RoleHouseholder).Return(&model.ConflictError{Entity: "account", ID: "a2"})
+
+	err := roleService.RevokeRole(context.Background(), "a1", "a2")
+	assert.EqualError(t, err, "the last admin cannot lose the role")
+}
+
+func TestRevokeRole_OwnAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	roleService, _ := newRoleService(ctrl)
+
+	assert.Error(t, roleService.RevokeRole(context.Background(), "a1", "a1"))
+}
@@ -0,0 +1,50 @@
+package service_test
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"testing"
+)
+
+func TestSearchServices_UsesHouseholderLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIndex := mocks.NewMockServiceIndex(ctrl)
+	expected := []model.SearchResult{{Service: model.Service{ID: "s1"}, Score: 0.9}}
+	mockIndex.EXPECT().
+		Search(model.SearchQuery{Text: "plumber", Latitude: 12.97, Longitude: 77.59, UseLocation: true, Limit: 20}).
+		Return(expected)
+
+	searchService := service.NewSearchService(mockIndex)
+	householder := &model.Householder{User: model.User{ID: "h1", Latitude: 12.97, Longitude: 77.59}}
+
+	results, err := searchService.SearchServices(context.Background(), householder, "plumber", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, results)
+}
+
+func TestSearchServices_WithoutLocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIndex := mocks.NewMockServiceIndex(ctrl)
+	mockIndex.EXPECT().Search(model.SearchQuery{Text: "cleaning", Limit: 5}).Return(nil)
+
+	searchService := service.NewSearchService(mockIndex)
+
+	results, err := searchService.SearchServices(context.Background(), &model.Householder{}, "cleaning", 5)
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+func TestSearchServices_RequiresText(t *testing.T) {
+	searchService := service.NewSearchService(nil)
+
+	_, err := searchService.SearchServices(context.Background(), nil, "   ", 0)
+	assert.EqualError(t, err, "search text is required")
+}
@@ -1,11 +1,11 @@
 package service_test
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
-	"os"
 	"serviceNest/model"
 	"serviceNest/service"
 	"serviceNest/tests/mocks"
@@ -19,6 +19,36 @@ func convertNullString(ns sql.NullString) *string {
 	}
 	return nil
 }
+
+// passthroughTransactions returns a transaction manager that simply runs the unit of work
+func passthroughTransactions(ctrl *gomock.Controller) *mocks.MockTransactionManager {
+	txManager := mocks.NewMockTransactionManager(ctrl)
+	txManager.EXPECT().WithinTransaction(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).AnyTimes()
+	return txManager
+}
+
+// activeAccounts lets every user act; tests of suspended accounts use a mock of their own
+func activeAccounts(ctrl *gomock.Controller) *mocks.MockAccountGuard {
+	accountGuard := mocks.NewMockAccountGuard(ctrl)
+	accountGuard.EXPECT().EnsureActive(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return accountGuard
+}
+
+// auditLog accepts every audit entry; tests that check the entries use a mock of their own
+func auditLog(ctrl *gomock.Controller) *mocks.MockAuditRepository {
+	auditRepo := mocks.NewMockAuditRepository(ctrl)
+	auditRepo.EXPECT().AppendEntry(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	return auditRepo
+}
+
+func expectVerifiedProvider(providerRepo *mocks.MockServiceProviderRepository, providerID string) {
+	providerRepo.EXPECT().GetProviderByID(gomock.Any(), providerID).
+		Return(&model.ServiceProvider{User: model.User{ID: providerID}, VerificationStatus: model.VerificationVerified}, nil).AnyTimes()
+}
+
 func TestAddService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -26,26 +56,93 @@ func TestAddService(t *testing.T) {
 	mockServiceProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
 
-	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo)
+	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo, mockCategoryRepo, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	providerID := "provider1"
-	newService := model.Service{ID: "service1", Name: "Test Service"}
+	newService := model.Service{ID: "service1", Name: "Test Service", Category: "Electrician"}
 
+	// The category is stored with the spelling of the catalogue
+	mockCategoryRepo.EXPECT().
+		GetCategoryByName(gomock.Any(), "Electrician").
+		Return(&model.Category{ID: "c1", Name: "electrician"}, nil)
 	mockServiceProviderRepo.EXPECT().
-		GetProviderByID(providerID).
-		Return(&model.ServiceProvider{User: model.User{ID: providerID}, ServicesOffered: []model.Service{}}, nil)
+		GetProviderByID(gomock.Any(), providerID).
+		Return(&model.ServiceProvider{User: model.User{ID: providerID}, ServicesOffered: []model.Service{}, VerificationStatus: model.VerificationVerified}, nil)
 	mockServiceProviderRepo.EXPECT().
-		UpdateServiceProvider(gomock.Any()).
+		UpdateServiceProvider(gomock.Any(), gomock.Any()).
 		Return(nil)
 	mockServiceRepo.EXPECT().
-		SaveService(newService).
+		SaveService(gomock.Any(), model.Service{ID: "service1", Name: "Test Service", Category: "electrician"}).
 		Return(nil)
 
-	err := serviceProviderService.AddService(providerID, newService)
+	err := serviceProviderService.AddService(context.Background(), providerID, newService)
 	assert.NoError(t, err)
 }
 
+func TestAddService_RequiresVerifiedProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, nil, nil, mockCategoryRepo, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	mockCategoryRepo.EXPECT().GetCategoryByName(gomock.Any(), "Plumbing").Return(&model.Category{ID: "c1", Name: "Plumbing"}, nil)
+	mockServiceProviderRepo.EXPECT().GetProviderByID(gomock.Any(), "provider1").
+		Return(&model.ServiceProvider{User: model.User{ID: "provider1"}, VerificationStatus: model.VerificationPending}, nil)
+
+	err := serviceProviderService.AddService(context.Background(), "provider1", model.Service{ID: "s1", Name: "Pipe Repair", Category: "Plumbing"})
+	assert.ErrorIs(t, err, model.ErrProviderNotVerified)
+}
+
+func TestAcceptServiceRequest_RequiresVerifiedProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	svc := service.NewServiceProviderService(mockServiceProviderRepo, nil, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	mockServiceProviderRepo.EXPECT().GetProviderByID(gomock.Any(), "provider1").
+		Return(&model.ServiceProvider{User: model.User{ID: "provider1"}, VerificationStatus: model.VerificationRejected}, nil)
+
+	err := svc.AcceptServiceRequest(context.Background(), "provider1", "request1", "150")
+	assert.ErrorIs(t, err, model.ErrProviderNotVerified)
+}
+
+func TestAcceptServiceRequest_SuspendedProvider(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	accountGuard := mocks.NewMockAccountGuard(ctrl)
+	svc := service.NewServiceProviderService(nil, nil, nil, nil, nil, accountGuard, auditLog(ctrl), passthroughTransactions(ctrl))
+
+	suspended := &model.AccountInactiveError{Status: model.AccountSuspended, Reason: "complaints"}
+	accountGuard.EXPECT().EnsureActive(gomock.Any(), "provider1").Return(suspended)
+
+	err := svc.AcceptServiceRequest(context.Background(), "provider1", "request1", "150")
+	assert.Equal(t, suspended, err)
+}
+
+func TestAddService_UnknownCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCategoryRepo := mocks.NewMockCategoryRepository(ctrl)
+	serviceProviderService := service.NewServiceProviderService(nil, nil, nil, mockCategoryRepo, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	mockCategoryRepo.EXPECT().
+		GetCategoryByName(gomock.Any(), "Custom").
+		Return(nil, errors.New("category not found"))
+
+	err := serviceProviderService.AddService(context.Background(), "provider1", model.Service{ID: "service1", Name: "Test Service", Category: "Custom"})
+	assert.EqualError(t, err, `unknown category "Custom"`)
+
+	err = serviceProviderService.AddService(context.Background(), "provider1", model.Service{ID: "service1", Name: "Test Service"})
+	assert.EqualError(t, err, "category is required")
+}
+
 func TestUpdateService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -56,11 +153,12 @@ func TestUpdateService(t *testing.T) {
 	serviceID := "service-456"
 	updatedService := model.Service{ID: serviceID, Name: "Updated Service"}
 
-	mockServiceRepo.EXPECT().UpdateService(providerID, updatedService).Return(nil)
+	mockServiceRepo.EXPECT().GetServiceByID(gomock.Any(), serviceID).Return(&model.Service{ID: serviceID, Name: "Service"}, nil)
+	mockServiceRepo.EXPECT().UpdateService(gomock.Any(), providerID, updatedService).Return(nil)
 
-	svc := service.NewServiceProviderService(nil, nil, mockServiceRepo)
+	svc := service.NewServiceProviderService(nil, nil, mockServiceRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
-	err := svc.UpdateService(providerID, serviceID, updatedService)
+	err := svc.UpdateService(context.Background(), providerID, serviceID, updatedService)
 	assert.NoError(t, err)
 }
 
@@ -73,41 +171,16 @@ func TestRemoveService(t *testing.T) {
 	providerID := "provider-123"
 	serviceID := "service-456"
 
-	mockServiceRepo.EXPECT().RemoveServiceByProviderID(providerID, serviceID).Return(nil)
+	mockServiceRepo.EXPECT().GetServiceByID(gomock.Any(), serviceID).Return(&model.Service{ID: serviceID, Name: "Service"}, nil)
+	mockServiceRepo.EXPECT().RemoveServiceByProviderID(gomock.Any(), providerID, serviceID).Return(nil)
 
-	svc := service.NewServiceProviderService(nil, nil, mockServiceRepo)
+	svc := service.NewServiceProviderService(nil, nil, mockServiceRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
-	err := svc.RemoveService(providerID, serviceID)
+	err := svc.RemoveService(context.Background(), providerID, serviceID)
 	assert.NoError(t, err)
 }
 
-func TestAcceptServiceRequestWithMockInput(t *testing.T) {
-	// Mock input to simulate typing "150" for the price
-	input := "150\n"
-
-	// Create a pipe to simulate stdin
-	r, w, err := os.Pipe()
-	if err != nil {
-		t.Fatalf("Failed to create pipe: %v", err)
-	}
-
-	// Save the original stdin
-	oldStdin := os.Stdin
-	defer func() {
-		os.Stdin = oldStdin
-	}()
-
-	// Set os.Stdin to read from the pipe (our simulated input)
-	os.Stdin = r
-
-	// Write the input to the pipe
-	_, err = w.Write([]byte(input))
-	if err != nil {
-		t.Fatalf("Failed to write to pipe: %v", err)
-	}
-	// Close the writer to simulate end of input
-	w.Close()
-
+func TestAcceptServiceRequest(t *testing.T) {
 	// Set up mocks and other test structures
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -129,29 +202,30 @@ func TestAcceptServiceRequestWithMockInput(t *testing.T) {
 		Name:              "John's Services",
 		Contact:           "1234567890",
 		Address:           "123 Service Lane",
-		Price:             "150", // This will be overwritten by Scanln mock
+		Price:             "150",
 		Rating:            4.2,
 		Reviews:           []model.Review{},
 	}
 
-	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(requestID).Return(mockServiceRequest, nil)
-	mockServiceProviderRepo.EXPECT().GetProviderDetailByID(providerID).Return(mockProviderDetails, nil)
-	mockServiceProviderRepo.EXPECT().GetReviewsByProviderID(providerID).Return([]model.Review{}, nil)
-	mockServiceRequestRepo.EXPECT().UpdateServiceRequest(mockServiceRequest).Return(nil)
-	mockServiceProviderRepo.EXPECT().SaveServiceProviderDetail(mockProviderDetails, requestID).Return(nil)
+	expectVerifiedProvider(mockServiceProviderRepo, providerID)
+	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), requestID).Return(mockServiceRequest, nil)
+	mockServiceProviderRepo.EXPECT().GetProviderDetailByID(gomock.Any(), providerID).Return(mockProviderDetails, nil)
+	mockServiceProviderRepo.EXPECT().GetReviewsByProviderID(gomock.Any(), providerID).Return([]model.Review{}, nil)
+	mockServiceRequestRepo.EXPECT().UpdateServiceRequest(gomock.Any(), mockServiceRequest).Return(nil)
+	mockServiceProviderRepo.EXPECT().SaveServiceProviderDetail(gomock.Any(), mockProviderDetails, requestID).Return(nil)
 
 	// Initialize the service with mock repositories
-	svc := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, nil)
+	svc := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	// Call the method
-	err = svc.AcceptServiceRequest(providerID, requestID)
+	err := svc.AcceptServiceRequest(context.Background(), providerID, requestID, "150")
 
 	// Check for errors
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
 
-	// Verify that the price has been set correctly from the mocked input
+	// Verify that the quoted price has been recorded
 	if mockServiceRequest.ProviderDetails[0].Price != "150" {
 		t.Errorf("expected price to be '150', got %v", mockServiceRequest.ProviderDetails[0].Price)
 	}
@@ -171,15 +245,117 @@ func TestDeclineServiceRequest(t *testing.T) {
 		Status: "Pending",
 	}
 
-	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(requestID).Return(mockServiceRequest, nil)
-	mockServiceRequestRepo.EXPECT().UpdateServiceRequest(mockServiceRequest).Return(nil)
+	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), requestID).Return(mockServiceRequest, nil)
+	mockServiceRequestRepo.EXPECT().UpdateServiceRequest(gomock.Any(), mockServiceRequest).Return(nil)
 
-	svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil)
+	svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
-	err := svc.DeclineServiceRequest(providerID, requestID)
+	err := svc.DeclineServiceRequest(context.Background(), providerID, requestID)
 	assert.NoError(t, err)
 }
 
+func TestDeclineServiceRequest_DirectBookingOpensToAllProviders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	mockNotificationRepo := mocks.NewMockNotificationRepository(ctrl)
+
+	householderID, providerID := "householder-1", "provider-123"
+	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "request-456").Return(&model.ServiceRequest{
+		ID: "request-456", HouseholderID: &householderID, Status: "Pending", TargetProviderID: &providerID,
+	}, nil)
+	mockServiceRequestRepo.EXPECT().OpenToAllProviders(gomock.Any(), "request-456", providerID).Return(nil)
+	mockServiceRequestRepo.EXPECT().UpdateServiceRequest(gomock.Any(), gomock.Any()).Times(0)
+	mockNotificationRepo.EXPECT().SaveNotification(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, notification model.Notification) error {
+		assert.Equal(t, householderID, notification.UserID)
+		assert.Contains(t, notification.Message, "open to all providers")
+		return nil
+	})
+
+	svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil, nil, mockNotificationRepo, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	assert.NoError(t, svc.DeclineServiceRequest(context.Background(), providerID, "request-456"))
+}
+
+func TestDirectBooking_HiddenFromOtherProviders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+
+	target := "provider-123"
+	request := &model.ServiceRequest{ID: "request-456", Status: "Pending", TargetProviderID: &target}
+	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), "request-456").Return(request, nil).Times(3)
+	mockServiceProviderRepo.EXPECT().GetProviderByID(gomock.Any(), "provider-999").
+		Return(&model.ServiceProvider{VerificationStatus: model.VerificationVerified}, nil)
+	mockServiceProviderRepo.EXPECT().GetProviderDetailByID(gomock.Any(), "provider-999").Return(&model.ServiceProviderDetails{}, nil)
+	mockServiceProviderRepo.EXPECT().GetReviewsByProviderID(gomock.Any(), "provider-999").Return(nil, nil)
+	mockServiceRequestRepo.EXPECT().UpdateServiceRequest(gomock.Any(), gomock.Any()).Times(0)
+
+	svc := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	_, err := svc.GetServiceRequestByID(context.Background(), "provider-999", "request-456")
+	assert.EqualError(t, err, "service request not found")
+	assert.EqualError(t, svc.AcceptServiceRequest(context.Background(), "provider-999", "request-456", "100"), "service request not found")
+	assert.EqualError(t, svc.DeclineServiceRequest(context.Background(), "provider-999", "request-456"), "service request not found")
+}
+
+func TestCompleteServiceRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		request     *model.ServiceRequest
+		expectSave  bool
+		expectedErr string
+	}{
+		{
+			name: "Approved request is completed",
+			request: &model.ServiceRequest{ID: "request-456", Status: "Accepted", ApproveStatus: true,
+				ProviderDetails: []model.ServiceProviderDetails{{ServiceProviderID: "provider-123", Approve: true}}},
+			expectSave: true,
+		},
+		{
+			name: "Another provider was approved",
+			request: &model.ServiceRequest{ID: "request-456", Status: "Accepted", ApproveStatus: true,
+				ProviderDetails: []model.ServiceProviderDetails{{ServiceProviderID: "provider-123", Approve: false}}},
+			expectedErr: "only requests approved for you can be completed",
+		},
+		{
+			name: "Already completed",
+			request: &model.ServiceRequest{ID: "request-456", Status: model.RequestCompleted, ApproveStatus: true,
+				ProviderDetails: []model.ServiceProviderDetails{{ServiceProviderID: "provider-123", Approve: true}}},
+			expectedErr: "service request has already been completed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+			mockServiceRequestRepo.EXPECT().GetServiceProviderByRequestID(gomock.Any(), "request-456", "provider-123").Return(tt.request, nil)
+			if tt.expectSave {
+				mockServiceRequestRepo.EXPECT().UpdateServiceRequest(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, request *model.ServiceRequest) error {
+						assert.Equal(t, model.RequestCompleted, request.Status)
+						return nil
+					})
+			}
+
+			svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+			err := svc.CompleteServiceRequest(context.Background(), "provider-123", "request-456")
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
 func TestUpdateAvailability(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -188,19 +364,19 @@ func TestUpdateAvailability(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo)
+	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	providerID := "provider1"
 	availability := true
 
 	mockServiceProviderRepo.EXPECT().
-		GetProviderByID(providerID).
+		GetProviderByID(gomock.Any(), providerID).
 		Return(&model.ServiceProvider{User: model.User{ID: providerID}}, nil)
 	mockServiceProviderRepo.EXPECT().
-		UpdateServiceProvider(gomock.Any()).
+		UpdateServiceProvider(gomock.Any(), gomock.Any()).
 		Return(nil)
 
-	err := serviceProviderService.UpdateAvailability(providerID, availability)
+	err := serviceProviderService.UpdateAvailability(context.Background(), providerID, availability)
 	assert.NoError(t, err)
 }
 
@@ -212,7 +388,7 @@ func TestViewServices(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo)
+	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	providerID := "provider1"
 	services := []model.Service{
@@ -220,10 +396,10 @@ func TestViewServices(t *testing.T) {
 	}
 
 	mockServiceRepo.EXPECT().
-		GetServiceByProviderID(providerID).
+		GetServiceByProviderID(gomock.Any(), providerID).
 		Return(services, nil)
 
-	result, err := serviceProviderService.ViewServices(providerID)
+	result, err := serviceProviderService.ViewServices(context.Background(), providerID)
 	assert.NoError(t, err)
 	assert.Equal(t, services, result)
 }
@@ -236,16 +412,16 @@ func TestGetServiceByID(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo)
+	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	serviceID := "123"
 	expectedService := &model.Service{ID: serviceID, Name: "Service Name"}
 
 	mockServiceRepo.EXPECT().
-		GetServiceByID(serviceID).
+		GetServiceByID(gomock.Any(), serviceID).
 		Return(expectedService, nil)
 
-	result, err := serviceProviderService.GetServiceByID(serviceID)
+	result, err := serviceProviderService.GetServiceByID(context.Background(), serviceID)
 	assert.NoError(t, err)
 	assert.Equal(t, expectedService, result)
 }
@@ -258,7 +434,7 @@ func TestGetReviews(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo)
+	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	providerID := "provider123"
 	expectedReviews := []model.Review{
@@ -267,10 +443,10 @@ func TestGetReviews(t *testing.T) {
 	}
 
 	mockServiceProviderRepo.EXPECT().
-		GetReviewsByProviderID(providerID).
+		GetReviewsByProviderID(gomock.Any(), providerID).
 		Return(expectedReviews, nil)
 
-	result, err := serviceProviderService.GetReviews(providerID)
+	result, err := serviceProviderService.GetReviews(context.Background(), providerID)
 	assert.NoError(t, err)
 	assert.ElementsMatch(t, expectedReviews, result)
 }
@@ -282,18 +458,61 @@ func TestGetAllServiceRequests(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo)
+	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 	mockServiceRequest := []model.ServiceRequest{
 		{ID: "requestID",
 			Status: "Pending"},
 	}
-	mockServiceRequestRepo.EXPECT().GetAllServiceRequests().Return(mockServiceRequest, nil)
+	mockServiceRequestRepo.EXPECT().GetAllServiceRequests(gomock.Any(), model.QueryOptions{OfferedTo: "provider1"}).Return(mockServiceRequest, nil)
 
-	result, err := serviceProviderService.GetAllServiceRequests()
+	result, err := serviceProviderService.GetAllServiceRequests(context.Background(), "provider1", model.QueryOptions{})
 	assert.NoError(t, err)
 	assert.Equal(t, mockServiceRequest, result)
 
 }
+func TestGetAllServiceRequests_MasksContactDetails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	serviceProviderService := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+	address := "12 Baker Street"
+	mockServiceRequestRepo.EXPECT().GetAllServiceRequests(gomock.Any(), model.QueryOptions{OfferedTo: "provider1"}).Return([]model.ServiceRequest{
+		{ID: "request1", Status: "Accepted", HouseholderAddress: &address, ProviderDetails: []model.ServiceProviderDetails{
+			{ServiceProviderID: "p2", Contact: "9876543211", Address: "2 High Street"},
+		}},
+	}, nil)
+
+	result, err := serviceProviderService.GetAllServiceRequests(context.Background(), "provider1", model.QueryOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, model.MaskedContact, *result[0].HouseholderAddress)
+		assert.Equal(t, model.MaskedContact, result[0].ProviderDetails[0].Contact)
+		assert.Equal(t, model.ContactReference("request1"), result[0].ContactReference)
+	}
+}
+
+func TestViewApprovedRequestsByHouseholder_RevealsAddress(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+	address := "12 Baker Street"
+	mockServiceRequestRepo.EXPECT().GetServiceRequestsByProviderID(gomock.Any(), "p1").Return([]model.ServiceRequest{
+		{ID: "request1", ApproveStatus: true, HouseholderAddress: &address, ProviderDetails: []model.ServiceProviderDetails{
+			{ServiceProviderID: "p1", Contact: "9876543210", Approve: true},
+		}},
+	}, nil)
+
+	result, err := svc.ViewApprovedRequestsByHouseholder(context.Background(), "p1")
+	assert.NoError(t, err)
+	if assert.Len(t, result, 1) {
+		assert.Equal(t, "12 Baker Street", *result[0].HouseholderAddress)
+		assert.Equal(t, "9876543210", result[0].ProviderDetails[0].Contact)
+	}
+}
+
 func TestViewApprovedRequestsByHouseholder(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -339,13 +558,13 @@ func TestViewApprovedRequestsByHouseholder(t *testing.T) {
 	}
 
 	// Set up the mock to return the sample service requests
-	mockServiceRequestRepo.EXPECT().GetServiceRequestsByProviderID(providerID).Return(mockServiceRequests, nil)
+	mockServiceRequestRepo.EXPECT().GetServiceRequestsByProviderID(gomock.Any(), providerID).Return(mockServiceRequests, nil)
 
 	// Initialize the ServiceProviderService with the mock repository
-	svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil)
+	svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	// Call the function to test
-	approvedRequests, err := svc.ViewApprovedRequestsByHouseholder(providerID)
+	approvedRequests, err := svc.ViewApprovedRequestsByHouseholder(context.Background(), providerID)
 
 	// Assertions
 	if err != nil {
@@ -399,13 +618,13 @@ func TestViewApprovedRequestsByHouseholder_NoApprovedRequests(t *testing.T) {
 	}
 
 	// Set up the mock to return the sample service requests
-	mockServiceRequestRepo.EXPECT().GetServiceRequestsByProviderID(providerID).Return(mockServiceRequests, nil)
+	mockServiceRequestRepo.EXPECT().GetServiceRequestsByProviderID(gomock.Any(), providerID).Return(mockServiceRequests, nil)
 
 	// Initialize the ServiceProviderService with the mock repository
-	svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil)
+	svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	// Call the function to test
-	_, err := svc.ViewApprovedRequestsByHouseholder(providerID)
+	_, err := svc.ViewApprovedRequestsByHouseholder(context.Background(), providerID)
 
 	// Assertions
 	if err == nil {
@@ -429,13 +648,13 @@ func TestViewApprovedRequestsByHouseholder_ErrorRetrievingRequests(t *testing.T)
 	providerID := "provider-123"
 
 	// Set up the mock to return an error
-	mockServiceRequestRepo.EXPECT().GetServiceRequestsByProviderID(providerID).Return(nil, errors.New("database error"))
+	mockServiceRequestRepo.EXPECT().GetServiceRequestsByProviderID(gomock.Any(), providerID).Return(nil, errors.New("database error"))
 
 	// Initialize the ServiceProviderService with the mock repository
-	svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil)
+	svc := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	// Call the function to test
-	_, err := svc.ViewApprovedRequestsByHouseholder(providerID)
+	_, err := svc.ViewApprovedRequestsByHouseholder(context.Background(), providerID)
 
 	// Assertions
 	if err == nil {
@@ -456,16 +675,16 @@ func TestViewServices_ErrorFromRepo(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo)
+	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	providerID := "provider1"
 	expectedError := errors.New("database error")
 
 	mockServiceRepo.EXPECT().
-		GetServiceByProviderID(providerID).
+		GetServiceByProviderID(gomock.Any(), providerID).
 		Return(nil, expectedError)
 
-	result, err := serviceProviderService.ViewServices(providerID)
+	result, err := serviceProviderService.ViewServices(context.Background(), providerID)
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
 	assert.Nil(t, result)
@@ -479,16 +698,16 @@ func TestViewServices_EmptyResult(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo)
+	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	providerID := "provider1"
 	services := []model.Service{} // Empty result
 
 	mockServiceRepo.EXPECT().
-		GetServiceByProviderID(providerID).
+		GetServiceByProviderID(gomock.Any(), providerID).
 		Return(services, nil)
 
-	result, err := serviceProviderService.ViewServices(providerID)
+	result, err := serviceProviderService.ViewServices(context.Background(), providerID)
 	assert.NoError(t, err)
 	assert.Equal(t, services, result)
 }
@@ -501,16 +720,16 @@ func TestViewServices_InvalidProviderID(t *testing.T) {
 	mockServiceRepo := mocks.NewMockServiceRepository(ctrl)
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
 
-	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo)
+	serviceProviderService := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, mockServiceRepo, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	providerID := "" // Invalid provider ID
 	services := []model.Service{}
 
 	mockServiceRepo.EXPECT().
-		GetServiceByProviderID(providerID).
+		GetServiceByProviderID(gomock.Any(), providerID).
 		Return(services, nil)
 
-	result, err := serviceProviderService.ViewServices(providerID)
+	result, err := serviceProviderService.ViewServices(context.Background(), providerID)
 	assert.NoError(t, err)
 	assert.Equal(t, services, result)
 }
@@ -519,7 +738,7 @@ func TestGetServiceRequestByID(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
-	serviceProviderService := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil)
+	serviceProviderService := service.NewServiceProviderService(nil, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	requestID := "request123"
 	expectedRequest := &model.ServiceRequest{
@@ -544,7 +763,7 @@ func TestGetServiceRequestByID(t *testing.T) {
 			name: "Successful retrieval",
 			mockGetByID: func() {
 				mockServiceRequestRepo.EXPECT().
-					GetServiceRequestByID(requestID).
+					GetServiceRequestByID(gomock.Any(), requestID).
 					Return(expectedRequest, nil)
 			},
 			expectedReq:   expectedRequest,
@@ -554,7 +773,7 @@ func TestGetServiceRequestByID(t *testing.T) {
 			name: "Error retrieving request",
 			mockGetByID: func() {
 				mockServiceRequestRepo.EXPECT().
-					GetServiceRequestByID(requestID).
+					GetServiceRequestByID(gomock.Any(), requestID).
 					Return(nil, errors.New("request not found"))
 			},
 			expectedReq:   nil,
@@ -566,10 +785,34 @@ func TestGetServiceRequestByID(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockGetByID()
 
-			result, err := serviceProviderService.GetServiceRequestByID(requestID)
+			result, err := serviceProviderService.GetServiceRequestByID(context.Background(), "provider1", requestID)
 
 			assert.Equal(t, tt.expectedReq, result)
 			assert.Equal(t, tt.expectedError, err)
 		})
 	}
 }
+
+func TestAcceptServiceRequest_StopsWhenRequestUpdateFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockServiceRequestRepo := mocks.NewMockServiceRequestRepository(ctrl)
+	mockServiceProviderRepo := mocks.NewMockServiceProviderRepository(ctrl)
+
+	providerID := "provider-123"
+	requestID := "request-456"
+
+	expectVerifiedProvider(mockServiceProviderRepo, providerID)
+	mockServiceRequestRepo.EXPECT().GetServiceRequestByID(gomock.Any(), requestID).Return(&model.ServiceRequest{ID: requestID, Status: "Pending"}, nil)
+	mockServiceProviderRepo.EXPECT().GetProviderDetailByID(gomock.Any(), providerID).Return(&model.ServiceProviderDetails{ServiceProviderID: providerID}, nil)
+	mockServiceProviderRepo.EXPECT().GetReviewsByProviderID(gomock.Any(), providerID).Return([]model.Review{}, nil)
+	mockServiceRequestRepo.EXPECT().UpdateServiceRequest(gomock.Any(), gomock.Any()).Return(errors.New("lock wait timeout"))
+	// The provider detail must not be written once the request update has failed
+	mockServiceProviderRepo.EXPECT().SaveServiceProviderDetail(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	svc := service.NewServiceProviderService(mockServiceProviderRepo, mockServiceRequestRepo, nil, nil, nil, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	err := svc.AcceptServiceRequest(context.Background(), providerID, requestID, "150")
+	assert.EqualError(t, err, "lock wait timeout")
+}
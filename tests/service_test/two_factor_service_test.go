@@ -0,0 +1,149 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"serviceNest/model"
+	"serviceNest/service"
+	"serviceNest/tests/mocks"
+	"serviceNest/totp"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "JBSWY3DPEHPK3PXP"
+
+func newTwoFactorService(ctrl *gomock.Controller) (*service.TwoFactorService, *mocks.MockUserRepository, *mocks.MockTwoFactorRepository) {
+	userRepo := mocks.NewMockUserRepository(ctrl)
+	twoFactorRepo := mocks.NewMockTwoFactorRepository(ctrl)
+	return service.NewTwoFactorService(userRepo, twoFactorRepo, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl)), userRepo, twoFactorRepo
+}
+
+// currentCode returns the code an authenticator app shows right now
+func currentCode(t *testing.T, secret string) string {
+	code, err := totp.Code(secret, totp.StepAt(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestStartEnrolment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	twoFactorService, userRepo, twoFactorRepo := newTwoFactorService(ctrl)
+
+	userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&model.User{ID: "u1", Email: "jane@example.com"}, nil)
+	twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), "u1").Return(nil, errors.New("two-factor settings not found"))
+	var saved model.TwoFactor
+	twoFactorRepo.EXPECT().SaveSecret(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, twoFactor model.TwoFactor) error {
+		saved = twoFactor
+		return nil
+	})
+
+	enrolment, err := twoFactorService.StartEnrolment(context.Background(), "u1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, saved.Secret, enrolment.Secret)
+	assert.False(t, saved.Enabled)
+	assert.True(t, strings.HasPrefix(enrolment.URI, "otpauth://totp/ServiceNest:jane@example.com?"))
+	assert.Contains(t, enrolment.URI, "secret="+enrolment.Secret)
+}
+
+func TestStartEnrolment_SuspendedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	accountGuard := mocks.NewMockAccountGuard(ctrl)
+	twoFactorService := service.NewTwoFactorService(nil, nil, accountGuard, auditLog(ctrl), passthroughTransactions(ctrl))
+
+	suspended := &model.AccountInactiveError{Status: model.AccountSuspended, Reason: "spam"}
+	accountGuard.EXPECT().EnsureActive(gomock.Any(), "u1").Return(suspended)
+
+	_, err := twoFactorService.StartEnrolment(context.Background(), "u1")
+	assert.Equal(t, suspended, err)
+}
+
+func TestStartEnrolment_AlreadyEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	twoFactorService, userRepo, twoFactorRepo := newTwoFactorService(ctrl)
+
+	userRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&model.User{ID: "u1"}, nil)
+	twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), "u1").Return(&model.TwoFactor{UserID: "u1", Secret: testSecret, Enabled: true}, nil)
+
+	_, err := twoFactorService.StartEnrolment(context.Background(), "u1")
+	assert.Equal(t, model.ErrTwoFactorEnabled, err)
+}
+
+func TestConfirmEnrolment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	twoFactorService, _, twoFactorRepo := newTwoFactorService(ctrl)
+
+	twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), "u1").Return(&model.TwoFactor{UserID: "u1", Secret: testSecret}, nil).Times(2)
+	twoFactorRepo.EXPECT().EnableTwoFactor(gomock.Any(), "u1", gomock.Any(), gomock.Any()).Return(nil)
+	var stored []model.RecoveryCode
+	twoFactorRepo.EXPECT().ReplaceRecoveryCodes(gomock.Any(), "u1", gomock.Any()).DoAndReturn(func(_ context.Context, _ string, codes []model.RecoveryCode) error {
+		stored = codes
+		return nil
+	})
+
+	_, err := twoFactorService.ConfirmEnrolment(context.Background(), "u1", "000000")
+	assert.Equal(t, model.ErrInvalidSecondFactor, err)
+
+	codes, err := twoFactorService.ConfirmEnrolment(context.Background(), "u1", currentCode(t, testSecret))
+	assert.NoError(t, err)
+	assert.Len(t, codes, 10)
+	assert.Len(t, stored, 10)
+	assert.Regexp(t, `^[a-z1-9]{5}-[a-z1-9]{5}$`, codes[0])
+	for i, code := range codes {
+		assert.Equal(t, hashOf(strings.ReplaceAll(code, "-", "")), stored[i].CodeHash, "only hashes are stored")
+	}
+}
+
+func TestVerify_TOTPCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	twoFactorService, _, twoFactorRepo := newTwoFactorService(ctrl)
+	code := currentCode(t, testSecret)
+	step := totp.StepAt(time.Now())
+
+	twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), "u1").Return(&model.TwoFactor{UserID: "u1", Secret: testSecret, Enabled: true, LastUsedStep: step - 5}, nil)
+	twoFactorRepo.EXPECT().UseStep(gomock.Any(), "u1", gomock.Any()).Return(nil)
+	assert.NoError(t, twoFactorService.Verify(context.Background(), "u1", code))
+
+	// The same code cannot be used again
+	twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), "u1").Return(&model.TwoFactor{UserID: "u1", Secret: testSecret, Enabled: true, LastUsedStep: step + 1}, nil)
+	assert.Equal(t, model.ErrInvalidSecondFactor, twoFactorService.Verify(context.Background(), "u1", code))
+}
+
+func TestVerify_RecoveryCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	twoFactorService, _, twoFactorRepo := newTwoFactorService(ctrl)
+
+	twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), "u1").Return(&model.TwoFactor{UserID: "u1", Secret: testSecret, Enabled: true}, nil).Times(2)
+	twoFactorRepo.EXPECT().UseRecoveryCode(gomock.Any(), "u1", hashOf("abcde12345")).Return(nil)
+	twoFactorRepo.EXPECT().UseRecoveryCode(gomock.Any(), "u1", hashOf("abcde12345")).Return(errors.New("recovery code not found"))
+
+	assert.NoError(t, twoFactorService.Verify(context.Background(), "u1", "ABCDE-12345"))
+	assert.Equal(t, model.ErrInvalidSecondFactor, twoFactorService.Verify(context.Background(), "u1", "abcde-12345"))
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	twoFactorService, userRepo, twoFactorRepo := newTwoFactorService(ctrl)
+
+	userRepo.EXPECT().GetUserByID(gomock.Any(), "admin1").Return(&model.User{ID: "admin1", Role: "Admin"}, nil)
+	assert.Equal(t, model.ErrTwoFactorMandatory, twoFactorService.Disable(context.Background(), "admin1", "123456"))
+
+	userRepo.EXPECT().GetUserByID(gomock.Any(), "p1").Return(&model.User{ID: "p1", Role: "ServiceProvider"}, nil)
+	twoFactorRepo.EXPECT().GetTwoFactor(gomock.Any(), "p1").Return(&model.TwoFactor{UserID: "p1", Secret: testSecret, Enabled: true}, nil).Times(2)
+	twoFactorRepo.EXPECT().UseStep(gomock.Any(), "p1", gomock.Any()).Return(nil)
+	twoFactorRepo.EXPECT().DeleteTwoFactor(gomock.Any(), "p1").Return(nil)
+	assert.NoError(t, twoFactorService.Disable(context.Background(), "p1", currentCode(t, testSecret)))
+}
@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"context"
 	"errors"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -15,7 +16,7 @@ func TestViewProfileByID(t *testing.T) {
 	//defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	userService := service.NewUserService(mockUserRepo)
+	userService := service.NewUserService(mockUserRepo, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	userID := "12345"
 	user := &model.User{ID: userID, Email: "test@example.com"}
@@ -30,7 +31,7 @@ func TestViewProfileByID(t *testing.T) {
 		{
 			name: "Success",
 			mockGetUserByID: func(m *mocks.MockUserRepository) {
-				m.EXPECT().GetUserByID(userID).Return(user, nil)
+				m.EXPECT().GetUserByID(gomock.Any(), userID).Return(user, nil)
 			},
 			expectedUser:  user,
 			expectedError: nil,
@@ -38,7 +39,7 @@ func TestViewProfileByID(t *testing.T) {
 		{
 			name: "User Not Found",
 			mockGetUserByID: func(m *mocks.MockUserRepository) {
-				m.EXPECT().GetUserByID(userID).Return(nil, nil)
+				m.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, nil)
 			},
 			expectedUser:  nil,
 			expectedError: errors.New("user not found"),
@@ -46,7 +47,7 @@ func TestViewProfileByID(t *testing.T) {
 		{
 			name: "Error Getting User",
 			mockGetUserByID: func(m *mocks.MockUserRepository) {
-				m.EXPECT().GetUserByID(userID).Return(nil, errors.New("database error"))
+				m.EXPECT().GetUserByID(gomock.Any(), userID).Return(nil, errors.New("database error"))
 			},
 			expectedUser:  nil,
 			expectedError: errors.New("could not find user: database error"),
@@ -56,7 +57,7 @@ func TestViewProfileByID(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.mockGetUserByID(mockUserRepo)
-			user, err := userService.ViewProfileByID(userID)
+			user, err := userService.ViewProfileByID(context.Background(), userID)
 			assert.Equal(t, tt.expectedUser, user)
 			assert.Equal(t, tt.expectedError, err)
 		})
@@ -68,7 +69,7 @@ func TestUpdateUser(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserRepository(ctrl)
-	userService := service.NewUserService(mockUserRepo)
+	userService := service.NewUserService(mockUserRepo, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
 
 	userID := "12345"
 	existingUser := &model.User{ID: userID, Email: "old@example.com"}
@@ -88,13 +89,13 @@ func TestUpdateUser(t *testing.T) {
 		{
 			name: "Success",
 			mockGetUserByID: func(m *mocks.MockUserRepository) {
-				m.EXPECT().GetUserByID(userID).Return(existingUser, nil)
+				m.EXPECT().GetUserByID(gomock.Any(), userID).Return(existingUser, nil)
 			},
 			mockGetUserByEmail: func(m *mocks.MockUserRepository) {
-				m.EXPECT().GetUserByEmail("new@example.com").Return(nil, errors.New("user not found"))
+				m.EXPECT().GetUserByEmail(gomock.Any(), "new@example.com").Return(nil, errors.New("user not found"))
 			},
 			mockUpdateUser: func(m *mocks.MockUserRepository) {
-				m.EXPECT().UpdateUser(existingUser).Return(nil)
+				m.EXPECT().UpdateUser(gomock.Any(), existingUser).Return(nil)
 			},
 			newEmail:      stringPtr("new@example.com"),
 			newPassword:   stringPtr("NewPassword@123"),
@@ -105,10 +106,10 @@ func TestUpdateUser(t *testing.T) {
 		{
 			name: "Email Already In Use",
 			mockGetUserByID: func(m *mocks.MockUserRepository) {
-				m.EXPECT().GetUserByID(userID).Return(existingUser, nil)
+				m.EXPECT().GetUserByID(gomock.Any(), userID).Return(existingUser, nil)
 			},
 			mockGetUserByEmail: func(m *mocks.MockUserRepository) {
-				m.EXPECT().GetUserByEmail("new@example.com").Return(&model.User{ID: "67890"}, nil)
+				m.EXPECT().GetUserByEmail(gomock.Any(), "new@example.com").Return(&model.User{ID: "67890"}, nil)
 			},
 			mockUpdateUser: func(m *mocks.MockUserRepository) {
 				// No expectations on UpdateUser here
@@ -119,13 +120,13 @@ func TestUpdateUser(t *testing.T) {
 		{
 			name: "Error Updating User",
 			mockGetUserByID: func(m *mocks.MockUserRepository) {
-				m.EXPECT().GetUserByID(userID).Return(existingUser, nil)
+				m.EXPECT().GetUserByID(gomock.Any(), userID).Return(existingUser, nil)
 			},
 			mockGetUserByEmail: func(m *mocks.MockUserRepository) {
-				m.EXPECT().GetUserByEmail("new@example.com").Return(nil, errors.New("user not found"))
+				m.EXPECT().GetUserByEmail(gomock.Any(), "new@example.com").Return(nil, errors.New("user not found"))
 			},
 			mockUpdateUser: func(m *mocks.MockUserRepository) {
-				m.EXPECT().UpdateUser(existingUser).Return(errors.New("update error"))
+				m.EXPECT().UpdateUser(gomock.Any(), existingUser).Return(errors.New("update error"))
 			},
 			newEmail:      stringPtr("new@example.com"),
 			expectedError: errors.New("could not update user: update error"),
@@ -137,7 +138,7 @@ func TestUpdateUser(t *testing.T) {
 			tt.mockGetUserByID(mockUserRepo)
 			tt.mockGetUserByEmail(mockUserRepo)
 			tt.mockUpdateUser(mockUserRepo)
-			err := userService.UpdateUser(userID, tt.newEmail, tt.newPassword, tt.newAddress, tt.newPhone)
+			err := userService.UpdateUser(context.Background(), userID, tt.newEmail, tt.newPassword, tt.newAddress, tt.newPhone)
 			assert.Equal(t, tt.expectedError, err)
 		})
 	}
@@ -147,3 +148,21 @@ func TestUpdateUser(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func TestUpdateUser_NewEmailNeedsVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserRepository(ctrl)
+	userService := service.NewUserService(mockUserRepo, activeAccounts(ctrl), auditLog(ctrl), passthroughTransactions(ctrl))
+
+	mockUserRepo.EXPECT().GetUserByID(gomock.Any(), "u1").Return(&model.User{ID: "u1", Email: "old@example.com", EmailVerified: true}, nil)
+	mockUserRepo.EXPECT().GetUserByEmail(gomock.Any(), "new@example.com").Return(nil, errors.New("user not found"))
+	mockUserRepo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, user *model.User) error {
+		assert.Equal(t, "new@example.com", user.Email)
+		assert.False(t, user.EmailVerified)
+		return nil
+	})
+
+	assert.NoError(t, userService.UpdateUser(context.Background(), "u1", stringPtr("new@example.com"), nil, nil, nil))
+}
@@ -0,0 +1,74 @@
+package totp_test
+
+import (
+	"encoding/base32"
+	"github.com/stretchr/testify/assert"
+	"net/url"
+	"serviceNest/totp"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors, "12345678901234567890"
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode_RFC6238Vectors(t *testing.T) {
+	// The RFC lists eight digit codes, authenticator apps use their last six digits
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, expected := range vectors {
+		code, err := totp.Code(rfcSecret, totp.StepAt(time.Unix(unix, 0)))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, code, "time %d", unix)
+	}
+}
+
+func TestVerify_Window(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, _ := totp.Code(rfcSecret, totp.StepAt(now)-1)
+	tooOld, _ := totp.Code(rfcSecret, totp.StepAt(now)-2)
+
+	step, ok := totp.Verify(rfcSecret, "005924", now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, totp.StepAt(now), step)
+
+	step, ok = totp.Verify(rfcSecret, previous, now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, totp.StepAt(now)-1, step)
+
+	_, ok = totp.Verify(rfcSecret, tooOld, now, 1)
+	assert.False(t, ok)
+	_, ok = totp.Verify(rfcSecret, "12345", now, 1)
+	assert.False(t, ok)
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+	second, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+
+	assert.Len(t, first, 32) // 160 bits in base32
+	assert.NotEqual(t, first, second)
+	_, err = totp.Code(first, 1)
+	assert.NoError(t, err)
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(totp.URI("ServiceNest", "jane@example.com", "JBSWY3DPEHPK3PXP"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/ServiceNest:jane@example.com", uri.Path)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", uri.Query().Get("secret"))
+	assert.Equal(t, "ServiceNest", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
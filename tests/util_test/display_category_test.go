@@ -2,7 +2,6 @@ package util_test
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"serviceNest/model"
@@ -10,19 +9,7 @@ import (
 	"testing"
 )
 
-func TestDisplayCategory(t *testing.T) {
-	// Mock data
-	mockCategories := []model.Category{
-		{Name: "Category1", Description: "Description1"},
-		{Name: "Category2", Description: "Description2"},
-	}
-
-	// Convert mock data to JSON
-	mockData, err := json.Marshal(mockCategories)
-	if err != nil {
-		t.Fatalf("Failed to marshal mock data: %v", err)
-	}
-
+func captureDisplayCategory(categories []model.Category) string {
 	// Override the global Print function to capture output
 	var buf bytes.Buffer
 	originalPrint := util.Print
@@ -31,15 +18,16 @@ func TestDisplayCategory(t *testing.T) {
 	}
 	defer func() { util.Print = originalPrint }() // Restore original Print after test
 
-	// Mock ioutil.ReadFile to return the mock data
-	oldReadFile := util.ReadFile
-	defer func() { util.ReadFile = oldReadFile }() // Restore original ReadFile after test
-	util.ReadFile = func(filename string) ([]byte, error) {
-		return mockData, nil
-	}
+	util.DisplayCategory(categories)
+	return buf.String()
+}
 
-	// Call the function to test
-	util.DisplayCategory()
+func TestDisplayCategory(t *testing.T) {
+	// Mock data
+	mockCategories := []model.Category{
+		{ID: "1", Name: "Category1", Description: "Description1"},
+		{ID: "2", Name: "Category2", Description: "Description2"},
+	}
 
 	// Define the expected output
 	expectedOutput := `1 Name : Category1 Description : Description1
@@ -47,67 +35,58 @@ func TestDisplayCategory(t *testing.T) {
 `
 
 	// Verify the output
-	assert.Equal(t, expectedOutput, buf.String())
+	assert.Equal(t, expectedOutput, captureDisplayCategory(mockCategories))
 }
 func TestDisplayCategoryAll(t *testing.T) {
 	tests := []struct {
 		name           string
 		mockData       []model.Category
-		mockReadFile   func(filename string) ([]byte, error)
 		expectedOutput string
-		expectError    bool
 	}{
 		{
-			name: "Successful Read and Unmarshal",
+			name: "Top-level categories",
 			mockData: []model.Category{
-				{Name: "Category1", Description: "Description1"},
-				{Name: "Category2", Description: "Description2"},
-			},
-			mockReadFile: func(filename string) ([]byte, error) {
-				return json.Marshal([]model.Category{
-					{Name: "Category1", Description: "Description1"},
-					{Name: "Category2", Description: "Description2"},
-				})
+				{ID: "1", Name: "Category1", Description: "Description1"},
+				{ID: "2", Name: "Category2", Description: "Description2"},
 			},
 			expectedOutput: `1 Name : Category1 Description : Description1
 2 Name : Category2 Description : Description2
 `,
-			expectError: false,
 		},
-
+		{
+			name: "Sub-categories are nested below their parent",
+			mockData: []model.Category{
+				{ID: "e", Name: "electrician", Description: "Electrical work"},
+				{ID: "l", Name: "lighting", Description: "Fixtures", ParentID: "e"},
+				{ID: "p", Name: "plumber", Description: "Pipes"},
+				{ID: "w", Name: "wiring", Description: "Wiring repairs", ParentID: "e"},
+				{ID: "s", Name: "smart switches", Description: "Home automation", ParentID: "w"},
+			},
+			expectedOutput: `1 Name : electrician Description : Electrical work
+  - Name : lighting Description : Fixtures
+  - Name : wiring Description : Wiring repairs
+    - Name : smart switches Description : Home automation
+2 Name : plumber Description : Pipes
+`,
+		},
+		{
+			name: "Sub-category with a missing parent is shown at the top level",
+			mockData: []model.Category{
+				{ID: "w", Name: "wiring", Description: "Wiring repairs", ParentID: "gone"},
+			},
+			expectedOutput: `1 Name : wiring Description : Wiring repairs
+`,
+		},
 		{
 			name:           "Empty Category List",
 			mockData:       []model.Category{},
-			mockReadFile:   func(filename string) ([]byte, error) { return json.Marshal([]model.Category{}) },
 			expectedOutput: "",
-			expectError:    false,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			// Override the global Print function to capture output
-			var buf bytes.Buffer
-			originalPrint := util.Print
-			util.Print = func(format string, args ...interface{}) (n int, err error) {
-				return fmt.Fprintf(&buf, format, args...)
-			}
-			defer func() { util.Print = originalPrint }() // Restore original Print after test
-
-			// Mock ioutil.ReadFile to return the mock data
-			originalReadFile := util.ReadFile
-			util.ReadFile = tt.mockReadFile
-			defer func() { util.ReadFile = originalReadFile }() // Restore original ReadFile after test
-
-			// Call the function to test
-			util.DisplayCategory()
-
-			// Verify the output
-			if tt.expectError {
-				assert.Contains(t, buf.String(), tt.expectedOutput)
-			} else {
-				assert.Equal(t, tt.expectedOutput, buf.String())
-			}
+			assert.Equal(t, tt.expectedOutput, captureDisplayCategory(tt.mockData))
 		})
 	}
 }
@@ -1,29 +1,42 @@
 package util
 
 import (
-	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"serviceNest/config"
 	"serviceNest/model"
+	"strings"
 )
 
 var Print = fmt.Printf
 var ReadFile = ioutil.ReadFile
 
-func DisplayCategory() {
-	var category []model.Category
-
-	file, err := ReadFile(config.FILENAME)
-	if err != nil {
-		fmt.Println(err)
+// DisplayCategory prints the catalogue as a tree, top-level categories are numbered and their
+// sub-categories are listed below them
+func DisplayCategory(categories []model.Category) {
+	known := make(map[string]bool, len(categories))
+	for _, category := range categories {
+		known[category.ID] = true
+	}
+	children := make(map[string][]model.Category)
+	var roots []model.Category
+	for _, category := range categories {
+		if category.ParentID == "" || !known[category.ParentID] {
+			roots = append(roots, category)
+		} else {
+			children[category.ParentID] = append(children[category.ParentID], category)
+		}
 	}
-	err = json.Unmarshal(file, &category)
-	if err != nil {
-		fmt.Println(err)
+
+	var printChildren func(parentID string, depth int)
+	printChildren = func(parentID string, depth int) {
+		for _, child := range children[parentID] {
+			Print("%s- Name : %s Description : %s\n", strings.Repeat("  ", depth), child.Name, child.Description)
+			printChildren(child.ID, depth+1)
+		}
 	}
-	for i, service := range category {
-		Print("%d Name : %s Description : %s\n", i+1, service.Name, service.Description)
+	for i, category := range roots {
+		Print("%d Name : %s Description : %s\n", i+1, category.Name, category.Description)
+		printChildren(category.ID, 1)
 		fmt.Println()
 	}
 }